@@ -8,6 +8,7 @@ import (
 	"github.com/prebid/prebid-server/v4/analytics/agma"
 	"github.com/prebid/prebid-server/v4/analytics/clients"
 	"github.com/prebid/prebid-server/v4/analytics/filesystem"
+	"github.com/prebid/prebid-server/v4/analytics/kafka"
 	"github.com/prebid/prebid-server/v4/analytics/pubstack"
 	"github.com/prebid/prebid-server/v4/config"
 	"github.com/prebid/prebid-server/v4/logger"
@@ -56,6 +57,15 @@ func New(analytics *config.Analytics) analytics.Runner {
 		}
 	}
 
+	if analytics.Kafka.Enabled {
+		kafkaModule, err := kafka.NewModule(analytics.Kafka, clock.New())
+		if err == nil {
+			modules["kafka"] = kafkaModule
+		} else {
+			logger.Errorf("Could not initialize Kafka Analytics: %v", err)
+		}
+	}
+
 	return modules
 }
 
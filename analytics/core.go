@@ -32,6 +32,7 @@ type AuctionObject struct {
 	HookExecutionOutcome []hookexecution.StageOutcome
 	SeatNonBid           []openrtb_ext.SeatNonBid
 	RequestWrapper       *openrtb_ext.RequestWrapper
+	FloorsOutcome        *FloorsOutcome
 }
 
 // Loggable object of a transaction at /openrtb2/amp endpoint
@@ -45,18 +46,52 @@ type AmpObject struct {
 	HookExecutionOutcome []hookexecution.StageOutcome
 	SeatNonBid           []openrtb_ext.SeatNonBid
 	RequestWrapper       *openrtb_ext.RequestWrapper
+	FloorsOutcome        *FloorsOutcome
 }
 
 // Loggable object of a transaction at /openrtb2/video endpoint
 type VideoObject struct {
-	Status         int
-	Errors         []error
-	Response       *openrtb2.BidResponse
-	VideoRequest   *openrtb_ext.BidRequestVideo
-	VideoResponse  *openrtb_ext.BidResponseVideo
-	StartTime      time.Time
-	SeatNonBid     []openrtb_ext.SeatNonBid
-	RequestWrapper *openrtb_ext.RequestWrapper
+	Status               int
+	Errors               []error
+	Response             *openrtb2.BidResponse
+	VideoRequest         *openrtb_ext.BidRequestVideo
+	VideoResponse        *openrtb_ext.BidResponseVideo
+	StartTime            time.Time
+	HookExecutionOutcome []hookexecution.StageOutcome
+	SeatNonBid           []openrtb_ext.SeatNonBid
+	RequestWrapper       *openrtb_ext.RequestWrapper
+}
+
+// FloorsOutcome describes the price floors applied to an auction, so that the floor model groups can be compared
+type FloorsOutcome struct {
+	// ModelVersion is the version of the model group selected for the auction
+	ModelVersion  string `json:"model_version,omitempty"`
+	FloorProvider string `json:"floor_provider,omitempty"`
+	// Location is where the floors came from: request, fetch or noData
+	Location    string `json:"location,omitempty"`
+	FetchStatus string `json:"fetch_status,omitempty"`
+	// Skipped is true when the floors weren't signaled because of the skip rate
+	Skipped bool `json:"skipped"`
+	// Enforced is true when the floors are to be enforced on the bids. When bids are returned, it's the
+	// decision made by the enforce rate, otherwise it's the enforcement requested.
+	Enforced bool `json:"enforced"`
+	// BidsEvaluated is true when the bids returned were evaluated against the floors
+	BidsEvaluated bool               `json:"bids_evaluated"`
+	Imps          []FloorsImpOutcome `json:"imps,omitempty"`
+}
+
+// FloorsImpOutcome describes the floor signaled for an imp
+type FloorsImpOutcome struct {
+	ImpID          string  `json:"imp_id"`
+	MatchedRule    string  `json:"matched_rule,omitempty"`
+	FloorRuleValue float64 `json:"floor_rule_value,omitempty"`
+	// FloorValue is the floor of the imp before the adjustments for each bidder
+	FloorValue    float64 `json:"floor_value,omitempty"`
+	FloorCurrency string  `json:"floor_currency,omitempty"`
+	// BidderFloorValues are the floors signaled to the bidders whose floor was adjusted
+	BidderFloorValues map[string]float64 `json:"bidder_floor_values,omitempty"`
+	// RejectedBids is the number of bids rejected below the floor
+	RejectedBids int `json:"rejected_bids"`
 }
 
 // Loggable object of a transaction at /setuid
@@ -73,6 +108,9 @@ type CookieSyncObject struct {
 	Status       int
 	Errors       []error
 	BidderStatus []*CookieSyncBidder
+	// BiddersInCooldown were not synced because a sync was offered to the user too recently.
+	BiddersInCooldown    []string
+	HookExecutionOutcome []hookexecution.StageOutcome `json:"-"`
 }
 
 type CookieSyncBidder struct {
@@ -88,6 +126,7 @@ type UsersyncInfo struct {
 
 // NotificationEvent object of a transaction at /event
 type NotificationEvent struct {
-	Request *EventRequest   `json:"request"`
-	Account *config.Account `json:"account"`
+	Request              *EventRequest                `json:"request"`
+	Account              *config.Account              `json:"account"`
+	HookExecutionOutcome []hookexecution.StageOutcome `json:"-"`
 }
@@ -130,6 +130,7 @@ func jsonifyAuctionObject(ao *analytics.AuctionObject) string {
 			Account:              ao.Account,
 			StartTime:            ao.StartTime,
 			HookExecutionOutcome: ao.HookExecutionOutcome,
+			FloorsOutcome:        ao.FloorsOutcome,
 		}
 	}
 
@@ -156,13 +157,14 @@ func jsonifyVideoObject(vo *analytics.VideoObject) string {
 			request = vo.RequestWrapper.BidRequest
 		}
 		logEntry = &logVideo{
-			Status:        vo.Status,
-			Errors:        vo.Errors,
-			Request:       request,
-			Response:      vo.Response,
-			VideoRequest:  vo.VideoRequest,
-			VideoResponse: vo.VideoResponse,
-			StartTime:     vo.StartTime,
+			Status:               vo.Status,
+			Errors:               vo.Errors,
+			Request:              request,
+			Response:             vo.Response,
+			VideoRequest:         vo.VideoRequest,
+			VideoResponse:        vo.VideoResponse,
+			StartTime:            vo.StartTime,
+			HookExecutionOutcome: vo.HookExecutionOutcome,
 		}
 	}
 
@@ -185,9 +187,10 @@ func jsonifyCookieSync(cso *analytics.CookieSyncObject) string {
 	var logEntry *logUserSync
 	if cso != nil {
 		logEntry = &logUserSync{
-			Status:       cso.Status,
-			Errors:       cso.Errors,
-			BidderStatus: cso.BidderStatus,
+			Status:            cso.Status,
+			Errors:            cso.Errors,
+			BidderStatus:      cso.BidderStatus,
+			BiddersInCooldown: cso.BiddersInCooldown,
 		}
 	}
 
@@ -249,6 +252,7 @@ func jsonifyAmpObject(ao *analytics.AmpObject) string {
 			Origin:               ao.Origin,
 			StartTime:            ao.StartTime,
 			HookExecutionOutcome: ao.HookExecutionOutcome,
+			FloorsOutcome:        ao.FloorsOutcome,
 		}
 	}
 
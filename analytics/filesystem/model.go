@@ -18,16 +18,18 @@ type logAuction struct {
 	Account              *config.Account
 	StartTime            time.Time
 	HookExecutionOutcome []hookexecution.StageOutcome
+	FloorsOutcome        *analytics.FloorsOutcome `json:",omitempty"`
 }
 
 type logVideo struct {
-	Status        int
-	Errors        []error
-	Request       *openrtb2.BidRequest
-	Response      *openrtb2.BidResponse
-	VideoRequest  *openrtb_ext.BidRequestVideo
-	VideoResponse *openrtb_ext.BidResponseVideo
-	StartTime     time.Time
+	Status               int
+	Errors               []error
+	Request              *openrtb2.BidRequest
+	Response             *openrtb2.BidResponse
+	VideoRequest         *openrtb_ext.BidRequestVideo
+	VideoResponse        *openrtb_ext.BidResponseVideo
+	StartTime            time.Time
+	HookExecutionOutcome []hookexecution.StageOutcome
 }
 
 type logSetUID struct {
@@ -39,9 +41,10 @@ type logSetUID struct {
 }
 
 type logUserSync struct {
-	Status       int
-	Errors       []error
-	BidderStatus []*analytics.CookieSyncBidder
+	Status            int
+	Errors            []error
+	BidderStatus      []*analytics.CookieSyncBidder
+	BiddersInCooldown []string `json:",omitempty"`
 }
 
 type logAMP struct {
@@ -53,6 +56,7 @@ type logAMP struct {
 	Origin               string
 	StartTime            time.Time
 	HookExecutionOutcome []hookexecution.StageOutcome
+	FloorsOutcome        *analytics.FloorsOutcome `json:",omitempty"`
 }
 
 type logNotificationEvent struct {
@@ -0,0 +1,203 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/prebid/prebid-server/v4/analytics"
+	"github.com/prebid/prebid-server/v4/config"
+	"github.com/prebid/prebid-server/v4/logger"
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+const (
+	headerSchemaVersion = "schema_version"
+	headerEventType     = "event_type"
+
+	// dropLogInterval limits the logging of dropped records to one in every interval, which keeps an
+	// unavailable Kafka cluster from flooding the logs.
+	dropLogInterval = 1000
+
+	defaultShutdownTimeout = 30 * time.Second
+)
+
+// KafkaLogger publishes analytics events to Kafka. Records are batched and retried in the
+// background by the producer. When the producer buffer is full new records are dropped rather
+// than blocking the request.
+type KafkaLogger struct {
+	producer        producer
+	clock           clock.Clock
+	topics          config.KafkaAnalyticsTopics
+	accountTopics   map[string]config.KafkaAnalyticsTopics
+	shutdownTimeout time.Duration
+	dropped         atomic.Int64
+}
+
+func NewModule(cfg config.KafkaAnalytics, clock clock.Clock) (analytics.Module, error) {
+	if len(cfg.Brokers) == 0 {
+		return nil, errors.New("Please configure at least one broker for Kafka Analytics")
+	}
+
+	producer, err := newProducer(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return newKafkaLogger(cfg, producer, clock), nil
+}
+
+func newKafkaLogger(cfg config.KafkaAnalytics, producer producer, clock clock.Clock) *KafkaLogger {
+	accountTopics := make(map[string]config.KafkaAnalyticsTopics, len(cfg.Accounts))
+	for _, account := range cfg.Accounts {
+		accountTopics[account.AccountID] = account.Topics
+	}
+
+	shutdownTimeout := defaultShutdownTimeout
+	if cfg.Producer.DeliveryTimeoutMS > 0 {
+		shutdownTimeout = time.Duration(cfg.Producer.DeliveryTimeoutMS) * time.Millisecond
+	}
+
+	return &KafkaLogger{
+		producer:        producer,
+		clock:           clock,
+		topics:          cfg.Topics,
+		accountTopics:   accountTopics,
+		shutdownTimeout: shutdownTimeout,
+	}
+}
+
+func (l *KafkaLogger) LogAuctionObject(ao *analytics.AuctionObject) {
+	if ao == nil {
+		return
+	}
+	accountID := requestAccountID(ao.RequestWrapper)
+	if ao.Account != nil && ao.Account.ID != "" {
+		accountID = ao.Account.ID
+	}
+	l.publish(eventTypeAuction, accountID, newAuctionEvent(ao))
+}
+
+func (l *KafkaLogger) LogAmpObject(ao *analytics.AmpObject) {
+	if ao == nil {
+		return
+	}
+	l.publish(eventTypeAmp, requestAccountID(ao.RequestWrapper), newAmpEvent(ao))
+}
+
+func (l *KafkaLogger) LogVideoObject(vo *analytics.VideoObject) {
+	if vo == nil {
+		return
+	}
+	l.publish(eventTypeVideo, requestAccountID(vo.RequestWrapper), newVideoEvent(vo))
+}
+
+func (l *KafkaLogger) LogCookieSyncObject(cso *analytics.CookieSyncObject) {
+	if cso == nil {
+		return
+	}
+	l.publish(eventTypeCookieSync, "", newCookieSyncEvent(cso))
+}
+
+func (l *KafkaLogger) LogSetUIDObject(so *analytics.SetUIDObject) {
+	if so == nil {
+		return
+	}
+	l.publish(eventTypeSetUID, "", newSetUIDEvent(so))
+}
+
+func (l *KafkaLogger) LogNotificationEventObject(ne *analytics.NotificationEvent) {
+	if ne == nil {
+		return
+	}
+	var accountID string
+	if ne.Account != nil {
+		accountID = ne.Account.ID
+	} else if ne.Request != nil {
+		accountID = ne.Request.AccountID
+	}
+	l.publish(eventTypeNotificationEvent, accountID, newNotificationEvent(ne))
+}
+
+// Shutdown waits for the buffered records to be delivered and closes the producer.
+func (l *KafkaLogger) Shutdown() {
+	logger.Infof("[KafkaAnalytics] Shutdown, trying to flush buffered records")
+
+	ctx, cancel := context.WithTimeout(context.Background(), l.shutdownTimeout)
+	defer cancel()
+	if err := l.producer.Flush(ctx); err != nil {
+		logger.Errorf("[KafkaAnalytics] Failed to flush buffered records: %v", err)
+	}
+	l.producer.Close()
+}
+
+func (l *KafkaLogger) publish(eventType eventType, accountID string, event any) {
+	topic := l.topic(eventType, accountID)
+	if topic == "" {
+		return
+	}
+
+	value, err := serialize(eventType, accountID, l.clock.Now(), event)
+	if err != nil {
+		logger.Errorf("[KafkaAnalytics] Failed to serialize %s event: %v", eventType, err)
+		return
+	}
+
+	record := &kgo.Record{
+		Topic: topic,
+		Value: value,
+		Headers: []kgo.RecordHeader{
+			{Key: headerSchemaVersion, Value: []byte(strconv.Itoa(schemaVersion))},
+			{Key: headerEventType, Value: []byte(eventType)},
+		},
+	}
+	// records of the same account are kept in order by publishing them to the same partition
+	if accountID != "" {
+		record.Key = []byte(accountID)
+	}
+
+	l.producer.TryProduce(context.Background(), record, l.onDelivery)
+}
+
+// onDelivery is called by the producer once a record is delivered, or failed to be delivered
+// within the configured retries or the delivery timeout.
+func (l *KafkaLogger) onDelivery(record *kgo.Record, err error) {
+	if err == nil {
+		return
+	}
+	dropped := l.dropped.Add(1)
+	if dropped%dropLogInterval == 1 {
+		logger.Warnf("[KafkaAnalytics] Dropped record for topic %s, %d records dropped in total: %v", record.Topic, dropped, err)
+	}
+}
+
+// topic returns the account topic of the event type, falling back to the default topic.
+func (l *KafkaLogger) topic(eventType eventType, accountID string) string {
+	if accountTopics, ok := l.accountTopics[accountID]; ok && accountID != "" {
+		if topic := topicOf(accountTopics, eventType); topic != "" {
+			return topic
+		}
+	}
+	return topicOf(l.topics, eventType)
+}
+
+func topicOf(topics config.KafkaAnalyticsTopics, eventType eventType) string {
+	switch eventType {
+	case eventTypeAuction:
+		return topics.Auction
+	case eventTypeAmp:
+		return topics.Amp
+	case eventTypeVideo:
+		return topics.Video
+	case eventTypeCookieSync:
+		return topics.CookieSync
+	case eventTypeSetUID:
+		return topics.SetUID
+	case eventTypeNotificationEvent:
+		return topics.NotificationEvent
+	}
+	return ""
+}
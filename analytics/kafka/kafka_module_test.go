@@ -0,0 +1,345 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v4/analytics"
+	"github.com/prebid/prebid-server/v4/config"
+	"github.com/prebid/prebid-server/v4/openrtb_ext"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+// fakeProducer is an in-process stand-in for a Kafka cluster. It keeps the produced records per
+// topic and rejects new records once maxBuffered records are waiting for a flush.
+type fakeProducer struct {
+	mutex       sync.Mutex
+	maxBuffered int
+	buffered    []*kgo.Record
+	topics      map[string][]*kgo.Record
+	flushErr    error
+	closed      bool
+}
+
+func newFakeProducer(maxBuffered int) *fakeProducer {
+	return &fakeProducer{maxBuffered: maxBuffered, topics: make(map[string][]*kgo.Record)}
+}
+
+func (p *fakeProducer) TryProduce(_ context.Context, record *kgo.Record, promise func(*kgo.Record, error)) {
+	p.mutex.Lock()
+	if len(p.buffered) >= p.maxBuffered {
+		p.mutex.Unlock()
+		promise(record, kgo.ErrMaxBuffered)
+		return
+	}
+	p.buffered = append(p.buffered, record)
+	p.mutex.Unlock()
+}
+
+func (p *fakeProducer) Flush(_ context.Context) error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if p.flushErr != nil {
+		return p.flushErr
+	}
+	for _, record := range p.buffered {
+		p.topics[record.Topic] = append(p.topics[record.Topic], record)
+	}
+	p.buffered = nil
+	return nil
+}
+
+func (p *fakeProducer) Close() {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.closed = true
+}
+
+func (p *fakeProducer) records(topic string) []*kgo.Record {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return p.topics[topic]
+}
+
+var testTopics = config.KafkaAnalyticsTopics{
+	Auction:           "auction",
+	Amp:               "amp",
+	Video:             "video",
+	CookieSync:        "cookie-sync",
+	SetUID:            "setuid",
+	NotificationEvent: "event",
+}
+
+func newTestLogger(cfg config.KafkaAnalytics, producer producer) *KafkaLogger {
+	clk := clock.NewMock()
+	clk.Set(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC))
+	return newKafkaLogger(cfg, producer, clk)
+}
+
+func siteRequest(publisherID string) *openrtb_ext.RequestWrapper {
+	return &openrtb_ext.RequestWrapper{
+		BidRequest: &openrtb2.BidRequest{
+			ID:   "request-id",
+			Site: &openrtb2.Site{Publisher: &openrtb2.Publisher{ID: publisherID}},
+		},
+	}
+}
+
+func TestNewModule(t *testing.T) {
+	testCases := []struct {
+		description   string
+		cfg           config.KafkaAnalytics
+		expectedError string
+	}{
+		{
+			description:   "no-brokers",
+			cfg:           config.KafkaAnalytics{Enabled: true},
+			expectedError: "Please configure at least one broker for Kafka Analytics",
+		},
+		{
+			description: "invalid-acks",
+			cfg: config.KafkaAnalytics{
+				Enabled:  true,
+				Brokers:  []string{"localhost:9092"},
+				Producer: config.KafkaAnalyticsProducer{Acks: "some"},
+			},
+			expectedError: `invalid producer acks "some", must be one of all, leader or none`,
+		},
+		{
+			description: "valid",
+			cfg: config.KafkaAnalytics{
+				Enabled:  true,
+				Brokers:  []string{"localhost:9092"},
+				Producer: config.KafkaAnalyticsProducer{Acks: "leader", LingerMS: 5, MaxBufferedRecords: 10, Retries: 1, DeliveryTimeoutMS: 1000},
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			module, err := NewModule(test.cfg, clock.New())
+			if test.expectedError != "" {
+				assert.EqualError(t, err, test.expectedError)
+				assert.Nil(t, module)
+				return
+			}
+			require.NoError(t, err)
+			require.NotNil(t, module)
+			// the client connects lazily so nothing needs to be delivered here
+			module.(*KafkaLogger).producer.Close()
+		})
+	}
+}
+
+func TestLogEvents(t *testing.T) {
+	testCases := []struct {
+		description     string
+		log             func(l *KafkaLogger)
+		expectedTopic   string
+		expectedKey     []byte
+		expectedType    string
+		expectedPayload string
+	}{
+		{
+			description: "auction",
+			log: func(l *KafkaLogger) {
+				l.LogAuctionObject(&analytics.AuctionObject{
+					Status:         200,
+					Errors:         []error{errors.New("some error")},
+					Account:        &config.Account{ID: "acct"},
+					RequestWrapper: siteRequest("pub"),
+					Response:       &openrtb2.BidResponse{ID: "response-id"},
+				})
+			},
+			expectedTopic:   "auction",
+			expectedKey:     []byte("acct"),
+			expectedType:    "auction",
+			expectedPayload: `{"schema_version":1,"type":"auction","timestamp":"2024-01-02T03:04:05Z","account":"acct","event":{"status":200,"errors":["some error"],"start_time":"0001-01-01T00:00:00Z","request":{"id":"request-id","imp":null,"site":{"publisher":{"id":"pub"}}},"response":{"id":"response-id"}}}`,
+		},
+		{
+			description: "auction-without-account-uses-publisher",
+			log: func(l *KafkaLogger) {
+				l.LogAuctionObject(&analytics.AuctionObject{Status: 400, RequestWrapper: siteRequest("pub")})
+			},
+			expectedTopic:   "auction",
+			expectedKey:     []byte("pub"),
+			expectedType:    "auction",
+			expectedPayload: `{"schema_version":1,"type":"auction","timestamp":"2024-01-02T03:04:05Z","account":"pub","event":{"status":400,"start_time":"0001-01-01T00:00:00Z","request":{"id":"request-id","imp":null,"site":{"publisher":{"id":"pub"}}}}}`,
+		},
+		{
+			description: "amp",
+			log: func(l *KafkaLogger) {
+				l.LogAmpObject(&analytics.AmpObject{Status: 200, Origin: "origin", RequestWrapper: siteRequest("pub")})
+			},
+			expectedTopic:   "amp",
+			expectedKey:     []byte("pub"),
+			expectedType:    "amp",
+			expectedPayload: `{"schema_version":1,"type":"amp","timestamp":"2024-01-02T03:04:05Z","account":"pub","event":{"status":200,"start_time":"0001-01-01T00:00:00Z","origin":"origin","request":{"id":"request-id","imp":null,"site":{"publisher":{"id":"pub"}}}}}`,
+		},
+		{
+			description: "video",
+			log: func(l *KafkaLogger) {
+				l.LogVideoObject(&analytics.VideoObject{Status: 200})
+			},
+			expectedTopic:   "video",
+			expectedType:    "video",
+			expectedPayload: `{"schema_version":1,"type":"video","timestamp":"2024-01-02T03:04:05Z","event":{"status":200,"start_time":"0001-01-01T00:00:00Z"}}`,
+		},
+		{
+			description: "cookie-sync",
+			log: func(l *KafkaLogger) {
+				l.LogCookieSyncObject(&analytics.CookieSyncObject{Status: 200, BidderStatus: []*analytics.CookieSyncBidder{{BidderCode: "appnexus", NoCookie: true}}})
+			},
+			expectedTopic:   "cookie-sync",
+			expectedType:    "cookie_sync",
+			expectedPayload: `{"schema_version":1,"type":"cookie_sync","timestamp":"2024-01-02T03:04:05Z","event":{"status":200,"bidders":[{"bidder":"appnexus","no_cookie":true}]}}`,
+		},
+		{
+			description: "setuid-without-uid",
+			log: func(l *KafkaLogger) {
+				l.LogSetUIDObject(&analytics.SetUIDObject{Status: 200, Bidder: "appnexus", UID: "secret-uid", Success: true})
+			},
+			expectedTopic:   "setuid",
+			expectedType:    "setuid",
+			expectedPayload: `{"schema_version":1,"type":"setuid","timestamp":"2024-01-02T03:04:05Z","event":{"status":200,"bidder":"appnexus","success":true}}`,
+		},
+		{
+			description: "notification-event",
+			log: func(l *KafkaLogger) {
+				l.LogNotificationEventObject(&analytics.NotificationEvent{
+					Request: &analytics.EventRequest{Type: analytics.Win, BidID: "bid", AccountID: "acct"},
+				})
+			},
+			expectedTopic:   "event",
+			expectedKey:     []byte("acct"),
+			expectedType:    "notification_event",
+			expectedPayload: `{"schema_version":1,"type":"notification_event","timestamp":"2024-01-02T03:04:05Z","account":"acct","event":{"request":{"type":"win","bidid":"bid","account_id":"acct"}}}`,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			producer := newFakeProducer(10)
+			l := newTestLogger(config.KafkaAnalytics{Topics: testTopics}, producer)
+
+			test.log(l)
+			l.Shutdown()
+
+			records := producer.records(test.expectedTopic)
+			require.Len(t, records, 1)
+			assert.Equal(t, test.expectedKey, records[0].Key)
+			assert.JSONEq(t, test.expectedPayload, string(records[0].Value))
+			assert.Equal(t, []kgo.RecordHeader{
+				{Key: "schema_version", Value: []byte("1")},
+				{Key: "event_type", Value: []byte(test.expectedType)},
+			}, records[0].Headers)
+			assert.True(t, producer.closed)
+		})
+	}
+}
+
+func TestLogNilEvents(t *testing.T) {
+	producer := newFakeProducer(10)
+	l := newTestLogger(config.KafkaAnalytics{Topics: testTopics}, producer)
+
+	l.LogAuctionObject(nil)
+	l.LogAmpObject(nil)
+	l.LogVideoObject(nil)
+	l.LogCookieSyncObject(nil)
+	l.LogSetUIDObject(nil)
+	l.LogNotificationEventObject(nil)
+
+	assert.Empty(t, producer.buffered)
+}
+
+func TestAccountTopicRouting(t *testing.T) {
+	cfg := config.KafkaAnalytics{
+		Topics: config.KafkaAnalyticsTopics{Auction: "auction", Amp: "amp"},
+		Accounts: []config.KafkaAnalyticsAccount{
+			{AccountID: "routed", Topics: config.KafkaAnalyticsTopics{Auction: "routed-auction"}},
+		},
+	}
+
+	testCases := []struct {
+		description   string
+		log           func(l *KafkaLogger)
+		expectedTopic string
+	}{
+		{
+			description: "account-topic",
+			log: func(l *KafkaLogger) {
+				l.LogAuctionObject(&analytics.AuctionObject{Account: &config.Account{ID: "routed"}})
+			},
+			expectedTopic: "routed-auction",
+		},
+		{
+			description: "account-falls-back-to-default-topic",
+			log: func(l *KafkaLogger) {
+				l.LogAmpObject(&analytics.AmpObject{RequestWrapper: siteRequest("routed")})
+			},
+			expectedTopic: "amp",
+		},
+		{
+			description: "other-account-default-topic",
+			log: func(l *KafkaLogger) {
+				l.LogAuctionObject(&analytics.AuctionObject{Account: &config.Account{ID: "other"}})
+			},
+			expectedTopic: "auction",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			producer := newFakeProducer(10)
+			l := newTestLogger(cfg, producer)
+
+			test.log(l)
+			l.Shutdown()
+
+			assert.Len(t, producer.records(test.expectedTopic), 1)
+		})
+	}
+}
+
+func TestEventTypeWithoutTopicIsSkipped(t *testing.T) {
+	producer := newFakeProducer(10)
+	l := newTestLogger(config.KafkaAnalytics{Topics: config.KafkaAnalyticsTopics{Auction: "auction"}}, producer)
+
+	l.LogVideoObject(&analytics.VideoObject{Status: 200})
+
+	assert.Empty(t, producer.buffered)
+}
+
+func TestBackPressureDropsRecords(t *testing.T) {
+	producer := newFakeProducer(2)
+	l := newTestLogger(config.KafkaAnalytics{Topics: testTopics}, producer)
+
+	for i := 0; i < 5; i++ {
+		l.LogSetUIDObject(&analytics.SetUIDObject{Status: 200})
+	}
+
+	assert.Len(t, producer.buffered, 2)
+	assert.Equal(t, int64(3), l.dropped.Load())
+
+	// records are accepted again once the buffer is flushed
+	require.NoError(t, producer.Flush(context.Background()))
+	l.LogSetUIDObject(&analytics.SetUIDObject{Status: 200})
+	assert.Len(t, producer.buffered, 1)
+}
+
+func TestShutdownClosesProducerWhenFlushFails(t *testing.T) {
+	producer := newFakeProducer(10)
+	producer.flushErr = context.DeadlineExceeded
+	l := newTestLogger(config.KafkaAnalytics{Topics: testTopics}, producer)
+
+	l.Shutdown()
+
+	assert.True(t, producer.closed)
+}
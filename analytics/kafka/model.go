@@ -0,0 +1,194 @@
+package kafka
+
+import (
+	"time"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v4/analytics"
+	"github.com/prebid/prebid-server/v4/openrtb_ext"
+	"github.com/prebid/prebid-server/v4/util/jsonutil"
+)
+
+// schemaVersion is incremented on breaking changes to the record format so consumers can handle
+// records written by older and newer hosts during a rollout.
+const schemaVersion = 1
+
+type eventType string
+
+const (
+	eventTypeAuction           eventType = "auction"
+	eventTypeAmp               eventType = "amp"
+	eventTypeVideo             eventType = "video"
+	eventTypeCookieSync        eventType = "cookie_sync"
+	eventTypeSetUID            eventType = "setuid"
+	eventTypeNotificationEvent eventType = "notification_event"
+)
+
+// envelope is the versioned record published to Kafka for every event.
+type envelope struct {
+	SchemaVersion int       `json:"schema_version"`
+	Type          eventType `json:"type"`
+	Timestamp     time.Time `json:"timestamp"`
+	Account       string    `json:"account,omitempty"`
+	Event         any       `json:"event"`
+}
+
+type auctionEvent struct {
+	Status        int                      `json:"status"`
+	Errors        []string                 `json:"errors,omitempty"`
+	StartTime     time.Time                `json:"start_time"`
+	Request       *openrtb2.BidRequest     `json:"request,omitempty"`
+	Response      *openrtb2.BidResponse    `json:"response,omitempty"`
+	SeatNonBid    []openrtb_ext.SeatNonBid `json:"seat_non_bid,omitempty"`
+	FloorsOutcome *analytics.FloorsOutcome `json:"floors,omitempty"`
+}
+
+type ampEvent struct {
+	Status             int                      `json:"status"`
+	Errors             []string                 `json:"errors,omitempty"`
+	StartTime          time.Time                `json:"start_time"`
+	Origin             string                   `json:"origin,omitempty"`
+	Request            *openrtb2.BidRequest     `json:"request,omitempty"`
+	Response           *openrtb2.BidResponse    `json:"response,omitempty"`
+	AmpTargetingValues map[string]string        `json:"amp_targeting_values,omitempty"`
+	SeatNonBid         []openrtb_ext.SeatNonBid `json:"seat_non_bid,omitempty"`
+	FloorsOutcome      *analytics.FloorsOutcome `json:"floors,omitempty"`
+}
+
+type videoEvent struct {
+	Status        int                           `json:"status"`
+	Errors        []string                      `json:"errors,omitempty"`
+	StartTime     time.Time                     `json:"start_time"`
+	Request       *openrtb2.BidRequest          `json:"request,omitempty"`
+	Response      *openrtb2.BidResponse         `json:"response,omitempty"`
+	VideoRequest  *openrtb_ext.BidRequestVideo  `json:"video_request,omitempty"`
+	VideoResponse *openrtb_ext.BidResponseVideo `json:"video_response,omitempty"`
+	SeatNonBid    []openrtb_ext.SeatNonBid      `json:"seat_non_bid,omitempty"`
+}
+
+type cookieSyncEvent struct {
+	Status            int                           `json:"status"`
+	Errors            []string                      `json:"errors,omitempty"`
+	Bidders           []*analytics.CookieSyncBidder `json:"bidders,omitempty"`
+	BiddersInCooldown []string                      `json:"bidders_in_cooldown,omitempty"`
+}
+
+// setUIDEvent intentionally leaves out the synced user id.
+type setUIDEvent struct {
+	Status  int      `json:"status"`
+	Errors  []string `json:"errors,omitempty"`
+	Bidder  string   `json:"bidder,omitempty"`
+	Success bool     `json:"success"`
+}
+
+type notificationEvent struct {
+	Request *analytics.EventRequest `json:"request,omitempty"`
+}
+
+func newAuctionEvent(ao *analytics.AuctionObject) *auctionEvent {
+	return &auctionEvent{
+		Status:        ao.Status,
+		Errors:        errorsToStrings(ao.Errors),
+		StartTime:     ao.StartTime,
+		Request:       bidRequest(ao.RequestWrapper),
+		Response:      ao.Response,
+		SeatNonBid:    ao.SeatNonBid,
+		FloorsOutcome: ao.FloorsOutcome,
+	}
+}
+
+func newAmpEvent(ao *analytics.AmpObject) *ampEvent {
+	return &ampEvent{
+		Status:             ao.Status,
+		Errors:             errorsToStrings(ao.Errors),
+		StartTime:          ao.StartTime,
+		Origin:             ao.Origin,
+		Request:            bidRequest(ao.RequestWrapper),
+		Response:           ao.AuctionResponse,
+		AmpTargetingValues: ao.AmpTargetingValues,
+		SeatNonBid:         ao.SeatNonBid,
+		FloorsOutcome:      ao.FloorsOutcome,
+	}
+}
+
+func newVideoEvent(vo *analytics.VideoObject) *videoEvent {
+	return &videoEvent{
+		Status:        vo.Status,
+		Errors:        errorsToStrings(vo.Errors),
+		StartTime:     vo.StartTime,
+		Request:       bidRequest(vo.RequestWrapper),
+		Response:      vo.Response,
+		VideoRequest:  vo.VideoRequest,
+		VideoResponse: vo.VideoResponse,
+		SeatNonBid:    vo.SeatNonBid,
+	}
+}
+
+func newCookieSyncEvent(cso *analytics.CookieSyncObject) *cookieSyncEvent {
+	return &cookieSyncEvent{
+		Status:            cso.Status,
+		Errors:            errorsToStrings(cso.Errors),
+		Bidders:           cso.BidderStatus,
+		BiddersInCooldown: cso.BiddersInCooldown,
+	}
+}
+
+func newSetUIDEvent(so *analytics.SetUIDObject) *setUIDEvent {
+	return &setUIDEvent{
+		Status:  so.Status,
+		Errors:  errorsToStrings(so.Errors),
+		Bidder:  so.Bidder,
+		Success: so.Success,
+	}
+}
+
+func newNotificationEvent(ne *analytics.NotificationEvent) *notificationEvent {
+	return &notificationEvent{
+		Request: ne.Request,
+	}
+}
+
+func serialize(eventType eventType, account string, timestamp time.Time, event any) ([]byte, error) {
+	return jsonutil.Marshal(&envelope{
+		SchemaVersion: schemaVersion,
+		Type:          eventType,
+		Timestamp:     timestamp,
+		Account:       account,
+		Event:         event,
+	})
+}
+
+func bidRequest(rw *openrtb_ext.RequestWrapper) *openrtb2.BidRequest {
+	if rw == nil {
+		return nil
+	}
+	return rw.BidRequest
+}
+
+func errorsToStrings(errs []error) []string {
+	if len(errs) == 0 {
+		return nil
+	}
+	result := make([]string, len(errs))
+	for i, err := range errs {
+		result[i] = err.Error()
+	}
+	return result
+}
+
+// requestAccountID returns the publisher id of the request, which is the account id unless the
+// request was rejected before the account was resolved.
+func requestAccountID(rw *openrtb_ext.RequestWrapper) string {
+	if rw == nil || rw.BidRequest == nil {
+		return ""
+	}
+	switch {
+	case rw.Site != nil && rw.Site.Publisher != nil:
+		return rw.Site.Publisher.ID
+	case rw.App != nil && rw.App.Publisher != nil:
+		return rw.App.Publisher.ID
+	case rw.DOOH != nil && rw.DOOH.Publisher != nil:
+		return rw.DOOH.Publisher.ID
+	}
+	return ""
+}
@@ -0,0 +1,63 @@
+package kafka
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/prebid/prebid-server/v4/config"
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+// producer publishes records to Kafka. It is implemented by *kgo.Client and replaced by an
+// in-process stand-in in tests.
+type producer interface {
+	TryProduce(ctx context.Context, record *kgo.Record, promise func(*kgo.Record, error))
+	Flush(ctx context.Context) error
+	Close()
+}
+
+const (
+	acksAll    = "all"
+	acksLeader = "leader"
+	acksNone   = "none"
+)
+
+// newProducer creates a Kafka client batching records in the background. Records are retried with a
+// fixed backoff until they are delivered or the delivery timeout expires.
+func newProducer(cfg config.KafkaAnalytics) (*kgo.Client, error) {
+	opts := []kgo.Opt{
+		kgo.SeedBrokers(cfg.Brokers...),
+		kgo.ClientID(cfg.ClientID),
+		kgo.ProducerLinger(time.Duration(cfg.Producer.LingerMS) * time.Millisecond),
+		kgo.RetryBackoffFn(func(int) time.Duration {
+			return time.Duration(cfg.Producer.RetryBackoffMS) * time.Millisecond
+		}),
+	}
+	if cfg.Producer.BatchMaxBytes > 0 {
+		opts = append(opts, kgo.ProducerBatchMaxBytes(int32(cfg.Producer.BatchMaxBytes)))
+	}
+	if cfg.Producer.MaxBufferedRecords > 0 {
+		opts = append(opts, kgo.MaxBufferedRecords(cfg.Producer.MaxBufferedRecords))
+	}
+	if cfg.Producer.Retries > 0 {
+		opts = append(opts, kgo.RecordRetries(cfg.Producer.Retries))
+	}
+	if cfg.Producer.DeliveryTimeoutMS > 0 {
+		opts = append(opts, kgo.RecordDeliveryTimeout(time.Duration(cfg.Producer.DeliveryTimeoutMS)*time.Millisecond))
+	}
+
+	switch cfg.Producer.Acks {
+	case acksAll, "":
+		opts = append(opts, kgo.RequiredAcks(kgo.AllISRAcks()))
+	case acksLeader:
+		// idempotent writes require acknowledgements from all in-sync replicas
+		opts = append(opts, kgo.RequiredAcks(kgo.LeaderAck()), kgo.DisableIdempotentWrite())
+	case acksNone:
+		opts = append(opts, kgo.RequiredAcks(kgo.NoAck()), kgo.DisableIdempotentWrite())
+	default:
+		return nil, fmt.Errorf("invalid producer acks %q, must be one of %s, %s or %s", cfg.Producer.Acks, acksAll, acksLeader, acksNone)
+	}
+
+	return kgo.NewClient(opts...)
+}
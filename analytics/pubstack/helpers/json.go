@@ -49,13 +49,14 @@ func JsonifyVideoObject(vo *analytics.VideoObject, scope string) ([]byte, error)
 			request = vo.RequestWrapper.BidRequest
 		}
 		logEntry = &logVideo{
-			Status:        vo.Status,
-			Errors:        vo.Errors,
-			Request:       request,
-			Response:      vo.Response,
-			VideoRequest:  vo.VideoRequest,
-			VideoResponse: vo.VideoResponse,
-			StartTime:     vo.StartTime,
+			Status:               vo.Status,
+			Errors:               vo.Errors,
+			Request:              request,
+			Response:             vo.Response,
+			VideoRequest:         vo.VideoRequest,
+			VideoResponse:        vo.VideoResponse,
+			StartTime:            vo.StartTime,
+			HookExecutionOutcome: vo.HookExecutionOutcome,
 		}
 	}
 
@@ -78,9 +79,10 @@ func JsonifyCookieSync(cso *analytics.CookieSyncObject, scope string) ([]byte, e
 	var logEntry *logUserSync
 	if cso != nil {
 		logEntry = &logUserSync{
-			Status:       cso.Status,
-			Errors:       cso.Errors,
-			BidderStatus: cso.BidderStatus,
+			Status:            cso.Status,
+			Errors:            cso.Errors,
+			BidderStatus:      cso.BidderStatus,
+			BiddersInCooldown: cso.BiddersInCooldown,
 		}
 	}
 
@@ -21,13 +21,14 @@ type logAuction struct {
 }
 
 type logVideo struct {
-	Status        int
-	Errors        []error
-	Request       *openrtb2.BidRequest
-	Response      *openrtb2.BidResponse
-	VideoRequest  *openrtb_ext.BidRequestVideo
-	VideoResponse *openrtb_ext.BidResponseVideo
-	StartTime     time.Time
+	Status               int
+	Errors               []error
+	Request              *openrtb2.BidRequest
+	Response             *openrtb2.BidResponse
+	VideoRequest         *openrtb_ext.BidRequestVideo
+	VideoResponse        *openrtb_ext.BidResponseVideo
+	StartTime            time.Time
+	HookExecutionOutcome []hookexecution.StageOutcome
 }
 
 type logSetUID struct {
@@ -39,9 +40,10 @@ type logSetUID struct {
 }
 
 type logUserSync struct {
-	Status       int
-	Errors       []error
-	BidderStatus []*analytics.CookieSyncBidder
+	Status            int
+	Errors            []error
+	BidderStatus      []*analytics.CookieSyncBidder
+	BiddersInCooldown []string `json:",omitempty"`
 }
 
 type logAMP struct {
@@ -0,0 +1,347 @@
+// Package capture records sampled auctions to NDJSON files so they can be replayed offline.
+//
+// A capture holds everything needed to run the auction again without reaching the bidders: the incoming
+// request, the resolved bid request and account, the HTTP calls made to the bidders and the final response.
+// Capturing is disabled unless enabled in the host configuration, in which case each auction is sampled
+// according to the capture sampling rate of its account.
+package capture
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"math/rand"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/prebid/prebid-server/v4/adapters"
+	"github.com/prebid/prebid-server/v4/config"
+	"github.com/prebid/prebid-server/v4/currency"
+	"github.com/prebid/prebid-server/v4/errortypes"
+	"github.com/prebid/prebid-server/v4/hooks/hookexecution"
+	"github.com/prebid/prebid-server/v4/logger"
+	"github.com/prebid/prebid-server/v4/openrtb_ext"
+	"github.com/prebid/prebid-server/v4/privacy"
+	"github.com/prebid/prebid-server/v4/util/jsonutil"
+)
+
+// Endpoints which support capturing.
+const (
+	EndpointAuction = "/openrtb2/auction"
+)
+
+// capturedHeaders are the only HTTP headers kept in the captures. The others, such as cookies, authorization
+// and client IP or user agent headers, may hold credentials or personal data.
+var capturedHeaders = []string{
+	"Accept",
+	"Accept-Encoding",
+	"Content-Encoding",
+	"Content-Type",
+	"Sec-Gpc",
+	"X-Openrtb-Version",
+	"X-Prebid",
+}
+
+// privacyComponent is the component the privacy activities are evaluated for before capturing an auction.
+var privacyComponent = privacy.Component{Type: privacy.ComponentTypeGeneral, Name: "capture"}
+
+// activeWriter is the writer of the captured auctions, or nil if capturing is disabled.
+var activeWriter atomic.Pointer[writer]
+
+type contextKey struct{}
+
+type recorderState int
+
+const (
+	statePending recorderState = iota
+	stateSampled
+	stateDone
+)
+
+// recorder collects the capture of a single auction. Its sampling decision is pending until the account of
+// the auction is known, after which it either records the auction or ignores any further data.
+type recorder struct {
+	writer       *writer
+	samplingRate float64
+
+	mutex    sync.Mutex
+	state    recorderState
+	record   Record
+	response *responseRecorder
+	// privacyChecked is true once the privacy signals of the auction allowed it to be captured
+	privacyChecked bool
+}
+
+// Start returns a copy of ctx carrying a recorder for a new auction of the endpoint, along with a response
+// writer recording the response of the auction. The context and the writer are returned unchanged if
+// capturing is disabled.
+func Start(ctx context.Context, endpoint string, w http.ResponseWriter) (context.Context, http.ResponseWriter) {
+	aw := activeWriter.Load()
+	if aw == nil {
+		return ctx, w
+	}
+	r := &recorder{
+		writer:       aw,
+		samplingRate: aw.samplingRate,
+		record: Record{
+			Version:   RecordVersion,
+			Timestamp: time.Now().UTC(),
+			Endpoint:  endpoint,
+		},
+	}
+	r.response = &responseRecorder{ResponseWriter: w, recorder: r}
+	return context.WithValue(ctx, contextKey{}, r), r.response
+}
+
+func fromContext(ctx context.Context) *recorder {
+	r, _ := ctx.Value(contextKey{}).(*recorder)
+	return r
+}
+
+// SetIncomingRequest records the HTTP request received for the auction. The body must be decompressed.
+func SetIncomingRequest(ctx context.Context, httpRequest *http.Request, body []byte) {
+	r := fromContext(ctx)
+	if r == nil {
+		return
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.state == stateDone {
+		return
+	}
+	r.record.IncomingRequest = HTTPRequest{
+		Method:  httpRequest.Method,
+		URI:     httpRequest.URL.RequestURI(),
+		Headers: filterHeaders(httpRequest.Header),
+	}
+	r.record.IncomingRequest.Body, r.record.IncomingRequest.RawBody = splitBody(body)
+}
+
+// SetAccount decides whether the auction is captured according to the sampling rate of the account, and
+// records the account if so. It should be called as soon as the account of the auction is known. Auctions
+// whose account is never resolved aren't captured.
+func SetAccount(ctx context.Context, account *config.Account) {
+	r := fromContext(ctx)
+	if r == nil || account == nil {
+		return
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.state != statePending {
+		return
+	}
+
+	samplingRate := r.samplingRate
+	if account.Capture.SamplingRate != nil {
+		samplingRate = *account.Capture.SamplingRate
+	}
+	if samplingRate <= 0 || rand.Float64() >= samplingRate {
+		r.discard()
+		return
+	}
+
+	accountJSON, err := jsonutil.Marshal(account)
+	if err != nil {
+		logger.Errorf("Failed to capture the account %s: %v", account.ID, err)
+		r.discard()
+		return
+	}
+	r.state = stateSampled
+	r.record.Account = accountJSON
+}
+
+// SetPrivacy discards the capture of the auction if GDPR applies to it, or if its user first party data or
+// precise geolocation may not be transmitted. Auctions are only captured once their privacy signals were
+// checked, so this must be called before the auction is finished.
+func SetPrivacy(ctx context.Context, activityControl privacy.ActivityControl, req *openrtb_ext.RequestWrapper, gdprEnforced bool) {
+	r := fromContext(ctx)
+	if r == nil || req == nil {
+		return
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.state != stateSampled {
+		return
+	}
+
+	activityRequest := privacy.NewRequestFromBidRequest(*req)
+	if gdprEnforced ||
+		!activityControl.Allow(privacy.ActivityTransmitUserFPD, privacyComponent, activityRequest) ||
+		!activityControl.Allow(privacy.ActivityTransmitPreciseGeo, privacyComponent, activityRequest) {
+		r.discard()
+		return
+	}
+	r.privacyChecked = true
+}
+
+// SetResolvedRequest records the bid request passed to the exchange.
+func SetResolvedRequest(ctx context.Context, req *openrtb_ext.RequestWrapper) {
+	r := fromContext(ctx)
+	if r == nil || req == nil {
+		return
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.state != stateSampled {
+		return
+	}
+
+	if err := req.RebuildRequest(); err != nil {
+		logger.Errorf("Failed to capture the resolved request: %v", err)
+		r.discard()
+		return
+	}
+	requestJSON, err := jsonutil.Marshal(req.BidRequest)
+	if err != nil {
+		logger.Errorf("Failed to capture the resolved request: %v", err)
+		r.discard()
+		return
+	}
+	r.record.ResolvedRequest = requestJSON
+}
+
+// SetCurrencyRates records the host currency rates used by the auction.
+func SetCurrencyRates(ctx context.Context, rates currency.Conversions) {
+	r := fromContext(ctx)
+	if r == nil || rates == nil {
+		return
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.state != stateSampled {
+		return
+	}
+	if conversions := rates.GetRates(); conversions != nil {
+		r.record.CurrencyRates = *conversions
+	}
+}
+
+// AddBidderCall records an HTTP call made to a bidder. The bodies must be decompressed.
+func AddBidderCall(ctx context.Context, bidder string, req *adapters.RequestData, resp *adapters.ResponseData, err error) {
+	r := fromContext(ctx)
+	if r == nil || req == nil {
+		return
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.state != stateSampled {
+		return
+	}
+
+	call := BidderCall{
+		Bidder: bidder,
+		Request: HTTPRequest{
+			Method:  req.Method,
+			URI:     req.Uri,
+			Headers: filterHeaders(req.Headers),
+		},
+	}
+	call.Request.Body, call.Request.RawBody = splitBody(req.Body)
+	if resp != nil {
+		call.Response = &HTTPResponse{
+			StatusCode: resp.StatusCode,
+			Headers:    filterHeaders(resp.Headers),
+		}
+		call.Response.Body, call.Response.RawBody = splitBody(resp.Body)
+	}
+	if err != nil {
+		call.Error = err.Error()
+		var timeoutErr *errortypes.Timeout
+		call.Timeout = errors.As(err, &timeoutErr)
+	}
+	r.record.BidderCalls = append(r.record.BidderCalls, call)
+}
+
+// Finish records the response and the hook outcomes of the auction and queues the capture for writing, as
+// long as the privacy signals of the auction were checked. Any data recorded afterwards, for example by
+// bidders which responded after the auction ended, is ignored.
+func Finish(ctx context.Context, outcomes []hookexecution.StageOutcome) {
+	r := fromContext(ctx)
+	if r == nil {
+		return
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.state != stateSampled || !r.privacyChecked {
+		r.discard()
+		return
+	}
+	r.state = stateDone
+
+	r.record.Response = &HTTPResponse{
+		StatusCode: r.response.statusCode,
+		Headers:    filterHeaders(r.response.Header()),
+	}
+	r.record.Response.Body, r.record.Response.RawBody = splitBody(r.response.body.Bytes())
+	if len(outcomes) > 0 {
+		// the stage isn't part of the JSON representation of an outcome, so the outcomes are keyed by stage
+		outcomesByStage := make(map[string][]hookexecution.StageOutcome, len(outcomes))
+		for _, outcome := range outcomes {
+			outcomesByStage[outcome.Stage] = append(outcomesByStage[outcome.Stage], outcome)
+		}
+		outcomesJSON, err := jsonutil.Marshal(outcomesByStage)
+		if err != nil {
+			logger.Errorf("Failed to capture the hook outcomes: %v", err)
+			return
+		}
+		r.record.HookOutcomes = outcomesJSON
+	}
+
+	r.writer.write(&r.record)
+}
+
+// filterHeaders returns a copy of the headers holding only the captured headers.
+func filterHeaders(headers http.Header) http.Header {
+	var filtered http.Header
+	for _, name := range capturedHeaders {
+		if values := headers.Values(name); len(values) > 0 {
+			if filtered == nil {
+				filtered = make(http.Header, len(capturedHeaders))
+			}
+			filtered[name] = append([]string(nil), values...)
+		}
+	}
+	return filtered
+}
+
+// discard stops the recording of the auction and releases the data recorded so far.
+func (r *recorder) discard() {
+	r.state = stateDone
+	r.record = Record{}
+	r.response.body = bytes.Buffer{}
+}
+
+// sampled returns true if the auction is being captured.
+func (r *recorder) sampled() bool {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.state == stateSampled
+}
+
+// responseRecorder records the status and the body of the response while writing it, as long as the
+// auction may be captured.
+type responseRecorder struct {
+	http.ResponseWriter
+	recorder   *recorder
+	statusCode int
+	body       bytes.Buffer
+}
+
+func (w *responseRecorder) WriteHeader(statusCode int) {
+	if w.statusCode == 0 {
+		w.statusCode = statusCode
+	}
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (w *responseRecorder) Write(b []byte) (int, error) {
+	if w.statusCode == 0 {
+		w.statusCode = http.StatusOK
+	}
+	if w.recorder.sampled() {
+		w.body.Write(b)
+	}
+	return w.ResponseWriter.Write(b)
+}
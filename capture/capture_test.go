@@ -0,0 +1,383 @@
+package capture
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/buger/jsonparser"
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v4/adapters"
+	"github.com/prebid/prebid-server/v4/config"
+	"github.com/prebid/prebid-server/v4/currency"
+	"github.com/prebid/prebid-server/v4/errortypes"
+	"github.com/prebid/prebid-server/v4/hooks/hookexecution"
+	"github.com/prebid/prebid-server/v4/openrtb_ext"
+	"github.com/prebid/prebid-server/v4/privacy"
+	"github.com/prebid/prebid-server/v4/util/jsonutil"
+	"github.com/prebid/prebid-server/v4/util/ptrutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// startTestWriter registers a writer to a temporary directory, returning a function which closes it and
+// returns the written records.
+func startTestWriter(t *testing.T, samplingRate float64) func() []Record {
+	t.Helper()
+	directory := t.TempDir()
+	shutdown, err := NewWriter(config.Capture{
+		Enabled:       true,
+		Directory:     directory,
+		SamplingRate:  samplingRate,
+		MaxFileSizeMB: 1,
+		BufferSize:    10,
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { activeWriter.Store(nil) })
+
+	return func() []Record {
+		shutdown()
+		return readRecords(t, directory)
+	}
+}
+
+func readRecords(t *testing.T, directory string) []Record {
+	t.Helper()
+	files, err := filepath.Glob(filepath.Join(directory, "*.ndjson"))
+	require.NoError(t, err)
+
+	var records []Record
+	for _, file := range files {
+		f, err := os.Open(file)
+		require.NoError(t, err)
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			var record Record
+			require.NoError(t, jsonutil.UnmarshalValid(scanner.Bytes(), &record))
+			records = append(records, record)
+		}
+		f.Close()
+	}
+	return records
+}
+
+func TestStartDisabled(t *testing.T) {
+	ctx := context.Background()
+	w := httptest.NewRecorder()
+
+	capturedCtx, capturedWriter := Start(ctx, EndpointAuction, w)
+
+	assert.Equal(t, ctx, capturedCtx)
+	assert.Equal(t, w, capturedWriter)
+	assert.NotPanics(t, func() {
+		SetAccount(capturedCtx, &config.Account{ID: "acct"})
+		Finish(capturedCtx, nil)
+	})
+}
+
+func TestCaptureAuction(t *testing.T) {
+	stop := startTestWriter(t, 0)
+
+	ctx, w := Start(context.Background(), EndpointAuction, httptest.NewRecorder())
+
+	httpRequest := httptest.NewRequest(http.MethodPost, "/openrtb2/auction?debug=1", nil)
+	httpRequest.Header.Set("Content-Type", "application/json")
+	SetIncomingRequest(ctx, httpRequest, []byte(`{"id": "req-1"}`))
+	SetAccount(ctx, &config.Account{ID: "acct", Capture: config.AccountCapture{SamplingRate: ptrutil.ToPtr(1.0)}})
+	resolvedRequest := &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{ID: "req-1", TMax: 500}}
+	SetPrivacy(ctx, privacy.ActivityControl{}, resolvedRequest, false)
+	SetResolvedRequest(ctx, resolvedRequest)
+	SetCurrencyRates(ctx, currency.NewRates(map[string]map[string]float64{"USD": {"EUR": 0.9}}))
+	AddBidderCall(ctx, "appnexus",
+		&adapters.RequestData{Method: http.MethodPost, Uri: "https://bidder.com/bid", Body: []byte(`{"id":"req-1"}`)},
+		&adapters.ResponseData{StatusCode: http.StatusOK, Body: []byte(`{"id":"resp-1"}`)},
+		nil)
+	AddBidderCall(ctx, "rubicon",
+		&adapters.RequestData{Method: http.MethodGet, Uri: "https://other.com/bid?id=1"},
+		&adapters.ResponseData{StatusCode: http.StatusBadRequest, Body: []byte("bad request")},
+		&errortypes.BadServerResponse{Message: "Server responded with failure status: 400"})
+	AddBidderCall(ctx, "pubmatic",
+		&adapters.RequestData{Method: http.MethodPost, Uri: "https://slow.com/bid"},
+		nil,
+		&errortypes.Timeout{Message: "context deadline exceeded"})
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(`{"id":"req-1","seatbid":[]}`))
+	Finish(ctx, []hookexecution.StageOutcome{{Stage: "entrypoint"}})
+
+	// calls made after the auction ended are ignored
+	AddBidderCall(ctx, "late", &adapters.RequestData{Method: http.MethodPost, Uri: "https://late.com"}, nil, nil)
+
+	records := stop()
+	require.Len(t, records, 1)
+	record := records[0]
+
+	accountID, _ := jsonparser.GetString(record.Account, "id")
+	assert.Equal(t, "acct", accountID)
+	assert.Equal(t, RecordVersion, record.Version)
+	assert.Equal(t, EndpointAuction, record.Endpoint)
+	assert.False(t, record.Timestamp.IsZero())
+	assert.Equal(t, http.MethodPost, record.IncomingRequest.Method)
+	assert.Equal(t, "/openrtb2/auction?debug=1", record.IncomingRequest.URI)
+	assert.Equal(t, http.Header{"Content-Type": {"application/json"}}, record.IncomingRequest.Headers)
+	assert.Equal(t, `{"id":"req-1"}`, string(record.IncomingRequest.Body))
+	assert.JSONEq(t, `{"id":"req-1","imp":null,"tmax":500}`, string(record.ResolvedRequest))
+	assert.Equal(t, map[string]map[string]float64{"USD": {"EUR": 0.9}}, record.CurrencyRates)
+
+	expectedCalls := []BidderCall{
+		{
+			Bidder:   "appnexus",
+			Request:  HTTPRequest{Method: http.MethodPost, URI: "https://bidder.com/bid", Body: []byte(`{"id":"req-1"}`)},
+			Response: &HTTPResponse{StatusCode: http.StatusOK, Body: []byte(`{"id":"resp-1"}`)},
+		},
+		{
+			Bidder:   "rubicon",
+			Request:  HTTPRequest{Method: http.MethodGet, URI: "https://other.com/bid?id=1"},
+			Response: &HTTPResponse{StatusCode: http.StatusBadRequest, RawBody: []byte("bad request")},
+			Error:    "Server responded with failure status: 400",
+		},
+		{
+			Bidder:  "pubmatic",
+			Request: HTTPRequest{Method: http.MethodPost, URI: "https://slow.com/bid"},
+			Error:   "context deadline exceeded",
+			Timeout: true,
+		},
+	}
+	assert.Equal(t, expectedCalls, record.BidderCalls)
+	assert.Equal(t, http.StatusOK, record.Response.StatusCode)
+	assert.Equal(t, `{"id":"req-1","seatbid":[]}`, string(record.Response.Body))
+	assert.Contains(t, string(record.HookOutcomes), `"entrypoint":[{`)
+}
+
+func TestCaptureSampling(t *testing.T) {
+	testCases := []struct {
+		description         string
+		hostSamplingRate    float64
+		accountSamplingRate *float64
+		expectedRecords     int
+	}{
+		{
+			description:      "host-rate",
+			hostSamplingRate: 1,
+			expectedRecords:  1,
+		},
+		{
+			description:         "account-rate-overrides-host-rate",
+			hostSamplingRate:    1,
+			accountSamplingRate: ptrutil.ToPtr(0.0),
+			expectedRecords:     0,
+		},
+		{
+			description:      "not-sampled",
+			hostSamplingRate: 0,
+			expectedRecords:  0,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			stop := startTestWriter(t, test.hostSamplingRate)
+
+			ctx, w := Start(context.Background(), EndpointAuction, httptest.NewRecorder())
+			SetAccount(ctx, &config.Account{ID: "acct", Capture: config.AccountCapture{SamplingRate: test.accountSamplingRate}})
+			SetPrivacy(ctx, privacy.ActivityControl{}, &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{}}, false)
+			w.Write([]byte(`{}`))
+			Finish(ctx, nil)
+
+			assert.Len(t, stop(), test.expectedRecords)
+		})
+	}
+}
+
+func TestCaptureWithoutAccountIsDiscarded(t *testing.T) {
+	stop := startTestWriter(t, 1)
+
+	ctx, w := Start(context.Background(), EndpointAuction, httptest.NewRecorder())
+	SetIncomingRequest(ctx, httptest.NewRequest(http.MethodPost, "/openrtb2/auction", nil), []byte(`{}`))
+	w.WriteHeader(http.StatusBadRequest)
+	w.Write([]byte("Invalid request"))
+	Finish(ctx, nil)
+
+	assert.Empty(t, stop())
+}
+
+func TestCaptureResponse(t *testing.T) {
+	stop := startTestWriter(t, 1)
+
+	response := httptest.NewRecorder()
+	ctx, w := Start(context.Background(), EndpointAuction, response)
+	SetAccount(ctx, &config.Account{ID: "acct"})
+	SetPrivacy(ctx, privacy.ActivityControl{}, &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{}}, false)
+	w.Header().Set("Content-Type", "text/plain")
+	w.WriteHeader(http.StatusBadRequest)
+	w.Write([]byte("Invalid request: "))
+	w.Write([]byte("missing imp"))
+	Finish(ctx, nil)
+
+	records := stop()
+	require.Len(t, records, 1)
+	assert.Equal(t, http.StatusBadRequest, records[0].Response.StatusCode)
+	assert.Equal(t, "text/plain", records[0].Response.Headers.Get("Content-Type"))
+	assert.Equal(t, "Invalid request: missing imp", string(records[0].Response.GetBody()))
+
+	assert.Equal(t, http.StatusBadRequest, response.Code, "the response must still be written")
+	assert.Equal(t, "Invalid request: missing imp", response.Body.String(), "the response must still be written")
+}
+
+func TestCaptureRedactsHeaders(t *testing.T) {
+	stop := startTestWriter(t, 1)
+
+	ctx, w := Start(context.Background(), EndpointAuction, httptest.NewRecorder())
+	httpRequest := httptest.NewRequest(http.MethodPost, "/openrtb2/auction", nil)
+	httpRequest.Header.Set("Content-Type", "application/json")
+	httpRequest.Header.Set("Sec-GPC", "1")
+	httpRequest.Header.Set("Cookie", "uids=abc; other=def")
+	httpRequest.Header.Set("Authorization", "Bearer secret")
+	httpRequest.Header.Set("User-Agent", "test-agent")
+	httpRequest.Header.Set("X-Forwarded-For", "1.2.3.4")
+	SetIncomingRequest(ctx, httpRequest, []byte(`{}`))
+	SetAccount(ctx, &config.Account{ID: "acct"})
+	SetPrivacy(ctx, privacy.ActivityControl{}, &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{}}, false)
+	AddBidderCall(ctx, "appnexus",
+		&adapters.RequestData{
+			Method: http.MethodPost,
+			Uri:    "https://bidder.com/bid",
+			Headers: http.Header{
+				"Content-Type":    {"application/json"},
+				"Cookie":          {"uuid=xyz"},
+				"Authorization":   {"Basic c2VjcmV0"},
+				"User-Agent":      {"test-agent"},
+				"X-Forwarded-For": {"1.2.3.4"},
+			},
+		},
+		&adapters.ResponseData{
+			StatusCode: http.StatusNoContent,
+			Headers:    http.Header{"Content-Encoding": {"gzip"}, "Set-Cookie": {"uuid=xyz"}},
+		},
+		nil)
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Set-Cookie", "uids=abc")
+	w.Write([]byte(`{}`))
+	Finish(ctx, nil)
+
+	records := stop()
+	require.Len(t, records, 1)
+	record := records[0]
+	assert.Equal(t, http.Header{"Content-Type": {"application/json"}, "Sec-Gpc": {"1"}}, record.IncomingRequest.Headers)
+	require.Len(t, record.BidderCalls, 1)
+	assert.Equal(t, http.Header{"Content-Type": {"application/json"}}, record.BidderCalls[0].Request.Headers)
+	assert.Equal(t, http.Header{"Content-Encoding": {"gzip"}}, record.BidderCalls[0].Response.Headers)
+	assert.Equal(t, http.Header{"Content-Type": {"application/json"}}, record.Response.Headers)
+}
+
+func TestCapturePrivacy(t *testing.T) {
+	denyAll := &config.AccountPrivacy{
+		AllowActivities: &config.AllowActivities{
+			TransmitUserFPD:    config.Activity{Default: ptrutil.ToPtr(false)},
+			TransmitPreciseGeo: config.Activity{Default: ptrutil.ToPtr(false)},
+		},
+	}
+	withActivities := func(activities *config.AllowActivities) *config.AccountPrivacy {
+		return &config.AccountPrivacy{AllowActivities: activities}
+	}
+	denyRule := config.Activity{
+		Default: ptrutil.ToPtr(true),
+		Rules: []config.ActivityRule{{
+			Allow:     false,
+			Condition: config.ActivityCondition{ComponentName: []string{"capture"}},
+		}},
+	}
+
+	testCases := []struct {
+		description     string
+		privacy         *config.AccountPrivacy
+		gdprEnforced    bool
+		skipPrivacy     bool
+		expectedRecords int
+	}{
+		{
+			description:     "allowed",
+			privacy:         &config.AccountPrivacy{},
+			expectedRecords: 1,
+		},
+		{
+			description:     "gdpr-enforced",
+			privacy:         &config.AccountPrivacy{},
+			gdprEnforced:    true,
+			expectedRecords: 0,
+		},
+		{
+			description:     "activities-denied",
+			privacy:         denyAll,
+			expectedRecords: 0,
+		},
+		{
+			description:     "transmit-ufpd-denied-for-capture",
+			privacy:         withActivities(&config.AllowActivities{TransmitUserFPD: denyRule}),
+			expectedRecords: 0,
+		},
+		{
+			description:     "transmit-precise-geo-denied-for-capture",
+			privacy:         withActivities(&config.AllowActivities{TransmitPreciseGeo: denyRule}),
+			expectedRecords: 0,
+		},
+		{
+			description:     "privacy-not-checked",
+			privacy:         &config.AccountPrivacy{},
+			skipPrivacy:     true,
+			expectedRecords: 0,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			stop := startTestWriter(t, 1)
+
+			ctx, w := Start(context.Background(), EndpointAuction, httptest.NewRecorder())
+			SetIncomingRequest(ctx, httptest.NewRequest(http.MethodPost, "/openrtb2/auction", nil), []byte(`{"user":{"id":"user-1"}}`))
+			SetAccount(ctx, &config.Account{ID: "acct"})
+			if !test.skipPrivacy {
+				SetPrivacy(ctx, privacy.NewActivityControl(test.privacy), &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{}}, test.gdprEnforced)
+			}
+			w.Write([]byte(`{}`))
+			Finish(ctx, nil)
+
+			assert.Len(t, stop(), test.expectedRecords)
+		})
+	}
+}
+
+func TestSplitBody(t *testing.T) {
+	testCases := []struct {
+		description     string
+		body            []byte
+		expectedBody    string
+		expectedRawBody string
+	}{
+		{
+			description: "empty",
+			body:        nil,
+		},
+		{
+			description:  "json-compacted",
+			body:         []byte("{\n  \"id\": \"req-1\"\n}"),
+			expectedBody: `{"id":"req-1"}`,
+		},
+		{
+			description:     "not-json",
+			body:            []byte("<VAST></VAST>"),
+			expectedRawBody: "<VAST></VAST>",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			body, rawBody := splitBody(test.body)
+			assert.Equal(t, test.expectedBody, string(body))
+			assert.Equal(t, test.expectedRawBody, string(rawBody))
+		})
+	}
+}
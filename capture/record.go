@@ -0,0 +1,90 @@
+package capture
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+// RecordVersion is the version of the record schema written by this build.
+const RecordVersion = 1
+
+// Record is a captured auction. Each record is written as a single line of a capture file.
+type Record struct {
+	Version   int       `json:"version"`
+	Timestamp time.Time `json:"timestamp"`
+	Endpoint  string    `json:"endpoint"`
+	// Account is the account configuration resolved for the auction, including the host defaults.
+	Account json.RawMessage `json:"account,omitempty"`
+	// IncomingRequest is the HTTP request received by Prebid Server, with its body decompressed.
+	IncomingRequest HTTPRequest `json:"incoming_request"`
+	// ResolvedRequest is the bid request passed to the exchange, after stored requests were merged and
+	// missing fields were filled in.
+	ResolvedRequest json.RawMessage `json:"resolved_request,omitempty"`
+	// CurrencyRates are the host currency rates used by the auction.
+	CurrencyRates map[string]map[string]float64 `json:"currency_rates,omitempty"`
+	BidderCalls   []BidderCall                  `json:"bidder_calls,omitempty"`
+	HookOutcomes  json.RawMessage               `json:"hook_outcomes,omitempty"`
+	// Response is the HTTP response sent by Prebid Server.
+	Response *HTTPResponse `json:"response,omitempty"`
+}
+
+// HTTPRequest is a captured HTTP request. Bodies which are valid JSON are kept as is for readability,
+// while other bodies are base64 encoded in RawBody.
+type HTTPRequest struct {
+	Method  string          `json:"method"`
+	URI     string          `json:"uri"`
+	Headers http.Header     `json:"headers,omitempty"`
+	Body    json.RawMessage `json:"body,omitempty"`
+	RawBody []byte          `json:"raw_body,omitempty"`
+}
+
+// HTTPResponse is a captured HTTP response. Bodies are stored decompressed, in the same way as request bodies.
+type HTTPResponse struct {
+	StatusCode int             `json:"status_code"`
+	Headers    http.Header     `json:"headers,omitempty"`
+	Body       json.RawMessage `json:"body,omitempty"`
+	RawBody    []byte          `json:"raw_body,omitempty"`
+}
+
+// BidderCall is an HTTP call made to a bidder during the auction. Response is nil if the call failed
+// without a response, in which case Error describes the failure.
+type BidderCall struct {
+	Bidder   string        `json:"bidder"`
+	Request  HTTPRequest   `json:"request"`
+	Response *HTTPResponse `json:"response,omitempty"`
+	Error    string        `json:"error,omitempty"`
+	// Timeout is true if the call didn't complete before the auction timed out.
+	Timeout bool `json:"timeout,omitempty"`
+}
+
+// GetBody returns the body of the request.
+func (r *HTTPRequest) GetBody() []byte {
+	return getBody(r.Body, r.RawBody)
+}
+
+// GetBody returns the body of the response.
+func (r *HTTPResponse) GetBody() []byte {
+	return getBody(r.Body, r.RawBody)
+}
+
+func getBody(body json.RawMessage, rawBody []byte) []byte {
+	if len(body) > 0 {
+		return body
+	}
+	return rawBody
+}
+
+// splitBody returns a compacted copy of the body if it's valid JSON, which keeps the record on a single
+// line, or a copy of the raw bytes otherwise.
+func splitBody(body []byte) (json.RawMessage, []byte) {
+	if len(body) == 0 {
+		return nil, nil
+	}
+	compacted := bytes.NewBuffer(make([]byte, 0, len(body)))
+	if err := json.Compact(compacted, body); err == nil {
+		return compacted.Bytes(), nil
+	}
+	return nil, bytes.Clone(body)
+}
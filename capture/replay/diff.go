@@ -0,0 +1,87 @@
+package replay
+
+import (
+	"bytes"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/prebid/prebid-server/v4/util/jsonutil"
+	"github.com/yudai/gojsondiff"
+	"github.com/yudai/gojsondiff/formatter"
+)
+
+// DefaultIgnoredPaths are the response fields which change from one auction to the next regardless of the
+// bidder responses, such as timings and generated IDs.
+var DefaultIgnoredPaths = []string{
+	"ext.responsetimemillis",
+	"ext.tmaxrequest",
+	"ext.prebid.auctiontimestamp",
+	"ext.prebid.modules.trace",
+	"seatbid.*.bid.*.ext.prebid.bidid",
+	"seatbid.*.bid.*.ext.prebid.cache",
+	"seatbid.*.bid.*.ext.prebid.targeting.hb_cache_*",
+	"seatbid.*.bid.*.ext.prebid.targeting.hb_uuid*",
+}
+
+// diffResponses returns a human readable diff of the replayed response body against the captured one, or
+// an empty string if they're equal. JSON objects are compared structurally, leaving out the fields matching
+// one of the ignored paths. Paths are dot separated, where each segment is a pattern as understood by
+// path.Match matching object keys or array indexes. Other bodies, such as error messages, are compared as is.
+func diffResponses(captured, replayed []byte, ignoredPaths []string) string {
+	var left, right map[string]any
+	if jsonutil.UnmarshalValid(captured, &left) != nil || jsonutil.UnmarshalValid(replayed, &right) != nil {
+		if bytes.Equal(captured, replayed) {
+			return ""
+		}
+		return fmt.Sprintf("-%s\n+%s\n", captured, replayed)
+	}
+
+	for _, ignoredPath := range ignoredPaths {
+		segments := strings.Split(ignoredPath, ".")
+		removePath(left, segments)
+		removePath(right, segments)
+	}
+
+	diff := gojsondiff.New().CompareObjects(left, right)
+	if !diff.Modified() {
+		return ""
+	}
+	printer := formatter.NewAsciiFormatter(left, formatter.AsciiFormatterConfig{ShowArrayIndex: true})
+	output, err := printer.Format(diff)
+	if err != nil {
+		return fmt.Sprintf("the responses differ but the diff failed: %v", err)
+	}
+	return output
+}
+
+// removePath deletes the values matching the path segments from the parsed JSON value.
+func removePath(value any, segments []string) {
+	if len(segments) == 0 {
+		return
+	}
+	pattern, rest := segments[0], segments[1:]
+
+	switch typed := value.(type) {
+	case map[string]any:
+		for key, child := range typed {
+			if matched, _ := path.Match(pattern, key); !matched {
+				continue
+			}
+			if len(rest) == 0 {
+				delete(typed, key)
+			} else {
+				removePath(child, rest)
+			}
+		}
+	case []any:
+		// array elements are only removed through their children, since removing them would shift the
+		// indexes of the other elements
+		for i, child := range typed {
+			if matched, _ := path.Match(pattern, strconv.Itoa(i)); matched {
+				removePath(child, rest)
+			}
+		}
+	}
+}
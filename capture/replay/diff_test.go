@@ -0,0 +1,114 @@
+package replay
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffResponses(t *testing.T) {
+	testCases := []struct {
+		description  string
+		captured     string
+		replayed     string
+		ignoredPaths []string
+		expectEqual  bool
+	}{
+		{
+			description: "equal",
+			captured:    `{"id":"req-1","seatbid":[{"bid":[{"price":1.5}]}]}`,
+			replayed:    `{"seatbid":[{"bid":[{"price":1.5}]}],"id":"req-1"}`,
+			expectEqual: true,
+		},
+		{
+			description: "different-price",
+			captured:    `{"id":"req-1","seatbid":[{"bid":[{"price":1.5}]}]}`,
+			replayed:    `{"id":"req-1","seatbid":[{"bid":[{"price":2}]}]}`,
+			expectEqual: false,
+		},
+		{
+			description:  "ignored-paths",
+			captured:     `{"id":"req-1","ext":{"responsetimemillis":{"appnexus":12}},"seatbid":[{"bid":[{"ext":{"prebid":{"bidid":"a","targeting":{"hb_cache_id":"1","hb_pb":"1.50"}}}}]}]}`,
+			replayed:     `{"id":"req-1","ext":{"responsetimemillis":{"appnexus":3}},"seatbid":[{"bid":[{"ext":{"prebid":{"bidid":"b","targeting":{"hb_cache_id":"2","hb_pb":"1.50"}}}}]}]}`,
+			ignoredPaths: DefaultIgnoredPaths,
+			expectEqual:  true,
+		},
+		{
+			description:  "not-ignored-targeting",
+			captured:     `{"seatbid":[{"bid":[{"ext":{"prebid":{"targeting":{"hb_pb":"1.50"}}}}]}]}`,
+			replayed:     `{"seatbid":[{"bid":[{"ext":{"prebid":{"targeting":{"hb_pb":"2.00"}}}}]}]}`,
+			ignoredPaths: DefaultIgnoredPaths,
+			expectEqual:  false,
+		},
+		{
+			description: "equal-text",
+			captured:    "Invalid request: missing imp\n",
+			replayed:    "Invalid request: missing imp\n",
+			expectEqual: true,
+		},
+		{
+			description: "text-and-json",
+			captured:    "Invalid request: missing imp\n",
+			replayed:    `{"id":"req-1"}`,
+			expectEqual: false,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			diff := diffResponses([]byte(test.captured), []byte(test.replayed), test.ignoredPaths)
+			if test.expectEqual {
+				assert.Empty(t, diff)
+			} else {
+				assert.NotEmpty(t, diff)
+			}
+		})
+	}
+}
+
+func TestRemovePath(t *testing.T) {
+	testCases := []struct {
+		description string
+		path        []string
+		expected    any
+	}{
+		{
+			description: "key",
+			path:        []string{"ext", "a"},
+			expected:    map[string]any{"ext": map[string]any{"b": 2.0}, "list": []any{map[string]any{"c": 1.0, "d": 2.0}, map[string]any{"c": 3.0}}},
+		},
+		{
+			description: "key-pattern",
+			path:        []string{"ext", "*"},
+			expected:    map[string]any{"ext": map[string]any{}, "list": []any{map[string]any{"c": 1.0, "d": 2.0}, map[string]any{"c": 3.0}}},
+		},
+		{
+			description: "all-array-elements",
+			path:        []string{"list", "*", "c"},
+			expected:    map[string]any{"ext": map[string]any{"a": 1.0, "b": 2.0}, "list": []any{map[string]any{"d": 2.0}, map[string]any{}}},
+		},
+		{
+			description: "array-index",
+			path:        []string{"list", "1", "c"},
+			expected:    map[string]any{"ext": map[string]any{"a": 1.0, "b": 2.0}, "list": []any{map[string]any{"c": 1.0, "d": 2.0}, map[string]any{}}},
+		},
+		{
+			description: "missing",
+			path:        []string{"ext", "a", "b"},
+			expected:    map[string]any{"ext": map[string]any{"a": 1.0, "b": 2.0}, "list": []any{map[string]any{"c": 1.0, "d": 2.0}, map[string]any{"c": 3.0}}},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			value := map[string]any{
+				"ext":  map[string]any{"a": 1.0, "b": 2.0},
+				"list": []any{map[string]any{"c": 1.0, "d": 2.0}, map[string]any{"c": 3.0}},
+			}
+
+			removePath(value, test.path)
+
+			assert.Equal(t, test.expected, value)
+		})
+	}
+}
@@ -0,0 +1,255 @@
+// Package replay runs captured auctions again through a fully wired auction endpoint, answering the bidder
+// requests with the recorded bidder responses, and compares the outcome with the captured response.
+package replay
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/buger/jsonparser"
+	"github.com/julienschmidt/httprouter"
+	analyticsBuild "github.com/prebid/prebid-server/v4/analytics/build"
+	"github.com/prebid/prebid-server/v4/capture"
+	"github.com/prebid/prebid-server/v4/config"
+	"github.com/prebid/prebid-server/v4/currency"
+	"github.com/prebid/prebid-server/v4/endpoints/openrtb2"
+	"github.com/prebid/prebid-server/v4/errortypes"
+	"github.com/prebid/prebid-server/v4/exchange"
+	"github.com/prebid/prebid-server/v4/experiment/adscert"
+	"github.com/prebid/prebid-server/v4/gdpr"
+	"github.com/prebid/prebid-server/v4/hooks"
+	"github.com/prebid/prebid-server/v4/macros"
+	metricsConf "github.com/prebid/prebid-server/v4/metrics/config"
+	"github.com/prebid/prebid-server/v4/modules"
+	"github.com/prebid/prebid-server/v4/modules/moduledeps"
+	"github.com/prebid/prebid-server/v4/openrtb_ext"
+	"github.com/prebid/prebid-server/v4/ortb"
+	pbc "github.com/prebid/prebid-server/v4/prebid_cache_client"
+	"github.com/prebid/prebid-server/v4/stored_requests"
+	"github.com/prebid/prebid-server/v4/stored_requests/backends/empty_fetcher"
+	storedRequestsConf "github.com/prebid/prebid-server/v4/stored_requests/config"
+	"github.com/prebid/prebid-server/v4/usersync"
+	"github.com/prebid/prebid-server/v4/util/uuidutil"
+)
+
+// Result is the outcome of replaying a captured auction.
+type Result struct {
+	// RequestID is the ID of the resolved bid request of the capture.
+	RequestID      string
+	AccountID      string
+	Timestamp      time.Time
+	CapturedStatus int
+	ReplayedStatus int
+	// Diff describes the differences between the captured and the replayed responses. It's empty if the
+	// responses are equal.
+	Diff string
+	// UnmatchedBidderRequests are the bidder requests made during the replay for which no call was recorded.
+	UnmatchedBidderRequests []string
+}
+
+// Equal returns true if the replay produced the captured response.
+func (r *Result) Equal() bool {
+	return r.CapturedStatus == r.ReplayedStatus && r.Diff == ""
+}
+
+// Replayer replays captured auctions. Captures must be replayed one at a time.
+type Replayer struct {
+	handler      httprouter.Handle
+	bidders      *bidderTransport
+	rates        *ratesClient
+	converter    *currency.RateConverter
+	accounts     *accountFetcher
+	ignoredPaths []string
+	shutdown     func()
+}
+
+// NewReplayer builds an auction endpoint from the host configuration, in which bidders, Prebid Cache and
+// currency rates are served from the capture being replayed. Accounts are always read from the capture,
+// while stored requests aren't needed since captures hold the resolved bid request. Fields of the responses
+// matching the ignored paths aren't compared.
+func NewReplayer(cfg *config.Configuration, ignoredPaths []string) (*Replayer, error) {
+	const schemaDirectory = "./static/bidder-params"
+
+	me := &metricsConf.NilMetricsEngine{}
+	bidders := &bidderTransport{}
+	biddersClient := &http.Client{Transport: bidders}
+	rates := &ratesClient{}
+	converter := currency.NewRateConverter(rates, time.Second, "replay", 0)
+
+	syncersByBidder, errs := usersync.BuildSyncers(cfg, cfg.BidderInfos)
+	if len(errs) > 0 {
+		return nil, errortypes.NewAggregateError("user sync", errs)
+	}
+
+	moduleDeps := moduledeps.ModuleDeps{HTTPClient: biddersClient, RateConvertor: converter}
+	repo, _, shutdownModules, err := modules.NewBuilder().Build(cfg.Hooks.Modules, moduleDeps)
+	if err != nil {
+		return nil, fmt.Errorf("failed to init hook modules: %v", err)
+	}
+
+	categoriesFetcher, shutdownCategories := storedRequestsConf.CreateStoredRequests(&cfg.CategoryMapping, me, biddersClient, httprouter.New(), nil)
+	storedRespFetcher, shutdownStoredResp := storedRequestsConf.CreateStoredRequests(&cfg.StoredResponses, me, biddersClient, httprouter.New(), nil)
+
+	paramsValidator, err := openrtb_ext.NewBidderParamsValidator(schemaDirectory)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the bidder params validator: %v", err)
+	}
+	activeBidders := exchange.GetActiveBidders(cfg.BidderInfos)
+	disabledBidders := exchange.GetDisabledBidderWarningMessages(cfg.BidderInfos)
+	requestValidator := ortb.NewRequestValidator(activeBidders, disabledBidders, paramsValidator)
+
+	// the global vendor lists are public and rarely change, so they're fetched rather than captured
+	vendorListFetcher := gdpr.NewVendorListFetcher(context.Background(), cfg.GDPR, &http.Client{}, me, gdpr.VendorListURLMaker)
+	gdprPermsBuilder := gdpr.NewPermissionsBuilder(cfg.GDPR, cfg.BidderInfos.ToGVLVendorIDMap(), gdpr.NewLiveGVLVendorIDs(), vendorListFetcher, me)
+
+	adapters, singleFormatAdapters, adaptersErrs := exchange.BuildAdapters(biddersClient, cfg, cfg.BidderInfos, me)
+	if len(adaptersErrs) > 0 {
+		return nil, errortypes.NewAggregateError("Failed to initialize adapters", adaptersErrs)
+	}
+	adsCertSigner, err := adscert.NewAdCertsSigner(cfg.Experiment.AdCerts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create ads cert signer: %v", err)
+	}
+
+	cacheClient := pbc.NewClient(&http.Client{Transport: cacheTransport{}}, &cfg.CacheURL, &cfg.ExtCacheURL, me)
+	tmaxAdjustments := exchange.ProcessTMaxAdjustments(cfg.TmaxAdjustments)
+	planBuilder := hooks.NewExecutionPlanBuilder(cfg.Hooks, repo)
+	theExchange := exchange.NewExchange(adapters, cacheClient, cfg, requestValidator, syncersByBidder, me, cfg.BidderInfos, gdprPermsBuilder, converter, categoriesFetcher.(stored_requests.CategoryFetcher), adsCertSigner, macros.NewStringIndexBasedReplacer(), noFloorsFetcher{}, singleFormatAdapters)
+
+	accounts := &accountFetcher{}
+	var uuidGenerator uuidutil.UUIDRandomGenerator
+	handler, err := openrtb2.NewEndpoint(uuidGenerator, theExchange, requestValidator, empty_fetcher.EmptyFetcher{}, accounts, cfg, me, analyticsBuild.New(&config.Analytics{}), disabledBidders, nil, activeBidders, storedRespFetcher.(stored_requests.Fetcher), planBuilder, tmaxAdjustments, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Replayer{
+		handler:      handler,
+		bidders:      bidders,
+		rates:        rates,
+		converter:    converter,
+		accounts:     accounts,
+		ignoredPaths: ignoredPaths,
+		shutdown: func() {
+			shutdownModules.Shutdown()
+			shutdownCategories()
+			shutdownStoredResp()
+		},
+	}, nil
+}
+
+// Replay runs the captured auction again and compares the response with the captured one.
+func (r *Replayer) Replay(record *capture.Record) (*Result, error) {
+	if record.Endpoint != capture.EndpointAuction {
+		return nil, fmt.Errorf("replaying captures of the %s endpoint isn't supported", record.Endpoint)
+	}
+	if len(record.ResolvedRequest) == 0 {
+		return nil, errors.New("the capture doesn't hold a resolved request")
+	}
+	if record.Response == nil {
+		return nil, errors.New("the capture doesn't hold a response")
+	}
+
+	accountID, _ := jsonparser.GetString(record.Account, "id")
+	requestID, _ := jsonparser.GetString(record.ResolvedRequest, "id")
+	result := &Result{
+		RequestID:      requestID,
+		AccountID:      accountID,
+		Timestamp:      record.Timestamp,
+		CapturedStatus: record.Response.StatusCode,
+	}
+
+	body, err := removeStoredRequestIDs(record.ResolvedRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	r.bidders.load(record.BidderCalls)
+	r.accounts.load(record.Account)
+	r.rates.load(record.CurrencyRates)
+	if err := r.converter.Run(); err != nil {
+		return nil, fmt.Errorf("failed to load the currency rates: %v", err)
+	}
+
+	httpRequest := httptest.NewRequest(http.MethodPost, capture.EndpointAuction, bytes.NewReader(body))
+	for name, values := range record.IncomingRequest.Headers {
+		httpRequest.Header[name] = values
+	}
+	// the body sent is the uncompressed resolved request
+	httpRequest.Header.Del("Content-Encoding")
+	httpRequest.Header.Del("Content-Length")
+
+	recorder := httptest.NewRecorder()
+	r.handler(recorder, httpRequest, nil)
+
+	result.ReplayedStatus = recorder.Code
+	result.UnmatchedBidderRequests = r.bidders.unmatchedRequests()
+
+	result.Diff = diffResponses(record.Response.GetBody(), recorder.Body.Bytes(), r.ignoredPaths)
+	return result, nil
+}
+
+// Shutdown stops the hook modules and fetchers of the replayer.
+func (r *Replayer) Shutdown() {
+	r.shutdown()
+}
+
+// removeStoredRequestIDs removes the references to stored requests and imps from the resolved request,
+// whose stored data is already merged in.
+func removeStoredRequestIDs(request json.RawMessage) ([]byte, error) {
+	result := jsonparser.Delete(bytes.Clone(request), "ext", "prebid", "storedrequest")
+
+	var imps [][]byte
+	var parseErr error
+	_, err := jsonparser.ArrayEach(result, func(imp []byte, _ jsonparser.ValueType, _ int, err error) {
+		if err != nil {
+			parseErr = err
+			return
+		}
+		imps = append(imps, jsonparser.Delete(bytes.Clone(imp), "ext", "prebid", "storedrequest"))
+	}, "imp")
+	if err != nil && err != jsonparser.KeyPathNotFoundError {
+		return nil, fmt.Errorf("failed to parse the resolved request: %v", err)
+	}
+	if parseErr != nil {
+		return nil, fmt.Errorf("failed to parse the resolved request: %v", parseErr)
+	}
+	if imps == nil {
+		return result, nil
+	}
+
+	impsJSON := append([]byte{'['}, bytes.Join(imps, []byte{','})...)
+	impsJSON = append(impsJSON, ']')
+	return jsonparser.Set(result, impsJSON, "imp")
+}
+
+// accountFetcher serves the account of the capture being replayed.
+type accountFetcher struct {
+	account json.RawMessage
+}
+
+func (f *accountFetcher) load(account json.RawMessage) {
+	f.account = account
+}
+
+func (f *accountFetcher) FetchAccount(_ context.Context, _ json.RawMessage, accountID string) (json.RawMessage, []error) {
+	if len(f.account) == 0 {
+		return nil, []error{stored_requests.NotFoundError{ID: accountID, DataType: "Account"}}
+	}
+	return f.account, nil
+}
+
+// noFloorsFetcher disables the fetching of dynamic floors, which aren't part of the captures.
+type noFloorsFetcher struct{}
+
+func (noFloorsFetcher) Fetch(config.AccountPriceFloors) (*openrtb_ext.PriceFloorRules, string) {
+	return nil, openrtb_ext.FetchNone
+}
+
+func (noFloorsFetcher) Stop() {}
@@ -0,0 +1,56 @@
+package replay
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/prebid/prebid-server/v4/stored_requests"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRemoveStoredRequestIDs(t *testing.T) {
+	testCases := []struct {
+		description string
+		request     string
+		expected    string
+	}{
+		{
+			description: "stored-request-and-imps",
+			request:     `{"id":"req-1","imp":[{"id":"imp-1","ext":{"prebid":{"storedrequest":{"id":"1"},"bidder":{"appnexus":{}}}}},{"id":"imp-2"}],"ext":{"prebid":{"storedrequest":{"id":"2"},"debug":true}}}`,
+			expected:    `{"id":"req-1","imp":[{"id":"imp-1","ext":{"prebid":{"bidder":{"appnexus":{}}}}},{"id":"imp-2"}],"ext":{"prebid":{"debug":true}}}`,
+		},
+		{
+			description: "no-stored-request",
+			request:     `{"id":"req-1","imp":[{"id":"imp-1"}]}`,
+			expected:    `{"id":"req-1","imp":[{"id":"imp-1"}]}`,
+		},
+		{
+			description: "no-imps",
+			request:     `{"id":"req-1"}`,
+			expected:    `{"id":"req-1"}`,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			result, err := removeStoredRequestIDs(json.RawMessage(test.request))
+
+			require.NoError(t, err)
+			assert.JSONEq(t, test.expected, string(result))
+		})
+	}
+}
+
+func TestAccountFetcher(t *testing.T) {
+	fetcher := &accountFetcher{}
+
+	_, errs := fetcher.FetchAccount(context.Background(), nil, "acct")
+	assert.Equal(t, []error{stored_requests.NotFoundError{ID: "acct", DataType: "Account"}}, errs)
+
+	fetcher.load(json.RawMessage(`{"id":"acct"}`))
+	account, errs := fetcher.FetchAccount(context.Background(), nil, "acct")
+	assert.Empty(t, errs)
+	assert.JSONEq(t, `{"id":"acct"}`, string(account))
+}
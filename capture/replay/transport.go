@@ -0,0 +1,216 @@
+package replay
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+
+	"github.com/prebid/prebid-server/v4/capture"
+	"github.com/prebid/prebid-server/v4/currency"
+	"github.com/prebid/prebid-server/v4/util/httputil"
+	"github.com/prebid/prebid-server/v4/util/jsonutil"
+)
+
+// bidderTransport answers the bidder requests with the responses recorded in a capture, so that an
+// auction can be replayed without reaching the bidders.
+type bidderTransport struct {
+	mutex     sync.Mutex
+	calls     []capture.BidderCall
+	used      []bool
+	unmatched []string
+}
+
+// load replaces the recorded calls with those of the next capture to replay.
+func (t *bidderTransport) load(calls []capture.BidderCall) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.calls = calls
+	t.used = make([]bool, len(calls))
+	t.unmatched = nil
+}
+
+// unmatchedRequests returns the requests made since the last load for which no call was recorded.
+func (t *bidderTransport) unmatchedRequests() []string {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	return t.unmatched
+}
+
+func (t *bidderTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	call := t.match(req.Method, req.URL.String(), body)
+	if call == nil {
+		return nil, fmt.Errorf("no recorded response for %s %s", req.Method, req.URL.String())
+	}
+	if call.Response == nil {
+		if call.Timeout {
+			return nil, context.DeadlineExceeded
+		}
+		return nil, errors.New(call.Error)
+	}
+
+	header := call.Response.Headers.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+	// the recorded body is decompressed
+	header.Del("Content-Encoding")
+	header.Del("Content-Length")
+
+	responseBody := call.Response.GetBody()
+	return &http.Response{
+		Status:        http.StatusText(call.Response.StatusCode),
+		StatusCode:    call.Response.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(responseBody)),
+		ContentLength: int64(len(responseBody)),
+		Request:       req,
+	}, nil
+}
+
+// match returns the first unused call recorded for the method and URI, preferring a call with the same
+// body since bidders may send several requests to the same endpoint.
+func (t *bidderTransport) match(method, uri string, body []byte) *capture.BidderCall {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	candidate := -1
+	for i := range t.calls {
+		if t.used[i] || t.calls[i].Request.Method != method || t.calls[i].Request.URI != uri {
+			continue
+		}
+		if sameBody(t.calls[i].Request.GetBody(), body) {
+			candidate = i
+			break
+		}
+		if candidate < 0 {
+			candidate = i
+		}
+	}
+	if candidate < 0 {
+		t.unmatched = append(t.unmatched, method+" "+uri)
+		return nil
+	}
+	t.used[candidate] = true
+	return &t.calls[candidate]
+}
+
+func readRequestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil {
+		return nil, nil
+	}
+	defer req.Body.Close()
+
+	contentEncoding := httputil.ContentEncoding(req.Header.Get("Content-Encoding"))
+	if contentEncoding == "" {
+		return io.ReadAll(req.Body)
+	}
+	reader, err := httputil.NewDecodingReader(req.Body, contentEncoding)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	return io.ReadAll(reader)
+}
+
+// sameBody compares the bodies ignoring JSON whitespace, since JSON bodies are stored compacted.
+func sameBody(recorded, actual []byte) bool {
+	if bytes.Equal(recorded, actual) {
+		return true
+	}
+	compacted := bytes.NewBuffer(make([]byte, 0, len(actual)))
+	if err := json.Compact(compacted, actual); err != nil {
+		return false
+	}
+	return bytes.Equal(recorded, compacted.Bytes())
+}
+
+// cacheTransport stands in for Prebid Cache, answering each put with a generated UUID.
+type cacheTransport struct{}
+
+type cachePuts struct {
+	Puts []json.RawMessage `json:"puts"`
+}
+
+type cacheResponse struct {
+	Responses []cacheUUID `json:"responses"`
+}
+
+type cacheUUID struct {
+	UUID string `json:"uuid"`
+}
+
+func (cacheTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+	var puts cachePuts
+	if err := jsonutil.UnmarshalValid(body, &puts); err != nil {
+		return nil, err
+	}
+
+	response := cacheResponse{Responses: make([]cacheUUID, len(puts.Puts))}
+	for i := range puts.Puts {
+		response.Responses[i].UUID = fmt.Sprintf("replay-%d", i)
+	}
+	responseBody, err := jsonutil.Marshal(response)
+	if err != nil {
+		return nil, err
+	}
+	return &http.Response{
+		Status:        http.StatusText(http.StatusOK),
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": []string{"application/json"}},
+		Body:          io.NopCloser(bytes.NewReader(responseBody)),
+		ContentLength: int64(len(responseBody)),
+		Request:       req,
+	}, nil
+}
+
+// ratesClient serves the currency rates recorded in a capture to the currency converter.
+type ratesClient struct {
+	mutex sync.Mutex
+	rates map[string]map[string]float64
+}
+
+func (c *ratesClient) load(rates map[string]map[string]float64) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.rates = rates
+}
+
+func (c *ratesClient) Do(req *http.Request) (*http.Response, error) {
+	c.mutex.Lock()
+	rates := currency.NewRates(c.rates)
+	c.mutex.Unlock()
+	if rates.Conversions == nil {
+		rates.Conversions = map[string]map[string]float64{}
+	}
+
+	body, err := jsonutil.Marshal(rates)
+	if err != nil {
+		return nil, err
+	}
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(bytes.NewReader(body)),
+		Request:    req,
+	}, nil
+}
@@ -0,0 +1,177 @@
+package replay
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/prebid/prebid-server/v4/capture"
+	"github.com/prebid/prebid-server/v4/util/httputil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBidderTransport(t *testing.T) {
+	calls := []capture.BidderCall{
+		{
+			Bidder:   "appnexus",
+			Request:  capture.HTTPRequest{Method: http.MethodPost, URI: "https://bidder.com/bid", Body: []byte(`{"imp":"1"}`)},
+			Response: &capture.HTTPResponse{StatusCode: http.StatusOK, Body: []byte(`{"id":"resp-1"}`)},
+		},
+		{
+			Bidder:   "appnexus",
+			Request:  capture.HTTPRequest{Method: http.MethodPost, URI: "https://bidder.com/bid", Body: []byte(`{"imp":"2"}`)},
+			Response: &capture.HTTPResponse{StatusCode: http.StatusOK, Body: []byte(`{"id":"resp-2"}`)},
+		},
+		{
+			Bidder:  "rubicon",
+			Request: capture.HTTPRequest{Method: http.MethodGet, URI: "https://other.com/bid"},
+			Response: &capture.HTTPResponse{
+				StatusCode: http.StatusNoContent,
+				Headers:    http.Header{"Content-Encoding": []string{"gzip"}, "X-Test": []string{"1"}},
+			},
+		},
+		{
+			Bidder:  "pubmatic",
+			Request: capture.HTTPRequest{Method: http.MethodPost, URI: "https://slow.com/bid"},
+			Error:   "context deadline exceeded",
+			Timeout: true,
+		},
+		{
+			Bidder:  "openx",
+			Request: capture.HTTPRequest{Method: http.MethodPost, URI: "https://down.com/bid"},
+			Error:   "connection refused",
+		},
+	}
+
+	transport := &bidderTransport{}
+	transport.load(calls)
+
+	t.Run("prefers-same-body", func(t *testing.T) {
+		resp, err := transport.RoundTrip(newRequest(t, http.MethodPost, "https://bidder.com/bid", "{\n\"imp\": \"2\"}"))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, `{"id":"resp-2"}`, readBody(t, resp))
+	})
+
+	t.Run("falls-back-to-same-uri", func(t *testing.T) {
+		resp, err := transport.RoundTrip(newRequest(t, http.MethodPost, "https://bidder.com/bid", `{"imp":"3"}`))
+		require.NoError(t, err)
+		assert.Equal(t, `{"id":"resp-1"}`, readBody(t, resp))
+	})
+
+	t.Run("calls-are-used-once", func(t *testing.T) {
+		_, err := transport.RoundTrip(newRequest(t, http.MethodPost, "https://bidder.com/bid", `{"imp":"1"}`))
+		assert.EqualError(t, err, "no recorded response for POST https://bidder.com/bid")
+	})
+
+	t.Run("decoded-body", func(t *testing.T) {
+		resp, err := transport.RoundTrip(newRequest(t, http.MethodGet, "https://other.com/bid", ""))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+		assert.Empty(t, resp.Header.Get("Content-Encoding"))
+		assert.Equal(t, "1", resp.Header.Get("X-Test"))
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		_, err := transport.RoundTrip(newRequest(t, http.MethodPost, "https://slow.com/bid", ""))
+		assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	})
+
+	t.Run("error", func(t *testing.T) {
+		_, err := transport.RoundTrip(newRequest(t, http.MethodPost, "https://down.com/bid", ""))
+		assert.EqualError(t, err, "connection refused")
+	})
+
+	assert.Equal(t, []string{"POST https://bidder.com/bid"}, transport.unmatchedRequests())
+
+	transport.load(nil)
+	assert.Empty(t, transport.unmatchedRequests())
+}
+
+func TestBidderTransportCompressedRequest(t *testing.T) {
+	transport := &bidderTransport{}
+	transport.load([]capture.BidderCall{
+		{
+			Request:  capture.HTTPRequest{Method: http.MethodPost, URI: "https://bidder.com/bid", Body: []byte(`{"imp":"1"}`)},
+			Response: &capture.HTTPResponse{StatusCode: http.StatusOK, Body: []byte(`{"id":"resp-1"}`)},
+		},
+		{
+			Request:  capture.HTTPRequest{Method: http.MethodPost, URI: "https://bidder.com/bid", Body: []byte(`{"imp":"2"}`)},
+			Response: &capture.HTTPResponse{StatusCode: http.StatusOK, Body: []byte(`{"id":"resp-2"}`)},
+		},
+	})
+
+	var compressed bytes.Buffer
+	encoder, err := httputil.AcquireEncoder(httputil.ContentEncodingGZIP, &compressed)
+	require.NoError(t, err)
+	encoder.Write([]byte(`{"imp":"2"}`))
+	require.NoError(t, encoder.Close())
+	httputil.ReleaseEncoder(httputil.ContentEncodingGZIP, encoder)
+
+	req := newRequest(t, http.MethodPost, "https://bidder.com/bid", compressed.String())
+	req.Header.Set("Content-Encoding", "gzip")
+
+	resp, err := transport.RoundTrip(req)
+	require.NoError(t, err)
+	assert.Equal(t, `{"id":"resp-2"}`, readBody(t, resp))
+}
+
+func TestCacheTransport(t *testing.T) {
+	req := newRequest(t, http.MethodPost, "https://cache.com/cache", `{"puts":[{"type":"json","value":{}},{"type":"xml","value":"<VAST/>"}]}`)
+
+	resp, err := cacheTransport{}.RoundTrip(req)
+
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.JSONEq(t, `{"responses":[{"uuid":"replay-0"},{"uuid":"replay-1"}]}`, readBody(t, resp))
+}
+
+func TestRatesClient(t *testing.T) {
+	testCases := []struct {
+		description  string
+		rates        map[string]map[string]float64
+		expectedBody string
+	}{
+		{
+			description:  "rates",
+			rates:        map[string]map[string]float64{"USD": {"EUR": 0.9}},
+			expectedBody: `{"conversions":{"USD":{"EUR":0.9}}}`,
+		},
+		{
+			description:  "no-rates",
+			rates:        nil,
+			expectedBody: `{"conversions":{}}`,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			client := &ratesClient{}
+			client.load(test.rates)
+
+			resp, err := client.Do(newRequest(t, http.MethodGet, "https://rates.com", ""))
+
+			require.NoError(t, err)
+			assert.JSONEq(t, test.expectedBody, readBody(t, resp))
+		})
+	}
+}
+
+func newRequest(t *testing.T, method, uri, body string) *http.Request {
+	t.Helper()
+	req, err := http.NewRequest(method, uri, strings.NewReader(body))
+	require.NoError(t, err)
+	return req
+}
+
+func readBody(t *testing.T, resp *http.Response) string {
+	t.Helper()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return string(body)
+}
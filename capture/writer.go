@@ -0,0 +1,171 @@
+package capture
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/prebid/prebid-server/v4/config"
+	"github.com/prebid/prebid-server/v4/logger"
+	"github.com/prebid/prebid-server/v4/util/jsonutil"
+)
+
+const (
+	// dropLogInterval limits the logging of dropped captures to one in every interval.
+	dropLogInterval = 1000
+	fileNameFormat  = "capture-20060102T150405.000000000.ndjson"
+	bytesPerMB      = 1024 * 1024
+)
+
+// writer appends the captured auctions to NDJSON files in the background, starting a new file once the
+// current one reaches the maximum size. Captures are dropped rather than blocking the auctions when the
+// writer falls behind.
+type writer struct {
+	directory    string
+	maxFileSize  int64
+	samplingRate float64
+
+	// closeMutex prevents captures from being queued once the writer is closed
+	closeMutex sync.RWMutex
+	closed     bool
+	records    chan []byte
+	done       chan struct{}
+	dropped    atomic.Int64
+
+	file   *os.File
+	buffer *bufio.Writer
+	size   int64
+}
+
+// NewWriter registers the writer of the captured auctions described by the configuration. The returned
+// function writes the pending captures and closes the current file. Nothing is registered if capturing is
+// disabled.
+func NewWriter(cfg config.Capture) (func(), error) {
+	if !cfg.Enabled {
+		return func() {}, nil
+	}
+
+	w, err := newWriter(cfg)
+	if err != nil {
+		return nil, err
+	}
+	go w.run()
+	activeWriter.Store(w)
+
+	return func() {
+		activeWriter.CompareAndSwap(w, nil)
+		w.close()
+	}, nil
+}
+
+func newWriter(cfg config.Capture) (*writer, error) {
+	if err := os.MkdirAll(cfg.Directory, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create the capture directory: %v", err)
+	}
+	return &writer{
+		directory:    cfg.Directory,
+		maxFileSize:  int64(cfg.MaxFileSizeMB) * bytesPerMB,
+		samplingRate: cfg.SamplingRate,
+		records:      make(chan []byte, cfg.BufferSize),
+		done:         make(chan struct{}),
+	}, nil
+}
+
+// write queues the record for writing. It never blocks.
+func (w *writer) write(record *Record) {
+	line, err := jsonutil.Marshal(record)
+	if err != nil {
+		logger.Errorf("Failed to serialize the capture: %v", err)
+		return
+	}
+
+	w.closeMutex.RLock()
+	defer w.closeMutex.RUnlock()
+	if w.closed {
+		return
+	}
+
+	select {
+	case w.records <- line:
+	default:
+		if dropped := w.dropped.Add(1); dropped%dropLogInterval == 1 {
+			logger.Warnf("Dropped auction capture, %d captures dropped in total: the capture buffer is full", dropped)
+		}
+	}
+}
+
+func (w *writer) run() {
+	defer close(w.done)
+	for line := range w.records {
+		if err := w.writeLine(line); err != nil {
+			logger.Errorf("Failed to write the auction capture: %v", err)
+		}
+		// flush once the queue is drained, so bursts are written together
+		if len(w.records) == 0 && w.buffer != nil {
+			if err := w.buffer.Flush(); err != nil {
+				logger.Errorf("Failed to write the auction capture: %v", err)
+			}
+		}
+	}
+	w.closeFile()
+}
+
+func (w *writer) writeLine(line []byte) error {
+	lineSize := int64(len(line)) + 1
+	if w.file != nil && w.size+lineSize > w.maxFileSize {
+		w.closeFile()
+	}
+	if w.file == nil {
+		if err := w.openFile(); err != nil {
+			return err
+		}
+	}
+
+	if _, err := w.buffer.Write(line); err != nil {
+		return err
+	}
+	if err := w.buffer.WriteByte('\n'); err != nil {
+		return err
+	}
+	w.size += lineSize
+	return nil
+}
+
+func (w *writer) openFile() error {
+	name := filepath.Join(w.directory, time.Now().UTC().Format(fileNameFormat))
+	file, err := os.OpenFile(name, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return err
+	}
+	w.file = file
+	w.buffer = bufio.NewWriter(file)
+	w.size = 0
+	return nil
+}
+
+func (w *writer) closeFile() {
+	if w.file == nil {
+		return
+	}
+	if err := w.buffer.Flush(); err != nil {
+		logger.Errorf("Failed to write the auction capture: %v", err)
+	}
+	if err := w.file.Close(); err != nil {
+		logger.Errorf("Failed to close the capture file %s: %v", w.file.Name(), err)
+	}
+	w.file = nil
+	w.buffer = nil
+}
+
+// close writes the queued captures and closes the current file.
+func (w *writer) close() {
+	w.closeMutex.Lock()
+	w.closed = true
+	close(w.records)
+	w.closeMutex.Unlock()
+	<-w.done
+}
@@ -0,0 +1,87 @@
+package capture
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/prebid/prebid-server/v4/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewWriterDisabled(t *testing.T) {
+	shutdown, err := NewWriter(config.Capture{Enabled: false})
+
+	require.NoError(t, err)
+	assert.Nil(t, activeWriter.Load())
+	assert.NotPanics(t, shutdown)
+}
+
+func TestWriterRotatesFiles(t *testing.T) {
+	directory := t.TempDir()
+	w, err := newWriter(config.Capture{Directory: directory, BufferSize: 10})
+	require.NoError(t, err)
+	// room for two records per file
+	w.maxFileSize = 250
+	go w.run()
+
+	for i := 0; i < 3; i++ {
+		w.write(&Record{Version: RecordVersion, Endpoint: EndpointAuction})
+	}
+	w.close()
+
+	files, err := filepath.Glob(filepath.Join(directory, "*.ndjson"))
+	require.NoError(t, err)
+	assert.Len(t, files, 2)
+	assert.Len(t, readRecords(t, directory), 3)
+}
+
+func TestWriterDropsRecordsWhenBufferIsFull(t *testing.T) {
+	directory := t.TempDir()
+	w, err := newWriter(config.Capture{Directory: directory, MaxFileSizeMB: 1, BufferSize: 1})
+	require.NoError(t, err)
+
+	// the writer isn't running, so the second record doesn't fit in the buffer
+	w.write(&Record{Version: RecordVersion})
+	w.write(&Record{Version: RecordVersion})
+	assert.Equal(t, int64(1), w.dropped.Load())
+
+	go w.run()
+	w.close()
+	assert.Len(t, readRecords(t, directory), 1)
+}
+
+func TestWriterIgnoresRecordsOnceClosed(t *testing.T) {
+	directory := t.TempDir()
+	w, err := newWriter(config.Capture{Directory: directory, MaxFileSizeMB: 1, BufferSize: 1})
+	require.NoError(t, err)
+	go w.run()
+	w.close()
+
+	assert.NotPanics(t, func() { w.write(&Record{Version: RecordVersion}) })
+
+	entries, err := os.ReadDir(directory)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestWriterRestrictsPermissions(t *testing.T) {
+	directory := filepath.Join(t.TempDir(), "capture")
+	w, err := newWriter(config.Capture{Directory: directory, MaxFileSizeMB: 1, BufferSize: 1})
+	require.NoError(t, err)
+	go w.run()
+	w.write(&Record{Version: RecordVersion})
+	w.close()
+
+	info, err := os.Stat(directory)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0700), info.Mode().Perm())
+
+	files, err := filepath.Glob(filepath.Join(directory, "*.ndjson"))
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	info, err = os.Stat(files[0])
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+}
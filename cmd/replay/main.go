@@ -0,0 +1,162 @@
+// Command replay runs auctions captured by Prebid Server again against the current build, answering the
+// bidder requests with the recorded bidder responses, and prints how the responses differ from the
+// captured ones.
+//
+// Usage:
+//
+//	go run ./cmd/replay [-config pbs.yaml] [-ignore path,...] capture.ndjson...
+//
+// It must be run from the root of the repository, like Prebid Server itself, so the bidder configurations
+// can be found. The exit status is 1 if any response differs or any capture couldn't be replayed.
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/prebid/prebid-server/v4/capture"
+	"github.com/prebid/prebid-server/v4/capture/replay"
+	"github.com/prebid/prebid-server/v4/config"
+	"github.com/prebid/prebid-server/v4/openrtb_ext"
+	"github.com/prebid/prebid-server/v4/util/jsonutil"
+	"github.com/spf13/viper"
+)
+
+const infoDirectory = "./static/bidder-info"
+
+func init() {
+	jsoniter.RegisterExtension(&jsonutil.RawMessageExtension{})
+}
+
+type summary struct {
+	replayed int
+	differed int
+	failed   int
+}
+
+func main() {
+	configFile := flag.String("config", "", "Prebid Server configuration file used to build the exchange. Defaults to pbs.yaml in the working directory, if any.")
+	ignore := flag.String("ignore", "", "Comma separated response paths to leave out of the comparison, in addition to the default ones.")
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		fmt.Fprintln(os.Stderr, "usage: replay [-config pbs.yaml] [-ignore path,...] capture.ndjson...")
+		os.Exit(2)
+	}
+
+	cfg, err := loadConfig(*configFile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Configuration could not be loaded or did not pass validation: %v\n", err)
+		os.Exit(2)
+	}
+
+	ignoredPaths := slices.Clone(replay.DefaultIgnoredPaths)
+	if *ignore != "" {
+		ignoredPaths = append(ignoredPaths, strings.Split(*ignore, ",")...)
+	}
+	replayer, err := replay.NewReplayer(cfg, ignoredPaths)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to build the exchange: %v\n", err)
+		os.Exit(2)
+	}
+	defer replayer.Shutdown()
+
+	var s summary
+	for _, file := range flag.Args() {
+		if err := replayFile(replayer, file, os.Stdout, &s); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to read %s: %v\n", file, err)
+			s.failed++
+		}
+	}
+
+	fmt.Printf("%d auctions replayed, %d differed, %d failed\n", s.replayed, s.differed, s.failed)
+	if s.differed > 0 || s.failed > 0 {
+		os.Exit(1)
+	}
+}
+
+func loadConfig(configFile string) (*config.Configuration, error) {
+	bidderInfoPath, err := filepath.Abs(infoDirectory)
+	if err != nil {
+		return nil, err
+	}
+	bidderInfos, err := config.LoadBidderInfoFromDisk(bidderInfoPath)
+	if err != nil {
+		return nil, err
+	}
+
+	v := viper.New()
+	configName := "pbs"
+	if configFile != "" {
+		v.SetConfigFile(configFile)
+		configName = ""
+	}
+	config.SetupViper(v, configName, bidderInfos)
+	return config.New(v, bidderInfos, openrtb_ext.NormalizeBidderName)
+}
+
+func replayFile(replayer *replay.Replayer, file string, out io.Writer, s *summary) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	reader := bufio.NewReader(f)
+	for lineNumber := 1; ; lineNumber++ {
+		line, err := reader.ReadBytes('\n')
+		if len(bytes.TrimSpace(line)) > 0 {
+			replayLine(replayer, fmt.Sprintf("%s:%d", file, lineNumber), line, out, s)
+		}
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func replayLine(replayer *replay.Replayer, location string, line []byte, out io.Writer, s *summary) {
+	var record capture.Record
+	if err := jsonutil.UnmarshalValid(line, &record); err != nil {
+		fmt.Fprintf(out, "%s: invalid capture: %v\n", location, err)
+		s.failed++
+		return
+	}
+
+	result, err := replayer.Replay(&record)
+	if err != nil {
+		fmt.Fprintf(out, "%s: replay failed: %v\n", location, err)
+		s.failed++
+		return
+	}
+	s.replayed++
+
+	description := fmt.Sprintf("%s: auction %s of account %s captured at %s", location, result.RequestID, result.AccountID, result.Timestamp.Format("2006-01-02T15:04:05Z07:00"))
+	for _, request := range result.UnmatchedBidderRequests {
+		fmt.Fprintf(out, "%s: no recorded response for %s\n", description, request)
+	}
+	if result.Equal() {
+		fmt.Fprintf(out, "%s: identical\n", description)
+		return
+	}
+
+	s.differed++
+	fmt.Fprintf(out, "%s: differs\n", description)
+	if result.CapturedStatus != result.ReplayedStatus {
+		fmt.Fprintf(out, "status %d replayed as %d\n", result.CapturedStatus, result.ReplayedStatus)
+	}
+	if result.Diff != "" {
+		fmt.Fprintln(out, result.Diff)
+	}
+}
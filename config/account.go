@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	"github.com/prebid/go-gdpr/consentconstants"
+	"github.com/prebid/openrtb/v20/adcom1"
 	"github.com/prebid/prebid-server/v4/openrtb_ext"
 	"github.com/prebid/prebid-server/v4/util/iputil"
 )
@@ -54,16 +55,34 @@ type Account struct {
 	Privacy                 AccountPrivacy                              `mapstructure:"privacy" json:"privacy"`
 	PreferredMediaType      openrtb_ext.PreferredMediaType              `mapstructure:"preferredmediatype" json:"preferredmediatype"`
 	TargetingPrefix         string                                      `mapstructure:"targeting_prefix" json:"targeting_prefix"`
+	Tracing                 AccountTracing                              `mapstructure:"tracing" json:"tracing"`
+	Capture                 AccountCapture                              `mapstructure:"capture" json:"capture"`
+	// SecondaryBidders are the bidders the auction doesn't wait for once all other bidders responded
+	SecondaryBidders []string `mapstructure:"secondary_bidders" json:"secondary_bidders"`
+	// HostEIDs are the UIDs of the uids cookie shared with every bidder as user.eids
+	HostEIDs []AccountHostEID `mapstructure:"host_eids" json:"host_eids"`
+}
+
+// AccountHostEID maps a UID of the uids cookie to the OpenRTB EID it's sent as.
+type AccountHostEID struct {
+	// Key is the syncer key of the UID, such as the host cookie family.
+	Key    string `mapstructure:"key" json:"key"`
+	Source string `mapstructure:"source" json:"source"`
+	// AType is the agent type of the UID, which defaults to a web cookie.
+	AType adcom1.AgentType `mapstructure:"atype" json:"atype"`
 }
 
 // AccountCookieSync represents the account-level defaults for the cookie sync endpoint.
 type AccountCookieSync struct {
-	DefaultLimit          *int       `mapstructure:"default_limit" json:"default_limit"`
-	MaxLimit              *int       `mapstructure:"max_limit" json:"max_limit"`
-	DefaultCoopSync       *bool      `mapstructure:"default_coop_sync" json:"default_coop_sync"`
-	PriorityGroups        [][]string `mapstructure:"priority_groups" json:"priority_groups"`
-	PriorityGroupsOnly    *bool      `mapstructure:"priority_groups_only" json:"priority_groups_only"`
-	DisabledIFrameBidders []string   `mapstructure:"disabled_iframe_bidders" json:"disabled_iframe_bidders"`
+	DefaultLimit          *int           `mapstructure:"default_limit" json:"default_limit"`
+	MaxLimit              *int           `mapstructure:"max_limit" json:"max_limit"`
+	DefaultCoopSync       *bool          `mapstructure:"default_coop_sync" json:"default_coop_sync"`
+	PriorityGroups        [][]string     `mapstructure:"priority_groups" json:"priority_groups"`
+	PriorityGroupsOnly    *bool          `mapstructure:"priority_groups_only" json:"priority_groups_only"`
+	DisabledIFrameBidders []string       `mapstructure:"disabled_iframe_bidders" json:"disabled_iframe_bidders"`
+	CooldownSec           *int           `mapstructure:"cooldown_sec" json:"cooldown_sec"`
+	MaxDailySyncs         *int           `mapstructure:"max_daily_syncs" json:"max_daily_syncs"`
+	BidderMaxDailySyncs   map[string]int `mapstructure:"bidder_max_daily_syncs" json:"bidder_max_daily_syncs"`
 }
 
 // AccountCCPA represents account-specific CCPA configuration
@@ -81,6 +100,9 @@ type AccountPriceFloors struct {
 	MaxRule                int               `mapstructure:"max_rules" json:"max_rules"`
 	MaxSchemaDims          int               `mapstructure:"max_schema_dims" json:"max_schema_dims"`
 	Fetcher                AccountFloorFetch `mapstructure:"fetch" json:"fetch"`
+	// BidderAdjustments multiplies the floors signaled to and enforced for each bidder, for example to
+	// shade them or to account for a revenue share
+	BidderAdjustments map[string]float64 `mapstructure:"bidder_adjustments" json:"bidder_adjustments"`
 }
 
 // AccountFloorFetch defines the configuration for dynamic floors fetching.
@@ -94,6 +116,8 @@ type AccountFloorFetch struct {
 	Period        int    `mapstructure:"period_sec" json:"period_sec"`
 	MaxSchemaDims int    `mapstructure:"max_schema_dims" json:"max_schema_dims"`
 	AccountID     string `mapstructure:"accountID" json:"accountID"`
+	// StoredID is the ID of the floor rule set to load from the stored_floors section, in place of the URL
+	StoredID string `mapstructure:"stored_id" json:"stored_id"`
 }
 
 func (pf *AccountPriceFloors) validate(errs []error) []error {
@@ -137,6 +161,20 @@ func (pf *AccountPriceFloors) validate(errs []error) []error {
 		errs = append(errs, fmt.Errorf(`account_defaults.price_floors.fetch.max_schema_dims should not be less than 0 and greater than 20`))
 	}
 
+	for bidder, adjustment := range pf.BidderAdjustments {
+		if adjustment <= 0 {
+			errs = append(errs, fmt.Errorf(`account_defaults.price_floors.bidder_adjustments.%s should be greater than 0`, bidder))
+		}
+	}
+
+	return errs
+}
+
+func (cs *AccountCookieSync) validate(errs []error) []error {
+	if cs.CooldownSec != nil && *cs.CooldownSec < 0 {
+		errs = append(errs, fmt.Errorf(`account_defaults.cookie_sync.cooldown_sec should be greater than or equal to 0`))
+	}
+
 	return errs
 }
 
@@ -144,6 +182,16 @@ func (pf *AccountPriceFloors) IsAdjustForBidAdjustmentEnabled() bool {
 	return pf.AdjustForBidAdjustment
 }
 
+// GetBidderAdjustment returns the floor multiplier of the first bidder with a bidder adjustment, or 1 if none of them has one
+func (pf *AccountPriceFloors) GetBidderAdjustment(bidders ...string) float64 {
+	for _, bidder := range bidders {
+		if adjustment, ok := pf.BidderAdjustments[bidder]; ok && adjustment > 0 {
+			return adjustment
+		}
+	}
+	return 1.0
+}
+
 // EnabledForChannelType indicates whether CCPA is turned on at the account level for the specified channel type
 // by using the channel type setting if defined or the general CCPA setting if defined; otherwise it returns nil
 func (a *AccountCCPA) EnabledForChannelType(channelType ChannelType) *bool {
@@ -355,6 +403,15 @@ type AccountPrivacy struct {
 	IPv6Config      IPv6             `mapstructure:"ipv6" json:"ipv6"`
 	IPv4Config      IPv4             `mapstructure:"ipv4" json:"ipv4"`
 	PrivacySandbox  PrivacySandbox   `mapstructure:"privacysandbox" json:"privacysandbox"`
+	Modules         []PrivacyModule  `mapstructure:"modules" json:"modules"`
+}
+
+// PrivacyModule configures a privacy regulation module which activity rules delegate to by listing
+// its code in their privacyreg field.
+type PrivacyModule struct {
+	Code     string `mapstructure:"code" json:"code"`
+	Enabled  *bool  `mapstructure:"enabled" json:"enabled"`
+	SkipSIDs []int8 `mapstructure:"skip_sids" json:"skip_sids"`
 }
 
 type PrivacySandbox struct {
@@ -7,6 +7,7 @@ import (
 
 	"github.com/prebid/go-gdpr/consentconstants"
 	"github.com/prebid/prebid-server/v4/openrtb_ext"
+	"github.com/prebid/prebid-server/v4/util/ptrutil"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -956,6 +957,21 @@ func TestAccountPriceFloorsValidate(t *testing.T) {
 			},
 			want: []error{errors.New("account_defaults.price_floors.fetch.max_schema_dims should not be less than 0 and greater than 20")},
 		},
+		{
+			description: "Invalid bidder_adjustments",
+			pf: &AccountPriceFloors{
+				BidderAdjustments: map[string]float64{"appnexus": 0.8, "rubicon": 0, "pubmatic": -1},
+				Fetcher: AccountFloorFetch{
+					Period:  300,
+					MaxAge:  600,
+					Timeout: 12,
+				},
+			},
+			want: []error{
+				errors.New("account_defaults.price_floors.bidder_adjustments.rubicon should be greater than 0"),
+				errors.New("account_defaults.price_floors.bidder_adjustments.pubmatic should be greater than 0"),
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.description, func(t *testing.T) {
@@ -966,6 +982,76 @@ func TestAccountPriceFloorsValidate(t *testing.T) {
 	}
 }
 
+func TestAccountCookieSyncValidate(t *testing.T) {
+	tests := []struct {
+		description string
+		cs          *AccountCookieSync
+		want        []error
+	}{
+		{
+			description: "cooldown_sec_not_set",
+			cs:          &AccountCookieSync{},
+		},
+		{
+			description: "cooldown_sec_zero",
+			cs:          &AccountCookieSync{CooldownSec: ptrutil.ToPtr(0)},
+		},
+		{
+			description: "cooldown_sec_positive",
+			cs:          &AccountCookieSync{CooldownSec: ptrutil.ToPtr(3600)},
+		},
+		{
+			description: "cooldown_sec_negative",
+			cs:          &AccountCookieSync{CooldownSec: ptrutil.ToPtr(-1)},
+			want:        []error{errors.New("account_defaults.cookie_sync.cooldown_sec should be greater than or equal to 0")},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			var errs []error
+			got := tt.cs.validate(errs)
+			assert.ElementsMatch(t, got, tt.want)
+		})
+	}
+}
+
+func TestAccountPriceFloorsGetBidderAdjustment(t *testing.T) {
+	pf := AccountPriceFloors{
+		BidderAdjustments: map[string]float64{"appnexus": 0.8, "groupm": 1.25},
+	}
+
+	tests := []struct {
+		description string
+		bidders     []string
+		want        float64
+	}{
+		{
+			description: "bidder_with_adjustment",
+			bidders:     []string{"appnexus"},
+			want:        0.8,
+		},
+		{
+			description: "bidder_without_adjustment",
+			bidders:     []string{"rubicon"},
+			want:        1.0,
+		},
+		{
+			description: "first_bidder_with_adjustment",
+			bidders:     []string{"rubicon", "groupm", "appnexus"},
+			want:        1.25,
+		},
+		{
+			description: "no_bidder",
+			want:        1.0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			assert.Equal(t, tt.want, pf.GetBidderAdjustment(tt.bidders...))
+		})
+	}
+}
+
 func TestIPMaskingValidate(t *testing.T) {
 	tests := []struct {
 		name    string
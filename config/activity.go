@@ -17,8 +17,9 @@ type Activity struct {
 }
 
 type ActivityRule struct {
-	Condition ActivityCondition `mapstructure:"condition" json:"condition"`
-	Allow     bool              `mapstructure:"allow" json:"allow"`
+	Condition  ActivityCondition `mapstructure:"condition" json:"condition"`
+	Allow      bool              `mapstructure:"allow" json:"allow"`
+	PrivacyReg []string          `mapstructure:"privacyreg" json:"privacyreg"`
 }
 
 type ActivityCondition struct {
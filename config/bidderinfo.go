@@ -11,6 +11,7 @@ import (
 	"github.com/prebid/prebid-server/v4/logger"
 	"github.com/prebid/prebid-server/v4/macros"
 	"github.com/prebid/prebid-server/v4/openrtb_ext"
+	"github.com/prebid/prebid-server/v4/util/httputil"
 	"github.com/prebid/prebid-server/v4/util/ptrutil"
 	"github.com/prebid/prebid-server/v4/util/sliceutil"
 
@@ -135,6 +136,9 @@ type Syncer struct {
 
 	// SkipWhen allows bidders to specify when they don't want to sync
 	SkipWhen *SkipWhen `yaml:"skipwhen" mapstructure:"skipwhen"`
+
+	// EIDSource is the domain used as the source of the OpenRTB EID built from the synced user id.
+	EIDSource string `yaml:"eidSource" mapstructure:"eid_source"`
 }
 
 func (s *Syncer) Equal(other *Syncer) bool {
@@ -153,7 +157,8 @@ func (s *Syncer) Equal(other *Syncer) bool {
 		s.ExternalURL == other.ExternalURL &&
 		s.FormatOverride == other.FormatOverride &&
 		ptrutil.Equal(s.Enabled, other.Enabled) &&
-		s.SkipWhen.Equal(other.SkipWhen)
+		s.SkipWhen.Equal(other.SkipWhen) &&
+		s.EIDSource == other.EIDSource
 }
 
 type SkipWhen struct {
@@ -250,7 +255,7 @@ func (bi BidderInfo) IsEnabled() bool {
 	return !bi.WhiteLabelOnly && !bi.Disabled
 }
 
-// Defined returns true if at least one field exists, except for the supports field.
+// Defined returns true if at least one field exists, except for the supports and eid source fields.
 func (s *Syncer) Defined() bool {
 	if s == nil {
 		return false
@@ -529,6 +534,9 @@ func validateInfo(bidder BidderInfo, infos BidderInfos, bidderName string) error
 	if err := validateCapabilities(bidder.Capabilities, bidderName); err != nil {
 		return err
 	}
+	if err := validateEndpointCompression(bidder.EndpointCompression, bidderName); err != nil {
+		return err
+	}
 	if len(bidder.AliasOf) > 0 {
 		if err := validateAliasCapabilities(bidder, infos, bidderName); err != nil {
 			return err
@@ -537,6 +545,14 @@ func validateInfo(bidder BidderInfo, infos BidderInfos, bidderName string) error
 	return nil
 }
 
+func validateEndpointCompression(endpointCompression string, bidderName string) error {
+	switch httputil.ContentEncoding(endpointCompression).Normalize() {
+	case "", httputil.ContentEncodingGZIP, httputil.ContentEncodingZSTD:
+		return nil
+	}
+	return fmt.Errorf("invalid endpointCompression '%s' for adapter: %s, must be gzip or zstd", endpointCompression, bidderName)
+}
+
 func validateMaintainer(info *MaintainerInfo, bidderName string) error {
 	if info == nil || info.Email == "" {
 		return fmt.Errorf("missing required field: maintainer.email for adapter: %s", bidderName)
@@ -804,6 +820,10 @@ func (s *Syncer) Override(original *Syncer) *Syncer {
 		copy.Enabled = s.Enabled
 	}
 
+	if s.EIDSource != "" {
+		copy.EIDSource = s.EIDSource
+	}
+
 	return &copy
 }
 
@@ -588,8 +588,9 @@ func TestBidderInfoFiles(t *testing.T) {
 func TestBidderInfoValidationPositive(t *testing.T) {
 	bidderInfos := BidderInfos{
 		"bidderA": BidderInfo{
-			Endpoint:   "http://bidderA.com/openrtb2",
-			PlatformID: "A",
+			Endpoint:            "http://bidderA.com/openrtb2",
+			EndpointCompression: "ZSTD",
+			PlatformID:          "A",
 			Maintainer: &MaintainerInfo{
 				Email: "maintainer@bidderA.com",
 			},
@@ -612,8 +613,9 @@ func TestBidderInfoValidationPositive(t *testing.T) {
 			},
 		},
 		"bidderB": BidderInfo{
-			Endpoint:   "http://bidderB.com/openrtb2",
-			PlatformID: "B",
+			Endpoint:            "http://bidderB.com/openrtb2",
+			EndpointCompression: "gzip",
+			PlatformID:          "B",
 			Maintainer: &MaintainerInfo{
 				Email: "maintainer@bidderB.com",
 			},
@@ -733,6 +735,28 @@ func TestBidderInfoValidationNegative(t *testing.T) {
 		bidderInfos  BidderInfos
 		expectErrors []error
 	}{
+		{
+			"One bidder incorrect endpoint compression",
+			BidderInfos{
+				"bidderA": BidderInfo{
+					Endpoint:            "http://bidderA.com/openrtb2",
+					EndpointCompression: "deflate",
+					Maintainer: &MaintainerInfo{
+						Email: "maintainer@bidderA.com",
+					},
+					Capabilities: &CapabilitiesInfo{
+						App: &PlatformInfo{
+							MediaTypes: []openrtb_ext.BidType{
+								openrtb_ext.BidTypeVideo,
+							},
+						},
+					},
+				},
+			},
+			[]error{
+				errors.New("invalid endpointCompression 'deflate' for adapter: bidderA, must be gzip or zstd"),
+			},
+		},
 		{
 			"One bidder incorrect url",
 			BidderInfos{
@@ -1467,6 +1491,12 @@ func TestSyncerOverride(t *testing.T) {
 			givenOverride: &Syncer{ExternalURL: "overrideExternalURL"},
 			expected:      &Syncer{Key: "originalKey", ExternalURL: "overrideExternalURL"},
 		},
+		{
+			description:   "Override EIDSource",
+			givenOriginal: &Syncer{Key: "originalKey", EIDSource: "original.com"},
+			givenOverride: &Syncer{EIDSource: "override.com"},
+			expected:      &Syncer{Key: "originalKey", EIDSource: "override.com"},
+		},
 		{
 			description:   "Override Enabled - True To False",
 			givenOriginal: &Syncer{Key: "originalKey", Enabled: ptrutil.ToPtr(true)},
@@ -1767,6 +1797,12 @@ func TestSyncerEqual(t *testing.T) {
 			},
 			expected: false,
 		},
+		{
+			name:     "different-eid-source",
+			syncer1:  &Syncer{Key: "key", EIDSource: "a.com"},
+			syncer2:  &Syncer{Key: "key", EIDSource: "b.com"},
+			expected: false,
+		},
 		{
 			name: "same-complete",
 			syncer1: &Syncer{
@@ -2081,6 +2117,11 @@ func TestSyncerDefined(t *testing.T) {
 			givenSyncer: &Syncer{SkipWhen: &SkipWhen{}},
 			expected:    true,
 		},
+		{
+			name:        "eidsource-only",
+			givenSyncer: &Syncer{EIDSource: "anySource"},
+			expected:    false,
+		},
 		{
 			name:        "supports-only",
 			givenSyncer: &Syncer{Supports: []string{"anySupports"}},
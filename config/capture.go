@@ -0,0 +1,51 @@
+package config
+
+import (
+	"fmt"
+)
+
+// Capture configures the recording of sampled auctions to NDJSON files, which can be replayed offline with
+// cmd/replay.
+type Capture struct {
+	Enabled bool `mapstructure:"enabled"`
+	// Directory is where the capture files are written.
+	Directory string `mapstructure:"directory"`
+	// SamplingRate is the ratio of auctions captured for accounts which don't define their own sampling rate.
+	SamplingRate float64 `mapstructure:"sampling_rate"`
+	// MaxFileSizeMB is the size after which the current capture file is closed and a new one is started.
+	MaxFileSizeMB int `mapstructure:"max_file_size_mb"`
+	// BufferSize is the number of captured auctions waiting to be written before new ones are dropped.
+	BufferSize int `mapstructure:"buffer_size"`
+}
+
+// AccountCapture represents account-specific auction capture configuration.
+type AccountCapture struct {
+	// SamplingRate overrides the host sampling rate for auctions of the account.
+	SamplingRate *float64 `mapstructure:"sampling_rate" json:"sampling_rate,omitempty"`
+}
+
+func (cfg *Capture) validate(errs []error) []error {
+	if !cfg.Enabled {
+		return errs
+	}
+	if cfg.Directory == "" {
+		errs = append(errs, fmt.Errorf("capture.directory must be specified when capture is enabled"))
+	}
+	if cfg.SamplingRate < 0 || cfg.SamplingRate > 1 {
+		errs = append(errs, fmt.Errorf("capture.sampling_rate must be >= 0 and <= 1. Got %f", cfg.SamplingRate))
+	}
+	if cfg.MaxFileSizeMB <= 0 {
+		errs = append(errs, fmt.Errorf("capture.max_file_size_mb must be > 0. Got %d", cfg.MaxFileSizeMB))
+	}
+	if cfg.BufferSize <= 0 {
+		errs = append(errs, fmt.Errorf("capture.buffer_size must be > 0. Got %d", cfg.BufferSize))
+	}
+	return errs
+}
+
+func (cfg *AccountCapture) validate(errs []error) []error {
+	if cfg.SamplingRate != nil && (*cfg.SamplingRate < 0 || *cfg.SamplingRate > 1) {
+		errs = append(errs, fmt.Errorf("account_defaults.capture.sampling_rate must be >= 0 and <= 1. Got %f", *cfg.SamplingRate))
+	}
+	return errs
+}
@@ -0,0 +1,89 @@
+package config
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/prebid/prebid-server/v4/util/ptrutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateCapture(t *testing.T) {
+	testCases := []struct {
+		description  string
+		capture      Capture
+		expectedErrs []error
+	}{
+		{
+			description:  "disabled",
+			capture:      Capture{Enabled: false, SamplingRate: 2},
+			expectedErrs: nil,
+		},
+		{
+			description: "valid",
+			capture: Capture{
+				Enabled:       true,
+				Directory:     "/var/log/pbs/capture",
+				SamplingRate:  0.01,
+				MaxFileSizeMB: 100,
+				BufferSize:    1000,
+			},
+			expectedErrs: nil,
+		},
+		{
+			description: "invalid",
+			capture: Capture{
+				Enabled:       true,
+				SamplingRate:  1.5,
+				MaxFileSizeMB: 0,
+				BufferSize:    -1,
+			},
+			expectedErrs: []error{
+				errors.New("capture.directory must be specified when capture is enabled"),
+				errors.New("capture.sampling_rate must be >= 0 and <= 1. Got 1.500000"),
+				errors.New("capture.max_file_size_mb must be > 0. Got 0"),
+				errors.New("capture.buffer_size must be > 0. Got -1"),
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			errs := test.capture.validate(nil)
+			assert.Equal(t, test.expectedErrs, errs)
+		})
+	}
+}
+
+func TestValidateAccountCapture(t *testing.T) {
+	testCases := []struct {
+		description  string
+		capture      AccountCapture
+		expectedErrs []error
+	}{
+		{
+			description:  "not-set",
+			capture:      AccountCapture{},
+			expectedErrs: nil,
+		},
+		{
+			description:  "valid",
+			capture:      AccountCapture{SamplingRate: ptrutil.ToPtr(0.5)},
+			expectedErrs: nil,
+		},
+		{
+			description: "out-of-range",
+			capture:     AccountCapture{SamplingRate: ptrutil.ToPtr(-0.5)},
+			expectedErrs: []error{
+				errors.New("account_defaults.capture.sampling_rate must be >= 0 and <= 1. Got -0.500000"),
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			errs := test.capture.validate(nil)
+			assert.Equal(t, test.expectedErrs, errs)
+		})
+	}
+}
@@ -9,13 +9,35 @@ type Compression struct {
 
 // CompressionInfo defines what types of compression algorithms are supported.
 type CompressionInfo struct {
-	GZIP bool `mapstructure:"enable_gzip"`
+	GZIP   bool `mapstructure:"enable_gzip"`
+	ZSTD   bool `mapstructure:"enable_zstd"`
+	Brotli bool `mapstructure:"enable_brotli"`
 }
 
 func (cfg *CompressionInfo) IsSupported(contentEncoding httputil.ContentEncoding) bool {
 	switch contentEncoding.Normalize() {
 	case httputil.ContentEncodingGZIP:
 		return cfg.GZIP
+	case httputil.ContentEncodingZSTD:
+		return cfg.ZSTD
+	case httputil.ContentEncodingBrotli:
+		return cfg.Brotli
 	}
 	return false
 }
+
+// Encodings returns the enabled content encodings in the order of preference, which puts the
+// encodings with a better compression ratio for the CPU spent first.
+func (cfg *CompressionInfo) Encodings() []httputil.ContentEncoding {
+	var encodings []httputil.ContentEncoding
+	if cfg.ZSTD {
+		encodings = append(encodings, httputil.ContentEncodingZSTD)
+	}
+	if cfg.Brotli {
+		encodings = append(encodings, httputil.ContentEncodingBrotli)
+	}
+	if cfg.GZIP {
+		encodings = append(encodings, httputil.ContentEncodingGZIP)
+	}
+	return encodings
+}
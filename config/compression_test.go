@@ -38,6 +38,31 @@ func TestReqCompressionCfgIsSupported(t *testing.T) {
 			contentEncoding: httputil.ContentEncodingGZIP,
 			wantSupported:   false,
 		},
+		{
+			description: "Zstd supported",
+			cfg: CompressionInfo{
+				ZSTD: true,
+			},
+			contentEncoding: httputil.ContentEncoding("ZSTD"),
+			wantSupported:   true,
+		},
+		{
+			description: "Brotli supported",
+			cfg: CompressionInfo{
+				Brotli: true,
+			},
+			contentEncoding: httputil.ContentEncodingBrotli,
+			wantSupported:   true,
+		},
+		{
+			description: "Brotli not enabled",
+			cfg: CompressionInfo{
+				GZIP: true,
+				ZSTD: true,
+			},
+			contentEncoding: httputil.ContentEncodingBrotli,
+			wantSupported:   false,
+		},
 	}
 
 	for _, test := range testCases {
@@ -45,3 +70,31 @@ func TestReqCompressionCfgIsSupported(t *testing.T) {
 		assert.Equal(t, got, test.wantSupported, test.description)
 	}
 }
+
+func TestCompressionCfgEncodings(t *testing.T) {
+	testCases := []struct {
+		description       string
+		cfg               CompressionInfo
+		expectedEncodings []httputil.ContentEncoding
+	}{
+		{
+			description:       "None enabled",
+			cfg:               CompressionInfo{},
+			expectedEncodings: nil,
+		},
+		{
+			description:       "Gzip only",
+			cfg:               CompressionInfo{GZIP: true},
+			expectedEncodings: []httputil.ContentEncoding{httputil.ContentEncodingGZIP},
+		},
+		{
+			description:       "All enabled in order of preference",
+			cfg:               CompressionInfo{GZIP: true, ZSTD: true, Brotli: true},
+			expectedEncodings: []httputil.ContentEncoding{httputil.ContentEncodingZSTD, httputil.ContentEncodingBrotli, httputil.ContentEncodingGZIP},
+		},
+	}
+
+	for _, test := range testCases {
+		assert.Equal(t, test.expectedEncodings, test.cfg.Encodings(), test.description)
+	}
+}
@@ -45,6 +45,8 @@ type Configuration struct {
 	RecaptchaSecret   string          `mapstructure:"recaptcha_secret"`
 	HostCookie        HostCookie      `mapstructure:"host_cookie"`
 	Metrics           Metrics         `mapstructure:"metrics"`
+	Tracing           Tracing         `mapstructure:"tracing"`
+	Capture           Capture         `mapstructure:"capture"`
 	StoredRequests    StoredRequests  `mapstructure:"stored_requests"`
 	StoredRequestsAMP StoredRequests  `mapstructure:"stored_amp_req"`
 	CategoryMapping   StoredRequests  `mapstructure:"category_mapping"`
@@ -59,6 +61,8 @@ type Configuration struct {
 	// Note that StoredVideo refers to stored video requests, and has nothing to do with caching video creatives.
 	StoredVideo     StoredRequests `mapstructure:"stored_video_req"`
 	StoredResponses StoredRequests `mapstructure:"stored_responses"`
+	// StoredFloors holds the price floor rule sets which the accounts load with price_floors.fetch.stored_id
+	StoredFloors StoredRequests `mapstructure:"stored_floors"`
 	// StoredRequestsTimeout defines the number of milliseconds before a timeout occurs with stored requests fetch
 	StoredRequestsTimeout int `mapstructure:"stored_requests_timeout_ms"`
 
@@ -129,14 +133,15 @@ type PriceFloorFetcher struct {
 const MIN_COOKIE_SIZE_BYTES = 500
 
 type HTTPClient struct {
-	MaxConnsPerHost       int          `mapstructure:"max_connections_per_host"`
-	MaxIdleConns          int          `mapstructure:"max_idle_connections"`
-	MaxIdleConnsPerHost   int          `mapstructure:"max_idle_connections_per_host"`
-	IdleConnTimeout       int          `mapstructure:"idle_connection_timeout_seconds"`
-	TLSHandshakeTimeout   int          `mapstructure:"tls_handshake_timeout_seconds"`
-	ExpectContinueTimeout int          `mapstructure:"expect_continue_timeout_seconds"`
-	Dialer                Dialer       `mapstructure:"dialer"`
-	Throttle              HTTPThrottle `mapstructure:"throttle"`
+	MaxConnsPerHost       int                `mapstructure:"max_connections_per_host"`
+	MaxIdleConns          int                `mapstructure:"max_idle_connections"`
+	MaxIdleConnsPerHost   int                `mapstructure:"max_idle_connections_per_host"`
+	IdleConnTimeout       int                `mapstructure:"idle_connection_timeout_seconds"`
+	TLSHandshakeTimeout   int                `mapstructure:"tls_handshake_timeout_seconds"`
+	ExpectContinueTimeout int                `mapstructure:"expect_continue_timeout_seconds"`
+	Dialer                Dialer             `mapstructure:"dialer"`
+	Throttle              HTTPThrottle       `mapstructure:"throttle"`
+	CircuitBreaker        HTTPCircuitBreaker `mapstructure:"circuit_breaker"`
 }
 
 type HTTPThrottle struct {
@@ -152,6 +157,50 @@ type HTTPThrottle struct {
 	ThrottleWindow int `mapstructure:"throttle_window"`
 }
 
+type HTTPCircuitBreaker struct {
+	// Enables the per bidder circuit breaker
+	Enabled bool `mapstructure:"enabled"`
+	// If enabled, a separate circuit breaker is kept for each endpoint host a bidder sends requests to.
+	PerHost bool `mapstructure:"per_host"`
+	// WindowSize is the number of most recent calls used to compute the error and timeout ratios.
+	WindowSize int `mapstructure:"window_size"`
+	// MinRequests is the number of calls which must be present in the window before the circuit may open.
+	MinRequests int `mapstructure:"min_requests"`
+	// ErrorThreshold is the ratio of failed calls in the window which opens the circuit.
+	ErrorThreshold float64 `mapstructure:"error_threshold"`
+	// TimeoutThreshold is the ratio of timed out calls in the window which opens the circuit.
+	TimeoutThreshold float64 `mapstructure:"timeout_threshold"`
+	// OpenDurationMS is how long the circuit stays open before probe requests are allowed through.
+	OpenDurationMS int `mapstructure:"open_duration_ms"`
+	// HalfOpenProbes is the number of probe requests which must succeed for the circuit to close again.
+	HalfOpenProbes int `mapstructure:"half_open_probes"`
+}
+
+func (cfg *HTTPCircuitBreaker) validate(errs []error) []error {
+	if !cfg.Enabled {
+		return errs
+	}
+	if cfg.WindowSize <= 0 {
+		errs = append(errs, fmt.Errorf("http_client.circuit_breaker.window_size must be > 0. Got %d", cfg.WindowSize))
+	}
+	if cfg.MinRequests <= 0 || cfg.MinRequests > cfg.WindowSize {
+		errs = append(errs, fmt.Errorf("http_client.circuit_breaker.min_requests must be > 0 and <= window_size. Got %d", cfg.MinRequests))
+	}
+	if cfg.ErrorThreshold <= 0 || cfg.ErrorThreshold > 1 {
+		errs = append(errs, fmt.Errorf("http_client.circuit_breaker.error_threshold must be > 0 and <= 1. Got %f", cfg.ErrorThreshold))
+	}
+	if cfg.TimeoutThreshold <= 0 || cfg.TimeoutThreshold > 1 {
+		errs = append(errs, fmt.Errorf("http_client.circuit_breaker.timeout_threshold must be > 0 and <= 1. Got %f", cfg.TimeoutThreshold))
+	}
+	if cfg.OpenDurationMS <= 0 {
+		errs = append(errs, fmt.Errorf("http_client.circuit_breaker.open_duration_ms must be > 0. Got %d", cfg.OpenDurationMS))
+	}
+	if cfg.HalfOpenProbes <= 0 {
+		errs = append(errs, fmt.Errorf("http_client.circuit_breaker.half_open_probes must be > 0. Got %d", cfg.HalfOpenProbes))
+	}
+	return errs
+}
+
 type Dialer struct {
 	TimeoutSeconds   int `mapstructure:"timeout_seconds"`
 	KeepAliveSeconds int `mapstructure:"keep_alive_seconds"`
@@ -168,7 +217,12 @@ func (cfg *Configuration) validate(v *viper.Viper) []error {
 	errs = cfg.Accounts.validate(errs)
 	errs = cfg.CategoryMapping.validate(errs)
 	errs = cfg.StoredVideo.validate(errs)
+	errs = cfg.StoredFloors.validate(errs)
 	errs = cfg.Metrics.validate(errs)
+	errs = cfg.Client.CircuitBreaker.validate(errs)
+	errs = cfg.Tracing.validate(errs)
+	errs = cfg.Capture.validate(errs)
+	errs = cfg.HostCookie.validate(errs)
 	if cfg.MaxRequestSize < 0 {
 		errs = append(errs, fmt.Errorf("cfg.max_request_size must be >= 0. Got %d", cfg.MaxRequestSize))
 	}
@@ -177,6 +231,9 @@ func (cfg *Configuration) validate(v *viper.Viper) []error {
 	errs = cfg.Debug.validate(errs)
 	errs = cfg.ExtCacheURL.validate(errs)
 	errs = cfg.AccountDefaults.PriceFloors.validate(errs)
+	errs = cfg.AccountDefaults.CookieSync.validate(errs)
+	errs = cfg.AccountDefaults.Tracing.validate(errs)
+	errs = cfg.AccountDefaults.Capture.validate(errs)
 	if cfg.AccountDefaults.Disabled {
 		logger.Warnf(`With account_defaults.disabled=true, host-defined accounts must exist and have "disabled":false. All other requests will be rejected.`)
 	}
@@ -479,9 +536,10 @@ type LMT struct {
 }
 
 type Analytics struct {
-	File     FileLogs      `mapstructure:"file"`
-	Agma     AgmaAnalytics `mapstructure:"agma"`
-	Pubstack Pubstack      `mapstructure:"pubstack"`
+	File     FileLogs       `mapstructure:"file"`
+	Agma     AgmaAnalytics  `mapstructure:"agma"`
+	Pubstack Pubstack       `mapstructure:"pubstack"`
+	Kafka    KafkaAnalytics `mapstructure:"kafka"`
 }
 
 type CurrencyConverter struct {
@@ -489,6 +547,28 @@ type CurrencyConverter struct {
 	FetchTimeoutMilliseconds int    `mapstructure:"fetch_timeout_ms"`
 	FetchIntervalSeconds     int    `mapstructure:"fetch_interval_seconds"`
 	StaleRatesSeconds        int    `mapstructure:"stale_rates_seconds"`
+	// Sources are the sources of the rates in priority order. If empty, the rates are fetched from
+	// FetchURL in the Prebid format and are stale after StaleRatesSeconds.
+	Sources []CurrencyRateSource `mapstructure:"sources"`
+}
+
+// The formats of the currency rate sources
+const (
+	CurrencyRateSourcePrebid = "prebid"
+	CurrencyRateSourceECB    = "ecb"
+	CurrencyRateSourceFile   = "file"
+)
+
+type CurrencyRateSource struct {
+	// Type is the format of the source, one of "prebid", "ecb" or "file"
+	Type string `mapstructure:"type"`
+	// URL is the endpoint of the prebid and ecb sources
+	URL string `mapstructure:"url"`
+	// Path is the local file of the file source, in the Prebid format
+	Path string `mapstructure:"path"`
+	// StaleRatesSeconds is the number of seconds after which the rates of the source are stale, if it fails
+	// to be fetched. Values <= 0 mean that the rates never go stale.
+	StaleRatesSeconds int `mapstructure:"stale_rates_seconds"`
 }
 
 func (cfg *CurrencyConverter) validate(errs []error) []error {
@@ -498,9 +578,35 @@ func (cfg *CurrencyConverter) validate(errs []error) []error {
 	if cfg.FetchTimeoutMilliseconds < 0 {
 		errs = append(errs, fmt.Errorf("currency_converter.fetch_timeout_ms must be 0 or greater. Got %d", cfg.FetchTimeoutMilliseconds))
 	}
+	for i, source := range cfg.Sources {
+		switch source.Type {
+		case CurrencyRateSourcePrebid, CurrencyRateSourceECB:
+			if source.URL == "" {
+				errs = append(errs, fmt.Errorf("currency_converter.sources[%d].url must be set for the %s sources", i, source.Type))
+			}
+		case CurrencyRateSourceFile:
+			if source.Path == "" {
+				errs = append(errs, fmt.Errorf("currency_converter.sources[%d].path must be set for the file sources", i))
+			}
+		default:
+			errs = append(errs, fmt.Errorf("currency_converter.sources[%d].type must be one of prebid, ecb or file. Got %s", i, source.Type))
+		}
+	}
 	return errs
 }
 
+// RateSources returns the sources of the rates in priority order, which default to the FetchURL in the Prebid format.
+func (cfg *CurrencyConverter) RateSources() []CurrencyRateSource {
+	if len(cfg.Sources) > 0 {
+		return cfg.Sources
+	}
+	return []CurrencyRateSource{{
+		Type:              CurrencyRateSourcePrebid,
+		URL:               cfg.FetchURL,
+		StaleRatesSeconds: cfg.StaleRatesSeconds,
+	}}
+}
+
 type AgmaAnalytics struct {
 	Enabled  bool                      `mapstructure:"enabled"`
 	Endpoint AgmaAnalyticsHttpEndpoint `mapstructure:"endpoint"`
@@ -526,6 +632,47 @@ type AgmaAnalyticsAccount struct {
 	SiteAppId   string `mapstructure:"site_app_id"`
 }
 
+// KafkaAnalytics configures the analytics module publishing events to Kafka topics.
+type KafkaAnalytics struct {
+	Enabled  bool     `mapstructure:"enabled"`
+	Brokers  []string `mapstructure:"brokers"`
+	ClientID string   `mapstructure:"client_id"`
+	// Topics are the default topics per event type. Events of a type without a topic aren't published.
+	Topics KafkaAnalyticsTopics `mapstructure:"topics"`
+	// Accounts route the events of specific accounts to their own topics.
+	Accounts []KafkaAnalyticsAccount `mapstructure:"accounts"`
+	Producer KafkaAnalyticsProducer  `mapstructure:"producer"`
+}
+
+type KafkaAnalyticsTopics struct {
+	Auction           string `mapstructure:"auction"`
+	Amp               string `mapstructure:"amp"`
+	Video             string `mapstructure:"video"`
+	CookieSync        string `mapstructure:"cookie_sync"`
+	SetUID            string `mapstructure:"setuid"`
+	NotificationEvent string `mapstructure:"notification_event"`
+}
+
+// KafkaAnalyticsAccount overrides the default topics for an account. Event types without a topic
+// fall back to the default topic.
+type KafkaAnalyticsAccount struct {
+	AccountID string               `mapstructure:"account_id"`
+	Topics    KafkaAnalyticsTopics `mapstructure:"topics"`
+}
+
+type KafkaAnalyticsProducer struct {
+	// Acks is the number of acknowledgements required for a write, one of all, leader or none.
+	Acks          string `mapstructure:"acks"`
+	LingerMS      int    `mapstructure:"linger_ms"`
+	BatchMaxBytes int    `mapstructure:"batch_max_bytes"`
+	// MaxBufferedRecords is the number of records buffered before new events are dropped, which
+	// keeps a slow or unavailable Kafka cluster from holding up requests.
+	MaxBufferedRecords int `mapstructure:"max_buffered_records"`
+	Retries            int `mapstructure:"retries"`
+	RetryBackoffMS     int `mapstructure:"retry_backoff_ms"`
+	DeliveryTimeoutMS  int `mapstructure:"delivery_timeout_ms"`
+}
+
 // FileLogs Corresponding config for FileLogger as a PBS Analytics Module
 type FileLogs struct {
 	Filename string `mapstructure:"filename"`
@@ -568,13 +715,33 @@ type HostCookie struct {
 	MaxCookieSizeBytes int    `mapstructure:"max_cookie_size_bytes"`
 	OptOutCookie       Cookie `mapstructure:"optout_cookie"`
 	// Cookie timeout in days
-	TTL int64 `mapstructure:"ttl_days"`
+	TTL      int64         `mapstructure:"ttl_days"`
+	UIDStore UIDStore      `mapstructure:"uid_store"`
+	KeyRing  CookieKeyRing `mapstructure:"key_ring"`
+	// Format of the uids cookie data. Cookies written in either format can always be read.
+	Format string `mapstructure:"format"`
 }
 
+const (
+	// HostCookieFormatJSON writes the uids cookie data as JSON.
+	HostCookieFormatJSON = "json"
+	// HostCookieFormatCompact writes the uids cookie data in a binary format, which fits more UIDs in the cookie.
+	HostCookieFormatCompact = "compact"
+)
+
 func (cfg *HostCookie) TTLDuration() time.Duration {
 	return time.Duration(cfg.TTL) * time.Hour * 24
 }
 
+func (cfg *HostCookie) validate(errs []error) []error {
+	if cfg.Format != "" && cfg.Format != HostCookieFormatJSON && cfg.Format != HostCookieFormatCompact {
+		errs = append(errs, fmt.Errorf("host_cookie.format must be one of json or compact. Got %s", cfg.Format))
+	}
+	errs = cfg.UIDStore.validate(errs)
+	errs = cfg.KeyRing.validate(errs)
+	return errs
+}
+
 type RequestTimeoutHeaders struct {
 	RequestTimeInQueue    string `mapstructure:"request_time_in_queue"`
 	RequestTimeoutInQueue string `mapstructure:"request_timeout_in_queue"`
@@ -583,6 +750,7 @@ type RequestTimeoutHeaders struct {
 type Metrics struct {
 	Influxdb   InfluxMetrics     `mapstructure:"influxdb"`
 	Prometheus PrometheusMetrics `mapstructure:"prometheus"`
+	StatsD     StatsDMetrics     `mapstructure:"statsd"`
 	Disabled   DisabledMetrics   `mapstructure:"disabled_metrics"`
 }
 
@@ -615,7 +783,8 @@ type DisabledMetrics struct {
 }
 
 func (cfg *Metrics) validate(errs []error) []error {
-	return cfg.Prometheus.validate(errs)
+	errs = cfg.Prometheus.validate(errs)
+	return cfg.StatsD.validate(errs)
 }
 
 type InfluxMetrics struct {
@@ -646,6 +815,59 @@ func (m *PrometheusMetrics) Timeout() time.Duration {
 	return time.Duration(m.TimeoutMillisRaw) * time.Millisecond
 }
 
+// StatsDFlavor is the wire format used to send metrics to a StatsD server.
+type StatsDFlavor string
+
+const (
+	// StatsDFlavorStatsD is the plain StatsD format. Tags aren't supported, so tag values are
+	// appended to the metric name instead.
+	StatsDFlavorStatsD StatsDFlavor = "statsd"
+	// StatsDFlavorDogStatsD is the DogStatsD format, which supports tags and histograms.
+	StatsDFlavorDogStatsD StatsDFlavor = "dogstatsd"
+)
+
+type StatsDMetrics struct {
+	// Host is the host:port of the StatsD server. The StatsD metrics engine is disabled if empty.
+	Host      string       `mapstructure:"host"`
+	Namespace string       `mapstructure:"namespace"`
+	Flavor    StatsDFlavor `mapstructure:"flavor"`
+	Tags      StatsDTags   `mapstructure:"tags"`
+	// Aggregation sums counters and packs timer and histogram samples of the same metric
+	// before they are sent, reducing the number of packets sent to the StatsD server.
+	Aggregation     bool `mapstructure:"aggregation"`
+	FlushIntervalMS int  `mapstructure:"flush_interval_ms"`
+	// MaxPacketSize is the maximum size of a UDP packet in bytes.
+	MaxPacketSize int `mapstructure:"max_packet_size"`
+	// BufferSize is the number of metrics buffered before they are sent ahead of the flush interval.
+	BufferSize int `mapstructure:"buffer_size"`
+}
+
+// StatsDTags selects the optional, potentially high cardinality, tags added to StatsD metrics.
+type StatsDTags struct {
+	Adapter bool `mapstructure:"adapter"`
+	Account bool `mapstructure:"account"`
+	Stage   bool `mapstructure:"stage"`
+}
+
+func (cfg *StatsDMetrics) validate(errs []error) []error {
+	if cfg.Host == "" {
+		return errs
+	}
+	if cfg.Flavor != StatsDFlavorStatsD && cfg.Flavor != StatsDFlavorDogStatsD {
+		errs = append(errs, fmt.Errorf("metrics.statsd.flavor must be one of %s or %s. Got %s", StatsDFlavorStatsD, StatsDFlavorDogStatsD, cfg.Flavor))
+	}
+	if cfg.FlushIntervalMS <= 0 {
+		errs = append(errs, fmt.Errorf("metrics.statsd.flush_interval_ms must be positive if metrics.statsd.host is defined. Got %d", cfg.FlushIntervalMS))
+	}
+	if cfg.MaxPacketSize <= 0 {
+		errs = append(errs, fmt.Errorf("metrics.statsd.max_packet_size must be positive if metrics.statsd.host is defined. Got %d", cfg.MaxPacketSize))
+	}
+	if cfg.BufferSize <= 0 {
+		errs = append(errs, fmt.Errorf("metrics.statsd.buffer_size must be positive if metrics.statsd.host is defined. Got %d", cfg.BufferSize))
+	}
+	return errs
+}
+
 // ExternalCache configures the externally accessible cache url.
 type ExternalCache struct {
 	Scheme string `mapstructure:"scheme"`
@@ -975,6 +1197,17 @@ func SetupViper(v *viper.Viper, filename string, bidderInfos BidderInfos) {
 	v.SetDefault("host_cookie.value", "")
 	v.SetDefault("host_cookie.ttl_days", 90)
 	v.SetDefault("host_cookie.max_cookie_size_bytes", 0)
+	v.SetDefault("host_cookie.format", HostCookieFormatJSON)
+	v.SetDefault("host_cookie.uid_store.type", UIDStoreTypeCookie)
+	v.SetDefault("host_cookie.uid_store.redis.address", "")
+	v.SetDefault("host_cookie.uid_store.redis.username", "")
+	v.SetDefault("host_cookie.uid_store.redis.password", "")
+	v.SetDefault("host_cookie.uid_store.redis.db", 0)
+	v.SetDefault("host_cookie.uid_store.redis.key_prefix", "pbs-uids:")
+	v.SetDefault("host_cookie.uid_store.redis.timeout_ms", 50)
+	v.SetDefault("host_cookie.key_ring.active_key_id", "")
+	v.SetDefault("host_cookie.key_ring.keys", []CookieKey{})
+	v.SetDefault("host_cookie.key_ring.accept_legacy", true)
 	v.SetDefault("host_schain_node", nil)
 	v.SetDefault("validations.banner_creative_max_size", ValidationSkip)
 	v.SetDefault("validations.secure_markup", ValidationSkip)
@@ -993,6 +1226,14 @@ func SetupViper(v *viper.Viper, filename string, bidderInfos BidderInfos) {
 	v.SetDefault("http_client.throttle.long_queue_wait_threshold_ms", 50)
 	v.SetDefault("http_client.throttle.short_queue_wait_threshold_ms", 10)
 	v.SetDefault("http_client.throttle.throttle_window", 1000)
+	v.SetDefault("http_client.circuit_breaker.enabled", false)
+	v.SetDefault("http_client.circuit_breaker.per_host", false)
+	v.SetDefault("http_client.circuit_breaker.window_size", 100)
+	v.SetDefault("http_client.circuit_breaker.min_requests", 20)
+	v.SetDefault("http_client.circuit_breaker.error_threshold", 0.5)
+	v.SetDefault("http_client.circuit_breaker.timeout_threshold", 0.5)
+	v.SetDefault("http_client.circuit_breaker.open_duration_ms", 30000)
+	v.SetDefault("http_client.circuit_breaker.half_open_probes", 5)
 	v.SetDefault("http_client_cache.max_connections_per_host", 0) // unlimited
 	v.SetDefault("http_client_cache.max_idle_connections", 10)
 	v.SetDefault("http_client_cache.max_idle_connections_per_host", 2)
@@ -1020,8 +1261,34 @@ func SetupViper(v *viper.Viper, filename string, bidderInfos BidderInfos) {
 	v.SetDefault("metrics.prometheus.namespace", "")
 	v.SetDefault("metrics.prometheus.subsystem", "")
 	v.SetDefault("metrics.prometheus.timeout_ms", 10000)
+	v.SetDefault("metrics.statsd.host", "")
+	v.SetDefault("metrics.statsd.namespace", "prebidserver")
+	v.SetDefault("metrics.statsd.flavor", StatsDFlavorDogStatsD)
+	v.SetDefault("metrics.statsd.tags.adapter", true)
+	v.SetDefault("metrics.statsd.tags.account", false)
+	v.SetDefault("metrics.statsd.tags.stage", true)
+	v.SetDefault("metrics.statsd.aggregation", true)
+	v.SetDefault("metrics.statsd.flush_interval_ms", 1000)
+	v.SetDefault("metrics.statsd.max_packet_size", 1432)
+	v.SetDefault("metrics.statsd.buffer_size", 4096)
+	v.SetDefault("tracing.enabled", false)
+	v.SetDefault("tracing.service_name", "prebid-server")
+	v.SetDefault("tracing.exporter", string(TracingExporterOTLPHTTP))
+	v.SetDefault("tracing.otlp.endpoint", "")
+	v.SetDefault("tracing.otlp.url_path", "/v1/traces")
+	v.SetDefault("tracing.otlp.insecure", false)
+	v.SetDefault("tracing.otlp.timeout_ms", 10000)
+	v.SetDefault("tracing.sampling_rate", 0.01)
+	v.SetDefault("tracing.max_sampling_rate", 1)
+	v.SetDefault("tracing.propagate_to_bidders", false)
+	v.SetDefault("capture.enabled", false)
+	v.SetDefault("capture.directory", "")
+	v.SetDefault("capture.sampling_rate", 0)
+	v.SetDefault("capture.max_file_size_mb", 100)
+	v.SetDefault("capture.buffer_size", 1000)
 	v.SetDefault("category_mapping.filesystem.enabled", true)
 	v.SetDefault("category_mapping.filesystem.directorypath", "./static/category-mapping")
+	v.SetDefault("category_mapping.filesystem.refresh_rate_seconds", 0)
 	v.SetDefault("category_mapping.http.endpoint", "")
 	v.SetDefault("stored_requests_timeout_ms", 50)
 	v.SetDefault("stored_requests.database.connection.driver", "")
@@ -1043,14 +1310,22 @@ func SetupViper(v *viper.Viper, filename string, bidderInfos BidderInfos) {
 	v.SetDefault("stored_requests.database.poll_for_updates.timeout_ms", 0)
 	v.SetDefault("stored_requests.database.poll_for_updates.query", "")
 	v.SetDefault("stored_requests.database.poll_for_updates.amp_query", "")
+	v.SetDefault("stored_requests.database.listen_for_updates.channel", "")
+	v.SetDefault("stored_requests.database.listen_for_updates.timeout_ms", 0)
+	v.SetDefault("stored_requests.database.listen_for_updates.query", "")
+	v.SetDefault("stored_requests.database.listen_for_updates.amp_channel", "")
+	v.SetDefault("stored_requests.database.listen_for_updates.amp_query", "")
 	v.SetDefault("stored_requests.filesystem.enabled", false)
 	v.SetDefault("stored_requests.filesystem.directorypath", "./stored_requests/data/by_id")
+	v.SetDefault("stored_requests.filesystem.refresh_rate_seconds", 0)
 	v.SetDefault("stored_requests.directorypath", "./stored_requests/data/by_id")
 	v.SetDefault("stored_requests.http.endpoint", "")
 	v.SetDefault("stored_requests.http.amp_endpoint", "")
 	v.SetDefault("stored_requests.http.use_rfc3986_compliant_request_builder", true)
 	v.SetDefault("stored_requests.in_memory_cache.type", "none")
 	v.SetDefault("stored_requests.in_memory_cache.ttl_seconds", 0)
+	v.SetDefault("stored_requests.in_memory_cache.stale_ttl_seconds", 0)
+	v.SetDefault("stored_requests.in_memory_cache.not_found_ttl_seconds", 0)
 	v.SetDefault("stored_requests.in_memory_cache.request_cache_size_bytes", 0)
 	v.SetDefault("stored_requests.in_memory_cache.imp_cache_size_bytes", 0)
 	v.SetDefault("stored_requests.in_memory_cache.resp_cache_size_bytes", 0)
@@ -1080,11 +1355,19 @@ func SetupViper(v *viper.Viper, filename string, bidderInfos BidderInfos) {
 	v.SetDefault("stored_video_req.database.poll_for_updates.timeout_ms", 0)
 	v.SetDefault("stored_video_req.database.poll_for_updates.query", "")
 	v.SetDefault("stored_video_req.database.poll_for_updates.amp_query", "")
+	v.SetDefault("stored_video_req.database.listen_for_updates.channel", "")
+	v.SetDefault("stored_video_req.database.listen_for_updates.timeout_ms", 0)
+	v.SetDefault("stored_video_req.database.listen_for_updates.query", "")
+	v.SetDefault("stored_video_req.database.listen_for_updates.amp_channel", "")
+	v.SetDefault("stored_video_req.database.listen_for_updates.amp_query", "")
 	v.SetDefault("stored_video_req.filesystem.enabled", false)
 	v.SetDefault("stored_video_req.filesystem.directorypath", "")
+	v.SetDefault("stored_video_req.filesystem.refresh_rate_seconds", 0)
 	v.SetDefault("stored_video_req.http.endpoint", "")
 	v.SetDefault("stored_video_req.in_memory_cache.type", "none")
 	v.SetDefault("stored_video_req.in_memory_cache.ttl_seconds", 0)
+	v.SetDefault("stored_video_req.in_memory_cache.stale_ttl_seconds", 0)
+	v.SetDefault("stored_video_req.in_memory_cache.not_found_ttl_seconds", 0)
 	v.SetDefault("stored_video_req.in_memory_cache.request_cache_size_bytes", 0)
 	v.SetDefault("stored_video_req.in_memory_cache.imp_cache_size_bytes", 0)
 	v.SetDefault("stored_video_req.in_memory_cache.resp_cache_size_bytes", 0)
@@ -1112,11 +1395,19 @@ func SetupViper(v *viper.Viper, filename string, bidderInfos BidderInfos) {
 	v.SetDefault("stored_responses.database.poll_for_updates.timeout_ms", 0)
 	v.SetDefault("stored_responses.database.poll_for_updates.query", "")
 	v.SetDefault("stored_responses.database.poll_for_updates.amp_query", "")
+	v.SetDefault("stored_responses.database.listen_for_updates.channel", "")
+	v.SetDefault("stored_responses.database.listen_for_updates.timeout_ms", 0)
+	v.SetDefault("stored_responses.database.listen_for_updates.query", "")
+	v.SetDefault("stored_responses.database.listen_for_updates.amp_channel", "")
+	v.SetDefault("stored_responses.database.listen_for_updates.amp_query", "")
 	v.SetDefault("stored_responses.filesystem.enabled", false)
 	v.SetDefault("stored_responses.filesystem.directorypath", "")
+	v.SetDefault("stored_responses.filesystem.refresh_rate_seconds", 0)
 	v.SetDefault("stored_responses.http.endpoint", "")
 	v.SetDefault("stored_responses.in_memory_cache.type", "none")
 	v.SetDefault("stored_responses.in_memory_cache.ttl_seconds", 0)
+	v.SetDefault("stored_responses.in_memory_cache.stale_ttl_seconds", 0)
+	v.SetDefault("stored_responses.in_memory_cache.not_found_ttl_seconds", 0)
 	v.SetDefault("stored_responses.in_memory_cache.request_cache_size_bytes", 0)
 	v.SetDefault("stored_responses.in_memory_cache.imp_cache_size_bytes", 0)
 	v.SetDefault("stored_responses.in_memory_cache.resp_cache_size_bytes", 0)
@@ -1126,6 +1417,41 @@ func SetupViper(v *viper.Viper, filename string, bidderInfos BidderInfos) {
 	v.SetDefault("stored_responses.http_events.refresh_rate_seconds", 0)
 	v.SetDefault("stored_responses.http_events.timeout_ms", 0)
 
+	v.SetDefault("stored_floors.database.connection.driver", "")
+	v.SetDefault("stored_floors.database.connection.dbname", "")
+	v.SetDefault("stored_floors.database.connection.host", "")
+	v.SetDefault("stored_floors.database.connection.port", 0)
+	v.SetDefault("stored_floors.database.connection.user", "")
+	v.SetDefault("stored_floors.database.connection.password", "")
+	v.SetDefault("stored_floors.database.connection.query_string", "")
+	v.SetDefault("stored_floors.database.connection.tls.root_cert", "")
+	v.SetDefault("stored_floors.database.connection.tls.client_cert", "")
+	v.SetDefault("stored_floors.database.connection.tls.client_key", "")
+	v.SetDefault("stored_floors.database.fetcher.query", "")
+	v.SetDefault("stored_floors.database.initialize_caches.timeout_ms", 0)
+	v.SetDefault("stored_floors.database.initialize_caches.query", "")
+	v.SetDefault("stored_floors.database.poll_for_updates.refresh_rate_seconds", 0)
+	v.SetDefault("stored_floors.database.poll_for_updates.timeout_ms", 0)
+	v.SetDefault("stored_floors.database.poll_for_updates.query", "")
+	v.SetDefault("stored_floors.database.listen_for_updates.channel", "")
+	v.SetDefault("stored_floors.database.listen_for_updates.timeout_ms", 0)
+	v.SetDefault("stored_floors.database.listen_for_updates.query", "")
+	v.SetDefault("stored_floors.filesystem.enabled", false)
+	v.SetDefault("stored_floors.filesystem.directorypath", "")
+	v.SetDefault("stored_floors.filesystem.refresh_rate_seconds", 0)
+	v.SetDefault("stored_floors.http.endpoint", "")
+	v.SetDefault("stored_floors.http.use_rfc3986_compliant_request_builder", true)
+	v.SetDefault("stored_floors.in_memory_cache.type", "none")
+	v.SetDefault("stored_floors.in_memory_cache.ttl_seconds", 0)
+	v.SetDefault("stored_floors.in_memory_cache.stale_ttl_seconds", 0)
+	v.SetDefault("stored_floors.in_memory_cache.not_found_ttl_seconds", 0)
+	v.SetDefault("stored_floors.in_memory_cache.size_bytes", 0)
+	v.SetDefault("stored_floors.cache_events.enabled", false)
+	v.SetDefault("stored_floors.cache_events.endpoint", "")
+	v.SetDefault("stored_floors.http_events.endpoint", "")
+	v.SetDefault("stored_floors.http_events.refresh_rate_seconds", 0)
+	v.SetDefault("stored_floors.http_events.timeout_ms", 0)
+
 	v.SetDefault("vtrack.timeout_ms", 2000)
 	v.SetDefault("vtrack.allow_unknown_bidder", true)
 	v.SetDefault("vtrack.enabled", true)
@@ -1135,13 +1461,17 @@ func SetupViper(v *viper.Viper, filename string, bidderInfos BidderInfos) {
 	v.SetDefault("video.enable_deprecated_endpoint", false)
 
 	v.SetDefault("user_sync.priority_groups", [][]string{})
+	v.SetDefault("user_sync.getuids.redirect_hosts", []string{})
 
 	v.SetDefault("accounts.filesystem.enabled", false)
 	v.SetDefault("accounts.filesystem.directorypath", "./stored_requests/data/by_id")
+	v.SetDefault("accounts.filesystem.refresh_rate_seconds", 0)
 	v.SetDefault("accounts.http.endpoint", "")
 	v.SetDefault("accounts.http.use_rfc3986_compliant_request_builder", true)
 	v.SetDefault("accounts.in_memory_cache.type", "none")
 	v.SetDefault("accounts.in_memory_cache.ttl_seconds", 0)
+	v.SetDefault("accounts.in_memory_cache.stale_ttl_seconds", 0)
+	v.SetDefault("accounts.in_memory_cache.not_found_ttl_seconds", 0)
 	v.SetDefault("accounts.in_memory_cache.size_bytes", 0)
 	v.SetDefault("accounts.cache_events.enabled", false)
 	v.SetDefault("accounts.cache_events.endpoint", "")
@@ -1173,6 +1503,23 @@ func SetupViper(v *viper.Viper, filename string, bidderInfos BidderInfos) {
 	v.SetDefault("analytics.agma.buffers.count", 100)
 	v.SetDefault("analytics.agma.buffers.timeout", "15m")
 	v.SetDefault("analytics.agma.accounts", []AgmaAnalyticsAccount{})
+	v.SetDefault("analytics.kafka.enabled", false)
+	v.SetDefault("analytics.kafka.brokers", []string{})
+	v.SetDefault("analytics.kafka.client_id", "prebid-server")
+	v.SetDefault("analytics.kafka.topics.auction", "prebid-auction")
+	v.SetDefault("analytics.kafka.topics.amp", "prebid-amp")
+	v.SetDefault("analytics.kafka.topics.video", "prebid-video")
+	v.SetDefault("analytics.kafka.topics.cookie_sync", "prebid-cookie-sync")
+	v.SetDefault("analytics.kafka.topics.setuid", "prebid-setuid")
+	v.SetDefault("analytics.kafka.topics.notification_event", "prebid-notification-event")
+	v.SetDefault("analytics.kafka.accounts", []KafkaAnalyticsAccount{})
+	v.SetDefault("analytics.kafka.producer.acks", "all")
+	v.SetDefault("analytics.kafka.producer.linger_ms", 50)
+	v.SetDefault("analytics.kafka.producer.batch_max_bytes", 1000000)
+	v.SetDefault("analytics.kafka.producer.max_buffered_records", 10000)
+	v.SetDefault("analytics.kafka.producer.retries", 5)
+	v.SetDefault("analytics.kafka.producer.retry_backoff_ms", 250)
+	v.SetDefault("analytics.kafka.producer.delivery_timeout_ms", 30000)
 	v.SetDefault("amp_timeout_adjustment_ms", 0)
 	v.BindEnv("gdpr.default_value")
 	v.SetDefault("gdpr.enabled", true)
@@ -1261,7 +1608,11 @@ func SetupViper(v *viper.Viper, filename string, bidderInfos BidderInfos) {
 
 	v.SetDefault("account_defaults.events_enabled", false)
 	v.SetDefault("compression.response.enable_gzip", false)
+	v.SetDefault("compression.response.enable_zstd", false)
+	v.SetDefault("compression.response.enable_brotli", false)
 	v.SetDefault("compression.request.enable_gzip", false)
+	v.SetDefault("compression.request.enable_zstd", false)
+	v.SetDefault("compression.request.enable_brotli", false)
 
 	v.SetDefault("certificates_file", "")
 
@@ -150,6 +150,7 @@ func TestDefaults(t *testing.T) {
 	cmpInts(t, "max_request_size", 1024*256, int(cfg.MaxRequestSize))
 	cmpInts(t, "host_cookie.ttl_days", 90, int(cfg.HostCookie.TTL))
 	cmpInts(t, "host_cookie.max_cookie_size_bytes", 0, cfg.HostCookie.MaxCookieSizeBytes)
+	cmpStrings(t, "host_cookie.format", HostCookieFormatJSON, cfg.HostCookie.Format)
 	cmpInts(t, "currency_converter.fetch_interval_seconds", 1800, cfg.CurrencyConverter.FetchIntervalSeconds)
 	cmpStrings(t, "currency_converter.fetch_url", "https://cdn.jsdelivr.net/gh/prebid/currency-file@1/latest.json", cfg.CurrencyConverter.FetchURL)
 	cmpBools(t, "account_required", false, cfg.AccountRequired)
@@ -206,7 +207,11 @@ func TestDefaults(t *testing.T) {
 
 	// Assert compression related defaults
 	cmpBools(t, "compression.request.enable_gzip", false, cfg.Compression.Request.GZIP)
+	cmpBools(t, "compression.request.enable_zstd", false, cfg.Compression.Request.ZSTD)
+	cmpBools(t, "compression.request.enable_brotli", false, cfg.Compression.Request.Brotli)
 	cmpBools(t, "compression.response.enable_gzip", false, cfg.Compression.Response.GZIP)
+	cmpBools(t, "compression.response.enable_zstd", false, cfg.Compression.Response.ZSTD)
+	cmpBools(t, "compression.response.enable_brotli", false, cfg.Compression.Response.Brotli)
 
 	cmpBools(t, "account_defaults.price_floors.enabled", false, cfg.AccountDefaults.PriceFloors.Enabled)
 	cmpInts(t, "account_defaults.price_floors.enforce_floors_rate", 100, cfg.AccountDefaults.PriceFloors.EnforceFloorsRate)
@@ -448,8 +453,10 @@ stored_requests_timeout_ms: 75
 compression:
     request:
         enable_gzip: true
+        enable_zstd: true
     response:
         enable_gzip: false
+        enable_brotli: true
 garbage_collector_threshold: 1
 datacenter: "1"
 auction_timeouts_ms:
@@ -767,7 +774,9 @@ func TestFullConfig(t *testing.T) {
 
 	// Assert compression related defaults
 	cmpBools(t, "compression.request.enable_gzip", true, cfg.Compression.Request.GZIP)
+	cmpBools(t, "compression.request.enable_zstd", true, cfg.Compression.Request.ZSTD)
 	cmpBools(t, "compression.response.enable_gzip", false, cfg.Compression.Response.GZIP)
+	cmpBools(t, "compression.response.enable_brotli", true, cfg.Compression.Response.Brotli)
 
 	//Assert the NonStandardPublishers was correctly unmarshalled
 	assert.Equal(t, []string{"pub1", "pub2"}, cfg.GDPR.NonStandardPublishers, "gdpr.non_standard_publishers")
@@ -997,6 +1006,10 @@ func TestValidateConfig(t *testing.T) {
 			Files:         FileFetcherConfig{Enabled: true},
 			InMemoryCache: InMemoryCache{Type: "none"},
 		},
+		StoredFloors: StoredRequests{
+			Files:         FileFetcherConfig{Enabled: true},
+			InMemoryCache: InMemoryCache{Type: "none"},
+		},
 		AccountDefaults: Account{
 			PriceFloors: AccountPriceFloors{
 				Fetcher: AccountFloorFetch{
@@ -1369,6 +1382,62 @@ func TestOverflowedCurrencyConverterFetchInterval(t *testing.T) {
 	assert.NotNil(t, err, "cfg.currency_converter.fetch_interval_seconds prevent values over %d, but it doesn't", 0xffff)
 }
 
+func TestCurrencyConverterRateSourcesValidation(t *testing.T) {
+	testCases := []struct {
+		description  string
+		sources      []CurrencyRateSource
+		expectedErrs []error
+	}{
+		{
+			description: "valid",
+			sources: []CurrencyRateSource{
+				{Type: CurrencyRateSourcePrebid, URL: "https://prebid.example.com"},
+				{Type: CurrencyRateSourceECB, URL: "https://ecb.example.com"},
+				{Type: CurrencyRateSourceFile, Path: "/rates.json"},
+			},
+		},
+		{
+			description: "missing_url",
+			sources: []CurrencyRateSource{
+				{Type: CurrencyRateSourceECB},
+			},
+			expectedErrs: []error{errors.New("currency_converter.sources[0].url must be set for the ecb sources")},
+		},
+		{
+			description: "missing_path",
+			sources: []CurrencyRateSource{
+				{Type: CurrencyRateSourcePrebid, URL: "https://prebid.example.com"},
+				{Type: CurrencyRateSourceFile},
+			},
+			expectedErrs: []error{errors.New("currency_converter.sources[1].path must be set for the file sources")},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			cfg := CurrencyConverter{Sources: test.sources}
+			errs := cfg.validate(nil)
+			assert.Equal(t, test.expectedErrs, errs)
+		})
+	}
+}
+
+func TestCurrencyConverterRateSourcesInvalidType(t *testing.T) {
+	cfg := CurrencyConverter{Sources: []CurrencyRateSource{{Type: "invalid"}}}
+	errs := cfg.validate(nil)
+	assert.Len(t, errs, 1, "currency_converter.sources[0].type should prevent invalid values, but it doesn't")
+}
+
+func TestCurrencyConverterRateSources(t *testing.T) {
+	cfg := CurrencyConverter{FetchURL: "https://prebid.example.com", StaleRatesSeconds: 60}
+	assert.Equal(t, []CurrencyRateSource{
+		{Type: CurrencyRateSourcePrebid, URL: "https://prebid.example.com", StaleRatesSeconds: 60},
+	}, cfg.RateSources(), "The fetch_url should be the only source by default")
+
+	cfg.Sources = []CurrencyRateSource{{Type: CurrencyRateSourceFile, Path: "/rates.json"}}
+	assert.Equal(t, cfg.Sources, cfg.RateSources(), "The configured sources should replace the fetch_url")
+}
+
 func TestLimitTimeout(t *testing.T) {
 	doTimeoutTest(t, 10, 15, 10, 0)
 	doTimeoutTest(t, 10, 0, 10, 0)
@@ -1401,6 +1470,24 @@ func TestCookieSizeError(t *testing.T) {
 	}
 }
 
+func TestValidateHostCookieFormat(t *testing.T) {
+	testCases := []struct {
+		description  string
+		format       string
+		expectedErrs []error
+	}{
+		{"not-set", "", nil},
+		{"json", HostCookieFormatJSON, nil},
+		{"compact", HostCookieFormatCompact, nil},
+		{"unknown", "binary", []error{errors.New("host_cookie.format must be one of json or compact. Got binary")}},
+	}
+
+	for _, test := range testCases {
+		hostCookie := HostCookie{Format: test.format}
+		assert.Equal(t, test.expectedErrs, hostCookie.validate(nil), test.description)
+	}
+}
+
 func TestNewCallsRequestValidation(t *testing.T) {
 	testCases := []struct {
 		description       string
@@ -1448,15 +1535,129 @@ func TestValidateDebug(t *testing.T) {
 	assert.NotNil(t, err, "cfg.debug.timeout_notification.sampling_rate should not be allowed to be greater than 1.0, but it was allowed")
 }
 
+func TestValidateCircuitBreaker(t *testing.T) {
+	testCases := []struct {
+		description    string
+		circuitBreaker HTTPCircuitBreaker
+		expectedErrs   []error
+	}{
+		{
+			description:    "disabled",
+			circuitBreaker: HTTPCircuitBreaker{Enabled: false, WindowSize: -1},
+			expectedErrs:   nil,
+		},
+		{
+			description: "valid",
+			circuitBreaker: HTTPCircuitBreaker{
+				Enabled:          true,
+				WindowSize:       100,
+				MinRequests:      20,
+				ErrorThreshold:   0.5,
+				TimeoutThreshold: 1,
+				OpenDurationMS:   1000,
+				HalfOpenProbes:   5,
+			},
+			expectedErrs: nil,
+		},
+		{
+			description: "invalid",
+			circuitBreaker: HTTPCircuitBreaker{
+				Enabled:          true,
+				WindowSize:       10,
+				MinRequests:      11,
+				ErrorThreshold:   0,
+				TimeoutThreshold: 1.5,
+				OpenDurationMS:   0,
+				HalfOpenProbes:   0,
+			},
+			expectedErrs: []error{
+				errors.New("http_client.circuit_breaker.min_requests must be > 0 and <= window_size. Got 11"),
+				errors.New("http_client.circuit_breaker.error_threshold must be > 0 and <= 1. Got 0.000000"),
+				errors.New("http_client.circuit_breaker.timeout_threshold must be > 0 and <= 1. Got 1.500000"),
+				errors.New("http_client.circuit_breaker.open_duration_ms must be > 0. Got 0"),
+				errors.New("http_client.circuit_breaker.half_open_probes must be > 0. Got 0"),
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			errs := test.circuitBreaker.validate(nil)
+			assert.Equal(t, test.expectedErrs, errs)
+		})
+	}
+}
+
+func TestValidateStatsDMetrics(t *testing.T) {
+	testCases := []struct {
+		description  string
+		statsD       StatsDMetrics
+		expectedErrs []error
+	}{
+		{
+			description:  "disabled",
+			statsD:       StatsDMetrics{Host: "", Flavor: "unknown"},
+			expectedErrs: nil,
+		},
+		{
+			description: "valid",
+			statsD: StatsDMetrics{
+				Host:            "localhost:8125",
+				Flavor:          StatsDFlavorStatsD,
+				FlushIntervalMS: 1000,
+				MaxPacketSize:   1432,
+				BufferSize:      4096,
+			},
+			expectedErrs: nil,
+		},
+		{
+			description: "invalid",
+			statsD: StatsDMetrics{
+				Host:            "localhost:8125",
+				Flavor:          "graphite",
+				FlushIntervalMS: 0,
+				MaxPacketSize:   -1,
+				BufferSize:      0,
+			},
+			expectedErrs: []error{
+				errors.New("metrics.statsd.flavor must be one of statsd or dogstatsd. Got graphite"),
+				errors.New("metrics.statsd.flush_interval_ms must be positive if metrics.statsd.host is defined. Got 0"),
+				errors.New("metrics.statsd.max_packet_size must be positive if metrics.statsd.host is defined. Got -1"),
+				errors.New("metrics.statsd.buffer_size must be positive if metrics.statsd.host is defined. Got 0"),
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			errs := test.statsD.validate(nil)
+			assert.Equal(t, test.expectedErrs, errs)
+		})
+	}
+}
+
 func TestValidateAccountsConfigRestrictions(t *testing.T) {
 	cfg, v := newDefaultConfig(t)
 	cfg.Accounts.Files.Enabled = true
 	cfg.Accounts.HTTP.Endpoint = "http://localhost"
 	cfg.Accounts.Database.ConnectionInfo.Database = "accounts"
+	cfg.Accounts.Database.FetcherQueries.QueryTemplate = "SELECT id, config, 'account' AS type FROM accounts WHERE id IN $ID_LIST"
 
 	errs := cfg.validate(v)
 	assert.Len(t, errs, 1)
-	assert.Contains(t, errs, errors.New("accounts.database: retrieving accounts via database not available, use accounts.files"))
+	assert.Contains(t, errs, errors.New("accounts.database: fetching accounts via database not available, use accounts.files or the database cache events"))
+}
+
+func TestValidateAccountsDatabaseEvents(t *testing.T) {
+	cfg, v := newDefaultConfig(t)
+	cfg.Accounts.InMemoryCache.Type = "unbounded"
+	cfg.Accounts.Database.ConnectionInfo.Driver = "postgres"
+	cfg.Accounts.Database.ConnectionInfo.Database = "accounts"
+	cfg.Accounts.Database.CacheInitialization = DatabaseCacheInitializer{Timeout: 100, Query: "SELECT id, config, 'account' AS type FROM accounts"}
+	cfg.Accounts.Database.PollUpdates = DatabaseUpdatePolling{RefreshRate: 60, Timeout: 100, Query: "SELECT id, config, 'account' AS type FROM accounts WHERE last_updated > $LAST_UPDATED"}
+	cfg.Accounts.Database.ListenUpdates = DatabaseUpdateListening{Channel: "accounts", Timeout: 100, Query: "SELECT id, config, 'account' AS type FROM accounts WHERE id IN $ID_LIST"}
+
+	assert.Empty(t, cfg.validate(v))
 }
 
 func newDefaultConfig(t *testing.T) (*Configuration, *viper.Viper) {
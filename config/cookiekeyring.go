@@ -0,0 +1,58 @@
+package config
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+)
+
+// CookieKeyRing holds the keys authenticating and encrypting the uids cookie. Cookies are written with the
+// active key and read with any key of the ring, so a key is rotated by adding the new key to every instance,
+// making it the active key, and removing the old key once the cookies written with it have expired.
+//
+// The uids cookie is written without authentication if there's no active key.
+type CookieKeyRing struct {
+	ActiveKeyID string      `mapstructure:"active_key_id"`
+	Keys        []CookieKey `mapstructure:"keys"`
+	// AcceptLegacy allows the cookies written without authentication before the key ring was made active to be
+	// read, and written again with the active key. It should be turned off once these cookies have been
+	// migrated, since they can be forged. Such cookies are always read while there's no active key.
+	AcceptLegacy bool `mapstructure:"accept_legacy"`
+}
+
+// CookieKey is a key of the uids cookie key ring.
+type CookieKey struct {
+	// ID is written along with the cookies, to find the key reading them.
+	ID string `mapstructure:"id"`
+	// Secret is the base64 encoded AES key, which is 16, 24 or 32 bytes long.
+	Secret string `mapstructure:"secret"`
+}
+
+// Enabled returns true if the uids cookie is written with the key ring.
+func (cfg *CookieKeyRing) Enabled() bool {
+	return cfg.ActiveKeyID != ""
+}
+
+func (cfg *CookieKeyRing) validate(errs []error) []error {
+	ids := make(map[string]struct{}, len(cfg.Keys))
+	for i, key := range cfg.Keys {
+		if key.ID == "" || strings.Contains(key.ID, ".") {
+			errs = append(errs, fmt.Errorf("host_cookie.key_ring.keys[%d].id must be non empty and may not contain '.'. Got %s", i, key.ID))
+		} else if _, ok := ids[key.ID]; ok {
+			errs = append(errs, fmt.Errorf("host_cookie.key_ring.keys[%d].id %s is used by several keys", i, key.ID))
+		}
+		ids[key.ID] = struct{}{}
+
+		secret, err := base64.StdEncoding.DecodeString(key.Secret)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("host_cookie.key_ring.keys[%d].secret must be base64 encoded: %v", i, err))
+		} else if len(secret) != 16 && len(secret) != 24 && len(secret) != 32 {
+			errs = append(errs, fmt.Errorf("host_cookie.key_ring.keys[%d].secret must be 16, 24 or 32 bytes long. Got %d", i, len(secret)))
+		}
+	}
+
+	if _, ok := ids[cfg.ActiveKeyID]; cfg.Enabled() && !ok {
+		errs = append(errs, fmt.Errorf("host_cookie.key_ring.active_key_id %s must be the id of one of the keys", cfg.ActiveKeyID))
+	}
+	return errs
+}
@@ -0,0 +1,80 @@
+package config
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateCookieKeyRing(t *testing.T) {
+	const (
+		secret16 = "MDEyMzQ1Njc4OWFiY2RlZg=="
+		secret32 = "MDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODlhYmNkZWY="
+	)
+
+	testCases := []struct {
+		description  string
+		keyRing      CookieKeyRing
+		expectedErrs []error
+	}{
+		{
+			description:  "not-set",
+			keyRing:      CookieKeyRing{},
+			expectedErrs: nil,
+		},
+		{
+			description: "active",
+			keyRing: CookieKeyRing{
+				ActiveKeyID: "2",
+				Keys:        []CookieKey{{ID: "1", Secret: secret16}, {ID: "2", Secret: secret32}},
+			},
+			expectedErrs: nil,
+		},
+		{
+			description: "keys-without-active-key",
+			keyRing: CookieKeyRing{
+				Keys: []CookieKey{{ID: "1", Secret: secret16}},
+			},
+			expectedErrs: nil,
+		},
+		{
+			description: "unknown-active-key",
+			keyRing: CookieKeyRing{
+				ActiveKeyID: "2",
+				Keys:        []CookieKey{{ID: "1", Secret: secret16}},
+			},
+			expectedErrs: []error{
+				errors.New("host_cookie.key_ring.active_key_id 2 must be the id of one of the keys"),
+			},
+		},
+		{
+			description: "invalid-ids",
+			keyRing: CookieKeyRing{
+				Keys: []CookieKey{{ID: "", Secret: secret16}, {ID: "a.b", Secret: secret16}, {ID: "1", Secret: secret16}, {ID: "1", Secret: secret32}},
+			},
+			expectedErrs: []error{
+				errors.New("host_cookie.key_ring.keys[0].id must be non empty and may not contain '.'. Got "),
+				errors.New("host_cookie.key_ring.keys[1].id must be non empty and may not contain '.'. Got a.b"),
+				errors.New("host_cookie.key_ring.keys[3].id 1 is used by several keys"),
+			},
+		},
+		{
+			description: "invalid-secrets",
+			keyRing: CookieKeyRing{
+				Keys: []CookieKey{{ID: "1", Secret: "not base64"}, {ID: "2", Secret: "c2hvcnQ="}},
+			},
+			expectedErrs: []error{
+				errors.New("host_cookie.key_ring.keys[0].secret must be base64 encoded: illegal base64 data at input byte 3"),
+				errors.New("host_cookie.key_ring.keys[1].secret must be 16, 24 or 32 bytes long. Got 5"),
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			errs := test.keyRing.validate(nil)
+			assert.Equal(t, test.expectedErrs, errs)
+		})
+	}
+}
@@ -18,6 +18,7 @@ const (
 	AMPRequestDataType DataType = "AMP Request"
 	AccountDataType    DataType = "Account"
 	ResponseDataType   DataType = "Response"
+	FloorsDataType     DataType = "Floors"
 )
 
 // Section returns the config section this type is defined in
@@ -29,9 +30,16 @@ func (dataType DataType) Section() string {
 		AMPRequestDataType: "stored_amp_req",
 		AccountDataType:    "accounts",
 		ResponseDataType:   "stored_responses",
+		FloorsDataType:     "stored_floors",
 	}[dataType]
 }
 
+// singleCache tells whether the data type is held in a single cache, sized by in_memory_cache.size_bytes,
+// rather than in the request, imp and response caches.
+func (dataType DataType) singleCache() bool {
+	return dataType == AccountDataType || dataType == FloorsDataType
+}
+
 // Section returns the config section
 func (sr *StoredRequests) Section() string {
 	return sr.dataType.Section()
@@ -47,11 +55,18 @@ func (sr *StoredRequests) SetDataType(dataType DataType) {
 	sr.dataType = dataType
 }
 
+// FetchTimeout returns the time the fetches of the stored data are expected to complete in
+func (sr *StoredRequests) FetchTimeout() time.Duration {
+	return sr.fetchTimeout
+}
+
 // StoredRequests struct defines options for stored requests for each data type
 // including some amp stored_requests options
 type StoredRequests struct {
 	// dataType is a tag pushed from upstream indicating the type of object fetched here
 	dataType DataType
+	// fetchTimeout is the stored_requests_timeout_ms pushed from upstream
+	fetchTimeout time.Duration
 	// Files should be used if Stored Requests should be loaded from the filesystem.
 	// Fetchers are in stored_requests/backends/file_system/fetcher.go
 	Files FileFetcherConfig `mapstructure:"filesystem"`
@@ -103,6 +118,12 @@ type FileFetcherConfig struct {
 	Enabled bool `mapstructure:"enabled"`
 	// Path to the directory this file fetcher gets data from.
 	Path string `mapstructure:"directorypath"`
+	// RefreshRate is the number of seconds between reloads of the directory. It's never reloaded if 0.
+	RefreshRate int64 `mapstructure:"refresh_rate_seconds"`
+}
+
+func (cfg FileFetcherConfig) RefreshRateDuration() time.Duration {
+	return time.Duration(cfg.RefreshRate) * time.Second
 }
 
 // HTTPFetcherConfig configures a stored_requests/backends/http_fetcher/fetcher.go
@@ -124,6 +145,8 @@ func resolvedStoredRequestsConfig(cfg *Configuration) {
 	amp.Database.FetcherQueries.QueryTemplate = sr.Database.FetcherQueries.AmpQueryTemplate
 	amp.Database.CacheInitialization.Query = sr.Database.CacheInitialization.AmpQuery
 	amp.Database.PollUpdates.Query = sr.Database.PollUpdates.AmpQuery
+	amp.Database.ListenUpdates.Channel = sr.Database.ListenUpdates.AmpChannel
+	amp.Database.ListenUpdates.Query = sr.Database.ListenUpdates.AmpQuery
 	amp.HTTP.Endpoint = sr.HTTP.AmpEndpoint
 	amp.CacheEvents.Endpoint = "/storedrequests/amp"
 	amp.HTTPEvents.Endpoint = sr.HTTPEvents.AmpEndpoint
@@ -135,11 +158,18 @@ func resolvedStoredRequestsConfig(cfg *Configuration) {
 	cfg.CategoryMapping.dataType = CategoryDataType
 	cfg.Accounts.dataType = AccountDataType
 	cfg.StoredResponses.dataType = ResponseDataType
+	cfg.StoredFloors.dataType = FloorsDataType
+
+	// Set the fetch timeout of each section
+	fetchTimeout := time.Duration(cfg.StoredRequestsTimeout) * time.Millisecond
+	for _, section := range []*StoredRequests{&cfg.StoredRequests, &cfg.StoredRequestsAMP, &cfg.StoredVideo, &cfg.CategoryMapping, &cfg.Accounts, &cfg.StoredResponses, &cfg.StoredFloors} {
+		section.fetchTimeout = fetchTimeout
+	}
 }
 
 func (cfg *StoredRequests) validate(errs []error) []error {
-	if cfg.DataType() == AccountDataType && cfg.Database.ConnectionInfo.Database != "" {
-		errs = append(errs, fmt.Errorf("%s.database: retrieving accounts via database not available, use accounts.files", cfg.Section()))
+	if cfg.DataType() == AccountDataType && cfg.Database.ConnectionInfo.Database != "" && cfg.Database.FetcherQueries.QueryTemplate != "" {
+		errs = append(errs, fmt.Errorf("%s.database: fetching accounts via database not available, use accounts.files or the database cache events", cfg.Section()))
 	} else {
 		errs = cfg.Database.validate(cfg.DataType(), errs)
 	}
@@ -164,6 +194,9 @@ func (cfg *StoredRequests) validate(errs []error) []error {
 		if cfg.Database.CacheInitialization.Query != "" {
 			errs = append(errs, fmt.Errorf("%s: database.initialize_caches.query must be empty if in_memory_cache=none", cfg.Section()))
 		}
+		if cfg.Database.ListenUpdates.Channel != "" {
+			errs = append(errs, fmt.Errorf("%s: database.listen_for_updates.channel must be empty if in_memory_cache=none", cfg.Section()))
+		}
 	}
 	errs = cfg.InMemoryCache.validate(cfg.DataType(), errs)
 	return errs
@@ -176,6 +209,7 @@ type DatabaseConfig struct {
 	FetcherQueries      DatabaseFetcherQueries   `mapstructure:"fetcher"`
 	CacheInitialization DatabaseCacheInitializer `mapstructure:"initialize_caches"`
 	PollUpdates         DatabaseUpdatePolling    `mapstructure:"poll_for_updates"`
+	ListenUpdates       DatabaseUpdateListening  `mapstructure:"listen_for_updates"`
 }
 
 func (cfg *DatabaseConfig) validate(dataType DataType, errs []error) []error {
@@ -185,6 +219,7 @@ func (cfg *DatabaseConfig) validate(dataType DataType, errs []error) []error {
 
 	errs = cfg.CacheInitialization.validate(dataType, errs)
 	errs = cfg.PollUpdates.validate(dataType, errs)
+	errs = cfg.ListenUpdates.validate(dataType, cfg.ConnectionInfo.Driver, cfg.PollUpdates.Query, errs)
 	return errs
 }
 
@@ -309,6 +344,59 @@ func (cfg *DatabaseUpdatePolling) validate(dataType DataType, errs []error) []er
 	return errs
 }
 
+// DatabaseUpdateListening configures the Postgres LISTEN/NOTIFY updates of the caches, which apply the changes
+// as soon as they're notified. The database is expected to NOTIFY the channel with a payload like:
+//
+//	{"type": "request", "ids": ["id1", "id2"]}
+//
+// where the type is one of "request", "imp", "response", "account" or "floors", as returned by the Query. Polling for
+// updates must still be configured, to catch up with the changes made while the server wasn't listening.
+type DatabaseUpdateListening struct {
+	// Channel is the Postgres channel to LISTEN to.
+	Channel string `mapstructure:"channel"`
+
+	// Timeout is the amount of time before a call to the database is aborted.
+	Timeout int `mapstructure:"timeout_ms"`
+
+	// An example Query is:
+	//
+	// SELECT id, requestData, 'request' AS type
+	//   FROM stored_requests
+	//   WHERE id IN $ID_LIST
+	// UNION ALL
+	// SELECT id, impData, 'imp' AS type
+	//   FROM stored_imps
+	//   WHERE id IN $ID_LIST
+	//
+	// The code will run it with the notified ids, and invalidate the ones it doesn't return.
+	Query string `mapstructure:"query"`
+	// AmpChannel is the same as Channel, but used for the `/openrtb2/amp` endpoint.
+	AmpChannel string `mapstructure:"amp_channel"`
+	// AmpQuery is the same as Query, but used for the `/openrtb2/amp` endpoint.
+	AmpQuery string `mapstructure:"amp_query"`
+}
+
+func (cfg *DatabaseUpdateListening) validate(dataType DataType, driver string, pollQuery string, errs []error) []error {
+	section := dataType.Section()
+	if cfg.Channel == "" {
+		return errs
+	}
+
+	if driver != "postgres" {
+		errs = append(errs, fmt.Errorf("%s: database.listen_for_updates is only supported by the postgres driver", section))
+	}
+	if pollQuery == "" {
+		errs = append(errs, fmt.Errorf("%s: database.listen_for_updates requires database.poll_for_updates.query, to catch up with the missed notifications", section))
+	}
+	if cfg.Timeout <= 0 {
+		errs = append(errs, fmt.Errorf("%s: database.listen_for_updates.timeout_ms must be > 0", section))
+	}
+	if !strings.Contains(cfg.Query, "$ID_LIST") {
+		errs = append(errs, fmt.Errorf("%s: database.listen_for_updates.query must contain $ID_LIST parameter", section))
+	}
+	return errs
+}
+
 type InMemoryCache struct {
 	// Identify the type of memory cache. "none", "unbounded", "lru"
 	Type string `mapstructure:"type"`
@@ -323,6 +411,12 @@ type InMemoryCache struct {
 	ImpCacheSize int `mapstructure:"imp_cache_size_bytes"`
 	// ResponsesCacheSize is the max number of bytes allowed in the cache for Stored Responses. Values <= 0 will have no limit
 	RespCacheSize int `mapstructure:"resp_cache_size_bytes"`
+	// StaleTTL is the number of seconds past the TTL during which a value is still served, while it's refreshed
+	// in the background. Values <= 0 disable it. Only supported by lru caches with a TTL.
+	StaleTTL int `mapstructure:"stale_ttl_seconds"`
+	// NotFoundTTL is the number of seconds during which the IDs which weren't found are remembered, so that they
+	// aren't fetched again on every request. Values <= 0 disable it. Only supported by lru caches.
+	NotFoundTTL int `mapstructure:"not_found_ttl_seconds"`
 }
 
 func (cfg *InMemoryCache) validate(dataType DataType, errs []error) []error {
@@ -334,7 +428,14 @@ func (cfg *InMemoryCache) validate(dataType DataType, errs []error) []error {
 		if cfg.TTL != 0 {
 			errs = append(errs, fmt.Errorf("%s: in_memory_cache.ttl_seconds is not supported for unbounded caches. Got %d", section, cfg.TTL))
 		}
-		if dataType == AccountDataType {
+		if cfg.StaleTTL > 0 {
+			errs = append(errs, fmt.Errorf("%s: in_memory_cache.stale_ttl_seconds is not supported for unbounded caches. Got %d", section, cfg.StaleTTL))
+		}
+		if cfg.NotFoundTTL > 0 {
+			// the IDs not found are only removed when they're looked up again, so the cache would grow with every unknown ID
+			errs = append(errs, fmt.Errorf("%s: in_memory_cache.not_found_ttl_seconds is not supported for unbounded caches. Got %d", section, cfg.NotFoundTTL))
+		}
+		if dataType.singleCache() {
 			// single cache
 			if cfg.Size != 0 {
 				errs = append(errs, fmt.Errorf("%s: in_memory_cache.size_bytes is not supported for unbounded caches. Got %d", section, cfg.Size))
@@ -352,7 +453,10 @@ func (cfg *InMemoryCache) validate(dataType DataType, errs []error) []error {
 			}
 		}
 	case "lru":
-		if dataType == AccountDataType {
+		if cfg.StaleTTL > 0 && cfg.TTL <= 0 {
+			errs = append(errs, fmt.Errorf("%s: in_memory_cache.stale_ttl_seconds requires in_memory_cache.ttl_seconds > 0. Got %d", section, cfg.TTL))
+		}
+		if dataType.singleCache() {
 			// single cache
 			if cfg.Size <= 0 {
 				errs = append(errs, fmt.Errorf("%s: in_memory_cache.size_bytes must be >= 0 when in_memory_cache.type=lru. Got %d", section, cfg.Size))
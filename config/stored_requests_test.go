@@ -61,6 +61,30 @@ func TestInMemoryCacheValidationStoredRequests(t *testing.T) {
 		Type: "lru",
 		Size: 1000,
 	}).validate(RequestDataType, nil))
+	assertNoErrs(t, (&InMemoryCache{
+		Type:             "lru",
+		TTL:              60,
+		StaleTTL:         60,
+		NotFoundTTL:      10,
+		RequestCacheSize: 1000,
+		ImpCacheSize:     1000,
+		RespCacheSize:    1000,
+	}).validate(RequestDataType, nil))
+	assertErrsExist(t, (&InMemoryCache{
+		Type:        "unbounded",
+		NotFoundTTL: 10,
+	}).validate(RequestDataType, nil))
+	assertErrsExist(t, (&InMemoryCache{
+		Type:     "unbounded",
+		StaleTTL: 60,
+	}).validate(RequestDataType, nil))
+	assertErrsExist(t, (&InMemoryCache{
+		Type:             "lru",
+		StaleTTL:         60,
+		RequestCacheSize: 1000,
+		ImpCacheSize:     1000,
+		RespCacheSize:    1000,
+	}).validate(RequestDataType, nil))
 }
 
 func TestInMemoryCacheValidationSingleCache(t *testing.T) {
@@ -101,6 +125,38 @@ func TestInMemoryCacheValidationSingleCache(t *testing.T) {
 		Type:          "lru",
 		RespCacheSize: 1000,
 	}).validate(AccountDataType, nil))
+	assertNoErrs(t, (&InMemoryCache{
+		Type:        "lru",
+		Size:        1000,
+		TTL:         60,
+		StaleTTL:    60,
+		NotFoundTTL: 10,
+	}).validate(AccountDataType, nil))
+	assertErrsExist(t, (&InMemoryCache{
+		Type:     "lru",
+		Size:     1000,
+		StaleTTL: 60,
+	}).validate(AccountDataType, nil))
+	assertErrsExist(t, (&InMemoryCache{
+		Type:        "unbounded",
+		NotFoundTTL: 10,
+	}).validate(AccountDataType, nil))
+}
+
+func TestInMemoryCacheValidationFloors(t *testing.T) {
+	// the floors are held in a single cache, like the accounts
+	assertNoErrs(t, (&InMemoryCache{
+		Type: "lru",
+		Size: 1000,
+	}).validate(FloorsDataType, nil))
+	assertErrsExist(t, (&InMemoryCache{
+		Type:             "lru",
+		RequestCacheSize: 1000,
+	}).validate(FloorsDataType, nil))
+	assertErrsExist(t, (&InMemoryCache{
+		Type: "unbounded",
+		Size: 1000,
+	}).validate(FloorsDataType, nil))
 }
 
 func TestDatabaseConfigValidation(t *testing.T) {
@@ -205,6 +261,43 @@ func TestDatabaseConfigValidation(t *testing.T) {
 	}
 }
 
+func TestValidateDatabaseUpdateListening(t *testing.T) {
+	testCases := []struct {
+		description    string
+		givenDriver    string
+		givenPollQuery string
+		givenListen    DatabaseUpdateListening
+		expectedErrs   []error
+	}{
+		{
+			description: "Not listening",
+			givenDriver: "mysql",
+		},
+		{
+			description:    "Valid",
+			givenDriver:    "postgres",
+			givenPollQuery: "SELECT id, data, 'request' AS type FROM stored_requests WHERE last_updated > $LAST_UPDATED",
+			givenListen:    DatabaseUpdateListening{Channel: "updates", Timeout: 100, Query: "SELECT id, data, 'request' AS type FROM stored_requests WHERE id IN $ID_LIST"},
+		},
+		{
+			description: "Invalid",
+			givenDriver: "mysql",
+			givenListen: DatabaseUpdateListening{Channel: "updates", Query: "SELECT id, data, 'request' AS type FROM stored_requests"},
+			expectedErrs: []error{
+				errors.New("stored_requests: database.listen_for_updates is only supported by the postgres driver"),
+				errors.New("stored_requests: database.listen_for_updates requires database.poll_for_updates.query, to catch up with the missed notifications"),
+				errors.New("stored_requests: database.listen_for_updates.timeout_ms must be > 0"),
+				errors.New("stored_requests: database.listen_for_updates.query must contain $ID_LIST parameter"),
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		errs := test.givenListen.validate(RequestDataType, test.givenDriver, test.givenPollQuery, nil)
+		assert.Equal(t, test.expectedErrs, errs, test.description)
+	}
+}
+
 func assertErrsExist(t *testing.T, err []error) {
 	t.Helper()
 	if len(err) == 0 {
@@ -250,6 +343,10 @@ func TestResolveConfig(t *testing.T) {
 				PollUpdates: DatabaseUpdatePolling{
 					AmpQuery: "amp-poll-query",
 				},
+				ListenUpdates: DatabaseUpdateListening{
+					AmpChannel: "amp-listen-channel",
+					AmpQuery:   "amp-listen-query",
+				},
 			},
 			HTTP: HTTPFetcherConfig{
 				AmpEndpoint: "amp-http-fetcher-endpoint",
@@ -286,7 +383,12 @@ func TestResolveConfig(t *testing.T) {
 	assertStringsEqual(t, amp.Database.FetcherQueries.QueryTemplate, cfg.StoredRequests.Database.FetcherQueries.AmpQueryTemplate)
 	assertStringsEqual(t, amp.Database.CacheInitialization.Query, cfg.StoredRequests.Database.CacheInitialization.AmpQuery)
 	assertStringsEqual(t, amp.Database.PollUpdates.Query, cfg.StoredRequests.Database.PollUpdates.AmpQuery)
+	assertStringsEqual(t, amp.Database.ListenUpdates.Channel, cfg.StoredRequests.Database.ListenUpdates.AmpChannel)
+	assertStringsEqual(t, amp.Database.ListenUpdates.Query, cfg.StoredRequests.Database.ListenUpdates.AmpQuery)
 	assertStringsEqual(t, amp.HTTP.Endpoint, cfg.StoredRequests.HTTP.AmpEndpoint)
 	assertStringsEqual(t, amp.HTTPEvents.Endpoint, cfg.StoredRequests.HTTPEvents.AmpEndpoint)
 	assertStringsEqual(t, amp.CacheEvents.Endpoint, "/storedrequests/amp")
+
+	assert.Equal(t, FloorsDataType, cfg.StoredFloors.DataType())
+	assertStringsEqual(t, cfg.StoredFloors.Section(), "stored_floors")
 }
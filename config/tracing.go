@@ -0,0 +1,76 @@
+package config
+
+import (
+	"fmt"
+)
+
+// TracingExporter is the destination of the spans collected by OpenTelemetry tracing.
+type TracingExporter string
+
+const (
+	TracingExporterOTLPHTTP TracingExporter = "otlp_http"
+	TracingExporterStdout   TracingExporter = "stdout"
+)
+
+// Tracing configures OpenTelemetry distributed tracing.
+type Tracing struct {
+	Enabled bool `mapstructure:"enabled"`
+	// ServiceName is reported as the service.name resource attribute of all spans.
+	ServiceName string          `mapstructure:"service_name"`
+	Exporter    TracingExporter `mapstructure:"exporter"`
+	OTLP        TracingOTLP     `mapstructure:"otlp"`
+	// SamplingRate is the ratio of requests traced for accounts which don't define their own sampling rate.
+	// Requests carrying a sampled W3C traceparent header are always traced.
+	SamplingRate float64 `mapstructure:"sampling_rate"`
+	// MaxSamplingRate is the highest sampling rate any account may use. Requests outside of it are never
+	// recorded, which avoids the cost of recording spans which would be dropped anyway.
+	MaxSamplingRate float64 `mapstructure:"max_sampling_rate"`
+	// PropagateToBidders controls whether the W3C traceparent header is sent with bidder requests.
+	PropagateToBidders bool `mapstructure:"propagate_to_bidders"`
+}
+
+// TracingOTLP configures the OTLP/HTTP span exporter.
+type TracingOTLP struct {
+	// Endpoint is the host and port of the collector, for example localhost:4318.
+	Endpoint string            `mapstructure:"endpoint"`
+	URLPath  string            `mapstructure:"url_path"`
+	Insecure bool              `mapstructure:"insecure"`
+	Headers  map[string]string `mapstructure:"headers"`
+	// TimeoutMS is the maximum time allowed for each batch export.
+	TimeoutMS int `mapstructure:"timeout_ms"`
+}
+
+// AccountTracing represents account-specific tracing configuration.
+type AccountTracing struct {
+	// SamplingRate overrides the host sampling rate for requests of the account.
+	SamplingRate *float64 `mapstructure:"sampling_rate" json:"sampling_rate,omitempty"`
+}
+
+func (cfg *Tracing) validate(errs []error) []error {
+	if !cfg.Enabled {
+		return errs
+	}
+	switch cfg.Exporter {
+	case TracingExporterOTLPHTTP:
+		if cfg.OTLP.Endpoint == "" {
+			errs = append(errs, fmt.Errorf("tracing.otlp.endpoint must be specified when tracing.exporter is %s", TracingExporterOTLPHTTP))
+		}
+	case TracingExporterStdout:
+	default:
+		errs = append(errs, fmt.Errorf("tracing.exporter must be one of %s or %s. Got %s", TracingExporterOTLPHTTP, TracingExporterStdout, cfg.Exporter))
+	}
+	if cfg.SamplingRate < 0 || cfg.SamplingRate > 1 {
+		errs = append(errs, fmt.Errorf("tracing.sampling_rate must be >= 0 and <= 1. Got %f", cfg.SamplingRate))
+	}
+	if cfg.MaxSamplingRate < cfg.SamplingRate || cfg.MaxSamplingRate > 1 {
+		errs = append(errs, fmt.Errorf("tracing.max_sampling_rate must be >= tracing.sampling_rate and <= 1. Got %f", cfg.MaxSamplingRate))
+	}
+	return errs
+}
+
+func (cfg *AccountTracing) validate(errs []error) []error {
+	if cfg.SamplingRate != nil && (*cfg.SamplingRate < 0 || *cfg.SamplingRate > 1) {
+		errs = append(errs, fmt.Errorf("account_defaults.tracing.sampling_rate must be >= 0 and <= 1. Got %f", *cfg.SamplingRate))
+	}
+	return errs
+}
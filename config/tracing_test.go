@@ -0,0 +1,122 @@
+package config
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/prebid/prebid-server/v4/util/ptrutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateTracing(t *testing.T) {
+	testCases := []struct {
+		description  string
+		tracing      Tracing
+		expectedErrs []error
+	}{
+		{
+			description:  "disabled",
+			tracing:      Tracing{Enabled: false, Exporter: "unknown", SamplingRate: 2},
+			expectedErrs: nil,
+		},
+		{
+			description: "valid-otlp",
+			tracing: Tracing{
+				Enabled:         true,
+				Exporter:        TracingExporterOTLPHTTP,
+				OTLP:            TracingOTLP{Endpoint: "localhost:4318"},
+				SamplingRate:    0.1,
+				MaxSamplingRate: 0.5,
+			},
+			expectedErrs: nil,
+		},
+		{
+			description: "valid-stdout",
+			tracing: Tracing{
+				Enabled:         true,
+				Exporter:        TracingExporterStdout,
+				SamplingRate:    1,
+				MaxSamplingRate: 1,
+			},
+			expectedErrs: nil,
+		},
+		{
+			description: "otlp-without-endpoint",
+			tracing: Tracing{
+				Enabled:         true,
+				Exporter:        TracingExporterOTLPHTTP,
+				SamplingRate:    0.1,
+				MaxSamplingRate: 1,
+			},
+			expectedErrs: []error{
+				errors.New("tracing.otlp.endpoint must be specified when tracing.exporter is otlp_http"),
+			},
+		},
+		{
+			description: "invalid",
+			tracing: Tracing{
+				Enabled:         true,
+				Exporter:        "zipkin",
+				SamplingRate:    0.5,
+				MaxSamplingRate: 0.1,
+			},
+			expectedErrs: []error{
+				errors.New("tracing.exporter must be one of otlp_http or stdout. Got zipkin"),
+				errors.New("tracing.max_sampling_rate must be >= tracing.sampling_rate and <= 1. Got 0.100000"),
+			},
+		},
+		{
+			description: "sampling-rate-out-of-range",
+			tracing: Tracing{
+				Enabled:         true,
+				Exporter:        TracingExporterStdout,
+				SamplingRate:    -0.1,
+				MaxSamplingRate: 1.5,
+			},
+			expectedErrs: []error{
+				errors.New("tracing.sampling_rate must be >= 0 and <= 1. Got -0.100000"),
+				errors.New("tracing.max_sampling_rate must be >= tracing.sampling_rate and <= 1. Got 1.500000"),
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			errs := test.tracing.validate(nil)
+			assert.Equal(t, test.expectedErrs, errs)
+		})
+	}
+}
+
+func TestValidateAccountTracing(t *testing.T) {
+	testCases := []struct {
+		description  string
+		tracing      AccountTracing
+		expectedErrs []error
+	}{
+		{
+			description:  "not-set",
+			tracing:      AccountTracing{},
+			expectedErrs: nil,
+		},
+		{
+			description:  "valid",
+			tracing:      AccountTracing{SamplingRate: ptrutil.ToPtr(0.25)},
+			expectedErrs: nil,
+		},
+		{
+			description: "out-of-range",
+			tracing:     AccountTracing{SamplingRate: ptrutil.ToPtr(1.25)},
+			expectedErrs: []error{
+				errors.New("account_defaults.tracing.sampling_rate must be >= 0 and <= 1. Got 1.250000"),
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			errs := test.tracing.validate(nil)
+			assert.Equal(t, test.expectedErrs, errs)
+		})
+	}
+}
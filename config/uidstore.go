@@ -0,0 +1,54 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	// UIDStoreTypeCookie keeps the UIDs of users in the uids cookie.
+	UIDStoreTypeCookie = "cookie"
+	// UIDStoreTypeMemory keeps the UIDs of users in memory. It's meant for tests and single instance setups.
+	UIDStoreTypeMemory = "memory"
+	// UIDStoreTypeRedis keeps the UIDs of users in Redis, which must be 7.0 or later.
+	UIDStoreTypeRedis = "redis"
+)
+
+// UIDStore configures where the UIDs synced for users are kept. Unless they're kept in the uids cookie,
+// the cookie only carries a key identifying the user in the store.
+type UIDStore struct {
+	Type  string        `mapstructure:"type"`
+	Redis UIDStoreRedis `mapstructure:"redis"`
+}
+
+// UIDStoreRedis configures the Redis server of the UID store.
+type UIDStoreRedis struct {
+	Address  string `mapstructure:"address"`
+	Username string `mapstructure:"username"`
+	Password string `mapstructure:"password"`
+	DB       int    `mapstructure:"db"`
+	// KeyPrefix is prepended to the user keys, so several hosts can share the same database.
+	KeyPrefix string `mapstructure:"key_prefix"`
+	// TimeoutMS bounds every call made to Redis.
+	TimeoutMS int `mapstructure:"timeout_ms"`
+}
+
+func (cfg *UIDStoreRedis) Timeout() time.Duration {
+	return time.Duration(cfg.TimeoutMS) * time.Millisecond
+}
+
+func (cfg *UIDStore) validate(errs []error) []error {
+	switch cfg.Type {
+	case "", UIDStoreTypeCookie, UIDStoreTypeMemory:
+	case UIDStoreTypeRedis:
+		if cfg.Redis.Address == "" {
+			errs = append(errs, fmt.Errorf("host_cookie.uid_store.redis.address must be specified when the uid store type is redis"))
+		}
+		if cfg.Redis.TimeoutMS <= 0 {
+			errs = append(errs, fmt.Errorf("host_cookie.uid_store.redis.timeout_ms must be > 0. Got %d", cfg.Redis.TimeoutMS))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("host_cookie.uid_store.type must be one of %s, %s or %s. Got %s", UIDStoreTypeCookie, UIDStoreTypeMemory, UIDStoreTypeRedis, cfg.Type))
+	}
+	return errs
+}
@@ -0,0 +1,59 @@
+package config
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateUIDStore(t *testing.T) {
+	testCases := []struct {
+		description  string
+		uidStore     UIDStore
+		expectedErrs []error
+	}{
+		{
+			description:  "not-set",
+			uidStore:     UIDStore{},
+			expectedErrs: nil,
+		},
+		{
+			description:  "cookie",
+			uidStore:     UIDStore{Type: UIDStoreTypeCookie},
+			expectedErrs: nil,
+		},
+		{
+			description:  "memory",
+			uidStore:     UIDStore{Type: UIDStoreTypeMemory},
+			expectedErrs: nil,
+		},
+		{
+			description:  "redis",
+			uidStore:     UIDStore{Type: UIDStoreTypeRedis, Redis: UIDStoreRedis{Address: "localhost:6379", TimeoutMS: 50}},
+			expectedErrs: nil,
+		},
+		{
+			description: "redis-invalid",
+			uidStore:    UIDStore{Type: UIDStoreTypeRedis},
+			expectedErrs: []error{
+				errors.New("host_cookie.uid_store.redis.address must be specified when the uid store type is redis"),
+				errors.New("host_cookie.uid_store.redis.timeout_ms must be > 0. Got 0"),
+			},
+		},
+		{
+			description: "unknown-type",
+			uidStore:    UIDStore{Type: "file"},
+			expectedErrs: []error{
+				errors.New("host_cookie.uid_store.type must be one of cookie, memory or redis. Got file"),
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			errs := test.uidStore.validate(nil)
+			assert.Equal(t, test.expectedErrs, errs)
+		})
+	}
+}
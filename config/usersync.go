@@ -6,6 +6,7 @@ type UserSync struct {
 	ExternalURL    string              `mapstructure:"external_url"`
 	RedirectURL    string              `mapstructure:"redirect_url"`
 	PriorityGroups [][]string          `mapstructure:"priority_groups"`
+	GetUIDs        UserSyncGetUIDs     `mapstructure:"getuids"`
 }
 
 // UserSyncCooperative specifies the static global default cooperative cookie sync
@@ -13,6 +14,13 @@ type UserSyncCooperative struct {
 	EnabledByDefault bool `mapstructure:"default"`
 }
 
+// UserSyncGetUIDs specifies the static global /getuids configuration
+type UserSyncGetUIDs struct {
+	// RedirectHosts lists the hosts the user may be redirected to with their ids. Redirects are
+	// rejected if it's empty, so that the ids can't be sent to any site.
+	RedirectHosts []string `mapstructure:"redirect_hosts"`
+}
+
 // CookieSync specifies host-level cookie sync settings that are always enforced by the
 // host operator. These settings cannot be overridden by account configuration and are
 // unioned with any account-level restrictions.
@@ -2,28 +2,35 @@ package currency
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"io"
 	"net/http"
+	"sync"
 	"sync/atomic"
 	"time"
 
-	"github.com/prebid/prebid-server/v4/errortypes"
 	"github.com/prebid/prebid-server/v4/logger"
-	"github.com/prebid/prebid-server/v4/util/jsonutil"
 	"github.com/prebid/prebid-server/v4/util/timeutil"
 )
 
 // RateConverter holds the currencies conversion rates dictionary
 type RateConverter struct {
-	httpClient          httpClient
-	httpTimeout         time.Duration
-	staleRatesThreshold time.Duration
-	syncSourceURL       string
-	rates               atomic.Value // Should only hold Rates struct
-	lastUpdated         atomic.Value // Should only hold time.Time
-	constantRates       Conversions
-	time                timeutil.Time
+	httpTimeout   time.Duration
+	sources       []ChainedRateSource
+	states        []rateSourceState
+	statesLock    sync.RWMutex
+	rates         atomic.Value // Should only hold Rates struct
+	lastUpdated   atomic.Value // Should only hold time.Time
+	constantRates Conversions
+	time          timeutil.Time
+}
+
+// rateSourceState holds the outcome of the last fetches of a source.
+type rateSourceState struct {
+	rates       *Rates
+	lastUpdated time.Time
+	lastError   error
+	active      bool
 }
 
 // NewRateConverter returns a new RateConverter
@@ -33,72 +40,82 @@ func NewRateConverter(
 	syncSourceURL string,
 	staleRatesThreshold time.Duration,
 ) *RateConverter {
+	return NewChainedRateConverter(httpTimeout, []ChainedRateSource{
+		{RateSource: NewPrebidRateSource(httpClient, syncSourceURL), StaleRatesThreshold: staleRatesThreshold},
+	})
+}
+
+// NewChainedRateConverter returns a new RateConverter which uses the rates of the first source, in priority
+// order, which either is fetched successfully or has rates which aren't stale yet. The constant rates are used
+// if there's no such source.
+func NewChainedRateConverter(timeout time.Duration, sources []ChainedRateSource) *RateConverter {
 	return &RateConverter{
-		httpClient:          httpClient,
-		httpTimeout:         httpTimeout,
-		staleRatesThreshold: staleRatesThreshold,
-		syncSourceURL:       syncSourceURL,
-		rates:               atomic.Value{},
-		lastUpdated:         atomic.Value{},
-		constantRates:       NewConstantRates(),
-		time:                &timeutil.RealTime{},
+		httpTimeout:   timeout,
+		sources:       sources,
+		states:        make([]rateSourceState, len(sources)),
+		rates:         atomic.Value{},
+		lastUpdated:   atomic.Value{},
+		constantRates: NewConstantRates(),
+		time:          &timeutil.RealTime{},
 	}
 }
 
-// fetch allows to retrieve the currencies rates from the syncSourceURL provided
-func (rc *RateConverter) fetch() (*Rates, error) {
+// fetch allows to retrieve the currencies rates from the source provided
+func (rc *RateConverter) fetch(source RateSource) (*Rates, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), rc.httpTimeout)
 	defer cancel()
 
-	request, err := http.NewRequestWithContext(ctx, "GET", rc.syncSourceURL, nil)
-	if err != nil {
-		return nil, err
-	}
+	return source.Fetch(ctx)
+}
 
-	response, err := rc.httpClient.Do(request)
-	if err != nil {
-		return nil, err
-	}
-	defer func() {
-		// read the entire response body to ensure full connection reuse if there's an
-		// invalid status code
-		if _, err := io.Copy(io.Discard, response.Body); err != nil {
-			logger.Errorf("error draining conversion rates response body: %v", err)
+// Update updates the internal currencies rates from remote sources
+func (rc *RateConverter) update() error {
+	var errs []error
+	active := -1
+
+	for i, source := range rc.sources {
+		rates, err := rc.fetch(source)
+
+		rc.statesLock.Lock()
+		state := &rc.states[i]
+		state.lastError = err
+		if err == nil {
+			state.rates = rates
+			state.lastUpdated = rc.time.Now()
+		} else if state.rates != nil && rc.checkStaleRates(state.lastUpdated, source.StaleRatesThreshold) {
+			state.rates = nil
 		}
-		response.Body.Close()
-	}()
+		if active == -1 && state.rates != nil {
+			active = i
+		}
+		rc.statesLock.Unlock()
 
-	if response.StatusCode >= 400 {
-		message := fmt.Sprintf("the currency rates request failed with status code %d", response.StatusCode)
-		return nil, &errortypes.BadServerResponse{Message: message}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %v", source.Source(), err))
+		}
+		if active != -1 {
+			break
+		}
 	}
 
-	bytesJSON, err := io.ReadAll(response.Body)
-	if err != nil {
-		return nil, fmt.Errorf("the currency rates request failed: %v", err)
+	rc.statesLock.Lock()
+	for i := range rc.states {
+		rc.states[i].active = i == active
 	}
-
-	updatedRates := &Rates{}
-	err = jsonutil.UnmarshalValid(bytesJSON, updatedRates)
-	if err != nil {
-		return nil, fmt.Errorf("the currency rates request failed to parse json: %v", err)
+	if active == -1 {
+		rc.clearRates()
+	} else {
+		rc.rates.Store(rc.states[active].rates)
+		rc.lastUpdated.Store(rc.states[active].lastUpdated)
 	}
+	rc.statesLock.Unlock()
 
-	return updatedRates, err
-}
-
-// Update updates the internal currencies rates from remote sources
-func (rc *RateConverter) update() error {
-	rates, err := rc.fetch()
-	if err == nil {
-		rc.rates.Store(rates)
-		rc.lastUpdated.Store(rc.time.Now())
-	} else {
-		if rc.checkStaleRates() {
-			rc.clearRates()
+	err := errors.Join(errs...)
+	if err != nil {
+		if active == -1 {
 			logger.Errorf("Error updating conversion rates, falling back to constant rates: %v", err)
 		} else {
-			logger.Errorf("Error updating conversion rates: %v", err)
+			logger.Errorf("Error updating conversion rates, using the rates of %s: %v", rc.sources[active].Source(), err)
 		}
 	}
 
@@ -133,29 +150,71 @@ func (rc *RateConverter) clearRates() {
 	rc.rates.Store((*Rates)(nil))
 }
 
-// checkStaleRates checks if loaded third party conversion rates are stale
-func (rc *RateConverter) checkStaleRates() bool {
-	if rc.staleRatesThreshold <= 0 {
+// checkStaleRates checks if the conversion rates of a source updated at lastUpdated are stale
+func (rc *RateConverter) checkStaleRates(lastUpdated time.Time, staleRatesThreshold time.Duration) bool {
+	if staleRatesThreshold <= 0 {
 		return false
 	}
 
 	currentTime := rc.time.Now().UTC()
-	if lastUpdated := rc.lastUpdated.Load(); lastUpdated != nil {
-		delta := currentTime.Sub(lastUpdated.(time.Time).UTC())
-		if delta.Seconds() > rc.staleRatesThreshold.Seconds() {
-			return true
-		}
-	}
-	return false
+	delta := currentTime.Sub(lastUpdated.UTC())
+	return delta.Seconds() > staleRatesThreshold.Seconds()
+}
+
+// RateSourcesInfo is the additional information of the RateConverter, about each of its sources
+type RateSourcesInfo struct {
+	ActiveSource string           `json:"activeSource,omitempty"`
+	Sources      []RateSourceInfo `json:"sources"`
+}
+
+// RateSourceInfo describes a source of the RateConverter, in priority order
+type RateSourceInfo struct {
+	Source              string     `json:"source"`
+	Active              bool       `json:"active"`
+	StaleRatesThreshold int64      `json:"staleRatesSeconds,omitempty"`
+	LastUpdated         *time.Time `json:"lastUpdated,omitempty"`
+	LastError           string     `json:"lastError,omitempty"`
 }
 
 // GetInfo returns setup information about the converter
 func (rc *RateConverter) GetInfo() ConverterInfo {
 	var rates *map[string]map[string]float64 = rc.Rates().GetRates()
+
+	rc.statesLock.RLock()
+	defer rc.statesLock.RUnlock()
+
+	info := RateSourcesInfo{Sources: make([]RateSourceInfo, len(rc.sources))}
+	for i, source := range rc.sources {
+		state := rc.states[i]
+		sourceInfo := RateSourceInfo{
+			Source:              source.Source(),
+			Active:              state.active,
+			StaleRatesThreshold: int64(source.StaleRatesThreshold.Seconds()),
+		}
+		if !state.lastUpdated.IsZero() {
+			lastUpdated := state.lastUpdated
+			sourceInfo.LastUpdated = &lastUpdated
+		}
+		if state.lastError != nil {
+			sourceInfo.LastError = state.lastError.Error()
+		}
+		if state.active {
+			info.ActiveSource = sourceInfo.Source
+		}
+		info.Sources[i] = sourceInfo
+	}
+
+	// the source is the active one, or the one of the highest priority when the constant rates are used
+	source := info.ActiveSource
+	if source == "" && len(rc.sources) > 0 {
+		source = rc.sources[0].Source()
+	}
+
 	return converterInfo{
-		source:      rc.syncSourceURL,
-		lastUpdated: rc.LastUpdated(),
-		rates:       rates,
+		source:         source,
+		lastUpdated:    rc.LastUpdated(),
+		rates:          rates,
+		additionalInfo: info,
 	}
 }
 
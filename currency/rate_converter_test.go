@@ -1,6 +1,8 @@
 package currency
 
 import (
+	"context"
+	"errors"
 	"io"
 	"net/http"
 	"net/http/httptest"
@@ -381,3 +383,109 @@ func (m *mockHttpClient) Do(req *http.Request) (*http.Response, error) {
 		Body:       io.NopCloser(strings.NewReader(m.responseBody)),
 	}, nil
 }
+
+func TestChainedRateSources(t *testing.T) {
+	primaryRates := NewRates(map[string]map[string]float64{"USD": {"GBP": 0.8}})
+	secondaryRates := NewRates(map[string]map[string]float64{"USD": {"GBP": 0.75}})
+	primary := &fakeRateSource{source: "primary", rates: primaryRates}
+	secondary := &fakeRateSource{source: "secondary", rates: secondaryRates}
+
+	initialFakeTime := time.Date(2018, time.September, 12, 30, 0, 0, 0, time.UTC)
+	fakeTime := &FakeTime{time: initialFakeTime}
+
+	currencyConverter := NewChainedRateConverter(time.Second, []ChainedRateSource{
+		{RateSource: primary, StaleRatesThreshold: 30 * time.Second},
+		{RateSource: secondary},
+	})
+	currencyConverter.time = fakeTime
+
+	// The primary source is used when it's fetched successfully, and the secondary one isn't fetched
+	assert.NoError(t, currencyConverter.Run())
+	assert.Equal(t, primaryRates, currencyConverter.Rates())
+	assert.Equal(t, 0, secondary.calls, "The secondary source shouldn't be fetched")
+	assertActiveSource(t, currencyConverter, "primary")
+
+	// The rates of the primary source are kept while they aren't stale
+	primary.err = errors.New("primary down")
+	fakeTime.time = fakeTime.time.Add(29 * time.Second)
+	assert.EqualError(t, currencyConverter.Run(), "primary: primary down")
+	assert.Equal(t, primaryRates, currencyConverter.Rates())
+	assert.Equal(t, initialFakeTime, currencyConverter.LastUpdated())
+	assert.Equal(t, 0, secondary.calls, "The secondary source shouldn't be fetched")
+	assertActiveSource(t, currencyConverter, "primary")
+
+	// The secondary source is used once the rates of the primary source are stale
+	fakeTime.time = fakeTime.time.Add(2 * time.Second)
+	assert.EqualError(t, currencyConverter.Run(), "primary: primary down")
+	assert.Equal(t, secondaryRates, currencyConverter.Rates())
+	assert.Equal(t, fakeTime.time, currencyConverter.LastUpdated())
+	assertActiveSource(t, currencyConverter, "secondary")
+
+	// The secondary rates never go stale
+	secondary.err = errors.New("secondary down")
+	fakeTime.time = fakeTime.time.Add(time.Hour)
+	assert.Error(t, currencyConverter.Run())
+	assert.Equal(t, secondaryRates, currencyConverter.Rates())
+	assertActiveSource(t, currencyConverter, "secondary")
+
+	info := currencyConverter.GetInfo().AdditionalInfo().(RateSourcesInfo)
+	assert.Equal(t, "primary down", info.Sources[0].LastError)
+	assert.Equal(t, int64(30), info.Sources[0].StaleRatesThreshold)
+	assert.Equal(t, initialFakeTime, *info.Sources[0].LastUpdated, "LastUpdated should be the time of the last successful fetch")
+	assert.False(t, info.Sources[0].Active)
+	assert.Equal(t, "secondary down", info.Sources[1].LastError)
+	assert.NotNil(t, info.Sources[1].LastUpdated)
+
+	// The primary source is used again as soon as it recovers
+	primary.err = nil
+	assert.NoError(t, currencyConverter.Run())
+	assert.Equal(t, primaryRates, currencyConverter.Rates())
+	assertActiveSource(t, currencyConverter, "primary")
+}
+
+func TestChainedRateSourcesAllDown(t *testing.T) {
+	currencyConverter := NewChainedRateConverter(time.Second, []ChainedRateSource{
+		{RateSource: &fakeRateSource{source: "primary", err: errors.New("primary down")}},
+		{RateSource: &fakeRateSource{source: "secondary", err: errors.New("secondary down")}},
+	})
+
+	err := currencyConverter.Run()
+
+	assert.EqualError(t, err, "primary: primary down\nsecondary: secondary down")
+	assert.Equal(t, &ConstantRates{}, currencyConverter.Rates(), "Rates should return constant rates")
+
+	info := currencyConverter.GetInfo()
+	assert.Equal(t, "primary", info.Source(), "The source of the highest priority should be reported when none is active")
+	assert.Equal(t, RateSourcesInfo{
+		Sources: []RateSourceInfo{
+			{Source: "primary", LastError: "primary down"},
+			{Source: "secondary", LastError: "secondary down"},
+		},
+	}, info.AdditionalInfo())
+}
+
+func assertActiveSource(t *testing.T, currencyConverter *RateConverter, expected string) {
+	t.Helper()
+	info := currencyConverter.GetInfo()
+	assert.Equal(t, expected, info.Source(), "Source should be the active source")
+	assert.Equal(t, expected, info.AdditionalInfo().(RateSourcesInfo).ActiveSource, "ActiveSource should be the active source")
+}
+
+type fakeRateSource struct {
+	source string
+	rates  *Rates
+	err    error
+	calls  int
+}
+
+func (s *fakeRateSource) Source() string {
+	return s.source
+}
+
+func (s *fakeRateSource) Fetch(ctx context.Context) (*Rates, error) {
+	s.calls++
+	if s.err != nil {
+		return nil, s.err
+	}
+	return s.rates, nil
+}
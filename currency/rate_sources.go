@@ -0,0 +1,155 @@
+package currency
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/prebid/prebid-server/v4/errortypes"
+	"github.com/prebid/prebid-server/v4/logger"
+	"github.com/prebid/prebid-server/v4/util/jsonutil"
+)
+
+// RateSource fetches the currencies rates from a single source.
+type RateSource interface {
+	// Source identifies where the rates are fetched from, such as their URL
+	Source() string
+	// Fetch returns the current rates of the source
+	Fetch(ctx context.Context) (*Rates, error)
+}
+
+// ChainedRateSource is a source of the RateConverter along with the threshold after which its rates are stale.
+// The rates of the source are kept when it fails to be fetched, until they're stale.
+// A threshold <= 0 means that the rates never go stale.
+type ChainedRateSource struct {
+	RateSource
+	StaleRatesThreshold time.Duration
+}
+
+// NewPrebidRateSource returns a RateSource fetching the rates in the Prebid currency file format, as found
+// on https://cdn.jsdelivr.net/gh/prebid/currency-file@1/latest.json
+func NewPrebidRateSource(httpClient httpClient, url string) RateSource {
+	return &httpRateSource{
+		httpClient: httpClient,
+		url:        url,
+		parse:      parsePrebidRates,
+	}
+}
+
+// NewECBRateSource returns a RateSource fetching the rates in the format of the daily reference rates of the
+// European Central Bank, as found on https://www.ecb.europa.eu/stats/eurofxref/eurofxref-daily.xml
+func NewECBRateSource(httpClient httpClient, url string) RateSource {
+	return &httpRateSource{
+		httpClient: httpClient,
+		url:        url,
+		parse:      parseECBRates,
+	}
+}
+
+// NewFileRateSource returns a RateSource reading the rates in the Prebid currency file format from a local file.
+func NewFileRateSource(path string) RateSource {
+	return &fileRateSource{path: path}
+}
+
+type httpRateSource struct {
+	httpClient httpClient
+	url        string
+	parse      func(data []byte) (*Rates, error)
+}
+
+func (s *httpRateSource) Source() string {
+	return s.url
+}
+
+func (s *httpRateSource) Fetch(ctx context.Context) (*Rates, error) {
+	request, err := http.NewRequestWithContext(ctx, "GET", s.url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := s.httpClient.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		// read the entire response body to ensure full connection reuse if there's an
+		// invalid status code
+		if _, err := io.Copy(io.Discard, response.Body); err != nil {
+			logger.Errorf("error draining conversion rates response body: %v", err)
+		}
+		response.Body.Close()
+	}()
+
+	if response.StatusCode >= 400 {
+		message := fmt.Sprintf("the currency rates request failed with status code %d", response.StatusCode)
+		return nil, &errortypes.BadServerResponse{Message: message}
+	}
+
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, fmt.Errorf("the currency rates request failed: %v", err)
+	}
+
+	return s.parse(body)
+}
+
+type fileRateSource struct {
+	path string
+}
+
+func (s *fileRateSource) Source() string {
+	return s.path
+}
+
+func (s *fileRateSource) Fetch(ctx context.Context) (*Rates, error) {
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		return nil, fmt.Errorf("the currency rates file failed to be read: %v", err)
+	}
+	return parsePrebidRates(data)
+}
+
+func parsePrebidRates(data []byte) (*Rates, error) {
+	rates := &Rates{}
+	if err := jsonutil.UnmarshalValid(data, rates); err != nil {
+		return nil, fmt.Errorf("the currency rates request failed to parse json: %v", err)
+	}
+	return rates, nil
+}
+
+// ecbEnvelope holds the daily reference rates of the European Central Bank, which are all based on the euro.
+type ecbEnvelope struct {
+	Cube struct {
+		Cube struct {
+			Rates []struct {
+				Currency string  `xml:"currency,attr"`
+				Rate     float64 `xml:"rate,attr"`
+			} `xml:"Cube"`
+		} `xml:"Cube"`
+	} `xml:"Cube"`
+}
+
+func parseECBRates(data []byte) (*Rates, error) {
+	var envelope ecbEnvelope
+	if err := xml.Unmarshal(data, &envelope); err != nil {
+		return nil, fmt.Errorf("the currency rates request failed to parse xml: %v", err)
+	}
+
+	conversions := make(map[string]float64, len(envelope.Cube.Cube.Rates))
+	for _, rate := range envelope.Cube.Cube.Rates {
+		if rate.Currency == "" || rate.Rate <= 0 {
+			continue
+		}
+		conversions[rate.Currency] = rate.Rate
+	}
+	if len(conversions) == 0 {
+		return nil, errors.New("the currency rates request returned no rates")
+	}
+
+	return NewRates(map[string]map[string]float64{"EUR": conversions}), nil
+}
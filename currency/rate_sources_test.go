@@ -0,0 +1,106 @@
+package currency
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestECBRateSource(t *testing.T) {
+	testCases := []struct {
+		description   string
+		giveResponse  string
+		giveStatus    int
+		wantRates     *Rates
+		wantErrString string
+	}{
+		{
+			description: "Daily reference rates",
+			giveResponse: `<?xml version="1.0" encoding="UTF-8"?>
+<gesmes:Envelope xmlns:gesmes="http://www.gesmes.org/xml/2002-08-01" xmlns="http://www.ecb.int/vocabulary/2002-08-01/eurofxref">
+	<gesmes:subject>Reference rates</gesmes:subject>
+	<gesmes:Sender>
+		<gesmes:name>European Central Bank</gesmes:name>
+	</gesmes:Sender>
+	<Cube>
+		<Cube time='2024-01-05'>
+			<Cube currency='USD' rate='1.0921'/>
+			<Cube currency='GBP' rate='0.86038'/>
+		</Cube>
+	</Cube>
+</gesmes:Envelope>`,
+			giveStatus: http.StatusOK,
+			wantRates:  NewRates(map[string]map[string]float64{"EUR": {"USD": 1.0921, "GBP": 0.86038}}),
+		},
+		{
+			description:   "No rates",
+			giveResponse:  `<gesmes:Envelope xmlns:gesmes="http://www.gesmes.org/xml/2002-08-01"><Cube><Cube time='2024-01-05'></Cube></Cube></gesmes:Envelope>`,
+			giveStatus:    http.StatusOK,
+			wantErrString: "the currency rates request returned no rates",
+		},
+		{
+			description:   "Invalid XML",
+			giveResponse:  `{"conversions":{}}`,
+			giveStatus:    http.StatusOK,
+			wantErrString: "the currency rates request failed to parse xml: EOF",
+		},
+		{
+			description:   "Error status",
+			giveStatus:    http.StatusServiceUnavailable,
+			wantErrString: "the currency rates request failed with status code 503",
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.description, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				rw.WriteHeader(tt.giveStatus)
+				rw.Write([]byte(tt.giveResponse))
+			}))
+			defer server.Close()
+
+			source := NewECBRateSource(server.Client(), server.URL)
+			rates, err := source.Fetch(context.Background())
+
+			assert.Equal(t, server.URL, source.Source())
+			assert.Equal(t, tt.wantRates, rates)
+			if tt.wantErrString == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.wantErrString)
+			}
+		})
+	}
+}
+
+func TestECBRatesConversions(t *testing.T) {
+	rates := NewRates(map[string]map[string]float64{"EUR": {"USD": 1.25, "GBP": 0.5}})
+
+	rate, err := rates.GetRate("USD", "GBP")
+
+	assert.NoError(t, err)
+	assert.Equal(t, 0.4, rate, "The rates based on the euro should convert between the other currencies")
+}
+
+func TestFileRateSource(t *testing.T) {
+	directory := t.TempDir()
+	validPath := filepath.Join(directory, "rates.json")
+	invalidPath := filepath.Join(directory, "invalid.json")
+	assert.NoError(t, os.WriteFile(validPath, getMockRates(), 0644))
+	assert.NoError(t, os.WriteFile(invalidPath, []byte(`{`), 0644))
+
+	rates, err := NewFileRateSource(validPath).Fetch(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, NewRates(map[string]map[string]float64{"USD": {"GBP": 0.77208}, "GBP": {"USD": 1.2952}}), rates)
+
+	_, err = NewFileRateSource(invalidPath).Fetch(context.Background())
+	assert.Error(t, err, "An invalid file should return an error")
+
+	_, err = NewFileRateSource(filepath.Join(directory, "missing.json")).Fetch(context.Background())
+	assert.Error(t, err, "A missing file should return an error")
+}
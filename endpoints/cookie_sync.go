@@ -21,6 +21,9 @@ import (
 	"github.com/prebid/prebid-server/v4/config"
 	"github.com/prebid/prebid-server/v4/errortypes"
 	"github.com/prebid/prebid-server/v4/gdpr"
+	"github.com/prebid/prebid-server/v4/hooks"
+	"github.com/prebid/prebid-server/v4/hooks/hookexecution"
+	"github.com/prebid/prebid-server/v4/hooks/hookstage"
 	"github.com/prebid/prebid-server/v4/logger"
 	"github.com/prebid/prebid-server/v4/macros"
 	"github.com/prebid/prebid-server/v4/metrics"
@@ -34,6 +37,7 @@ import (
 	"github.com/prebid/prebid-server/v4/util/ptrutil"
 	stringutil "github.com/prebid/prebid-server/v4/util/stringutil"
 	"github.com/prebid/prebid-server/v4/util/timeutil"
+	"github.com/prebid/prebid-server/v4/util/uuidutil"
 )
 
 const receiveCookieDeprecation = "receive-cookie-deprecation"
@@ -60,7 +64,9 @@ func NewCookieSyncEndpoint(
 	metrics metrics.MetricsEngine,
 	analyticsRunner analytics.Runner,
 	accountsFetcher stored_requests.AccountFetcher,
-	bidders map[string]openrtb_ext.BidderName) HTTPRouterHandler {
+	bidders map[string]openrtb_ext.BidderName,
+	hookExecutionPlanBuilder hooks.ExecutionPlanBuilder,
+	uidStore usersync.UIDStore) HTTPRouterHandler {
 
 	bidderHashSet := make(map[string]struct{}, len(bidders))
 	for _, bidder := range bidders {
@@ -68,8 +74,9 @@ func NewCookieSyncEndpoint(
 	}
 
 	return &cookieSyncEndpoint{
-		chooser: usersync.NewChooser(syncersByBidder, bidderHashSet, config.BidderInfos),
-		config:  config,
+		chooser:         usersync.NewChooser(syncersByBidder, bidderHashSet, config.BidderInfos),
+		syncersByBidder: syncersByBidder,
+		config:          config,
 		privacyConfig: usersyncPrivacyConfig{
 			gdprConfig:             config.GDPR,
 			gdprPermissionsBuilder: gdprPermsBuilder,
@@ -77,21 +84,30 @@ func NewCookieSyncEndpoint(
 			ccpaEnforce:            config.CCPA.Enforce,
 			bidderHashSet:          bidderHashSet,
 		},
-		metrics:         metrics,
-		pbsAnalytics:    analyticsRunner,
-		accountsFetcher: accountsFetcher,
-		time:            &timeutil.RealTime{},
+		metrics:                  metrics,
+		pbsAnalytics:             analyticsRunner,
+		accountsFetcher:          accountsFetcher,
+		time:                     &timeutil.RealTime{},
+		hookExecutionPlanBuilder: hookExecutionPlanBuilder,
+		uidStore:                 uidStore,
+		encoder:                  usersync.NewEncoder(&config.HostCookie),
+		decoder:                  usersync.NewDecoder(&config.HostCookie, metrics),
 	}
 }
 
 type cookieSyncEndpoint struct {
-	chooser         usersync.Chooser
-	config          *config.Configuration
-	privacyConfig   usersyncPrivacyConfig
-	metrics         metrics.MetricsEngine
-	pbsAnalytics    analytics.Runner
-	accountsFetcher stored_requests.AccountFetcher
-	time            timeutil.Time
+	chooser                  usersync.Chooser
+	syncersByBidder          map[string]usersync.Syncer
+	config                   *config.Configuration
+	privacyConfig            usersyncPrivacyConfig
+	metrics                  metrics.MetricsEngine
+	pbsAnalytics             analytics.Runner
+	accountsFetcher          stored_requests.AccountFetcher
+	time                     timeutil.Time
+	hookExecutionPlanBuilder hooks.ExecutionPlanBuilder
+	uidStore                 usersync.UIDStore
+	encoder                  usersync.Encoder
+	decoder                  usersync.Decoder
 }
 
 func (c *cookieSyncEndpoint) Handle(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
@@ -99,27 +115,54 @@ func (c *cookieSyncEndpoint) Handle(w http.ResponseWriter, r *http.Request, _ ht
 	c.setCookieDeprecationHeader(w, r, account)
 	if err != nil {
 		c.writeParseRequestErrorMetrics(err)
-		c.handleError(w, err, http.StatusBadRequest)
+		c.handleError(w, err, http.StatusBadRequest, nil)
 		return
 	}
-	decoder := usersync.Base64Decoder{}
-
-	cookie := usersync.ReadCookie(r, decoder, &c.config.HostCookie)
+	cookie := usersync.ReadCookie(r, c.decoder, &c.config.HostCookie)
+	storedUIDsLoaded := true
+	if err := cookie.LoadStoredUIDs(r.Context(), c.uidStore); err != nil {
+		logger.Warnf("/cookie_sync failed to load the uids of the user: %v", err)
+		storedUIDsLoaded = false
+	}
 	usersync.SyncHostCookie(r, cookie, &c.config.HostCookie)
 
+	hookExecutor := hookexecution.NewHookExecutor(c.hookExecutionPlanBuilder, hookexecution.EndpointCookieSync, c.metrics)
+	hookExecutor.SetAccount(account)
+	hookExecutor.SetActivityControl(privacy.NewActivityControl(&account.Privacy))
+
+	hookPayload, rejectErr := hookExecutor.ExecuteCookieSyncRequestStage(hookstage.CookieSyncRequestPayload{
+		Request: r,
+		Bidders: request.Bidders,
+		Limit:   request.Limit,
+	})
+	if rejectErr != nil {
+		c.metrics.RecordCookieSync(metrics.CookieSyncOK)
+		c.handleResponse(w, request.SyncTypeFilter, cookie, privacyMacros, nil, nil, request.Debug, hookExecutor.GetOutcomes())
+		return
+	}
+	request.Bidders = hookPayload.Bidders
+	// the hooks can't raise the limit above the maximum of the account, nor disable it with a non positive one
+	if hookPayload.Limit > 0 {
+		request.Limit = min(hookPayload.Limit, getEffectiveMaxLimit(account.CookieSync.MaxLimit))
+	}
+
 	result := c.chooser.Choose(request, cookie)
 
 	switch result.Status {
 	case usersync.StatusBlockedByUserOptOut:
 		c.metrics.RecordCookieSync(metrics.CookieSyncOptOut)
-		c.handleError(w, errCookieSyncOptOut, http.StatusUnauthorized)
+		c.handleError(w, errCookieSyncOptOut, http.StatusUnauthorized, hookExecutor.GetOutcomes())
 	case usersync.StatusBlockedByPrivacy:
 		c.metrics.RecordCookieSync(metrics.CookieSyncGDPRHostCookieBlocked)
-		c.handleResponse(w, request.SyncTypeFilter, cookie, privacyMacros, nil, result.BiddersEvaluated, request.Debug)
+		c.handleResponse(w, request.SyncTypeFilter, cookie, privacyMacros, nil, result.BiddersEvaluated, request.Debug, hookExecutor.GetOutcomes())
 	case usersync.StatusOK:
 		c.metrics.RecordCookieSync(metrics.CookieSyncOK)
 		c.writeSyncerMetrics(result.BiddersEvaluated)
-		c.handleResponse(w, request.SyncTypeFilter, cookie, privacyMacros, result.SyncersChosen, result.BiddersEvaluated, request.Debug)
+		// the cookie can't be written without the stored UIDs, which would be lost otherwise
+		if request.Cooldown.Enabled() && len(result.SyncersChosen) > 0 && storedUIDsLoaded {
+			c.writeSyncAttempts(w, r, cookie, result.SyncersChosen)
+		}
+		c.handleResponse(w, request.SyncTypeFilter, cookie, privacyMacros, result.SyncersChosen, result.BiddersEvaluated, request.Debug, hookExecutor.GetOutcomes())
 	}
 }
 
@@ -146,24 +189,11 @@ func (c *cookieSyncEndpoint) parseRequest(r *http.Request) (usersync.Request, ma
 	request = c.setLimit(request, account.CookieSync)
 	request = c.setCooperativeSync(request, account.CookieSync)
 
-	privacyMacros, gdprSignal, privacyPolicies, err := extractPrivacyPolicies(request, c.privacyConfig.gdprConfig.DefaultValue)
+	syncPrivacy, privacyMacros, err := c.privacyConfig.buildPrivacy(request, account)
 	if err != nil {
 		return usersync.Request{}, macros.UserSyncPrivacy{}, account, err
 	}
 
-	ccpaParsedPolicy := ccpa.ParsedPolicy{}
-	if request.USPrivacy != "" {
-		parsedPolicy, err := ccpa.Policy{Consent: request.USPrivacy}.Parse(c.privacyConfig.bidderHashSet)
-		if err != nil {
-			privacyMacros.USPrivacy = ""
-		}
-		if c.privacyConfig.ccpaEnforce {
-			ccpaParsedPolicy = parsedPolicy
-		}
-	}
-
-	activityControl := privacy.NewActivityControl(&account.Privacy)
-
 	syncTypeFilter, err := parseTypeFilter(request.FilterSettings)
 	if err != nil {
 		return usersync.Request{}, macros.UserSyncPrivacy{}, account, err
@@ -172,14 +202,6 @@ func (c *cookieSyncEndpoint) parseRequest(r *http.Request) (usersync.Request, ma
 	disabledIFrameBidders := mergeDisabledIFrameBidders(c.config.CookieSync.DisabledIFrameBidders, account.CookieSync.DisabledIFrameBidders)
 	syncTypeFilter = applyDisabledIFrameBidders(syncTypeFilter, disabledIFrameBidders)
 
-	gdprRequestInfo := gdpr.RequestInfo{
-		Consent:    privacyMacros.GDPRConsent,
-		GDPRSignal: gdprSignal,
-	}
-
-	tcf2Cfg := c.privacyConfig.tcf2ConfigBuilder(c.privacyConfig.gdprConfig.TCF2, account.GDPR)
-	gdprPerms := c.privacyConfig.gdprPermissionsBuilder(tcf2Cfg, gdprRequestInfo)
-
 	limit := math.MaxInt
 	if request.Limit != nil {
 		limit = *request.Limit
@@ -192,21 +214,57 @@ func (c *cookieSyncEndpoint) parseRequest(r *http.Request) (usersync.Request, ma
 			PriorityGroups:     c.findPriorityGroups(account.CookieSync),
 			PriorityGroupsOnly: ptrutil.ValueOrDefault(account.CookieSync.PriorityGroupsOnly),
 		},
-		Debug: request.Debug,
-		Limit: limit,
-		Privacy: usersyncPrivacy{
-			gdprPermissions:  gdprPerms,
-			ccpaParsedPolicy: ccpaParsedPolicy,
-			activityControl:  activityControl,
-			activityRequest:  privacy.NewRequestFromPolicies(privacyPolicies),
-			gdprSignal:       gdprSignal,
-		},
+		Debug:          request.Debug,
+		Limit:          limit,
+		Privacy:        syncPrivacy,
 		SyncTypeFilter: syncTypeFilter,
 		GPPSID:         request.GPPSID,
+		Cooldown: usersync.Cooldown{
+			Duration:            time.Duration(ptrutil.ValueOrDefault(account.CookieSync.CooldownSec)) * time.Second,
+			MaxDailySyncs:       ptrutil.ValueOrDefault(account.CookieSync.MaxDailySyncs),
+			BidderMaxDailySyncs: account.CookieSync.BidderMaxDailySyncs,
+		},
 	}
 	return rx, privacyMacros, account, nil
 }
 
+// buildPrivacy returns the privacy policies of the request, as enforced for the account, along with the
+// privacy macros of the sync urls.
+func (c usersyncPrivacyConfig) buildPrivacy(request cookieSyncRequest, account *config.Account) (usersyncPrivacy, macros.UserSyncPrivacy, error) {
+	privacyMacros, gdprSignal, privacyPolicies, err := extractPrivacyPolicies(request, c.gdprConfig.DefaultValue)
+	if err != nil {
+		return usersyncPrivacy{}, macros.UserSyncPrivacy{}, err
+	}
+
+	ccpaParsedPolicy := ccpa.ParsedPolicy{}
+	if request.USPrivacy != "" {
+		parsedPolicy, err := ccpa.Policy{Consent: request.USPrivacy}.Parse(c.bidderHashSet)
+		if err != nil {
+			privacyMacros.USPrivacy = ""
+		}
+		if c.ccpaEnforce {
+			ccpaParsedPolicy = parsedPolicy
+		}
+	}
+
+	gdprRequestInfo := gdpr.RequestInfo{
+		Consent:    privacyMacros.GDPRConsent,
+		GDPRSignal: gdprSignal,
+	}
+
+	tcf2Cfg := c.tcf2ConfigBuilder(c.gdprConfig.TCF2, account.GDPR)
+	gdprPerms := c.gdprPermissionsBuilder(tcf2Cfg, gdprRequestInfo)
+
+	syncPrivacy := usersyncPrivacy{
+		gdprPermissions:  gdprPerms,
+		ccpaParsedPolicy: ccpaParsedPolicy,
+		activityControl:  privacy.NewActivityControl(&account.Privacy),
+		activityRequest:  privacy.NewRequestFromPolicies(privacyPolicies),
+		gdprSignal:       gdprSignal,
+	}
+	return syncPrivacy, privacyMacros, nil
+}
+
 func extractPrivacyPolicies(request cookieSyncRequest, usersyncDefaultGDPRValue string) (macros.UserSyncPrivacy, gdpr.Signal, privacy.Policies, error) {
 	// GDPR
 	gppSID, err := stringutil.StrToInt8Slice(request.GPPSID)
@@ -259,6 +317,7 @@ func extractPrivacyPolicies(request cookieSyncRequest, usersyncDefaultGDPRValue
 
 	privacyPolicies := privacy.Policies{
 		GPPSID: gppSID,
+		GPP:    gpp,
 	}
 
 	return privacyMacros, gdprSignal, privacyPolicies, nil
@@ -460,12 +519,13 @@ func parseBidderFilter(filter *cookieSyncRequestFilter) (usersync.BidderFilter,
 	}
 }
 
-func (c *cookieSyncEndpoint) handleError(w http.ResponseWriter, err error, httpStatus int) {
+func (c *cookieSyncEndpoint) handleError(w http.ResponseWriter, err error, httpStatus int, hookOutcomes []hookexecution.StageOutcome) {
 	http.Error(w, err.Error(), httpStatus)
 	c.pbsAnalytics.LogCookieSyncObject(&analytics.CookieSyncObject{
-		Status:       httpStatus,
-		Errors:       []error{err},
-		BidderStatus: []*analytics.CookieSyncBidder{},
+		Status:               httpStatus,
+		Errors:               []error{err},
+		BidderStatus:         []*analytics.CookieSyncBidder{},
+		HookExecutionOutcome: hookOutcomes,
 	})
 }
 
@@ -503,7 +563,32 @@ func (c *cookieSyncEndpoint) writeSyncerMetrics(biddersEvaluated []usersync.Bidd
 	}
 }
 
-func (c *cookieSyncEndpoint) handleResponse(w http.ResponseWriter, tf usersync.SyncTypeFilter, co *usersync.Cookie, m macros.UserSyncPrivacy, s []usersync.SyncerChoice, biddersEvaluated []usersync.BidderEvaluation, debug bool) {
+// writeSyncAttempts records the syncs offered to the user in the uids cookie, so that they aren't offered again
+// before the cooldown of the account is over.
+func (c *cookieSyncEndpoint) writeSyncAttempts(w http.ResponseWriter, r *http.Request, cookie *usersync.Cookie, syncersChosen []usersync.SyncerChoice) {
+	now := c.time.Now()
+	for _, syncerChoice := range syncersChosen {
+		cookie.RecordSyncAttempt(syncerChoice.Syncer.Key(), now)
+	}
+
+	var encodedCookie string
+	var err error
+	if c.uidStore != nil {
+		encodedCookie, err = cookie.PrepareCookieForStore(r.Context(), c.uidStore, c.encoder, uuidutil.UUIDRandomGenerator{})
+	} else {
+		// no uid is added here, so the ejector must not refuse to eject the last non priority uid
+		priorityEjector := &usersync.PriorityBidderEjector{PriorityGroups: c.config.UserSync.PriorityGroups, TieEjector: &usersync.OldestEjector{}, SyncersByBidder: c.syncersByBidder, IsSyncerPriority: true}
+		encodedCookie, err = cookie.PrepareCookieForWrite(&c.config.HostCookie, c.encoder, priorityEjector)
+	}
+	if err != nil {
+		logger.Warnf("/cookie_sync failed to record the sync attempts of the user: %v", err)
+		return
+	}
+
+	usersync.WriteCookie(w, encodedCookie, &c.config.HostCookie, siteCookieCheck(r.UserAgent()))
+}
+
+func (c *cookieSyncEndpoint) handleResponse(w http.ResponseWriter, tf usersync.SyncTypeFilter, co *usersync.Cookie, m macros.UserSyncPrivacy, s []usersync.SyncerChoice, biddersEvaluated []usersync.BidderEvaluation, debug bool, hookOutcomes []hookexecution.StageOutcome) {
 	status := "no_cookie"
 	if co.HasAnyLiveSyncs() {
 		status = "ok"
@@ -550,9 +635,18 @@ func (c *cookieSyncEndpoint) handleResponse(w http.ResponseWriter, tf usersync.S
 		response.Debug = debugInfo
 	}
 
+	var biddersInCooldown []string
+	for _, bidderEval := range biddersEvaluated {
+		if bidderEval.Status == usersync.StatusCooldown {
+			biddersInCooldown = append(biddersInCooldown, bidderEval.Bidder)
+		}
+	}
+
 	c.pbsAnalytics.LogCookieSyncObject(&analytics.CookieSyncObject{
-		Status:       http.StatusOK,
-		BidderStatus: mapBidderStatusToAnalytics(response.BidderStatus),
+		Status:               http.StatusOK,
+		BidderStatus:         mapBidderStatusToAnalytics(response.BidderStatus),
+		BiddersInCooldown:    biddersInCooldown,
+		HookExecutionOutcome: hookOutcomes,
 	})
 
 	w.Header().Set("Content-Type", "application/json; charset=utf-8")
@@ -621,6 +715,8 @@ func getDebugMessage(status usersync.Status) string {
 		return "Rejected by request filter"
 	case usersync.StatusBlockedByDisabledUsersync:
 		return "Sync disabled by config"
+	case usersync.StatusCooldown:
+		return "Sync attempted too recently"
 	}
 	return ""
 }
@@ -709,6 +805,11 @@ func (p usersyncPrivacy) ActivityAllowsUserSync(bidder string) bool {
 		p.activityRequest)
 }
 
+// allowsBidderSync returns true if the bidder is allowed to sync the user by every privacy policy.
+func (p usersyncPrivacy) allowsBidderSync(bidder string) bool {
+	return p.ActivityAllowsUserSync(bidder) && p.GDPRAllowsBidderSync(bidder) && p.CCPAAllowsBidderSync(bidder)
+}
+
 func (p usersyncPrivacy) GDPRInScope() bool {
 	return p.gdprSignal == gdpr.SignalYes
 }
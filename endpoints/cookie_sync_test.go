@@ -15,10 +15,14 @@ import (
 	"testing/iotest"
 	"time"
 
+	gpplib "github.com/prebid/go-gpp"
 	"github.com/prebid/prebid-server/v4/analytics"
 	"github.com/prebid/prebid-server/v4/config"
 	"github.com/prebid/prebid-server/v4/errortypes"
 	"github.com/prebid/prebid-server/v4/gdpr"
+	"github.com/prebid/prebid-server/v4/hooks"
+	"github.com/prebid/prebid-server/v4/hooks/hookexecution"
+	"github.com/prebid/prebid-server/v4/hooks/hookstage"
 	"github.com/prebid/prebid-server/v4/macros"
 	"github.com/prebid/prebid-server/v4/metrics"
 	"github.com/prebid/prebid-server/v4/openrtb_ext"
@@ -75,6 +79,8 @@ func TestNewCookieSyncEndpoint(t *testing.T) {
 		&analytics,
 		&fetcher,
 		bidders,
+		hooks.EmptyPlanBuilder{},
+		nil,
 	)
 	result := endpoint.(*cookieSyncEndpoint)
 
@@ -94,9 +100,10 @@ func TestNewCookieSyncEndpoint(t *testing.T) {
 			ccpaEnforce:            configCCPAEnforce,
 			bidderHashSet:          map[string]struct{}{"bidderA": {}, "bidderB": {}},
 		},
-		metrics:         &metrics,
-		pbsAnalytics:    &analytics,
-		accountsFetcher: &fetcher,
+		metrics:                  &metrics,
+		pbsAnalytics:             &analytics,
+		accountsFetcher:          &fetcher,
+		hookExecutionPlanBuilder: hooks.EmptyPlanBuilder{},
 	}
 
 	assert.IsType(t, &cookieSyncEndpoint{}, endpoint)
@@ -110,6 +117,8 @@ func TestNewCookieSyncEndpoint(t *testing.T) {
 	assert.Equal(t, expected.privacyConfig.gdprConfig, result.privacyConfig.gdprConfig)
 	assert.Equal(t, expected.privacyConfig.ccpaEnforce, result.privacyConfig.ccpaEnforce)
 	assert.Equal(t, expected.privacyConfig.bidderHashSet, result.privacyConfig.bidderHashSet)
+	assert.Equal(t, usersync.Base64Encoder{}, result.encoder)
+	assert.Equal(t, usersync.Base64Decoder{}, result.decoder)
 }
 
 func TestCookieSyncHandle(t *testing.T) {
@@ -161,6 +170,7 @@ func TestCookieSyncHandle(t *testing.T) {
 							UsersyncInfo: &analytics.UsersyncInfo{URL: "aURL", Type: "redirect"},
 						},
 					},
+					HookExecutionOutcome: []hookexecution.StageOutcome{},
 				}
 				a.On("LogCookieSyncObject", &expected).Once()
 			},
@@ -193,6 +203,7 @@ func TestCookieSyncHandle(t *testing.T) {
 							UsersyncInfo: &analytics.UsersyncInfo{URL: "aURL", Type: "redirect"},
 						},
 					},
+					HookExecutionOutcome: []hookexecution.StageOutcome{},
 				}
 				a.On("LogCookieSyncObject", &expected).Once()
 			},
@@ -236,9 +247,10 @@ func TestCookieSyncHandle(t *testing.T) {
 			},
 			setAnalyticsExpectations: func(a *MockAnalyticsRunner) {
 				expected := analytics.CookieSyncObject{
-					Status:       401,
-					Errors:       []error{errors.New("User has opted out")},
-					BidderStatus: []*analytics.CookieSyncBidder{},
+					Status:               401,
+					Errors:               []error{errors.New("User has opted out")},
+					BidderStatus:         []*analytics.CookieSyncBidder{},
+					HookExecutionOutcome: []hookexecution.StageOutcome{},
 				}
 				a.On("LogCookieSyncObject", &expected).Once()
 			},
@@ -259,9 +271,10 @@ func TestCookieSyncHandle(t *testing.T) {
 			},
 			setAnalyticsExpectations: func(a *MockAnalyticsRunner) {
 				expected := analytics.CookieSyncObject{
-					Status:       200,
-					Errors:       nil,
-					BidderStatus: []*analytics.CookieSyncBidder{},
+					Status:               200,
+					Errors:               nil,
+					BidderStatus:         []*analytics.CookieSyncBidder{},
+					HookExecutionOutcome: []hookexecution.StageOutcome{},
 				}
 				a.On("LogCookieSyncObject", &expected).Once()
 			},
@@ -294,6 +307,7 @@ func TestCookieSyncHandle(t *testing.T) {
 							UsersyncInfo: &analytics.UsersyncInfo{URL: "aURL", Type: "redirect"},
 						},
 					},
+					HookExecutionOutcome: []hookexecution.StageOutcome{},
 				}
 				a.On("LogCookieSyncObject", &expected).Once()
 			},
@@ -330,6 +344,7 @@ func TestCookieSyncHandle(t *testing.T) {
 							UsersyncInfo: &analytics.UsersyncInfo{URL: "aURL", Type: "redirect"},
 						},
 					},
+					HookExecutionOutcome: []hookexecution.StageOutcome{},
 				}
 				a.On("LogCookieSyncObject", &expected).Once()
 			},
@@ -377,10 +392,13 @@ func TestCookieSyncHandle(t *testing.T) {
 				tcf2ConfigBuilder:      tcf2ConfigBuilder,
 				ccpaEnforce:            true,
 			},
-			metrics:         &mockMetrics,
-			pbsAnalytics:    &mockAnalytics,
-			accountsFetcher: &fakeAccountFetcher,
-			time:            &fakeTime{time: time.Date(2024, 2, 22, 9, 42, 4, 13, time.UTC)},
+			metrics:                  &mockMetrics,
+			pbsAnalytics:             &mockAnalytics,
+			accountsFetcher:          &fakeAccountFetcher,
+			time:                     &fakeTime{time: time.Date(2024, 2, 22, 9, 42, 4, 13, time.UTC)},
+			hookExecutionPlanBuilder: hooks.EmptyPlanBuilder{},
+			encoder:                  usersync.Base64Encoder{},
+			decoder:                  usersync.Base64Decoder{},
 		}
 		assert.NoError(t, endpoint.config.MarshalAccountDefaults())
 
@@ -403,6 +421,232 @@ func TestCookieSyncHandle(t *testing.T) {
 	}
 }
 
+func TestCookieSyncHandleCooldown(t *testing.T) {
+	syncTypeExpected := []usersync.SyncType{usersync.SyncTypeIFrame, usersync.SyncTypeRedirect}
+	syncer := MockSyncer{}
+	syncer.On("GetSync", syncTypeExpected, macros.UserSyncPrivacy{}).Return(usersync.Sync{URL: "aURL", Type: usersync.SyncTypeRedirect}, nil)
+	syncer.On("Key").Return("aSyncer")
+
+	chooser := &capturingChooser{
+		result: usersync.Result{
+			Status: usersync.StatusOK,
+			BiddersEvaluated: []usersync.BidderEvaluation{
+				{Bidder: "a", SyncerKey: "aSyncer", Status: usersync.StatusOK},
+				{Bidder: "b", SyncerKey: "bSyncer", Status: usersync.StatusCooldown},
+			},
+			SyncersChosen: []usersync.SyncerChoice{{Bidder: "a", Syncer: &syncer}},
+		},
+	}
+
+	mockMetrics := metrics.MetricsEngineMock{}
+	mockMetrics.On("RecordCookieSync", metrics.CookieSyncOK).Once()
+	mockMetrics.On("RecordSyncerRequest", "aSyncer", metrics.SyncerCookieSyncOK).Once()
+
+	mockAnalytics := MockAnalyticsRunner{}
+	mockAnalytics.On("LogCookieSyncObject", &analytics.CookieSyncObject{
+		Status: 200,
+		BidderStatus: []*analytics.CookieSyncBidder{
+			{BidderCode: "a", NoCookie: true, UsersyncInfo: &analytics.UsersyncInfo{URL: "aURL", Type: "redirect"}},
+		},
+		BiddersInCooldown:    []string{"b"},
+		HookExecutionOutcome: []hookexecution.StageOutcome{},
+	}).Once()
+
+	now := time.Date(2024, 2, 22, 9, 42, 4, 0, time.UTC)
+	endpoint := cookieSyncEndpoint{
+		chooser: chooser,
+		config: &config.Configuration{
+			AccountDefaults: config.Account{Disabled: false},
+		},
+		privacyConfig: usersyncPrivacyConfig{
+			gdprConfig: config.GDPR{
+				Enabled:      true,
+				DefaultValue: "0",
+			},
+			gdprPermissionsBuilder: fakePermissionsBuilder{permissions: &fakePermissions{}}.Builder,
+			tcf2ConfigBuilder:      fakeTCF2ConfigBuilder{cfg: gdpr.NewTCF2Config(config.TCF2{}, config.AccountGDPR{})}.Builder,
+		},
+		metrics:      &mockMetrics,
+		pbsAnalytics: &mockAnalytics,
+		accountsFetcher: &FakeAccountsFetcher{AccountData: map[string]json.RawMessage{
+			"testAccount": json.RawMessage(`{"id":"1","cookie_sync":{"cooldown_sec":3600,"max_daily_syncs":5,"bidder_max_daily_syncs":{"b":1}}}`),
+		}},
+		time:                     &fakeTime{time: now},
+		hookExecutionPlanBuilder: hooks.EmptyPlanBuilder{},
+		encoder:                  usersync.Base64Encoder{},
+		decoder:                  usersync.Base64Decoder{},
+	}
+	assert.NoError(t, endpoint.config.MarshalAccountDefaults())
+
+	writer := httptest.NewRecorder()
+	endpoint.Handle(writer, httptest.NewRequest("POST", "/cookiesync", strings.NewReader(`{"account":"testAccount","debug":true}`)), nil)
+
+	assert.Equal(t, http.StatusOK, writer.Code)
+	assert.Equal(t, `{"status":"no_cookie","bidder_status":[`+
+		`{"bidder":"a","no_cookie":true,"usersync":{"url":"aURL","type":"redirect"}}`+
+		`],"debug":[{"bidder":"b","error":"Sync attempted too recently"}]}`+"\n", writer.Body.String())
+	assert.Equal(t, usersync.Cooldown{Duration: time.Hour, MaxDailySyncs: 5, BidderMaxDailySyncs: map[string]int{"b": 1}}, chooser.request.Cooldown)
+
+	// the sync attempt is recorded in the cookie
+	request := httptest.NewRequest("POST", "/cookiesync", nil)
+	request.AddCookie(writer.Result().Cookies()[0])
+	cookie := usersync.ReadCookie(request, usersync.Base64Decoder{}, &config.HostCookie{})
+	assert.False(t, cookie.SyncAttemptAllowed("aSyncer", time.Hour, 0, now.Add(time.Minute)))
+	assert.True(t, cookie.SyncAttemptAllowed("bSyncer", time.Hour, 0, now.Add(time.Minute)))
+
+	mockMetrics.AssertExpectations(t)
+	mockAnalytics.AssertExpectations(t)
+}
+
+func TestCookieSyncWriteSyncAttemptsPriorityEjection(t *testing.T) {
+	aSyncer := MockSyncer{}
+	aSyncer.On("Key").Return("aSyncer")
+	bSyncer := MockSyncer{}
+	bSyncer.On("Key").Return("bSyncer")
+
+	// a cookie with a single uid fits, but not with two
+	single := usersync.NewCookie()
+	assert.NoError(t, single.Sync("aSyncer", "aUID"))
+	encodedSingle, err := single.PrepareCookieForWrite(&config.HostCookie{}, usersync.Base64Encoder{}, &usersync.OldestEjector{})
+	assert.NoError(t, err)
+	singleSize := len((&http.Cookie{Name: "uids", Value: encodedSingle, Expires: time.Now(), Path: "/"}).String())
+
+	// aSyncer is synced first, so it's the oldest uid and the one the oldest ejector would drop
+	cookie := usersync.NewCookie()
+	assert.NoError(t, cookie.Sync("aSyncer", "aUID"))
+	assert.NoError(t, cookie.Sync("bSyncer", "bUID"))
+
+	endpoint := cookieSyncEndpoint{
+		syncersByBidder: map[string]usersync.Syncer{"a": &aSyncer, "b": &bSyncer},
+		config: &config.Configuration{
+			HostCookie: config.HostCookie{MaxCookieSizeBytes: singleSize + 5},
+			UserSync:   config.UserSync{PriorityGroups: [][]string{{"a"}}},
+		},
+		time:    &fakeTime{time: time.Date(2024, 2, 22, 9, 42, 4, 0, time.UTC)},
+		encoder: usersync.Base64Encoder{},
+	}
+
+	writer := httptest.NewRecorder()
+	endpoint.writeSyncAttempts(writer, httptest.NewRequest("POST", "/cookiesync", nil), cookie, nil)
+
+	request := httptest.NewRequest("POST", "/cookiesync", nil)
+	request.AddCookie(writer.Result().Cookies()[0])
+	written := usersync.ReadCookie(request, usersync.Base64Decoder{}, &config.HostCookie{})
+	assert.True(t, written.HasLiveSync("aSyncer"), "priority uid should be kept")
+	assert.False(t, written.HasLiveSync("bSyncer"), "non priority uid should be ejected")
+}
+
+func TestCookieSyncHandleHooks(t *testing.T) {
+	testCases := []struct {
+		description        string
+		givenHook          hookstage.CookieSyncRequest
+		expectedBidders    []string
+		expectedLimit      int
+		expectChooserCall  bool
+		expectedBody       string
+		expectedSyncStatus metrics.CookieSyncStatus
+	}{
+		{
+			description:        "hook-updates-bidders",
+			givenHook:          mockCookieSyncHook{bidders: []string{"appnexus"}},
+			expectedBidders:    []string{"appnexus"},
+			expectedLimit:      2,
+			expectChooserCall:  true,
+			expectedBody:       `{"status":"no_cookie","bidder_status":[]}` + "\n",
+			expectedSyncStatus: metrics.CookieSyncOK,
+		},
+		{
+			description:        "hook-limit-capped-by-account-max-limit",
+			givenHook:          mockCookieSyncHook{bidders: []string{"appnexus"}, limit: ptrutil.ToPtr(10)},
+			expectedBidders:    []string{"appnexus"},
+			expectedLimit:      3,
+			expectChooserCall:  true,
+			expectedBody:       `{"status":"no_cookie","bidder_status":[]}` + "\n",
+			expectedSyncStatus: metrics.CookieSyncOK,
+		},
+		{
+			description:        "hook-zero-limit-ignored",
+			givenHook:          mockCookieSyncHook{bidders: []string{"appnexus"}, limit: ptrutil.ToPtr(0)},
+			expectedBidders:    []string{"appnexus"},
+			expectedLimit:      2,
+			expectChooserCall:  true,
+			expectedBody:       `{"status":"no_cookie","bidder_status":[]}` + "\n",
+			expectedSyncStatus: metrics.CookieSyncOK,
+		},
+		{
+			description:        "hook-negative-limit-ignored",
+			givenHook:          mockCookieSyncHook{bidders: []string{"appnexus"}, limit: ptrutil.ToPtr(-1)},
+			expectedBidders:    []string{"appnexus"},
+			expectedLimit:      2,
+			expectChooserCall:  true,
+			expectedBody:       `{"status":"no_cookie","bidder_status":[]}` + "\n",
+			expectedSyncStatus: metrics.CookieSyncOK,
+		},
+		{
+			description:        "hook-rejects-request",
+			givenHook:          mockCookieSyncHook{reject: true},
+			expectChooserCall:  false,
+			expectedBody:       `{"status":"no_cookie","bidder_status":[]}` + "\n",
+			expectedSyncStatus: metrics.CookieSyncOK,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			mockMetrics := metrics.MetricsEngineMock{}
+			mockMetrics.On("RecordCookieSync", test.expectedSyncStatus).Once()
+			mockMetrics.On("RecordModuleCalled", mock.Anything, mock.Anything).Maybe()
+			mockMetrics.On("RecordModuleSuccessUpdated", mock.Anything).Maybe()
+			mockMetrics.On("RecordModuleSuccessRejected", mock.Anything).Maybe()
+
+			mockAnalytics := MockAnalyticsRunner{}
+			mockAnalytics.On("LogCookieSyncObject", mock.MatchedBy(func(object *analytics.CookieSyncObject) bool {
+				return len(object.HookExecutionOutcome) == 1
+			})).Once()
+
+			chooser := &capturingChooser{result: usersync.Result{Status: usersync.StatusOK}}
+
+			endpoint := cookieSyncEndpoint{
+				chooser: chooser,
+				config:  &config.Configuration{},
+				privacyConfig: usersyncPrivacyConfig{
+					gdprConfig:             config.GDPR{Enabled: true, DefaultValue: "0"},
+					gdprPermissionsBuilder: fakePermissionsBuilder{permissions: &fakePermissions{}}.Builder,
+					tcf2ConfigBuilder:      fakeTCF2ConfigBuilder{cfg: gdpr.NewTCF2Config(config.TCF2{}, config.AccountGDPR{})}.Builder,
+				},
+				metrics:      &mockMetrics,
+				pbsAnalytics: &mockAnalytics,
+				accountsFetcher: &FakeAccountsFetcher{AccountData: map[string]json.RawMessage{
+					"testAccount": json.RawMessage(`{"id":"1","cookie_sync":{"max_limit":3}}`),
+				}},
+				time:                     &fakeTime{time: time.Date(2024, 2, 22, 9, 42, 4, 13, time.UTC)},
+				hookExecutionPlanBuilder: mockCookieSyncPlanBuilder{hook: test.givenHook},
+				encoder:                  usersync.Base64Encoder{},
+				decoder:                  usersync.Base64Decoder{},
+			}
+			assert.NoError(t, endpoint.config.MarshalAccountDefaults())
+
+			request := httptest.NewRequest("POST", "/cookiesync", strings.NewReader(`{"account":"testAccount","bidders":["rubicon","pubmatic"],"limit":2}`))
+			writer := httptest.NewRecorder()
+
+			endpoint.Handle(writer, request, nil)
+
+			assert.Equal(t, http.StatusOK, writer.Code)
+			assert.Equal(t, test.expectedBody, writer.Body.String())
+			if test.expectChooserCall {
+				if assert.NotNil(t, chooser.request) {
+					assert.Equal(t, test.expectedBidders, chooser.request.Bidders)
+					assert.Equal(t, test.expectedLimit, chooser.request.Limit)
+				}
+			} else {
+				assert.Nil(t, chooser.request)
+			}
+			mockMetrics.AssertExpectations(t)
+			mockAnalytics.AssertExpectations(t)
+		})
+	}
+}
+
 func TestExtractGDPRSignal(t *testing.T) {
 	type testInput struct {
 		requestGDPR *int
@@ -490,6 +734,8 @@ func TestExtractGDPRSignal(t *testing.T) {
 }
 
 func TestExtractPrivacyPolicies(t *testing.T) {
+	expectedGPP, _ := gpplib.Parse("DBACNYA~CPXxRfAPXxRfAAfKABENB-CgAAAAAAAAAAYgAAAAAAAA~1YNN")
+
 	type testInput struct {
 		request                  cookieSyncRequest
 		usersyncDefaultGDPRValue string
@@ -566,7 +812,7 @@ func TestExtractPrivacyPolicies(t *testing.T) {
 					GPPSID:      "6",
 				},
 				gdprSignal: gdpr.SignalNo,
-				policies:   privacy.Policies{GPPSID: []int8{6}},
+				policies:   privacy.Policies{GPPSID: []int8{6}, GPP: expectedGPP},
 				err:        nil,
 			},
 		},
@@ -584,6 +830,7 @@ func TestExtractPrivacyPolicies(t *testing.T) {
 func TestCookieSyncParseRequest(t *testing.T) {
 	expectedCCPAParsedPolicy, _ := ccpa.Policy{Consent: "1NYN"}.Parse(map[string]struct{}{})
 	emptyActivityPoliciesRequest := privacy.NewRequestFromPolicies(privacy.Policies{})
+	expectedGPP, _ := gpplib.Parse("DBABMA~CPXxRfAPXxRfAAfKABENB-CgAAAAAAAAAAYgAAAAAAAA")
 
 	testCases := []struct {
 		description              string
@@ -637,7 +884,7 @@ func TestCookieSyncParseRequest(t *testing.T) {
 				Privacy: usersyncPrivacy{
 					gdprPermissions:  &fakePermissions{},
 					ccpaParsedPolicy: expectedCCPAParsedPolicy,
-					activityRequest:  privacy.NewRequestFromPolicies(privacy.Policies{GPPSID: []int8{2}}),
+					activityRequest:  privacy.NewRequestFromPolicies(privacy.Policies{GPPSID: []int8{2}, GPP: expectedGPP}),
 					gdprSignal:       1,
 				},
 				SyncTypeFilter: usersync.SyncTypeFilter{
@@ -1555,7 +1802,7 @@ func TestWriteParseRequestErrorMetrics(t *testing.T) {
 	writer := httptest.NewRecorder()
 
 	endpoint := cookieSyncEndpoint{pbsAnalytics: &mockAnalytics}
-	endpoint.handleError(writer, err, 418)
+	endpoint.handleError(writer, err, 418, nil)
 
 	assert.Equal(t, writer.Code, 418)
 	assert.Equal(t, writer.Body.String(), "anyError\n")
@@ -1960,7 +2207,7 @@ func TestCookieSyncHandleError(t *testing.T) {
 	writer := httptest.NewRecorder()
 
 	endpoint := cookieSyncEndpoint{pbsAnalytics: &mockAnalytics}
-	endpoint.handleError(writer, err, 418)
+	endpoint.handleError(writer, err, 418, nil)
 
 	assert.Equal(t, writer.Code, 418)
 	assert.Equal(t, writer.Body.String(), "anyError\n")
@@ -2187,7 +2434,7 @@ func TestCookieSyncHandleResponse(t *testing.T) {
 		} else {
 			bidderEval = []usersync.BidderEvaluation{}
 		}
-		endpoint.handleResponse(writer, syncTypeFilter, cookie, privacyMacros, test.givenSyncersChosen, bidderEval, test.givenDebug)
+		endpoint.handleResponse(writer, syncTypeFilter, cookie, privacyMacros, test.givenSyncersChosen, bidderEval, test.givenDebug, nil)
 
 		if assert.Equal(t, writer.Code, http.StatusOK, test.description+":http_status") {
 			assert.Equal(t, writer.Header().Get("Content-Type"), "application/json; charset=utf-8", test.description+":http_header")
@@ -2516,6 +2763,53 @@ func (c FakeChooser) Choose(request usersync.Request, cookie *usersync.Cookie) u
 	return c.Result
 }
 
+type capturingChooser struct {
+	result  usersync.Result
+	request *usersync.Request
+}
+
+func (c *capturingChooser) Choose(request usersync.Request, cookie *usersync.Cookie) usersync.Result {
+	c.request = &request
+	return c.result
+}
+
+type mockCookieSyncPlanBuilder struct {
+	hooks.EmptyPlanBuilder
+	hook hookstage.CookieSyncRequest
+}
+
+func (m mockCookieSyncPlanBuilder) PlanForCookieSyncRequestStage(_ string, _ *config.Account) hooks.Plan[hookstage.CookieSyncRequest] {
+	return hooks.Plan[hookstage.CookieSyncRequest]{
+		{
+			Timeout: 5 * time.Millisecond,
+			Hooks:   []hooks.HookWrapper[hookstage.CookieSyncRequest]{{Module: "foobar", Code: "foo", Hook: m.hook}},
+		},
+	}
+}
+
+type mockCookieSyncHook struct {
+	bidders []string
+	limit   *int
+	reject  bool
+}
+
+func (m mockCookieSyncHook) HandleCookieSyncRequestHook(_ context.Context, _ hookstage.ModuleInvocationContext, _ hookstage.CookieSyncRequestPayload) (hookstage.HookResult[hookstage.CookieSyncRequestPayload], error) {
+	if m.reject {
+		return hookstage.HookResult[hookstage.CookieSyncRequestPayload]{Reject: true}, nil
+	}
+
+	c := hookstage.ChangeSet[hookstage.CookieSyncRequestPayload]{}
+	c.AddMutation(func(payload hookstage.CookieSyncRequestPayload) (hookstage.CookieSyncRequestPayload, error) {
+		payload.Bidders = m.bidders
+		if m.limit != nil {
+			payload.Limit = *m.limit
+		}
+		return payload, nil
+	}, hookstage.MutationUpdate, "bidders")
+
+	return hookstage.HookResult[hookstage.CookieSyncRequestPayload]{ChangeSet: c}, nil
+}
+
 type MockSyncer struct {
 	mock.Mock
 }
@@ -3043,6 +3337,8 @@ func TestCookieSyncPriorityGroupsIntegration(t *testing.T) {
 					},
 				},
 				bidders,
+				hooks.EmptyPlanBuilder{},
+				nil,
 			)
 			// Create test request
 			request := httptest.NewRequest("POST", "/cookie_sync", strings.NewReader(tc.givenRequestBody))
@@ -3221,6 +3517,8 @@ func TestCookieSyncPriorityGroupsEdgeCases(t *testing.T) {
 					},
 				},
 				bidders,
+				hooks.EmptyPlanBuilder{},
+				nil,
 			)
 
 			// Create test request
@@ -57,9 +57,10 @@ func newCurrencyRatesInfo(rateConverter rateConverter, fetchingInterval time.Dur
 
 // NewCurrencyRatesEndpoint returns current currency rates applied by the PBS server.
 func NewCurrencyRatesEndpoint(rateConverter rateConverter, fetchingInterval time.Duration) http.HandlerFunc {
-	currencyRateInfo := newCurrencyRatesInfo(rateConverter, fetchingInterval)
-
 	return func(w http.ResponseWriter, _ *http.Request) {
+		// the info is read on every request since the rates and their active source change over time
+		currencyRateInfo := newCurrencyRatesInfo(rateConverter, fetchingInterval)
+
 		jsonOutput, err := jsonutil.Marshal(currencyRateInfo)
 		if err != nil {
 			logger.Errorf("/currency/rates Critical error when trying to marshal currencyRateInfo: %v", err)
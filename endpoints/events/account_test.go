@@ -11,6 +11,7 @@ import (
 	"github.com/julienschmidt/httprouter"
 	"github.com/prebid/prebid-server/v4/config"
 	"github.com/prebid/prebid-server/v4/errortypes"
+	"github.com/prebid/prebid-server/v4/hooks"
 	"github.com/prebid/prebid-server/v4/metrics"
 	"github.com/prebid/prebid-server/v4/stored_requests"
 	"github.com/stretchr/testify/assert"
@@ -136,7 +137,7 @@ func event(cfg *config.Configuration, fetcher stored_requests.AccountFetcher, ac
 		r    *http.Request
 	}{
 		name: "event",
-		h:    NewEventEndpoint(cfg, fetcher, nil, &metrics.MetricsEngineMock{}),
+		h:    NewEventEndpoint(cfg, fetcher, nil, &metrics.MetricsEngineMock{}, hooks.EmptyPlanBuilder{}),
 		r:    httptest.NewRequest("GET", "/event?t=win&b=test&ts=1234&f=b&x=1&a="+accountID, strings.NewReader("")),
 	}
 }
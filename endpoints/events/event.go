@@ -17,6 +17,9 @@ import (
 	"github.com/prebid/prebid-server/v4/analytics"
 	"github.com/prebid/prebid-server/v4/config"
 	"github.com/prebid/prebid-server/v4/errortypes"
+	"github.com/prebid/prebid-server/v4/hooks"
+	"github.com/prebid/prebid-server/v4/hooks/hookexecution"
+	"github.com/prebid/prebid-server/v4/hooks/hookstage"
 	"github.com/prebid/prebid-server/v4/metrics"
 	"github.com/prebid/prebid-server/v4/privacy"
 	"github.com/prebid/prebid-server/v4/stored_requests"
@@ -42,20 +45,22 @@ const (
 const integrationParamMaxLength = 64
 
 type eventEndpoint struct {
-	Accounts      stored_requests.AccountFetcher
-	Analytics     analytics.Runner
-	Cfg           *config.Configuration
-	TrackingPixel *httputil.Pixel
-	MetricsEngine metrics.MetricsEngine
+	Accounts                 stored_requests.AccountFetcher
+	Analytics                analytics.Runner
+	Cfg                      *config.Configuration
+	TrackingPixel            *httputil.Pixel
+	MetricsEngine            metrics.MetricsEngine
+	HookExecutionPlanBuilder hooks.ExecutionPlanBuilder
 }
 
-func NewEventEndpoint(cfg *config.Configuration, accounts stored_requests.AccountFetcher, analytics analytics.Runner, me metrics.MetricsEngine) httprouter.Handle {
+func NewEventEndpoint(cfg *config.Configuration, accounts stored_requests.AccountFetcher, analytics analytics.Runner, me metrics.MetricsEngine, hookExecutionPlanBuilder hooks.ExecutionPlanBuilder) httprouter.Handle {
 	ee := &eventEndpoint{
-		Accounts:      accounts,
-		Analytics:     analytics,
-		Cfg:           cfg,
-		TrackingPixel: &httputil.Pixel1x1PNG,
-		MetricsEngine: me,
+		Accounts:                 accounts,
+		Analytics:                analytics,
+		Cfg:                      cfg,
+		TrackingPixel:            &httputil.Pixel1x1PNG,
+		MetricsEngine:            me,
+		HookExecutionPlanBuilder: hookExecutionPlanBuilder,
 	}
 
 	return ee.Handle
@@ -119,10 +124,18 @@ func (e *eventEndpoint) Handle(w http.ResponseWriter, r *http.Request, _ httprou
 
 	activities := privacy.NewActivityControl(&account.Privacy)
 
+	hookExecutor := hookexecution.NewHookExecutor(e.HookExecutionPlanBuilder, hookexecution.EndpointEvent, e.MetricsEngine)
+	hookExecutor.SetAccount(account)
+	hookExecutor.SetActivityControl(activities)
+
+	hookPayload := hookExecutor.ExecuteEventNotificationStage(eventRequestToHookPayload(r, eventRequest))
+	applyHookPayloadToEventRequest(hookPayload, eventRequest)
+
 	// handle notification event
 	e.Analytics.LogNotificationEventObject(&analytics.NotificationEvent{
-		Request: eventRequest,
-		Account: account,
+		Request:              eventRequest,
+		Account:              account,
+		HookExecutionOutcome: hookExecutor.GetOutcomes(),
 	}, activities)
 
 	// Add tracking pixel if format == image
@@ -137,6 +150,31 @@ func (e *eventEndpoint) Handle(w http.ResponseWriter, r *http.Request, _ httprou
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// eventRequestToHookPayload exposes the event parameters to the event_notification stage hooks
+func eventRequestToHookPayload(r *http.Request, event *analytics.EventRequest) hookstage.EventNotificationPayload {
+	return hookstage.EventNotificationPayload{
+		Request:     r,
+		Type:        string(event.Type),
+		BidID:       event.BidID,
+		AccountID:   event.AccountID,
+		Bidder:      event.Bidder,
+		Timestamp:   event.Timestamp,
+		Integration: event.Integration,
+		VType:       string(event.VType),
+	}
+}
+
+// applyHookPayloadToEventRequest copies the event parameters modified by the event_notification stage hooks
+func applyHookPayloadToEventRequest(payload hookstage.EventNotificationPayload, event *analytics.EventRequest) {
+	event.Type = analytics.EventType(payload.Type)
+	event.BidID = payload.BidID
+	event.AccountID = payload.AccountID
+	event.Bidder = payload.Bidder
+	event.Timestamp = payload.Timestamp
+	event.Integration = payload.Integration
+	event.VType = analytics.VastType(payload.VType)
+}
+
 // EventRequestToUrl converts an analytics.EventRequest to an URL
 func EventRequestToUrl(externalUrl string, request *analytics.EventRequest) string {
 	s := fmt.Sprintf(TemplateUrl, externalUrl, request.Type, request.BidID, request.AccountID)
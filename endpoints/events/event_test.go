@@ -15,7 +15,10 @@ import (
 	"github.com/prebid/prebid-server/v4/analytics"
 	"github.com/prebid/prebid-server/v4/config"
 	"github.com/prebid/prebid-server/v4/errortypes"
+	"github.com/prebid/prebid-server/v4/hooks"
+	"github.com/prebid/prebid-server/v4/hooks/hookstage"
 	"github.com/prebid/prebid-server/v4/metrics"
+	metricsConfig "github.com/prebid/prebid-server/v4/metrics/config"
 	"github.com/prebid/prebid-server/v4/privacy"
 	"github.com/prebid/prebid-server/v4/stored_requests"
 	"github.com/stretchr/testify/assert"
@@ -25,6 +28,7 @@ type eventsMockAnalyticsModule struct {
 	Fail    bool
 	Error   error
 	Invoked bool
+	Event   *analytics.NotificationEvent
 }
 
 func (e *eventsMockAnalyticsModule) LogAuctionObject(ao *analytics.AuctionObject, _ privacy.ActivityControl) {
@@ -62,6 +66,7 @@ func (e *eventsMockAnalyticsModule) LogNotificationEventObject(ne *analytics.Not
 		panic(e.Error)
 	}
 	e.Invoked = true
+	e.Event = ne
 }
 
 func (e *eventsMockAnalyticsModule) Shutdown() {}
@@ -123,7 +128,7 @@ func TestShouldReturnBadRequestWhenTypeIsMissing(t *testing.T) {
 	req := httptest.NewRequest("GET", "/event?b=test", strings.NewReader(reqData))
 	recorder := httptest.NewRecorder()
 
-	e := NewEventEndpoint(cfg, mockAccountsFetcher, mockAnalyticsModule, &metrics.MetricsEngineMock{})
+	e := NewEventEndpoint(cfg, mockAccountsFetcher, mockAnalyticsModule, &metrics.MetricsEngineMock{}, hooks.EmptyPlanBuilder{})
 
 	// execute
 	e(recorder, req, nil)
@@ -159,7 +164,7 @@ func TestShouldReturnBadRequestWhenTypeIsInvalid(t *testing.T) {
 	req := httptest.NewRequest("GET", "/event?t=test&b=t", strings.NewReader(reqData))
 	recorder := httptest.NewRecorder()
 
-	e := NewEventEndpoint(cfg, mockAccounts, mockAnalyticsModule, &metrics.MetricsEngineMock{})
+	e := NewEventEndpoint(cfg, mockAccounts, mockAnalyticsModule, &metrics.MetricsEngineMock{}, hooks.EmptyPlanBuilder{})
 
 	// execute
 	e(recorder, req, nil)
@@ -195,7 +200,7 @@ func TestShouldReturnBadRequestWhenBidIdIsMissing(t *testing.T) {
 	req := httptest.NewRequest("GET", "/event?t=win", strings.NewReader(reqData))
 	recorder := httptest.NewRecorder()
 
-	e := NewEventEndpoint(cfg, mockAccountsFetcher, mockAnalyticsModule, &metrics.MetricsEngineMock{})
+	e := NewEventEndpoint(cfg, mockAccountsFetcher, mockAnalyticsModule, &metrics.MetricsEngineMock{}, hooks.EmptyPlanBuilder{})
 
 	// execute
 	e(recorder, req, nil)
@@ -231,7 +236,7 @@ func TestShouldReturnBadRequestWhenTimestampIsInvalid(t *testing.T) {
 	req := httptest.NewRequest("GET", "/event?t=win&b=test&ts=q", strings.NewReader(reqData))
 	recorder := httptest.NewRecorder()
 
-	e := NewEventEndpoint(cfg, mockAccountsFetcher, mockAnalyticsModule, &metrics.MetricsEngineMock{})
+	e := NewEventEndpoint(cfg, mockAccountsFetcher, mockAnalyticsModule, &metrics.MetricsEngineMock{}, hooks.EmptyPlanBuilder{})
 
 	// execute
 	e(recorder, req, nil)
@@ -267,7 +272,7 @@ func TestShouldReturnUnauthorizedWhenAccountIsMissing(t *testing.T) {
 	req := httptest.NewRequest("GET", "/event?t=win&b=test&ts=1234", strings.NewReader(reqData))
 	recorder := httptest.NewRecorder()
 
-	e := NewEventEndpoint(cfg, mockAccountsFetcher, mockAnalyticsModule, &metrics.MetricsEngineMock{})
+	e := NewEventEndpoint(cfg, mockAccountsFetcher, mockAnalyticsModule, &metrics.MetricsEngineMock{}, hooks.EmptyPlanBuilder{})
 
 	// execute
 	e(recorder, req, nil)
@@ -303,7 +308,7 @@ func TestShouldReturnBadRequestWhenFormatValueIsInvalid(t *testing.T) {
 	req := httptest.NewRequest("GET", "/event?t=win&b=test&ts=1234&f=q", strings.NewReader(reqData))
 	recorder := httptest.NewRecorder()
 
-	e := NewEventEndpoint(cfg, mockAccountsFetcher, mockAnalyticsModule, &metrics.MetricsEngineMock{})
+	e := NewEventEndpoint(cfg, mockAccountsFetcher, mockAnalyticsModule, &metrics.MetricsEngineMock{}, hooks.EmptyPlanBuilder{})
 
 	// execute
 	e(recorder, req, nil)
@@ -339,7 +344,7 @@ func TestShouldReturnBadRequestWhenAnalyticsValueIsInvalid(t *testing.T) {
 	req := httptest.NewRequest("GET", "/event?t=win&b=test&ts=1234&f=b&x=4", strings.NewReader(reqData))
 	recorder := httptest.NewRecorder()
 
-	e := NewEventEndpoint(cfg, mockAccountsFetcher, mockAnalyticsModule, &metrics.MetricsEngineMock{})
+	e := NewEventEndpoint(cfg, mockAccountsFetcher, mockAnalyticsModule, &metrics.MetricsEngineMock{}, hooks.EmptyPlanBuilder{})
 
 	// execute
 	e(recorder, req, nil)
@@ -378,7 +383,7 @@ func TestShouldNotPassEventToAnalyticsReporterWhenAccountNotFoundAndDefaultIsFal
 	req := httptest.NewRequest("GET", "/event?t=win&b=test&ts=1234&f=b&x=1&a=testacc", strings.NewReader(reqData))
 	recorder := httptest.NewRecorder()
 
-	e := NewEventEndpoint(cfg, mockAccountsFetcher, mockAnalyticsModule, &metrics.MetricsEngineMock{})
+	e := NewEventEndpoint(cfg, mockAccountsFetcher, mockAnalyticsModule, &metrics.MetricsEngineMock{}, hooks.EmptyPlanBuilder{})
 
 	// execute
 	e(recorder, req, nil)
@@ -412,7 +417,7 @@ func TestShouldReturnBadRequestWhenIntegrationValueIsInvalid(t *testing.T) {
 	req := httptest.NewRequest("GET", "/event?t=win&b=bidId&f=b&ts=1000&x=1&a=accountId&bidder=bidder&int=Te$tIntegrationType", strings.NewReader(reqData))
 	recorder := httptest.NewRecorder()
 
-	e := NewEventEndpoint(cfg, mockAccountsFetcher, mockAnalyticsModule, &metrics.MetricsEngineMock{})
+	e := NewEventEndpoint(cfg, mockAccountsFetcher, mockAnalyticsModule, &metrics.MetricsEngineMock{}, hooks.EmptyPlanBuilder{})
 
 	// execute
 	e(recorder, req, nil)
@@ -451,7 +456,7 @@ func TestShouldNotPassEventToAnalyticsReporterWhenAccountEventNotEnabled(t *test
 	req := httptest.NewRequest("GET", "/event?t=win&b=test&ts=1234&f=b&x=1&a=events_disabled", strings.NewReader(reqData))
 	recorder := httptest.NewRecorder()
 
-	e := NewEventEndpoint(cfg, mockAccountsFetcher, mockAnalyticsModule, &metrics.MetricsEngineMock{})
+	e := NewEventEndpoint(cfg, mockAccountsFetcher, mockAnalyticsModule, &metrics.MetricsEngineMock{}, hooks.EmptyPlanBuilder{})
 
 	// execute
 	e(recorder, req, nil)
@@ -489,7 +494,7 @@ func TestShouldPassEventToAnalyticsReporterWhenAccountEventEnabled(t *testing.T)
 	req := httptest.NewRequest("GET", "/event?t=win&b=test&ts=1234&f=b&x=1&a=events_enabled", strings.NewReader(reqData))
 	recorder := httptest.NewRecorder()
 
-	e := NewEventEndpoint(cfg, mockAccountsFetcher, mockAnalyticsModule, &metrics.MetricsEngineMock{})
+	e := NewEventEndpoint(cfg, mockAccountsFetcher, mockAnalyticsModule, &metrics.MetricsEngineMock{}, hooks.EmptyPlanBuilder{})
 
 	// execute
 	e(recorder, req, nil)
@@ -523,7 +528,7 @@ func TestShouldNotPassEventToAnalyticsReporterWhenAnalyticsValueIsZero(t *testin
 	req := httptest.NewRequest("GET", "/event?t=win&b=test&ts=1234&f=b&x=0&a=events_enabled", strings.NewReader(reqData))
 	recorder := httptest.NewRecorder()
 
-	e := NewEventEndpoint(cfg, mockAccountsFetcher, mockAnalyticsModule, &metrics.MetricsEngineMock{})
+	e := NewEventEndpoint(cfg, mockAccountsFetcher, mockAnalyticsModule, &metrics.MetricsEngineMock{}, hooks.EmptyPlanBuilder{})
 
 	// execute
 	e(recorder, req, nil)
@@ -557,7 +562,7 @@ func TestShouldRespondWithPixelAndContentTypeWhenRequestFormatIsImage(t *testing
 	req := httptest.NewRequest("GET", "/event?t=win&b=test&ts=1234&f=i&x=1&a=events_enabled", strings.NewReader(reqData))
 	recorder := httptest.NewRecorder()
 
-	e := NewEventEndpoint(cfg, mockAccountsFetcher, mockAnalyticsModule, &metrics.MetricsEngineMock{})
+	e := NewEventEndpoint(cfg, mockAccountsFetcher, mockAnalyticsModule, &metrics.MetricsEngineMock{}, hooks.EmptyPlanBuilder{})
 
 	// execute
 	e(recorder, req, nil)
@@ -598,7 +603,7 @@ func TestShouldRespondWithNoContentWhenRequestFormatIsNotDefined(t *testing.T) {
 	req := httptest.NewRequest("GET", "/event?t=imp&b=test&ts=1234&x=1&a=events_enabled", strings.NewReader(reqData))
 	recorder := httptest.NewRecorder()
 
-	e := NewEventEndpoint(cfg, mockAccountsFetcher, mockAnalyticsModule, &metrics.MetricsEngineMock{})
+	e := NewEventEndpoint(cfg, mockAccountsFetcher, mockAnalyticsModule, &metrics.MetricsEngineMock{}, hooks.EmptyPlanBuilder{})
 
 	// execute
 	e(recorder, req, nil)
@@ -815,7 +820,7 @@ func TestShouldReturnBadRequestWhenVTypeIsInvalid(t *testing.T) {
 
 		recorder := httptest.NewRecorder()
 
-		e := NewEventEndpoint(cfg, mockAccountsFetcher, mockAnalyticsModule, &metrics.MetricsEngineMock{})
+		e := NewEventEndpoint(cfg, mockAccountsFetcher, mockAnalyticsModule, &metrics.MetricsEngineMock{}, hooks.EmptyPlanBuilder{})
 		e(recorder, test.req, nil)
 
 		d, err := io.ReadAll(recorder.Result().Body)
@@ -915,3 +920,54 @@ func TestReadVType(t *testing.T) {
 		})
 	}
 }
+
+type mockEventPlanBuilder struct {
+	hooks.EmptyPlanBuilder
+	hook hookstage.EventNotification
+}
+
+func (m mockEventPlanBuilder) PlanForEventNotificationStage(_ string, _ *config.Account) hooks.Plan[hookstage.EventNotification] {
+	return hooks.Plan[hookstage.EventNotification]{
+		hooks.Group[hookstage.EventNotification]{
+			Timeout: 10 * time.Millisecond,
+			Hooks: []hooks.HookWrapper[hookstage.EventNotification]{
+				{Module: "foobar", Code: "foo", Hook: m.hook},
+			},
+		},
+	}
+}
+
+type mockEventNotificationHook struct {
+	integration string
+}
+
+func (h mockEventNotificationHook) HandleEventNotificationHook(_ context.Context, _ hookstage.ModuleInvocationContext, _ hookstage.EventNotificationPayload) (hookstage.HookResult[hookstage.EventNotificationPayload], error) {
+	c := hookstage.ChangeSet[hookstage.EventNotificationPayload]{}
+	c.AddMutation(func(payload hookstage.EventNotificationPayload) (hookstage.EventNotificationPayload, error) {
+		payload.Integration = h.integration
+		return payload, nil
+	}, hookstage.MutationUpdate, "integration")
+
+	return hookstage.HookResult[hookstage.EventNotificationPayload]{ChangeSet: c}, nil
+}
+
+func TestShouldExecuteEventNotificationHooks(t *testing.T) {
+	mockAnalyticsModule := &eventsMockAnalyticsModule{}
+	cfg := &config.Configuration{
+		AccountDefaults: config.Account{},
+	}
+	cfg.MarshalAccountDefaults()
+
+	planBuilder := mockEventPlanBuilder{hook: mockEventNotificationHook{integration: "updated"}}
+	req := httptest.NewRequest("GET", "/event?t=win&b=bidId&ts=1234&f=b&x=1&int=original&a=events_enabled", strings.NewReader(""))
+	recorder := httptest.NewRecorder()
+
+	e := NewEventEndpoint(cfg, &mockAccountsFetcher{}, mockAnalyticsModule, &metricsConfig.NilMetricsEngine{}, planBuilder)
+	e(recorder, req, nil)
+
+	assert.Equal(t, 204, recorder.Result().StatusCode)
+	if assert.NotNil(t, mockAnalyticsModule.Event) {
+		assert.Equal(t, "updated", mockAnalyticsModule.Event.Request.Integration)
+		assert.NotEmpty(t, mockAnalyticsModule.Event.HookExecutionOutcome)
+	}
+}
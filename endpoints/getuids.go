@@ -1,28 +1,228 @@
 package endpoints
 
 import (
+	"errors"
 	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
 
 	"github.com/julienschmidt/httprouter"
+	"github.com/prebid/openrtb/v20/openrtb2"
+	accountService "github.com/prebid/prebid-server/v4/account"
 	"github.com/prebid/prebid-server/v4/config"
+	"github.com/prebid/prebid-server/v4/gdpr"
+	"github.com/prebid/prebid-server/v4/logger"
+	"github.com/prebid/prebid-server/v4/macros"
+	"github.com/prebid/prebid-server/v4/metrics"
+	"github.com/prebid/prebid-server/v4/openrtb_ext"
+	"github.com/prebid/prebid-server/v4/stored_requests"
 	"github.com/prebid/prebid-server/v4/usersync"
+	"github.com/prebid/prebid-server/v4/util/jsonutil"
+	"github.com/prebid/prebid-server/v4/util/ptrutil"
+)
+
+const (
+	getUIDsFormatBuyerUIDs = "buyeruids"
+	getUIDsFormatEIDs      = "eids"
+)
 
-	"encoding/json"
+var (
+	errGetUIDsFormatInvalid   = errors.New(`format must be one of "buyeruids" or "eids"`)
+	errGetUIDsGDPRInvalid     = errors.New("gdpr must be 0 or 1")
+	errGetUIDsRedirectInvalid = errors.New("redirect must be an http or https url to one of the hosts allowed by the server")
+	errGetUIDsCallbackInvalid = errors.New("callback must be a javascript identifier, optionally dot separated")
+	errGetUIDsModeConflict    = errors.New("callback and redirect can't be used together")
 )
 
+// getUIDsCallbackPattern matches the javascript functions which can be called by a JSON-P response, such
+// as "pbjs.onUIDs", so that no script can be injected through the callback.
+var getUIDsCallbackPattern = regexp.MustCompile(`^[A-Za-z_$][\w$]*(\.[A-Za-z_$][\w$]*)*$`)
+
 type userSyncs struct {
 	BuyerUIDs map[string]string `json:"buyeruids,omitempty"`
 }
 
-// NewGetUIDsEndpoint implements the /getuid endpoint which
-// returns all the existing syncs for the user
-func NewGetUIDsEndpoint(cfg config.HostCookie) httprouter.Handle {
+type userEIDs struct {
+	EIDs []openrtb2.EID `json:"eids,omitempty"`
+}
+
+// NewGetUIDsEndpoint implements the /getuids endpoint which returns all the existing syncs for the user, as
+// long as the privacy policies of the request allow them to be synced.
+//
+// The syncs are returned as a map of syncer keys to UIDs, or as OpenRTB EIDs with format=eids. With a
+// redirect url, the user is redirected to it instead, with the {{.UIDs}} macro replaced by the response. With
+// a callback, the response is returned as JSON-P.
+func NewGetUIDsEndpoint(cfg *config.Configuration, syncersByBidder map[string]usersync.Syncer, gdprPermsBuilder gdpr.PermissionsBuilder, tcf2CfgBuilder gdpr.TCF2ConfigBuilder, accountsFetcher stored_requests.AccountFetcher, metricsEngine metrics.MetricsEngine, bidders map[string]openrtb_ext.BidderName, uidStore usersync.UIDStore) httprouter.Handle {
+	decoder := usersync.NewDecoder(&cfg.HostCookie, metricsEngine)
+	eidSources := usersync.EIDSources(cfg.BidderInfos)
+
+	biddersBySyncerKey := make(map[string][]string, len(syncersByBidder))
+	for bidder, syncer := range syncersByBidder {
+		biddersBySyncerKey[syncer.Key()] = append(biddersBySyncerKey[syncer.Key()], bidder)
+	}
+
+	bidderHashSet := make(map[string]struct{}, len(bidders))
+	for _, bidder := range bidders {
+		bidderHashSet[string(bidder)] = struct{}{}
+	}
+
+	privacyConfig := usersyncPrivacyConfig{
+		gdprConfig:             cfg.GDPR,
+		gdprPermissionsBuilder: gdprPermsBuilder,
+		tcf2ConfigBuilder:      tcf2CfgBuilder,
+		ccpaEnforce:            cfg.CCPA.Enforce,
+		bidderHashSet:          bidderHashSet,
+	}
+
 	return httprouter.Handle(func(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
-		cookie := usersync.ReadCookie(r, usersync.Base64Decoder{}, &cfg)
-		usersync.SyncHostCookie(r, cookie, &cfg)
+		query := r.URL.Query()
+
+		format := query.Get("format")
+		if format == "" {
+			format = getUIDsFormatBuyerUIDs
+		}
+		if format != getUIDsFormatBuyerUIDs && format != getUIDsFormatEIDs {
+			http.Error(w, errGetUIDsFormatInvalid.Error(), http.StatusBadRequest)
+			return
+		}
+
+		redirect := query.Get("redirect")
+		if redirect != "" && !isGetUIDsRedirectAllowed(redirect, cfg.UserSync.GetUIDs.RedirectHosts) {
+			http.Error(w, errGetUIDsRedirectInvalid.Error(), http.StatusBadRequest)
+			return
+		}
+
+		callback := query.Get("callback")
+		if callback != "" && !getUIDsCallbackPattern.MatchString(callback) {
+			http.Error(w, errGetUIDsCallbackInvalid.Error(), http.StatusBadRequest)
+			return
+		}
+		if callback != "" && redirect != "" {
+			http.Error(w, errGetUIDsModeConflict.Error(), http.StatusBadRequest)
+			return
+		}
+
+		request, err := parseGetUIDsRequest(query)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		// The plain buyeruids output has always been served without an account, so the account is only
+		// required by the hosts which require one when it's given or when a newer output is requested.
+		account := ptrutil.ToPtr(cfg.AccountDefaults)
+		if request.Account != "" || format == getUIDsFormatEIDs || redirect != "" || callback != "" {
+			accountID := request.Account
+			if accountID == "" {
+				accountID = metrics.PublisherUnknown
+			}
+			var fetchErrs []error
+			account, fetchErrs = accountService.GetAccount(r.Context(), cfg, accountsFetcher, accountID, metricsEngine)
+			if len(fetchErrs) > 0 {
+				http.Error(w, combineErrors(fetchErrs).Error(), http.StatusBadRequest)
+				return
+			}
+		}
+
+		syncPrivacy, privacyMacros, err := privacyConfig.buildPrivacy(request, account)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
 
-		userSyncs := new(userSyncs)
-		userSyncs.BuyerUIDs = cookie.GetUIDs()
-		json.NewEncoder(w).Encode(userSyncs)
+		cookie := usersync.ReadCookie(r, decoder, &cfg.HostCookie)
+		if err := cookie.LoadStoredUIDs(r.Context(), uidStore); err != nil {
+			logger.Warnf("/getuids failed to load the uids of the user: %v", err)
+		}
+		usersync.SyncHostCookie(r, cookie, &cfg.HostCookie)
+
+		uids := map[string]string{}
+		if syncPrivacy.GDPRAllowsHostCookie() {
+			for key, uid := range cookie.GetUIDs() {
+				// the keys of no bidder, such as the custom host cookie families, are returned as is
+				bidders, ok := biddersBySyncerKey[key]
+				if !ok || slices.ContainsFunc(bidders, syncPrivacy.allowsBidderSync) {
+					uids[key] = uid
+				}
+			}
+		}
+
+		var response interface{} = userSyncs{BuyerUIDs: uids}
+		if format == getUIDsFormatEIDs {
+			response = userEIDs{EIDs: usersync.BuildEIDs(uids, eidSources)}
+		}
+
+		body, err := jsonutil.Marshal(response)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		if redirect != "" {
+			http.Redirect(w, r, resolveGetUIDsRedirect(redirect, string(body), privacyMacros), http.StatusFound)
+			return
+		}
+
+		if callback != "" {
+			w.Header().Set("Content-Type", "application/javascript")
+			w.Header().Set("X-Content-Type-Options", "nosniff")
+			w.Write([]byte(callback + "(" + string(body) + ");"))
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(body)
 	})
 }
+
+// parseGetUIDsRequest reads the privacy signals and the account from the query, which are the same as the
+// ones of the /cookie_sync request.
+func parseGetUIDsRequest(query url.Values) (cookieSyncRequest, error) {
+	request := cookieSyncRequest{
+		GDPRConsent: query.Get("gdpr_consent"),
+		USPrivacy:   query.Get("us_privacy"),
+		GPP:         query.Get("gpp"),
+		GPPSID:      query.Get("gpp_sid"),
+		Account:     query.Get("account"),
+	}
+
+	if gdprValue := query.Get("gdpr"); gdprValue != "" {
+		gdprInt, err := strconv.Atoi(gdprValue)
+		if err != nil {
+			return cookieSyncRequest{}, errGetUIDsGDPRInvalid
+		}
+		request.GDPR = &gdprInt
+	}
+	return request, nil
+}
+
+// isGetUIDsRedirectAllowed returns true if the redirect url is an absolute http or https url to one of the
+// allowed hosts.
+func isGetUIDsRedirectAllowed(redirect string, allowedHosts []string) bool {
+	redirectURL, err := url.Parse(redirect)
+	if err != nil || (redirectURL.Scheme != "http" && redirectURL.Scheme != "https") {
+		return false
+	}
+
+	host := strings.ToLower(redirectURL.Hostname())
+	return slices.ContainsFunc(allowedHosts, func(allowedHost string) bool {
+		return strings.ToLower(allowedHost) == host
+	})
+}
+
+// resolveGetUIDsRedirect replaces the macros of the redirect url with their query escaped values. The url is
+// provided by the request, so it's not parsed as a template.
+func resolveGetUIDsRedirect(redirect, uids string, privacyMacros macros.UserSyncPrivacy) string {
+	replacer := strings.NewReplacer(
+		"{{.UIDs}}", url.QueryEscape(uids),
+		"{{.GDPR}}", url.QueryEscape(privacyMacros.GDPR),
+		"{{.GDPRConsent}}", url.QueryEscape(privacyMacros.GDPRConsent),
+		"{{.USPrivacy}}", url.QueryEscape(privacyMacros.USPrivacy),
+		"{{.GPP}}", url.QueryEscape(privacyMacros.GPP),
+		"{{.GPPSID}}", url.QueryEscape(privacyMacros.GPPSID),
+	)
+	return replacer.Replace(redirect)
+}
@@ -1,17 +1,48 @@
 package endpoints
 
 import (
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"testing"
 
+	"github.com/julienschmidt/httprouter"
 	"github.com/prebid/prebid-server/v4/config"
+	"github.com/prebid/prebid-server/v4/gdpr"
+	"github.com/prebid/prebid-server/v4/macros"
+	metricsConf "github.com/prebid/prebid-server/v4/metrics/config"
+	"github.com/prebid/prebid-server/v4/openrtb_ext"
+	"github.com/prebid/prebid-server/v4/usersync"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
+// newTestGetUIDsEndpoint returns the /getuids endpoint for bidders named after their syncer key, whose
+// privacy policies are all allowed unless the permissions say otherwise.
+func newTestGetUIDsEndpoint(cfg *config.Configuration, permissions gdpr.Permissions, accountData map[string]json.RawMessage, syncerKeys ...string) httprouter.Handle {
+	if permissions == nil {
+		permissions = &fakePermissions{}
+	}
+	gdprPermsBuilder := fakePermissionsBuilder{permissions: permissions}.Builder
+	tcf2ConfigBuilder := fakeTCF2ConfigBuilder{
+		cfg: gdpr.NewTCF2Config(config.TCF2{}, config.AccountGDPR{}),
+	}.Builder
+
+	syncersByBidder := make(map[string]usersync.Syncer, len(syncerKeys))
+	bidders := make(map[string]openrtb_ext.BidderName, len(syncerKeys))
+	for _, key := range syncerKeys {
+		syncersByBidder[key] = fakeSyncer{key: key, defaultSyncType: usersync.SyncTypeIFrame}
+		bidders[key] = openrtb_ext.BidderName(key)
+	}
+
+	cfg.MarshalAccountDefaults()
+	return NewGetUIDsEndpoint(cfg, syncersByBidder, gdprPermsBuilder, tcf2ConfigBuilder, FakeAccountsFetcher{AccountData: accountData}, &metricsConf.NilMetricsEngine{}, bidders, nil)
+}
+
 func TestGetUIDs(t *testing.T) {
-	req := makeRequest("/getuids", map[string]string{"adnxs": "123", "audienceNetwork": "456"})
-	endpoint := NewGetUIDsEndpoint(config.HostCookie{})
+	req := makeRequest("/getuids?gdpr=0", map[string]string{"adnxs": "123", "audienceNetwork": "456"})
+	endpoint := newTestGetUIDsEndpoint(&config.Configuration{}, nil, nil, "adnxs", "audienceNetwork")
 	res := httptest.NewRecorder()
 	endpoint(res, req, nil)
 
@@ -21,8 +52,8 @@ func TestGetUIDs(t *testing.T) {
 }
 
 func TestGetUIDsWithNoSyncs(t *testing.T) {
-	req := makeRequest("/getuids", map[string]string{})
-	endpoint := NewGetUIDsEndpoint(config.HostCookie{})
+	req := makeRequest("/getuids?gdpr=0", map[string]string{})
+	endpoint := newTestGetUIDsEndpoint(&config.Configuration{}, nil, nil, "adnxs")
 	res := httptest.NewRecorder()
 	endpoint(res, req, nil)
 
@@ -31,11 +62,337 @@ func TestGetUIDsWithNoSyncs(t *testing.T) {
 }
 
 func TestGetUIDWIthNoCookie(t *testing.T) {
-	req := httptest.NewRequest("GET", "/getuids", nil)
-	endpoint := NewGetUIDsEndpoint(config.HostCookie{})
+	req := httptest.NewRequest("GET", "/getuids?gdpr=0", nil)
+	endpoint := newTestGetUIDsEndpoint(&config.Configuration{}, nil, nil, "adnxs")
 	res := httptest.NewRecorder()
 	endpoint(res, req, nil)
 
 	assert.Equal(t, http.StatusOK, res.Code)
 	assert.JSONEq(t, `{}`, res.Body.String(), "GetUIDs endpoint shouldn't return anything if there doesn't exist a PBS cookie")
 }
+
+func TestGetUIDsFormat(t *testing.T) {
+	cfg := &config.Configuration{
+		BidderInfos: config.BidderInfos{
+			"adnxs":     {Syncer: &config.Syncer{EIDSource: "adnxs.com"}},
+			"appnexus2": {Syncer: &config.Syncer{Key: "appnexus2", EIDSource: "adnxs.com"}},
+			"pubmatic":  {Syncer: &config.Syncer{EIDSource: "pubmatic.com"}},
+			"rubicon":   {Syncer: &config.Syncer{}},
+		},
+	}
+	syncs := map[string]string{"adnxs": "1", "appnexus2": "2", "pubmatic": "3", "rubicon": "4"}
+
+	testCases := []struct {
+		name           string
+		givenURL       string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "default",
+			givenURL:       "/getuids?gdpr=0",
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"buyeruids":{"adnxs":"1","appnexus2":"2","pubmatic":"3","rubicon":"4"}}`,
+		},
+		{
+			name:           "buyeruids",
+			givenURL:       "/getuids?gdpr=0&format=buyeruids",
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"buyeruids":{"adnxs":"1","appnexus2":"2","pubmatic":"3","rubicon":"4"}}`,
+		},
+		{
+			name:           "eids",
+			givenURL:       "/getuids?gdpr=0&format=eids",
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"eids":[{"source":"adnxs.com","uids":[{"id":"1","atype":1},{"id":"2","atype":1}]},{"source":"pubmatic.com","uids":[{"id":"3","atype":1}]}]}`,
+		},
+		{
+			name:           "invalid",
+			givenURL:       "/getuids?gdpr=0&format=jsonp",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   errGetUIDsFormatInvalid.Error() + "\n",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			endpoint := newTestGetUIDsEndpoint(cfg, nil, nil, "adnxs", "appnexus2", "pubmatic", "rubicon")
+			res := httptest.NewRecorder()
+			endpoint(res, makeRequest(test.givenURL, syncs), nil)
+
+			assert.Equal(t, test.expectedStatus, res.Code)
+			if test.expectedStatus == http.StatusOK {
+				assert.JSONEq(t, test.expectedBody, res.Body.String())
+				assert.Equal(t, "application/json", res.Header().Get("Content-Type"))
+			} else {
+				assert.Equal(t, test.expectedBody, res.Body.String())
+			}
+		})
+	}
+}
+
+func TestGetUIDsPrivacy(t *testing.T) {
+	syncs := map[string]string{"adnxs": "1", "pubmatic": "2", "unknown": "3"}
+	accountData := map[string]json.RawMessage{
+		"blocks-pubmatic": json.RawMessage(`{"privacy":{"allowactivities":{"syncUser":{"default":true,"rules":[{"allow":false,"condition":{"componentName":["pubmatic"],"componentType":["bidder"]}}]}}}}`),
+		"disabled":        json.RawMessage(`{"disabled":true}`),
+	}
+
+	testCases := []struct {
+		name             string
+		givenURL         string
+		givenCfg         config.Configuration
+		givenPermissions func() gdpr.Permissions
+		expectedStatus   int
+		expectedBody     string
+	}{
+		{
+			name:           "allowed",
+			givenURL:       "/getuids?gdpr=0",
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"buyeruids":{"adnxs":"1","pubmatic":"2","unknown":"3"}}`,
+		},
+		{
+			name:     "gdpr-host-cookie-blocked",
+			givenURL: "/getuids?gdpr=1&gdpr_consent=consent",
+			givenPermissions: func() gdpr.Permissions {
+				return &fakePermsSetUID{allowHost: false}
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{}`,
+		},
+		{
+			name:     "gdpr-bidder-blocked",
+			givenURL: "/getuids?gdpr=1&gdpr_consent=consent",
+			givenPermissions: func() gdpr.Permissions {
+				permissions := &MockGDPRPerms{}
+				permissions.On("HostCookiesAllowed", mock.Anything).Return(true, nil)
+				permissions.On("BidderSyncAllowed", mock.Anything, openrtb_ext.BidderName("adnxs")).Return(true, nil)
+				permissions.On("BidderSyncAllowed", mock.Anything, openrtb_ext.BidderName("pubmatic")).Return(false, nil)
+				return permissions
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"buyeruids":{"adnxs":"1","unknown":"3"}}`,
+		},
+		{
+			name:           "gdpr-consent-missing",
+			givenURL:       "/getuids?gdpr=1",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   errCookieSyncGDPRConsentMissing.Error() + "\n",
+		},
+		{
+			name:           "gdpr-invalid",
+			givenURL:       "/getuids?gdpr=yes",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   errGetUIDsGDPRInvalid.Error() + "\n",
+		},
+		{
+			name:           "ccpa-opt-out",
+			givenURL:       "/getuids?gdpr=0&us_privacy=1-Y-",
+			givenCfg:       config.Configuration{CCPA: config.CCPA{Enforce: true}},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"buyeruids":{"unknown":"3"}}`,
+		},
+		{
+			name:           "ccpa-not-enforced",
+			givenURL:       "/getuids?gdpr=0&us_privacy=1-Y-",
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"buyeruids":{"adnxs":"1","pubmatic":"2","unknown":"3"}}`,
+		},
+		{
+			name:           "activity-blocked",
+			givenURL:       "/getuids?gdpr=0&account=blocks-pubmatic",
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"buyeruids":{"adnxs":"1","unknown":"3"}}`,
+		},
+		{
+			name:           "account-disabled",
+			givenURL:       "/getuids?gdpr=0&account=disabled",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   errCookieSyncAccountBlocked.Error() + "\n",
+		},
+		{
+			name:           "account-required-buyeruids",
+			givenURL:       "/getuids?gdpr=0",
+			givenCfg:       config.Configuration{AccountRequired: true},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"buyeruids":{"adnxs":"1","pubmatic":"2","unknown":"3"}}`,
+		},
+		{
+			name:           "account-required-eids",
+			givenURL:       "/getuids?gdpr=0&format=eids",
+			givenCfg:       config.Configuration{AccountRequired: true},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   errCookieSyncAccountInvalid.Error() + "\n",
+		},
+		{
+			name:           "account-required-callback",
+			givenURL:       "/getuids?gdpr=0&callback=onUIDs",
+			givenCfg:       config.Configuration{AccountRequired: true},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   errCookieSyncAccountInvalid.Error() + "\n",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			var permissions gdpr.Permissions
+			if test.givenPermissions != nil {
+				permissions = test.givenPermissions()
+			}
+			endpoint := newTestGetUIDsEndpoint(&test.givenCfg, permissions, accountData, "adnxs", "pubmatic")
+			res := httptest.NewRecorder()
+			endpoint(res, makeRequest(test.givenURL, syncs), nil)
+
+			assert.Equal(t, test.expectedStatus, res.Code)
+			if test.expectedStatus == http.StatusOK {
+				assert.JSONEq(t, test.expectedBody, res.Body.String())
+			} else {
+				assert.Equal(t, test.expectedBody, res.Body.String())
+			}
+		})
+	}
+}
+
+func TestGetUIDsRedirect(t *testing.T) {
+	cfg := config.Configuration{
+		BidderInfos: config.BidderInfos{"adnxs": {Syncer: &config.Syncer{EIDSource: "adnxs.com"}}},
+		UserSync:    config.UserSync{GetUIDs: config.UserSyncGetUIDs{RedirectHosts: []string{"pub.example.com"}}},
+	}
+
+	testCases := []struct {
+		name             string
+		givenCfg         config.Configuration
+		givenRedirect    string
+		givenQuery       string
+		expectedStatus   int
+		expectedLocation string
+		expectedBody     string
+	}{
+		{
+			name:             "buyeruids",
+			givenCfg:         cfg,
+			givenRedirect:    "https://pub.example.com/ids?uids={{.UIDs}}&gdpr={{.GDPR}}",
+			givenQuery:       "&gdpr=0",
+			expectedStatus:   http.StatusFound,
+			expectedLocation: "https://pub.example.com/ids?uids=%7B%22buyeruids%22%3A%7B%22adnxs%22%3A%221%22%7D%7D&gdpr=0",
+		},
+		{
+			name:             "eids",
+			givenCfg:         cfg,
+			givenRedirect:    "https://PUB.example.com/ids?eids={{.UIDs}}",
+			givenQuery:       "&gdpr=0&format=eids",
+			expectedStatus:   http.StatusFound,
+			expectedLocation: "https://PUB.example.com/ids?eids=%7B%22eids%22%3A%5B%7B%22source%22%3A%22adnxs.com%22%2C%22uids%22%3A%5B%7B%22id%22%3A%221%22%2C%22atype%22%3A1%7D%5D%7D%5D%7D",
+		},
+		{
+			name:           "host-not-allowed",
+			givenCfg:       cfg,
+			givenRedirect:  "https://evil.example.com/ids?uids={{.UIDs}}",
+			givenQuery:     "&gdpr=0",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   errGetUIDsRedirectInvalid.Error() + "\n",
+		},
+		{
+			name:           "scheme-not-allowed",
+			givenCfg:       cfg,
+			givenRedirect:  "javascript://pub.example.com/%0Aalert(1)",
+			givenQuery:     "&gdpr=0",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   errGetUIDsRedirectInvalid.Error() + "\n",
+		},
+		{
+			name:           "no-hosts-allowed",
+			givenCfg:       config.Configuration{},
+			givenRedirect:  "https://pub.example.com/ids?uids={{.UIDs}}",
+			givenQuery:     "&gdpr=0",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   errGetUIDsRedirectInvalid.Error() + "\n",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			endpoint := newTestGetUIDsEndpoint(&test.givenCfg, nil, nil, "adnxs")
+			res := httptest.NewRecorder()
+			query := "/getuids?redirect=" + url.QueryEscape(test.givenRedirect) + test.givenQuery
+			endpoint(res, makeRequest(query, map[string]string{"adnxs": "1"}), nil)
+
+			assert.Equal(t, test.expectedStatus, res.Code)
+			assert.Equal(t, test.expectedLocation, res.Header().Get("Location"))
+			if test.expectedBody != "" {
+				assert.Equal(t, test.expectedBody, res.Body.String())
+			}
+		})
+	}
+}
+
+func TestResolveGetUIDsRedirect(t *testing.T) {
+	privacyMacros := macros.UserSyncPrivacy{
+		GDPR:        "1",
+		GDPRConsent: "consent",
+		USPrivacy:   "1NYN",
+		GPP:         "DBA",
+		GPPSID:      "2,6",
+	}
+
+	redirect := resolveGetUIDsRedirect("https://pub.example.com/?gdpr={{.GDPR}}&consent={{.GDPRConsent}}&ccpa={{.USPrivacy}}&gpp={{.GPP}}&sid={{.GPPSID}}&ids={{.UIDs}}&other={{.Other}}", `{"a":"b c"}`, privacyMacros)
+
+	assert.Equal(t, "https://pub.example.com/?gdpr=1&consent=consent&ccpa=1NYN&gpp=DBA&sid=2%2C6&ids=%7B%22a%22%3A%22b+c%22%7D&other={{.Other}}", redirect)
+}
+
+func TestGetUIDsCallback(t *testing.T) {
+	cfg := config.Configuration{
+		UserSync: config.UserSync{GetUIDs: config.UserSyncGetUIDs{RedirectHosts: []string{"pub.example.com"}}},
+	}
+
+	testCases := []struct {
+		name                string
+		givenQuery          string
+		expectedStatus      int
+		expectedContentType string
+		expectedBody        string
+	}{
+		{
+			name:                "function",
+			givenQuery:          "&callback=onUIDs",
+			expectedStatus:      http.StatusOK,
+			expectedContentType: "application/javascript",
+			expectedBody:        `onUIDs({"buyeruids":{"adnxs":"1"}});`,
+		},
+		{
+			name:                "namespaced-function",
+			givenQuery:          "&callback=pbjs.$on_UIDs",
+			expectedStatus:      http.StatusOK,
+			expectedContentType: "application/javascript",
+			expectedBody:        `pbjs.$on_UIDs({"buyeruids":{"adnxs":"1"}});`,
+		},
+		{
+			name:           "script-injection",
+			givenQuery:     "&callback=" + url.QueryEscape("alert(1);f"),
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   errGetUIDsCallbackInvalid.Error() + "\n",
+		},
+		{
+			name:           "with-redirect",
+			givenQuery:     "&callback=onUIDs&redirect=" + url.QueryEscape("https://pub.example.com/?ids={{.UIDs}}"),
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   errGetUIDsModeConflict.Error() + "\n",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			endpoint := newTestGetUIDsEndpoint(&cfg, nil, nil, "adnxs")
+			res := httptest.NewRecorder()
+			endpoint(res, makeRequest("/getuids?gdpr=0"+test.givenQuery, map[string]string{"adnxs": "1"}), nil)
+
+			assert.Equal(t, test.expectedStatus, res.Code)
+			assert.Equal(t, test.expectedBody, res.Body.String())
+			if test.expectedContentType != "" {
+				assert.Equal(t, test.expectedContentType, res.Header().Get("Content-Type"))
+				assert.Equal(t, "nosniff", res.Header().Get("X-Content-Type-Options"))
+			}
+		})
+	}
+}
@@ -1,7 +1,6 @@
 package openrtb2
 
 import (
-	"compress/gzip"
 	"context"
 	"encoding/json"
 	"errors"
@@ -22,12 +21,15 @@ import (
 	"github.com/prebid/openrtb/v20/openrtb2"
 	"github.com/prebid/openrtb/v20/openrtb3"
 	"github.com/prebid/prebid-server/v4/bidadjustment"
+	"github.com/prebid/prebid-server/v4/capture"
 	"github.com/prebid/prebid-server/v4/hooks"
 	"github.com/prebid/prebid-server/v4/logger"
 	"github.com/prebid/prebid-server/v4/ortb"
 	"github.com/prebid/prebid-server/v4/privacy"
 	"github.com/prebid/prebid-server/v4/privacysandbox"
 	"github.com/prebid/prebid-server/v4/schain"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"golang.org/x/net/publicsuffix"
 	jsonpatch "gopkg.in/evanphx/json-patch.v5"
 
@@ -47,6 +49,7 @@ import (
 	"github.com/prebid/prebid-server/v4/stored_requests"
 	"github.com/prebid/prebid-server/v4/stored_requests/backends/empty_fetcher"
 	"github.com/prebid/prebid-server/v4/stored_responses"
+	"github.com/prebid/prebid-server/v4/tracing"
 	"github.com/prebid/prebid-server/v4/usersync"
 	"github.com/prebid/prebid-server/v4/util/httputil"
 	"github.com/prebid/prebid-server/v4/util/iputil"
@@ -98,6 +101,7 @@ func NewEndpoint(
 	storedRespFetcher stored_requests.Fetcher,
 	hookExecutionPlanBuilder hooks.ExecutionPlanBuilder,
 	tmaxAdjustments *exchange.TmaxAdjustmentsPreprocessed,
+	uidStore usersync.UIDStore,
 ) (httprouter.Handle, error) {
 	if ex == nil || requestValidator == nil || requestsById == nil || accounts == nil || cfg == nil || metricsEngine == nil {
 		return nil, errors.New("NewEndpoint requires non-nil arguments.")
@@ -130,7 +134,9 @@ func NewEndpoint(
 		storedRespFetcher,
 		hookExecutionPlanBuilder,
 		tmaxAdjustments,
-		openrtb_ext.NormalizeBidderName}).Auction), nil
+		openrtb_ext.NormalizeBidderName,
+		uidStore,
+		usersync.NewDecoder(&cfg.HostCookie, metricsEngine)}).Auction), nil
 }
 
 type endpointDeps struct {
@@ -154,6 +160,8 @@ type endpointDeps struct {
 	hookExecutionPlanBuilder  hooks.ExecutionPlanBuilder
 	tmaxAdjustments           *exchange.TmaxAdjustmentsPreprocessed
 	normalizeBidderName       openrtb_ext.BidderNameNormalizer
+	uidStore                  usersync.UIDStore
+	cookieDecoder             usersync.Decoder
 }
 
 func (deps *endpointDeps) Auction(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
@@ -165,7 +173,12 @@ func (deps *endpointDeps) Auction(w http.ResponseWriter, r *http.Request, _ http
 	// to compute the auction timeout.
 	start := time.Now()
 
+	traceCtx, span := tracing.Start(tracing.Extract(context.Background(), r.Header), "openrtb2.auction", trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+	traceCtx, w = capture.Start(traceCtx, capture.EndpointAuction, w)
+
 	hookExecutor := hookexecution.NewHookExecutor(deps.hookExecutionPlanBuilder, hookexecution.EndpointAuction, deps.metricsEngine)
+	hookExecutor.SetTraceContext(traceCtx)
 
 	ao := analytics.AuctionObject{
 		Status:    http.StatusOK,
@@ -186,12 +199,14 @@ func (deps *endpointDeps) Auction(w http.ResponseWriter, r *http.Request, _ http
 		deps.metricsEngine.RecordRequest(labels)
 		deps.metricsEngine.RecordRequestTime(labels, time.Since(start))
 		deps.analytics.LogAuctionObject(&ao, activityControl)
+		span.SetAttributes(attribute.String("pbs.request_status", string(labels.RequestStatus)))
+		capture.Finish(traceCtx, hookExecutor.GetOutcomes())
 	}()
 
 	w.Header().Set("X-Prebid", version.BuildXPrebidHeader(version.Ver))
 	setBrowsingTopicsHeader(w, r)
 
-	req, impExtInfoMap, storedAuctionResponses, storedBidResponses, bidderImpReplaceImp, account, errL := deps.parseRequest(r, &labels, hookExecutor)
+	req, impExtInfoMap, storedAuctionResponses, storedBidResponses, bidderImpReplaceImp, account, errL := deps.parseRequest(traceCtx, r, &labels, hookExecutor)
 	if errortypes.ContainsFatalError(errL) && writeError(errL, w, &labels) {
 		return
 	}
@@ -207,7 +222,7 @@ func (deps *endpointDeps) Auction(w http.ResponseWriter, r *http.Request, _ http
 	hookExecutor.SetActivityControl(activityControl)
 	hookExecutor.SetAccount(account)
 
-	ctx := context.Background()
+	ctx := traceCtx
 
 	timeout := deps.cfg.AuctionTimeouts.LimitAuctionTimeout(time.Duration(req.TMax) * time.Millisecond)
 	if timeout > 0 {
@@ -220,9 +235,7 @@ func (deps *endpointDeps) Auction(w http.ResponseWriter, r *http.Request, _ http
 	errL = append(errL, gdprErrs...)
 
 	// Read Usersyncs/Cookie
-	decoder := usersync.Base64Decoder{}
-	usersyncs := usersync.ReadCookie(r, decoder, &deps.cfg.HostCookie)
-	usersync.SyncHostCookie(r, usersyncs, &deps.cfg.HostCookie)
+	usersyncs := deps.readUserSyncs(ctx, r)
 
 	if req.Site != nil {
 		if usersyncs.HasAnyLiveSyncs() {
@@ -264,6 +277,8 @@ func (deps *endpointDeps) Auction(w http.ResponseWriter, r *http.Request, _ http
 		GDPRSignal:                 gdprSignal,
 		GDPREnforced:               gdprEnforced,
 	}
+	capture.SetPrivacy(ctx, activityControl, req, gdprEnforced)
+	capture.SetResolvedRequest(ctx, req)
 	auctionResponse, err := deps.ex.HoldAuction(ctx, auctionRequest, nil)
 	defer func() {
 		if !auctionRequest.BidderResponseStartTime.IsZero() {
@@ -278,6 +293,7 @@ func (deps *endpointDeps) Auction(w http.ResponseWriter, r *http.Request, _ http
 	}
 	ao.Response = response
 	ao.SeatNonBid = auctionResponse.GetSeatNonBid()
+	ao.FloorsOutcome = auctionResponse.GetFloorsOutcome()
 	rejectErr, isRejectErr := hookexecution.CastRejectErr(err)
 	if err != nil && !isRejectErr {
 		if errortypes.ReadCode(err) == errortypes.BadInputErrorCode {
@@ -406,6 +422,17 @@ func setBrowsingTopicsHeader(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// readUserSyncs reads the uids cookie of the request, along with the UIDs kept in the UID store for the user.
+// The auction goes on with the UIDs of the cookie alone if the store can't be reached.
+func (deps *endpointDeps) readUserSyncs(ctx context.Context, r *http.Request) *usersync.Cookie {
+	usersyncs := usersync.ReadCookie(r, deps.cookieDecoder, &deps.cfg.HostCookie)
+	if err := usersyncs.LoadStoredUIDs(ctx, deps.uidStore); err != nil {
+		logger.Warnf("Failed to load the uids of the user from the uid store: %v", err)
+	}
+	usersync.SyncHostCookie(r, usersyncs, &deps.cfg.HostCookie)
+	return usersyncs
+}
+
 // parseRequest turns the HTTP request into an OpenRTB request. This is guaranteed to return:
 //
 //   - A context which times out appropriately, given the request.
@@ -416,23 +443,14 @@ func setBrowsingTopicsHeader(w http.ResponseWriter, r *http.Request) {
 // possible, it will return errors with messages that suggest improvements.
 //
 // If the errors list has at least one element, then no guarantees are made about the returned request.
-func (deps *endpointDeps) parseRequest(httpRequest *http.Request, labels *metrics.Labels, hookExecutor hookexecution.HookStageExecutor) (req *openrtb_ext.RequestWrapper, impExtInfoMap map[string]exchange.ImpExtInfo, storedAuctionResponses stored_responses.ImpsWithBidResponses, storedBidResponses stored_responses.ImpBidderStoredResp, bidderImpReplaceImpId stored_responses.BidderImpReplaceImpID, account *config.Account, errs []error) {
+func (deps *endpointDeps) parseRequest(traceCtx context.Context, httpRequest *http.Request, labels *metrics.Labels, hookExecutor hookexecution.HookStageExecutor) (req *openrtb_ext.RequestWrapper, impExtInfoMap map[string]exchange.ImpExtInfo, storedAuctionResponses stored_responses.ImpsWithBidResponses, storedBidResponses stored_responses.ImpBidderStoredResp, bidderImpReplaceImpId stored_responses.BidderImpReplaceImpID, account *config.Account, errs []error) {
 	errs = nil
 	var err error
 	var errL []error
-	var r io.ReadCloser = httpRequest.Body
-	reqContentEncoding := httputil.ContentEncoding(httpRequest.Header.Get("Content-Encoding"))
-	if reqContentEncoding != "" {
-		if !deps.cfg.Compression.Request.IsSupported(reqContentEncoding) {
-			errs = []error{fmt.Errorf("Content-Encoding of type %s is not supported", reqContentEncoding)}
-			return
-		} else {
-			r, err = getCompressionEnabledReader(httpRequest.Body, reqContentEncoding)
-			if err != nil {
-				errs = []error{err}
-				return
-			}
-		}
+	r, err := deps.getRequestBodyReader(httpRequest)
+	if err != nil {
+		errs = []error{err}
+		return
 	}
 	defer r.Close()
 	limitedReqReader := &io.LimitedReader{
@@ -458,6 +476,7 @@ func (deps *endpointDeps) parseRequest(httpRequest *http.Request, labels *metric
 			return
 		}
 	}
+	capture.SetIncomingRequest(traceCtx, httpRequest, requestJson)
 
 	req = &openrtb_ext.RequestWrapper{}
 	req.BidRequest = &openrtb2.BidRequest{}
@@ -472,7 +491,7 @@ func (deps *endpointDeps) parseRequest(httpRequest *http.Request, labels *metric
 	}
 
 	timeout := parseTimeout(requestJson, time.Duration(deps.cfg.StoredRequestsTimeout)*time.Millisecond)
-	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	ctx, cancel := context.WithTimeout(traceCtx, timeout)
 	defer cancel()
 
 	impInfo, errs := parseImpInfo(requestJson)
@@ -508,6 +527,8 @@ func (deps *endpointDeps) parseRequest(httpRequest *http.Request, labels *metric
 	if len(errs) > 0 {
 		return
 	}
+	tracing.SetAccount(ctx, account)
+	capture.SetAccount(ctx, account)
 
 	hookExecutor.SetAccount(account)
 	requestJson, rejectErr = hookExecutor.ExecuteRawAuctionStage(requestJson)
@@ -585,13 +606,17 @@ func (deps *endpointDeps) parseRequest(httpRequest *http.Request, labels *metric
 	return
 }
 
-func getCompressionEnabledReader(body io.ReadCloser, contentEncoding httputil.ContentEncoding) (io.ReadCloser, error) {
-	switch contentEncoding {
-	case httputil.ContentEncodingGZIP:
-		return gzip.NewReader(body)
-	default:
-		return nil, fmt.Errorf("unsupported compression type '%s'", contentEncoding)
+// getRequestBodyReader returns a reader of the request body, which is decompressed if the request
+// has a Content-Encoding enabled in the host config.
+func (deps *endpointDeps) getRequestBodyReader(httpRequest *http.Request) (io.ReadCloser, error) {
+	reqContentEncoding := httputil.ContentEncoding(httpRequest.Header.Get("Content-Encoding"))
+	if reqContentEncoding == "" {
+		return httpRequest.Body, nil
+	}
+	if !deps.cfg.Compression.Request.IsSupported(reqContentEncoding) {
+		return nil, fmt.Errorf("Content-Encoding of type %s is not supported", reqContentEncoding)
 	}
+	return httputil.NewDecodingReader(httpRequest.Body, reqContentEncoding)
 }
 
 // hasPayloadUpdatesAt checks if there are any successful payload updates at given stage
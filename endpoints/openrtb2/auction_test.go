@@ -37,12 +37,16 @@ import (
 	metricsConfig "github.com/prebid/prebid-server/v4/metrics/config"
 	"github.com/prebid/prebid-server/v4/openrtb_ext"
 	"github.com/prebid/prebid-server/v4/ortb"
+	"github.com/prebid/prebid-server/v4/stored_requests"
 	"github.com/prebid/prebid-server/v4/stored_requests/backends/empty_fetcher"
 	"github.com/prebid/prebid-server/v4/stored_responses"
+	"github.com/prebid/prebid-server/v4/usersync"
+	"github.com/prebid/prebid-server/v4/util/httputil"
 	"github.com/prebid/prebid-server/v4/util/iputil"
 	"github.com/prebid/prebid-server/v4/util/jsonutil"
 	"github.com/prebid/prebid-server/v4/util/ptrutil"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 const jsonFileExtension string = ".json"
@@ -488,6 +492,7 @@ func TestExplicitUserId(t *testing.T) {
 		empty_fetcher.EmptyFetcher{},
 		hooks.EmptyPlanBuilder{},
 		nil,
+		nil,
 	)
 
 	endpoint(httptest.NewRecorder(), request, nil)
@@ -546,6 +551,7 @@ func doBadAliasRequest(t *testing.T, filename string, expectMsg string) {
 		empty_fetcher.EmptyFetcher{},
 		hooks.EmptyPlanBuilder{},
 		nil,
+		nil,
 	)
 
 	request := httptest.NewRequest("POST", "/openrtb2/auction", bytes.NewReader(testBidRequest))
@@ -601,6 +607,7 @@ func TestNilExchange(t *testing.T) {
 		empty_fetcher.EmptyFetcher{},
 		hooks.EmptyPlanBuilder{},
 		nil,
+		nil,
 	)
 
 	if err == nil {
@@ -627,6 +634,7 @@ func TestExchangeError(t *testing.T) {
 		empty_fetcher.EmptyFetcher{},
 		hooks.EmptyPlanBuilder{},
 		nil,
+		nil,
 	)
 
 	request := httptest.NewRequest("POST", "/openrtb2/auction", strings.NewReader(validRequest(t, "site.json")))
@@ -754,6 +762,7 @@ func TestImplicitIPsEndToEnd(t *testing.T) {
 			empty_fetcher.EmptyFetcher{},
 			hooks.EmptyPlanBuilder{},
 			nil,
+			nil,
 		)
 
 		httpReq := httptest.NewRequest("POST", "/openrtb2/auction", strings.NewReader(validRequest(t, test.reqJSONFile)))
@@ -954,6 +963,7 @@ func TestImplicitDNTEndToEnd(t *testing.T) {
 			empty_fetcher.EmptyFetcher{},
 			hooks.EmptyPlanBuilder{},
 			nil,
+			nil,
 		)
 
 		httpReq := httptest.NewRequest("POST", "/openrtb2/auction", strings.NewReader(validRequest(t, test.reqJSONFile)))
@@ -1196,6 +1206,8 @@ func TestStoredRequests(t *testing.T) {
 		hooks.EmptyPlanBuilder{},
 		nil,
 		openrtb_ext.NormalizeBidderName,
+		nil,
+		usersync.Base64Decoder{},
 	}
 
 	testStoreVideoAttr := []bool{true, true, false, false, false}
@@ -1227,6 +1239,53 @@ func TestStoredRequests(t *testing.T) {
 	}
 }
 
+func TestStoredRequestChainInDebugOutput(t *testing.T) {
+	storedFetcher := stored_requests.WithInheritance(&mockInheritanceFetcher{
+		requests: map[string]json.RawMessage{
+			"child":  json.RawMessage(`{"site":{"page":"https://example.com"},"ext":{"prebid":{"storedrequest":{"extends":"parent"}}}}`),
+			"parent": json.RawMessage(`{"tmax":500,"site":{"domain":"example.com"}}`),
+		},
+		imps: map[string]json.RawMessage{
+			"imp-child":  json.RawMessage(`{"id":"imp","banner":{"format":[{"w":300,"h":250}]},"ext":{"prebid":{"storedrequest":{"extends":"imp-parent"}}}}`),
+			"imp-parent": json.RawMessage(`{"ext":{"appnexus":{"placementId":12883451}}}`),
+		},
+	})
+
+	endpoint, _ := NewEndpoint(
+		fakeUUIDGenerator{},
+		&resolvedRequestExchange{},
+		ortb.NewRequestValidator(openrtb_ext.BuildBidderMap(), map[string]string{}, mockBidderParamValidator{}),
+		storedFetcher,
+		empty_fetcher.EmptyFetcher{},
+		&config.Configuration{MaxRequestSize: maxSize},
+		&metricsConfig.NilMetricsEngine{},
+		analyticsBuild.New(&config.Analytics{}),
+		map[string]string{},
+		[]byte{},
+		openrtb_ext.BuildBidderMap(),
+		empty_fetcher.EmptyFetcher{},
+		hooks.EmptyPlanBuilder{},
+		nil,
+		nil,
+	)
+
+	requestBody := `{"id":"req","test":1,"imp":[{"id":"imp","ext":{"prebid":{"storedrequest":{"id":"imp-child"}}}}],"ext":{"prebid":{"storedrequest":{"id":"child"}}}}`
+	request := httptest.NewRequest("POST", "/openrtb2/auction", strings.NewReader(requestBody))
+	recorder := httptest.NewRecorder()
+	endpoint(recorder, request, nil)
+
+	assert.Equal(t, http.StatusOK, recorder.Code, recorder.Body.String())
+	requestChain, _, _, err := jsonparser.Get(recorder.Body.Bytes(), "ext", "debug", "resolvedrequest", "ext", "prebid", "storedrequest", "chain")
+	assert.NoError(t, err)
+	assert.JSONEq(t, `["parent"]`, string(requestChain), "The chain of the Stored Request should show in the debug output")
+	impChain, _, _, err := jsonparser.Get(recorder.Body.Bytes(), "ext", "debug", "resolvedrequest", "imp", "[0]", "ext", "prebid", "storedrequest", "chain")
+	assert.NoError(t, err)
+	assert.JSONEq(t, `["imp-parent"]`, string(impChain), "The chain of the Stored Imp should show in the debug output")
+	domain, err := jsonparser.GetString(recorder.Body.Bytes(), "ext", "debug", "resolvedrequest", "site", "domain")
+	assert.NoError(t, err)
+	assert.Equal(t, "example.com", domain, "The parent Stored Request should be merged")
+}
+
 func TestMergeBidderParams(t *testing.T) {
 	testCases := []struct {
 		description         string
@@ -1646,6 +1705,8 @@ func TestValidateRequest(t *testing.T) {
 		hooks.EmptyPlanBuilder{},
 		nil,
 		openrtb_ext.NormalizeBidderName,
+		nil,
+		usersync.Base64Decoder{},
 	}
 
 	testCases := []struct {
@@ -2364,6 +2425,8 @@ func TestSetIntegrationType(t *testing.T) {
 		hooks.EmptyPlanBuilder{},
 		nil,
 		openrtb_ext.NormalizeBidderName,
+		nil,
+		usersync.Base64Decoder{},
 	}
 
 	testCases := []struct {
@@ -2431,6 +2494,8 @@ func TestStoredRequestGenerateUuid(t *testing.T) {
 		hooks.EmptyPlanBuilder{},
 		nil,
 		openrtb_ext.NormalizeBidderName,
+		nil,
+		usersync.Base64Decoder{},
 	}
 
 	req := &openrtb2.BidRequest{}
@@ -2536,6 +2601,8 @@ func TestOversizedRequest(t *testing.T) {
 		hooks.EmptyPlanBuilder{},
 		nil,
 		openrtb_ext.NormalizeBidderName,
+		nil,
+		usersync.Base64Decoder{},
 	}
 
 	req := httptest.NewRequest("POST", "/openrtb2/auction", strings.NewReader(reqBody))
@@ -2576,6 +2643,8 @@ func TestRequestSizeEdgeCase(t *testing.T) {
 		hooks.EmptyPlanBuilder{},
 		nil,
 		openrtb_ext.NormalizeBidderName,
+		nil,
+		usersync.Base64Decoder{},
 	}
 
 	req := httptest.NewRequest("POST", "/openrtb2/auction", strings.NewReader(reqBody))
@@ -2609,6 +2678,7 @@ func TestNoEncoding(t *testing.T) {
 		empty_fetcher.EmptyFetcher{},
 		hooks.EmptyPlanBuilder{},
 		nil,
+		nil,
 	)
 	request := httptest.NewRequest("POST", "/openrtb2/auction", strings.NewReader(validRequest(t, "site.json")))
 	recorder := httptest.NewRecorder()
@@ -2694,6 +2764,7 @@ func TestContentType(t *testing.T) {
 		empty_fetcher.EmptyFetcher{},
 		hooks.EmptyPlanBuilder{},
 		nil,
+		nil,
 	)
 	request := httptest.NewRequest("POST", "/openrtb2/auction", strings.NewReader(validRequest(t, "site.json")))
 	recorder := httptest.NewRecorder()
@@ -2737,6 +2808,8 @@ func TestCurrencyTrunc(t *testing.T) {
 		hooks.EmptyPlanBuilder{},
 		nil,
 		openrtb_ext.NormalizeBidderName,
+		nil,
+		usersync.Base64Decoder{},
 	}
 
 	ui := int64(1)
@@ -2786,6 +2859,8 @@ func TestCCPAInvalid(t *testing.T) {
 		hooks.EmptyPlanBuilder{},
 		nil,
 		openrtb_ext.NormalizeBidderName,
+		nil,
+		usersync.Base64Decoder{},
 	}
 
 	ui := int64(1)
@@ -2839,6 +2914,8 @@ func TestNoSaleInvalid(t *testing.T) {
 		hooks.EmptyPlanBuilder{},
 		nil,
 		openrtb_ext.NormalizeBidderName,
+		nil,
+		usersync.Base64Decoder{},
 	}
 
 	ui := int64(1)
@@ -2895,6 +2972,8 @@ func TestValidateSourceTID(t *testing.T) {
 		hooks.EmptyPlanBuilder{},
 		nil,
 		openrtb_ext.NormalizeBidderName,
+		nil,
+		usersync.Base64Decoder{},
 	}
 
 	ui := int64(1)
@@ -2941,6 +3020,8 @@ func TestSChainInvalid(t *testing.T) {
 		hooks.EmptyPlanBuilder{},
 		nil,
 		openrtb_ext.NormalizeBidderName,
+		nil,
+		usersync.Base64Decoder{},
 	}
 
 	ui := int64(1)
@@ -3382,6 +3463,8 @@ func TestEidPermissionsInvalid(t *testing.T) {
 		hooks.EmptyPlanBuilder{},
 		nil,
 		openrtb_ext.NormalizeBidderName,
+		nil,
+		usersync.Base64Decoder{},
 	}
 
 	ui := int64(1)
@@ -3656,6 +3739,7 @@ func TestIOS14EndToEnd(t *testing.T) {
 		empty_fetcher.EmptyFetcher{},
 		hooks.EmptyPlanBuilder{},
 		nil,
+		nil,
 	)
 
 	httpReq := httptest.NewRequest("POST", "/openrtb2/auction", strings.NewReader(validRequest(t, "app-ios140-no-ifa.json")))
@@ -3724,6 +3808,8 @@ func TestAuctionWarnings(t *testing.T) {
 		hooks.EmptyPlanBuilder{},
 		nil,
 		openrtb_ext.NormalizeBidderName,
+		nil,
+		usersync.Base64Decoder{},
 	}
 	for _, test := range testCases {
 		t.Run(test.name, func(t *testing.T) {
@@ -3771,13 +3857,15 @@ func TestParseRequestParseImpInfoError(t *testing.T) {
 		hooks.EmptyPlanBuilder{},
 		nil,
 		openrtb_ext.NormalizeBidderName,
+		nil,
+		usersync.Base64Decoder{},
 	}
 
 	hookExecutor := hookexecution.NewHookExecutor(deps.hookExecutionPlanBuilder, hookexecution.EndpointAuction, deps.metricsEngine)
 
 	req := httptest.NewRequest("POST", "/openrtb2/auction", strings.NewReader(reqBody))
 
-	resReq, impExtInfoMap, _, _, _, _, errL := deps.parseRequest(req, &metrics.Labels{}, hookExecutor)
+	resReq, impExtInfoMap, _, _, _, _, errL := deps.parseRequest(context.Background(), req, &metrics.Labels{}, hookExecutor)
 
 	assert.Nil(t, resReq, "Result request should be nil due to incorrect imp")
 	assert.Nil(t, impExtInfoMap, "Impression info map should be nil due to incorrect imp")
@@ -3852,6 +3940,8 @@ func TestParseGzipedRequest(t *testing.T) {
 		hooks.EmptyPlanBuilder{},
 		nil,
 		openrtb_ext.NormalizeBidderName,
+		nil,
+		usersync.Base64Decoder{},
 	}
 
 	hookExecutor := hookexecution.NewHookExecutor(deps.hookExecutionPlanBuilder, hookexecution.EndpointAuction, deps.metricsEngine)
@@ -3871,7 +3961,7 @@ func TestParseGzipedRequest(t *testing.T) {
 		} else {
 			req = httptest.NewRequest("POST", "/openrtb2/auction", bytes.NewReader(reqBody))
 		}
-		resReq, impExtInfoMap, _, _, _, _, errL := deps.parseRequest(req, &metrics.Labels{}, hookExecutor)
+		resReq, impExtInfoMap, _, _, _, _, errL := deps.parseRequest(context.Background(), req, &metrics.Labels{}, hookExecutor)
 
 		if test.expectedErr == "" {
 			assert.Nil(t, errL, "Error list should be nil", test.desc)
@@ -3886,6 +3976,67 @@ func TestParseGzipedRequest(t *testing.T) {
 	}
 }
 
+func TestGetRequestBodyReader(t *testing.T) {
+	body := []byte(`{"id":"some-request-id"}`)
+
+	testCases := []struct {
+		description    string
+		contentEnc     string
+		compressionCfg config.CompressionInfo
+		expectedErr    string
+	}{
+		{
+			description:    "Not compressed",
+			contentEnc:     "",
+			compressionCfg: config.CompressionInfo{},
+		},
+		{
+			description:    "Zstd compression enabled",
+			contentEnc:     "zstd",
+			compressionCfg: config.CompressionInfo{ZSTD: true},
+		},
+		{
+			description:    "Brotli compression enabled",
+			contentEnc:     "br",
+			compressionCfg: config.CompressionInfo{GZIP: true, Brotli: true},
+		},
+		{
+			description:    "Zstd compression disabled",
+			contentEnc:     "zstd",
+			compressionCfg: config.CompressionInfo{GZIP: true},
+			expectedErr:    "Content-Encoding of type zstd is not supported",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			deps := &endpointDeps{cfg: &config.Configuration{Compression: config.Compression{Request: test.compressionCfg}}}
+
+			reqBody := body
+			if test.contentEnc != "" {
+				var compressed bytes.Buffer
+				encoder, err := httputil.AcquireEncoder(httputil.ContentEncoding(test.contentEnc), &compressed)
+				require.NoError(t, err)
+				encoder.Write(body)
+				require.NoError(t, encoder.Close())
+				reqBody = compressed.Bytes()
+			}
+			req := httptest.NewRequest("POST", "/openrtb2/auction", bytes.NewReader(reqBody))
+			req.Header.Set("Content-Encoding", test.contentEnc)
+
+			r, err := deps.getRequestBodyReader(req)
+			if test.expectedErr != "" {
+				assert.EqualError(t, err, test.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			decoded, err := io.ReadAll(r)
+			assert.NoError(t, err)
+			assert.Equal(t, body, decoded)
+		})
+	}
+}
+
 func TestAuctionResponseHeaders(t *testing.T) {
 	testCases := []struct {
 		description     string
@@ -3955,6 +4106,7 @@ func TestAuctionResponseHeaders(t *testing.T) {
 		empty_fetcher.EmptyFetcher{},
 		hooks.EmptyPlanBuilder{},
 		nil,
+		nil,
 	)
 
 	for _, test := range testCases {
@@ -4035,13 +4187,15 @@ func TestParseRequestMergeBidderParams(t *testing.T) {
 				hooks.EmptyPlanBuilder{},
 				nil,
 				openrtb_ext.NormalizeBidderName,
+				nil,
+				usersync.Base64Decoder{},
 			}
 
 			hookExecutor := hookexecution.NewHookExecutor(deps.hookExecutionPlanBuilder, hookexecution.EndpointAuction, deps.metricsEngine)
 
 			req := httptest.NewRequest("POST", "/openrtb2/auction", strings.NewReader(test.givenRequestBody))
 
-			resReq, _, _, _, _, _, errL := deps.parseRequest(req, &metrics.Labels{}, hookExecutor)
+			resReq, _, _, _, _, _, errL := deps.parseRequest(context.Background(), req, &metrics.Labels{}, hookExecutor)
 
 			assert.NoError(t, resReq.RebuildRequest())
 
@@ -4141,13 +4295,15 @@ func TestParseRequestStoredResponses(t *testing.T) {
 				hooks.EmptyPlanBuilder{},
 				nil,
 				openrtb_ext.NormalizeBidderName,
+				nil,
+				usersync.Base64Decoder{},
 			}
 
 			hookExecutor := hookexecution.NewHookExecutor(deps.hookExecutionPlanBuilder, hookexecution.EndpointAuction, deps.metricsEngine)
 
 			req := httptest.NewRequest("POST", "/openrtb2/auction", strings.NewReader(test.givenRequestBody))
 
-			_, _, storedResponses, _, _, _, errL := deps.parseRequest(req, &metrics.Labels{}, hookExecutor)
+			_, _, storedResponses, _, _, _, errL := deps.parseRequest(context.Background(), req, &metrics.Labels{}, hookExecutor)
 
 			if test.expectedErrorCount == 0 {
 				assert.Equal(t, test.expectedStoredResponses, storedResponses, "stored responses should match")
@@ -4254,12 +4410,14 @@ func TestParseRequestStoredBidResponses(t *testing.T) {
 				hooks.EmptyPlanBuilder{},
 				nil,
 				openrtb_ext.NormalizeBidderName,
+				nil,
+				usersync.Base64Decoder{},
 			}
 
 			hookExecutor := hookexecution.NewHookExecutor(deps.hookExecutionPlanBuilder, hookexecution.EndpointAuction, deps.metricsEngine)
 
 			req := httptest.NewRequest("POST", "/openrtb2/auction", strings.NewReader(test.givenRequestBody))
-			_, _, _, storedBidResponses, _, _, errL := deps.parseRequest(req, &metrics.Labels{}, hookExecutor)
+			_, _, _, storedBidResponses, _, _, errL := deps.parseRequest(context.Background(), req, &metrics.Labels{}, hookExecutor)
 			if test.expectedErrorCount == 0 {
 				assert.Empty(t, errL)
 				assert.Equal(t, test.expectedStoredBidResponses, storedBidResponses, "stored responses should match")
@@ -4292,6 +4450,8 @@ func TestValidateStoredResp(t *testing.T) {
 		hooks.EmptyPlanBuilder{},
 		nil,
 		openrtb_ext.NormalizeBidderName,
+		nil,
+		usersync.Base64Decoder{},
 	}
 
 	testCases := []struct {
@@ -5115,13 +5275,15 @@ func TestParseRequestMultiBid(t *testing.T) {
 				hooks.EmptyPlanBuilder{},
 				nil,
 				openrtb_ext.NormalizeBidderName,
+				nil,
+				usersync.Base64Decoder{},
 			}
 
 			hookExecutor := hookexecution.NewHookExecutor(deps.hookExecutionPlanBuilder, hookexecution.EndpointAuction, deps.metricsEngine)
 
 			req := httptest.NewRequest("POST", "/openrtb2/auction", strings.NewReader(test.givenRequestBody))
 
-			resReq, _, _, _, _, _, errL := deps.parseRequest(req, &metrics.Labels{}, hookExecutor)
+			resReq, _, _, _, _, _, errL := deps.parseRequest(context.Background(), req, &metrics.Labels{}, hookExecutor)
 
 			assert.NoError(t, resReq.RebuildRequest())
 
@@ -5144,6 +5306,32 @@ func (cf *mockStoredResponseFetcher) FetchResponses(ctx context.Context, ids []s
 	return cf.data, nil
 }
 
+// mockInheritanceFetcher returns the stored data it holds for the requested IDs.
+type mockInheritanceFetcher struct {
+	requests map[string]json.RawMessage
+	imps     map[string]json.RawMessage
+}
+
+func (cf *mockInheritanceFetcher) FetchRequests(ctx context.Context, requestIDs []string, impIDs []string) (requestData map[string]json.RawMessage, impData map[string]json.RawMessage, errs []error) {
+	requestData = make(map[string]json.RawMessage, len(requestIDs))
+	for _, id := range requestIDs {
+		if data, ok := cf.requests[id]; ok {
+			requestData[id] = data
+		}
+	}
+	impData = make(map[string]json.RawMessage, len(impIDs))
+	for _, id := range impIDs {
+		if data, ok := cf.imps[id]; ok {
+			impData[id] = data
+		}
+	}
+	return requestData, impData, nil
+}
+
+func (cf *mockInheritanceFetcher) FetchResponses(ctx context.Context, ids []string) (data map[string]json.RawMessage, errs []error) {
+	return nil, nil
+}
+
 func getObject(t *testing.T, filename, key string) json.RawMessage {
 	requestData, err := os.ReadFile("sample-requests/valid-whole/supplementary/" + filename)
 	if err != nil {
@@ -5846,6 +6034,8 @@ func TestValidateRequestCookieDeprecation(t *testing.T) {
 		hooks.EmptyPlanBuilder{},
 		nil,
 		openrtb_ext.NormalizeBidderName,
+		nil,
+		usersync.Base64Decoder{},
 	}
 
 	for _, test := range testCases {
@@ -38,6 +38,7 @@ import (
 	pbc "github.com/prebid/prebid-server/v4/prebid_cache_client"
 	"github.com/prebid/prebid-server/v4/stored_requests"
 	"github.com/prebid/prebid-server/v4/stored_requests/backends/empty_fetcher"
+	"github.com/prebid/prebid-server/v4/usersync"
 	"github.com/prebid/prebid-server/v4/util/iputil"
 	"github.com/prebid/prebid-server/v4/util/jsonutil"
 	"github.com/prebid/prebid-server/v4/util/uuidutil"
@@ -935,6 +936,28 @@ func (e *nobidExchange) HoldAuction(ctx context.Context, auctionRequest *exchang
 	}, nil
 }
 
+// resolvedRequestExchange returns the resolved request in the debug output, like the exchange does.
+// implements the Exchange interface
+type resolvedRequestExchange struct{}
+
+func (e *resolvedRequestExchange) HoldAuction(ctx context.Context, auctionRequest *exchange.AuctionRequest, debugLog *exchange.DebugLog) (*exchange.AuctionResponse, error) {
+	r := auctionRequest.BidRequestWrapper
+	if err := r.RebuildRequest(); err != nil {
+		return nil, err
+	}
+	resolvedRequest, err := jsonutil.Marshal(r.BidRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	return &exchange.AuctionResponse{
+		BidResponse: &openrtb2.BidResponse{
+			ID:  r.BidRequest.ID,
+			Ext: json.RawMessage(`{"debug":{"resolvedrequest":` + string(resolvedRequest) + `}}`),
+		},
+	}, nil
+}
+
 // mockCurrencyRatesClient is a mock currency rate server and the rates it returns
 // are set in the JSON test file
 type mockCurrencyRatesClient struct {
@@ -1392,7 +1415,7 @@ func buildTestEndpoint(test testCase, cfg *config.Configuration) (httprouter.Han
 		planBuilder = hooks.EmptyPlanBuilder{}
 	}
 
-	var endpointBuilder func(uuidutil.UUIDGenerator, exchange.Exchange, ortb.RequestValidator, stored_requests.Fetcher, stored_requests.AccountFetcher, *config.Configuration, metrics.MetricsEngine, analytics.Runner, map[string]string, []byte, map[string]openrtb_ext.BidderName, stored_requests.Fetcher, hooks.ExecutionPlanBuilder, *exchange.TmaxAdjustmentsPreprocessed) (httprouter.Handle, error)
+	var endpointBuilder func(uuidutil.UUIDGenerator, exchange.Exchange, ortb.RequestValidator, stored_requests.Fetcher, stored_requests.AccountFetcher, *config.Configuration, metrics.MetricsEngine, analytics.Runner, map[string]string, []byte, map[string]openrtb_ext.BidderName, stored_requests.Fetcher, hooks.ExecutionPlanBuilder, *exchange.TmaxAdjustmentsPreprocessed, usersync.UIDStore) (httprouter.Handle, error)
 
 	switch test.endpointType {
 	case AMP_ENDPOINT:
@@ -1416,6 +1439,7 @@ func buildTestEndpoint(test testCase, cfg *config.Configuration) (httprouter.Han
 		storedResponseFetcher,
 		planBuilder,
 		nil,
+		nil,
 	)
 
 	return endpoint, testExchange.(*exchangeTestWrapper), mockBidServersArray, mockCurrencyRatesServer, err
@@ -1522,6 +1546,7 @@ func (p *fakePermissions) AuctionActivitiesAllowed(ctx context.Context, bidderCo
 }
 
 type mockPlanBuilder struct {
+	hooks.EmptyPlanBuilder
 	entrypointPlan               hooks.Plan[hookstage.Entrypoint]
 	rawAuctionPlan               hooks.Plan[hookstage.RawAuctionRequest]
 	processedAuctionPlan         hooks.Plan[hookstage.ProcessedAuctionRequest]
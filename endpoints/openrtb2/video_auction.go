@@ -16,6 +16,7 @@ import (
 	"github.com/gofrs/uuid"
 	"github.com/julienschmidt/httprouter"
 	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/openrtb/v20/openrtb3"
 	"github.com/prebid/prebid-server/v4/hooks"
 	"github.com/prebid/prebid-server/v4/hooks/hookexecution"
 	"github.com/prebid/prebid-server/v4/logger"
@@ -57,7 +58,9 @@ func NewVideoEndpoint(
 	defReqJSON []byte,
 	bidderMap map[string]openrtb_ext.BidderName,
 	cache prebid_cache_client.Client,
+	hookExecutionPlanBuilder hooks.ExecutionPlanBuilder,
 	tmaxAdjustments *exchange.TmaxAdjustmentsPreprocessed,
+	uidStore usersync.UIDStore,
 ) (httprouter.Handle, error) {
 
 	if ex == nil || requestValidator == nil || requestsById == nil || accounts == nil || cfg == nil || met == nil {
@@ -91,9 +94,11 @@ func NewVideoEndpoint(
 		videoEndpointRegexp,
 		ipValidator,
 		empty_fetcher.EmptyFetcher{},
-		hooks.EmptyPlanBuilder{},
+		hookExecutionPlanBuilder,
 		tmaxAdjustments,
-		openrtb_ext.NormalizeBidderName}).VideoAuctionEndpoint), nil
+		openrtb_ext.NormalizeBidderName,
+		uidStore,
+		usersync.NewDecoder(&cfg.HostCookie, met)}).VideoAuctionEndpoint), nil
 }
 
 /*
@@ -128,6 +133,8 @@ func (deps *endpointDeps) VideoAuctionEndpoint(w http.ResponseWriter, r *http.Re
 		return
 	}
 
+	hookExecutor := hookexecution.NewHookExecutor(deps.hookExecutionPlanBuilder, hookexecution.EndpointVideo, deps.metricsEngine)
+
 	vo := analytics.VideoObject{
 		Status:    http.StatusOK,
 		Errors:    make([]error, 0),
@@ -173,8 +180,15 @@ func (deps *endpointDeps) VideoAuctionEndpoint(w http.ResponseWriter, r *http.Re
 	w.Header().Set("X-Prebid", version.BuildXPrebidHeader(version.Ver))
 	setBrowsingTopicsHeader(w, r)
 
+	body, err := deps.getRequestBodyReader(r)
+	if err != nil {
+		handleError(&labels, w, []error{err}, &vo, &debugLog)
+		return
+	}
+	defer body.Close()
+
 	lr := &io.LimitedReader{
-		R: r.Body,
+		R: body,
 		N: deps.cfg.MaxRequestSize,
 	}
 	requestJson, err := io.ReadAll(lr)
@@ -184,6 +198,12 @@ func (deps *endpointDeps) VideoAuctionEndpoint(w http.ResponseWriter, r *http.Re
 	}
 	labels.RequestSize = len(requestJson)
 
+	requestJson, rejectErr := hookExecutor.ExecuteEntrypointStage(r, requestJson)
+	if rejectErr != nil {
+		deps.rejectVideoRequest(*rejectErr, w, hookExecutor, nil, nil, nil, &labels, &vo, &debugLog)
+		return
+	}
+
 	resolvedRequest := requestJson
 	if debugLog.DebugEnabledOrOverridden {
 		debugLog.Data.Request = string(requestJson)
@@ -286,9 +306,7 @@ func (deps *endpointDeps) VideoAuctionEndpoint(w http.ResponseWriter, r *http.Re
 	}
 
 	// Read Usersyncs/Cookie
-	decoder := usersync.Base64Decoder{}
-	usersyncs := usersync.ReadCookie(r, decoder, &deps.cfg.HostCookie)
-	usersync.SyncHostCookie(r, usersyncs, &deps.cfg.HostCookie)
+	usersyncs := deps.readUserSyncs(ctx, r)
 
 	if bidReqWrapper.App != nil {
 		labels.Source = metrics.DemandApp
@@ -310,6 +328,18 @@ func (deps *endpointDeps) VideoAuctionEndpoint(w http.ResponseWriter, r *http.Re
 		return
 	}
 
+	hookExecutor.SetAccount(account)
+	bidReqWrapper, rejectErr, err = deps.executeVideoRawAuctionStage(hookExecutor, bidReqWrapper)
+	if rejectErr != nil {
+		deps.rejectVideoRequest(*rejectErr, w, hookExecutor, bidReqWrapper, account, podErrors, &labels, &vo, &debugLog)
+		return
+	}
+	if err != nil {
+		handleError(&labels, w, []error{err}, &vo, &debugLog)
+		return
+	}
+	bidReq = bidReqWrapper.BidRequest
+
 	tcf2Config, gdprSignal, gdprEnforced, gdprErrs := deps.processGDPR(bidReqWrapper, account.GDPR, labels.RType)
 	errL = append(errL, gdprErrs...)
 
@@ -327,6 +357,8 @@ func (deps *endpointDeps) VideoAuctionEndpoint(w http.ResponseWriter, r *http.Re
 
 	activityControl = privacy.NewActivityControl(&account.Privacy)
 
+	hookExecutor.SetActivityControl(activityControl)
+
 	warnings := errortypes.WarningOnly(errL)
 
 	secGPC := r.Header.Get("Sec-GPC")
@@ -340,7 +372,7 @@ func (deps *endpointDeps) VideoAuctionEndpoint(w http.ResponseWriter, r *http.Re
 		Warnings:                   warnings,
 		GlobalPrivacyControlHeader: secGPC,
 		PubID:                      labels.PubID,
-		HookExecutor:               hookexecution.EmptyHookExecutor{},
+		HookExecutor:               hookExecutor,
 		TCF2Config:                 tcf2Config,
 		TmaxAdjustments:            deps.tmaxAdjustments,
 		Activities:                 activityControl,
@@ -361,24 +393,149 @@ func (deps *endpointDeps) VideoAuctionEndpoint(w http.ResponseWriter, r *http.Re
 	}
 	vo.Response = response
 	vo.SeatNonBid = auctionResponse.GetSeatNonBid()
-	if err != nil {
+	rejectErr, isRejectErr := hookexecution.CastRejectErr(err)
+	if err != nil && !isRejectErr {
 		errL := []error{err}
 		handleError(&labels, w, errL, &vo, &debugLog)
 		return
+	} else if isRejectErr {
+		deps.rejectVideoRequest(*rejectErr, w, hookExecutor, bidReqWrapper, account, podErrors, &labels, &vo, &debugLog)
+		return
+	}
+
+	if bidReq.Test == 1 {
+		err = setSeatNonBidRaw(bidReqWrapper, auctionResponse)
+		if err != nil {
+			logger.Errorf("Error setting seat non-bid: %v", err)
+		}
+	}
+
+	deps.sendVideoResponse(w, hookExecutor, response, bidReqWrapper, account, podErrors, &labels, &vo, &debugLog)
+}
+
+// executeVideoRawAuctionStage runs the raw auction request stage hooks against the OpenRTB request built
+// from the video request. The request is rebuilt from the hook payload only if a hook changed it, in which
+// case its imps are validated again since they were built from the ad pods of the video request.
+func (deps *endpointDeps) executeVideoRawAuctionStage(hookExecutor hookexecution.HookStageExecutor, req *openrtb_ext.RequestWrapper) (*openrtb_ext.RequestWrapper, *hookexecution.RejectError, error) {
+	requestJson, err := jsonutil.Marshal(req.BidRequest)
+	if err != nil {
+		return req, nil, err
+	}
+
+	requestJson, rejectErr := hookExecutor.ExecuteRawAuctionStage(requestJson)
+	if rejectErr != nil || !hasPayloadUpdatesAt(hooks.StageRawAuctionRequest.String(), hookExecutor.GetOutcomes()) {
+		return req, rejectErr, nil
+	}
+
+	bidReq := &openrtb2.BidRequest{}
+	if err := jsonutil.UnmarshalValid(requestJson, bidReq); err != nil {
+		return req, nil, err
+	}
+
+	updatedReq := &openrtb_ext.RequestWrapper{BidRequest: bidReq}
+	if err := openrtb_ext.ConvertUpTo26(updatedReq); err != nil {
+		return req, nil, err
+	}
+
+	if err := ortb.SetDefaults(updatedReq, deps.cfg.TmaxDefault); err != nil {
+		return req, nil, err
+	}
+
+	if err := validateVideoImps(req, updatedReq); err != nil {
+		return req, nil, err
+	}
+
+	return updatedReq, nil, nil
+}
+
+// validateVideoImps checks that every imp of the updated request is still an ad pod impression of the
+// original request: it must have a video object and an id created from one of the pods.
+func validateVideoImps(original, updated *openrtb_ext.RequestWrapper) error {
+	if len(updated.Imp) == 0 {
+		return errors.New("request.imp must contain at least one element.")
+	}
+
+	podImpIDs := make(map[string]struct{}, len(original.Imp))
+	for _, imp := range original.Imp {
+		podImpIDs[imp.ID] = struct{}{}
+	}
+
+	for i, imp := range updated.Imp {
+		if imp.Video == nil {
+			return fmt.Errorf("request.imp[%d] missing required field: video", i)
+		}
+		if _, ok := podImpIDs[imp.ID]; !ok {
+			return fmt.Errorf("request.imp[%d].id %s does not match an ad pod impression", i, imp.ID)
+		}
+	}
+	return nil
+}
+
+func (deps *endpointDeps) rejectVideoRequest(
+	rejectErr hookexecution.RejectError,
+	w http.ResponseWriter,
+	hookExecutor hookexecution.HookStageExecutor,
+	req *openrtb_ext.RequestWrapper,
+	account *config.Account,
+	podErrors []PodError,
+	labels *metrics.Labels,
+	vo *analytics.VideoObject,
+	debugLog *exchange.DebugLog,
+) {
+	response := &openrtb2.BidResponse{NBR: openrtb3.NoBidReason(rejectErr.NBR).Ptr()}
+	if req != nil && req.BidRequest != nil {
+		response.ID = req.ID
+	}
+
+	vo.RequestWrapper = req
+	vo.Response = response
+	vo.Errors = append(vo.Errors, rejectErr)
+
+	deps.sendVideoResponse(w, hookExecutor, response, req, account, podErrors, labels, vo, debugLog)
+}
+
+func (deps *endpointDeps) sendVideoResponse(
+	w http.ResponseWriter,
+	hookExecutor hookexecution.HookStageExecutor,
+	response *openrtb2.BidResponse,
+	req *openrtb_ext.RequestWrapper,
+	account *config.Account,
+	podErrors []PodError,
+	labels *metrics.Labels,
+	vo *analytics.VideoObject,
+	debugLog *exchange.DebugLog,
+) {
+	hookExecutor.ExecuteAuctionResponseStage(response)
+
+	var bidReq *openrtb2.BidRequest
+	if req != nil {
+		bidReq = req.BidRequest
+	}
+
+	stageOutcomes := hookExecutor.GetOutcomes()
+	vo.HookExecutionOutcome = stageOutcomes
+
+	ext, warns, err := hookexecution.EnrichExtBidResponse(response.Ext, stageOutcomes, bidReq, account)
+	if err != nil {
+		err = fmt.Errorf("Failed to enrich Bid Response with hook debug information: %s", err)
+		logger.Errorf("%v", err)
+		vo.Errors = append(vo.Errors, err)
+	} else {
+		response.Ext = ext
+	}
+
+	if len(warns) > 0 {
+		vo.Errors = append(vo.Errors, warns...)
 	}
 
 	//build simplified response
 	bidResp, err := buildVideoResponse(response, podErrors)
 	if err != nil {
 		errL := []error{err}
-		handleError(&labels, w, errL, &vo, &debugLog)
+		handleError(labels, w, errL, vo, debugLog)
 		return
 	}
-	if bidReq.Test == 1 {
-		err = setSeatNonBidRaw(bidReqWrapper, auctionResponse)
-		if err != nil {
-			logger.Errorf("Error setting seat non-bid: %v", err)
-		}
+	if bidReq != nil && bidReq.Test == 1 {
 		bidResp.Ext = response.Ext
 	}
 
@@ -399,10 +556,13 @@ func (deps *endpointDeps) VideoAuctionEndpoint(w http.ResponseWriter, r *http.Re
 
 	vo.VideoResponse = bidResp
 
-	resp, err := jsonutil.Marshal(bidResp)
+	// Exitpoint will modify the response and set response headers according to hook implementation.
+	finalResponse := hookExecutor.ExecuteExitpointStage(bidResp, w)
+
+	resp, err := jsonutil.Marshal(finalResponse)
 	if err != nil {
 		errL := []error{err}
-		handleError(&labels, w, errL, &vo, &debugLog)
+		handleError(labels, w, errL, vo, debugLog)
 		return
 	}
 
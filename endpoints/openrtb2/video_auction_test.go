@@ -17,6 +17,7 @@ import (
 	"github.com/prebid/prebid-server/v4/errortypes"
 	"github.com/prebid/prebid-server/v4/exchange"
 	"github.com/prebid/prebid-server/v4/hooks"
+	"github.com/prebid/prebid-server/v4/hooks/hookstage"
 	"github.com/prebid/prebid-server/v4/metrics"
 	metricsConfig "github.com/prebid/prebid-server/v4/metrics/config"
 	"github.com/prebid/prebid-server/v4/openrtb_ext"
@@ -24,6 +25,7 @@ import (
 	"github.com/prebid/prebid-server/v4/prebid_cache_client"
 	"github.com/prebid/prebid-server/v4/privacy"
 	"github.com/prebid/prebid-server/v4/stored_requests/backends/empty_fetcher"
+	"github.com/prebid/prebid-server/v4/usersync"
 	"github.com/prebid/prebid-server/v4/util/jsonutil"
 	"github.com/prebid/prebid-server/v4/util/ptrutil"
 
@@ -32,6 +34,7 @@ import (
 	gometrics "github.com/rcrowley/go-metrics"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	jsonpatch "gopkg.in/evanphx/json-patch.v5"
 )
 
 func TestVideoEndpointImpressionsNumber(t *testing.T) {
@@ -1287,6 +1290,8 @@ func mockDepsWithMetrics(t *testing.T, ex *mockExchangeVideo) (*endpointDeps, *m
 		hooks.EmptyPlanBuilder{},
 		nil,
 		openrtb_ext.NormalizeBidderName,
+		nil,
+		usersync.Base64Decoder{},
 	}
 	return deps, metrics, mockModule
 }
@@ -1338,6 +1343,8 @@ func mockDeps(t *testing.T, ex *mockExchangeVideo) *endpointDeps {
 		hooks.EmptyPlanBuilder{},
 		nil,
 		openrtb_ext.NormalizeBidderName,
+		nil,
+		usersync.Base64Decoder{},
 	}
 }
 
@@ -1363,6 +1370,8 @@ func mockDepsAppendBidderNames(t *testing.T, ex *mockExchangeAppendBidderNames)
 		hooks.EmptyPlanBuilder{},
 		nil,
 		openrtb_ext.NormalizeBidderName,
+		nil,
+		usersync.Base64Decoder{},
 	}
 
 	return deps
@@ -1390,6 +1399,8 @@ func mockDepsNoBids(t *testing.T, ex *mockExchangeVideoNoBids) *endpointDeps {
 		hooks.EmptyPlanBuilder{},
 		nil,
 		openrtb_ext.NormalizeBidderName,
+		nil,
+		usersync.Base64Decoder{},
 	}
 
 	return edep
@@ -1575,6 +1586,8 @@ func TestVideoEndpointDisabled(t *testing.T) {
 		hooks.EmptyPlanBuilder{},
 		nil,
 		openrtb_ext.NormalizeBidderName,
+		nil,
+		usersync.Base64Decoder{},
 	}
 
 	deps.VideoAuctionEndpoint(recorder, req, nil)
@@ -1582,3 +1595,167 @@ func TestVideoEndpointDisabled(t *testing.T) {
 	assert.Equal(t, http.StatusNotImplemented, recorder.Code, "Expected 501 status code when video endpoint is disabled")
 	assert.Equal(t, "The video endpoint is deprecated and will be removed in 5.0. You may re-enable it via the host configuration setting video.enable_deprecated_endpoint", recorder.Body.String(), "Unexpected error message")
 }
+
+func TestVideoAuctionHookExecution(t *testing.T) {
+	noBidResponse := `{"adPods":[]}`
+	testCases := []struct {
+		description         string
+		planBuilder         hooks.ExecutionPlanBuilder
+		expectedResponse    string
+		expectedStages      []string
+		expectAuctionCalled bool
+	}{
+		{
+			description:         "entrypoint-rejection",
+			planBuilder:         mockPlanBuilder{entrypointPlan: makePlan[hookstage.Entrypoint](mockRejectionHook{nbr: 123})},
+			expectedResponse:    noBidResponse,
+			expectedStages:      []string{hooks.StageEntrypoint.String()},
+			expectAuctionCalled: false,
+		},
+		{
+			description:         "raw-auction-request-rejection",
+			planBuilder:         mockPlanBuilder{rawAuctionPlan: makePlan[hookstage.RawAuctionRequest](mockRejectionHook{nbr: 123})},
+			expectedResponse:    noBidResponse,
+			expectedStages:      []string{hooks.StageRawAuctionRequest.String()},
+			expectAuctionCalled: false,
+		},
+		{
+			description:         "exitpoint-updates-response",
+			planBuilder:         mockPlanBuilder{exitpointPlan: makePlan[hookstage.Exitpoint](mockUpdateResponseHook{})},
+			expectedResponse:    `{"id":"modified-id"}`,
+			expectedStages:      nil,
+			expectAuctionCalled: true,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			ex := &mockExchangeVideo{cache: &mockCacheClient{}}
+			reqBody := readVideoTestFile(t, "sample-requests/video/video_valid_sample.json")
+			req := httptest.NewRequest("POST", "/openrtb2/video", strings.NewReader(reqBody))
+			recorder := httptest.NewRecorder()
+
+			deps, _, mockModule := mockDepsWithMetrics(t, ex)
+			deps.hookExecutionPlanBuilder = test.planBuilder
+			deps.VideoAuctionEndpoint(recorder, req, nil)
+
+			assert.Equal(t, http.StatusOK, recorder.Code)
+			assert.JSONEq(t, test.expectedResponse, recorder.Body.String())
+			assert.Equal(t, test.expectAuctionCalled, ex.lastRequest != nil)
+
+			if assert.Len(t, mockModule.videoObjects, 1) {
+				var stages []string
+				for _, outcome := range mockModule.videoObjects[0].HookExecutionOutcome {
+					stages = append(stages, outcome.Stage)
+				}
+				assert.Equal(t, test.expectedStages, stages)
+			}
+		})
+	}
+}
+
+func TestVideoAuctionRawAuctionHookUpdates(t *testing.T) {
+	testCases := []struct {
+		description         string
+		patch               string
+		expectedStatus      int
+		expectAuctionCalled bool
+	}{
+		{
+			description:         "valid-update",
+			patch:               `{"tmax":500}`,
+			expectedStatus:      http.StatusOK,
+			expectAuctionCalled: true,
+		},
+		{
+			description:         "imps-removed",
+			patch:               `{"imp":[]}`,
+			expectedStatus:      http.StatusInternalServerError,
+			expectAuctionCalled: false,
+		},
+		{
+			description:         "imp-not-from-pod",
+			patch:               `{"imp":[{"id":"other","banner":{"w":300,"h":250}}]}`,
+			expectedStatus:      http.StatusInternalServerError,
+			expectAuctionCalled: false,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			ex := &mockExchangeVideo{cache: &mockCacheClient{}}
+			reqBody := readVideoTestFile(t, "sample-requests/video/video_valid_sample.json")
+			req := httptest.NewRequest("POST", "/openrtb2/video", strings.NewReader(reqBody))
+			recorder := httptest.NewRecorder()
+
+			deps, _, _ := mockDepsWithMetrics(t, ex)
+			deps.hookExecutionPlanBuilder = mockPlanBuilder{rawAuctionPlan: makePlan[hookstage.RawAuctionRequest](mockRawAuctionPatchHook{patch: test.patch})}
+			deps.VideoAuctionEndpoint(recorder, req, nil)
+
+			assert.Equal(t, test.expectedStatus, recorder.Code)
+			assert.Equal(t, test.expectAuctionCalled, ex.lastRequest != nil)
+		})
+	}
+}
+
+func TestValidateVideoImps(t *testing.T) {
+	original := &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{Imp: []openrtb2.Imp{
+		{ID: "1_0", Video: &openrtb2.Video{}},
+		{ID: "1_1", Video: &openrtb2.Video{}},
+	}}}
+
+	testCases := []struct {
+		description string
+		imps        []openrtb2.Imp
+		expectedErr string
+	}{
+		{
+			description: "pod-imps",
+			imps:        []openrtb2.Imp{{ID: "1_1", Video: &openrtb2.Video{MIMEs: []string{"video/mp4"}}}},
+		},
+		{
+			description: "no-imps",
+			imps:        nil,
+			expectedErr: "request.imp must contain at least one element.",
+		},
+		{
+			description: "imp-without-video",
+			imps:        []openrtb2.Imp{{ID: "1_0", Banner: &openrtb2.Banner{}}},
+			expectedErr: "request.imp[0] missing required field: video",
+		},
+		{
+			description: "imp-with-unknown-id",
+			imps:        []openrtb2.Imp{{ID: "1_0", Video: &openrtb2.Video{}}, {ID: "2_0", Video: &openrtb2.Video{}}},
+			expectedErr: "request.imp[1].id 2_0 does not match an ad pod impression",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			updated := &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{Imp: test.imps}}
+			err := validateVideoImps(original, updated)
+			if test.expectedErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, test.expectedErr)
+			}
+		})
+	}
+}
+
+type mockRawAuctionPatchHook struct {
+	patch string
+}
+
+func (m mockRawAuctionPatchHook) HandleRawAuctionHook(
+	_ context.Context,
+	_ hookstage.ModuleInvocationContext,
+	_ hookstage.RawAuctionRequestPayload,
+) (hookstage.HookResult[hookstage.RawAuctionRequestPayload], error) {
+	c := hookstage.ChangeSet[hookstage.RawAuctionRequestPayload]{}
+	c.AddMutation(func(payload hookstage.RawAuctionRequestPayload) (hookstage.RawAuctionRequestPayload, error) {
+		return jsonpatch.MergePatch(payload, []byte(m.patch))
+	}, hookstage.MutationUpdate, "body")
+
+	return hookstage.HookResult[hookstage.RawAuctionRequestPayload]{ChangeSet: c}, nil
+}
@@ -17,6 +17,9 @@ import (
 	"github.com/prebid/prebid-server/v4/config"
 	"github.com/prebid/prebid-server/v4/errortypes"
 	"github.com/prebid/prebid-server/v4/gdpr"
+	"github.com/prebid/prebid-server/v4/hooks"
+	"github.com/prebid/prebid-server/v4/hooks/hookexecution"
+	"github.com/prebid/prebid-server/v4/hooks/hookstage"
 	"github.com/prebid/prebid-server/v4/metrics"
 	"github.com/prebid/prebid-server/v4/openrtb_ext"
 	"github.com/prebid/prebid-server/v4/privacy"
@@ -25,6 +28,7 @@ import (
 	"github.com/prebid/prebid-server/v4/usersync"
 	"github.com/prebid/prebid-server/v4/util/httputil"
 	stringutil "github.com/prebid/prebid-server/v4/util/stringutil"
+	"github.com/prebid/prebid-server/v4/util/uuidutil"
 )
 
 const (
@@ -37,9 +41,10 @@ const (
 
 const uidCookieName = "uids"
 
-func NewSetUIDEndpoint(cfg *config.Configuration, syncersByBidder map[string]usersync.Syncer, gdprPermsBuilder gdpr.PermissionsBuilder, tcf2CfgBuilder gdpr.TCF2ConfigBuilder, analyticsRunner analytics.Runner, accountsFetcher stored_requests.AccountFetcher, metricsEngine metrics.MetricsEngine) httprouter.Handle {
-	encoder := usersync.Base64Encoder{}
-	decoder := usersync.Base64Decoder{}
+func NewSetUIDEndpoint(cfg *config.Configuration, syncersByBidder map[string]usersync.Syncer, gdprPermsBuilder gdpr.PermissionsBuilder, tcf2CfgBuilder gdpr.TCF2ConfigBuilder, analyticsRunner analytics.Runner, accountsFetcher stored_requests.AccountFetcher, metricsEngine metrics.MetricsEngine, hookExecutionPlanBuilder hooks.ExecutionPlanBuilder, uidStore usersync.UIDStore) httprouter.Handle {
+	encoder := usersync.NewEncoder(&cfg.HostCookie)
+	decoder := usersync.NewDecoder(&cfg.HostCookie, metricsEngine)
+	uuidGenerator := uuidutil.UUIDRandomGenerator{}
 
 	return httprouter.Handle(func(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
 		so := analytics.SetUIDObject{
@@ -54,6 +59,12 @@ func NewSetUIDEndpoint(cfg *config.Configuration, syncersByBidder map[string]use
 			handleBadStatus(w, http.StatusUnauthorized, metrics.SetUidOptOut, nil, metricsEngine, &so)
 			return
 		}
+		// only the UIDs changed since they were loaded are written to the store, so the stored UIDs are needed to
+		// tell which ones changed, or were ejected by the new one
+		if err := cookie.LoadStoredUIDs(r.Context(), uidStore); err != nil {
+			handleBadStatus(w, http.StatusServiceUnavailable, metrics.SetUidStoreError, err, metricsEngine, &so)
+			return
+		}
 		usersync.SyncHostCookie(r, cookie, &cfg.HostCookie)
 
 		query := r.URL.Query()
@@ -110,6 +121,11 @@ func NewSetUIDEndpoint(cfg *config.Configuration, syncersByBidder map[string]use
 			GPPSID: gppSID,
 		}
 
+		// gpp parsing errors are reported when the gdpr consent is extracted below
+		if gppStr := query.Get("gpp"); len(gppStr) > 0 {
+			policies.GPP, _ = gpplib.Parse(gppStr)
+		}
+
 		userSyncActivityAllowed := activityControl.Allow(privacy.ActivitySyncUser,
 			privacy.Component{Type: privacy.ComponentTypeBidder, Name: bidderName},
 			privacy.NewRequestFromPolicies(policies))
@@ -142,7 +158,21 @@ func NewSetUIDEndpoint(cfg *config.Configuration, syncersByBidder map[string]use
 			return
 		}
 
-		uid := query.Get("uid")
+		hookExecutor := hookexecution.NewHookExecutor(hookExecutionPlanBuilder, hookexecution.EndpointSetUID, metricsEngine)
+		hookExecutor.SetAccount(account)
+		hookExecutor.SetActivityControl(activityControl)
+
+		hookPayload, rejectErr := hookExecutor.ExecuteSetUIDRequestStage(hookstage.SetUIDRequestPayload{
+			Request: r,
+			Bidder:  syncer.Key(),
+			UID:     query.Get("uid"),
+		})
+		if rejectErr != nil {
+			handleBadStatus(w, http.StatusBadRequest, metrics.SetUidBadRequest, rejectErr, metricsEngine, &so)
+			return
+		}
+
+		uid := hookPayload.UID
 		so.UID = uid
 
 		if uid == "" {
@@ -158,22 +188,32 @@ func NewSetUIDEndpoint(cfg *config.Configuration, syncersByBidder map[string]use
 
 		setSiteCookie := siteCookieCheck(r.UserAgent())
 
-		// Priority Ejector Set Up
-		priorityEjector := &usersync.PriorityBidderEjector{PriorityGroups: cfg.UserSync.PriorityGroups, TieEjector: &usersync.OldestEjector{}, SyncersByBidder: syncersByBidder}
-		priorityEjector.IsSyncerPriority = isSyncerPriority(bidderName, cfg.UserSync.PriorityGroups)
-
 		// Write Cookie
-		encodedCookie, err := cookie.PrepareCookieForWrite(&cfg.HostCookie, encoder, priorityEjector)
-		if err != nil {
-			if err.Error() == errSyncerIsNotPriority.Error() {
-				w.WriteHeader(http.StatusOK)
-				w.Write([]byte("Warning: " + err.Error() + ", cookie not updated"))
-				so.Status = http.StatusOK
-				return
-			} else {
-				handleBadStatus(w, http.StatusBadRequest, metrics.SetUidBadRequest, err, metricsEngine, &so)
+		var encodedCookie string
+		if uidStore != nil {
+			// the cookie only carries the user key, so no UID needs to be ejected
+			encodedCookie, err = cookie.PrepareCookieForStore(r.Context(), uidStore, encoder, uuidGenerator)
+			if err != nil {
+				handleBadStatus(w, http.StatusServiceUnavailable, metrics.SetUidStoreError, err, metricsEngine, &so)
 				return
 			}
+		} else {
+			// Priority Ejector Set Up
+			priorityEjector := &usersync.PriorityBidderEjector{PriorityGroups: cfg.UserSync.PriorityGroups, TieEjector: &usersync.OldestEjector{}, SyncersByBidder: syncersByBidder}
+			priorityEjector.IsSyncerPriority = isSyncerPriority(bidderName, cfg.UserSync.PriorityGroups)
+
+			encodedCookie, err = cookie.PrepareCookieForWrite(&cfg.HostCookie, encoder, priorityEjector)
+			if err != nil {
+				if err.Error() == errSyncerIsNotPriority.Error() {
+					w.WriteHeader(http.StatusOK)
+					w.Write([]byte("Warning: " + err.Error() + ", cookie not updated"))
+					so.Status = http.StatusOK
+					return
+				} else {
+					handleBadStatus(w, http.StatusBadRequest, metrics.SetUidBadRequest, err, metricsEngine, &so)
+					return
+				}
+			}
 		}
 		usersync.WriteCookie(w, encodedCookie, &cfg.HostCookie, setSiteCookie)
 
@@ -17,10 +17,13 @@ import (
 	"github.com/prebid/prebid-server/v4/config"
 	"github.com/prebid/prebid-server/v4/errortypes"
 	"github.com/prebid/prebid-server/v4/gdpr"
+	"github.com/prebid/prebid-server/v4/hooks"
+	"github.com/prebid/prebid-server/v4/hooks/hookstage"
 	"github.com/prebid/prebid-server/v4/macros"
 	"github.com/prebid/prebid-server/v4/metrics"
 	"github.com/prebid/prebid-server/v4/openrtb_ext"
 	"github.com/prebid/prebid-server/v4/usersync"
+	"github.com/prebid/prebid-server/v4/usersync/uidstore"
 	"github.com/stretchr/testify/assert"
 
 	metricsConf "github.com/prebid/prebid-server/v4/metrics/config"
@@ -392,6 +395,167 @@ func TestSetUIDEndpoint(t *testing.T) {
 	}
 }
 
+func TestSetUIDEndpointHooks(t *testing.T) {
+	testCases := []struct {
+		description        string
+		givenHook          hookstage.SetUIDRequest
+		expectedSyncs      map[string]string
+		expectedStatusCode int
+	}{
+		{
+			description:        "hook-updates-uid",
+			givenHook:          mockSetUIDHook{uid: "456"},
+			expectedSyncs:      map[string]string{"pubmatic": "456"},
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			description:        "hook-rejects-uid",
+			givenHook:          mockSetUIDHook{reject: true},
+			expectedSyncs:      nil,
+			expectedStatusCode: http.StatusBadRequest,
+		},
+	}
+
+	cfg := config.Configuration{}
+	cfg.MarshalAccountDefaults()
+
+	gdprPermsBuilder := fakePermissionsBuilder{
+		permissions: &fakePermsSetUID{allowHost: true, personalInfoAllowed: true},
+	}.Builder
+	tcf2ConfigBuilder := fakeTCF2ConfigBuilder{
+		cfg: gdpr.NewTCF2Config(config.TCF2{}, config.AccountGDPR{}),
+	}.Builder
+	syncersByBidder := map[string]usersync.Syncer{
+		"pubmatic": fakeSyncer{key: "pubmatic", defaultSyncType: usersync.SyncTypeIFrame},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			planBuilder := mockSetUIDPlanBuilder{hook: test.givenHook}
+			endpoint := NewSetUIDEndpoint(&cfg, syncersByBidder, gdprPermsBuilder, tcf2ConfigBuilder, analyticsBuild.New(&config.Analytics{}), FakeAccountsFetcher{}, &metricsConf.NilMetricsEngine{}, planBuilder, nil)
+
+			response := httptest.NewRecorder()
+			endpoint(response, makeRequest("/setuid?bidder=pubmatic&uid=123", nil), nil)
+
+			assert.Equal(t, test.expectedStatusCode, response.Code)
+			if test.expectedSyncs != nil {
+				assertHasSyncs(t, test.description, response, test.expectedSyncs)
+			} else {
+				assert.Empty(t, response.Header().Get("Set-Cookie"))
+			}
+		})
+	}
+}
+
+func TestSetUIDEndpointUIDStore(t *testing.T) {
+	cfg := config.Configuration{}
+	cfg.MarshalAccountDefaults()
+
+	gdprPermsBuilder := fakePermissionsBuilder{
+		permissions: &fakePermsSetUID{allowHost: true, personalInfoAllowed: true},
+	}.Builder
+	tcf2ConfigBuilder := fakeTCF2ConfigBuilder{
+		cfg: gdpr.NewTCF2Config(config.TCF2{}, config.AccountGDPR{}),
+	}.Builder
+	syncersByBidder := map[string]usersync.Syncer{
+		"adnxs":    fakeSyncer{key: "adnxs", defaultSyncType: usersync.SyncTypeIFrame},
+		"pubmatic": fakeSyncer{key: "pubmatic", defaultSyncType: usersync.SyncTypeIFrame},
+		"rubicon":  fakeSyncer{key: "rubicon", defaultSyncType: usersync.SyncTypeIFrame},
+	}
+	uidStore := uidstore.NewMemoryStore()
+	endpoint := NewSetUIDEndpoint(&cfg, syncersByBidder, gdprPermsBuilder, tcf2ConfigBuilder, analyticsBuild.New(&config.Analytics{}), FakeAccountsFetcher{}, &metricsConf.NilMetricsEngine{}, hooks.EmptyPlanBuilder{}, uidStore)
+
+	// the uids of a cookie written before the store was configured are moved to the store
+	response := httptest.NewRecorder()
+	endpoint(response, makeRequest("/setuid?bidder=pubmatic&uid=123", map[string]string{"adnxs": "111"}), nil)
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Empty(t, parseCookieString(t, response).GetUIDs(), "the cookie must only carry the user key")
+
+	request := httptest.NewRequest("GET", "/setuid?bidder=rubicon&uid=456", nil)
+	request.AddCookie(response.Result().Cookies()[0])
+	response = httptest.NewRecorder()
+	endpoint(response, request, nil)
+	assert.Equal(t, http.StatusOK, response.Code)
+
+	request = httptest.NewRequest("GET", "/getuids?gdpr=0", nil)
+	request.AddCookie(response.Result().Cookies()[0])
+	response = httptest.NewRecorder()
+	NewGetUIDsEndpoint(&cfg, syncersByBidder, fakePermissionsBuilder{permissions: &fakePermissions{}}.Builder, tcf2ConfigBuilder, FakeAccountsFetcher{}, &metricsConf.NilMetricsEngine{}, nil, uidStore)(response, request, nil)
+	assert.JSONEq(t, `{"buyeruids":{"adnxs":"111","pubmatic":"123","rubicon":"456"}}`, response.Body.String())
+}
+
+func TestSetUIDEndpointUIDStoreError(t *testing.T) {
+	cfg := config.Configuration{}
+	cfg.MarshalAccountDefaults()
+
+	gdprPermsBuilder := fakePermissionsBuilder{
+		permissions: &fakePermsSetUID{allowHost: true, personalInfoAllowed: true},
+	}.Builder
+	tcf2ConfigBuilder := fakeTCF2ConfigBuilder{
+		cfg: gdpr.NewTCF2Config(config.TCF2{}, config.AccountGDPR{}),
+	}.Builder
+	syncersByBidder := map[string]usersync.Syncer{
+		"pubmatic": fakeSyncer{key: "pubmatic", defaultSyncType: usersync.SyncTypeIFrame},
+	}
+	metricsEngine := &metrics.MetricsEngineMock{}
+	metricsEngine.On("RecordSetUid", metrics.SetUidOK).Once()
+	metricsEngine.On("RecordSyncerSet", "pubmatic", metrics.SyncerSetUidOK).Once()
+	metricsEngine.On("RecordSetUid", metrics.SetUidStoreError).Once()
+	endpoint := NewSetUIDEndpoint(&cfg, syncersByBidder, gdprPermsBuilder, tcf2ConfigBuilder, analyticsBuild.New(&config.Analytics{}), FakeAccountsFetcher{}, metricsEngine, hooks.EmptyPlanBuilder{}, failingUIDStore{})
+
+	response := httptest.NewRecorder()
+	endpoint(response, makeRequest("/setuid?bidder=pubmatic&uid=123", nil), nil)
+
+	assert.Equal(t, http.StatusServiceUnavailable, response.Code)
+	assert.Empty(t, response.Header().Get("Set-Cookie"))
+	metricsEngine.AssertExpectations(t)
+}
+
+func TestSetUIDEndpointKeyRing(t *testing.T) {
+	cfg := config.Configuration{
+		HostCookie: config.HostCookie{
+			KeyRing: config.CookieKeyRing{
+				ActiveKeyID:  "1",
+				Keys:         []config.CookieKey{{ID: "1", Secret: "MDEyMzQ1Njc4OWFiY2RlZg=="}},
+				AcceptLegacy: true,
+			},
+		},
+	}
+	cfg.MarshalAccountDefaults()
+
+	gdprPermsBuilder := fakePermissionsBuilder{
+		permissions: &fakePermsSetUID{allowHost: true, personalInfoAllowed: true},
+	}.Builder
+	tcf2ConfigBuilder := fakeTCF2ConfigBuilder{
+		cfg: gdpr.NewTCF2Config(config.TCF2{}, config.AccountGDPR{}),
+	}.Builder
+	syncersByBidder := map[string]usersync.Syncer{
+		"adnxs":    fakeSyncer{key: "adnxs", defaultSyncType: usersync.SyncTypeIFrame},
+		"pubmatic": fakeSyncer{key: "pubmatic", defaultSyncType: usersync.SyncTypeIFrame},
+	}
+	metricsEngine := &metrics.MetricsEngineMock{}
+	metricsEngine.On("RecordUIDCookieDecode", metrics.UIDCookieDecodeLegacy).Once()
+	metricsEngine.On("RecordSetUid", metrics.SetUidOK).Once()
+	metricsEngine.On("RecordSyncerSet", "pubmatic", metrics.SyncerSetUidOK).Once()
+	endpoint := NewSetUIDEndpoint(&cfg, syncersByBidder, gdprPermsBuilder, tcf2ConfigBuilder, analyticsBuild.New(&config.Analytics{}), FakeAccountsFetcher{}, metricsEngine, hooks.EmptyPlanBuilder{}, nil)
+
+	// the base64 encoded cookie written before the key ring was configured is written again with the active key
+	response := httptest.NewRecorder()
+	endpoint(response, makeRequest("/setuid?bidder=pubmatic&uid=123", map[string]string{"adnxs": "111"}), nil)
+	assert.Equal(t, http.StatusOK, response.Code)
+	metricsEngine.AssertExpectations(t)
+
+	cookie := response.Result().Cookies()[0]
+	assert.True(t, strings.HasPrefix(cookie.Value, "v1.1."), "the cookie must be written with the active key")
+	assert.Empty(t, usersync.Base64Decoder{}.Decode(cookie.Value).GetUIDs())
+
+	request := httptest.NewRequest("GET", "/getuids?gdpr=0", nil)
+	request.AddCookie(cookie)
+	response = httptest.NewRecorder()
+	NewGetUIDsEndpoint(&cfg, syncersByBidder, fakePermissionsBuilder{permissions: &fakePermissions{}}.Builder, tcf2ConfigBuilder, FakeAccountsFetcher{}, &metricsConf.NilMetricsEngine{}, nil, nil)(response, request, nil)
+	assert.JSONEq(t, `{"buyeruids":{"adnxs":"111","pubmatic":"123"}}`, response.Body.String())
+}
+
 func TestSetUIDPriorityEjection(t *testing.T) {
 	decoder := usersync.Base64Decoder{}
 	analytics := analyticsBuild.New(&config.Analytics{})
@@ -1639,7 +1803,7 @@ func doRequest(req *http.Request, analytics analytics.Runner, metrics metrics.Me
 		"valid_acct_with_invalid_activities":                 json.RawMessage(`{"privacy":{"allowactivities":{"syncUser":{"rules":[{"condition":{"componentName": ["bidderA.bidderB.bidderC"]}}]}}}}`),
 	}}
 
-	endpoint := NewSetUIDEndpoint(&cfg, syncersByBidder, gdprPermsBuilder, tcf2ConfigBuilder, analytics, fakeAccountsFetcher, metrics)
+	endpoint := NewSetUIDEndpoint(&cfg, syncersByBidder, gdprPermsBuilder, tcf2ConfigBuilder, analytics, fakeAccountsFetcher, metrics, hooks.EmptyPlanBuilder{}, nil)
 	response := httptest.NewRecorder()
 	endpoint(response, req, nil)
 	return response
@@ -1663,6 +1827,20 @@ func parseCookieString(t *testing.T, response *httptest.ResponseRecorder) *users
 	return decoder.Decode(httpCookie.Value)
 }
 
+type failingUIDStore struct{}
+
+func (failingUIDStore) Get(context.Context, string) (map[string]usersync.UIDEntry, error) {
+	return nil, errors.New("uid store error")
+}
+
+func (failingUIDStore) Update(context.Context, string, map[string]usersync.UIDEntry, []string) error {
+	return errors.New("uid store error")
+}
+
+func (failingUIDStore) Delete(context.Context, string) error {
+	return errors.New("uid store error")
+}
+
 type fakePermissionsBuilder struct {
 	permissions gdpr.Permissions
 }
@@ -1679,6 +1857,39 @@ func (fcr fakeTCF2ConfigBuilder) Builder(hostConfig config.TCF2, accountConfig c
 	return fcr.cfg
 }
 
+type mockSetUIDPlanBuilder struct {
+	hooks.EmptyPlanBuilder
+	hook hookstage.SetUIDRequest
+}
+
+func (m mockSetUIDPlanBuilder) PlanForSetUIDRequestStage(_ string, _ *config.Account) hooks.Plan[hookstage.SetUIDRequest] {
+	return hooks.Plan[hookstage.SetUIDRequest]{
+		{
+			Timeout: 5 * time.Millisecond,
+			Hooks:   []hooks.HookWrapper[hookstage.SetUIDRequest]{{Module: "foobar", Code: "foo", Hook: m.hook}},
+		},
+	}
+}
+
+type mockSetUIDHook struct {
+	uid    string
+	reject bool
+}
+
+func (m mockSetUIDHook) HandleSetUIDRequestHook(_ context.Context, _ hookstage.ModuleInvocationContext, _ hookstage.SetUIDRequestPayload) (hookstage.HookResult[hookstage.SetUIDRequestPayload], error) {
+	if m.reject {
+		return hookstage.HookResult[hookstage.SetUIDRequestPayload]{Reject: true}, nil
+	}
+
+	c := hookstage.ChangeSet[hookstage.SetUIDRequestPayload]{}
+	c.AddMutation(func(payload hookstage.SetUIDRequestPayload) (hookstage.SetUIDRequestPayload, error) {
+		payload.UID = m.uid
+		return payload, nil
+	}, hookstage.MutationUpdate, "uid")
+
+	return hookstage.HookResult[hookstage.SetUIDRequestPayload]{ChangeSet: c}, nil
+}
+
 type fakePermsSetUID struct {
 	allowHost           bool
 	consent             string
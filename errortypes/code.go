@@ -19,6 +19,7 @@ const (
 	FailedToUnmarshalErrorCode
 	InvalidImpFirstPartyDataErrorCode
 	BidderTemporarilyThrottledErrorCode
+	BidderCircuitOpenErrorCode
 )
 
 // Defines numeric codes for well-known warnings.
@@ -197,6 +197,24 @@ func (err *BidderThrottled) Severity() Severity {
 	return SeverityWarning
 }
 
+// BidderCircuitOpen is used when a request to a bidder is skipped because its circuit breaker is open
+// after repeated errors or timeouts. Requests resume once probe requests to the bidder succeed again.
+type BidderCircuitOpen struct {
+	Message string
+}
+
+func (err *BidderCircuitOpen) Error() string {
+	return err.Message
+}
+
+func (err *BidderCircuitOpen) Code() int {
+	return BidderCircuitOpenErrorCode
+}
+
+func (err *BidderCircuitOpen) Severity() Severity {
+	return SeverityWarning
+}
+
 // MalformedAcct should be used when the retrieved account config cannot be unmarshaled
 // These errors will be written to http.ResponseWriter before canceling execution
 type MalformedAcct struct {
@@ -2,6 +2,7 @@ package exchange
 
 import (
 	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v4/analytics"
 	"github.com/prebid/prebid-server/v4/openrtb_ext"
 )
 
@@ -9,6 +10,7 @@ import (
 type AuctionResponse struct {
 	*openrtb2.BidResponse
 	ExtBidResponse *openrtb_ext.ExtBidResponse
+	FloorsOutcome  *analytics.FloorsOutcome
 }
 
 // GetSeatNonBid returns array of seat non-bid if present. nil otherwise
@@ -18,3 +20,11 @@ func (ar *AuctionResponse) GetSeatNonBid() []openrtb_ext.SeatNonBid {
 	}
 	return nil
 }
+
+// GetFloorsOutcome returns the price floors outcome if floors were applied to the auction. nil otherwise
+func (ar *AuctionResponse) GetFloorsOutcome() *analytics.FloorsOutcome {
+	if ar != nil {
+		return ar.FloorsOutcome
+	}
+	return nil
+}
@@ -2,7 +2,6 @@ package exchange
 
 import (
 	"bytes"
-	"compress/gzip"
 	"context"
 	"crypto/tls"
 	"encoding/json"
@@ -15,11 +14,11 @@ import (
 	"net/http/httptrace"
 	"regexp"
 	"strings"
-	"sync"
 	"sync/atomic"
 	"time"
 
 	"github.com/prebid/prebid-server/v4/bidadjustment"
+	"github.com/prebid/prebid-server/v4/capture"
 	"github.com/prebid/prebid-server/v4/config/util"
 	"github.com/prebid/prebid-server/v4/currency"
 	"github.com/prebid/prebid-server/v4/exchange/entities"
@@ -37,7 +36,12 @@ import (
 	"github.com/prebid/prebid-server/v4/errortypes"
 	"github.com/prebid/prebid-server/v4/metrics"
 	"github.com/prebid/prebid-server/v4/openrtb_ext"
+	"github.com/prebid/prebid-server/v4/tracing"
+	"github.com/prebid/prebid-server/v4/util/httputil"
 	"github.com/prebid/prebid-server/v4/util/jsonutil"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	oteltrace "go.opentelemetry.io/otel/trace"
 	"golang.org/x/net/context/ctxhttp"
 )
 
@@ -98,6 +102,7 @@ const ImpIdReqBody = "Stored bid response for impression id: "
 // Possible values of compression types Prebid Server can support for bidder compression
 const (
 	Gzip string = "GZIP"
+	Zstd string = "ZSTD"
 )
 
 // AdaptBidder converts an adapters.Bidder into an exchange.AdaptedBidder.
@@ -116,6 +121,7 @@ func AdaptBidder(bidder adapters.Bidder, client *http.Client, cfg *config.Config
 			DisableConnDialMetrics: cfg.Metrics.Disabled.AdapterConnectionDialMetrics,
 			DebugInfo:              config.DebugInfo{Allow: parseDebugInfo(debugInfo)},
 			EndpointCompression:    endpointCompression,
+			PropagateTraceContext:  cfg.Tracing.PropagateToBidders,
 			ThrottleConfig: bidderAdapterThrottleConfig{
 				enabled:                 cfg.Client.Throttle.EnableThrottling,
 				simulateOnly:            cfg.Client.Throttle.SimulateThrottlingOnly,
@@ -131,6 +137,7 @@ func AdaptBidder(bidder adapters.Bidder, client *http.Client, cfg *config.Config
 	// Precalculate bulk and delta values for health updates.
 	ba.config.ThrottleConfig.deltaValue = 1.0 / float64(ba.config.ThrottleConfig.throttleWindow)
 	ba.config.ThrottleConfig.bulkValue = 1.0 - ba.config.ThrottleConfig.deltaValue
+	ba.circuitBreakers = newBidderCircuitBreakers(cfg.Client.CircuitBreaker, name, me)
 
 	return ba
 }
@@ -149,7 +156,8 @@ type BidderAdapter struct {
 	me         metrics.MetricsEngine
 	config     bidderAdapterConfig
 	healthBits atomic.Uint64 // use atomic on this
-
+	// circuitBreakers is nil when circuit breaking is disabled
+	circuitBreakers *bidderCircuitBreakers
 }
 
 type bidderAdapterConfig struct {
@@ -158,6 +166,7 @@ type bidderAdapterConfig struct {
 	DisableConnDialMetrics bool
 	DebugInfo              config.DebugInfo
 	EndpointCompression    string
+	PropagateTraceContext  bool
 	ThrottleConfig         bidderAdapterThrottleConfig
 }
 
@@ -433,6 +442,7 @@ func (bidder *BidderAdapter) requestBid(ctx context.Context, bidderRequest Bidde
 							OriginalBidCPM: originalBidCpm,
 							OriginalBidCur: bidResponse.Currency,
 							AdapterCode:    bidderRequest.BidderCoreName,
+							BidderName:     bidderRequest.BidderName,
 						})
 						seatBidMap[bidderName].Currency = currencyAfterAdjustments
 					}
@@ -569,16 +579,41 @@ func makeExt(httpInfo *httpCallInfo) *openrtb_ext.ExtHttpCall {
 // doRequest makes a request, handles the response, and returns the data needed by the
 // Bidder interface.
 func (bidder *BidderAdapter) doRequest(ctx context.Context, req *adapters.RequestData, bidderRequestStartTime time.Time, tmaxAdjustments *TmaxAdjustmentsPreprocessed) *httpCallInfo {
+	breaker := bidder.circuitBreakers.forRequest(req)
+	ticket, allowed := breaker.allow()
+	if !allowed {
+		return &httpCallInfo{
+			request: req,
+			err:     &errortypes.BidderCircuitOpen{Message: fmt.Sprintf("Bidder %s is temporarily skipped due to repeated errors or timeouts", bidder.BidderName)},
+		}
+	}
 	if bidder.shouldRequest() {
-		return bidder.doRequestImpl(ctx, req, loggerI.Warnf, bidderRequestStartTime, tmaxAdjustments)
+		httpInfo := bidder.doRequestImpl(ctx, req, loggerI.Warnf, bidderRequestStartTime, tmaxAdjustments)
+		breaker.record(ticket, httpInfoToCircuitBreakerOutcome(httpInfo))
+		return httpInfo
 	}
+	breaker.record(ticket, circuitBreakerIgnored)
 	return &httpCallInfo{
 		request: req,
 		err:     &errortypes.BidderThrottled{Message: fmt.Sprintf("Bidder %s is temporarily throttled", bidder.BidderName)},
 	}
 }
 
-func (bidder *BidderAdapter) doRequestImpl(ctx context.Context, req *adapters.RequestData, logger util.LogMsg, bidderRequestStartTime time.Time, tmaxAdjustments *TmaxAdjustmentsPreprocessed) *httpCallInfo {
+func (bidder *BidderAdapter) doRequestImpl(ctx context.Context, req *adapters.RequestData, logger util.LogMsg, bidderRequestStartTime time.Time, tmaxAdjustments *TmaxAdjustmentsPreprocessed) (httpInfo *httpCallInfo) {
+	ctx, span := tracing.Start(ctx, "bidder.http",
+		oteltrace.WithSpanKind(oteltrace.SpanKindClient),
+		oteltrace.WithAttributes(tracing.AttributeBidder.String(string(bidder.BidderName))))
+	defer func() {
+		if httpInfo.response != nil {
+			span.SetAttributes(semconv.HTTPResponseStatusCode(httpInfo.response.StatusCode))
+		}
+		if httpInfo.err != nil {
+			tracing.RecordErrors(span, httpInfo.err)
+		}
+		span.End()
+		capture.AddBidderCall(ctx, string(bidder.BidderName), httpInfo.request, httpInfo.response, httpInfo.err)
+	}()
+
 	requestBody, err := getRequestBody(req, bidder.config.EndpointCompression)
 	if err != nil {
 		return &httpCallInfo{
@@ -594,6 +629,13 @@ func (bidder *BidderAdapter) doRequestImpl(ctx context.Context, req *adapters.Re
 		}
 	}
 	httpReq.Header = req.Headers
+	span.SetAttributes(semconv.HTTPRequestMethodKey.String(req.Method), semconv.ServerAddress(httpReq.URL.Hostname()))
+	if bidder.config.PropagateTraceContext {
+		if httpReq.Header == nil {
+			httpReq.Header = http.Header{}
+		}
+		tracing.Inject(ctx, httpReq.Header)
+	}
 
 	// If adapter connection metrics are not disabled, add the client trace
 	// to get complete connection info into our metrics
@@ -639,7 +681,7 @@ func (bidder *BidderAdapter) doRequestImpl(ctx context.Context, req *adapters.Re
 	}
 	defer httpResp.Body.Close()
 
-	respBody, err := io.ReadAll(httpResp.Body)
+	respBody, err := readResponseBody(httpResp)
 	if err != nil {
 		return &httpCallInfo{
 			request: req,
@@ -731,6 +773,7 @@ type httpCallInfo struct {
 // the time from the connection request, to the connection creation.
 func (bidder *BidderAdapter) addClientTrace(ctx context.Context, dialMetricsDisabled bool) context.Context {
 	var connStart, dnsStart, tlsStart, dialStart time.Time
+	span := oteltrace.SpanFromContext(ctx)
 
 	trace := &httptrace.ClientTrace{
 		// GetConn is called before a connection is created or retrieved from an idle pool
@@ -753,6 +796,9 @@ func (bidder *BidderAdapter) addClientTrace(ctx context.Context, dialMetricsDisa
 			}
 
 			bidder.me.RecordAdapterConnections(bidder.BidderName, info.Reused, connWaitTime)
+			span.SetAttributes(
+				attribute.Bool("pbs.conn.reused", info.Reused),
+				attribute.Int64("pbs.conn.wait_ms", connWaitTime.Milliseconds()))
 		},
 		// DNSStart is called when a DNS lookup begins.
 		DNSStart: func(info httptrace.DNSStartInfo) {
@@ -763,6 +809,7 @@ func (bidder *BidderAdapter) addClientTrace(ctx context.Context, dialMetricsDisa
 			dnsLookupTime := time.Since(dnsStart)
 
 			bidder.me.RecordDNSTime(dnsLookupTime)
+			span.SetAttributes(attribute.Int64("pbs.dns.duration_ms", dnsLookupTime.Milliseconds()))
 		},
 
 		TLSHandshakeStart: func() {
@@ -773,6 +820,7 @@ func (bidder *BidderAdapter) addClientTrace(ctx context.Context, dialMetricsDisa
 			tlsHandshakeTime := time.Since(tlsStart)
 
 			bidder.me.RecordTLSHandshakeTime(tlsHandshakeTime)
+			span.SetAttributes(attribute.Int64("pbs.tls.duration_ms", tlsHandshakeTime.Milliseconds()))
 		},
 	}
 
@@ -848,35 +896,62 @@ func hasShorterDurationThanTmax(ctx bidderTmaxContext, tmaxAdjustments TmaxAdjus
 func getRequestBody(req *adapters.RequestData, endpointCompression string) (*bytes.Buffer, error) {
 	switch strings.ToUpper(endpointCompression) {
 	case Gzip:
-		// Compress to GZIP
-		b := bytes.NewBuffer(make([]byte, 0, len(req.Body)))
+		return compressRequestBody(req, httputil.ContentEncodingGZIP)
+	case Zstd:
+		return compressRequestBody(req, httputil.ContentEncodingZSTD)
+	default:
+		return bytes.NewBuffer(req.Body), nil
+	}
+}
 
-		w := gzipWriterPool.Get().(*gzip.Writer)
-		defer gzipWriterPool.Put(w)
+func compressRequestBody(req *adapters.RequestData, contentEncoding httputil.ContentEncoding) (*bytes.Buffer, error) {
+	b := bytes.NewBuffer(make([]byte, 0, len(req.Body)))
 
-		w.Reset(b)
-		_, err := w.Write(req.Body)
-		if err != nil {
-			return nil, err
-		}
-		err = w.Close()
-		if err != nil {
-			return nil, err
-		}
+	w, err := httputil.AcquireEncoder(contentEncoding, b)
+	if err != nil {
+		return nil, err
+	}
+	defer httputil.ReleaseEncoder(contentEncoding, w)
 
-		// Set Header
-		req.Headers.Set("Content-Encoding", "gzip")
+	_, err = w.Write(req.Body)
+	if err != nil {
+		return nil, err
+	}
+	err = w.Close()
+	if err != nil {
+		return nil, err
+	}
 
-		return b, nil
-	default:
-		return bytes.NewBuffer(req.Body), nil
+	// Set Header
+	req.Headers.Set("Content-Encoding", string(contentEncoding))
+	// Bidders accepting compressed requests are likely to compress their responses the same way
+	if contentEncoding == httputil.ContentEncodingZSTD && req.Headers.Get("Accept-Encoding") == "" {
+		req.Headers.Set("Accept-Encoding", "zstd, gzip")
 	}
+
+	return b, nil
 }
 
-var gzipWriterPool = sync.Pool{
-	New: func() interface{} {
-		return gzip.NewWriter(nil)
-	},
+// readResponseBody reads the bidder response body, decompressing it if needed. The http client only
+// decompresses gzip responses transparently if it set the Accept-Encoding header itself. Bodies with an
+// unsupported encoding are read as is.
+func readResponseBody(httpResp *http.Response) ([]byte, error) {
+	contentEncoding := httputil.ContentEncoding(httpResp.Header.Get("Content-Encoding"))
+	if contentEncoding == "" || httpResp.Uncompressed {
+		return io.ReadAll(httpResp.Body)
+	}
+
+	r, err := httputil.NewDecodingReader(httpResp.Body, contentEncoding)
+	if errors.Is(err, httputil.ErrUnsupportedContentEncoding) {
+		// the body is passed through as is, as the bidder may label an uncompressed body with any encoding
+		loggerI.Debugf("Bidder response with unsupported Content-Encoding '%s' read without decompression", contentEncoding)
+		return io.ReadAll(httpResp.Body)
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return io.ReadAll(r)
 }
 
 func (bidder *BidderAdapter) getHealth() float64 {
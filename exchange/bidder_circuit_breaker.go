@@ -0,0 +1,268 @@
+package exchange
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/prebid/prebid-server/v4/adapters"
+	"github.com/prebid/prebid-server/v4/config"
+	"github.com/prebid/prebid-server/v4/errortypes"
+	"github.com/prebid/prebid-server/v4/metrics"
+	"github.com/prebid/prebid-server/v4/openrtb_ext"
+	"github.com/prebid/prebid-server/v4/util/timeutil"
+)
+
+// circuitBreakerOutcome classifies the result of a bidder call for the purpose of circuit breaking.
+type circuitBreakerOutcome int
+
+const (
+	// circuitBreakerIgnored is used for calls which say nothing about the health of the bidder, such as
+	// calls which were never sent or were canceled by Prebid Server.
+	circuitBreakerIgnored circuitBreakerOutcome = iota
+	circuitBreakerSuccess
+	circuitBreakerError
+	circuitBreakerTimeout
+)
+
+type circuitBreakerConfig struct {
+	windowSize       int
+	minRequests      int
+	errorThreshold   float64
+	timeoutThreshold float64
+	openDuration     time.Duration
+	halfOpenProbes   int
+}
+
+// bidderCircuitBreakers holds the circuit breakers of a single bidder. There is a single circuit breaker
+// per bidder, unless circuit breaking per endpoint host is enabled.
+type bidderCircuitBreakers struct {
+	bidderName openrtb_ext.BidderName
+	config     circuitBreakerConfig
+	perHost    bool
+	me         metrics.MetricsEngine
+	time       timeutil.Time
+
+	mutex    sync.Mutex
+	breakers map[string]*circuitBreaker
+}
+
+// newBidderCircuitBreakers returns nil if circuit breaking is disabled, in which case all bidder calls are allowed.
+func newBidderCircuitBreakers(cfg config.HTTPCircuitBreaker, bidderName openrtb_ext.BidderName, me metrics.MetricsEngine) *bidderCircuitBreakers {
+	if !cfg.Enabled {
+		return nil
+	}
+	return &bidderCircuitBreakers{
+		bidderName: bidderName,
+		config: circuitBreakerConfig{
+			windowSize:       cfg.WindowSize,
+			minRequests:      cfg.MinRequests,
+			errorThreshold:   cfg.ErrorThreshold,
+			timeoutThreshold: cfg.TimeoutThreshold,
+			openDuration:     time.Duration(cfg.OpenDurationMS) * time.Millisecond,
+			halfOpenProbes:   cfg.HalfOpenProbes,
+		},
+		perHost:  cfg.PerHost,
+		me:       me,
+		time:     &timeutil.RealTime{},
+		breakers: make(map[string]*circuitBreaker),
+	}
+}
+
+// forRequest returns the circuit breaker guarding the endpoint the request is sent to.
+func (b *bidderCircuitBreakers) forRequest(req *adapters.RequestData) *circuitBreaker {
+	if b == nil {
+		return nil
+	}
+
+	key := ""
+	if b.perHost {
+		if u, err := url.Parse(req.Uri); err == nil {
+			key = u.Host
+		}
+	}
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	breaker, ok := b.breakers[key]
+	if !ok {
+		breaker = &circuitBreaker{
+			config: b.config,
+			time:   b.time,
+			state:  metrics.CircuitBreakerClosed,
+			window: make([]circuitBreakerOutcome, b.config.windowSize),
+			onTransition: func(state metrics.CircuitBreakerState) {
+				b.me.RecordAdapterCircuitBreakerTransition(b.bidderName, state)
+			},
+		}
+		b.breakers[key] = breaker
+	}
+	return breaker
+}
+
+// circuitBreaker tracks the outcome of the most recent calls to a bidder endpoint. It opens when the ratio of
+// errors or timeouts in the window reaches the configured threshold, after which calls are skipped until the
+// open duration elapses. It then lets a limited number of probe calls through, closing again once enough of
+// them succeed or reopening on the first failure.
+type circuitBreaker struct {
+	config       circuitBreakerConfig
+	time         timeutil.Time
+	onTransition func(state metrics.CircuitBreakerState)
+
+	mutex          sync.Mutex
+	state          metrics.CircuitBreakerState
+	generation     uint64
+	openedAt       time.Time
+	window         []circuitBreakerOutcome
+	next           int
+	count          int
+	errors         int
+	timeouts       int
+	probesInFlight int
+	probeSuccesses int
+}
+
+// circuitBreakerTicket identifies the state of the circuit breaker a call was allowed in. Outcomes of calls
+// allowed before the latest state transition are discarded.
+type circuitBreakerTicket struct {
+	generation uint64
+}
+
+// allow returns true if a call may be made. A nil circuit breaker allows all calls.
+func (cb *circuitBreaker) allow() (circuitBreakerTicket, bool) {
+	if cb == nil {
+		return circuitBreakerTicket{}, true
+	}
+
+	cb.mutex.Lock()
+	defer cb.mutex.Unlock()
+
+	if cb.state == metrics.CircuitBreakerOpen && cb.time.Now().Sub(cb.openedAt) >= cb.config.openDuration {
+		cb.transition(metrics.CircuitBreakerHalfOpen)
+	}
+
+	switch cb.state {
+	case metrics.CircuitBreakerOpen:
+		return circuitBreakerTicket{}, false
+	case metrics.CircuitBreakerHalfOpen:
+		if cb.probesInFlight+cb.probeSuccesses >= cb.config.halfOpenProbes {
+			return circuitBreakerTicket{}, false
+		}
+		cb.probesInFlight++
+	}
+	return circuitBreakerTicket{generation: cb.generation}, true
+}
+
+// record registers the outcome of a call previously allowed by the circuit breaker.
+func (cb *circuitBreaker) record(ticket circuitBreakerTicket, outcome circuitBreakerOutcome) {
+	if cb == nil {
+		return
+	}
+
+	cb.mutex.Lock()
+	defer cb.mutex.Unlock()
+
+	if ticket.generation != cb.generation {
+		return
+	}
+
+	switch cb.state {
+	case metrics.CircuitBreakerClosed:
+		cb.recordClosed(outcome)
+	case metrics.CircuitBreakerHalfOpen:
+		cb.recordHalfOpen(outcome)
+	}
+}
+
+func (cb *circuitBreaker) recordClosed(outcome circuitBreakerOutcome) {
+	if outcome == circuitBreakerIgnored {
+		return
+	}
+
+	if cb.count == len(cb.window) {
+		cb.count--
+		switch cb.window[cb.next] {
+		case circuitBreakerError:
+			cb.errors--
+		case circuitBreakerTimeout:
+			cb.timeouts--
+		}
+	}
+	cb.window[cb.next] = outcome
+	cb.next = (cb.next + 1) % len(cb.window)
+	cb.count++
+
+	switch outcome {
+	case circuitBreakerError:
+		cb.errors++
+	case circuitBreakerTimeout:
+		cb.timeouts++
+	}
+
+	if cb.count < cb.config.minRequests {
+		return
+	}
+	if float64(cb.errors)/float64(cb.count) >= cb.config.errorThreshold ||
+		float64(cb.timeouts)/float64(cb.count) >= cb.config.timeoutThreshold {
+		cb.transition(metrics.CircuitBreakerOpen)
+	}
+}
+
+func (cb *circuitBreaker) recordHalfOpen(outcome circuitBreakerOutcome) {
+	cb.probesInFlight--
+
+	switch outcome {
+	case circuitBreakerSuccess:
+		cb.probeSuccesses++
+		if cb.probeSuccesses >= cb.config.halfOpenProbes {
+			cb.transition(metrics.CircuitBreakerClosed)
+		}
+	case circuitBreakerError, circuitBreakerTimeout:
+		cb.transition(metrics.CircuitBreakerOpen)
+	}
+}
+
+// transition moves the circuit breaker to a new state and resets all call accounting. Must be called with
+// the mutex held.
+func (cb *circuitBreaker) transition(state metrics.CircuitBreakerState) {
+	cb.state = state
+	cb.generation++
+	cb.next = 0
+	cb.count = 0
+	cb.errors = 0
+	cb.timeouts = 0
+	cb.probesInFlight = 0
+	cb.probeSuccesses = 0
+	if state == metrics.CircuitBreakerOpen {
+		cb.openedAt = cb.time.Now()
+	}
+	cb.onTransition(state)
+}
+
+// httpInfoToCircuitBreakerOutcome classifies a bidder call. Server errors, connection failures and timeouts
+// count against the bidder. Any other response, including client errors, shows the endpoint is healthy.
+func httpInfoToCircuitBreakerOutcome(httpInfo *httpCallInfo) circuitBreakerOutcome {
+	if httpInfo.response != nil {
+		if httpInfo.response.StatusCode >= 500 {
+			return circuitBreakerError
+		}
+		return circuitBreakerSuccess
+	}
+	if httpInfo.err == nil {
+		return circuitBreakerIgnored
+	}
+
+	switch errortypes.ReadCode(httpInfo.err) {
+	case errortypes.TimeoutErrorCode:
+		return circuitBreakerTimeout
+	case errortypes.TmaxTimeoutErrorCode, errortypes.BidderTemporarilyThrottledErrorCode:
+		return circuitBreakerIgnored
+	}
+	if errors.Is(httpInfo.err, context.Canceled) {
+		return circuitBreakerIgnored
+	}
+	return circuitBreakerError
+}
@@ -0,0 +1,336 @@
+package exchange
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v4/adapters"
+	"github.com/prebid/prebid-server/v4/config"
+	"github.com/prebid/prebid-server/v4/currency"
+	"github.com/prebid/prebid-server/v4/errortypes"
+	"github.com/prebid/prebid-server/v4/experiment/adscert"
+	"github.com/prebid/prebid-server/v4/hooks/hookexecution"
+	"github.com/prebid/prebid-server/v4/metrics"
+	"github.com/prebid/prebid-server/v4/openrtb_ext"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type fakeCircuitBreakerTime struct {
+	now time.Time
+}
+
+func (f *fakeCircuitBreakerTime) Now() time.Time {
+	return f.now
+}
+
+func newTestCircuitBreaker(cfg circuitBreakerConfig) (*circuitBreaker, *fakeCircuitBreakerTime, *[]metrics.CircuitBreakerState) {
+	clock := &fakeCircuitBreakerTime{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	transitions := &[]metrics.CircuitBreakerState{}
+	cb := &circuitBreaker{
+		config: cfg,
+		time:   clock,
+		state:  metrics.CircuitBreakerClosed,
+		window: make([]circuitBreakerOutcome, cfg.windowSize),
+		onTransition: func(state metrics.CircuitBreakerState) {
+			*transitions = append(*transitions, state)
+		},
+	}
+	return cb, clock, transitions
+}
+
+func recordOutcomes(cb *circuitBreaker, outcomes ...circuitBreakerOutcome) {
+	for _, outcome := range outcomes {
+		ticket, _ := cb.allow()
+		cb.record(ticket, outcome)
+	}
+}
+
+func TestCircuitBreakerOpens(t *testing.T) {
+	testCases := []struct {
+		name          string
+		outcomes      []circuitBreakerOutcome
+		expectedState metrics.CircuitBreakerState
+	}{
+		{
+			name:          "below-min-requests",
+			outcomes:      []circuitBreakerOutcome{circuitBreakerError, circuitBreakerError, circuitBreakerError},
+			expectedState: metrics.CircuitBreakerClosed,
+		},
+		{
+			name:          "error-threshold-reached",
+			outcomes:      []circuitBreakerOutcome{circuitBreakerSuccess, circuitBreakerSuccess, circuitBreakerError, circuitBreakerError},
+			expectedState: metrics.CircuitBreakerOpen,
+		},
+		{
+			name:          "timeout-threshold-reached",
+			outcomes:      []circuitBreakerOutcome{circuitBreakerTimeout, circuitBreakerSuccess, circuitBreakerTimeout, circuitBreakerTimeout},
+			expectedState: metrics.CircuitBreakerOpen,
+		},
+		{
+			name:          "errors-and-timeouts-counted-separately",
+			outcomes:      []circuitBreakerOutcome{circuitBreakerTimeout, circuitBreakerSuccess, circuitBreakerError, circuitBreakerSuccess},
+			expectedState: metrics.CircuitBreakerClosed,
+		},
+		{
+			name:          "ignored-outcomes-not-counted",
+			outcomes:      []circuitBreakerOutcome{circuitBreakerError, circuitBreakerIgnored, circuitBreakerIgnored, circuitBreakerIgnored},
+			expectedState: metrics.CircuitBreakerClosed,
+		},
+		{
+			name: "old-errors-slide-out-of-window",
+			outcomes: []circuitBreakerOutcome{
+				circuitBreakerError, circuitBreakerSuccess, circuitBreakerSuccess, circuitBreakerSuccess,
+				circuitBreakerSuccess, circuitBreakerError, circuitBreakerSuccess, circuitBreakerSuccess,
+			},
+			expectedState: metrics.CircuitBreakerClosed,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			cb, _, _ := newTestCircuitBreaker(circuitBreakerConfig{
+				windowSize:       4,
+				minRequests:      4,
+				errorThreshold:   0.5,
+				timeoutThreshold: 0.75,
+				openDuration:     time.Second,
+				halfOpenProbes:   1,
+			})
+
+			recordOutcomes(cb, test.outcomes...)
+
+			assert.Equal(t, test.expectedState, cb.state)
+		})
+	}
+}
+
+func TestCircuitBreakerRecovery(t *testing.T) {
+	cfg := circuitBreakerConfig{
+		windowSize:       2,
+		minRequests:      2,
+		errorThreshold:   1,
+		timeoutThreshold: 1,
+		openDuration:     time.Second,
+		halfOpenProbes:   2,
+	}
+
+	t.Run("closes-after-probes-succeed", func(t *testing.T) {
+		cb, clock, transitions := newTestCircuitBreaker(cfg)
+		recordOutcomes(cb, circuitBreakerError, circuitBreakerError)
+
+		_, allowed := cb.allow()
+		assert.False(t, allowed, "open circuit should skip calls")
+
+		clock.now = clock.now.Add(time.Second)
+		first, allowed := cb.allow()
+		assert.True(t, allowed, "first probe")
+		second, allowed := cb.allow()
+		assert.True(t, allowed, "second probe")
+		_, allowed = cb.allow()
+		assert.False(t, allowed, "probes exhausted")
+
+		cb.record(first, circuitBreakerSuccess)
+		cb.record(second, circuitBreakerSuccess)
+
+		assert.Equal(t, metrics.CircuitBreakerClosed, cb.state)
+		assert.Equal(t, []metrics.CircuitBreakerState{metrics.CircuitBreakerOpen, metrics.CircuitBreakerHalfOpen, metrics.CircuitBreakerClosed}, *transitions)
+	})
+
+	t.Run("reopens-when-probe-fails", func(t *testing.T) {
+		cb, clock, transitions := newTestCircuitBreaker(cfg)
+		recordOutcomes(cb, circuitBreakerTimeout, circuitBreakerTimeout)
+
+		clock.now = clock.now.Add(time.Second)
+		first, _ := cb.allow()
+		second, _ := cb.allow()
+		cb.record(first, circuitBreakerError)
+		cb.record(second, circuitBreakerSuccess)
+
+		_, allowed := cb.allow()
+		assert.False(t, allowed)
+		assert.Equal(t, metrics.CircuitBreakerOpen, cb.state)
+		assert.Equal(t, []metrics.CircuitBreakerState{metrics.CircuitBreakerOpen, metrics.CircuitBreakerHalfOpen, metrics.CircuitBreakerOpen}, *transitions)
+	})
+
+	t.Run("ignored-probe-releases-slot", func(t *testing.T) {
+		cb, clock, _ := newTestCircuitBreaker(cfg)
+		recordOutcomes(cb, circuitBreakerError, circuitBreakerError)
+
+		clock.now = clock.now.Add(time.Second)
+		first, _ := cb.allow()
+		cb.allow()
+		cb.record(first, circuitBreakerIgnored)
+
+		_, allowed := cb.allow()
+		assert.True(t, allowed)
+	})
+
+	t.Run("outcomes-from-previous-state-discarded", func(t *testing.T) {
+		cb, _, _ := newTestCircuitBreaker(cfg)
+		stale, _ := cb.allow()
+		recordOutcomes(cb, circuitBreakerError, circuitBreakerError)
+
+		cb.record(stale, circuitBreakerSuccess)
+
+		assert.Equal(t, metrics.CircuitBreakerOpen, cb.state)
+	})
+}
+
+func TestNilCircuitBreakerAllowsCalls(t *testing.T) {
+	var breakers *bidderCircuitBreakers
+	cb := breakers.forRequest(&adapters.RequestData{Uri: "http://bidder.com"})
+
+	ticket, allowed := cb.allow()
+	cb.record(ticket, circuitBreakerError)
+
+	assert.Nil(t, cb)
+	assert.True(t, allowed)
+}
+
+func TestBidderCircuitBreakersForRequest(t *testing.T) {
+	cfg := config.HTTPCircuitBreaker{Enabled: true, WindowSize: 10, MinRequests: 1, ErrorThreshold: 1, TimeoutThreshold: 1, OpenDurationMS: 1000, HalfOpenProbes: 1}
+
+	testCases := []struct {
+		name         string
+		perHost      bool
+		expectShared bool
+	}{
+		{
+			name:         "per-bidder",
+			perHost:      false,
+			expectShared: true,
+		},
+		{
+			name:         "per-host",
+			perHost:      true,
+			expectShared: false,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			cfg.PerHost = test.perHost
+			breakers := newBidderCircuitBreakers(cfg, openrtb_ext.BidderAppnexus, &metrics.MetricsEngineMock{})
+
+			a := breakers.forRequest(&adapters.RequestData{Uri: "https://a.bidder.com/path?x=1"})
+			b := breakers.forRequest(&adapters.RequestData{Uri: "https://b.bidder.com/path"})
+
+			assert.Same(t, a, breakers.forRequest(&adapters.RequestData{Uri: "https://a.bidder.com/other"}))
+			assert.Equal(t, test.expectShared, a == b)
+		})
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		assert.Nil(t, newBidderCircuitBreakers(config.HTTPCircuitBreaker{Enabled: false}, openrtb_ext.BidderAppnexus, &metrics.MetricsEngineMock{}))
+	})
+}
+
+func TestHttpInfoToCircuitBreakerOutcome(t *testing.T) {
+	testCases := []struct {
+		name     string
+		httpInfo *httpCallInfo
+		expected circuitBreakerOutcome
+	}{
+		{
+			name:     "ok",
+			httpInfo: &httpCallInfo{response: &adapters.ResponseData{StatusCode: 200}},
+			expected: circuitBreakerSuccess,
+		},
+		{
+			name:     "client-error",
+			httpInfo: &httpCallInfo{response: &adapters.ResponseData{StatusCode: 400}, err: &errortypes.BadServerResponse{}},
+			expected: circuitBreakerSuccess,
+		},
+		{
+			name:     "server-error",
+			httpInfo: &httpCallInfo{response: &adapters.ResponseData{StatusCode: 503}, err: &errortypes.BadServerResponse{}},
+			expected: circuitBreakerError,
+		},
+		{
+			name:     "connection-error",
+			httpInfo: &httpCallInfo{err: errors.New("connection refused")},
+			expected: circuitBreakerError,
+		},
+		{
+			name:     "timeout",
+			httpInfo: &httpCallInfo{err: &errortypes.Timeout{}},
+			expected: circuitBreakerTimeout,
+		},
+		{
+			name:     "tmax-timeout",
+			httpInfo: &httpCallInfo{err: &errortypes.TmaxTimeout{}},
+			expected: circuitBreakerIgnored,
+		},
+		{
+			name:     "canceled",
+			httpInfo: &httpCallInfo{err: context.Canceled},
+			expected: circuitBreakerIgnored,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, httpInfoToCircuitBreakerOutcome(test.httpInfo))
+		})
+	}
+}
+
+func TestRequestBidCircuitBreaker(t *testing.T) {
+	server := httptest.NewServer(mockHandler(http.StatusServiceUnavailable, "getBody", ""))
+	defer server.Close()
+
+	bidderImpl := &goodSingleBidder{
+		httpRequest: &adapters.RequestData{
+			Method:  "POST",
+			Uri:     server.URL,
+			Body:    []byte(`{"key":"val"}`),
+			Headers: http.Header{},
+			ImpIDs:  []string{"impId"},
+		},
+	}
+
+	cfg := &config.Configuration{
+		Client: config.HTTPClient{
+			CircuitBreaker: config.HTTPCircuitBreaker{
+				Enabled:          true,
+				WindowSize:       2,
+				MinRequests:      2,
+				ErrorThreshold:   1,
+				TimeoutThreshold: 1,
+				OpenDurationMS:   60000,
+				HalfOpenProbes:   1,
+			},
+		},
+	}
+	me := &metrics.MetricsEngineMock{}
+	me.On("RecordOverheadTime", mock.Anything, mock.Anything).Return()
+	me.On("RecordBidderServerResponseTime", mock.Anything).Return()
+	me.On("RecordAdapterCircuitBreakerTransition", openrtb_ext.BidderAppnexus, metrics.CircuitBreakerOpen).Once()
+
+	bidder := AdaptBidder(bidderImpl, server.Client(), cfg, me, openrtb_ext.BidderAppnexus, nil, "")
+	bidder.(*BidderAdapter).config.DisableConnMetrics = true
+	currencyConverter := currency.NewRateConverter(&http.Client{}, time.Duration(1), "", time.Duration(0))
+
+	var nonBidReasons []int
+	for i := 0; i < 3; i++ {
+		bidderReq := BidderRequest{
+			BidRequest: &openrtb2.BidRequest{Imp: []openrtb2.Imp{{ID: "impId"}}},
+			BidderName: openrtb_ext.BidderAppnexus,
+		}
+		_, extraInfo, errs := bidder.requestBid(context.Background(), bidderReq, currencyConverter.Rates(), &adapters.ExtraRequestInfo{}, &adscert.NilSigner{}, bidRequestOptions{}, openrtb_ext.ExtAlternateBidderCodes{}, &hookexecution.EmptyHookExecutor{}, nil)
+
+		assert.Len(t, errs, 1)
+		for _, nonBid := range extraInfo.seatNonBidBuilder[string(openrtb_ext.BidderAppnexus)] {
+			nonBidReasons = append(nonBidReasons, nonBid.StatusCode)
+		}
+	}
+
+	assert.Equal(t, []int{int(ErrorGeneral), int(ErrorGeneral), int(RequestBlockedCircuitOpen)}, nonBidReasons)
+	me.AssertExpectations(t)
+}
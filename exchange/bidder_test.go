@@ -2,7 +2,7 @@ package exchange
 
 import (
 	"bytes"
-	"compress/gzip"
+	"compress/zlib"
 	"context"
 	"crypto/tls"
 	"encoding/json"
@@ -36,11 +36,16 @@ import (
 	"github.com/prebid/prebid-server/v4/metrics"
 	metricsConfig "github.com/prebid/prebid-server/v4/metrics/config"
 	"github.com/prebid/prebid-server/v4/openrtb_ext"
+	"github.com/prebid/prebid-server/v4/util/httputil"
 	"github.com/prebid/prebid-server/v4/util/jsonutil"
 	"github.com/prebid/prebid-server/v4/util/ptrutil"
 	"github.com/prebid/prebid-server/v4/version"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	oteltrace "go.opentelemetry.io/otel/trace"
 )
 
 // TestSingleBidder makes sure that the following things work if the Bidder needs only one request.
@@ -2190,6 +2195,64 @@ func TestCallRecordTLSHandshakeTime(t *testing.T) {
 	metricsMock.AssertExpectations(t)
 }
 
+type headerCapturingTripper struct {
+	header http.Header
+}
+
+func (h *headerCapturingTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	h.header = req.Header.Clone()
+	return &http.Response{
+		StatusCode: 200,
+		Body:       io.NopCloser(strings.NewReader("postBody")),
+	}, nil
+}
+
+func TestPropagateTraceContextToBidder(t *testing.T) {
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	defer otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator())
+
+	parent := oteltrace.NewSpanContext(oteltrace.SpanContextConfig{
+		TraceID:    oteltrace.TraceID{0x01},
+		SpanID:     oteltrace.SpanID{0x02},
+		TraceFlags: oteltrace.FlagsSampled,
+		Remote:     true,
+	})
+	ctx := oteltrace.ContextWithRemoteSpanContext(context.Background(), parent)
+
+	testCases := []struct {
+		description         string
+		propagate           bool
+		expectedTraceparent string
+	}{
+		{
+			description:         "enabled",
+			propagate:           true,
+			expectedTraceparent: "00-01000000000000000000000000000000-0200000000000000-01",
+		},
+		{
+			description:         "disabled",
+			propagate:           false,
+			expectedTraceparent: "",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			tripper := &headerCapturingTripper{}
+			bidder := &BidderAdapter{
+				Bidder: &mixedMultiBidder{},
+				Client: &http.Client{Transport: tripper},
+				me:     &metricsConfig.NilMetricsEngine{},
+				config: bidderAdapterConfig{DisableConnMetrics: true, PropagateTraceContext: test.propagate},
+			}
+
+			bidder.doRequest(ctx, &adapters.RequestData{Method: "POST", Uri: "http://www.example.com/"}, time.Now(), &TmaxAdjustmentsPreprocessed{})
+
+			assert.Equal(t, test.expectedTraceparent, tripper.header.Get("traceparent"))
+		})
+	}
+}
+
 func TestTimeoutNotificationOff(t *testing.T) {
 	respBody := "{\"bid\":false}"
 	respStatus := 200
@@ -2526,6 +2589,7 @@ func TestExtraBid(t *testing.T) {
 				DealPriority:   5,
 				BidType:        openrtb_ext.BidTypeVideo,
 				OriginalBidCur: "USD",
+				BidderName:     openrtb_ext.BidderPubmatic,
 			}},
 			Seat:     "groupm",
 			Currency: "USD",
@@ -2537,6 +2601,7 @@ func TestExtraBid(t *testing.T) {
 				DealPriority:   4,
 				BidType:        openrtb_ext.BidTypeBanner,
 				OriginalBidCur: "USD",
+				BidderName:     openrtb_ext.BidderPubmatic,
 			}},
 			Seat:     string(openrtb_ext.BidderPubmatic),
 			Currency: "USD",
@@ -2634,6 +2699,7 @@ func TestExtraBidWithAlternateBidderCodeDisabled(t *testing.T) {
 				DealPriority:   5,
 				BidType:        openrtb_ext.BidTypeVideo,
 				OriginalBidCur: "USD",
+				BidderName:     openrtb_ext.BidderPubmatic,
 			}},
 			Seat:     "groupm-allowed",
 			Currency: "USD",
@@ -2645,6 +2711,7 @@ func TestExtraBidWithAlternateBidderCodeDisabled(t *testing.T) {
 				DealPriority:   4,
 				BidType:        openrtb_ext.BidTypeBanner,
 				OriginalBidCur: "USD",
+				BidderName:     openrtb_ext.BidderPubmatic,
 			}},
 			Seat:     string(openrtb_ext.BidderPubmatic),
 			Currency: "USD",
@@ -2742,6 +2809,7 @@ func TestExtraBidWithBidAdjustments(t *testing.T) {
 				BidType:        openrtb_ext.BidTypeVideo,
 				OriginalBidCPM: 7,
 				OriginalBidCur: "USD",
+				BidderName:     "PUBMATIC",
 			}},
 			Seat:     "groupm",
 			Currency: "USD",
@@ -2756,6 +2824,7 @@ func TestExtraBidWithBidAdjustments(t *testing.T) {
 				DealPriority:   4,
 				BidType:        openrtb_ext.BidTypeBanner,
 				OriginalBidCur: "USD",
+				BidderName:     "PUBMATIC",
 				OriginalBidCPM: 3,
 			}},
 			Seat:     "PUBMATIC",
@@ -2855,6 +2924,7 @@ func TestExtraBidWithBidAdjustmentsUsingAdapterCode(t *testing.T) {
 				BidType:        openrtb_ext.BidTypeVideo,
 				OriginalBidCPM: 7,
 				OriginalBidCur: "USD",
+				BidderName:     openrtb_ext.BidderPubmatic,
 			}},
 			Seat:     "groupm",
 			Currency: "USD",
@@ -2869,6 +2939,7 @@ func TestExtraBidWithBidAdjustmentsUsingAdapterCode(t *testing.T) {
 				DealPriority:   4,
 				BidType:        openrtb_ext.BidTypeBanner,
 				OriginalBidCur: "USD",
+				BidderName:     openrtb_ext.BidderPubmatic,
 				OriginalBidCPM: 3,
 			}},
 			Seat:     string(openrtb_ext.BidderPubmatic),
@@ -2967,6 +3038,7 @@ func TestExtraBidWithMultiCurrencies(t *testing.T) {
 				BidType:        openrtb_ext.BidTypeVideo,
 				OriginalBidCPM: 7,
 				OriginalBidCur: "USD",
+				BidderName:     openrtb_ext.BidderPubmatic,
 			}},
 			Seat:     "groupm",
 			Currency: "INR",
@@ -2982,6 +3054,7 @@ func TestExtraBidWithMultiCurrencies(t *testing.T) {
 				BidType:        openrtb_ext.BidTypeBanner,
 				OriginalBidCPM: 3,
 				OriginalBidCur: "USD",
+				BidderName:     openrtb_ext.BidderPubmatic,
 			}},
 			Seat:     string(openrtb_ext.BidderPubmatic),
 			Currency: "INR",
@@ -3499,9 +3572,11 @@ func TestDoRequestImplWithTmaxTimeout(t *testing.T) {
 
 func TestGetRequestBody(t *testing.T) {
 	tests := []struct {
-		name                string
-		endpointCompression string
-		givenReqBody        []byte
+		name                    string
+		endpointCompression     string
+		givenReqBody            []byte
+		expectedContentEncoding string
+		expectedAcceptEncoding  string
 	}{
 		{
 			name:                "No-Compression",
@@ -3509,9 +3584,17 @@ func TestGetRequestBody(t *testing.T) {
 			givenReqBody:        []byte("test body"),
 		},
 		{
-			name:                "GZIP-Compression",
-			endpointCompression: "GZIP",
-			givenReqBody:        []byte("test body"),
+			name:                    "GZIP-Compression",
+			endpointCompression:     "GZIP",
+			givenReqBody:            []byte("test body"),
+			expectedContentEncoding: "gzip",
+		},
+		{
+			name:                    "ZSTD-Compression",
+			endpointCompression:     "zstd",
+			givenReqBody:            []byte("test body"),
+			expectedContentEncoding: "zstd",
+			expectedAcceptEncoding:  "zstd, gzip",
 		},
 	}
 
@@ -3520,11 +3603,13 @@ func TestGetRequestBody(t *testing.T) {
 			req := &adapters.RequestData{Body: test.givenReqBody, Headers: http.Header{}}
 			requestBody, err := getRequestBody(req, test.endpointCompression)
 			assert.NoError(t, err)
+			assert.Equal(t, test.expectedContentEncoding, req.Headers.Get("Content-Encoding"))
+			assert.Equal(t, test.expectedAcceptEncoding, req.Headers.Get("Accept-Encoding"))
 
-			if test.endpointCompression == "GZIP" {
-				assert.Equal(t, "gzip", req.Headers.Get("Content-Encoding"))
-
-				decompressedReqBody, err := decompressGzip(requestBody.Bytes())
+			if test.expectedContentEncoding != "" {
+				decoder, err := httputil.NewDecodingReader(requestBody, httputil.ContentEncoding(test.expectedContentEncoding))
+				assert.NoError(t, err)
+				decompressedReqBody, err := io.ReadAll(decoder)
 				assert.NoError(t, err)
 				assert.Equal(t, test.givenReqBody, decompressedReqBody)
 			} else {
@@ -3534,19 +3619,84 @@ func TestGetRequestBody(t *testing.T) {
 	}
 }
 
-func decompressGzip(input []byte) ([]byte, error) {
-	r, err := gzip.NewReader(bytes.NewReader(input))
-	if err != nil {
-		return nil, err
-	}
-	defer r.Close()
+func TestReadResponseBody(t *testing.T) {
+	respBody := []byte(`{"id":"some-response-id"}`)
 
-	decompressed, err := io.ReadAll(r)
-	if err != nil {
-		return nil, err
+	tests := []struct {
+		name            string
+		contentEncoding string
+		uncompressed    bool
+		expectedError   string
+	}{
+		{
+			name: "Not-Compressed",
+		},
+		{
+			name:            "GZIP",
+			contentEncoding: "gzip",
+		},
+		{
+			name:            "ZSTD",
+			contentEncoding: "zstd",
+		},
+		{
+			name:            "Brotli",
+			contentEncoding: "br",
+		},
+		{
+			name:            "Deflate",
+			contentEncoding: "deflate",
+		},
+		{
+			name:            "Identity",
+			contentEncoding: "identity",
+		},
+		{
+			name:            "Decompressed-By-Transport",
+			contentEncoding: "gzip",
+			uncompressed:    true,
+		},
+		{
+			name:            "Unsupported",
+			contentEncoding: "compress",
+		},
 	}
 
-	return decompressed, nil
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			body := respBody
+			if !test.uncompressed {
+				switch test.contentEncoding {
+				case "gzip", "zstd", "br":
+					var compressed bytes.Buffer
+					encoder, err := httputil.AcquireEncoder(httputil.ContentEncoding(test.contentEncoding), &compressed)
+					require.NoError(t, err)
+					encoder.Write(respBody)
+					require.NoError(t, encoder.Close())
+					body = compressed.Bytes()
+				case "deflate":
+					var compressed bytes.Buffer
+					writer := zlib.NewWriter(&compressed)
+					writer.Write(respBody)
+					require.NoError(t, writer.Close())
+					body = compressed.Bytes()
+				}
+			}
+			httpResp := &http.Response{
+				Header:       http.Header{"Content-Encoding": []string{test.contentEncoding}},
+				Body:         io.NopCloser(bytes.NewReader(body)),
+				Uncompressed: test.uncompressed,
+			}
+
+			result, err := readResponseBody(httpResp)
+			if test.expectedError != "" {
+				assert.EqualError(t, err, test.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, respBody, result)
+		})
+	}
 }
 
 func BenchmarkCompressToGZIPOptimized(b *testing.B) {
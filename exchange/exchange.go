@@ -16,7 +16,9 @@ import (
 
 	"github.com/prebid/prebid-server/v4/adapters"
 	"github.com/prebid/prebid-server/v4/adservertargeting"
+	"github.com/prebid/prebid-server/v4/analytics"
 	"github.com/prebid/prebid-server/v4/bidadjustment"
+	"github.com/prebid/prebid-server/v4/capture"
 	"github.com/prebid/prebid-server/v4/config"
 	"github.com/prebid/prebid-server/v4/currency"
 	"github.com/prebid/prebid-server/v4/dsa"
@@ -36,6 +38,7 @@ import (
 	"github.com/prebid/prebid-server/v4/privacy"
 	"github.com/prebid/prebid-server/v4/stored_requests"
 	"github.com/prebid/prebid-server/v4/stored_responses"
+	"github.com/prebid/prebid-server/v4/tracing"
 	"github.com/prebid/prebid-server/v4/usersync"
 	"github.com/prebid/prebid-server/v4/util/jsonutil"
 	"github.com/prebid/prebid-server/v4/util/maputil"
@@ -274,10 +277,16 @@ func (e *exchange) HoldAuction(ctx context.Context, r *AuctionRequest, debugLog
 
 	// Get currency rates conversions for the auction
 	conversions := currency.GetAuctionCurrencyRates(e.currencyConverter, requestExtPrebid.CurrencyConversions)
+	if e.currencyConverter != nil {
+		capture.SetCurrencyRates(ctx, e.currencyConverter.Rates())
+	}
 
 	var floorErrs []error
 	if e.priceFloorEnabled {
+		_, span := tracing.Start(ctx, "floors.enrich")
 		floorErrs = floors.EnrichWithPriceFloors(r.BidRequestWrapper, r.Account, conversions, e.priceFloorFetcher)
+		tracing.RecordErrors(span, floorErrs...)
+		span.End()
 	}
 
 	responseDebugAllow, accountDebugAllow, debugLog := getDebugInfo(r.BidRequestWrapper.Test, requestExtPrebid, r.Account.DebugAllow, debugLog)
@@ -295,6 +304,10 @@ func (e *exchange) HoldAuction(ctx context.Context, r *AuctionRequest, debugLog
 	}
 	e.me.RecordDebugRequest(responseDebugAllow || accountDebugAllow, r.PubID)
 
+	gpp, gppErr := parseRequestGPP(r.BidRequestWrapper)
+
+	addHostEIDs(r.BidRequestWrapper, r.Account.HostEIDs, r.UserSyncs, r.Activities, gpp)
+
 	if r.RequestType == metrics.ReqTypeORTB2Web ||
 		r.RequestType == metrics.ReqTypeORTB2App ||
 		r.RequestType == metrics.ReqTypeAMP {
@@ -334,12 +347,15 @@ func (e *exchange) HoldAuction(ctx context.Context, r *AuctionRequest, debugLog
 		Prebid: *requestExtPrebid,
 		SChain: requestExt.GetSChain(),
 	}
-	bidderRequests, privacyLabels, errs := e.requestSplitter.cleanOpenRTBRequests(ctx, *r, requestExtLegacy, bidAdjustmentFactors)
+	bidderRequests, privacyLabels, errs := e.requestSplitter.cleanOpenRTBRequests(ctx, *r, requestExtLegacy, bidAdjustmentFactors, gpp)
 	for _, err := range errs {
 		if errortypes.ReadCode(err) == errortypes.InvalidImpFirstPartyDataErrorCode {
 			return nil, err
 		}
 	}
+	if gppErr != nil {
+		errs = append(errs, gppErr)
+	}
 	errs = append(errs, floorErrs...)
 
 	mergedBidAdj, err := bidadjustment.Merge(r.BidRequestWrapper, r.Account.BidAdjustments)
@@ -392,9 +408,10 @@ func (e *exchange) HoldAuction(ctx context.Context, r *AuctionRequest, debugLog
 		}
 
 		liveAdaptersPreferredMediaType := getBidderPreferredMediaTypeMap(requestExtPrebid, &r.Account, liveAdapters, e.singleFormatBidders)
+		secondaryBidders := getSecondaryBidders(requestExtPrebid, &r.Account)
 
 		var extraRespInfo extraAuctionResponseInfo
-		adapterBids, adapterExtra, extraRespInfo = e.getAllBids(auctionCtx, bidderRequests, bidAdjustmentFactors, conversions, accountDebugAllow, r.GlobalPrivacyControlHeader, debugLog.DebugOverride, alternateBidderCodes, requestExtLegacy.Prebid.Experiment, r.HookExecutor, r.StartTime, bidAdjustmentRules, r.TmaxAdjustments, responseDebugAllow, liveAdaptersPreferredMediaType)
+		adapterBids, adapterExtra, extraRespInfo = e.getAllBids(auctionCtx, bidderRequests, bidAdjustmentFactors, conversions, accountDebugAllow, r.GlobalPrivacyControlHeader, debugLog.DebugOverride, alternateBidderCodes, requestExtLegacy.Prebid.Experiment, r.HookExecutor, r.StartTime, bidAdjustmentRules, r.TmaxAdjustments, responseDebugAllow, liveAdaptersPreferredMediaType, secondaryBidders)
 		fledge = extraRespInfo.fledge
 		anyBidsReturned = extraRespInfo.bidsFound
 		r.BidderResponseStartTime = extraRespInfo.bidderResponseStartTime
@@ -404,21 +421,27 @@ func (e *exchange) HoldAuction(ctx context.Context, r *AuctionRequest, debugLog
 	}
 
 	var (
-		auc            *auction
-		cacheErrs      []error
-		bidResponseExt *openrtb_ext.ExtBidResponse
+		auc                        *auction
+		cacheErrs                  []error
+		bidResponseExt             *openrtb_ext.ExtBidResponse
+		floorsRejectedBids         []*entities.PbsOrtbSeatBid
+		floorsEnforcementEvaluated bool
 	)
 
 	if anyBidsReturned {
 		if e.priceFloorEnabled {
-			var rejectedBids []*entities.PbsOrtbSeatBid
 			var enforceErrs []error
 
-			adapterBids, enforceErrs, rejectedBids = floors.Enforce(r.BidRequestWrapper, adapterBids, r.Account, conversions)
+			adapterBids, enforceErrs, floorsRejectedBids = floors.Enforce(r.BidRequestWrapper, adapterBids, r.Account, conversions)
+			floorsEnforcementEvaluated = true
 			errs = append(errs, enforceErrs...)
-			for _, rejectedBid := range rejectedBids {
+			for _, rejectedBid := range floorsRejectedBids {
+				floorValue := rejectedBid.Bids[0].BidFloors.FloorValue
+				if rejectedBid.Bids[0].BidFloors.BidderFloorValue > 0 {
+					floorValue = rejectedBid.Bids[0].BidFloors.BidderFloorValue
+				}
 				errs = append(errs, &errortypes.Warning{
-					Message:     fmt.Sprintf("%s bid id %s rejected - bid price %.4f %s is less than bid floor %.4f %s for imp %s", rejectedBid.Seat, rejectedBid.Bids[0].Bid.ID, rejectedBid.Bids[0].Bid.Price, rejectedBid.Currency, rejectedBid.Bids[0].BidFloors.FloorValue, rejectedBid.Bids[0].BidFloors.FloorCurrency, rejectedBid.Bids[0].Bid.ImpID),
+					Message:     fmt.Sprintf("%s bid id %s rejected - bid price %.4f %s is less than bid floor %.4f %s for imp %s", rejectedBid.Seat, rejectedBid.Bids[0].Bid.ID, rejectedBid.Bids[0].Bid.Price, rejectedBid.Currency, floorValue, rejectedBid.Bids[0].BidFloors.FloorCurrency, rejectedBid.Bids[0].Bid.ImpID),
 					WarningCode: errortypes.FloorBidRejectionWarningCode})
 				rejectionReason := ResponseRejectedBelowFloor
 				if rejectedBid.Bids[0].Bid.DealID != "" {
@@ -544,9 +567,15 @@ func (e *exchange) HoldAuction(ctx context.Context, r *AuctionRequest, debugLog
 	}
 	bidResponseExt = setSeatNonBid(bidResponseExt, seatNonBidBuilder)
 
+	var floorsOutcome *analytics.FloorsOutcome
+	if e.priceFloorEnabled {
+		floorsOutcome = buildFloorsOutcome(r.BidRequestWrapper, r.Account, bidderRequests, floorsEnforcementEvaluated, floorsRejectedBids)
+	}
+
 	return &AuctionResponse{
 		BidResponse:    bidResponse,
 		ExtBidResponse: bidResponseExt,
+		FloorsOutcome:  floorsOutcome,
 	}, nil
 }
 
@@ -726,7 +755,8 @@ func (e *exchange) getAllBids(
 	bidAdjustmentRules map[string][]openrtb_ext.Adjustment,
 	tmaxAdjustments *TmaxAdjustmentsPreprocessed,
 	responseDebugAllowed bool,
-	liveAdaptersPreferredMediaType openrtb_ext.PreferredMediaType) (
+	liveAdaptersPreferredMediaType openrtb_ext.PreferredMediaType,
+	secondaryBidders map[string]struct{}) (
 	map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid,
 	map[openrtb_ext.BidderName]*seatResponseExtra,
 	extraAuctionResponseInfo) {
@@ -809,9 +839,37 @@ func (e *exchange) getAllBids(
 		go bidderRunner(bidder, conversions)
 	}
 
-	// Wait for the bidders to do their thing
-	for i := 0; i < len(bidderRequests); i++ {
-		brw := <-chBids
+	// Wait for the bidders to do their thing. Secondary bidders are only waited on while primary
+	// bidders are still bidding, unless all bidders are secondary.
+	pendingBidders := make(map[openrtb_ext.BidderName]BidderRequest, len(bidderRequests))
+	pendingPrimaryBidders := 0
+	for _, bidderRequest := range bidderRequests {
+		pendingBidders[bidderRequest.BidderName] = bidderRequest
+		if !isSecondaryBidder(secondaryBidders, bidderRequest.BidderName) {
+			pendingPrimaryBidders++
+		}
+	}
+	waitForSecondaryBidders := pendingPrimaryBidders == 0
+
+	for len(pendingBidders) > 0 {
+		var brw *bidResponseWrapper
+		if pendingPrimaryBidders > 0 || waitForSecondaryBidders {
+			brw = <-chBids
+		} else {
+			// all primary bidders responded, so only take the secondary bids which already arrived
+			select {
+			case brw = <-chBids:
+			default:
+			}
+			if brw == nil {
+				break
+			}
+		}
+		delete(pendingBidders, brw.bidder)
+		if !isSecondaryBidder(secondaryBidders, brw.bidder) {
+			pendingPrimaryBidders--
+		}
+
 		if !brw.bidderResponseStartTime.IsZero() {
 			extraRespInfo.bidderResponseStartTime = brw.bidderResponseStartTime
 		}
@@ -839,9 +897,44 @@ func (e *exchange) getAllBids(
 
 	}
 
+	// the remaining secondary bidders keep running until the auction context is done, but their bids are discarded
+	for bidderName, bidderRequest := range pendingBidders {
+		impIds := make([]string, 0, len(bidderRequest.BidRequest.Imp))
+		for _, imp := range bidderRequest.BidRequest.Imp {
+			impIds = append(impIds, imp.ID)
+		}
+		extraRespInfo.seatNonBidBuilder.rejectImps(impIds, ErrorSecondaryBidderNotFinished, bidderName.String())
+	}
+
 	return adapterBids, adapterExtra, extraRespInfo
 }
 
+// getSecondaryBidders returns the bidders the auction doesn't wait for once all other bidders
+// responded. The bidders of the request take precedence over the bidders of the account.
+func getSecondaryBidders(prebid *openrtb_ext.ExtRequestPrebid, account *config.Account) map[string]struct{} {
+	bidders := account.SecondaryBidders
+	if prebid != nil && prebid.SecondaryBidders != nil {
+		bidders = prebid.SecondaryBidders
+	}
+	if len(bidders) == 0 {
+		return nil
+	}
+
+	secondaryBidders := make(map[string]struct{}, len(bidders))
+	for _, bidder := range bidders {
+		secondaryBidders[strings.ToLower(bidder)] = struct{}{}
+	}
+	return secondaryBidders
+}
+
+func isSecondaryBidder(secondaryBidders map[string]struct{}, bidderName openrtb_ext.BidderName) bool {
+	if len(secondaryBidders) == 0 {
+		return false
+	}
+	_, found := secondaryBidders[strings.ToLower(bidderName.String())]
+	return found
+}
+
 func collectFledgeFromSeatBid(fledge *openrtb_ext.Fledge, bidderName openrtb_ext.BidderName, adapterName openrtb_ext.BidderName, seatBid *entities.PbsOrtbSeatBid) *openrtb_ext.Fledge {
 	if seatBid.FledgeAuctionConfigs != nil {
 		if fledge == nil {
@@ -884,6 +977,8 @@ func (e *exchange) recoverSafely(bidderRequests []BidderRequest,
 				e.me.RecordAdapterPanic(bidderRequest.BidderLabels)
 				// Let the master request know that there is no data here
 				brw := new(bidResponseWrapper)
+				brw.bidder = bidderRequest.BidderName
+				brw.adapter = bidderRequest.BidderCoreName
 				brw.adapterExtra = new(seatResponseExtra)
 				chBids <- brw
 			}
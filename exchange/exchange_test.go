@@ -2193,7 +2193,7 @@ func runSpec(t *testing.T, filename string, spec *exchangeSpec) {
 				Enabled: spec.EventsEnabled,
 			},
 			DebugAllow:  true,
-			PriceFloors: config.AccountPriceFloors{Enabled: spec.AccountFloorsEnabled, EnforceDealFloors: spec.AccountEnforceDealFloors},
+			PriceFloors: config.AccountPriceFloors{Enabled: spec.AccountFloorsEnabled, EnforceDealFloors: spec.AccountEnforceDealFloors, BidderAdjustments: spec.AccountFloorsBidderAdj},
 			Privacy:     spec.AccountPrivacy,
 			Validations: spec.AccountConfigBidValidation,
 			GDPR:        config.AccountGDPR{EEACountries: spec.AccountEEACountries},
@@ -5249,6 +5249,7 @@ func TestGetAllBids(t *testing.T) {
 								},
 								OriginalBidCur: "USD",
 								AdapterCode:    openrtb_ext.BidderPubmatic,
+								BidderName:     openrtb_ext.BidderPubmatic,
 							},
 						},
 						Currency:  "USD",
@@ -5318,6 +5319,7 @@ func TestGetAllBids(t *testing.T) {
 								},
 								OriginalBidCur: "USD",
 								AdapterCode:    openrtb_ext.BidderPubmatic,
+								BidderName:     openrtb_ext.BidderPubmatic,
 							},
 						},
 						Currency:  "USD",
@@ -5332,6 +5334,7 @@ func TestGetAllBids(t *testing.T) {
 								},
 								OriginalBidCur: "USD",
 								AdapterCode:    openrtb_ext.BidderPubmatic,
+								BidderName:     openrtb_ext.BidderPubmatic,
 							},
 						},
 						Currency:  "USD",
@@ -5400,6 +5403,7 @@ func TestGetAllBids(t *testing.T) {
 								},
 								OriginalBidCur: "USD",
 								AdapterCode:    openrtb_ext.BidderPubmatic,
+								BidderName:     openrtb_ext.BidderPubmatic,
 							},
 						},
 						Currency:  "USD",
@@ -5474,7 +5478,7 @@ func TestGetAllBids(t *testing.T) {
 
 			adapterBids, adapterExtra, extraRespInfo := e.getAllBids(context.Background(), test.in.bidderRequests, test.in.bidAdjustments,
 				test.in.conversions, test.in.accountDebugAllowed, test.in.globalPrivacyControlHeader, test.in.headerDebugAllowed, test.in.alternateBidderCodes, test.in.experiment,
-				test.in.hookExecutor, test.in.pbsRequestStartTime, test.in.bidAdjustmentRules, test.in.tmaxAdjustments, false, test.in.liveAdaptersPreferredMediaType)
+				test.in.hookExecutor, test.in.pbsRequestStartTime, test.in.bidAdjustmentRules, test.in.tmaxAdjustments, false, test.in.liveAdaptersPreferredMediaType, nil)
 
 			assert.Equalf(t, test.expected.extraRespInfo.bidsFound, extraRespInfo.bidsFound, "extraRespInfo.bidsFound mismatch")
 			assert.Equalf(t, test.expected.adapterBids, adapterBids, "adapterBids mismatch")
@@ -5515,6 +5519,7 @@ type exchangeSpec struct {
 	AccountConfigBidValidation config.Validations     `json:"account_bid_validations"`
 	AccountFloorsEnabled       bool                   `json:"account_floors_enabled"`
 	AccountEnforceDealFloors   bool                   `json:"account_enforce_deal_floors"`
+	AccountFloorsBidderAdj     map[string]float64     `json:"account_floors_bidder_adjustments"`
 	FledgeEnabled              bool                   `json:"fledge_enabled,omitempty"`
 	MultiBid                   *multiBidSpec          `json:"multiBid,omitempty"`
 	Server                     exchangeServer         `json:"server,omitempty"`
@@ -5585,7 +5590,7 @@ type mockIdFetcher map[string]string
 
 func (f mockIdFetcher) GetUID(key string) (uid string, exists bool, notExpired bool) {
 	uid, exists = f[string(key)]
-	return
+	return uid, exists, exists
 }
 
 func (f mockIdFetcher) HasAnyLiveSyncs() bool {
@@ -6665,3 +6670,177 @@ func TestAmpEnv(t *testing.T) {
 		assert.Equalf(t, test.expectedEnvInResponse, responseExt.Prebid.Targeting["hb_env"], "Response mismatch")
 	}
 }
+
+func TestGetSecondaryBidders(t *testing.T) {
+	testCases := []struct {
+		name     string
+		prebid   *openrtb_ext.ExtRequestPrebid
+		account  *config.Account
+		expected map[string]struct{}
+	}{
+		{
+			name:     "none",
+			prebid:   &openrtb_ext.ExtRequestPrebid{},
+			account:  &config.Account{},
+			expected: nil,
+		},
+		{
+			name:     "account",
+			prebid:   nil,
+			account:  &config.Account{SecondaryBidders: []string{"AppNexus"}},
+			expected: map[string]struct{}{"appnexus": {}},
+		},
+		{
+			name:     "request-overrides-account",
+			prebid:   &openrtb_ext.ExtRequestPrebid{SecondaryBidders: []string{"pubmatic"}},
+			account:  &config.Account{SecondaryBidders: []string{"appnexus"}},
+			expected: map[string]struct{}{"pubmatic": {}},
+		},
+		{
+			name:     "empty-request-overrides-account",
+			prebid:   &openrtb_ext.ExtRequestPrebid{SecondaryBidders: []string{}},
+			account:  &config.Account{SecondaryBidders: []string{"appnexus"}},
+			expected: nil,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, getSecondaryBidders(test.prebid, test.account))
+		})
+	}
+}
+
+func TestGetAllBidsSecondaryBidders(t *testing.T) {
+	newBidderRequest := func(bidderName openrtb_ext.BidderName) BidderRequest {
+		return BidderRequest{
+			BidderName:     bidderName,
+			BidderCoreName: bidderName,
+			BidderLabels:   metrics.AdapterLabels{Adapter: bidderName},
+			BidRequest: &openrtb2.BidRequest{
+				ID:  "some-request-id",
+				Imp: []openrtb2.Imp{{ID: "imp-1"}, {ID: "imp-2"}},
+			},
+		}
+	}
+
+	testCases := []struct {
+		name               string
+		secondaryBidders   map[string]struct{}
+		secondaryResponds  bool
+		expectedSeats      []openrtb_ext.BidderName
+		expectedSeatNonBid []openrtb_ext.SeatNonBid
+	}{
+		{
+			name:               "no-secondary-bidders",
+			secondaryBidders:   nil,
+			secondaryResponds:  true,
+			expectedSeats:      []openrtb_ext.BidderName{openrtb_ext.BidderAppnexus, openrtb_ext.BidderPubmatic},
+			expectedSeatNonBid: []openrtb_ext.SeatNonBid{},
+		},
+		{
+			name:              "secondary-not-finished",
+			secondaryBidders:  map[string]struct{}{"pubmatic": {}},
+			secondaryResponds: false,
+			expectedSeats:     []openrtb_ext.BidderName{openrtb_ext.BidderAppnexus},
+			expectedSeatNonBid: []openrtb_ext.SeatNonBid{{
+				Seat: "pubmatic",
+				NonBid: []openrtb_ext.NonBid{
+					{ImpId: "imp-1", StatusCode: int(ErrorSecondaryBidderNotFinished)},
+					{ImpId: "imp-2", StatusCode: int(ErrorSecondaryBidderNotFinished)},
+				},
+			}},
+		},
+		{
+			name:               "secondary-finished-before-primary",
+			secondaryBidders:   map[string]struct{}{"pubmatic": {}},
+			secondaryResponds:  true,
+			expectedSeats:      []openrtb_ext.BidderName{openrtb_ext.BidderAppnexus, openrtb_ext.BidderPubmatic},
+			expectedSeatNonBid: []openrtb_ext.SeatNonBid{},
+		},
+		{
+			name:               "all-bidders-secondary",
+			secondaryBidders:   map[string]struct{}{"appnexus": {}, "pubmatic": {}},
+			secondaryResponds:  true,
+			expectedSeats:      []openrtb_ext.BidderName{openrtb_ext.BidderAppnexus, openrtb_ext.BidderPubmatic},
+			expectedSeatNonBid: []openrtb_ext.SeatNonBid{},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			secondaryRelease := make(chan struct{})
+			secondaryReported := make(chan struct{})
+			defer func() {
+				if !test.secondaryResponds {
+					close(secondaryRelease)
+				}
+			}()
+			if test.secondaryResponds {
+				close(secondaryRelease)
+			}
+
+			// the primary bidder responds once the secondary bidder reported its bids, unless the
+			// secondary bidder is held back
+			primaryRelease := secondaryReported
+			if !test.secondaryResponds {
+				primaryRelease = nil
+			}
+
+			e := exchange{
+				me: &adapterRequestNotifyingMetrics{adapter: openrtb_ext.BidderPubmatic, notify: secondaryReported},
+				adapterMap: map[openrtb_ext.BidderName]AdaptedBidder{
+					openrtb_ext.BidderAppnexus: &blockingAdapter{release: primaryRelease},
+					openrtb_ext.BidderPubmatic: &blockingAdapter{release: secondaryRelease},
+				},
+			}
+			bidderRequests := []BidderRequest{newBidderRequest(openrtb_ext.BidderAppnexus), newBidderRequest(openrtb_ext.BidderPubmatic)}
+
+			adapterBids, _, extraRespInfo := e.getAllBids(context.Background(), bidderRequests, nil, &currency.ConstantRates{}, false, "", false,
+				openrtb_ext.ExtAlternateBidderCodes{}, nil, hookexecution.EmptyHookExecutor{}, time.Now(), nil, nil, false, nil, test.secondaryBidders)
+
+			seats := make([]openrtb_ext.BidderName, 0, len(adapterBids))
+			for seat := range adapterBids {
+				seats = append(seats, seat)
+			}
+			assert.ElementsMatch(t, test.expectedSeats, seats)
+			assert.Equal(t, test.expectedSeatNonBid, extraRespInfo.seatNonBidBuilder.Slice())
+		})
+	}
+}
+
+// blockingAdapter bids once the release channel is closed. A nil channel makes it respond immediately.
+type blockingAdapter struct {
+	release <-chan struct{}
+}
+
+func (b *blockingAdapter) requestBid(ctx context.Context, bidderRequest BidderRequest, conversions currency.Conversions, reqInfo *adapters.ExtraRequestInfo, adsCertSigner adscert.Signer, bidRequestMetadata bidRequestOptions, alternateBidderCodes openrtb_ext.ExtAlternateBidderCodes, executor hookexecution.StageExecutor, ruleToAdjustments openrtb_ext.AdjustmentsByDealID) ([]*entities.PbsOrtbSeatBid, extraBidderRespInfo, []error) {
+	if b.release != nil {
+		<-b.release
+	}
+	seatBid := &entities.PbsOrtbSeatBid{
+		Seat: bidderRequest.BidderName.String(),
+		Bids: []*entities.PbsOrtbBid{{Bid: &openrtb2.Bid{ID: "bid", ImpID: "imp-1", Price: 1}}},
+	}
+	return []*entities.PbsOrtbSeatBid{seatBid}, extraBidderRespInfo{}, nil
+}
+
+func (b *blockingAdapter) logHealthCheck(success bool) {}
+
+func (b *blockingAdapter) shouldRequest() bool {
+	return true
+}
+
+// adapterRequestNotifyingMetrics closes the notify channel once the adapter request of the adapter is
+// recorded, which happens after its bids are handed over to the auction.
+type adapterRequestNotifyingMetrics struct {
+	metricsConf.NilMetricsEngine
+	adapter openrtb_ext.BidderName
+	notify  chan struct{}
+}
+
+func (m *adapterRequestNotifyingMetrics) RecordAdapterRequest(labels metrics.AdapterLabels) {
+	if labels.Adapter == m.adapter {
+		close(m.notify)
+	}
+}
@@ -0,0 +1,88 @@
+package exchange
+
+import (
+	"github.com/prebid/prebid-server/v4/analytics"
+	"github.com/prebid/prebid-server/v4/config"
+	"github.com/prebid/prebid-server/v4/exchange/entities"
+	"github.com/prebid/prebid-server/v4/openrtb_ext"
+)
+
+// buildFloorsOutcome describes the floors signaled in the request and to the bidders, and enforced on the bids, so
+// that the analytics modules can compare the floor model groups. It returns nil if the floors aren't enabled for the
+// auction.
+func buildFloorsOutcome(req *openrtb_ext.RequestWrapper, account config.Account, bidderRequests []BidderRequest, bidsEvaluated bool, rejectedBids []*entities.PbsOrtbSeatBid) *analytics.FloorsOutcome {
+	if !account.PriceFloors.Enabled {
+		return nil
+	}
+
+	requestExt, err := req.GetRequestExt()
+	if err != nil {
+		return nil
+	}
+	prebidExt := requestExt.GetPrebid()
+	if prebidExt == nil || prebidExt.Floors == nil || !prebidExt.Floors.GetEnabled() {
+		return nil
+	}
+	floorsExt := prebidExt.Floors
+
+	outcome := &analytics.FloorsOutcome{
+		Location:      floorsExt.PriceFloorLocation,
+		FetchStatus:   floorsExt.FetchStatus,
+		Skipped:       floorsExt.GetFloorsSkippedFlag(),
+		BidsEvaluated: bidsEvaluated,
+	}
+	if floorsExt.Data != nil {
+		outcome.FloorProvider = floorsExt.Data.FloorProvider
+		if len(floorsExt.Data.ModelGroups) > 0 {
+			outcome.ModelVersion = floorsExt.Data.ModelGroups[0].ModelVersion
+		}
+	}
+
+	rejectedBidsByImp := make(map[string]int)
+	for _, seatBid := range rejectedBids {
+		for _, bid := range seatBid.Bids {
+			rejectedBidsByImp[bid.Bid.ImpID]++
+		}
+	}
+
+	// the floors of the bidder requests are adjusted by the bidder adjustments of the account and the bid adjustments
+	bidderFloorsByImp := make(map[string]map[string]float64)
+	for _, bidderRequest := range bidderRequests {
+		for _, imp := range bidderRequest.BidRequest.Imp {
+			if bidderFloorsByImp[imp.ID] == nil {
+				bidderFloorsByImp[imp.ID] = make(map[string]float64)
+			}
+			bidderFloorsByImp[imp.ID][bidderRequest.BidderName.String()] = imp.BidFloor
+		}
+	}
+
+	floorsSignaled := false
+	for _, imp := range req.GetImp() {
+		impOutcome := analytics.FloorsImpOutcome{
+			ImpID:         imp.ID,
+			FloorValue:    imp.BidFloor,
+			FloorCurrency: imp.BidFloorCur,
+			RejectedBids:  rejectedBidsByImp[imp.ID],
+		}
+		for bidder, bidderFloor := range bidderFloorsByImp[imp.ID] {
+			if bidderFloor != imp.BidFloor {
+				if impOutcome.BidderFloorValues == nil {
+					impOutcome.BidderFloorValues = make(map[string]float64)
+				}
+				impOutcome.BidderFloorValues[bidder] = bidderFloor
+			}
+		}
+		if impExt, err := imp.GetImpExt(); err == nil {
+			if impPrebid := impExt.GetPrebid(); impPrebid != nil && impPrebid.Floors != nil {
+				impOutcome.MatchedRule = impPrebid.Floors.FloorRule
+				impOutcome.FloorRuleValue = impPrebid.Floors.FloorRuleValue
+			}
+		}
+		floorsSignaled = floorsSignaled || imp.BidFloor > 0
+		outcome.Imps = append(outcome.Imps, impOutcome)
+	}
+
+	// enforcepbs is updated with the decision of the enforce rate when the bids are evaluated
+	outcome.Enforced = !outcome.Skipped && floorsSignaled && floorsExt.GetEnforcePBS()
+	return outcome
+}
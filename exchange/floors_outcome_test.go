@@ -0,0 +1,150 @@
+package exchange
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v4/analytics"
+	"github.com/prebid/prebid-server/v4/config"
+	"github.com/prebid/prebid-server/v4/exchange/entities"
+	"github.com/prebid/prebid-server/v4/openrtb_ext"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildFloorsOutcome(t *testing.T) {
+	floorsAccount := config.Account{PriceFloors: config.AccountPriceFloors{Enabled: true}}
+	imps := []openrtb2.Imp{
+		{ID: "imp1", BidFloor: 1.5, BidFloorCur: "USD", Ext: json.RawMessage(`{"prebid":{"floors":{"floorrule":"banner|*","floorrulevalue":1.5,"floorvalue":1.5}}}`)},
+		{ID: "imp2", BidFloor: 0.5, BidFloorCur: "USD"},
+		{ID: "imp3"},
+	}
+	rejectedBids := []*entities.PbsOrtbSeatBid{
+		{Seat: "appnexus", Bids: []*entities.PbsOrtbBid{{Bid: &openrtb2.Bid{ID: "bid1", ImpID: "imp1"}}}},
+		{Seat: "pubmatic", Bids: []*entities.PbsOrtbBid{{Bid: &openrtb2.Bid{ID: "bid2", ImpID: "imp1"}}}},
+		{Seat: "pubmatic", Bids: []*entities.PbsOrtbBid{{Bid: &openrtb2.Bid{ID: "bid3", ImpID: "imp2"}}}},
+	}
+	bidderRequests := []BidderRequest{
+		{BidderName: "appnexus", BidRequest: &openrtb2.BidRequest{Imp: []openrtb2.Imp{{ID: "imp1", BidFloor: 1.5}, {ID: "imp2", BidFloor: 0.5}}}},
+		{BidderName: "pubmatic", BidRequest: &openrtb2.BidRequest{Imp: []openrtb2.Imp{{ID: "imp1", BidFloor: 1.35}, {ID: "imp2", BidFloor: 0.45}}}},
+	}
+	expectedImps := []analytics.FloorsImpOutcome{
+		{ImpID: "imp1", MatchedRule: "banner|*", FloorRuleValue: 1.5, FloorValue: 1.5, FloorCurrency: "USD", BidderFloorValues: map[string]float64{"pubmatic": 1.35}, RejectedBids: 2},
+		{ImpID: "imp2", FloorValue: 0.5, FloorCurrency: "USD", BidderFloorValues: map[string]float64{"pubmatic": 0.45}, RejectedBids: 1},
+		{ImpID: "imp3"},
+	}
+
+	tests := []struct {
+		name           string
+		account        config.Account
+		requestExt     json.RawMessage
+		bidderRequests []BidderRequest
+		bidsEvaluated  bool
+		rejectedBids   []*entities.PbsOrtbSeatBid
+		expected       *analytics.FloorsOutcome
+	}{
+		{
+			name:       "floors_disabled_for_account",
+			account:    config.Account{},
+			requestExt: json.RawMessage(`{"prebid":{"floors":{"location":"fetch"}}}`),
+		},
+		{
+			name:       "floors_disabled_for_request",
+			account:    floorsAccount,
+			requestExt: json.RawMessage(`{"prebid":{"floors":{"enabled":false}}}`),
+		},
+		{
+			name:       "no_floors_in_request",
+			account:    floorsAccount,
+			requestExt: json.RawMessage(`{"prebid":{}}`),
+		},
+		{
+			name:           "floors_enforced",
+			account:        floorsAccount,
+			requestExt:     json.RawMessage(`{"prebid":{"floors":{"floormin":1,"data":{"floorprovider":"provider","modelgroups":[{"modelversion":"model_b","values":{"banner|*":1.5}}]},"location":"fetch","fetchstatus":"success","skipped":false,"enforcement":{"enforcepbs":true}}}}`),
+			bidderRequests: bidderRequests,
+			bidsEvaluated:  true,
+			rejectedBids:   rejectedBids,
+			expected: &analytics.FloorsOutcome{
+				ModelVersion:  "model_b",
+				FloorProvider: "provider",
+				Location:      openrtb_ext.FetchLocation,
+				FetchStatus:   openrtb_ext.FetchSuccess,
+				Enforced:      true,
+				BidsEvaluated: true,
+				Imps:          expectedImps,
+			},
+		},
+		{
+			name:          "floors_not_enforced_by_enforce_rate",
+			account:       floorsAccount,
+			requestExt:    json.RawMessage(`{"prebid":{"floors":{"data":{"modelgroups":[{"modelversion":"model_a"}]},"location":"request","fetchstatus":"none","enforcement":{"enforcepbs":false}}}}`),
+			bidsEvaluated: true,
+			expected: &analytics.FloorsOutcome{
+				ModelVersion:  "model_a",
+				Location:      openrtb_ext.RequestLocation,
+				FetchStatus:   openrtb_ext.FetchNone,
+				BidsEvaluated: true,
+				Imps: []analytics.FloorsImpOutcome{
+					{ImpID: "imp1", MatchedRule: "banner|*", FloorRuleValue: 1.5, FloorValue: 1.5, FloorCurrency: "USD"},
+					{ImpID: "imp2", FloorValue: 0.5, FloorCurrency: "USD"},
+					{ImpID: "imp3"},
+				},
+			},
+		},
+		{
+			name:       "no_bids_evaluated",
+			account:    floorsAccount,
+			requestExt: json.RawMessage(`{"prebid":{"floors":{"data":{"modelgroups":[{"modelversion":"model_a"}]},"location":"request","enforcement":{"enforcepbs":true}}}}`),
+			expected: &analytics.FloorsOutcome{
+				ModelVersion: "model_a",
+				Location:     openrtb_ext.RequestLocation,
+				Enforced:     true,
+				Imps: []analytics.FloorsImpOutcome{
+					{ImpID: "imp1", MatchedRule: "banner|*", FloorRuleValue: 1.5, FloorValue: 1.5, FloorCurrency: "USD"},
+					{ImpID: "imp2", FloorValue: 0.5, FloorCurrency: "USD"},
+					{ImpID: "imp3"},
+				},
+			},
+		},
+		{
+			name:          "floors_skipped",
+			account:       floorsAccount,
+			requestExt:    json.RawMessage(`{"prebid":{"floors":{"data":{"modelgroups":[{"modelversion":"model_a"}]},"location":"fetch","fetchstatus":"success","skipped":true}}}`),
+			bidsEvaluated: true,
+			expected: &analytics.FloorsOutcome{
+				ModelVersion:  "model_a",
+				Location:      openrtb_ext.FetchLocation,
+				FetchStatus:   openrtb_ext.FetchSuccess,
+				Skipped:       true,
+				BidsEvaluated: true,
+				Imps: []analytics.FloorsImpOutcome{
+					{ImpID: "imp1", MatchedRule: "banner|*", FloorRuleValue: 1.5, FloorValue: 1.5, FloorCurrency: "USD"},
+					{ImpID: "imp2", FloorValue: 0.5, FloorCurrency: "USD"},
+					{ImpID: "imp3"},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{
+				Imp: imps,
+				Ext: tt.requestExt,
+			}}
+
+			outcome := buildFloorsOutcome(req, tt.account, tt.bidderRequests, tt.bidsEvaluated, tt.rejectedBids)
+			assert.Equal(t, tt.expected, outcome)
+		})
+	}
+}
+
+func TestGetFloorsOutcome(t *testing.T) {
+	var nilResponse *AuctionResponse
+	assert.Nil(t, nilResponse.GetFloorsOutcome())
+
+	outcome := &analytics.FloorsOutcome{ModelVersion: "model_a"}
+	response := &AuctionResponse{FloorsOutcome: outcome}
+	assert.Equal(t, outcome, response.GetFloorsOutcome())
+}
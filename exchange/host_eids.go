@@ -0,0 +1,76 @@
+package exchange
+
+import (
+	"slices"
+
+	gpplib "github.com/prebid/go-gpp"
+	"github.com/prebid/openrtb/v20/adcom1"
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v4/config"
+	"github.com/prebid/prebid-server/v4/openrtb_ext"
+	"github.com/prebid/prebid-server/v4/privacy"
+)
+
+// hostEIDsComponent is the component enriching the user first party data with the host EIDs, as known by
+// the activity rules.
+var hostEIDsComponent = privacy.Component{Type: privacy.ComponentTypeGeneral, Name: "hostEids"}
+
+// addHostEIDs adds the UIDs of the uids cookie configured by the account to user.eids, as long as the
+// enrichUfpd activity allows it. An EID of the request is kept over a host EID of the same source.
+//
+// The host EIDs are then filtered per bidder like any other EID, by the eid permissions of the request and
+// the transmitUfpd activity. The gpp is the parsed GPP string of the request.
+func addHostEIDs(req *openrtb_ext.RequestWrapper, hostEIDs []config.AccountHostEID, usersyncs IdFetcher, activities privacy.ActivityControl, gpp gpplib.GppContainer) {
+	if len(hostEIDs) == 0 || usersyncs == nil {
+		return
+	}
+
+	if !activities.Allow(privacy.ActivityEnrichUserFPD, hostEIDsComponent, privacy.NewRequestFromBidRequestWithGPP(*req, gpp)) {
+		return
+	}
+
+	var eids []openrtb2.EID
+	if req.User != nil {
+		// clip the eids so that appending to them can't write to the request's array
+		eids = slices.Clip(req.User.EIDs)
+	}
+	eidsCount := len(eids)
+
+	for _, hostEID := range hostEIDs {
+		if hostEID.Key == "" || hostEID.Source == "" || hasEIDSource(eids, hostEID.Source) {
+			continue
+		}
+
+		uid, exists, active := usersyncs.GetUID(hostEID.Key)
+		if !exists || !active || uid == "" {
+			continue
+		}
+
+		atype := hostEID.AType
+		if atype == 0 {
+			atype = adcom1.AgentTypeWeb
+		}
+		eids = append(eids, openrtb2.EID{
+			Source: hostEID.Source,
+			UIDs:   []openrtb2.UID{{ID: uid, AType: atype}},
+		})
+	}
+
+	if len(eids) == eidsCount {
+		return
+	}
+
+	// clone User before setting the EIDs to avoid corrupting a shared pointer
+	var user openrtb2.User
+	if req.User != nil {
+		user = *req.User
+	}
+	user.EIDs = eids
+	req.User = &user
+}
+
+func hasEIDSource(eids []openrtb2.EID, source string) bool {
+	return slices.ContainsFunc(eids, func(eid openrtb2.EID) bool {
+		return eid.Source == source
+	})
+}
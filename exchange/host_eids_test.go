@@ -0,0 +1,116 @@
+package exchange
+
+import (
+	"testing"
+
+	gpplib "github.com/prebid/go-gpp"
+	"github.com/prebid/openrtb/v20/adcom1"
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v4/config"
+	"github.com/prebid/prebid-server/v4/openrtb_ext"
+	"github.com/prebid/prebid-server/v4/privacy"
+	"github.com/prebid/prebid-server/v4/util/ptrutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddHostEIDs(t *testing.T) {
+	hostEIDs := []config.AccountHostEID{
+		{Key: "host", Source: "host.com"},
+		{Key: "pub", Source: "pub.com", AType: adcom1.AgentTypePerson},
+	}
+	usersyncs := mockIdFetcher{"host": "hostUID", "pub": "pubUID", "adnxs": "adnxsUID"}
+
+	testCases := []struct {
+		name             string
+		givenUser        *openrtb2.User
+		givenHostEIDs    []config.AccountHostEID
+		givenUsersyncs   IdFetcher
+		givenActivities  privacy.ActivityControl
+		expectedUser     *openrtb2.User
+		expectedOriginal *openrtb2.User
+	}{
+		{
+			name:           "no-user",
+			givenHostEIDs:  hostEIDs,
+			givenUsersyncs: usersyncs,
+			expectedUser: &openrtb2.User{EIDs: []openrtb2.EID{
+				{Source: "host.com", UIDs: []openrtb2.UID{{ID: "hostUID", AType: adcom1.AgentTypeWeb}}},
+				{Source: "pub.com", UIDs: []openrtb2.UID{{ID: "pubUID", AType: adcom1.AgentTypePerson}}},
+			}},
+		},
+		{
+			name:           "request-eids-kept",
+			givenUser:      &openrtb2.User{ID: "id", EIDs: []openrtb2.EID{{Source: "pub.com", UIDs: []openrtb2.UID{{ID: "requestUID"}}}}},
+			givenHostEIDs:  hostEIDs,
+			givenUsersyncs: usersyncs,
+			expectedUser: &openrtb2.User{ID: "id", EIDs: []openrtb2.EID{
+				{Source: "pub.com", UIDs: []openrtb2.UID{{ID: "requestUID"}}},
+				{Source: "host.com", UIDs: []openrtb2.UID{{ID: "hostUID", AType: adcom1.AgentTypeWeb}}},
+			}},
+			expectedOriginal: &openrtb2.User{ID: "id", EIDs: []openrtb2.EID{{Source: "pub.com", UIDs: []openrtb2.UID{{ID: "requestUID"}}}}},
+		},
+		{
+			name:           "uid-missing",
+			givenUser:      &openrtb2.User{ID: "id"},
+			givenHostEIDs:  []config.AccountHostEID{{Key: "other", Source: "other.com"}},
+			givenUsersyncs: usersyncs,
+			expectedUser:   &openrtb2.User{ID: "id"},
+		},
+		{
+			name:           "uid-expired",
+			givenUser:      &openrtb2.User{ID: "id"},
+			givenHostEIDs:  hostEIDs,
+			givenUsersyncs: expiredIdFetcher{"host": "hostUID", "pub": "pubUID"},
+			expectedUser:   &openrtb2.User{ID: "id"},
+		},
+		{
+			name:           "incomplete-config",
+			givenHostEIDs:  []config.AccountHostEID{{Key: "host"}, {Source: "host.com"}},
+			givenUsersyncs: usersyncs,
+		},
+		{
+			name:          "no-usersyncs",
+			givenHostEIDs: hostEIDs,
+		},
+		{
+			name:           "enrich-activity-denied",
+			givenHostEIDs:  hostEIDs,
+			givenUsersyncs: usersyncs,
+			givenActivities: privacy.NewActivityControl(&config.AccountPrivacy{
+				AllowActivities: &config.AllowActivities{
+					EnrichUserFPD: config.Activity{
+						Default: ptrutil.ToPtr(true),
+						Rules: []config.ActivityRule{{
+							Allow:     false,
+							Condition: config.ActivityCondition{ComponentName: []string{"hostEids"}, ComponentType: []string{"general"}},
+						}},
+					},
+				},
+			}),
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			req := &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{User: test.givenUser}}
+			addHostEIDs(req, test.givenHostEIDs, test.givenUsersyncs, test.givenActivities, gpplib.GppContainer{})
+
+			assert.Equal(t, test.expectedUser, req.User)
+			if test.expectedOriginal != nil {
+				assert.Equal(t, test.expectedOriginal, test.givenUser, "the original user must not be modified")
+			}
+		})
+	}
+}
+
+// expiredIdFetcher holds UIDs which have expired.
+type expiredIdFetcher map[string]string
+
+func (f expiredIdFetcher) GetUID(key string) (uid string, exists bool, notExpired bool) {
+	uid, exists = f[key]
+	return uid, exists, false
+}
+
+func (f expiredIdFetcher) HasAnyLiveSyncs() bool {
+	return false
+}
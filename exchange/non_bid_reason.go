@@ -17,18 +17,22 @@ const (
 	ErrorGeneral                           NonBidReason = 100 // Error - General
 	ErrorTimeout                           NonBidReason = 101 // Error - Timeout
 	ErrorBidderUnreachable                 NonBidReason = 103 // Error - Bidder Unreachable
+	RequestBlockedCircuitOpen              NonBidReason = 203 // Request Blocked - Optimized (Bidder Circuit Breaker Open)
 	ResponseRejectedGeneral                NonBidReason = 300
 	ResponseRejectedBelowFloor             NonBidReason = 301 // Response Rejected - Below Floor
 	ResponseRejectedCategoryMappingInvalid NonBidReason = 303 // Response Rejected - Category Mapping Invalid
 	ResponseRejectedBelowDealFloor         NonBidReason = 304 // Response Rejected - Bid was Below Deal Floor
 	ResponseRejectedCreativeSizeNotAllowed NonBidReason = 351 // Response Rejected - Invalid Creative (Size Not Allowed)
 	ResponseRejectedCreativeNotSecure      NonBidReason = 352 // Response Rejected - Invalid Creative (Not Secure)
+	ErrorSecondaryBidderNotFinished        NonBidReason = 500 // Exchange Specific - Secondary bidder didn't respond before the primary bidders
 )
 
 func errorToNonBidReason(err error) NonBidReason {
 	switch errortypes.ReadCode(err) {
 	case errortypes.TimeoutErrorCode:
 		return ErrorTimeout
+	case errortypes.BidderCircuitOpenErrorCode:
+		return RequestBlockedCircuitOpen
 	default:
 		return ErrorGeneral
 	}
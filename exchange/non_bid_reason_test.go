@@ -28,6 +28,15 @@ func Test_httpInfoToNonBidReason(t *testing.T) {
 			},
 			want: ErrorTimeout,
 		},
+		{
+			name: "error-circuit-open",
+			args: args{
+				httpInfo: &httpCallInfo{
+					err: &errortypes.BidderCircuitOpen{},
+				},
+			},
+			want: RequestBlockedCircuitOpen,
+		},
 		{
 			name: "error-general",
 			args: args{
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
 	"math/rand"
 	"strings"
 
@@ -51,6 +52,20 @@ type requestSplitter struct {
 	requestValidator  ortb.RequestValidator
 }
 
+// parseRequestGPP parses the GPP string of the request, so that it's parsed once for the whole auction. The
+// sections parsed are returned along with the first error if some of them are invalid.
+func parseRequestGPP(req *openrtb_ext.RequestWrapper) (gpplib.GppContainer, error) {
+	if req.BidRequest.Regs == nil || len(req.BidRequest.Regs.GPP) == 0 {
+		return gpplib.GppContainer{}, nil
+	}
+
+	gpp, errs := gpplib.Parse(req.BidRequest.Regs.GPP)
+	if len(errs) > 0 {
+		return gpp, errs[0]
+	}
+	return gpp, nil
+}
+
 // cleanOpenRTBRequests splits the input request into requests which are sanitized for each bidder. Intended behavior is:
 //
 //  1. BidRequest.Imp[].Ext will only contain the "prebid" field and a "bidder" field which has the params for the intended Bidder.
@@ -60,6 +75,7 @@ func (rs *requestSplitter) cleanOpenRTBRequests(ctx context.Context,
 	auctionReq AuctionRequest,
 	requestExt *openrtb_ext.ExtRequest,
 	bidAdjustmentFactors map[string]float64,
+	gpp gpplib.GppContainer,
 ) (bidderRequests []BidderRequest, privacyLabels metrics.PrivacyLabels, errs []error) {
 	req := auctionReq.BidRequestWrapper
 	if err := PreloadExts(req); err != nil {
@@ -104,15 +120,6 @@ func (rs *requestSplitter) cleanOpenRTBRequests(ctx context.Context,
 		return
 	}
 
-	var gpp gpplib.GppContainer
-	if req.BidRequest.Regs != nil && len(req.BidRequest.Regs.GPP) > 0 {
-		var gppErrs []error
-		gpp, gppErrs = gpplib.Parse(req.BidRequest.Regs.GPP)
-		if len(gppErrs) > 0 {
-			errs = append(errs, gppErrs[0])
-		}
-	}
-
 	consent := gdpr.GetConsent(req, gpp)
 
 	ccpaEnforcer, err := extractCCPA(req.BidRequest, rs.privacyConfig, &auctionReq.Account, requestAliases, ChannelTypeMap[auctionReq.LegacyLabels.RType], gpp)
@@ -178,6 +185,9 @@ func (rs *requestSplitter) cleanOpenRTBRequests(ctx context.Context,
 			continue
 		}
 
+		// apply bidder floor adjustments
+		applyBidderAdjustmentToFloor(reqWrapperCopy, bidder, coreBidder, auctionReq.Account.PriceFloors)
+
 		// apply bid adjustments
 		if auctionReq.Account.PriceFloors.IsAdjustForBidAdjustmentEnabled() {
 			applyBidAdjustmentToFloor(reqWrapperCopy, bidder, bidAdjustmentFactors)
@@ -190,7 +200,7 @@ func (rs *requestSplitter) cleanOpenRTBRequests(ctx context.Context,
 		auctionPermissions := gdprPerms.AuctionActivitiesAllowed(ctx, coreBidder, openrtb_ext.BidderName(bidder))
 
 		// privacy blocking
-		if rs.isBidderBlockedByPrivacy(reqWrapperCopy, auctionReq.Activities, auctionPermissions, coreBidder, openrtb_ext.BidderName(bidder)) {
+		if rs.isBidderBlockedByPrivacy(reqWrapperCopy, auctionReq.Activities, auctionPermissions, coreBidder, openrtb_ext.BidderName(bidder), gpp) {
 			errs = append(errs, &errortypes.Warning{
 				Message:     fmt.Sprintf("bidder %q blocked by privacy settings", coreBidder),
 				WarningCode: errortypes.BidderBlockedByPrivacySettings,
@@ -202,7 +212,7 @@ func (rs *requestSplitter) cleanOpenRTBRequests(ctx context.Context,
 		applyFPD(auctionReq.FirstPartyData, coreBidder, openrtb_ext.BidderName(bidder), isRequestAlias, reqWrapperCopy, fpdUserEIDsPresent)
 
 		// privacy scrubbing
-		if err := rs.applyPrivacy(reqWrapperCopy, coreBidder, bidder, auctionReq, auctionPermissions, ccpaEnforcer, lmt, coppa); err != nil {
+		if err := rs.applyPrivacy(reqWrapperCopy, coreBidder, bidder, auctionReq, auctionPermissions, ccpaEnforcer, lmt, coppa, gpp); err != nil {
 			errs = append(errs, err)
 			continue
 		}
@@ -347,10 +357,10 @@ func PreloadExts(req *openrtb_ext.RequestWrapper) error {
 	return nil
 }
 
-func (rs *requestSplitter) isBidderBlockedByPrivacy(r *openrtb_ext.RequestWrapper, activities privacy.ActivityControl, auctionPermissions gdpr.AuctionPermissions, coreBidder, bidderName openrtb_ext.BidderName) bool {
+func (rs *requestSplitter) isBidderBlockedByPrivacy(r *openrtb_ext.RequestWrapper, activities privacy.ActivityControl, auctionPermissions gdpr.AuctionPermissions, coreBidder, bidderName openrtb_ext.BidderName, gpp gpplib.GppContainer) bool {
 	// activities control
 	scope := privacy.Component{Type: privacy.ComponentTypeBidder, Name: bidderName.String()}
-	fetchBidsActivityAllowed := activities.Allow(privacy.ActivityFetchBids, scope, privacy.NewRequestFromBidRequest(*r))
+	fetchBidsActivityAllowed := activities.Allow(privacy.ActivityFetchBids, scope, privacy.NewRequestFromBidRequestWithGPP(*r, gpp))
 	if !fetchBidsActivityAllowed {
 		return true
 	}
@@ -364,14 +374,14 @@ func (rs *requestSplitter) isBidderBlockedByPrivacy(r *openrtb_ext.RequestWrappe
 	return false
 }
 
-func (rs *requestSplitter) applyPrivacy(reqWrapper *openrtb_ext.RequestWrapper, coreBidderName openrtb_ext.BidderName, bidderName string, auctionReq AuctionRequest, auctionPermissions gdpr.AuctionPermissions, ccpaEnforcer privacy.PolicyEnforcer, lmt bool, coppa bool) error {
+func (rs *requestSplitter) applyPrivacy(reqWrapper *openrtb_ext.RequestWrapper, coreBidderName openrtb_ext.BidderName, bidderName string, auctionReq AuctionRequest, auctionPermissions gdpr.AuctionPermissions, ccpaEnforcer privacy.PolicyEnforcer, lmt bool, coppa bool, gpp gpplib.GppContainer) error {
 	scope := privacy.Component{Type: privacy.ComponentTypeBidder, Name: bidderName}
 	ipConf := privacy.IPConf{IPV6: auctionReq.Account.Privacy.IPv6Config, IPV4: auctionReq.Account.Privacy.IPv4Config}
 
 	bidRequest := ortb.CloneBidRequestPartial(reqWrapper.BidRequest)
 	reqWrapper.BidRequest = bidRequest
 
-	passIDActivityAllowed := auctionReq.Activities.Allow(privacy.ActivityTransmitUserFPD, scope, privacy.NewRequestFromBidRequest(*reqWrapper))
+	passIDActivityAllowed := auctionReq.Activities.Allow(privacy.ActivityTransmitUserFPD, scope, privacy.NewRequestFromBidRequestWithGPP(*reqWrapper, gpp))
 	buyerUIDSet := reqWrapper.User != nil && reqWrapper.User.BuyerUID != ""
 	buyerUIDRemoved := false
 	if !passIDActivityAllowed {
@@ -392,7 +402,7 @@ func (rs *requestSplitter) applyPrivacy(reqWrapper *openrtb_ext.RequestWrapper,
 		rs.me.RecordAdapterBuyerUIDScrubbed(coreBidderName)
 	}
 
-	passGeoActivityAllowed := auctionReq.Activities.Allow(privacy.ActivityTransmitPreciseGeo, scope, privacy.NewRequestFromBidRequest(*reqWrapper))
+	passGeoActivityAllowed := auctionReq.Activities.Allow(privacy.ActivityTransmitPreciseGeo, scope, privacy.NewRequestFromBidRequestWithGPP(*reqWrapper, gpp))
 	if !passGeoActivityAllowed {
 		privacy.ScrubGeoAndDeviceIP(reqWrapper, ipConf)
 	} else {
@@ -408,7 +418,7 @@ func (rs *requestSplitter) applyPrivacy(reqWrapper *openrtb_ext.RequestWrapper,
 		privacy.ScrubDeviceIDsIPsUserDemoExt(reqWrapper, ipConf, "eids", coppa)
 	}
 
-	passTIDAllowed := auctionReq.Activities.Allow(privacy.ActivityTransmitTIDs, scope, privacy.NewRequestFromBidRequest(*reqWrapper))
+	passTIDAllowed := auctionReq.Activities.Allow(privacy.ActivityTransmitTIDs, scope, privacy.NewRequestFromBidRequestWithGPP(*reqWrapper, gpp))
 	if !passTIDAllowed {
 		privacy.ScrubTID(reqWrapper)
 	}
@@ -1190,6 +1200,22 @@ func getPrebidMediaTypeForBid(bid openrtb2.Bid) (openrtb_ext.BidType, error) {
 	}
 }
 
+// applyBidderAdjustmentToFloor multiplies the imp floors by the account floor adjustment of the bidder, or of its
+// core adapter if the bidder is an alias without one. The enforcement compares all the bids of the bidder with the
+// same adjusted floors, including the ones made under an alternate bidder code.
+func applyBidderAdjustmentToFloor(req *openrtb_ext.RequestWrapper, bidder string, coreBidder openrtb_ext.BidderName, priceFloors config.AccountPriceFloors) {
+	if len(priceFloors.BidderAdjustments) == 0 {
+		return
+	}
+
+	if adjustment := priceFloors.GetBidderAdjustment(bidder, coreBidder.String()); adjustment != 1.0 {
+		for index, imp := range req.Imp {
+			imp.BidFloor = math.Round(imp.BidFloor*adjustment*10000) / 10000
+			req.Imp[index] = imp
+		}
+	}
+}
+
 func applyBidAdjustmentToFloor(req *openrtb_ext.RequestWrapper, bidder string, adjustmentFactors map[string]float64) {
 	if len(adjustmentFactors) == 0 {
 		return
@@ -769,7 +769,7 @@ func TestCleanOpenRTBRequests(t *testing.T) {
 			hostSChainNode:    nil,
 			bidderInfo:        config.BidderInfos{},
 		}
-		bidderRequests, _, err := reqSplitter.cleanOpenRTBRequests(context.Background(), test.req, nil, map[string]float64{})
+		bidderRequests, _, err := reqSplitter.cleanOpenRTBRequests(context.Background(), test.req, nil, map[string]float64{}, gpplib.GppContainer{})
 		if test.hasError {
 			assert.NotNil(t, err, "Error shouldn't be nil")
 		} else {
@@ -835,7 +835,7 @@ func TestCleanOpenRTBRequestsWithFPD(t *testing.T) {
 			bidderInfo:        config.BidderInfos{},
 		}
 
-		bidderRequests, _, err := reqSplitter.cleanOpenRTBRequests(context.Background(), test.req, nil, map[string]float64{})
+		bidderRequests, _, err := reqSplitter.cleanOpenRTBRequests(context.Background(), test.req, nil, map[string]float64{}, gpplib.GppContainer{})
 		assert.Empty(t, err, "No errors should be returned")
 		for _, bidderRequest := range bidderRequests {
 			bidderName := bidderRequest.BidderName
@@ -1158,7 +1158,7 @@ func TestCleanOpenRTBRequestsWithBidResponses(t *testing.T) {
 			bidderInfo:        config.BidderInfos{},
 		}
 
-		actualBidderRequests, _, err := reqSplitter.cleanOpenRTBRequests(context.Background(), auctionReq, nil, map[string]float64{})
+		actualBidderRequests, _, err := reqSplitter.cleanOpenRTBRequests(context.Background(), auctionReq, nil, map[string]float64{}, gpplib.GppContainer{})
 		assert.Empty(t, err, "No errors should be returned")
 		assert.Len(t, actualBidderRequests, len(test.expectedBidderRequests), "result len doesn't match for testCase %s", test.description)
 		for _, actualBidderRequest := range actualBidderRequests {
@@ -1330,7 +1330,7 @@ func TestCleanOpenRTBRequestsCCPA(t *testing.T) {
 			bidderInfo:        config.BidderInfos{},
 		}
 
-		bidderRequests, privacyLabels, errs := reqSplitter.cleanOpenRTBRequests(context.Background(), auctionReq, nil, map[string]float64{})
+		bidderRequests, privacyLabels, errs := reqSplitter.cleanOpenRTBRequests(context.Background(), auctionReq, nil, map[string]float64{}, gpplib.GppContainer{})
 		result := bidderRequests[0]
 
 		assert.Nil(t, errs)
@@ -1409,7 +1409,7 @@ func TestCleanOpenRTBRequestsCCPAErrors(t *testing.T) {
 			bidderInfo:        config.BidderInfos{},
 		}
 
-		_, _, errs := reqSplitter.cleanOpenRTBRequests(context.Background(), auctionReq, &reqExtStruct, map[string]float64{})
+		_, _, errs := reqSplitter.cleanOpenRTBRequests(context.Background(), auctionReq, &reqExtStruct, map[string]float64{}, gpplib.GppContainer{})
 
 		assert.ElementsMatch(t, []error{test.expectError}, errs, test.description)
 	}
@@ -1468,7 +1468,7 @@ func TestCleanOpenRTBRequestsCOPPA(t *testing.T) {
 			bidderInfo:        config.BidderInfos{},
 		}
 
-		bidderRequests, privacyLabels, errs := reqSplitter.cleanOpenRTBRequests(context.Background(), auctionReq, nil, map[string]float64{})
+		bidderRequests, privacyLabels, errs := reqSplitter.cleanOpenRTBRequests(context.Background(), auctionReq, nil, map[string]float64{}, gpplib.GppContainer{})
 		result := bidderRequests[0]
 
 		assert.Nil(t, errs)
@@ -1629,7 +1629,7 @@ func TestCleanOpenRTBRequestsSChain(t *testing.T) {
 			bidderInfo:        config.BidderInfos{"appnexus": config.BidderInfo{OpenRTB: &config.OpenRTBInfo{Version: test.ortbVersion}}},
 		}
 
-		bidderRequests, _, errs := reqSplitter.cleanOpenRTBRequests(context.Background(), auctionReq, extRequest, map[string]float64{})
+		bidderRequests, _, errs := reqSplitter.cleanOpenRTBRequests(context.Background(), auctionReq, extRequest, map[string]float64{}, gpplib.GppContainer{})
 		if test.hasError == true {
 			assert.NotNil(t, errs)
 			assert.Len(t, bidderRequests, 0)
@@ -1700,7 +1700,7 @@ func TestCleanOpenRTBRequestsBidderParams(t *testing.T) {
 			bidderInfo:        config.BidderInfos{},
 		}
 
-		bidderRequests, _, errs := reqSplitter.cleanOpenRTBRequests(context.Background(), auctionReq, extRequest, map[string]float64{})
+		bidderRequests, _, errs := reqSplitter.cleanOpenRTBRequests(context.Background(), auctionReq, extRequest, map[string]float64{}, gpplib.GppContainer{})
 		if test.hasError == true {
 			assert.NotNil(t, errs)
 			assert.Len(t, bidderRequests, 0)
@@ -2312,7 +2312,7 @@ func TestCleanOpenRTBRequestsLMT(t *testing.T) {
 			bidderInfo:        config.BidderInfos{},
 		}
 
-		results, privacyLabels, errs := reqSplitter.cleanOpenRTBRequests(context.Background(), auctionReq, nil, map[string]float64{})
+		results, privacyLabels, errs := reqSplitter.cleanOpenRTBRequests(context.Background(), auctionReq, nil, map[string]float64{}, gpplib.GppContainer{})
 		result := results[0]
 
 		assert.Nil(t, errs)
@@ -2427,7 +2427,7 @@ func TestCleanOpenRTBRequestsGDPR(t *testing.T) {
 			bidderInfo:        config.BidderInfos{},
 		}
 
-		results, privacyLabels, errs := reqSplitter.cleanOpenRTBRequests(context.Background(), auctionReq, nil, map[string]float64{})
+		results, privacyLabels, errs := reqSplitter.cleanOpenRTBRequests(context.Background(), auctionReq, nil, map[string]float64{}, gpplib.GppContainer{})
 		result := results[0]
 
 		if test.expectError {
@@ -2528,7 +2528,7 @@ func TestCleanOpenRTBRequestsGDPRBlockBidRequest(t *testing.T) {
 			bidderInfo:        config.BidderInfos{},
 		}
 
-		results, _, errs := reqSplitter.cleanOpenRTBRequests(context.Background(), auctionReq, nil, map[string]float64{})
+		results, _, errs := reqSplitter.cleanOpenRTBRequests(context.Background(), auctionReq, nil, map[string]float64{}, gpplib.GppContainer{})
 
 		// extract bidder name from each request in the results
 		bidders := []openrtb_ext.BidderName{}
@@ -2548,6 +2548,44 @@ func TestCleanOpenRTBRequestsGDPRBlockBidRequest(t *testing.T) {
 	}
 }
 
+func TestParseRequestGPP(t *testing.T) {
+	testCases := []struct {
+		name             string
+		givenRegs        *openrtb2.Regs
+		expectedSections []constants.SectionID
+		expectedErr      bool
+	}{
+		{
+			name: "no_regs",
+		},
+		{
+			name:      "no_gpp",
+			givenRegs: &openrtb2.Regs{},
+		},
+		{
+			name:             "valid",
+			givenRegs:        &openrtb2.Regs{GPP: "DBABLA~BEAQAAAAAAA.QA"},
+			expectedSections: []constants.SectionID{constants.SectionUSPNAT},
+		},
+		{
+			name:        "invalid",
+			givenRegs:   &openrtb2.Regs{GPP: "malformed"},
+			expectedErr: true,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			req := &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{Regs: test.givenRegs}}
+
+			gpp, err := parseRequestGPP(req)
+
+			assert.Equal(t, test.expectedErr, err != nil)
+			assert.Equal(t, test.expectedSections, gpp.SectionTypes)
+		})
+	}
+}
+
 func TestCleanOpenRTBRequestsWithOpenRTBDowngrade(t *testing.T) {
 	emptyTCF2Config := gdpr.NewTCF2Config(config.TCF2{}, config.AccountGDPR{})
 
@@ -2615,7 +2653,8 @@ func TestCleanOpenRTBRequestsWithOpenRTBDowngrade(t *testing.T) {
 				hostSChainNode:    nil,
 				bidderInfo:        test.bidderInfos,
 			}
-			bidderRequests, _, err := reqSplitter.cleanOpenRTBRequests(context.Background(), test.req, nil, map[string]float64{})
+			gpp, _ := parseRequestGPP(test.req.BidRequestWrapper)
+			bidderRequests, _, err := reqSplitter.cleanOpenRTBRequests(context.Background(), test.req, nil, map[string]float64{}, gpp)
 			assert.Nil(t, err, "Err should be nil")
 			bidRequest := bidderRequests[0]
 			assert.Equal(t, test.expectRegs, bidRequest.BidRequest.Regs)
@@ -3253,7 +3292,7 @@ func TestCleanOpenRTBRequestsEidPermissionsRaceCondition(t *testing.T) {
 			bidderInfo:        bidderInfo,
 		}
 
-		bidderRequests, _, errs := reqSplitter.cleanOpenRTBRequests(context.Background(), buildAuctionRequest(), nil, map[string]float64{})
+		bidderRequests, _, errs := reqSplitter.cleanOpenRTBRequests(context.Background(), buildAuctionRequest(), nil, map[string]float64{}, gpplib.GppContainer{})
 		require.Empty(t, errs, "iteration %d returned errors", i)
 		require.Len(t, bidderRequests, len(permittedBidders)+len(deniedBidders), "iteration %d produced unexpected number of bidder requests", i)
 
@@ -3481,7 +3520,7 @@ func TestCleanOpenRTBRequestsSChainMultipleBidders(t *testing.T) {
 		hostSChainNode:    nil,
 		bidderInfo:        config.BidderInfos{"appnexus": ortb26enabled, "axonix": ortb26enabled},
 	}
-	bidderRequests, _, errs := reqSplitter.cleanOpenRTBRequests(context.Background(), auctionReq, extRequest, map[string]float64{})
+	bidderRequests, _, errs := reqSplitter.cleanOpenRTBRequests(context.Background(), auctionReq, extRequest, map[string]float64{}, gpplib.GppContainer{})
 
 	assert.Nil(t, errs)
 	assert.Len(t, bidderRequests, 2, "Bid request count is not 2")
@@ -3630,7 +3669,7 @@ func TestCleanOpenRTBRequestsBidAdjustment(t *testing.T) {
 			hostSChainNode:    nil,
 			bidderInfo:        config.BidderInfos{},
 		}
-		results, _, errs := reqSplitter.cleanOpenRTBRequests(context.Background(), auctionReq, nil, test.bidAdjustmentFactor)
+		results, _, errs := reqSplitter.cleanOpenRTBRequests(context.Background(), auctionReq, nil, test.bidAdjustmentFactor, gpplib.GppContainer{})
 		result := results[0]
 		assert.Nil(t, errs)
 		assert.Equal(t, test.expectedImp, result.BidRequest.Imp, test.description)
@@ -3784,7 +3823,7 @@ func TestCleanOpenRTBRequestsBuyerUID(t *testing.T) {
 				},
 			}
 
-			results, _, errs := reqSplitter.cleanOpenRTBRequests(context.Background(), auctionReq, nil, nil)
+			results, _, errs := reqSplitter.cleanOpenRTBRequests(context.Background(), auctionReq, nil, nil, gpplib.GppContainer{})
 
 			assert.Empty(t, errs)
 			for _, v := range results {
@@ -4283,7 +4322,7 @@ func TestCleanOpenRTBRequestsFilterBidderRequestExt(t *testing.T) {
 			bidderInfo:        config.BidderInfos{},
 		}
 
-		bidderRequests, _, errs := reqSplitter.cleanOpenRTBRequests(context.Background(), auctionReq, extRequest, map[string]float64{})
+		bidderRequests, _, errs := reqSplitter.cleanOpenRTBRequests(context.Background(), auctionReq, extRequest, map[string]float64{}, gpplib.GppContainer{})
 		assert.Equal(t, test.wantError, len(errs) != 0, test.desc)
 		sort.Slice(bidderRequests, func(i, j int) bool {
 			return bidderRequests[i].BidderCoreName < bidderRequests[j].BidderCoreName
@@ -5329,6 +5368,40 @@ func TestCleanOpenRTBRequestsActivities(t *testing.T) {
 			},
 			expectedSource: expectedSourceDefault,
 		},
+		{
+			name:              "transmit_ufpd_deny_gpp_us",
+			req:               newBidRequestWithGPP("DBABLA~BEAQAAAAAAA.QA", []int8{7}),
+			privacyConfig:     getGPPUSActivityConfig(),
+			ortbVersion:       "2.6",
+			expectedReqNumber: 1,
+			expectedUser: openrtb2.User{
+				ID:       "",
+				BuyerUID: "",
+				Yob:      0,
+				Geo:      &openrtb2.Geo{Lat: ptrutil.ToPtr(123.456), Lon: ptrutil.ToPtr(11.278)},
+				EIDs:     nil,
+				Ext:      json.RawMessage(`{"test":2}`),
+				Data:     nil,
+			},
+			expectUserScrub: true,
+			expectedDevice: openrtb2.Device{
+				UA:       deviceUA,
+				Language: "EN",
+				IP:       "132.173.230.74",
+				Geo:      &openrtb2.Geo{Lat: ptrutil.ToPtr(123.456), Lon: ptrutil.ToPtr(11.278)},
+			},
+			expectedSource: expectedSourceDefault,
+		},
+		{
+			name:              "transmit_ufpd_allowed_gpp_us_not_applicable",
+			req:               newBidRequestWithGPP("DBABLA~BEAQAAAAAAA.QA", []int8{8}),
+			privacyConfig:     getGPPUSActivityConfig(),
+			ortbVersion:       "2.6",
+			expectedReqNumber: 1,
+			expectedUser:      expectedUserDefault,
+			expectedDevice:    expectedDeviceDefault,
+			expectedSource:    expectedSourceDefault,
+		},
 		{
 			name:              "transmit_precise_geo_allowed",
 			req:               newBidRequest(),
@@ -5429,7 +5502,8 @@ func TestCleanOpenRTBRequestsActivities(t *testing.T) {
 				bidderInfo:        config.BidderInfos{"appnexus": config.BidderInfo{OpenRTB: &config.OpenRTBInfo{Version: test.ortbVersion}}},
 			}
 
-			bidderRequests, _, errs := reqSplitter.cleanOpenRTBRequests(context.Background(), auctionReq, nil, map[string]float64{})
+			gpp, _ := parseRequestGPP(auctionReq.BidRequestWrapper)
+			bidderRequests, _, errs := reqSplitter.cleanOpenRTBRequests(context.Background(), auctionReq, nil, map[string]float64{}, gpp)
 			assert.Equal(t, test.expectedErrors, errs)
 			assert.Len(t, bidderRequests, test.expectedReqNumber)
 
@@ -5490,6 +5564,25 @@ func getTransmitPreciseGeoActivityConfig(componentName string, allow bool) confi
 	}
 }
 
+func getGPPUSActivityConfig() config.AccountPrivacy {
+	return config.AccountPrivacy{
+		AllowActivities: &config.AllowActivities{
+			TransmitUserFPD: config.Activity{
+				Default: ptrutil.ToPtr(true),
+				Rules: []config.ActivityRule{
+					{PrivacyReg: []string{"iab.usgeneral"}},
+				},
+			},
+		},
+	}
+}
+
+func newBidRequestWithGPP(gpp string, gppSID []int8) *openrtb2.BidRequest {
+	req := newBidRequest()
+	req.Regs = &openrtb2.Regs{GPP: gpp, GPPSID: gppSID}
+	return req
+}
+
 func getTransmitTIDActivityConfig(componentName string, allow bool) config.AccountPrivacy {
 	return config.AccountPrivacy{
 		AllowActivities: &config.AllowActivities{
@@ -5594,6 +5687,64 @@ func TestApplyBidAdjustmentToFloor(t *testing.T) {
 	}
 }
 
+func TestApplyBidderAdjustmentToFloor(t *testing.T) {
+	tests := []struct {
+		name               string
+		bidderName         string
+		coreBidder         openrtb_ext.BidderName
+		bidderAdjustments  map[string]float64
+		expectedBidRequest *openrtb2.BidRequest
+	}{
+		{
+			name:               "bidder_adjustments_are_nil",
+			bidderName:         "appnexus",
+			coreBidder:         "appnexus",
+			bidderAdjustments:  nil,
+			expectedBidRequest: &openrtb2.BidRequest{Imp: []openrtb2.Imp{{BidFloor: 1.5}, {BidFloor: 2}}},
+		},
+		{
+			name:               "bidder_adjustment_not_present",
+			bidderName:         "appnexus",
+			coreBidder:         "appnexus",
+			bidderAdjustments:  map[string]float64{"pubmatic": 0.8},
+			expectedBidRequest: &openrtb2.BidRequest{Imp: []openrtb2.Imp{{BidFloor: 1.5}, {BidFloor: 2}}},
+		},
+		{
+			name:               "bidder_adjustment_present",
+			bidderName:         "appnexus",
+			coreBidder:         "appnexus",
+			bidderAdjustments:  map[string]float64{"pubmatic": 0.8, "appnexus": 1.1},
+			expectedBidRequest: &openrtb2.BidRequest{Imp: []openrtb2.Imp{{BidFloor: 1.65}, {BidFloor: 2.2}}},
+		},
+		{
+			name:               "alias_adjustment_present",
+			bidderName:         "alias",
+			coreBidder:         "appnexus",
+			bidderAdjustments:  map[string]float64{"alias": 0.8, "appnexus": 1.1},
+			expectedBidRequest: &openrtb2.BidRequest{Imp: []openrtb2.Imp{{BidFloor: 1.2}, {BidFloor: 1.6}}},
+		},
+		{
+			name:               "alias_adjustment_not_present_uses_core_bidder",
+			bidderName:         "alias",
+			coreBidder:         "appnexus",
+			bidderAdjustments:  map[string]float64{"appnexus": 1.1},
+			expectedBidRequest: &openrtb2.BidRequest{Imp: []openrtb2.Imp{{BidFloor: 1.65}, {BidFloor: 2.2}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bidRequestWrapper := &openrtb_ext.RequestWrapper{
+				BidRequest: &openrtb2.BidRequest{
+					Imp: []openrtb2.Imp{{BidFloor: 1.5}, {BidFloor: 2}},
+				},
+			}
+			applyBidderAdjustmentToFloor(bidRequestWrapper, tt.bidderName, tt.coreBidder, config.AccountPriceFloors{BidderAdjustments: tt.bidderAdjustments})
+			assert.NoError(t, bidRequestWrapper.RebuildRequest())
+			assert.Equal(t, tt.expectedBidRequest, bidRequestWrapper.BidRequest)
+		})
+	}
+}
+
 func TestBuildRequestExtAlternateBidderCodes(t *testing.T) {
 	type testInput struct {
 		bidderNameRaw string
@@ -38,10 +38,10 @@ func Enforce(bidRequestWrapper *openrtb_ext.RequestWrapper, seatBids map[openrtb
 			return seatBids, []error{err}, rejectedBids
 		}
 	}
-	updateBidExt(bidRequestWrapper, seatBids)
+	updateBidExt(bidRequestWrapper, seatBids, account.PriceFloors)
 	if enforceFloors {
 		enforceDealFloors := account.PriceFloors.EnforceDealFloors && getEnforceDealsFlag(requestExt)
-		seatBids, rejectionErrs, rejectedBids = enforceFloorToBids(bidRequestWrapper, seatBids, conversions, enforceDealFloors)
+		seatBids, rejectionErrs, rejectedBids = enforceFloorToBids(bidRequestWrapper, seatBids, conversions, enforceDealFloors, account.PriceFloors)
 	}
 	return seatBids, rejectionErrs, rejectedBids
 }
@@ -82,17 +82,18 @@ func updateEnforcePBS(enforceFloors bool, requestExt *openrtb_ext.RequestExt) bo
 }
 
 // updateBidExt updates bid extension for floors related details
-func updateBidExt(bidRequestWrapper *openrtb_ext.RequestWrapper, seatBids map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid) {
+func updateBidExt(bidRequestWrapper *openrtb_ext.RequestWrapper, seatBids map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid, priceFloors config.AccountPriceFloors) {
 	impMap := make(map[string]*openrtb_ext.ImpWrapper, bidRequestWrapper.LenImp())
 	for _, imp := range bidRequestWrapper.GetImp() {
 		impMap[imp.ID] = imp
 	}
 
-	for _, seatBid := range seatBids {
+	for bidderName, seatBid := range seatBids {
 		for _, bid := range seatBid.Bids {
 			reqImp, ok := impMap[bid.Bid.ImpID]
 			if ok {
 				updateBidExtWithFloors(reqImp, bid, reqImp.BidFloorCur)
+				updateBidExtWithBidderFloor(bid, getBidderAdjustment(priceFloors, bidderName, bid))
 			}
 		}
 	}
@@ -100,7 +101,7 @@ func updateBidExt(bidRequestWrapper *openrtb_ext.RequestWrapper, seatBids map[op
 
 // enforceFloorToBids function does floors enforcement for each bid,
 // The bids returned by each partner below bid floor price are rejected and remaining eligible bids are considered for further processing
-func enforceFloorToBids(bidRequestWrapper *openrtb_ext.RequestWrapper, seatBids map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid, conversions currency.Conversions, enforceDealFloors bool) (map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid, []error, []*entities.PbsOrtbSeatBid) {
+func enforceFloorToBids(bidRequestWrapper *openrtb_ext.RequestWrapper, seatBids map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid, conversions currency.Conversions, enforceDealFloors bool, priceFloors config.AccountPriceFloors) (map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid, []error, []*entities.PbsOrtbSeatBid) {
 	errs := []error{}
 	rejectedBids := []*entities.PbsOrtbSeatBid{}
 	impMap := make(map[string]*openrtb_ext.ImpWrapper, bidRequestWrapper.LenImp())
@@ -137,7 +138,8 @@ func enforceFloorToBids(bidRequestWrapper *openrtb_ext.RequestWrapper, seatBids
 				}
 
 				bidPrice := rate * bid.Bid.Price
-				if (bidPrice + floorPrecision) < reqImp.BidFloor {
+				bidFloor := getBidderFloor(reqImp.BidFloor, getBidderAdjustment(priceFloors, bidderName, bid))
+				if (bidPrice + floorPrecision) < bidFloor {
 					rejectedBid := &entities.PbsOrtbSeatBid{
 						Currency: seatBid.Currency,
 						Seat:     seatBid.Seat,
@@ -258,3 +260,29 @@ func updateBidExtWithFloors(reqImp *openrtb_ext.ImpWrapper, bid *entities.PbsOrt
 		bid.BidFloors = &bidExtFloors
 	}
 }
+
+// updateBidExtWithBidderFloor records the floor signaled to the bidder in bid extension, when the bidder has a floor adjustment
+func updateBidExtWithBidderFloor(bid *entities.PbsOrtbBid, bidderAdjustment float64) {
+	if bid.BidFloors != nil && bid.BidFloors.FloorValue > 0 && bidderAdjustment != 1.0 {
+		bid.BidFloors.BidderFloorValue = getBidderFloor(bid.BidFloors.FloorValue, bidderAdjustment)
+	}
+}
+
+// getBidderFloor applies the bidder adjustment to the floor
+func getBidderFloor(floor float64, bidderAdjustment float64) float64 {
+	if bidderAdjustment == 1.0 {
+		return floor
+	}
+	return roundToFourDecimals(floor * bidderAdjustment)
+}
+
+// getBidderAdjustment returns the floor adjustment of the bidder the request was sent to, or of its adapter,
+// which is the adjustment applied to the floors signaled to the bidder. Bids made under an alternate bidder
+// code are enforced with the floors of the bidder which made them, not the ones of their seat.
+func getBidderAdjustment(priceFloors config.AccountPriceFloors, seat openrtb_ext.BidderName, bid *entities.PbsOrtbBid) float64 {
+	bidder := bid.BidderName
+	if bidder == "" {
+		bidder = seat
+	}
+	return priceFloors.GetBidderAdjustment(bidder.String(), bid.AdapterCode.String())
+}
@@ -64,6 +64,7 @@ func TestEnforceFloorToBids(t *testing.T) {
 		seatBids          map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid
 		conversions       currency.Conversions
 		enforceDealFloors bool
+		priceFloorsCfg    config.AccountPriceFloors
 	}
 	tests := []struct {
 		name            string
@@ -397,9 +398,130 @@ func TestEnforceFloorToBids(t *testing.T) {
 			expRejectedBids: []*entities.PbsOrtbSeatBid{},
 			expErrs:         []error{},
 		},
+		{
+			name: "Bids enforced against the bidder adjusted floors",
+			args: args{
+				bidRequestWrapper: func() *openrtb_ext.RequestWrapper {
+					bw := openrtb_ext.RequestWrapper{
+						BidRequest: &openrtb2.BidRequest{
+							ID: "some-request-id",
+							Imp: []openrtb2.Imp{
+								{ID: "some-impression-id-1", BidFloor: 1.0, BidFloorCur: "USD"},
+							},
+						},
+					}
+					bw.RebuildRequest()
+					return &bw
+				}(),
+				seatBids: map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid{
+					"pubmatic": {
+						Bids: []*entities.PbsOrtbBid{
+							{Bid: &openrtb2.Bid{ID: "some-bid-1", Price: 0.85, ImpID: "some-impression-id-1"}},
+						},
+						Seat:     "pubmatic",
+						Currency: "USD",
+					},
+					"appnexus": {
+						Bids: []*entities.PbsOrtbBid{
+							{Bid: &openrtb2.Bid{ID: "some-bid-11", Price: 1.1, ImpID: "some-impression-id-1"}},
+						},
+						Seat:     "appnexus",
+						Currency: "USD",
+					},
+					"groupm": {
+						Bids: []*entities.PbsOrtbBid{
+							{Bid: &openrtb2.Bid{ID: "some-bid-21", Price: 0.95, ImpID: "some-impression-id-1"}, AdapterCode: "pubmatic"},
+						},
+						Seat:     "groupm",
+						Currency: "USD",
+					},
+				},
+				conversions:    currency.Conversions(convert{}),
+				priceFloorsCfg: config.AccountPriceFloors{BidderAdjustments: map[string]float64{"pubmatic": 0.8, "appnexus": 1.25}},
+			},
+			expEligibleBids: map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid{
+				"pubmatic": {
+					Bids: []*entities.PbsOrtbBid{
+						{Bid: &openrtb2.Bid{ID: "some-bid-1", Price: 0.85, ImpID: "some-impression-id-1"}},
+					},
+					Seat:     "pubmatic",
+					Currency: "USD",
+				},
+				"appnexus": {
+					Bids:     []*entities.PbsOrtbBid{},
+					Seat:     "appnexus",
+					Currency: "USD",
+				},
+				"groupm": {
+					Bids: []*entities.PbsOrtbBid{
+						{Bid: &openrtb2.Bid{ID: "some-bid-21", Price: 0.95, ImpID: "some-impression-id-1"}, AdapterCode: "pubmatic"},
+					},
+					Seat:     "groupm",
+					Currency: "USD",
+				},
+			},
+			expRejectedBids: []*entities.PbsOrtbSeatBid{
+				{
+					Seat:     "appnexus",
+					Currency: "USD",
+					Bids: []*entities.PbsOrtbBid{
+						{Bid: &openrtb2.Bid{ID: "some-bid-11", Price: 1.1, ImpID: "some-impression-id-1"}},
+					},
+				},
+			},
+			expErrs: []error{},
+		},
+		{
+			name: "Bids under an alternate bidder code enforced against the floors signaled to the bidder",
+			args: args{
+				bidRequestWrapper: func() *openrtb_ext.RequestWrapper {
+					bw := openrtb_ext.RequestWrapper{
+						BidRequest: &openrtb2.BidRequest{
+							ID: "some-request-id",
+							Imp: []openrtb2.Imp{
+								{ID: "some-impression-id-1", BidFloor: 1.0, BidFloorCur: "USD"},
+							},
+						},
+					}
+					bw.RebuildRequest()
+					return &bw
+				}(),
+				seatBids: map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid{
+					"groupm": {
+						Bids: []*entities.PbsOrtbBid{
+							{Bid: &openrtb2.Bid{ID: "some-bid-21", Price: 0.9, ImpID: "some-impression-id-1"}, AdapterCode: "pubmatic", BidderName: "pubmatic"},
+							{Bid: &openrtb2.Bid{ID: "some-bid-22", Price: 0.7, ImpID: "some-impression-id-1"}, AdapterCode: "pubmatic", BidderName: "pubmatic"},
+						},
+						Seat:     "groupm",
+						Currency: "USD",
+					},
+				},
+				conversions:    currency.Conversions(convert{}),
+				priceFloorsCfg: config.AccountPriceFloors{BidderAdjustments: map[string]float64{"pubmatic": 0.8, "groupm": 1.25}},
+			},
+			expEligibleBids: map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid{
+				"groupm": {
+					Bids: []*entities.PbsOrtbBid{
+						{Bid: &openrtb2.Bid{ID: "some-bid-21", Price: 0.9, ImpID: "some-impression-id-1"}, AdapterCode: "pubmatic", BidderName: "pubmatic"},
+					},
+					Seat:     "groupm",
+					Currency: "USD",
+				},
+			},
+			expRejectedBids: []*entities.PbsOrtbSeatBid{
+				{
+					Seat:     "groupm",
+					Currency: "USD",
+					Bids: []*entities.PbsOrtbBid{
+						{Bid: &openrtb2.Bid{ID: "some-bid-22", Price: 0.7, ImpID: "some-impression-id-1"}, AdapterCode: "pubmatic", BidderName: "pubmatic"},
+					},
+				},
+			},
+			expErrs: []error{},
+		},
 	}
 	for _, tt := range tests {
-		seatbids, errs, rejBids := enforceFloorToBids(tt.args.bidRequestWrapper, tt.args.seatBids, tt.args.conversions, tt.args.enforceDealFloors)
+		seatbids, errs, rejBids := enforceFloorToBids(tt.args.bidRequestWrapper, tt.args.seatBids, tt.args.conversions, tt.args.enforceDealFloors, tt.args.priceFloorsCfg)
 		assert.Equal(t, tt.expEligibleBids, seatbids, tt.name)
 		assert.Equal(t, tt.expErrs, errs, tt.name)
 		assert.Equal(t, tt.expRejectedBids, rejBids, tt.name)
@@ -788,6 +910,39 @@ func TestUpdateBidExtWithFloors(t *testing.T) {
 	}
 }
 
+func TestUpdateBidExtWithBidderFloor(t *testing.T) {
+	tests := []struct {
+		name             string
+		bidFloors        *openrtb_ext.ExtBidPrebidFloors
+		bidderAdjustment float64
+		expBidFloors     *openrtb_ext.ExtBidPrebidFloors
+	}{
+		{
+			name:             "no_floors",
+			bidderAdjustment: 1.2,
+		},
+		{
+			name:             "no_bidder_adjustment",
+			bidFloors:        &openrtb_ext.ExtBidPrebidFloors{FloorValue: 1.5, FloorCurrency: "USD"},
+			bidderAdjustment: 1.0,
+			expBidFloors:     &openrtb_ext.ExtBidPrebidFloors{FloorValue: 1.5, FloorCurrency: "USD"},
+		},
+		{
+			name:             "bidder_adjustment",
+			bidFloors:        &openrtb_ext.ExtBidPrebidFloors{FloorValue: 1.5, FloorCurrency: "USD"},
+			bidderAdjustment: 1.1,
+			expBidFloors:     &openrtb_ext.ExtBidPrebidFloors{FloorValue: 1.5, FloorCurrency: "USD", BidderFloorValue: 1.65},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bid := &entities.PbsOrtbBid{BidFloors: tt.bidFloors}
+			updateBidExtWithBidderFloor(bid, tt.bidderAdjustment)
+			assert.Equal(t, tt.expBidFloors, bid.BidFloors)
+		})
+	}
+}
+
 func TestIsEnforcementEnabledForRequest(t *testing.T) {
 	tests := []struct {
 		name   string
@@ -5,8 +5,12 @@ import (
 	"math/bits"
 	"regexp"
 	"sort"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 
+	"github.com/prebid/openrtb/v20/adcom1"
 	"github.com/prebid/openrtb/v20/openrtb2"
 	"github.com/prebid/prebid-server/v4/currency"
 	"github.com/prebid/prebid-server/v4/logger"
@@ -26,6 +30,16 @@ const (
 	AdUnitCode          string = "adUnitCode"
 	Country             string = "country"
 	DeviceType          string = "deviceType"
+	HourOfDay           string = "hourOfDay"
+	OS                  string = "os"
+	Browser             string = "browser"
+	ConnectionType      string = "connectionType"
+	AppStoreURL         string = "appStoreUrl"
+	EIDsPresent         string = "eidsPresent"
+	DealPresent         string = "dealPresent"
+	VideoPlacement      string = "videoPlacement"
+	VideoPlcmt          string = "videoPlcmt"
+	ImpPosition         string = "impPosition"
 	Tablet              string = "tablet"
 	Desktop             string = "desktop"
 	Phone               string = "phone"
@@ -36,6 +50,53 @@ const (
 	NativeMedia         string = "native"
 )
 
+var (
+	mobileDevicePattern = regexp.MustCompile("(?i)Phone|iPhone|Android.*Mobile|Mobile.*Android")
+	tabletDevicePattern = regexp.MustCompile("(?i)tablet|iPad|touch.*Windows NT|Windows NT.*touch|Android")
+)
+
+type userAgentPattern struct {
+	name    string
+	pattern *regexp.Regexp
+}
+
+// userAgentBrowsers are checked in order, as most browsers also name the browsers they are based on
+var userAgentBrowsers = []userAgentPattern{
+	{name: "edge", pattern: regexp.MustCompile("Edg(e|A|iOS)?/")},
+	{name: "opera", pattern: regexp.MustCompile("OPR/|Opera")},
+	{name: "samsung", pattern: regexp.MustCompile("SamsungBrowser/")},
+	{name: "chrome", pattern: regexp.MustCompile("Chrome/|CriOS/")},
+	{name: "firefox", pattern: regexp.MustCompile("Firefox/|FxiOS/")},
+	{name: "safari", pattern: regexp.MustCompile("Safari/")},
+	{name: "ie", pattern: regexp.MustCompile("MSIE |Trident/")},
+}
+
+// userAgentOS are checked in order, as the Android user agents also name Linux
+var userAgentOS = []userAgentPattern{
+	{name: "ios", pattern: regexp.MustCompile("iPhone|iPad|iPod")},
+	{name: "android", pattern: regexp.MustCompile("Android")},
+	{name: "windows", pattern: regexp.MustCompile("Windows")},
+	{name: "macos", pattern: regexp.MustCompile("Mac OS X|Macintosh")},
+	{name: "linux", pattern: regexp.MustCompile("Linux")},
+}
+
+var connectionTypeNames = map[adcom1.ConnectionType]string{
+	adcom1.ConnectionEthernet: "ethernet",
+	adcom1.ConnectionWIFI:     "wifi",
+	adcom1.ConnectionCellular: "cellular",
+	adcom1.Connection2G:       "2g",
+	adcom1.Connection3G:       "3g",
+	adcom1.Connection4G:       "4g",
+	adcom1.Connection5G:       "5g",
+}
+
+var (
+	// locations caches the schema timezones by name
+	locations sync.Map
+	// wildcardCombinations caches the sorted wildcard combinations by number of schema fields
+	wildcardCombinations sync.Map
+)
+
 // getFloorCurrency returns floors currency provided in floors JSON,
 // if currency is not provided then defaults to USD
 func getFloorCurrency(floorExt *openrtb_ext.PriceFloorRules) string {
@@ -181,11 +242,29 @@ func shouldSkipFloors(ModelGroupsSkipRate, DataSkipRate, RootSkipRate int, f fun
 
 // findRule prepares rule combinations based on schema dimensions provided in floors data, request values associated with these fields and
 // does matching with rules provided in floors data and returns matched rule
+// The rule keys are checked in the same order as prepareRuleCombinations, but they are built one at a time so that
+// the lookup stops at the first match.
 func findRule(ruleValues map[string]float64, delimiter string, desiredRuleKey []string) (string, bool) {
-	ruleKeys := prepareRuleCombinations(desiredRuleKey, delimiter)
-	for i := 0; i < len(ruleKeys); i++ {
-		if _, ok := ruleValues[ruleKeys[i]]; ok {
-			return ruleKeys[i], true
+	numSchemaFields := len(desiredRuleKey)
+	schemaFields := make([]string, numSchemaFields)
+	for i := range desiredRuleKey {
+		schemaFields[i] = strings.ToLower(desiredRuleKey[i])
+	}
+
+	ruleKey := strings.Join(schemaFields, delimiter)
+	if _, ok := ruleValues[ruleKey]; ok {
+		return ruleKey, true
+	}
+
+	eachSet := make([]string, numSchemaFields)
+	for _, comb := range getWildcardCombinations(numSchemaFields) {
+		copy(eachSet, schemaFields)
+		for _, position := range comb {
+			eachSet[position] = catchAll
+		}
+		ruleKey = strings.Join(eachSet, delimiter)
+		if _, ok := ruleValues[ruleKey]; ok {
+			return ruleKey, true
 		}
 	}
 	return "", false
@@ -220,6 +299,26 @@ func createRuleKey(floorSchema openrtb_ext.PriceFloorSchema, request *openrtb_ex
 			value = getGptSlot(imp)
 		case AdUnitCode:
 			value = getAdUnitCode(imp)
+		case HourOfDay:
+			value = getHourOfDay(time.Now(), floorSchema.TimeZone)
+		case OS:
+			value = getOS(request)
+		case Browser:
+			value = getBrowser(request)
+		case ConnectionType:
+			value = getConnectionType(request)
+		case AppStoreURL:
+			value = getAppStoreURL(request)
+		case EIDsPresent:
+			value = getEIDsPresent(request)
+		case DealPresent:
+			value = getDealPresent(imp.Imp)
+		case VideoPlacement:
+			value = getVideoPlacement(imp.Imp)
+		case VideoPlcmt:
+			value = getVideoPlcmt(imp.Imp)
+		case ImpPosition:
+			value = getImpPosition(imp.Imp)
 		}
 		ruleKeys = append(ruleKeys, value)
 	}
@@ -429,22 +528,126 @@ func getAdUnitCode(imp *openrtb_ext.ImpWrapper) string {
 	return adUnitCode
 }
 
-// isMobileDevice returns true if device is mobile
-func isMobileDevice(userAgent string) bool {
-	isMobile, err := regexp.MatchString("(?i)Phone|iPhone|Android.*Mobile|Mobile.*Android", userAgent)
+// getHourOfDay returns the hour of now, from 0 to 23, in the timezone of the schema or in UTC
+func getHourOfDay(now time.Time, timeZone string) string {
+	location, err := loadLocation(timeZone)
 	if err != nil {
-		return false
+		return catchAll
 	}
-	return isMobile
+	return strconv.Itoa(now.In(location).Hour())
 }
 
-// isTabletDevice returns true if device is tablet
-func isTabletDevice(userAgent string) bool {
-	isTablet, err := regexp.MatchString("(?i)tablet|iPad|touch.*Windows NT|Windows NT.*touch|Android", userAgent)
+// getOS returns device os provided into request, or the os found in the user agent
+func getOS(request *openrtb_ext.RequestWrapper) string {
+	if request.Device == nil {
+		return catchAll
+	}
+	if len(request.Device.OS) > 0 {
+		return request.Device.OS
+	}
+	for _, os := range userAgentOS {
+		if os.pattern.MatchString(request.Device.UA) {
+			return os.name
+		}
+	}
+	return catchAll
+}
+
+// getBrowser returns the browser found in the device user agent
+func getBrowser(request *openrtb_ext.RequestWrapper) string {
+	if request.Device == nil || len(request.Device.UA) == 0 {
+		return catchAll
+	}
+	for _, browser := range userAgentBrowsers {
+		if browser.pattern.MatchString(request.Device.UA) {
+			return browser.name
+		}
+	}
+	return catchAll
+}
+
+// getConnectionType returns the name of device connection type provided into request
+func getConnectionType(request *openrtb_ext.RequestWrapper) string {
+	if request.Device == nil || request.Device.ConnectionType == nil {
+		return catchAll
+	}
+	if name, ok := connectionTypeNames[*request.Device.ConnectionType]; ok {
+		return name
+	}
+	return catchAll
+}
+
+// getAppStoreURL returns app store url provided into app object
+func getAppStoreURL(request *openrtb_ext.RequestWrapper) string {
+	value := catchAll
+	if request.App != nil && len(request.App.StoreURL) > 0 {
+		value = request.App.StoreURL
+	}
+	return value
+}
+
+// getEIDsPresent returns whether user eids are provided into request
+func getEIDsPresent(request *openrtb_ext.RequestWrapper) string {
+	return strconv.FormatBool(request.User != nil && len(request.User.EIDs) > 0)
+}
+
+// getDealPresent returns whether deals are provided into impression
+func getDealPresent(imp *openrtb2.Imp) string {
+	return strconv.FormatBool(imp.PMP != nil && len(imp.PMP.Deals) > 0)
+}
+
+// getVideoPlacement returns video placement provided into impression
+func getVideoPlacement(imp *openrtb2.Imp) string {
+	if imp.Video == nil || imp.Video.Placement == 0 {
+		return catchAll
+	}
+	return strconv.Itoa(int(imp.Video.Placement))
+}
+
+// getVideoPlcmt returns video plcmt provided into impression
+func getVideoPlcmt(imp *openrtb2.Imp) string {
+	if imp.Video == nil || imp.Video.Plcmt == 0 {
+		return catchAll
+	}
+	return strconv.Itoa(int(imp.Video.Plcmt))
+}
+
+// getImpPosition returns ad position provided into banner or video impression
+func getImpPosition(imp *openrtb2.Imp) string {
+	if imp.Banner != nil && imp.Banner.Pos != nil {
+		return strconv.Itoa(int(*imp.Banner.Pos))
+	}
+	if imp.Video != nil && imp.Video.Pos != nil {
+		return strconv.Itoa(int(*imp.Video.Pos))
+	}
+	return catchAll
+}
+
+// loadLocation returns the location with the given IANA name, or UTC if the name is empty.
+// The locations are cached, as time.LoadLocation reads the timezone database on every call.
+func loadLocation(name string) (*time.Location, error) {
+	if len(name) == 0 {
+		return time.UTC, nil
+	}
+	if location, ok := locations.Load(name); ok {
+		return location.(*time.Location), nil
+	}
+	location, err := time.LoadLocation(name)
 	if err != nil {
-		return false
+		return nil, err
 	}
-	return isTablet
+	locations.Store(name, location)
+	return location, nil
+}
+
+// isMobileDevice returns true if device is mobile
+func isMobileDevice(userAgent string) bool {
+	return mobileDevicePattern.MatchString(userAgent)
+}
+
+// isTabletDevice returns true if device is tablet
+func isTabletDevice(userAgent string) bool {
+	return tabletDevicePattern.MatchString(userAgent)
 }
 
 // prepareRuleCombinations prepares rule combinations based on schema dimensions and request fields
@@ -458,22 +661,34 @@ func prepareRuleCombinations(keys []string, delimiter string) []string {
 	}
 	ruleKey.appendRuleKey(schemaFields)
 
-	for numWildCard := 1; numWildCard <= numSchemaFields; numWildCard++ {
-		newComb := generateCombinations(numSchemaFields, numWildCard)
-		sortCombinations(newComb, numSchemaFields)
-
-		for i := 0; i < len(newComb); i++ {
-			eachSet := make([]string, numSchemaFields)
-			copy(eachSet, schemaFields)
-			for j := 0; j < len(newComb[i]); j++ {
-				eachSet[newComb[i][j]] = catchAll
-			}
-			ruleKey.appendRuleKey(eachSet)
+	for _, comb := range getWildcardCombinations(numSchemaFields) {
+		eachSet := make([]string, numSchemaFields)
+		copy(eachSet, schemaFields)
+		for j := 0; j < len(comb); j++ {
+			eachSet[comb[j]] = catchAll
 		}
+		ruleKey.appendRuleKey(eachSet)
 	}
 	return ruleKey.getAllRuleKeys()
 }
 
+// getWildcardCombinations returns the wildcard positions of every rule combination for the given number of fields,
+// from the most specific to the least specific. They only depend on the number of fields, so they are computed once.
+func getWildcardCombinations(numSchemaFields int) [][]int {
+	if combinations, ok := wildcardCombinations.Load(numSchemaFields); ok {
+		return combinations.([][]int)
+	}
+
+	var combinations [][]int
+	for numWildCard := 1; numWildCard <= numSchemaFields; numWildCard++ {
+		newComb := generateCombinations(numSchemaFields, numWildCard)
+		sortCombinations(newComb)
+		combinations = append(combinations, newComb...)
+	}
+	wildcardCombinations.Store(numSchemaFields, combinations)
+	return combinations
+}
+
 // generateCombinations generates every permutation for the given number of fields with the specified number of
 // wildcards. Permutations are returned as a list of integer lists where each integer list represents a single
 // permutation with each integer indicating the position of the fields that are wildcards
@@ -498,20 +713,16 @@ func generateCombinations(numSchemaFields int, numWildCard int) (comb [][]int) {
 // sortCombinations sorts the list of combinations from most specific to least specific. A combination is considered more specific than
 // another combination if it has more exact values (less wildcards). If two combinations have the same number of wildcards, a combination
 // is considered more specific than another if its left-most fields are more exact.
-func sortCombinations(comb [][]int, numSchemaFields int) {
-	totalComb := 1 << numSchemaFields
-
+func sortCombinations(comb [][]int) {
 	sort.SliceStable(comb, func(i, j int) bool {
-		wt1 := 0
-		for k := 0; k < len(comb[i]); k++ {
-			wt1 += 1 << (totalComb - comb[i][k])
-		}
-
-		wt2 := 0
-		for k := 0; k < len(comb[j]); k++ {
-			wt2 += 1 << (totalComb - comb[j][k])
+		// the wildcard positions are in increasing order, so the first different position tells which one
+		// has its left-most fields more exact
+		for k := 0; k < len(comb[i]) && k < len(comb[j]); k++ {
+			if comb[i][k] != comb[j][k] {
+				return comb[i][k] > comb[j][k]
+			}
 		}
-		return wt1 < wt2
+		return len(comb[i]) < len(comb[j])
 	})
 }
 
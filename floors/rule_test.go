@@ -3,8 +3,12 @@ package floors
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/prebid/openrtb/v20/adcom1"
 	"github.com/prebid/openrtb/v20/openrtb2"
 	"github.com/prebid/prebid-server/v4/currency"
 	"github.com/prebid/prebid-server/v4/openrtb_ext"
@@ -504,6 +508,32 @@ func TestCreateRuleKeys(t *testing.T) {
 			floorSchema: openrtb_ext.PriceFloorSchema{Delimiter: "|", Fields: []string{"domain", "adUnitCode", "channel"}},
 			out:         []string{"www.test.com", "storedid_123", "*"},
 		},
+		{
+			name: "CreateRule with os, browser, connectionType, appStoreUrl and eidsPresent",
+			request: &openrtb2.BidRequest{
+				App: &openrtb2.App{StoreURL: "https://apps.apple.com/app/id123"},
+				Device: &openrtb2.Device{
+					UA:             "Mozilla/5.0 (iPhone; CPU iPhone OS 17_0 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) CriOS/120.0.0.0 Mobile/15E148 Safari/604.1",
+					ConnectionType: ptrutil.ToPtr(adcom1.Connection4G),
+				},
+				User: &openrtb2.User{EIDs: []openrtb2.EID{{Source: "id5-sync.com"}}},
+				Imp:  []openrtb2.Imp{{ID: "1234", Banner: &openrtb2.Banner{}}},
+			},
+			floorSchema: openrtb_ext.PriceFloorSchema{Delimiter: "|", Fields: []string{"os", "browser", "connectionType", "appStoreUrl", "eidsPresent"}},
+			out:         []string{"ios", "chrome", "4g", "https://apps.apple.com/app/id123", "true"},
+		},
+		{
+			name: "CreateRule with dealPresent, videoPlacement, videoPlcmt and impPosition",
+			request: &openrtb2.BidRequest{
+				Imp: []openrtb2.Imp{{
+					ID:    "1234",
+					Video: &openrtb2.Video{Placement: adcom1.VideoPlacementInArticle, Plcmt: adcom1.VideoPlcmtAccompanyingContent, Pos: ptrutil.ToPtr(adcom1.PositionAboveFold)},
+					PMP:   &openrtb2.PMP{Deals: []openrtb2.Deal{{ID: "deal1"}}},
+				}},
+			},
+			floorSchema: openrtb_ext.PriceFloorSchema{Delimiter: "|", Fields: []string{"dealPresent", "videoPlacement", "videoPlcmt", "impPosition", "eidsPresent"}},
+			out:         []string{"true", "3", "2", "1", "false"},
+		},
 	}
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
@@ -775,44 +805,36 @@ func TestGetMinFloorValue(t *testing.T) {
 }
 
 func TestSortCombinations(t *testing.T) {
-	type args struct {
-		comb            [][]int
-		numSchemaFields int
-	}
 	tests := []struct {
 		name    string
-		args    args
+		comb    [][]int
 		expComb [][]int
 	}{
 		{
-			name: "With schema fields = 3",
-			args: args{
-				comb:            [][]int{{0}, {1}, {2}},
-				numSchemaFields: 3,
-			},
+			name:    "With schema fields = 3",
+			comb:    [][]int{{0}, {1}, {2}},
 			expComb: [][]int{{2}, {1}, {0}},
 		},
 		{
-			name: "With schema fields = 3",
-			args: args{
-				comb:            [][]int{{0, 1}, {1, 2}, {0, 2}},
-				numSchemaFields: 3,
-			},
+			name:    "With schema fields = 3",
+			comb:    [][]int{{0, 1}, {1, 2}, {0, 2}},
 			expComb: [][]int{{1, 2}, {0, 2}, {0, 1}},
 		},
 		{
-			name: "With schema fields = 4",
-			args: args{
-				comb:            [][]int{{0, 1, 2}, {1, 2, 3}, {0, 2, 3}, {0, 1, 3}},
-				numSchemaFields: 3,
-			},
+			name:    "With schema fields = 4",
+			comb:    [][]int{{0, 1, 2}, {1, 2, 3}, {0, 2, 3}, {0, 1, 3}},
 			expComb: [][]int{{1, 2, 3}, {0, 2, 3}, {0, 1, 3}, {0, 1, 2}},
 		},
+		{
+			name:    "With schema fields = 8",
+			comb:    [][]int{{0, 7}, {1, 7}, {6, 7}, {0, 1}},
+			expComb: [][]int{{6, 7}, {1, 7}, {0, 7}, {0, 1}},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			sortCombinations(tt.args.comb, tt.args.numSchemaFields)
-			assert.Equal(t, tt.expComb, tt.args.comb)
+			sortCombinations(tt.comb)
+			assert.Equal(t, tt.expComb, tt.comb)
 		})
 	}
 }
@@ -1218,3 +1240,285 @@ func getIntPtr(v int) *int {
 func getInt64Ptr(v int64) *int64 {
 	return &v
 }
+
+func TestGetHourOfDay(t *testing.T) {
+	now := time.Date(2024, 6, 1, 23, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		timeZone string
+		want     string
+	}{
+		{
+			name:     "default_utc",
+			timeZone: "",
+			want:     "23",
+		},
+		{
+			name:     "timezone_ahead_of_utc",
+			timeZone: "Europe/Paris",
+			want:     "1",
+		},
+		{
+			name:     "timezone_behind_utc",
+			timeZone: "America/New_York",
+			want:     "19",
+		},
+		{
+			name:     "invalid_timezone",
+			timeZone: "Invalid/TimeZone",
+			want:     "*",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, getHourOfDay(now, tt.timeZone))
+		})
+	}
+}
+
+func TestGetOS(t *testing.T) {
+	tests := []struct {
+		name    string
+		request *openrtb2.BidRequest
+		want    string
+	}{
+		{
+			name:    "no_device",
+			request: &openrtb2.BidRequest{},
+			want:    "*",
+		},
+		{
+			name:    "device_os",
+			request: &openrtb2.BidRequest{Device: &openrtb2.Device{OS: "Android", UA: "Mozilla/5.0 (Windows NT 10.0; Win64; x64)"}},
+			want:    "Android",
+		},
+		{
+			name:    "user_agent_ios",
+			request: &openrtb2.BidRequest{Device: &openrtb2.Device{UA: "Mozilla/5.0 (iPad; CPU OS 17_0 like Mac OS X)"}},
+			want:    "ios",
+		},
+		{
+			name:    "user_agent_android",
+			request: &openrtb2.BidRequest{Device: &openrtb2.Device{UA: "Mozilla/5.0 (Linux; Android 14; Pixel 8)"}},
+			want:    "android",
+		},
+		{
+			name:    "user_agent_windows",
+			request: &openrtb2.BidRequest{Device: &openrtb2.Device{UA: "Mozilla/5.0 (Windows NT 10.0; Win64; x64)"}},
+			want:    "windows",
+		},
+		{
+			name:    "user_agent_macos",
+			request: &openrtb2.BidRequest{Device: &openrtb2.Device{UA: "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7)"}},
+			want:    "macos",
+		},
+		{
+			name:    "user_agent_linux",
+			request: &openrtb2.BidRequest{Device: &openrtb2.Device{UA: "Mozilla/5.0 (X11; Linux x86_64)"}},
+			want:    "linux",
+		},
+		{
+			name:    "user_agent_unknown",
+			request: &openrtb2.BidRequest{Device: &openrtb2.Device{UA: "curl/8.0"}},
+			want:    "*",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, getOS(&openrtb_ext.RequestWrapper{BidRequest: tt.request}))
+		})
+	}
+}
+
+func TestGetBrowser(t *testing.T) {
+	tests := []struct {
+		name string
+		ua   string
+		want string
+	}{
+		{
+			name: "edge",
+			ua:   "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36 Edg/120.0.0.0",
+			want: "edge",
+		},
+		{
+			name: "opera",
+			ua:   "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36 OPR/106.0.0.0",
+			want: "opera",
+		},
+		{
+			name: "samsung",
+			ua:   "Mozilla/5.0 (Linux; Android 14; SM-S918B) AppleWebKit/537.36 (KHTML, like Gecko) SamsungBrowser/23.0 Chrome/115.0.0.0 Mobile Safari/537.36",
+			want: "samsung",
+		},
+		{
+			name: "chrome",
+			ua:   "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
+			want: "chrome",
+		},
+		{
+			name: "firefox",
+			ua:   "Mozilla/5.0 (Windows NT 10.0; Win64; x64; rv:121.0) Gecko/20100101 Firefox/121.0",
+			want: "firefox",
+		},
+		{
+			name: "safari",
+			ua:   "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.1 Safari/605.1.15",
+			want: "safari",
+		},
+		{
+			name: "ie",
+			ua:   "Mozilla/5.0 (Windows NT 10.0; WOW64; Trident/7.0; rv:11.0) like Gecko",
+			want: "ie",
+		},
+		{
+			name: "unknown",
+			ua:   "curl/8.0",
+			want: "*",
+		},
+		{
+			name: "empty",
+			ua:   "",
+			want: "*",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{Device: &openrtb2.Device{UA: tt.ua}}}
+			assert.Equal(t, tt.want, getBrowser(request))
+		})
+	}
+}
+
+func TestGetConnectionType(t *testing.T) {
+	tests := []struct {
+		name           string
+		connectionType *adcom1.ConnectionType
+		want           string
+	}{
+		{
+			name: "not_provided",
+			want: "*",
+		},
+		{
+			name:           "unknown",
+			connectionType: ptrutil.ToPtr(adcom1.ConnectionUnknown),
+			want:           "*",
+		},
+		{
+			name:           "wifi",
+			connectionType: ptrutil.ToPtr(adcom1.ConnectionWIFI),
+			want:           "wifi",
+		},
+		{
+			name:           "5g",
+			connectionType: ptrutil.ToPtr(adcom1.Connection5G),
+			want:           "5g",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{Device: &openrtb2.Device{ConnectionType: tt.connectionType}}}
+			assert.Equal(t, tt.want, getConnectionType(request))
+		})
+	}
+}
+
+func TestGetImpPosition(t *testing.T) {
+	tests := []struct {
+		name string
+		imp  *openrtb2.Imp
+		want string
+	}{
+		{
+			name: "banner_position",
+			imp:  &openrtb2.Imp{Banner: &openrtb2.Banner{Pos: ptrutil.ToPtr(adcom1.PositionBelowFold)}, Video: &openrtb2.Video{Pos: ptrutil.ToPtr(adcom1.PositionAboveFold)}},
+			want: "3",
+		},
+		{
+			name: "video_position",
+			imp:  &openrtb2.Imp{Banner: &openrtb2.Banner{}, Video: &openrtb2.Video{Pos: ptrutil.ToPtr(adcom1.PositionAboveFold)}},
+			want: "1",
+		},
+		{
+			name: "no_position",
+			imp:  &openrtb2.Imp{Native: &openrtb2.Native{}},
+			want: "*",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, getImpPosition(tt.imp))
+		})
+	}
+}
+
+func TestFindRuleMatchesPrepareRuleCombinationsOrder(t *testing.T) {
+	desiredRuleKey := []string{"Banner", "300x250", "www.test.com", "US", "chrome", "true"}
+	ruleKeys := prepareRuleCombinations(desiredRuleKey, "|")
+
+	// each rule key should be found when it's the most specific of the rules
+	for i := range ruleKeys {
+		ruleValues := make(map[string]float64, len(ruleKeys)-i)
+		for _, ruleKey := range ruleKeys[i:] {
+			ruleValues[ruleKey] = 1
+		}
+		matchedRule, found := findRule(ruleValues, "|", desiredRuleKey)
+		assert.True(t, found)
+		assert.Equal(t, ruleKeys[i], matchedRule)
+	}
+
+	_, found := findRule(map[string]float64{"video|*|*|*|*|*": 1}, "|", desiredRuleKey)
+	assert.False(t, found)
+}
+
+func BenchmarkFindRule(b *testing.B) {
+	for _, numSchemaFields := range []int{3, 6, 9} {
+		for _, numRules := range []int{1000, 100000} {
+			ruleValues := make(map[string]float64, numRules)
+			for i := 0; i < numRules; i++ {
+				ruleKey := make([]string, numSchemaFields)
+				for j := range ruleKey {
+					ruleKey[j] = fmt.Sprintf("value%d", (i>>j)%50)
+				}
+				ruleValues[strings.Join(ruleKey, "|")] = 1
+			}
+			ruleValues[strings.Repeat("*|", numSchemaFields-1)+"*"] = 1
+
+			// the request values don't match any rule, so that every combination is looked up before the catch-all rule
+			desiredRuleKey := make([]string, numSchemaFields)
+			for j := range desiredRuleKey {
+				desiredRuleKey[j] = "unknown"
+			}
+
+			b.Run(fmt.Sprintf("fields_%d_rules_%d", numSchemaFields, numRules), func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					findRule(ruleValues, "|", desiredRuleKey)
+				}
+			})
+		}
+	}
+}
+
+func BenchmarkCreateRuleKey(b *testing.B) {
+	floorSchema := openrtb_ext.PriceFloorSchema{
+		Delimiter: "|",
+		TimeZone:  "America/New_York",
+		Fields:    []string{"mediaType", "size", "deviceType", "hourOfDay", "os", "browser", "connectionType", "eidsPresent", "dealPresent", "impPosition"},
+	}
+	request := &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{
+		Site: &openrtb2.Site{Domain: "www.test.com"},
+		Device: &openrtb2.Device{
+			UA:             "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
+			ConnectionType: ptrutil.ToPtr(adcom1.ConnectionWIFI),
+		},
+		User: &openrtb2.User{EIDs: []openrtb2.EID{{Source: "id5-sync.com"}}},
+		Imp:  []openrtb2.Imp{{ID: "1234", Banner: &openrtb2.Banner{Format: []openrtb2.Format{{W: 300, H: 250}}, Pos: ptrutil.ToPtr(adcom1.PositionAboveFold)}}},
+	}}
+	imp := &openrtb_ext.ImpWrapper{Imp: &request.Imp[0]}
+
+	for i := 0; i < b.N; i++ {
+		createRuleKey(floorSchema, request, imp)
+	}
+}
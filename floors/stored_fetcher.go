@@ -0,0 +1,164 @@
+package floors
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/prebid/prebid-server/v4/config"
+	"github.com/prebid/prebid-server/v4/logger"
+	"github.com/prebid/prebid-server/v4/openrtb_ext"
+	"github.com/prebid/prebid-server/v4/stored_requests"
+)
+
+// storedFloorsRefreshInterval is the minimum time between two loads of the same stored floors, so that the
+// updates and invalidations of the stored data are picked up shortly after they happen.
+const storedFloorsRefreshInterval = 30 * time.Second
+
+// StoredFloorFetcher loads the floor rule sets of the accounts with a fetch.stored_id through the stored floors
+// fetcher, which shares the backends, caches and invalidation events of the other stored data.
+// The accounts without a fetch.stored_id are delegated to the URL based FloorFetcher.
+//
+// Like the URL based FloorFetcher, it never blocks the auctions: the floors are loaded in the background, and
+// the auctions use the floors loaded last, or none while the first load is in progress.
+type StoredFloorFetcher struct {
+	storedFetcher stored_requests.FloorsFetcher
+	urlFetcher    FloorFetcher
+	now           func() time.Time
+
+	ctx     context.Context
+	cancel  context.CancelFunc
+	loads   sync.WaitGroup
+	mutex   sync.Mutex
+	entries map[string]*storedFloorsEntry
+}
+
+// storedFloorsEntry holds the floors loaded last for a stored ID.
+type storedFloorsEntry struct {
+	floors   *openrtb_ext.PriceFloorRules
+	loadedAt time.Time
+	// status is the outcome of the last load, used while no floors were loaded
+	status      string
+	attemptedAt time.Time
+	loading     bool
+}
+
+// NewStoredFloorFetcher returns a FloorFetcher which loads the stored floors with storedFetcher, and delegates
+// the fetches by URL to urlFetcher.
+func NewStoredFloorFetcher(config config.PriceFloors, storedFetcher stored_requests.FloorsFetcher, urlFetcher FloorFetcher) FloorFetcher {
+	if !config.Enabled || storedFetcher == nil {
+		return urlFetcher
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	return &StoredFloorFetcher{
+		storedFetcher: storedFetcher,
+		urlFetcher:    urlFetcher,
+		now:           time.Now,
+		ctx:           ctx,
+		cancel:        cancel,
+		entries:       make(map[string]*storedFloorsEntry),
+	}
+}
+
+func (f *StoredFloorFetcher) Fetch(config config.AccountPriceFloors) (*openrtb_ext.PriceFloorRules, string) {
+	if len(config.Fetcher.StoredID) == 0 {
+		if f.urlFetcher == nil {
+			return nil, openrtb_ext.FetchNone
+		}
+		return f.urlFetcher.Fetch(config)
+	}
+	if !config.UseDynamicData || !config.Enabled || !config.Fetcher.Enabled {
+		return nil, openrtb_ext.FetchNone
+	}
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	now := f.now()
+	entry, ok := f.entries[config.Fetcher.StoredID]
+	if !ok {
+		entry = &storedFloorsEntry{status: openrtb_ext.FetchInprogress}
+		f.entries[config.Fetcher.StoredID] = entry
+	}
+	if !entry.loading && now.Sub(entry.attemptedAt) >= storedFloorsRefreshInterval {
+		entry.loading = true
+		entry.attemptedAt = now
+		f.loads.Add(1)
+		go f.load(config.Fetcher, entry)
+	}
+
+	maxAge := time.Duration(config.Fetcher.MaxAge) * time.Second
+	if entry.floors != nil && (maxAge <= 0 || now.Sub(entry.loadedAt) < maxAge) {
+		// the auctions may modify the floors they use
+		return entry.floors.DeepCopy(), openrtb_ext.FetchSuccess
+	}
+	return nil, entry.status
+}
+
+// load loads the stored floors of the entry. The floors loaded previously are kept if the load fails.
+func (f *StoredFloorFetcher) load(config config.AccountFloorFetch, entry *storedFloorsEntry) {
+	defer f.loads.Done()
+
+	priceFloors, status := f.fetch(config)
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	entry.loading = false
+	entry.status = status
+	if priceFloors != nil {
+		entry.floors = priceFloors
+		entry.loadedAt = f.now()
+	}
+}
+
+func (f *StoredFloorFetcher) fetch(config config.AccountFloorFetch) (*openrtb_ext.PriceFloorRules, string) {
+	ctx := f.ctx
+	if config.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(config.Timeout)*time.Millisecond)
+		defer cancel()
+	}
+
+	data, errs := f.storedFetcher.FetchFloors(ctx, []string{config.StoredID})
+	if len(errs) > 0 {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil, openrtb_ext.FetchTimeout
+		}
+		logger.Errorf("Error while fetching stored floors with ID %s, reason : %v", config.StoredID, errors.Join(errs...))
+		return nil, openrtb_ext.FetchError
+	}
+
+	priceFloors, err := parseStoredFloors(config, data[config.StoredID])
+	if err != nil {
+		logger.Errorf("Validation failed for stored floors with ID %s, reason: %s", config.StoredID, err.Error())
+		return nil, openrtb_ext.FetchError
+	}
+	return priceFloors, openrtb_ext.FetchSuccess
+}
+
+func (f *StoredFloorFetcher) Stop() {
+	f.cancel()
+	f.loads.Wait()
+	if f.urlFetcher != nil {
+		f.urlFetcher.Stop()
+	}
+}
+
+// parseStoredFloors validates the stored floor data, which has the same format as the floors fetched from a URL.
+func parseStoredFloors(config config.AccountFloorFetch, data json.RawMessage) (*openrtb_ext.PriceFloorRules, error) {
+	if len(data) > (config.MaxFileSizeKB * 1024) {
+		return nil, errors.New("floor data size is greater than MaxFileSize")
+	}
+
+	var priceFloors openrtb_ext.PriceFloorRules
+	if err := json.Unmarshal(data, &priceFloors.Data); err != nil {
+		return nil, errors.New("invalid price floor json")
+	}
+
+	if err := validateRules(config, &priceFloors); err != nil {
+		return nil, err
+	}
+	return &priceFloors, nil
+}
@@ -0,0 +1,306 @@
+package floors
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prebid/prebid-server/v4/config"
+	"github.com/prebid/prebid-server/v4/openrtb_ext"
+	"github.com/prebid/prebid-server/v4/stored_requests"
+	"github.com/prebid/prebid-server/v4/util/ptrutil"
+	"github.com/stretchr/testify/assert"
+)
+
+const storedFloorsJSON = `{"currency":"USD","modelgroups":[{"modelversion":"stored","schema":{"fields":["mediaType"]},"values":{"banner":2}}]}`
+
+func TestStoredFloorFetcherFetch(t *testing.T) {
+	storedFetcher := fakeFloorsFetcher{
+		"floors1":  json.RawMessage(storedFloorsJSON),
+		"invalid":  json.RawMessage(`{"currency":"USD","modelgroups":[]}`),
+		"notjson":  json.RawMessage(`{`),
+		"oversize": json.RawMessage(`{"currency":"USD","modelgroups":[{"schema":{"fields":["mediaType"]},"values":{"banner":2}}],"padding":"` + strings.Repeat("a", 1024) + `"}`),
+	}
+	urlFetcher := &fakeURLFetcher{}
+
+	testCases := []struct {
+		name           string
+		storedID       string
+		expectedData   *openrtb_ext.PriceFloorRules
+		expectedStatus string
+	}{
+		{
+			name:     "found",
+			storedID: "floors1",
+			expectedData: &openrtb_ext.PriceFloorRules{
+				Data: &openrtb_ext.PriceFloorData{
+					Currency: "USD",
+					ModelGroups: []openrtb_ext.PriceFloorModelGroup{{
+						ModelVersion: "stored",
+						Schema:       openrtb_ext.PriceFloorSchema{Fields: []string{"mediaType"}},
+						Values:       map[string]float64{"banner": 2},
+					}},
+				},
+			},
+			expectedStatus: openrtb_ext.FetchSuccess,
+		},
+		{
+			name:           "not_found",
+			storedID:       "missing",
+			expectedStatus: openrtb_ext.FetchError,
+		},
+		{
+			name:           "invalid_rules",
+			storedID:       "invalid",
+			expectedStatus: openrtb_ext.FetchError,
+		},
+		{
+			name:           "invalid_json",
+			storedID:       "notjson",
+			expectedStatus: openrtb_ext.FetchError,
+		},
+		{
+			name:           "greater_than_max_file_size",
+			storedID:       "oversize",
+			expectedStatus: openrtb_ext.FetchError,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fetcher := NewStoredFloorFetcher(config.PriceFloors{Enabled: true}, storedFetcher, urlFetcher).(*StoredFloorFetcher)
+			floorsConfig := config.AccountPriceFloors{
+				Enabled:        true,
+				UseDynamicData: true,
+				Fetcher: config.AccountFloorFetch{
+					Enabled:       true,
+					StoredID:      tc.storedID,
+					Timeout:       100,
+					MaxFileSizeKB: 1,
+					MaxRules:      10,
+				},
+			}
+
+			data, status := fetcher.Fetch(floorsConfig)
+			assert.Nil(t, data, "The floors should be loaded in the background")
+			assert.Equal(t, openrtb_ext.FetchInprogress, status)
+
+			fetcher.loads.Wait()
+			data, status = fetcher.Fetch(floorsConfig)
+			assert.Equal(t, tc.expectedData, data)
+			assert.Equal(t, tc.expectedStatus, status)
+		})
+	}
+	assert.Zero(t, urlFetcher.calls, "The floors with a stored ID shouldn't be fetched by URL")
+}
+
+func TestStoredFloorFetcherRefresh(t *testing.T) {
+	storedFetcher := fakeFloorsFetcher{"floors1": json.RawMessage(storedFloorsJSON)}
+	fetcher := NewStoredFloorFetcher(config.PriceFloors{Enabled: true}, &storedFetcher, nil).(*StoredFloorFetcher)
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	fetcher.now = func() time.Time { return now }
+	floorsConfig := config.AccountPriceFloors{
+		Enabled:        true,
+		UseDynamicData: true,
+		Fetcher:        config.AccountFloorFetch{Enabled: true, StoredID: "floors1", MaxFileSizeKB: 1, MaxRules: 10, MaxAge: 600},
+	}
+
+	fetcher.Fetch(floorsConfig)
+	fetcher.loads.Wait()
+
+	// the floors are loaded again once the refresh interval is over, while the auctions use the loaded ones
+	storedFetcher["floors1"] = json.RawMessage(`{"modelgroups":[{"modelversion":"updated","schema":{"fields":["mediaType"]},"values":{"banner":3}}]}`)
+	now = now.Add(storedFloorsRefreshInterval - time.Second)
+	data, status := fetcher.Fetch(floorsConfig)
+	fetcher.loads.Wait()
+	assert.Equal(t, "stored", data.Data.ModelGroups[0].ModelVersion)
+	assert.Equal(t, openrtb_ext.FetchSuccess, status)
+
+	now = now.Add(time.Second)
+	data, _ = fetcher.Fetch(floorsConfig)
+	fetcher.loads.Wait()
+	assert.Equal(t, "stored", data.Data.ModelGroups[0].ModelVersion, "The floors loaded previously should be used during the refresh")
+	data, _ = fetcher.Fetch(floorsConfig)
+	assert.Equal(t, "updated", data.Data.ModelGroups[0].ModelVersion)
+
+	// the floors loaded last are kept when the refresh fails, until they're older than the max age
+	delete(storedFetcher, "floors1")
+	now = now.Add(storedFloorsRefreshInterval)
+	fetcher.Fetch(floorsConfig)
+	fetcher.loads.Wait()
+	data, status = fetcher.Fetch(floorsConfig)
+	assert.Equal(t, "updated", data.Data.ModelGroups[0].ModelVersion)
+	assert.Equal(t, openrtb_ext.FetchSuccess, status)
+
+	now = now.Add(600 * time.Second)
+	data, status = fetcher.Fetch(floorsConfig)
+	fetcher.loads.Wait()
+	assert.Nil(t, data)
+	assert.Equal(t, openrtb_ext.FetchError, status)
+}
+
+func TestStoredFloorFetcherReturnsCopies(t *testing.T) {
+	fetcher := NewStoredFloorFetcher(config.PriceFloors{Enabled: true}, fakeFloorsFetcher{"floors1": json.RawMessage(storedFloorsJSON)}, nil).(*StoredFloorFetcher)
+	floorsConfig := config.AccountPriceFloors{
+		Enabled:        true,
+		UseDynamicData: true,
+		Fetcher:        config.AccountFloorFetch{Enabled: true, StoredID: "floors1", MaxFileSizeKB: 1, MaxRules: 10},
+	}
+	fetcher.Fetch(floorsConfig)
+	fetcher.loads.Wait()
+
+	data, _ := fetcher.Fetch(floorsConfig)
+	data.Data.ModelGroups[0].Values["banner"] = 10
+
+	data, _ = fetcher.Fetch(floorsConfig)
+	assert.Equal(t, 2.0, data.Data.ModelGroups[0].Values["banner"])
+}
+
+func TestStoredFloorFetcherDisabled(t *testing.T) {
+	urlFetcher := &fakeURLFetcher{}
+	fetcher := NewStoredFloorFetcher(config.PriceFloors{Enabled: true}, fakeFloorsFetcher{"floors1": json.RawMessage(storedFloorsJSON)}, urlFetcher)
+
+	testCases := []struct {
+		name   string
+		config config.AccountPriceFloors
+	}{
+		{
+			name:   "dynamic_data_not_used",
+			config: config.AccountPriceFloors{Enabled: true, Fetcher: config.AccountFloorFetch{Enabled: true, StoredID: "floors1"}},
+		},
+		{
+			name:   "fetch_disabled",
+			config: config.AccountPriceFloors{Enabled: true, UseDynamicData: true, Fetcher: config.AccountFloorFetch{StoredID: "floors1"}},
+		},
+		{
+			name:   "floors_disabled",
+			config: config.AccountPriceFloors{UseDynamicData: true, Fetcher: config.AccountFloorFetch{Enabled: true, StoredID: "floors1"}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			data, status := fetcher.Fetch(tc.config)
+			assert.Nil(t, data)
+			assert.Equal(t, openrtb_ext.FetchNone, status)
+		})
+	}
+}
+
+func TestStoredFloorFetcherTimeout(t *testing.T) {
+	fetcher := NewStoredFloorFetcher(config.PriceFloors{Enabled: true}, blockingFloorsFetcher{}, nil).(*StoredFloorFetcher)
+	floorsConfig := config.AccountPriceFloors{
+		Enabled:        true,
+		UseDynamicData: true,
+		Fetcher:        config.AccountFloorFetch{Enabled: true, StoredID: "floors1", Timeout: 1},
+	}
+
+	start := time.Now()
+	data, status := fetcher.Fetch(floorsConfig)
+	assert.Less(t, time.Since(start), 500*time.Millisecond, "The auction shouldn't wait for the stored floors")
+	assert.Nil(t, data)
+	assert.Equal(t, openrtb_ext.FetchInprogress, status)
+
+	fetcher.loads.Wait()
+	data, status = fetcher.Fetch(floorsConfig)
+	assert.Nil(t, data)
+	assert.Equal(t, openrtb_ext.FetchTimeout, status)
+}
+
+func TestStoredFloorFetcherStopCancelsLoads(t *testing.T) {
+	fetcher := NewStoredFloorFetcher(config.PriceFloors{Enabled: true}, blockingFloorsFetcher{}, nil).(*StoredFloorFetcher)
+
+	fetcher.Fetch(config.AccountPriceFloors{
+		Enabled:        true,
+		UseDynamicData: true,
+		Fetcher:        config.AccountFloorFetch{Enabled: true, StoredID: "floors1"},
+	})
+
+	start := time.Now()
+	fetcher.Stop()
+	assert.Less(t, time.Since(start), 500*time.Millisecond, "Stop should cancel the loads in progress")
+}
+
+func TestStoredFloorFetcherDelegatesURL(t *testing.T) {
+	urlFetcher := &fakeURLFetcher{
+		data:   &openrtb_ext.PriceFloorRules{FloorMin: 1},
+		status: openrtb_ext.FetchSuccess,
+	}
+	fetcher := NewStoredFloorFetcher(config.PriceFloors{Enabled: true}, fakeFloorsFetcher{}, urlFetcher)
+
+	data, status := fetcher.Fetch(config.AccountPriceFloors{
+		UseDynamicData: true,
+		Fetcher:        config.AccountFloorFetch{URL: "https://floors.example.com"},
+	})
+
+	assert.Equal(t, urlFetcher.data, data)
+	assert.Equal(t, openrtb_ext.FetchSuccess, status)
+	assert.Equal(t, 1, urlFetcher.calls)
+
+	fetcher.Stop()
+	assert.True(t, urlFetcher.stopped, "Stop should stop the URL fetcher")
+}
+
+func TestNewStoredFloorFetcherDisabled(t *testing.T) {
+	urlFetcher := &fakeURLFetcher{}
+
+	fetcher := NewStoredFloorFetcher(config.PriceFloors{Enabled: false}, fakeFloorsFetcher{}, urlFetcher)
+
+	assert.Equal(t, urlFetcher, fetcher, "The URL fetcher should be used as is when the price floors are disabled")
+}
+
+func TestParseStoredFloorsUseFetchDataRate(t *testing.T) {
+	data := json.RawMessage(`{"usefetchdatarate":50,"modelgroups":[{"schema":{"fields":["mediaType"]},"values":{"banner":2}}]}`)
+
+	priceFloors, err := parseStoredFloors(config.AccountFloorFetch{MaxFileSizeKB: 1, MaxRules: 10}, data)
+
+	assert.NoError(t, err)
+	assert.Equal(t, ptrutil.ToPtr(50), priceFloors.Data.UseFetchDataRate)
+}
+
+// fakeFloorsFetcher returns the floors it holds, and NotFoundErrors for the other IDs.
+type fakeFloorsFetcher map[string]json.RawMessage
+
+func (f fakeFloorsFetcher) FetchFloors(ctx context.Context, ids []string) (map[string]json.RawMessage, []error) {
+	data := make(map[string]json.RawMessage, len(ids))
+	var errs []error
+	for _, id := range ids {
+		if floors, ok := f[id]; ok {
+			data[id] = floors
+		} else {
+			errs = append(errs, stored_requests.NotFoundError{ID: id, DataType: "Floors"})
+		}
+	}
+	return data, errs
+}
+
+// blockingFloorsFetcher waits until the context is done before returning its error.
+type blockingFloorsFetcher struct{}
+
+func (f blockingFloorsFetcher) FetchFloors(ctx context.Context, ids []string) (map[string]json.RawMessage, []error) {
+	select {
+	case <-ctx.Done():
+		return nil, []error{ctx.Err()}
+	case <-time.After(time.Second):
+		return nil, []error{errors.New("the context should have been done")}
+	}
+}
+
+type fakeURLFetcher struct {
+	data    *openrtb_ext.PriceFloorRules
+	status  string
+	calls   int
+	stopped bool
+}
+
+func (f *fakeURLFetcher) Fetch(configs config.AccountPriceFloors) (*openrtb_ext.PriceFloorRules, string) {
+	f.calls++
+	return f.data, f.status
+}
+
+func (f *fakeURLFetcher) Stop() {
+	f.stopped = true
+}
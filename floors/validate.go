@@ -9,17 +9,27 @@ import (
 )
 
 var validSchemaDimensions = map[string]struct{}{
-	SiteDomain: {},
-	PubDomain:  {},
-	Domain:     {},
-	Bundle:     {},
-	Channel:    {},
-	MediaType:  {},
-	Size:       {},
-	GptSlot:    {},
-	AdUnitCode: {},
-	Country:    {},
-	DeviceType: {},
+	SiteDomain:     {},
+	PubDomain:      {},
+	Domain:         {},
+	Bundle:         {},
+	Channel:        {},
+	MediaType:      {},
+	Size:           {},
+	GptSlot:        {},
+	AdUnitCode:     {},
+	Country:        {},
+	DeviceType:     {},
+	HourOfDay:      {},
+	OS:             {},
+	Browser:        {},
+	ConnectionType: {},
+	AppStoreURL:    {},
+	EIDsPresent:    {},
+	DealPresent:    {},
+	VideoPlacement: {},
+	VideoPlcmt:     {},
+	ImpPosition:    {},
 }
 
 // validateSchemaDimensions validates schema dimesions given in floors JSON
@@ -32,6 +42,14 @@ func validateSchemaDimensions(fields []string) error {
 	return nil
 }
 
+// validateSchemaTimeZone validates the timezone given in floors JSON schema, which is used by the hourOfDay dimension
+func validateSchemaTimeZone(schema openrtb_ext.PriceFloorSchema) error {
+	if _, err := loadLocation(schema.TimeZone); err != nil {
+		return fmt.Errorf("Invalid schema timezone provided = '%s'", schema.TimeZone)
+	}
+	return nil
+}
+
 // validateFloorRulesAndLowerValidRuleKey validates rule keys for number of schema dimension fields and drops invalid rules.
 // It also lower case of rule if any charactor in a rule is upper
 func validateFloorRulesAndLowerValidRuleKey(schema openrtb_ext.PriceFloorSchema, delimiter string, ruleValues map[string]float64) []error {
@@ -88,6 +106,11 @@ func selectValidFloorModelGroups(modelGroups []openrtb_ext.PriceFloorModelGroup,
 			continue
 		}
 
+		if err := validateSchemaTimeZone(modelGroup.Schema); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
 		if account.PriceFloors.MaxSchemaDims > 0 && len(modelGroup.Schema.Fields) > account.PriceFloors.MaxSchemaDims {
 			errs = append(errs, fmt.Errorf("Invalid Floor Model = '%v' due to number of schema fields = '%v' are greater than limit %v", modelGroup.ModelVersion, len(modelGroup.Schema.Fields), account.PriceFloors.MaxSchemaDims))
 			continue
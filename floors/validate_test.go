@@ -415,6 +415,10 @@ func TestValidateSchemaDimensions(t *testing.T) {
 			name:   "valid_fields",
 			fields: []string{"deviceType", "size"},
 		},
+		{
+			name:   "valid_additional_fields",
+			fields: []string{"hourOfDay", "os", "browser", "connectionType", "appStoreUrl", "eidsPresent", "dealPresent", "videoPlacement", "videoPlcmt", "impPosition"},
+		},
 		{
 			name:   "invalid_fields",
 			fields: []string{"deviceType", "dealType"},
@@ -428,3 +432,31 @@ func TestValidateSchemaDimensions(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateSchemaTimeZone(t *testing.T) {
+	tests := []struct {
+		name   string
+		schema openrtb_ext.PriceFloorSchema
+		err    error
+	}{
+		{
+			name:   "no_timezone",
+			schema: openrtb_ext.PriceFloorSchema{Fields: []string{"hourOfDay"}},
+		},
+		{
+			name:   "valid_timezone",
+			schema: openrtb_ext.PriceFloorSchema{Fields: []string{"hourOfDay"}, TimeZone: "Asia/Kolkata"},
+		},
+		{
+			name:   "invalid_timezone",
+			schema: openrtb_ext.PriceFloorSchema{Fields: []string{"hourOfDay"}, TimeZone: "Mars/Olympus_Mons"},
+			err:    fmt.Errorf("Invalid schema timezone provided = 'Mars/Olympus_Mons'"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateSchemaTimeZone(tt.schema)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
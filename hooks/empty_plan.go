@@ -40,3 +40,15 @@ func (e EmptyPlanBuilder) PlanForAuctionResponseStage(endpoint string, account *
 func (e EmptyPlanBuilder) PlanForExitpointStage(endpoint string, account *config.Account) Plan[hookstage.Exitpoint] {
 	return nil
 }
+
+func (e EmptyPlanBuilder) PlanForCookieSyncRequestStage(endpoint string, account *config.Account) Plan[hookstage.CookieSyncRequest] {
+	return nil
+}
+
+func (e EmptyPlanBuilder) PlanForSetUIDRequestStage(endpoint string, account *config.Account) Plan[hookstage.SetUIDRequest] {
+	return nil
+}
+
+func (e EmptyPlanBuilder) PlanForEventNotificationStage(endpoint string, account *config.Account) Plan[hookstage.EventNotification] {
+	return nil
+}
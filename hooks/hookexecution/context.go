@@ -1,6 +1,7 @@
 package hookexecution
 
 import (
+	"context"
 	"sync"
 
 	"github.com/prebid/prebid-server/v4/config"
@@ -17,6 +18,7 @@ type executionContext struct {
 	account         *config.Account
 	moduleContexts  *moduleContexts
 	activityControl privacy.ActivityControl
+	traceCtx        context.Context
 }
 
 func (ctx executionContext) getModuleContext(moduleName string) hookstage.ModuleInvocationContext {
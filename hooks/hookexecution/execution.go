@@ -16,7 +16,9 @@ import (
 	"github.com/prebid/prebid-server/v4/openrtb_ext"
 	"github.com/prebid/prebid-server/v4/ortb"
 	"github.com/prebid/prebid-server/v4/privacy"
+	"github.com/prebid/prebid-server/v4/tracing"
 	"github.com/prebid/prebid-server/v4/util/iputil"
+	oteltrace "go.opentelemetry.io/otel/trace"
 )
 
 type hookResponse[T any] struct {
@@ -40,6 +42,13 @@ func executeStage[H any, P any](
 	hookHandler hookHandler[H, P],
 	metricEngine metrics.MetricsEngine,
 ) (StageOutcome, P, stageModuleContext, *RejectError) {
+	if executionCtx.traceCtx == nil {
+		executionCtx.traceCtx = context.Background()
+	}
+	traceCtx, span := tracing.Start(executionCtx.traceCtx, "hooks."+executionCtx.stage, oteltrace.WithAttributes(tracing.AttributeStage.String(executionCtx.stage)))
+	defer span.End()
+	executionCtx.traceCtx = traceCtx
+
 	stageOutcome := StageOutcome{}
 	stageOutcome.Groups = make([]GroupOutcome, 0, len(plan))
 	stageModuleCtx := stageModuleContext{}
@@ -78,7 +87,7 @@ func executeGroup[H any, P any](
 		wg.Add(1)
 		go func(hw hooks.HookWrapper[H], moduleCtx hookstage.ModuleInvocationContext) {
 			defer wg.Done()
-			executeHook(moduleCtx, hw, newPayload, hookHandler, group.Timeout, resp, rejected)
+			executeHook(executionCtx.traceCtx, moduleCtx, hw, newPayload, hookHandler, group.Timeout, resp, rejected)
 		}(hook, mCtx)
 	}
 
@@ -93,6 +102,7 @@ func executeGroup[H any, P any](
 }
 
 func executeHook[H any, P any](
+	traceCtx context.Context,
 	moduleCtx hookstage.ModuleInvocationContext,
 	hw hooks.HookWrapper[H],
 	payload P,
@@ -113,9 +123,19 @@ func executeHook[H any, P any](
 			}
 		}()
 
-		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		_, span := tracing.Start(traceCtx, "hooks.module "+hw.Module, oteltrace.WithAttributes(
+			tracing.AttributeModule.String(hw.Module),
+			tracing.AttributeHook.String(hw.Code),
+		))
+		defer span.End()
+
+		// only the span is carried over, so that the hook timeout is independent of the caller context
+		ctx, cancel := context.WithTimeout(oteltrace.ContextWithSpan(context.Background(), span), timeout)
 		defer cancel()
 		result, err := hookHandler(ctx, moduleCtx, hw.Hook, payload)
+		if err != nil {
+			tracing.RecordErrors(span, err)
+		}
 		hookRespCh <- hookResponse[P]{
 			Result: result,
 			Err:    err,
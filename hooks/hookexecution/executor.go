@@ -19,6 +19,11 @@ import (
 const (
 	EndpointAuction = "/openrtb2/auction"
 	EndpointAmp     = "/openrtb2/amp"
+	EndpointVideo   = "/openrtb2/video"
+
+	EndpointCookieSync = "/cookie_sync"
+	EndpointSetUID     = "/setuid"
+	EndpointEvent      = "/event"
 )
 
 // An entity specifies the type of object that was processed during the execution of the stage.
@@ -30,6 +35,9 @@ const (
 	entityAuctionResponse          entity = "auction_response"
 	entityAllProcessedBidResponses entity = "all_processed_bid_responses"
 	entityExitpoint                entity = "exitpoint"
+	entityCookieSyncRequest        entity = "cookie_sync_request"
+	entitySetUIDRequest            entity = "setuid_request"
+	entityEventNotification        entity = "event_notification"
 )
 
 type StageExecutor interface {
@@ -41,12 +49,17 @@ type StageExecutor interface {
 	ExecuteAllProcessedBidResponsesStage(adapterBids map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid)
 	ExecuteAuctionResponseStage(response *openrtb2.BidResponse)
 	ExecuteExitpointStage(response any, w http.ResponseWriter) any
+	ExecuteCookieSyncRequestStage(payload hookstage.CookieSyncRequestPayload) (hookstage.CookieSyncRequestPayload, *RejectError)
+	ExecuteSetUIDRequestStage(payload hookstage.SetUIDRequestPayload) (hookstage.SetUIDRequestPayload, *RejectError)
+	ExecuteEventNotificationStage(payload hookstage.EventNotificationPayload) hookstage.EventNotificationPayload
 }
 
 type HookStageExecutor interface {
 	StageExecutor
 	SetAccount(account *config.Account)
 	SetActivityControl(activityControl privacy.ActivityControl)
+	// SetTraceContext sets the context holding the span which stage spans are created under.
+	SetTraceContext(ctx context.Context)
 	GetOutcomes() []StageOutcome
 }
 
@@ -59,6 +72,7 @@ type hookExecutor struct {
 	moduleContexts  *moduleContexts
 	metricEngine    metrics.MetricsEngine
 	activityControl privacy.ActivityControl
+	traceCtx        context.Context
 	// Mutex needed for BidderRequest and RawBidderResponse Stages as they are run in several goroutines
 	sync.Mutex
 }
@@ -70,6 +84,7 @@ func NewHookExecutor(builder hooks.ExecutionPlanBuilder, endpoint string, me met
 		stageOutcomes:  []StageOutcome{},
 		moduleContexts: &moduleContexts{ctxs: make(map[string]*hookstage.ModuleContext)},
 		metricEngine:   me,
+		traceCtx:       context.Background(),
 	}
 }
 
@@ -86,7 +101,14 @@ func (e *hookExecutor) SetActivityControl(activityControl privacy.ActivityContro
 	e.activityControl = activityControl
 }
 
+func (e *hookExecutor) SetTraceContext(ctx context.Context) {
+	e.traceCtx = ctx
+}
+
 func (e *hookExecutor) GetOutcomes() []StageOutcome {
+	// secondary bidders may still be running the bidder stages when the outcomes are read
+	e.Lock()
+	defer e.Unlock()
 	return e.stageOutcomes
 }
 
@@ -326,6 +348,90 @@ func (e *hookExecutor) ExecuteExitpointStage(response any, w http.ResponseWriter
 	return payload.Response
 }
 
+func (e *hookExecutor) ExecuteCookieSyncRequestStage(payload hookstage.CookieSyncRequestPayload) (hookstage.CookieSyncRequestPayload, *RejectError) {
+	plan := e.planBuilder.PlanForCookieSyncRequestStage(e.endpoint, e.account)
+	if len(plan) == 0 {
+		return payload, nil
+	}
+
+	handler := func(
+		ctx context.Context,
+		moduleCtx hookstage.ModuleInvocationContext,
+		hook hookstage.CookieSyncRequest,
+		payload hookstage.CookieSyncRequestPayload,
+	) (hookstage.HookResult[hookstage.CookieSyncRequestPayload], error) {
+		return hook.HandleCookieSyncRequestHook(ctx, moduleCtx, payload)
+	}
+
+	stageName := hooks.StageCookieSyncRequest.String()
+	executionCtx := e.newContext(stageName)
+
+	outcome, payload, contexts, reject := executeStage(executionCtx, plan, payload, handler, e.metricEngine)
+	outcome.Entity = entityCookieSyncRequest
+	outcome.Stage = stageName
+
+	e.saveModuleContexts(contexts)
+	e.pushStageOutcome(outcome)
+
+	return payload, reject
+}
+
+func (e *hookExecutor) ExecuteSetUIDRequestStage(payload hookstage.SetUIDRequestPayload) (hookstage.SetUIDRequestPayload, *RejectError) {
+	plan := e.planBuilder.PlanForSetUIDRequestStage(e.endpoint, e.account)
+	if len(plan) == 0 {
+		return payload, nil
+	}
+
+	handler := func(
+		ctx context.Context,
+		moduleCtx hookstage.ModuleInvocationContext,
+		hook hookstage.SetUIDRequest,
+		payload hookstage.SetUIDRequestPayload,
+	) (hookstage.HookResult[hookstage.SetUIDRequestPayload], error) {
+		return hook.HandleSetUIDRequestHook(ctx, moduleCtx, payload)
+	}
+
+	stageName := hooks.StageSetUIDRequest.String()
+	executionCtx := e.newContext(stageName)
+
+	outcome, payload, contexts, reject := executeStage(executionCtx, plan, payload, handler, e.metricEngine)
+	outcome.Entity = entitySetUIDRequest
+	outcome.Stage = stageName
+
+	e.saveModuleContexts(contexts)
+	e.pushStageOutcome(outcome)
+
+	return payload, reject
+}
+
+func (e *hookExecutor) ExecuteEventNotificationStage(payload hookstage.EventNotificationPayload) hookstage.EventNotificationPayload {
+	plan := e.planBuilder.PlanForEventNotificationStage(e.endpoint, e.account)
+	if len(plan) == 0 {
+		return payload
+	}
+
+	handler := func(
+		ctx context.Context,
+		moduleCtx hookstage.ModuleInvocationContext,
+		hook hookstage.EventNotification,
+		payload hookstage.EventNotificationPayload,
+	) (hookstage.HookResult[hookstage.EventNotificationPayload], error) {
+		return hook.HandleEventNotificationHook(ctx, moduleCtx, payload)
+	}
+
+	stageName := hooks.StageEventNotification.String()
+	executionCtx := e.newContext(stageName)
+
+	outcome, payload, contexts, _ := executeStage(executionCtx, plan, payload, handler, e.metricEngine)
+	outcome.Entity = entityEventNotification
+	outcome.Stage = stageName
+
+	e.saveModuleContexts(contexts)
+	e.pushStageOutcome(outcome)
+
+	return payload
+}
+
 func (e *hookExecutor) newContext(stage string) executionContext {
 	return executionContext{
 		account:         e.account,
@@ -334,6 +440,7 @@ func (e *hookExecutor) newContext(stage string) executionContext {
 		moduleContexts:  e.moduleContexts,
 		stage:           stage,
 		activityControl: e.activityControl,
+		traceCtx:        e.traceCtx,
 	}
 }
 
@@ -357,6 +464,8 @@ func (executor EmptyHookExecutor) SetAccount(_ *config.Account) {}
 
 func (executor EmptyHookExecutor) SetActivityControl(_ privacy.ActivityControl) {}
 
+func (executor EmptyHookExecutor) SetTraceContext(_ context.Context) {}
+
 func (executor EmptyHookExecutor) GetOutcomes() []StageOutcome {
 	return []StageOutcome{}
 }
@@ -390,3 +499,15 @@ func (executor EmptyHookExecutor) ExecuteAuctionResponseStage(_ *openrtb2.BidRes
 func (executor EmptyHookExecutor) ExecuteExitpointStage(response any, _ http.ResponseWriter) any {
 	return response
 }
+
+func (executor EmptyHookExecutor) ExecuteCookieSyncRequestStage(payload hookstage.CookieSyncRequestPayload) (hookstage.CookieSyncRequestPayload, *RejectError) {
+	return payload, nil
+}
+
+func (executor EmptyHookExecutor) ExecuteSetUIDRequestStage(payload hookstage.SetUIDRequestPayload) (hookstage.SetUIDRequestPayload, *RejectError) {
+	return payload, nil
+}
+
+func (executor EmptyHookExecutor) ExecuteEventNotificationStage(payload hookstage.EventNotificationPayload) hookstage.EventNotificationPayload {
+	return payload
+}
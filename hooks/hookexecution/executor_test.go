@@ -2403,6 +2403,160 @@ func TestExecuteExitpointStage(t *testing.T) {
 	}
 }
 
+func TestExecuteCookieSyncRequestStage(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/cookie_sync", nil)
+	payload := hookstage.CookieSyncRequestPayload{Request: req, Bidders: []string{"appnexus", "rubicon"}, Limit: 2}
+
+	testCases := []struct {
+		description      string
+		givenPlanBuilder hooks.ExecutionPlanBuilder
+		expectedPayload  hookstage.CookieSyncRequestPayload
+		expectedReject   *RejectError
+		expectedOutcomes int
+	}{
+		{
+			description:      "Payload not changed if hook execution plan empty",
+			givenPlanBuilder: hooks.EmptyPlanBuilder{},
+			expectedPayload:  payload,
+			expectedReject:   nil,
+			expectedOutcomes: 0,
+		},
+		{
+			description:      "Payload changed if hooks return mutations",
+			givenPlanBuilder: TestUserSyncPlanBuilder{hook: mockUpdateUserSyncHook{}},
+			expectedPayload:  hookstage.CookieSyncRequestPayload{Request: req, Bidders: []string{"appnexus"}, Limit: 2},
+			expectedReject:   nil,
+			expectedOutcomes: 1,
+		},
+		{
+			description:      "Stage execution can be rejected",
+			givenPlanBuilder: TestUserSyncPlanBuilder{hook: mockRejectHook{}},
+			expectedPayload:  payload,
+			expectedReject:   &RejectError{0, HookID{ModuleCode: "foobar", HookImplCode: "foo"}, hooks.StageCookieSyncRequest.String()},
+			expectedOutcomes: 1,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			exec := NewHookExecutor(test.givenPlanBuilder, EndpointCookieSync, &metricsConfig.NilMetricsEngine{})
+			exec.SetAccount(&config.Account{ID: "some-account"})
+
+			newPayload, reject := exec.ExecuteCookieSyncRequestStage(payload)
+
+			assert.Equal(t, test.expectedReject, reject, "Unexpected stage reject.")
+			assert.Equal(t, test.expectedPayload, newPayload, "Incorrect payload.")
+
+			outcomes := exec.GetOutcomes()
+			if assert.Len(t, outcomes, test.expectedOutcomes, "Incorrect stage outcomes.") && test.expectedOutcomes > 0 {
+				assert.Equal(t, entityCookieSyncRequest, outcomes[0].Entity)
+				assert.Equal(t, hooks.StageCookieSyncRequest.String(), outcomes[0].Stage)
+			}
+		})
+	}
+}
+
+func TestExecuteSetUIDRequestStage(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/setuid?bidder=appnexus&uid=some-uid", nil)
+	payload := hookstage.SetUIDRequestPayload{Request: req, Bidder: "appnexus", UID: "some-uid"}
+
+	testCases := []struct {
+		description      string
+		givenPlanBuilder hooks.ExecutionPlanBuilder
+		expectedPayload  hookstage.SetUIDRequestPayload
+		expectedReject   *RejectError
+		expectedOutcomes int
+	}{
+		{
+			description:      "Payload not changed if hook execution plan empty",
+			givenPlanBuilder: hooks.EmptyPlanBuilder{},
+			expectedPayload:  payload,
+			expectedReject:   nil,
+			expectedOutcomes: 0,
+		},
+		{
+			description:      "Payload changed if hooks return mutations",
+			givenPlanBuilder: TestUserSyncPlanBuilder{hook: mockUpdateUserSyncHook{}},
+			expectedPayload:  hookstage.SetUIDRequestPayload{Request: req, Bidder: "appnexus", UID: "new-uid"},
+			expectedReject:   nil,
+			expectedOutcomes: 1,
+		},
+		{
+			description:      "Stage execution can be rejected",
+			givenPlanBuilder: TestUserSyncPlanBuilder{hook: mockRejectHook{}},
+			expectedPayload:  payload,
+			expectedReject:   &RejectError{0, HookID{ModuleCode: "foobar", HookImplCode: "foo"}, hooks.StageSetUIDRequest.String()},
+			expectedOutcomes: 1,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			exec := NewHookExecutor(test.givenPlanBuilder, EndpointSetUID, &metricsConfig.NilMetricsEngine{})
+			exec.SetAccount(&config.Account{ID: "some-account"})
+
+			newPayload, reject := exec.ExecuteSetUIDRequestStage(payload)
+
+			assert.Equal(t, test.expectedReject, reject, "Unexpected stage reject.")
+			assert.Equal(t, test.expectedPayload, newPayload, "Incorrect payload.")
+
+			outcomes := exec.GetOutcomes()
+			if assert.Len(t, outcomes, test.expectedOutcomes, "Incorrect stage outcomes.") && test.expectedOutcomes > 0 {
+				assert.Equal(t, entitySetUIDRequest, outcomes[0].Entity)
+				assert.Equal(t, hooks.StageSetUIDRequest.String(), outcomes[0].Stage)
+			}
+		})
+	}
+}
+
+func TestExecuteEventNotificationStage(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/event?t=win&b=bid&a=account", nil)
+	payload := hookstage.EventNotificationPayload{Request: req, Type: "win", BidID: "bid", AccountID: "account"}
+
+	testCases := []struct {
+		description      string
+		givenPlanBuilder hooks.ExecutionPlanBuilder
+		expectedPayload  hookstage.EventNotificationPayload
+		expectedOutcomes int
+	}{
+		{
+			description:      "Payload not changed if hook execution plan empty",
+			givenPlanBuilder: hooks.EmptyPlanBuilder{},
+			expectedPayload:  payload,
+			expectedOutcomes: 0,
+		},
+		{
+			description:      "Payload changed if hooks return mutations",
+			givenPlanBuilder: TestUserSyncPlanBuilder{hook: mockUpdateUserSyncHook{}},
+			expectedPayload:  hookstage.EventNotificationPayload{Request: req, Type: "win", BidID: "bid", AccountID: "account", Integration: "new-integration"},
+			expectedOutcomes: 1,
+		},
+		{
+			description:      "Stage execution can't be rejected - stage doesn't support rejection",
+			givenPlanBuilder: TestUserSyncPlanBuilder{hook: mockRejectHook{}},
+			expectedPayload:  payload,
+			expectedOutcomes: 1,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			exec := NewHookExecutor(test.givenPlanBuilder, EndpointEvent, &metricsConfig.NilMetricsEngine{})
+			exec.SetAccount(&config.Account{ID: "some-account"})
+
+			newPayload := exec.ExecuteEventNotificationStage(payload)
+
+			assert.Equal(t, test.expectedPayload, newPayload, "Incorrect payload.")
+
+			outcomes := exec.GetOutcomes()
+			if assert.Len(t, outcomes, test.expectedOutcomes, "Incorrect stage outcomes.") && test.expectedOutcomes > 0 {
+				assert.Equal(t, entityEventNotification, outcomes[0].Entity)
+				assert.Equal(t, hooks.StageEventNotification.String(), outcomes[0].Stage)
+			}
+		})
+	}
+}
+
 func TestInterStageContextCommunication(t *testing.T) {
 	body := []byte(`{"foo": "bar"}`)
 	reader := bytes.NewReader(body)
@@ -3177,3 +3331,47 @@ func (e TestMultipleHooksUpdatePayloadBuilder) PlanForExitpointStage(_ string, _
 		},
 	}
 }
+
+type userSyncHook interface {
+	hookstage.CookieSyncRequest
+	hookstage.SetUIDRequest
+	hookstage.EventNotification
+}
+
+type TestUserSyncPlanBuilder struct {
+	hooks.EmptyPlanBuilder
+	hook userSyncHook
+}
+
+func (e TestUserSyncPlanBuilder) PlanForCookieSyncRequestStage(_ string, _ *config.Account) hooks.Plan[hookstage.CookieSyncRequest] {
+	return hooks.Plan[hookstage.CookieSyncRequest]{
+		hooks.Group[hookstage.CookieSyncRequest]{
+			Timeout: 10 * time.Millisecond,
+			Hooks: []hooks.HookWrapper[hookstage.CookieSyncRequest]{
+				{Module: "foobar", Code: "foo", Hook: e.hook},
+			},
+		},
+	}
+}
+
+func (e TestUserSyncPlanBuilder) PlanForSetUIDRequestStage(_ string, _ *config.Account) hooks.Plan[hookstage.SetUIDRequest] {
+	return hooks.Plan[hookstage.SetUIDRequest]{
+		hooks.Group[hookstage.SetUIDRequest]{
+			Timeout: 10 * time.Millisecond,
+			Hooks: []hooks.HookWrapper[hookstage.SetUIDRequest]{
+				{Module: "foobar", Code: "foo", Hook: e.hook},
+			},
+		},
+	}
+}
+
+func (e TestUserSyncPlanBuilder) PlanForEventNotificationStage(_ string, _ *config.Account) hooks.Plan[hookstage.EventNotification] {
+	return hooks.Plan[hookstage.EventNotification]{
+		hooks.Group[hookstage.EventNotification]{
+			Timeout: 10 * time.Millisecond,
+			Hooks: []hooks.HookWrapper[hookstage.EventNotification]{
+				{Module: "foobar", Code: "foo", Hook: e.hook},
+			},
+		},
+	}
+}
@@ -105,6 +105,18 @@ func (e mockRejectHook) HandleExitpointHook(_ context.Context, _ hookstage.Modul
 	return hookstage.HookResult[hookstage.ExitpointPayload]{Reject: true}, nil
 }
 
+func (e mockRejectHook) HandleCookieSyncRequestHook(_ context.Context, _ hookstage.ModuleInvocationContext, _ hookstage.CookieSyncRequestPayload) (hookstage.HookResult[hookstage.CookieSyncRequestPayload], error) {
+	return hookstage.HookResult[hookstage.CookieSyncRequestPayload]{Reject: true}, nil
+}
+
+func (e mockRejectHook) HandleSetUIDRequestHook(_ context.Context, _ hookstage.ModuleInvocationContext, _ hookstage.SetUIDRequestPayload) (hookstage.HookResult[hookstage.SetUIDRequestPayload], error) {
+	return hookstage.HookResult[hookstage.SetUIDRequestPayload]{Reject: true}, nil
+}
+
+func (e mockRejectHook) HandleEventNotificationHook(_ context.Context, _ hookstage.ModuleInvocationContext, _ hookstage.EventNotificationPayload) (hookstage.HookResult[hookstage.EventNotificationPayload], error) {
+	return hookstage.HookResult[hookstage.EventNotificationPayload]{Reject: true}, nil
+}
+
 type mockTimeoutHook struct{}
 
 func (e mockTimeoutHook) HandleEntrypointHook(_ context.Context, _ hookstage.ModuleInvocationContext, _ hookstage.EntrypointPayload) (hookstage.HookResult[hookstage.EntrypointPayload], error) {
@@ -446,3 +458,38 @@ func (e mockUpdateResponseAgainHook) HandleExitpointHook(_ context.Context, _ ho
 
 	return hookstage.HookResult[hookstage.ExitpointPayload]{ChangeSet: c}, nil
 }
+
+type mockUpdateUserSyncHook struct{}
+
+func (e mockUpdateUserSyncHook) HandleCookieSyncRequestHook(_ context.Context, _ hookstage.ModuleInvocationContext, _ hookstage.CookieSyncRequestPayload) (hookstage.HookResult[hookstage.CookieSyncRequestPayload], error) {
+	c := hookstage.ChangeSet[hookstage.CookieSyncRequestPayload]{}
+	c.AddMutation(
+		func(payload hookstage.CookieSyncRequestPayload) (hookstage.CookieSyncRequestPayload, error) {
+			payload.Bidders = []string{"appnexus"}
+			return payload, nil
+		}, hookstage.MutationUpdate, "cookieSyncRequest", "bidders")
+
+	return hookstage.HookResult[hookstage.CookieSyncRequestPayload]{ChangeSet: c}, nil
+}
+
+func (e mockUpdateUserSyncHook) HandleSetUIDRequestHook(_ context.Context, _ hookstage.ModuleInvocationContext, _ hookstage.SetUIDRequestPayload) (hookstage.HookResult[hookstage.SetUIDRequestPayload], error) {
+	c := hookstage.ChangeSet[hookstage.SetUIDRequestPayload]{}
+	c.AddMutation(
+		func(payload hookstage.SetUIDRequestPayload) (hookstage.SetUIDRequestPayload, error) {
+			payload.UID = "new-uid"
+			return payload, nil
+		}, hookstage.MutationUpdate, "setuidRequest", "uid")
+
+	return hookstage.HookResult[hookstage.SetUIDRequestPayload]{ChangeSet: c}, nil
+}
+
+func (e mockUpdateUserSyncHook) HandleEventNotificationHook(_ context.Context, _ hookstage.ModuleInvocationContext, _ hookstage.EventNotificationPayload) (hookstage.HookResult[hookstage.EventNotificationPayload], error) {
+	c := hookstage.ChangeSet[hookstage.EventNotificationPayload]{}
+	c.AddMutation(
+		func(payload hookstage.EventNotificationPayload) (hookstage.EventNotificationPayload, error) {
+			payload.Integration = "new-integration"
+			return payload, nil
+		}, hookstage.MutationUpdate, "eventNotification", "integration")
+
+	return hookstage.HookResult[hookstage.EventNotificationPayload]{ChangeSet: c}, nil
+}
@@ -0,0 +1,33 @@
+package hookstage
+
+import (
+	"context"
+	"net/http"
+)
+
+// CookieSyncRequest hooks are invoked for "/cookie_sync" endpoint
+// after retrieving the account config and parsing the request,
+// but before the bidders to sync are chosen.
+//
+// At this stage, account config is available,
+// so it can be configured at the account-level execution plan,
+// the account-level module config is passed to hooks.
+//
+// Rejection results in sending a response without any user syncs.
+type CookieSyncRequest interface {
+	HandleCookieSyncRequestHook(
+		context.Context,
+		ModuleInvocationContext,
+		CookieSyncRequestPayload,
+	) (HookResult[CookieSyncRequestPayload], error)
+}
+
+// CookieSyncRequestPayload consists of an HTTP request and the parsed
+// parameters of the cookie sync request.
+// Hooks are allowed to modify the list of bidders and the sync limit using mutations.
+// A limit above the max_limit of the account is capped, and a non positive one is ignored.
+type CookieSyncRequestPayload struct {
+	Request *http.Request
+	Bidders []string
+	Limit   int
+}
@@ -0,0 +1,37 @@
+package hookstage
+
+import (
+	"context"
+	"net/http"
+)
+
+// EventNotification hooks are invoked for "/event" endpoint
+// after retrieving the account config,
+// but before the notification event is passed to the analytics modules.
+//
+// At this stage, account config is available,
+// so it can be configured at the account-level execution plan,
+// the account-level module config is passed to hooks.
+//
+// Rejection has no effect and is completely ignored at this stage.
+type EventNotification interface {
+	HandleEventNotificationHook(
+		context.Context,
+		ModuleInvocationContext,
+		EventNotificationPayload,
+	) (HookResult[EventNotificationPayload], error)
+}
+
+// EventNotificationPayload consists of an HTTP request and the
+// parameters of the notification event.
+// Hooks are allowed to modify the event parameters using mutations.
+type EventNotificationPayload struct {
+	Request     *http.Request
+	Type        string
+	BidID       string
+	AccountID   string
+	Bidder      string
+	Timestamp   int64
+	Integration string
+	VType       string
+}
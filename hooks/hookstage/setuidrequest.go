@@ -0,0 +1,33 @@
+package hookstage
+
+import (
+	"context"
+	"net/http"
+)
+
+// SetUIDRequest hooks are invoked for "/setuid" endpoint
+// after the privacy checks have passed,
+// but before the bidder's UID is written to the uids cookie.
+//
+// At this stage, account config is available,
+// so it can be configured at the account-level execution plan,
+// the account-level module config is passed to hooks.
+//
+// Rejection results in a Bad Request response, leaving the cookie unchanged.
+type SetUIDRequest interface {
+	HandleSetUIDRequestHook(
+		context.Context,
+		ModuleInvocationContext,
+		SetUIDRequestPayload,
+	) (HookResult[SetUIDRequestPayload], error)
+}
+
+// SetUIDRequestPayload consists of an HTTP request, the bidder key
+// and the UID to be stored for that bidder. An empty UID unsyncs the bidder.
+// Hooks are allowed to modify the UID using mutations.
+// Changes to the bidder are ignored, the UID is always stored for the bidder of the request.
+type SetUIDRequestPayload struct {
+	Request *http.Request
+	Bidder  string
+	UID     string
+}
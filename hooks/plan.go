@@ -20,6 +20,9 @@ const (
 	StageAllProcessedBidResponses Stage = "all_processed_bid_responses"
 	StageAuctionResponse          Stage = "auction_response"
 	StageExitpoint                Stage = "exitpoint"
+	StageCookieSyncRequest        Stage = "cookie_sync_request"
+	StageSetUIDRequest            Stage = "setuid_request"
+	StageEventNotification        Stage = "event_notification"
 )
 
 func (s Stage) String() string {
@@ -28,7 +31,8 @@ func (s Stage) String() string {
 
 func (s Stage) IsRejectable() bool {
 	return s != StageAllProcessedBidResponses &&
-		s != StageAuctionResponse && s != StageExitpoint
+		s != StageAuctionResponse && s != StageExitpoint &&
+		s != StageEventNotification
 }
 
 // ExecutionPlanBuilder is the interface that provides methods
@@ -43,6 +47,9 @@ type ExecutionPlanBuilder interface {
 	PlanForAllProcessedBidResponsesStage(endpoint string, account *config.Account) Plan[hookstage.AllProcessedBidResponses]
 	PlanForAuctionResponseStage(endpoint string, account *config.Account) Plan[hookstage.AuctionResponse]
 	PlanForExitpointStage(endpoint string, account *config.Account) Plan[hookstage.Exitpoint]
+	PlanForCookieSyncRequestStage(endpoint string, account *config.Account) Plan[hookstage.CookieSyncRequest]
+	PlanForSetUIDRequestStage(endpoint string, account *config.Account) Plan[hookstage.SetUIDRequest]
+	PlanForEventNotificationStage(endpoint string, account *config.Account) Plan[hookstage.EventNotification]
 }
 
 // Plan represents a slice of groups of hooks of a specific type grouped in the established order.
@@ -168,6 +175,36 @@ func (p PlanBuilder) PlanForExitpointStage(endpoint string, account *config.Acco
 	)
 }
 
+func (p PlanBuilder) PlanForCookieSyncRequestStage(endpoint string, account *config.Account) Plan[hookstage.CookieSyncRequest] {
+	return getMergedPlan(
+		p.hooks,
+		account,
+		endpoint,
+		StageCookieSyncRequest,
+		p.repo.GetCookieSyncRequestHook,
+	)
+}
+
+func (p PlanBuilder) PlanForSetUIDRequestStage(endpoint string, account *config.Account) Plan[hookstage.SetUIDRequest] {
+	return getMergedPlan(
+		p.hooks,
+		account,
+		endpoint,
+		StageSetUIDRequest,
+		p.repo.GetSetUIDRequestHook,
+	)
+}
+
+func (p PlanBuilder) PlanForEventNotificationStage(endpoint string, account *config.Account) Plan[hookstage.EventNotification] {
+	return getMergedPlan(
+		p.hooks,
+		account,
+		endpoint,
+		StageEventNotification,
+		p.repo.GetEventNotificationHook,
+	)
+}
+
 type hookFn[T any] func(moduleName string) (T, bool)
 
 func getMergedPlan[T any](
@@ -876,6 +876,120 @@ func TestPlanForExitpointStage(t *testing.T) {
 	}
 }
 
+func TestPlanForCookieSyncRequestStage(t *testing.T) {
+	const group1 string = `{"timeout":  5, "hook_sequence": [{"module_code": "foobar", "hook_impl_code": "foo"}]}`
+	const group2 string = `{"timeout": 10, "hook_sequence": [{"module_code": "prebid", "hook_impl_code": "bar"}]}`
+	const hostPlanData string = `{"endpoints": {"/cookie_sync": {"stages": {"cookie_sync_request": {"groups": [` + group1 + `]}}}}}`
+	const accountPlanData string = `{"execution_plan": {"endpoints": {"/cookie_sync": {"stages": {"cookie_sync_request": {"groups": [` + group2 + `]}}}}}}`
+
+	hooks := map[string]interface{}{
+		"foobar": fakeCookieSyncRequestHook{},
+		"prebid": fakeCookieSyncRequestHook{},
+	}
+
+	testCases := map[string]struct {
+		givenEndpoint       string
+		giveAccountPlanData []byte
+		expectedPlan        Plan[hookstage.CookieSyncRequest]
+	}{
+		"Host and account execution plans are merged": {
+			givenEndpoint:       "/cookie_sync",
+			giveAccountPlanData: []byte(accountPlanData),
+			expectedPlan: Plan[hookstage.CookieSyncRequest]{
+				Group[hookstage.CookieSyncRequest]{
+					Timeout: 5 * time.Millisecond,
+					Hooks: []HookWrapper[hookstage.CookieSyncRequest]{
+						{Module: "foobar", Code: "foo", Hook: fakeCookieSyncRequestHook{}},
+					},
+				},
+				Group[hookstage.CookieSyncRequest]{
+					Timeout: 10 * time.Millisecond,
+					Hooks: []HookWrapper[hookstage.CookieSyncRequest]{
+						{Module: "prebid", Code: "bar", Hook: fakeCookieSyncRequestHook{}},
+					},
+				},
+			},
+		},
+		"Plan for other endpoint is empty": {
+			givenEndpoint:       "/setuid",
+			giveAccountPlanData: []byte(accountPlanData),
+			expectedPlan:        Plan[hookstage.CookieSyncRequest]{},
+		},
+	}
+
+	for name, test := range testCases {
+		t.Run(name, func(t *testing.T) {
+			account := new(config.Account)
+			if err := jsonutil.UnmarshalValid(test.giveAccountPlanData, &account.Hooks); err != nil {
+				t.Fatal(err)
+			}
+
+			planBuilder, err := getPlanBuilder(hooks, []byte(hostPlanData), []byte(`{}`))
+			if assert.NoError(t, err, "Failed to init hook execution plan builder") {
+				plan := planBuilder.PlanForCookieSyncRequestStage(test.givenEndpoint, account)
+				assert.Equal(t, test.expectedPlan, plan)
+			}
+		})
+	}
+}
+
+func TestPlanForSetUIDRequestStage(t *testing.T) {
+	const group1 string = `{"timeout":  5, "hook_sequence": [{"module_code": "foobar", "hook_impl_code": "foo"}]}`
+	const group2 string = `{"timeout": 10, "hook_sequence": [{"module_code": "prebid", "hook_impl_code": "bar"}]}`
+	const hostPlanData string = `{"endpoints": {"/setuid": {"stages": {"setuid_request": {"groups": [` + group1 + `]}}}}}`
+	const defaultAccountPlanData string = `{"endpoints": {"/setuid": {"stages": {"setuid_request": {"groups": [` + group2 + `]}}}}}`
+
+	hooks := map[string]interface{}{
+		"foobar": fakeSetUIDRequestHook{},
+		"prebid": fakeSetUIDRequestHook{},
+	}
+
+	expectedPlan := Plan[hookstage.SetUIDRequest]{
+		Group[hookstage.SetUIDRequest]{
+			Timeout: 5 * time.Millisecond,
+			Hooks: []HookWrapper[hookstage.SetUIDRequest]{
+				{Module: "foobar", Code: "foo", Hook: fakeSetUIDRequestHook{}},
+			},
+		},
+		Group[hookstage.SetUIDRequest]{
+			Timeout: 10 * time.Millisecond,
+			Hooks: []HookWrapper[hookstage.SetUIDRequest]{
+				{Module: "prebid", Code: "bar", Hook: fakeSetUIDRequestHook{}},
+			},
+		},
+	}
+
+	planBuilder, err := getPlanBuilder(hooks, []byte(hostPlanData), []byte(defaultAccountPlanData))
+	if assert.NoError(t, err, "Failed to init hook execution plan builder") {
+		plan := planBuilder.PlanForSetUIDRequestStage("/setuid", new(config.Account))
+		assert.Equal(t, expectedPlan, plan)
+	}
+}
+
+func TestPlanForEventNotificationStage(t *testing.T) {
+	const group1 string = `{"timeout":  5, "hook_sequence": [{"module_code": "foobar", "hook_impl_code": "foo"}]}`
+	const hostPlanData string = `{"endpoints": {"/event": {"stages": {"event_notification": {"groups": [` + group1 + `]}}}}}`
+
+	hooks := map[string]interface{}{
+		"foobar": fakeEventNotificationHook{},
+	}
+
+	expectedPlan := Plan[hookstage.EventNotification]{
+		Group[hookstage.EventNotification]{
+			Timeout: 5 * time.Millisecond,
+			Hooks: []HookWrapper[hookstage.EventNotification]{
+				{Module: "foobar", Code: "foo", Hook: fakeEventNotificationHook{}},
+			},
+		},
+	}
+
+	planBuilder, err := getPlanBuilder(hooks, []byte(hostPlanData), []byte(`{}`))
+	if assert.NoError(t, err, "Failed to init hook execution plan builder") {
+		plan := planBuilder.PlanForEventNotificationStage("/event", new(config.Account))
+		assert.Equal(t, expectedPlan, plan)
+	}
+}
+
 func getPlanBuilder(
 	moduleHooks map[string]interface{},
 	hostPlanData, accountPlanData []byte,
@@ -986,3 +1100,33 @@ func (f fakeExitpointHook) HandleExitpointHook(
 ) (hookstage.HookResult[hookstage.ExitpointPayload], error) {
 	return hookstage.HookResult[hookstage.ExitpointPayload]{}, nil
 }
+
+type fakeCookieSyncRequestHook struct{}
+
+func (f fakeCookieSyncRequestHook) HandleCookieSyncRequestHook(
+	_ context.Context,
+	_ hookstage.ModuleInvocationContext,
+	_ hookstage.CookieSyncRequestPayload,
+) (hookstage.HookResult[hookstage.CookieSyncRequestPayload], error) {
+	return hookstage.HookResult[hookstage.CookieSyncRequestPayload]{}, nil
+}
+
+type fakeSetUIDRequestHook struct{}
+
+func (f fakeSetUIDRequestHook) HandleSetUIDRequestHook(
+	_ context.Context,
+	_ hookstage.ModuleInvocationContext,
+	_ hookstage.SetUIDRequestPayload,
+) (hookstage.HookResult[hookstage.SetUIDRequestPayload], error) {
+	return hookstage.HookResult[hookstage.SetUIDRequestPayload]{}, nil
+}
+
+type fakeEventNotificationHook struct{}
+
+func (f fakeEventNotificationHook) HandleEventNotificationHook(
+	_ context.Context,
+	_ hookstage.ModuleInvocationContext,
+	_ hookstage.EventNotificationPayload,
+) (hookstage.HookResult[hookstage.EventNotificationPayload], error) {
+	return hookstage.HookResult[hookstage.EventNotificationPayload]{}, nil
+}
@@ -22,6 +22,9 @@ type HookRepository interface {
 	GetAllProcessedBidResponsesHook(id string) (hookstage.AllProcessedBidResponses, bool)
 	GetAuctionResponseHook(id string) (hookstage.AuctionResponse, bool)
 	GetExitpointHook(id string) (hookstage.Exitpoint, bool)
+	GetCookieSyncRequestHook(id string) (hookstage.CookieSyncRequest, bool)
+	GetSetUIDRequestHook(id string) (hookstage.SetUIDRequest, bool)
+	GetEventNotificationHook(id string) (hookstage.EventNotification, bool)
 }
 
 // NewHookRepository returns a new instance of the HookRepository interface.
@@ -51,6 +54,9 @@ type hookRepository struct {
 	allProcessedBidResponseHooks map[string]hookstage.AllProcessedBidResponses
 	auctionResponseHooks         map[string]hookstage.AuctionResponse
 	exitpointHooks               map[string]hookstage.Exitpoint
+	cookieSyncRequestHooks       map[string]hookstage.CookieSyncRequest
+	setUIDRequestHooks           map[string]hookstage.SetUIDRequest
+	eventNotificationHooks       map[string]hookstage.EventNotification
 }
 
 func (r *hookRepository) GetEntrypointHook(id string) (hookstage.Entrypoint, bool) {
@@ -85,6 +91,18 @@ func (r *hookRepository) GetExitpointHook(id string) (hookstage.Exitpoint, bool)
 	return getHook(r.exitpointHooks, id)
 }
 
+func (r *hookRepository) GetCookieSyncRequestHook(id string) (hookstage.CookieSyncRequest, bool) {
+	return getHook(r.cookieSyncRequestHooks, id)
+}
+
+func (r *hookRepository) GetSetUIDRequestHook(id string) (hookstage.SetUIDRequest, bool) {
+	return getHook(r.setUIDRequestHooks, id)
+}
+
+func (r *hookRepository) GetEventNotificationHook(id string) (hookstage.EventNotification, bool) {
+	return getHook(r.eventNotificationHooks, id)
+}
+
 func (r *hookRepository) add(id string, hook interface{}) error {
 	var hasAnyHooks bool
 	var err error
@@ -145,6 +163,27 @@ func (r *hookRepository) add(id string, hook interface{}) error {
 		}
 	}
 
+	if h, ok := hook.(hookstage.CookieSyncRequest); ok {
+		hasAnyHooks = true
+		if r.cookieSyncRequestHooks, err = addHook(r.cookieSyncRequestHooks, h, id); err != nil {
+			return err
+		}
+	}
+
+	if h, ok := hook.(hookstage.SetUIDRequest); ok {
+		hasAnyHooks = true
+		if r.setUIDRequestHooks, err = addHook(r.setUIDRequestHooks, h, id); err != nil {
+			return err
+		}
+	}
+
+	if h, ok := hook.(hookstage.EventNotification); ok {
+		hasAnyHooks = true
+		if r.eventNotificationHooks, err = addHook(r.eventNotificationHooks, h, id); err != nil {
+			return err
+		}
+	}
+
 	if !hasAnyHooks {
 		return fmt.Errorf(`hook "%s" does not implement any supported hook interface`, id)
 	}
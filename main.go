@@ -65,11 +65,32 @@ func loadConfig(bidderInfos config.BidderInfos) (*config.Configuration, error) {
 	return config.New(v, bidderInfos, openrtb_ext.NormalizeBidderName)
 }
 
+// newCurrencyRateSources returns the currency rate sources of the configuration, in priority order.
+func newCurrencyRateSources(cfgSources []config.CurrencyRateSource, client *http.Client) []currency.ChainedRateSource {
+	sources := make([]currency.ChainedRateSource, 0, len(cfgSources))
+	for _, cfgSource := range cfgSources {
+		source := currency.ChainedRateSource{
+			StaleRatesThreshold: time.Duration(cfgSource.StaleRatesSeconds) * time.Second,
+		}
+		switch cfgSource.Type {
+		case config.CurrencyRateSourcePrebid:
+			source.RateSource = currency.NewPrebidRateSource(client, cfgSource.URL)
+		case config.CurrencyRateSourceECB:
+			source.RateSource = currency.NewECBRateSource(client, cfgSource.URL)
+		case config.CurrencyRateSourceFile:
+			source.RateSource = currency.NewFileRateSource(cfgSource.Path)
+		default:
+			continue
+		}
+		sources = append(sources, source)
+	}
+	return sources
+}
+
 func serve(cfg *config.Configuration) error {
 	httpTimeout := time.Duration(cfg.CurrencyConverter.FetchTimeoutMilliseconds) * time.Millisecond
 	fetchingInterval := time.Duration(cfg.CurrencyConverter.FetchIntervalSeconds) * time.Second
-	staleRatesThreshold := time.Duration(cfg.CurrencyConverter.StaleRatesSeconds) * time.Second
-	currencyConverter := currency.NewRateConverter(&http.Client{}, httpTimeout, cfg.CurrencyConverter.FetchURL, staleRatesThreshold)
+	currencyConverter := currency.NewChainedRateConverter(httpTimeout, newCurrencyRateSources(cfg.CurrencyConverter.RateSources(), &http.Client{}))
 
 	currencyConverterTickerTask := task.NewTickerTask(fetchingInterval, currencyConverter)
 	currencyConverterTickerTask.Start()
@@ -4,8 +4,10 @@ import (
 	"time"
 
 	"github.com/prebid/prebid-server/v4/config"
+	"github.com/prebid/prebid-server/v4/logger"
 	"github.com/prebid/prebid-server/v4/metrics"
 	prometheusmetrics "github.com/prebid/prebid-server/v4/metrics/prometheus"
+	statsdmetrics "github.com/prebid/prebid-server/v4/metrics/statsd"
 	"github.com/prebid/prebid-server/v4/openrtb_ext"
 	gometrics "github.com/rcrowley/go-metrics"
 	influxdb "github.com/vrischmann/go-metrics-influxdb"
@@ -43,6 +45,14 @@ func NewMetricsEngine(cfg *config.Configuration, adapterList []openrtb_ext.Bidde
 		returnEngine.PrometheusMetrics = prometheusmetrics.NewMetrics(cfg.Metrics.Prometheus, cfg.Metrics.Disabled, syncerKeys, moduleStageNames)
 		engineList = append(engineList, returnEngine.PrometheusMetrics)
 	}
+	if cfg.Metrics.StatsD.Host != "" {
+		statsDMetrics, err := statsdmetrics.NewMetrics(cfg.Metrics.StatsD, cfg.Metrics.Disabled)
+		if err != nil {
+			logger.Fatalf("Failed to create the StatsD metrics engine: %v", err)
+		}
+		returnEngine.StatsDMetrics = statsDMetrics
+		engineList = append(engineList, returnEngine.StatsDMetrics)
+	}
 
 	// Now return the proper metrics engine
 	if len(engineList) > 1 {
@@ -61,6 +71,7 @@ type DetailedMetricsEngine struct {
 	metrics.MetricsEngine
 	GoMetrics         *metrics.Metrics
 	PrometheusMetrics *prometheusmetrics.Metrics
+	StatsDMetrics     *statsdmetrics.Metrics
 }
 
 // MultiMetricsEngine logs metrics to multiple metrics databases The can be useful in transitioning
@@ -217,6 +228,13 @@ func (me *MultiMetricsEngine) RecordSyncerSet(key string, status metrics.SyncerS
 	}
 }
 
+// RecordUIDCookieDecode across all engines
+func (me *MultiMetricsEngine) RecordUIDCookieDecode(status metrics.UIDCookieDecodeStatus) {
+	for _, thisME := range *me {
+		thisME.RecordUIDCookieDecode(status)
+	}
+}
+
 // RecordStoredReqCacheResult across all engines
 func (me *MultiMetricsEngine) RecordStoredReqCacheResult(cacheResult metrics.CacheResult, inc int) {
 	for _, thisME := range *me {
@@ -390,6 +408,13 @@ func (me *MultiMetricsEngine) RecordAdapterThrottled(adapter openrtb_ext.BidderN
 	}
 }
 
+// RecordAdapterCircuitBreakerTransition across all engines
+func (me *MultiMetricsEngine) RecordAdapterCircuitBreakerTransition(adapter openrtb_ext.BidderName, state metrics.CircuitBreakerState) {
+	for _, thisME := range *me {
+		thisME.RecordAdapterCircuitBreakerTransition(adapter, state)
+	}
+}
+
 func (me *MultiMetricsEngine) RecordAdapterConnectionDialError(adapterName openrtb_ext.BidderName) {
 	for _, thisME := range *me {
 		thisME.RecordAdapterConnectionDialError(adapterName)
@@ -494,6 +519,10 @@ func (me *NilMetricsEngine) RecordSetUid(status metrics.SetUidStatus) {
 func (me *NilMetricsEngine) RecordSyncerSet(key string, status metrics.SyncerSetUidStatus) {
 }
 
+// RecordUIDCookieDecode as a noop
+func (me *NilMetricsEngine) RecordUIDCookieDecode(status metrics.UIDCookieDecodeStatus) {
+}
+
 // RecordStoredReqCacheResult as a noop
 func (me *NilMetricsEngine) RecordStoredReqCacheResult(cacheResult metrics.CacheResult, inc int) {
 }
@@ -588,6 +617,10 @@ func (me *NilMetricsEngine) RecordModuleTimeout(labels metrics.ModuleLabels) {
 func (me *NilMetricsEngine) RecordAdapterThrottled(adapter openrtb_ext.BidderName) {
 }
 
+// RecordAdapterCircuitBreakerTransition as a noop
+func (me *NilMetricsEngine) RecordAdapterCircuitBreakerTransition(adapter openrtb_ext.BidderName, state metrics.CircuitBreakerState) {
+}
+
 func (me *NilMetricsEngine) RecordAdapterConnectionDialError(adapterName openrtb_ext.BidderName) {
 }
 
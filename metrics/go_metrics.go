@@ -49,6 +49,7 @@ type Metrics struct {
 	SetUidMeter           metrics.Meter
 	SetUidStatusMeter     map[SetUidStatus]metrics.Meter
 	SyncerSetsMeter       map[string]map[SyncerSetUidStatus]metrics.Meter
+	UIDCookieDecodeMeter  map[UIDCookieDecodeStatus]metrics.Meter
 
 	// Media types found in the "imp" JSON object
 	ImpsTypeBanner metrics.Meter
@@ -108,6 +109,8 @@ type AdapterMetrics struct {
 	BuyerUIDScrubbed   metrics.Meter
 	GDPRRequestBlocked metrics.Meter
 	ThrottledMeter     metrics.Meter
+	// CircuitBreakerMeters counts circuit breaker transitions by the state transitioned to.
+	CircuitBreakerMeters map[CircuitBreakerState]metrics.Meter
 
 	BidValidationCreativeSizeErrorMeter metrics.Meter
 	BidValidationCreativeSizeWarnMeter  metrics.Meter
@@ -188,6 +191,7 @@ func NewBlankMetrics(registry metrics.Registry, exchanges []string, disabledMetr
 		SetUidMeter:                    blankMeter,
 		SetUidStatusMeter:              make(map[SetUidStatus]metrics.Meter),
 		SyncerSetsMeter:                make(map[string]map[SyncerSetUidStatus]metrics.Meter),
+		UIDCookieDecodeMeter:           make(map[UIDCookieDecodeStatus]metrics.Meter),
 		StoredResponsesMeter:           blankMeter,
 		GvlListRequestsMeter:           blankMeter,
 		LiveGVLFetchSuccess:            blankMeter,
@@ -346,6 +350,10 @@ func NewMetrics(registry metrics.Registry, exchanges []openrtb_ext.BidderName, d
 		newMetrics.SetUidStatusMeter[s] = metrics.GetOrRegisterMeter(fmt.Sprintf("setuid_requests.%s", s), registry)
 	}
 
+	for _, s := range UIDCookieDecodeStatuses() {
+		newMetrics.UIDCookieDecodeMeter[s] = metrics.GetOrRegisterMeter(fmt.Sprintf("uid_cookie_decode.%s", s), registry)
+	}
+
 	for _, syncerKey := range syncerKeys {
 		newMetrics.SyncerRequestsMeter[syncerKey] = make(map[SyncerCookieSyncStatus]metrics.Meter)
 		for _, status := range SyncerRequestStatuses() {
@@ -426,6 +434,8 @@ func makeBlankAdapterMetrics(disabledMetrics config.DisabledMetrics) *AdapterMet
 		PanicMeter:        blankMeter,
 		MarkupMetrics:     makeBlankBidMarkupMetrics(),
 		ThrottledMeter:    blankMeter,
+
+		CircuitBreakerMeters: make(map[CircuitBreakerState]metrics.Meter),
 	}
 	if !disabledMetrics.AdapterConnectionMetrics {
 		newAdapter.ConnCreated = metrics.NilCounter{}
@@ -443,6 +453,9 @@ func makeBlankAdapterMetrics(disabledMetrics config.DisabledMetrics) *AdapterMet
 	for _, err := range AdapterErrors() {
 		newAdapter.ErrorMeters[err] = blankMeter
 	}
+	for _, state := range CircuitBreakerStates() {
+		newAdapter.CircuitBreakerMeters[state] = blankMeter
+	}
 	return newAdapter
 }
 
@@ -521,6 +534,9 @@ func registerAdapterMetrics(registry metrics.Registry, adapterOrAccount string,
 	am.BuyerUIDScrubbed = metrics.GetOrRegisterMeter(fmt.Sprintf("%[1]s.%[2]s.buyeruid_scrubbed", adapterOrAccount, exchange), registry)
 	am.GDPRRequestBlocked = metrics.GetOrRegisterMeter(fmt.Sprintf("%[1]s.%[2]s.gdpr_request_blocked", adapterOrAccount, exchange), registry)
 	am.ThrottledMeter = metrics.GetOrRegisterMeter(fmt.Sprintf("%[1]s.%[2]s.requests.throttled", adapterOrAccount, exchange), registry)
+	for state := range am.CircuitBreakerMeters {
+		am.CircuitBreakerMeters[state] = metrics.GetOrRegisterMeter(fmt.Sprintf("%s.%s.circuit_breaker.%s", adapterOrAccount, exchange, state), registry)
+	}
 
 	am.BidValidationCreativeSizeErrorMeter = metrics.GetOrRegisterMeter(fmt.Sprintf("%[1]s.%[2]s.response.validation.size.err", adapterOrAccount, exchange), registry)
 	am.BidValidationCreativeSizeWarnMeter = metrics.GetOrRegisterMeter(fmt.Sprintf("%[1]s.%[2]s.response.validation.size.warn", adapterOrAccount, exchange), registry)
@@ -919,6 +935,12 @@ func (me *Metrics) RecordSyncerSet(key string, status SyncerSetUidStatus) {
 	}
 }
 
+func (me *Metrics) RecordUIDCookieDecode(status UIDCookieDecodeStatus) {
+	if meter, exists := me.UIDCookieDecodeMeter[status]; exists {
+		meter.Mark(1)
+	}
+}
+
 // RecordStoredReqCacheResult implements a part of the MetricsEngine interface. Records the
 // cache hits and misses when looking up stored requests
 func (me *Metrics) RecordStoredReqCacheResult(cacheResult CacheResult, inc int) {
@@ -1231,3 +1253,16 @@ func (me *Metrics) RecordAdapterThrottled(adapterName openrtb_ext.BidderName) {
 
 	am.ThrottledMeter.Mark(1)
 }
+
+func (me *Metrics) RecordAdapterCircuitBreakerTransition(adapterName openrtb_ext.BidderName, state CircuitBreakerState) {
+	adapterStr := adapterName.String()
+	am, ok := me.AdapterMetrics[strings.ToLower(adapterStr)]
+	if !ok {
+		logger.Errorf("Trying to log adapter circuit breaker metric for %s: adapter not found", adapterStr)
+		return
+	}
+
+	if meter, ok := am.CircuitBreakerMeters[state]; ok {
+		meter.Mark(1)
+	}
+}
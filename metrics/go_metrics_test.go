@@ -35,6 +35,7 @@ func TestNewMetrics(t *testing.T) {
 	ensureContains(t, registry, "setuid_requests.opt_out", m.SetUidStatusMeter[SetUidOptOut])
 	ensureContains(t, registry, "setuid_requests.gdpr_blocked_host_cookie", m.SetUidStatusMeter[SetUidGDPRHostCookieBlocked])
 	ensureContains(t, registry, "setuid_requests.syncer_unknown", m.SetUidStatusMeter[SetUidSyncerUnknown])
+	ensureContains(t, registry, "setuid_requests.uid_store_error", m.SetUidStatusMeter[SetUidStoreError])
 	ensureContains(t, registry, "stored_responses", m.StoredResponsesMeter)
 	ensureContains(t, registry, "gvl_requests", m.GvlListRequestsMeter)
 	ensureContains(t, registry, "live_gvl_fetch.ok", m.LiveGVLFetchSuccess)
@@ -881,6 +882,40 @@ func TestRecordAdapterGDPRRequestBlocked(t *testing.T) {
 	}
 }
 
+func TestRecordAdapterCircuitBreakerTransition(t *testing.T) {
+	var fakeBidder openrtb_ext.BidderName = "fooAdvertising"
+	adapter := "AnyName"
+	lowerCaseAdapterName := "anyname"
+
+	tests := []struct {
+		name          string
+		adapterName   openrtb_ext.BidderName
+		expectedCount int64
+	}{
+		{
+			name:          "bidder_found",
+			adapterName:   openrtb_ext.BidderName(adapter),
+			expectedCount: 1,
+		},
+		{
+			name:          "bidder_not_found",
+			adapterName:   fakeBidder,
+			expectedCount: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registry := metrics.NewRegistry()
+			m := NewMetrics(registry, []openrtb_ext.BidderName{openrtb_ext.BidderName(adapter)}, config.DisabledMetrics{}, nil, nil)
+
+			m.RecordAdapterCircuitBreakerTransition(tt.adapterName, CircuitBreakerOpen)
+
+			assert.Equal(t, tt.expectedCount, m.AdapterMetrics[lowerCaseAdapterName].CircuitBreakerMeters[CircuitBreakerOpen].Count())
+			assert.Equal(t, int64(0), m.AdapterMetrics[lowerCaseAdapterName].CircuitBreakerMeters[CircuitBreakerClosed].Count())
+		})
+	}
+}
+
 func TestRecordCookieSync(t *testing.T) {
 	registry := metrics.NewRegistry()
 	m := NewMetrics(registry, []openrtb_ext.BidderName{openrtb_ext.BidderName("Foo"), openrtb_ext.BidderName("Bar")}, config.DisabledMetrics{}, nil, nil)
@@ -936,6 +971,21 @@ func TestRecordSetUid(t *testing.T) {
 	assert.Equal(t, m.SetUidStatusMeter[SetUidSyncerUnknown].Count(), int64(0))
 }
 
+func TestRecordUIDCookieDecode(t *testing.T) {
+	registry := metrics.NewRegistry()
+	m := NewMetrics(registry, []openrtb_ext.BidderName{openrtb_ext.BidderName("Foo")}, config.DisabledMetrics{}, nil, nil)
+
+	// Known
+	m.RecordUIDCookieDecode(UIDCookieDecodeTampered)
+
+	// Unknown
+	m.RecordUIDCookieDecode(UIDCookieDecodeStatus("unknown status"))
+
+	assert.Equal(t, m.UIDCookieDecodeMeter[UIDCookieDecodeLegacy].Count(), int64(0))
+	assert.Equal(t, m.UIDCookieDecodeMeter[UIDCookieDecodeTampered].Count(), int64(1))
+	assert.Equal(t, m.UIDCookieDecodeMeter[UIDCookieDecodeUndecodable].Count(), int64(0))
+}
+
 func TestRecordSyncerSet(t *testing.T) {
 	registry := metrics.NewRegistry()
 	syncerKeys := []string{"foo"}
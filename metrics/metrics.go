@@ -85,6 +85,7 @@ const (
 	RequestDataType  StoredDataType = "request"
 	VideoDataType    StoredDataType = "video"
 	ResponseDataType StoredDataType = "response"
+	FloorsDataType   StoredDataType = "floors"
 )
 
 func StoredDataTypes() []StoredDataType {
@@ -95,6 +96,7 @@ func StoredDataTypes() []StoredDataType {
 		RequestDataType,
 		VideoDataType,
 		ResponseDataType,
+		FloorsDataType,
 	}
 }
 
@@ -330,13 +332,20 @@ const (
 	// CacheMiss represents a cache miss i.e that key wasn't found in cache
 	// and had to be fetched from the backend
 	CacheMiss CacheResult = "miss"
+	// CacheStaleHit represents a key found in cache past its TTL, which is served
+	// while it's refreshed from the backend
+	CacheStaleHit CacheResult = "stale_hit"
+	// CacheNegativeHit represents a key known by the cache not to exist in the backend
+	CacheNegativeHit CacheResult = "negative_hit"
 )
 
-// CacheResults returns possible cache results i.e. cache hit or miss
+// CacheResults returns possible cache results i.e. cache hit, miss, stale hit or negative hit
 func CacheResults() []CacheResult {
 	return []CacheResult{
 		CacheHit,
 		CacheMiss,
+		CacheStaleHit,
+		CacheNegativeHit,
 	}
 }
 
@@ -424,6 +433,7 @@ const (
 	SetUidAccountConfigMalformed SetUidStatus = "acct_config_malformed"
 	SetUidAccountInvalid         SetUidStatus = "acct_invalid"
 	SetUidSyncerUnknown          SetUidStatus = "syncer_unknown"
+	SetUidStoreError             SetUidStatus = "uid_store_error"
 )
 
 // SetUidStatuses returns possible setuid statuses.
@@ -437,6 +447,7 @@ func SetUidStatuses() []SetUidStatus {
 		SetUidAccountConfigMalformed,
 		SetUidAccountInvalid,
 		SetUidSyncerUnknown,
+		SetUidStoreError,
 	}
 }
 
@@ -456,6 +467,44 @@ func SyncerSetUidStatuses() []SyncerSetUidStatus {
 	}
 }
 
+// UIDCookieDecodeStatus is the outcome of reading a uids cookie which isn't in the authenticated format.
+type UIDCookieDecodeStatus string
+
+const (
+	UIDCookieDecodeLegacy         UIDCookieDecodeStatus = "legacy"
+	UIDCookieDecodeLegacyRejected UIDCookieDecodeStatus = "legacy_rejected"
+	UIDCookieDecodeTampered       UIDCookieDecodeStatus = "tampered"
+	UIDCookieDecodeUndecodable    UIDCookieDecodeStatus = "undecodable"
+)
+
+// UIDCookieDecodeStatuses returns possible uids cookie decode statuses.
+func UIDCookieDecodeStatuses() []UIDCookieDecodeStatus {
+	return []UIDCookieDecodeStatus{
+		UIDCookieDecodeLegacy,
+		UIDCookieDecodeLegacyRejected,
+		UIDCookieDecodeTampered,
+		UIDCookieDecodeUndecodable,
+	}
+}
+
+// CircuitBreakerState is the state a bidder circuit breaker transitioned to.
+type CircuitBreakerState string
+
+const (
+	CircuitBreakerClosed   CircuitBreakerState = "closed"
+	CircuitBreakerOpen     CircuitBreakerState = "open"
+	CircuitBreakerHalfOpen CircuitBreakerState = "half_open"
+)
+
+// CircuitBreakerStates returns possible circuit breaker states.
+func CircuitBreakerStates() []CircuitBreakerState {
+	return []CircuitBreakerState{
+		CircuitBreakerClosed,
+		CircuitBreakerOpen,
+		CircuitBreakerHalfOpen,
+	}
+}
+
 // MetricsEngine is a generic interface to record PBS metrics into the desired backend
 // The first three metrics function fire off once per incoming request, so total metrics
 // will equal the total number of incoming requests. The remaining 5 fire off per outgoing
@@ -483,6 +532,7 @@ type MetricsEngine interface {
 	RecordSyncerRequest(key string, status SyncerCookieSyncStatus)
 	RecordSetUid(status SetUidStatus)
 	RecordSyncerSet(key string, status SyncerSetUidStatus)
+	RecordUIDCookieDecode(status UIDCookieDecodeStatus)
 	RecordStoredReqCacheResult(cacheResult CacheResult, inc int)
 	RecordStoredImpCacheResult(cacheResult CacheResult, inc int)
 	RecordAccountCacheResult(cacheResult CacheResult, inc int)
@@ -512,6 +562,7 @@ type MetricsEngine interface {
 	RecordModuleExecutionError(labels ModuleLabels)
 	RecordModuleTimeout(labels ModuleLabels)
 	RecordAdapterThrottled(adapterName openrtb_ext.BidderName)
+	RecordAdapterCircuitBreakerTransition(adapterName openrtb_ext.BidderName, state CircuitBreakerState)
 	RecordAdapterConnectionDialError(adapterName openrtb_ext.BidderName)
 	RecordAdapterConnectionDialTime(adapterName openrtb_ext.BidderName, dialStartTime time.Duration)
 }
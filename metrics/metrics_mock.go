@@ -121,6 +121,11 @@ func (me *MetricsEngineMock) RecordSyncerSet(key string, status SyncerSetUidStat
 	me.Called(key, status)
 }
 
+// RecordUIDCookieDecode mock
+func (me *MetricsEngineMock) RecordUIDCookieDecode(status UIDCookieDecodeStatus) {
+	me.Called(status)
+}
+
 // RecordStoredReqCacheResult mock
 func (me *MetricsEngineMock) RecordStoredReqCacheResult(cacheResult CacheResult, inc int) {
 	me.Called(cacheResult, inc)
@@ -239,6 +244,10 @@ func (me *MetricsEngineMock) RecordAdapterThrottled(adapterName openrtb_ext.Bidd
 	me.Called(adapterName)
 }
 
+func (me *MetricsEngineMock) RecordAdapterCircuitBreakerTransition(adapterName openrtb_ext.BidderName, state CircuitBreakerState) {
+	me.Called(adapterName, state)
+}
+
 func (me *MetricsEngineMock) RecordAdapterConnectionDialError(adapterName openrtb_ext.BidderName) {
 	me.Called()
 }
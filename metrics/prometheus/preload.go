@@ -20,6 +20,7 @@ func preloadLabelValues(m *Metrics, syncerKeys []string, moduleStageNames map[st
 		requestStatusValues       = enumAsString(metrics.RequestStatuses())
 		requestTypeValues         = enumAsString(metrics.RequestTypes())
 		setUidStatusValues        = enumAsString(metrics.SetUidStatuses())
+		uidCookieDecodeValues     = enumAsString(metrics.UIDCookieDecodeStatuses())
 		sourceValues              = []string{sourceRequest}
 		storedDataErrorValues     = enumAsString(metrics.StoredDataErrors())
 		storedDataFetchTypeValues = enumAsString(metrics.StoredDataFetchTypes())
@@ -40,6 +41,10 @@ func preloadLabelValues(m *Metrics, syncerKeys []string, moduleStageNames map[st
 		statusLabel: setUidStatusValues,
 	})
 
+	preloadLabelValuesForCounter(m.uidCookieDecode, map[string][]string{
+		statusLabel: uidCookieDecodeValues,
+	})
+
 	preloadLabelValuesForCounter(m.impressions, map[string][]string{
 		isBannerLabel: boolValues,
 		isVideoLabel:  boolValues,
@@ -84,6 +89,10 @@ func preloadLabelValues(m *Metrics, syncerKeys []string, moduleStageNames map[st
 		storedDataFetchTypeLabel: storedDataFetchTypeValues,
 	})
 
+	preloadLabelValuesForHistogram(m.storedFloorsFetchTimer, map[string][]string{
+		storedDataFetchTypeLabel: storedDataFetchTypeValues,
+	})
+
 	preloadLabelValuesForCounter(m.storedAccountErrors, map[string][]string{
 		storedDataErrorLabel: storedDataErrorValues,
 	})
@@ -108,6 +117,10 @@ func preloadLabelValues(m *Metrics, syncerKeys []string, moduleStageNames map[st
 		storedDataErrorLabel: storedDataErrorValues,
 	})
 
+	preloadLabelValuesForCounter(m.storedFloorsErrors, map[string][]string{
+		storedDataErrorLabel: storedDataErrorValues,
+	})
+
 	preloadLabelValuesForCounter(m.requestsWithoutCookie, map[string][]string{
 		requestTypeLabel: requestTypeValues,
 	})
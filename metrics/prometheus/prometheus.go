@@ -25,6 +25,7 @@ type Metrics struct {
 	connectionsOpened            prometheus.Counter
 	cookieSync                   *prometheus.CounterVec
 	setUid                       *prometheus.CounterVec
+	uidCookieDecode              *prometheus.CounterVec
 	impressions                  *prometheus.CounterVec
 	prebidCacheWriteTimer        *prometheus.HistogramVec
 	requests                     *prometheus.CounterVec
@@ -58,6 +59,8 @@ type Metrics struct {
 	liveGVLFetch                 *prometheus.CounterVec
 	storedResponsesFetchTimer    *prometheus.HistogramVec
 	storedResponsesErrors        *prometheus.CounterVec
+	storedFloorsFetchTimer       *prometheus.HistogramVec
+	storedFloorsErrors           *prometheus.CounterVec
 	adsCertRequests              *prometheus.CounterVec
 	adsCertSignTimer             prometheus.Histogram
 	bidderServerResponseTimer    prometheus.Histogram
@@ -80,6 +83,7 @@ type Metrics struct {
 	adapterBidResponseSecureMarkupError   *prometheus.CounterVec
 	adapterBidResponseSecureMarkupWarn    *prometheus.CounterVec
 	adapterThrottled                      *prometheus.CounterVec
+	adapterCircuitBreakerTransitions      *prometheus.CounterVec
 	adapterConnectionDialErrors           *prometheus.CounterVec
 	adapterConnectionDialTime             *prometheus.HistogramVec
 
@@ -116,6 +120,7 @@ const (
 	adapterLabel         = "adapter"
 	bidTypeLabel         = "bid_type"
 	cacheResultLabel     = "cache_result"
+	circuitStateLabel    = "circuit_state"
 	connectionErrorLabel = "connection_error"
 	cookieLabel          = "cookie"
 	hasBidsLabel         = "has_bids"
@@ -207,6 +212,11 @@ func NewMetrics(cfg config.PrometheusMetrics, disabledMetrics config.DisabledMet
 		"Count of set uid requests to Prebid Server.",
 		[]string{statusLabel})
 
+	metrics.uidCookieDecode = newCounter(cfg, reg,
+		"uid_cookie_decode",
+		"Count of uids cookies read without the authenticated encoding, labeled by status.",
+		[]string{statusLabel})
+
 	metrics.impressions = newCounter(cfg, reg,
 		"impressions_requests",
 		"Count of requested impressions to Prebid Server labeled by type.",
@@ -372,6 +382,17 @@ func NewMetrics(cfg config.PrometheusMetrics, disabledMetrics config.DisabledMet
 		"Count of stored video errors by error type",
 		[]string{storedDataErrorLabel})
 
+	metrics.storedFloorsFetchTimer = newHistogramVec(cfg, reg,
+		"stored_floors_fetch_time_seconds",
+		"Seconds to fetch stored floors labeled by fetch type",
+		[]string{storedDataFetchTypeLabel},
+		standardTimeBuckets)
+
+	metrics.storedFloorsErrors = newCounter(cfg, reg,
+		"stored_floors_errors",
+		"Count of stored floors errors by error type",
+		[]string{storedDataErrorLabel})
+
 	metrics.storedResponses = newCounterWithoutLabels(cfg, reg,
 		"stored_responses",
 		"Count of total requests to Prebid Server that have stored responses")
@@ -467,6 +488,11 @@ func NewMetrics(cfg config.PrometheusMetrics, disabledMetrics config.DisabledMet
 		"Count of requests throttled labeled by adapter.",
 		[]string{adapterLabel})
 
+	metrics.adapterCircuitBreakerTransitions = newCounter(cfg, reg,
+		"adapter_circuit_breaker_transitions",
+		"Count of circuit breaker state transitions labeled by adapter and the state transitioned to.",
+		[]string{adapterLabel, circuitStateLabel})
+
 	metrics.overheadTimer = newHistogramVec(cfg, reg,
 		"overhead_time_seconds",
 		"Seconds to prepare adapter request or resolve adapter response",
@@ -800,6 +826,10 @@ func (m *Metrics) RecordStoredDataFetchTime(labels metrics.StoredDataLabels, len
 		m.storedResponsesFetchTimer.With(prometheus.Labels{
 			storedDataFetchTypeLabel: string(labels.DataFetchType),
 		}).Observe(length.Seconds())
+	case metrics.FloorsDataType:
+		m.storedFloorsFetchTimer.With(prometheus.Labels{
+			storedDataFetchTypeLabel: string(labels.DataFetchType),
+		}).Observe(length.Seconds())
 	}
 }
 
@@ -829,6 +859,10 @@ func (m *Metrics) RecordStoredDataError(labels metrics.StoredDataLabels) {
 		m.storedResponsesErrors.With(prometheus.Labels{
 			storedDataErrorLabel: string(labels.Error),
 		}).Inc()
+	case metrics.FloorsDataType:
+		m.storedFloorsErrors.With(prometheus.Labels{
+			storedDataErrorLabel: string(labels.Error),
+		}).Inc()
 	}
 }
 
@@ -947,6 +981,12 @@ func (m *Metrics) RecordSyncerSet(key string, status metrics.SyncerSetUidStatus)
 	}).Inc()
 }
 
+func (m *Metrics) RecordUIDCookieDecode(status metrics.UIDCookieDecodeStatus) {
+	m.uidCookieDecode.With(prometheus.Labels{
+		statusLabel: string(status),
+	}).Inc()
+}
+
 func (m *Metrics) RecordStoredReqCacheResult(cacheResult metrics.CacheResult, inc int) {
 	m.storedRequestCacheResult.With(prometheus.Labels{
 		cacheResultLabel: string(cacheResult),
@@ -1159,6 +1199,13 @@ func (m *Metrics) RecordAdapterThrottled(adapterName openrtb_ext.BidderName) {
 	}).Inc()
 }
 
+func (m *Metrics) RecordAdapterCircuitBreakerTransition(adapterName openrtb_ext.BidderName, state metrics.CircuitBreakerState) {
+	m.adapterCircuitBreakerTransitions.With(prometheus.Labels{
+		adapterLabel:      strings.ToLower(string(adapterName)),
+		circuitStateLabel: string(state),
+	}).Inc()
+}
+
 func (m *Metrics) RecordAdapterConnectionDialError(adapterName openrtb_ext.BidderName) {
 	m.adapterConnectionDialErrors.With(prometheus.Labels{
 		adapterLabel: strings.ToLower(string(adapterName)),
@@ -1339,6 +1339,18 @@ func TestSetUidMetric(t *testing.T) {
 	}
 }
 
+func TestUIDCookieDecodeMetric(t *testing.T) {
+	m := createMetricsForTesting()
+
+	m.RecordUIDCookieDecode(metrics.UIDCookieDecodeTampered)
+
+	assertCounterVecValue(t, "", "uid_cookie_decode:tampered", m.uidCookieDecode,
+		float64(1),
+		prometheus.Labels{
+			statusLabel: string(metrics.UIDCookieDecodeTampered),
+		})
+}
+
 func TestRecordSyncerSetMetric(t *testing.T) {
 	key := "anyKey"
 
@@ -1896,6 +1908,28 @@ func TestRecordAdapterGDPRRequestBlocked(t *testing.T) {
 		})
 }
 
+func TestRecordAdapterCircuitBreakerTransition(t *testing.T) {
+	m := createMetricsForTesting()
+	adapterName := openrtb_ext.BidderName("AnyName")
+	lowerCasedAdapterName := "anyname"
+	m.RecordAdapterCircuitBreakerTransition(adapterName, metrics.CircuitBreakerOpen)
+	m.RecordAdapterCircuitBreakerTransition(adapterName, metrics.CircuitBreakerOpen)
+	m.RecordAdapterCircuitBreakerTransition(adapterName, metrics.CircuitBreakerHalfOpen)
+
+	assertCounterVecValue(t, "", "adapter_circuit_breaker_transitions:open", m.adapterCircuitBreakerTransitions,
+		2,
+		prometheus.Labels{
+			adapterLabel:      lowerCasedAdapterName,
+			circuitStateLabel: string(metrics.CircuitBreakerOpen),
+		})
+	assertCounterVecValue(t, "", "adapter_circuit_breaker_transitions:half_open", m.adapterCircuitBreakerTransitions,
+		1,
+		prometheus.Labels{
+			adapterLabel:      lowerCasedAdapterName,
+			circuitStateLabel: string(metrics.CircuitBreakerHalfOpen),
+		})
+}
+
 func TestStoredResponsesMetric(t *testing.T) {
 	testCases := []struct {
 		description                           string
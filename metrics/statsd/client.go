@@ -0,0 +1,248 @@
+package statsd
+
+import (
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prebid/prebid-server/v4/config"
+)
+
+type metricType string
+
+const (
+	counterType   metricType = "c"
+	timerType     metricType = "ms"
+	histogramType metricType = "h"
+)
+
+type tag struct {
+	key   string
+	value string
+}
+
+// metricKey identifies a metric series. The name and tags are already formatted for the wire.
+type metricKey struct {
+	name       string
+	tags       string
+	metricType metricType
+}
+
+// client buffers metrics and sends them to a StatsD server in packets of at most maxPacketSize
+// bytes. Metrics are sent every flush interval, or earlier once bufferSize metrics are buffered.
+type client struct {
+	writer        io.Writer
+	namespace     string
+	flavor        config.StatsDFlavor
+	aggregate     bool
+	maxPacketSize int
+	bufferSize    int
+
+	mutex    sync.Mutex
+	counters map[metricKey]int64
+	samples  map[metricKey][]float64
+	lines    []string
+	buffered int
+
+	flushRequests chan struct{}
+	done          chan struct{}
+	stopped       chan struct{}
+}
+
+func newClient(writer io.Writer, cfg config.StatsDMetrics) *client {
+	namespace := cfg.Namespace
+	if namespace != "" && !strings.HasSuffix(namespace, ".") {
+		namespace += "."
+	}
+	return &client{
+		writer:        writer,
+		namespace:     namespace,
+		flavor:        cfg.Flavor,
+		aggregate:     cfg.Aggregation,
+		maxPacketSize: cfg.MaxPacketSize,
+		bufferSize:    cfg.BufferSize,
+		counters:      make(map[metricKey]int64),
+		samples:       make(map[metricKey][]float64),
+		flushRequests: make(chan struct{}, 1),
+		done:          make(chan struct{}),
+		stopped:       make(chan struct{}),
+	}
+}
+
+// run flushes the buffered metrics every interval until close is called.
+func (c *client) run(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	defer close(c.stopped)
+
+	for {
+		select {
+		case <-ticker.C:
+			c.flush()
+		case <-c.flushRequests:
+			c.flush()
+		case <-c.done:
+			c.flush()
+			return
+		}
+	}
+}
+
+// close stops the flush loop after sending all buffered metrics.
+func (c *client) close() {
+	close(c.done)
+	<-c.stopped
+}
+
+func (c *client) count(name string, value int64, tags ...tag) {
+	if value == 0 {
+		return
+	}
+	c.record(c.key(name, counterType, tags), float64(value))
+}
+
+func (c *client) timing(name string, value time.Duration, tags ...tag) {
+	c.record(c.key(name, timerType, tags), float64(value.Microseconds())/1000)
+}
+
+func (c *client) histogram(name string, value float64, tags ...tag) {
+	metricType := histogramType
+	if c.flavor == config.StatsDFlavorStatsD {
+		// plain StatsD has no histogram type, but timers are aggregated into the same statistics
+		metricType = timerType
+	}
+	c.record(c.key(name, metricType, tags), value)
+}
+
+func (c *client) record(key metricKey, value float64) {
+	c.mutex.Lock()
+	switch {
+	case !c.aggregate:
+		c.lines = append(c.lines, formatLine(key, formatValue(value)))
+	case key.metricType == counterType:
+		c.counters[key] += int64(value)
+	default:
+		c.samples[key] = append(c.samples[key], value)
+	}
+	c.buffered++
+	full := c.buffered >= c.bufferSize
+	c.mutex.Unlock()
+
+	if full {
+		select {
+		case c.flushRequests <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// key formats the metric name and tags for the wire. Plain StatsD doesn't support tags, so their
+// values are appended to the metric name in the order given.
+func (c *client) key(name string, metricType metricType, tags []tag) metricKey {
+	if c.flavor == config.StatsDFlavorStatsD {
+		var sb strings.Builder
+		sb.WriteString(c.namespace)
+		sb.WriteString(name)
+		for _, t := range tags {
+			sb.WriteByte('.')
+			sb.WriteString(sanitize(t.value, true))
+		}
+		return metricKey{name: sb.String(), metricType: metricType}
+	}
+
+	formattedTags := make([]string, len(tags))
+	for i, t := range tags {
+		formattedTags[i] = t.key + ":" + sanitize(t.value, false)
+	}
+	return metricKey{name: c.namespace + name, tags: strings.Join(formattedTags, ","), metricType: metricType}
+}
+
+func (c *client) flush() {
+	c.mutex.Lock()
+	lines := c.lines
+	counters := c.counters
+	samples := c.samples
+	c.lines = nil
+	c.counters = make(map[metricKey]int64, len(counters))
+	c.samples = make(map[metricKey][]float64, len(samples))
+	c.buffered = 0
+	c.mutex.Unlock()
+
+	for key, value := range counters {
+		lines = append(lines, formatLine(key, strconv.FormatInt(value, 10)))
+	}
+	for key, values := range samples {
+		lines = append(lines, c.formatSamples(key, values)...)
+	}
+	// sorted for a stable output, which also keeps related metrics in the same packet
+	sort.Strings(lines)
+
+	c.send(lines)
+}
+
+// formatSamples packs multiple samples into a single line if supported by the flavor.
+func (c *client) formatSamples(key metricKey, values []float64) []string {
+	formattedValues := make([]string, len(values))
+	for i, value := range values {
+		formattedValues[i] = formatValue(value)
+	}
+	if c.flavor == config.StatsDFlavorDogStatsD {
+		return []string{formatLine(key, strings.Join(formattedValues, ":"))}
+	}
+
+	lines := make([]string, len(formattedValues))
+	for i, value := range formattedValues {
+		lines[i] = formatLine(key, value)
+	}
+	return lines
+}
+
+// send writes the lines in newline separated packets. Lines longer than the maximum packet size are
+// sent on their own. Write errors are ignored since delivery isn't guaranteed over UDP anyway.
+func (c *client) send(lines []string) {
+	var packet []byte
+	for _, line := range lines {
+		if len(packet) > 0 && len(packet)+1+len(line) > c.maxPacketSize {
+			c.writer.Write(packet)
+			packet = packet[:0]
+		}
+		if len(packet) > 0 {
+			packet = append(packet, '\n')
+		}
+		packet = append(packet, line...)
+	}
+	if len(packet) > 0 {
+		c.writer.Write(packet)
+	}
+}
+
+func formatLine(key metricKey, value string) string {
+	line := key.name + ":" + value + "|" + string(key.metricType)
+	if key.tags != "" {
+		line += "|#" + key.tags
+	}
+	return line
+}
+
+func formatValue(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+// sanitize replaces the characters reserved by the StatsD protocol. Dots separate the segments of
+// plain StatsD metric names, so they are replaced too when a value becomes part of the name.
+func sanitize(value string, inName bool) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case ':', '|', ',', '#', '@', '\n', ' ':
+			return '_'
+		case '.':
+			if inName {
+				return '_'
+			}
+		}
+		return r
+	}, value)
+}
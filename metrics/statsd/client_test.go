@@ -0,0 +1,192 @@
+package statsd
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/prebid/prebid-server/v4/config"
+	"github.com/stretchr/testify/assert"
+)
+
+// packetRecorder is an io.Writer recording each write as a separate packet.
+type packetRecorder struct {
+	mutex   sync.Mutex
+	packets []string
+}
+
+func (r *packetRecorder) Write(p []byte) (int, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.packets = append(r.packets, string(p))
+	return len(p), nil
+}
+
+func (r *packetRecorder) lines() []string {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	var lines []string
+	for _, packet := range r.packets {
+		lines = append(lines, strings.Split(packet, "\n")...)
+	}
+	return lines
+}
+
+func newTestClient(recorder *packetRecorder, flavor config.StatsDFlavor, aggregate bool) *client {
+	return newClient(recorder, config.StatsDMetrics{
+		Namespace:     "pbs",
+		Flavor:        flavor,
+		Aggregation:   aggregate,
+		MaxPacketSize: 1432,
+		BufferSize:    1000,
+	})
+}
+
+func TestClientFormat(t *testing.T) {
+	testCases := []struct {
+		description   string
+		flavor        config.StatsDFlavor
+		aggregate     bool
+		record        func(c *client)
+		expectedLines []string
+	}{
+		{
+			description: "dogstatsd-aggregated",
+			flavor:      config.StatsDFlavorDogStatsD,
+			aggregate:   true,
+			record: func(c *client) {
+				c.count("requests", 1, tag{"request_type", "openrtb2-web"})
+				c.count("requests", 2, tag{"request_type", "openrtb2-web"})
+				c.timing("request_time", 15*time.Millisecond, tag{"request_type", "openrtb2-web"})
+				c.timing("request_time", 1500*time.Microsecond, tag{"request_type", "openrtb2-web"})
+				c.histogram("adapter_prices", 0.25, tag{"adapter", "appnexus"})
+				c.count("tmax_timeout", 1)
+			},
+			expectedLines: []string{
+				"pbs.adapter_prices:0.25|h|#adapter:appnexus",
+				"pbs.request_time:15:1.5|ms|#request_type:openrtb2-web",
+				"pbs.requests:3|c|#request_type:openrtb2-web",
+				"pbs.tmax_timeout:1|c",
+			},
+		},
+		{
+			description: "dogstatsd-not-aggregated",
+			flavor:      config.StatsDFlavorDogStatsD,
+			aggregate:   false,
+			record: func(c *client) {
+				c.count("requests", 1, tag{"request_type", "openrtb2-web"})
+				c.count("requests", 2, tag{"request_type", "openrtb2-web"})
+				c.timing("request_time", 15*time.Millisecond)
+			},
+			expectedLines: []string{
+				"pbs.request_time:15|ms",
+				"pbs.requests:1|c|#request_type:openrtb2-web",
+				"pbs.requests:2|c|#request_type:openrtb2-web",
+			},
+		},
+		{
+			description: "statsd-aggregated",
+			flavor:      config.StatsDFlavorStatsD,
+			aggregate:   true,
+			record: func(c *client) {
+				c.count("requests", 1, tag{"request_type", "openrtb2-web"}, tag{"request_status", "ok"})
+				c.count("requests", 1, tag{"request_type", "openrtb2-web"}, tag{"request_status", "ok"})
+				c.timing("request_time", 15*time.Millisecond)
+				c.timing("request_time", 20*time.Millisecond)
+				c.histogram("adapter_prices", 0.25, tag{"adapter", "appnexus"})
+			},
+			expectedLines: []string{
+				"pbs.adapter_prices.appnexus:0.25|ms",
+				"pbs.request_time:15|ms",
+				"pbs.request_time:20|ms",
+				"pbs.requests.openrtb2-web.ok:2|c",
+			},
+		},
+		{
+			description: "sanitized",
+			flavor:      config.StatsDFlavorStatsD,
+			aggregate:   true,
+			record: func(c *client) {
+				c.count("account_requests", 1, tag{"account", "my.account:1|a,b#c"})
+			},
+			expectedLines: []string{
+				"pbs.account_requests.my_account_1_a_b_c:1|c",
+			},
+		},
+		{
+			description: "zero-count-ignored",
+			flavor:      config.StatsDFlavorDogStatsD,
+			aggregate:   true,
+			record: func(c *client) {
+				c.count("stored_request_cache_performance", 0, tag{"cache_result", "hit"})
+			},
+			expectedLines: nil,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			recorder := &packetRecorder{}
+			c := newTestClient(recorder, test.flavor, test.aggregate)
+
+			test.record(c)
+			c.flush()
+
+			assert.Equal(t, test.expectedLines, recorder.lines())
+		})
+	}
+}
+
+func TestClientPacketSize(t *testing.T) {
+	recorder := &packetRecorder{}
+	c := newClient(recorder, config.StatsDMetrics{
+		Flavor:        config.StatsDFlavorDogStatsD,
+		Aggregation:   true,
+		MaxPacketSize: 20,
+		BufferSize:    1000,
+	})
+
+	c.count("a", 1)
+	c.count("b", 1)
+	c.count("c", 1)
+	c.count("z_very_long_metric_name", 1)
+	c.flush()
+
+	expectedPackets := []string{
+		"a:1|c\nb:1|c\nc:1|c",
+		"z_very_long_metric_name:1|c",
+	}
+	assert.Equal(t, expectedPackets, recorder.packets)
+}
+
+func TestClientFlushesWhenBufferIsFull(t *testing.T) {
+	recorder := &packetRecorder{}
+	c := newClient(recorder, config.StatsDMetrics{
+		Flavor:        config.StatsDFlavorDogStatsD,
+		Aggregation:   true,
+		MaxPacketSize: 1432,
+		BufferSize:    2,
+	})
+	go c.run(time.Hour)
+	defer c.close()
+
+	c.count("requests", 1)
+	c.count("requests", 1)
+
+	assert.Eventually(t, func() bool {
+		return len(recorder.lines()) == 1
+	}, time.Second, time.Millisecond)
+	assert.Equal(t, []string{"requests:2|c"}, recorder.lines())
+}
+
+func TestClientFlushesOnClose(t *testing.T) {
+	recorder := &packetRecorder{}
+	c := newTestClient(recorder, config.StatsDFlavorDogStatsD, true)
+	go c.run(time.Hour)
+
+	c.count("requests", 1)
+	c.close()
+
+	assert.Equal(t, []string{"pbs.requests:1|c"}, recorder.lines())
+}
@@ -0,0 +1,397 @@
+// Package statsd implements a metrics engine which sends metrics to a StatsD or DogStatsD server over UDP.
+package statsd
+
+import (
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prebid/prebid-server/v4/config"
+	"github.com/prebid/prebid-server/v4/metrics"
+	"github.com/prebid/prebid-server/v4/openrtb_ext"
+)
+
+const (
+	accountTag        = "account"
+	adapterErrorTag   = "adapter_error"
+	adapterTag        = "adapter"
+	cacheResultTag    = "cache_result"
+	circuitStateTag   = "circuit_state"
+	connectionTag     = "connection"
+	cookieTag         = "cookie"
+	endpointTag       = "endpoint"
+	fetchTypeTag      = "fetch_type"
+	hasBidsTag        = "has_bids"
+	isAudioTag        = "audio"
+	isBannerTag       = "banner"
+	isNativeTag       = "native"
+	isVideoTag        = "video"
+	markupDeliveryTag = "markup_delivery"
+	moduleTag         = "module"
+	optOutTag         = "opt_out"
+	overheadTypeTag   = "overhead_type"
+	requestStatusTag  = "request_status"
+	requestTypeTag    = "request_type"
+	stageTag          = "stage"
+	statusTag         = "status"
+	storedDataTag     = "stored_data"
+	storedErrorTag    = "error"
+	successTag        = "success"
+	syncerTag         = "syncer"
+	versionTag        = "version"
+)
+
+// Metrics is a metrics.MetricsEngine which sends every recorded metric to a StatsD server. Counters are
+// sent as StatsD counters, durations as timers in milliseconds and other distributions as histograms.
+type Metrics struct {
+	client          *client
+	tags            config.StatsDTags
+	metricsDisabled config.DisabledMetrics
+}
+
+// NewMetrics dials the StatsD server and starts sending the metrics recorded by the returned engine.
+func NewMetrics(cfg config.StatsDMetrics, disabledMetrics config.DisabledMetrics) (*Metrics, error) {
+	conn, err := net.Dial("udp", cfg.Host)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &Metrics{
+		client:          newClient(conn, cfg),
+		tags:            cfg.Tags,
+		metricsDisabled: disabledMetrics,
+	}
+	go m.client.run(time.Duration(cfg.FlushIntervalMS) * time.Millisecond)
+	return m, nil
+}
+
+// Shutdown sends all buffered metrics and stops the engine.
+func (m *Metrics) Shutdown() {
+	m.client.close()
+}
+
+// adapterTags returns the adapter tag, if enabled, followed by the other tags.
+func (m *Metrics) adapterTags(adapterName openrtb_ext.BidderName, tags ...tag) []tag {
+	if !m.tags.Adapter {
+		return tags
+	}
+	return append([]tag{{adapterTag, strings.ToLower(string(adapterName))}}, tags...)
+}
+
+// moduleTags returns the module tag followed by the stage tag, if enabled.
+func (m *Metrics) moduleTags(labels metrics.ModuleLabels) []tag {
+	tags := []tag{{moduleTag, labels.Module}}
+	if m.tags.Stage {
+		tags = append(tags, tag{stageTag, labels.Stage})
+	}
+	return tags
+}
+
+// recordsAccount returns true if metrics for the account are recorded. Account metrics are only
+// recorded if the account tag is enabled, since they would otherwise duplicate the totals.
+func (m *Metrics) recordsAccount(account string) bool {
+	return m.tags.Account && account != metrics.PublisherUnknown
+}
+
+func successValue(success bool) string {
+	return strconv.FormatBool(success)
+}
+
+func (m *Metrics) RecordConnectionAccept(success bool) {
+	if success {
+		m.client.count("connections_opened", 1)
+	} else {
+		m.client.count("connections_error", 1, tag{connectionTag, "accept"})
+	}
+}
+
+func (m *Metrics) RecordTMaxTimeout() {
+	m.client.count("tmax_timeout", 1)
+}
+
+func (m *Metrics) RecordConnectionClose(success bool) {
+	if success {
+		m.client.count("connections_closed", 1)
+	} else {
+		m.client.count("connections_error", 1, tag{connectionTag, "close"})
+	}
+}
+
+func (m *Metrics) RecordRequest(labels metrics.Labels) {
+	m.client.count("requests", 1, tag{requestTypeTag, string(labels.RType)}, tag{requestStatusTag, string(labels.RequestStatus)})
+
+	if labels.RequestSize > 0 && labels.RType != metrics.ReqTypeAMP {
+		endpoint := metrics.GetEndpointFromRequestType(labels.RType)
+		m.client.histogram("request_size_bytes", float64(labels.RequestSize), tag{endpointTag, string(endpoint)})
+	}
+
+	if labels.CookieFlag == metrics.CookieFlagNo {
+		m.client.count("requests_without_cookie", 1, tag{requestTypeTag, string(labels.RType)})
+	}
+
+	if m.recordsAccount(labels.PubID) {
+		m.client.count("account_requests", 1, tag{accountTag, labels.PubID})
+	}
+}
+
+func (m *Metrics) RecordImps(labels metrics.ImpLabels) {
+	m.client.count("impressions_requests", 1,
+		tag{isBannerTag, strconv.FormatBool(labels.BannerImps)},
+		tag{isVideoTag, strconv.FormatBool(labels.VideoImps)},
+		tag{isAudioTag, strconv.FormatBool(labels.AudioImps)},
+		tag{isNativeTag, strconv.FormatBool(labels.NativeImps)})
+}
+
+func (m *Metrics) RecordRequestTime(labels metrics.Labels, length time.Duration) {
+	if labels.RequestStatus == metrics.RequestStatusOK {
+		m.client.timing("request_time", length, tag{requestTypeTag, string(labels.RType)})
+	}
+}
+
+func (m *Metrics) RecordOverheadTime(overhead metrics.OverheadType, length time.Duration) {
+	m.client.timing("overhead_time", length, tag{overheadTypeTag, overhead.String()})
+}
+
+func (m *Metrics) RecordAdapterRequest(labels metrics.AdapterLabels) {
+	m.client.count("adapter_requests", 1, m.adapterTags(labels.Adapter,
+		tag{cookieTag, string(labels.CookieFlag)},
+		tag{hasBidsTag, strconv.FormatBool(labels.AdapterBids == metrics.AdapterBidPresent)})...)
+
+	for err := range labels.AdapterErrors {
+		m.client.count("adapter_errors", 1, m.adapterTags(labels.Adapter, tag{adapterErrorTag, string(err)})...)
+	}
+}
+
+func (m *Metrics) RecordAdapterConnections(adapterName openrtb_ext.BidderName, connWasReused bool, connWaitTime time.Duration) {
+	if m.metricsDisabled.AdapterConnectionMetrics {
+		return
+	}
+
+	if connWasReused {
+		m.client.count("adapter_connection_reused", 1, m.adapterTags(adapterName)...)
+	} else {
+		m.client.count("adapter_connection_created", 1, m.adapterTags(adapterName)...)
+	}
+	m.client.timing("adapter_connection_wait", connWaitTime, m.adapterTags(adapterName)...)
+}
+
+func (m *Metrics) RecordDNSTime(dnsLookupTime time.Duration) {
+	m.client.timing("dns_lookup_time", dnsLookupTime)
+}
+
+func (m *Metrics) RecordTLSHandshakeTime(tlsHandshakeTime time.Duration) {
+	m.client.timing("tls_handshake_time", tlsHandshakeTime)
+}
+
+func (m *Metrics) RecordBidderServerResponseTime(bidderServerResponseTime time.Duration) {
+	m.client.timing("bidder_server_response_time", bidderServerResponseTime)
+}
+
+func (m *Metrics) RecordAdapterPanic(labels metrics.AdapterLabels) {
+	m.client.count("adapter_panics", 1, m.adapterTags(labels.Adapter)...)
+}
+
+func (m *Metrics) RecordAdapterBidReceived(labels metrics.AdapterLabels, bidType openrtb_ext.BidType, hasAdm bool) {
+	markupDelivery := "nurl"
+	if hasAdm {
+		markupDelivery = "adm"
+	}
+	m.client.count("adapter_bids", 1, m.adapterTags(labels.Adapter, tag{markupDeliveryTag, markupDelivery})...)
+}
+
+func (m *Metrics) RecordAdapterPrice(labels metrics.AdapterLabels, cpm float64) {
+	m.client.histogram("adapter_prices", cpm, m.adapterTags(labels.Adapter)...)
+}
+
+func (m *Metrics) RecordAdapterTime(labels metrics.AdapterLabels, length time.Duration) {
+	if len(labels.AdapterErrors) == 0 {
+		m.client.timing("adapter_request_time", length, m.adapterTags(labels.Adapter)...)
+	}
+}
+
+func (m *Metrics) RecordCookieSync(status metrics.CookieSyncStatus) {
+	m.client.count("cookie_sync_requests", 1, tag{statusTag, string(status)})
+}
+
+func (m *Metrics) RecordSyncerRequest(key string, status metrics.SyncerCookieSyncStatus) {
+	m.client.count("syncer_requests", 1, tag{syncerTag, key}, tag{statusTag, string(status)})
+}
+
+func (m *Metrics) RecordSetUid(status metrics.SetUidStatus) {
+	m.client.count("setuid_requests", 1, tag{statusTag, string(status)})
+}
+
+func (m *Metrics) RecordSyncerSet(key string, status metrics.SyncerSetUidStatus) {
+	m.client.count("syncer_sets", 1, tag{syncerTag, key}, tag{statusTag, string(status)})
+}
+
+func (m *Metrics) RecordUIDCookieDecode(status metrics.UIDCookieDecodeStatus) {
+	m.client.count("uid_cookie_decode", 1, tag{statusTag, string(status)})
+}
+
+func (m *Metrics) RecordStoredReqCacheResult(cacheResult metrics.CacheResult, inc int) {
+	m.client.count("stored_request_cache_performance", int64(inc), tag{cacheResultTag, string(cacheResult)})
+}
+
+func (m *Metrics) RecordStoredImpCacheResult(cacheResult metrics.CacheResult, inc int) {
+	m.client.count("stored_impressions_cache_performance", int64(inc), tag{cacheResultTag, string(cacheResult)})
+}
+
+func (m *Metrics) RecordAccountCacheResult(cacheResult metrics.CacheResult, inc int) {
+	m.client.count("account_cache_performance", int64(inc), tag{cacheResultTag, string(cacheResult)})
+}
+
+func (m *Metrics) RecordStoredDataFetchTime(labels metrics.StoredDataLabels, length time.Duration) {
+	m.client.timing("stored_data_fetch_time", length, tag{storedDataTag, string(labels.DataType)}, tag{fetchTypeTag, string(labels.DataFetchType)})
+}
+
+func (m *Metrics) RecordStoredDataError(labels metrics.StoredDataLabels) {
+	m.client.count("stored_data_errors", 1, tag{storedDataTag, string(labels.DataType)}, tag{storedErrorTag, string(labels.Error)})
+}
+
+func (m *Metrics) RecordPrebidCacheRequestTime(success bool, length time.Duration) {
+	m.client.timing("prebidcache_write_time", length, tag{successTag, successValue(success)})
+}
+
+func (m *Metrics) RecordRequestQueueTime(success bool, requestType metrics.RequestType, length time.Duration) {
+	status := "rejected"
+	if success {
+		status = "accepted"
+	}
+	m.client.timing("request_queue_time", length, tag{requestTypeTag, string(requestType)}, tag{requestStatusTag, status})
+}
+
+func (m *Metrics) RecordTimeoutNotice(success bool) {
+	m.client.count("timeout_notification", 1, tag{successTag, successValue(success)})
+}
+
+func (m *Metrics) RecordRequestPrivacy(privacy metrics.PrivacyLabels) {
+	if privacy.CCPAProvided {
+		m.client.count("privacy_ccpa", 1, tag{optOutTag, strconv.FormatBool(privacy.CCPAEnforced)})
+	}
+	if privacy.COPPAEnforced {
+		m.client.count("privacy_coppa", 1)
+	}
+	if privacy.GDPREnforced {
+		m.client.count("privacy_tcf", 1, tag{versionTag, string(privacy.GDPRTCFVersion)})
+	}
+	if privacy.LMTEnforced {
+		m.client.count("privacy_lmt", 1)
+	}
+}
+
+func (m *Metrics) RecordAdapterBuyerUIDScrubbed(adapterName openrtb_ext.BidderName) {
+	if m.metricsDisabled.AdapterBuyerUIDScrubbed {
+		return
+	}
+	m.client.count("adapter_buyeruids_scrubbed", 1, m.adapterTags(adapterName)...)
+}
+
+func (m *Metrics) RecordAdapterGDPRRequestBlocked(adapterName openrtb_ext.BidderName) {
+	if m.metricsDisabled.AdapterGDPRRequestBlocked {
+		return
+	}
+	m.client.count("adapter_gdpr_requests_blocked", 1, m.adapterTags(adapterName)...)
+}
+
+func (m *Metrics) RecordDebugRequest(debugEnabled bool, pubId string) {
+	if !debugEnabled {
+		return
+	}
+	m.client.count("debug_requests", 1)
+	if !m.metricsDisabled.AccountDebug && m.recordsAccount(pubId) {
+		m.client.count("account_debug_requests", 1, tag{accountTag, pubId})
+	}
+}
+
+func (m *Metrics) RecordStoredResponse(pubId string) {
+	m.client.count("stored_responses", 1)
+	if !m.metricsDisabled.AccountStoredResponses && m.recordsAccount(pubId) {
+		m.client.count("account_stored_responses", 1, tag{accountTag, pubId})
+	}
+}
+
+func (m *Metrics) RecordGvlListRequest() {
+	m.client.count("gvl_requests", 1)
+}
+
+func (m *Metrics) RecordLiveGVLFetch(success bool) {
+	m.client.count("live_gvl_fetch", 1, tag{successTag, successValue(success)})
+}
+
+func (m *Metrics) RecordAdsCertReq(success bool) {
+	m.client.count("ads_cert_requests", 1, tag{successTag, successValue(success)})
+}
+
+func (m *Metrics) RecordAdsCertSignTime(adsCertSignTime time.Duration) {
+	m.client.timing("ads_cert_sign_time", adsCertSignTime)
+}
+
+func (m *Metrics) RecordBidValidationCreativeSizeError(adapter openrtb_ext.BidderName, account string) {
+	m.recordBidValidation("response_validation_size_err", adapter, account)
+}
+
+func (m *Metrics) RecordBidValidationCreativeSizeWarn(adapter openrtb_ext.BidderName, account string) {
+	m.recordBidValidation("response_validation_size_warn", adapter, account)
+}
+
+func (m *Metrics) RecordBidValidationSecureMarkupError(adapter openrtb_ext.BidderName, account string) {
+	m.recordBidValidation("response_validation_secure_err", adapter, account)
+}
+
+func (m *Metrics) RecordBidValidationSecureMarkupWarn(adapter openrtb_ext.BidderName, account string) {
+	m.recordBidValidation("response_validation_secure_warn", adapter, account)
+}
+
+func (m *Metrics) recordBidValidation(name string, adapter openrtb_ext.BidderName, account string) {
+	m.client.count("adapter_"+name, 1, m.adapterTags(adapter)...)
+	if !m.metricsDisabled.AccountAdapterDetails && m.recordsAccount(account) {
+		m.client.count("account_"+name, 1, tag{accountTag, account})
+	}
+}
+
+func (m *Metrics) RecordModuleCalled(labels metrics.ModuleLabels, duration time.Duration) {
+	m.client.count("module_calls", 1, m.moduleTags(labels)...)
+	m.client.timing("module_duration", duration, m.moduleTags(labels)...)
+}
+
+func (m *Metrics) RecordModuleFailed(labels metrics.ModuleLabels) {
+	m.client.count("module_failures", 1, m.moduleTags(labels)...)
+}
+
+func (m *Metrics) RecordModuleSuccessNooped(labels metrics.ModuleLabels) {
+	m.client.count("module_success_noops", 1, m.moduleTags(labels)...)
+}
+
+func (m *Metrics) RecordModuleSuccessUpdated(labels metrics.ModuleLabels) {
+	m.client.count("module_success_updates", 1, m.moduleTags(labels)...)
+}
+
+func (m *Metrics) RecordModuleSuccessRejected(labels metrics.ModuleLabels) {
+	m.client.count("module_success_rejects", 1, m.moduleTags(labels)...)
+}
+
+func (m *Metrics) RecordModuleExecutionError(labels metrics.ModuleLabels) {
+	m.client.count("module_execution_errors", 1, m.moduleTags(labels)...)
+}
+
+func (m *Metrics) RecordModuleTimeout(labels metrics.ModuleLabels) {
+	m.client.count("module_timeouts", 1, m.moduleTags(labels)...)
+}
+
+func (m *Metrics) RecordAdapterThrottled(adapterName openrtb_ext.BidderName) {
+	m.client.count("adapter_throttled", 1, m.adapterTags(adapterName)...)
+}
+
+func (m *Metrics) RecordAdapterCircuitBreakerTransition(adapterName openrtb_ext.BidderName, state metrics.CircuitBreakerState) {
+	m.client.count("adapter_circuit_breaker_transitions", 1, m.adapterTags(adapterName, tag{circuitStateTag, string(state)})...)
+}
+
+func (m *Metrics) RecordAdapterConnectionDialError(adapterName openrtb_ext.BidderName) {
+	m.client.count("adapter_connection_dial_errors", 1, m.adapterTags(adapterName)...)
+}
+
+func (m *Metrics) RecordAdapterConnectionDialTime(adapterName openrtb_ext.BidderName, dialStartTime time.Duration) {
+	m.client.timing("adapter_connection_dial_time", dialStartTime, m.adapterTags(adapterName)...)
+}
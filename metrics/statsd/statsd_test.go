@@ -0,0 +1,265 @@
+package statsd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prebid/prebid-server/v4/config"
+	"github.com/prebid/prebid-server/v4/metrics"
+	"github.com/prebid/prebid-server/v4/openrtb_ext"
+	"github.com/stretchr/testify/assert"
+)
+
+func createMetricsForTesting(tags config.StatsDTags, disabledMetrics config.DisabledMetrics) (*Metrics, *packetRecorder) {
+	recorder := &packetRecorder{}
+	return &Metrics{
+		client:          newTestClient(recorder, config.StatsDFlavorDogStatsD, true),
+		tags:            tags,
+		metricsDisabled: disabledMetrics,
+	}, recorder
+}
+
+func TestRecordRequest(t *testing.T) {
+	testCases := []struct {
+		description   string
+		tags          config.StatsDTags
+		labels        metrics.Labels
+		expectedLines []string
+	}{
+		{
+			description: "without-account-tag",
+			tags:        config.StatsDTags{},
+			labels: metrics.Labels{
+				RType:         metrics.ReqTypeORTB2Web,
+				RequestStatus: metrics.RequestStatusOK,
+				PubID:         "acct",
+				CookieFlag:    metrics.CookieFlagNo,
+				RequestSize:   100,
+			},
+			expectedLines: []string{
+				"pbs.request_size_bytes:100|h|#endpoint:auction",
+				"pbs.requests:1|c|#request_type:openrtb2-web,request_status:ok",
+				"pbs.requests_without_cookie:1|c|#request_type:openrtb2-web",
+			},
+		},
+		{
+			description: "with-account-tag",
+			tags:        config.StatsDTags{Account: true},
+			labels: metrics.Labels{
+				RType:         metrics.ReqTypeAMP,
+				RequestStatus: metrics.RequestStatusBadInput,
+				PubID:         "acct",
+				CookieFlag:    metrics.CookieFlagYes,
+				RequestSize:   100,
+			},
+			expectedLines: []string{
+				"pbs.account_requests:1|c|#account:acct",
+				"pbs.requests:1|c|#request_type:amp,request_status:badinput",
+			},
+		},
+		{
+			description: "with-account-tag-unknown-account",
+			tags:        config.StatsDTags{Account: true},
+			labels: metrics.Labels{
+				RType:         metrics.ReqTypeVideo,
+				RequestStatus: metrics.RequestStatusOK,
+				PubID:         metrics.PublisherUnknown,
+				CookieFlag:    metrics.CookieFlagYes,
+			},
+			expectedLines: []string{
+				"pbs.requests:1|c|#request_type:video,request_status:ok",
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			m, recorder := createMetricsForTesting(test.tags, config.DisabledMetrics{})
+
+			m.RecordRequest(test.labels)
+			m.client.flush()
+
+			assert.Equal(t, test.expectedLines, recorder.lines())
+		})
+	}
+}
+
+func TestRecordAdapterRequest(t *testing.T) {
+	labels := metrics.AdapterLabels{
+		Adapter:       "AppNexus",
+		CookieFlag:    metrics.CookieFlagYes,
+		AdapterBids:   metrics.AdapterBidPresent,
+		AdapterErrors: map[metrics.AdapterError]struct{}{metrics.AdapterErrorTimeout: {}},
+	}
+
+	testCases := []struct {
+		description   string
+		tags          config.StatsDTags
+		expectedLines []string
+	}{
+		{
+			description: "with-adapter-tag",
+			tags:        config.StatsDTags{Adapter: true},
+			expectedLines: []string{
+				"pbs.adapter_errors:1|c|#adapter:appnexus,adapter_error:timeout",
+				"pbs.adapter_requests:1|c|#adapter:appnexus,cookie:exists,has_bids:true",
+			},
+		},
+		{
+			description: "without-adapter-tag",
+			tags:        config.StatsDTags{},
+			expectedLines: []string{
+				"pbs.adapter_errors:1|c|#adapter_error:timeout",
+				"pbs.adapter_requests:1|c|#cookie:exists,has_bids:true",
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			m, recorder := createMetricsForTesting(test.tags, config.DisabledMetrics{})
+
+			m.RecordAdapterRequest(labels)
+			m.client.flush()
+
+			assert.Equal(t, test.expectedLines, recorder.lines())
+		})
+	}
+}
+
+func TestRecordModuleCalled(t *testing.T) {
+	labels := metrics.ModuleLabels{Module: "foobar", Stage: "entrypoint", AccountID: "acct"}
+
+	testCases := []struct {
+		description   string
+		tags          config.StatsDTags
+		expectedLines []string
+	}{
+		{
+			description: "with-stage-tag",
+			tags:        config.StatsDTags{Stage: true},
+			expectedLines: []string{
+				"pbs.module_calls:1|c|#module:foobar,stage:entrypoint",
+				"pbs.module_duration:5|ms|#module:foobar,stage:entrypoint",
+			},
+		},
+		{
+			description: "without-stage-tag",
+			tags:        config.StatsDTags{},
+			expectedLines: []string{
+				"pbs.module_calls:1|c|#module:foobar",
+				"pbs.module_duration:5|ms|#module:foobar",
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			m, recorder := createMetricsForTesting(test.tags, config.DisabledMetrics{})
+
+			m.RecordModuleCalled(labels, 5*time.Millisecond)
+			m.client.flush()
+
+			assert.Equal(t, test.expectedLines, recorder.lines())
+		})
+	}
+}
+
+func TestRecordAccountMetricsDisabled(t *testing.T) {
+	testCases := []struct {
+		description     string
+		disabledMetrics config.DisabledMetrics
+		expectedLines   []string
+	}{
+		{
+			description:     "enabled",
+			disabledMetrics: config.DisabledMetrics{},
+			expectedLines: []string{
+				"pbs.account_debug_requests:1|c|#account:acct",
+				"pbs.account_response_validation_size_err:1|c|#account:acct",
+				"pbs.account_stored_responses:1|c|#account:acct",
+				"pbs.adapter_response_validation_size_err:1|c|#adapter:appnexus",
+				"pbs.debug_requests:1|c",
+				"pbs.stored_responses:1|c",
+			},
+		},
+		{
+			description: "disabled",
+			disabledMetrics: config.DisabledMetrics{
+				AccountAdapterDetails:  true,
+				AccountDebug:           true,
+				AccountStoredResponses: true,
+			},
+			expectedLines: []string{
+				"pbs.adapter_response_validation_size_err:1|c|#adapter:appnexus",
+				"pbs.debug_requests:1|c",
+				"pbs.stored_responses:1|c",
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			m, recorder := createMetricsForTesting(config.StatsDTags{Adapter: true, Account: true}, test.disabledMetrics)
+
+			m.RecordDebugRequest(true, "acct")
+			m.RecordStoredResponse("acct")
+			m.RecordBidValidationCreativeSizeError(openrtb_ext.BidderAppnexus, "acct")
+			m.client.flush()
+
+			assert.Equal(t, test.expectedLines, recorder.lines())
+		})
+	}
+}
+
+func TestRecordAdapterConnections(t *testing.T) {
+	testCases := []struct {
+		description     string
+		disabledMetrics config.DisabledMetrics
+		reused          bool
+		expectedLines   []string
+	}{
+		{
+			description: "created",
+			reused:      false,
+			expectedLines: []string{
+				"pbs.adapter_connection_created:1|c|#adapter:appnexus",
+				"pbs.adapter_connection_wait:2|ms|#adapter:appnexus",
+			},
+		},
+		{
+			description: "reused",
+			reused:      true,
+			expectedLines: []string{
+				"pbs.adapter_connection_reused:1|c|#adapter:appnexus",
+				"pbs.adapter_connection_wait:2|ms|#adapter:appnexus",
+			},
+		},
+		{
+			description:     "disabled",
+			disabledMetrics: config.DisabledMetrics{AdapterConnectionMetrics: true},
+			reused:          true,
+			expectedLines:   nil,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			m, recorder := createMetricsForTesting(config.StatsDTags{Adapter: true}, test.disabledMetrics)
+
+			m.RecordAdapterConnections(openrtb_ext.BidderAppnexus, test.reused, 2*time.Millisecond)
+			m.client.flush()
+
+			assert.Equal(t, test.expectedLines, recorder.lines())
+		})
+	}
+}
+
+func TestRecordAdapterCircuitBreakerTransition(t *testing.T) {
+	m, recorder := createMetricsForTesting(config.StatsDTags{Adapter: true}, config.DisabledMetrics{})
+
+	m.RecordAdapterCircuitBreakerTransition(openrtb_ext.BidderAppnexus, metrics.CircuitBreakerOpen)
+	m.client.flush()
+
+	assert.Equal(t, []string{"pbs.adapter_circuit_breaker_transitions:1|c|#adapter:appnexus,circuit_state:open"}, recorder.lines())
+}
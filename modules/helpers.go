@@ -63,6 +63,24 @@ func createModuleStageNamesCollection(modules map[string]interface{}) (map[strin
 			moduleStageNameCollector = addModuleStageName(moduleStageNameCollector, id, stageName)
 		}
 
+		if _, ok := hook.(hookstage.CookieSyncRequest); ok {
+			added = true
+			stageName := hooks.StageCookieSyncRequest.String()
+			moduleStageNameCollector = addModuleStageName(moduleStageNameCollector, id, stageName)
+		}
+
+		if _, ok := hook.(hookstage.SetUIDRequest); ok {
+			added = true
+			stageName := hooks.StageSetUIDRequest.String()
+			moduleStageNameCollector = addModuleStageName(moduleStageNameCollector, id, stageName)
+		}
+
+		if _, ok := hook.(hookstage.EventNotification); ok {
+			added = true
+			stageName := hooks.StageEventNotification.String()
+			moduleStageNameCollector = addModuleStageName(moduleStageNameCollector, id, stageName)
+		}
+
 		if !added {
 			return nil, fmt.Errorf(`hook "%s" does not implement any supported hook interface`, id)
 		}
@@ -35,6 +35,8 @@ type ExtBidPrebidFloors struct {
 	FloorRuleValue float64 `json:"floorRuleValue,omitempty"`
 	FloorValue     float64 `json:"floorValue,omitempty"`
 	FloorCurrency  string  `json:"floorCurrency,omitempty"`
+	// BidderFloorValue is the floor after the bidder adjustment, which the bid is enforced against
+	BidderFloorValue float64 `json:"bidderFloorValue,omitempty"`
 }
 
 // ExtBidPrebidCache defines the contract for  bidresponse.seatbid.bid[i].ext.prebid.cache
@@ -114,6 +114,7 @@ func (mg PriceFloorModelGroup) Copy() PriceFloorModelGroup {
 	}
 
 	newMg.Schema.Delimiter = mg.Schema.Delimiter
+	newMg.Schema.TimeZone = mg.Schema.TimeZone
 	newMg.Schema.Fields = make([]string, len(mg.Schema.Fields))
 	copy(newMg.Schema.Fields, mg.Schema.Fields)
 	newMg.Values = make(map[string]float64, len(mg.Values))
@@ -126,6 +127,7 @@ func (mg PriceFloorModelGroup) Copy() PriceFloorModelGroup {
 type PriceFloorSchema struct {
 	Fields    []string `json:"fields,omitempty"`
 	Delimiter string   `json:"delimiter,omitempty"`
+	TimeZone  string   `json:"timezone,omitempty"`
 }
 
 type PriceFloorEnforcement struct {
@@ -188,6 +190,7 @@ func (data *PriceFloorData) DeepCopy() *PriceFloorData {
 		eachGroup.Schema = PriceFloorSchema{
 			Fields:    slices.Clone(data.ModelGroups[i].Schema.Fields),
 			Delimiter: data.ModelGroups[i].Schema.Delimiter,
+			TimeZone:  data.ModelGroups[i].Schema.TimeZone,
 		}
 		newModelGroups[i] = eachGroup
 	}
@@ -74,6 +74,9 @@ type ExtImpPrebidFloors struct {
 // ExtStoredRequest defines the contract for bidrequest.imp[i].ext.prebid.storedrequest
 type ExtStoredRequest struct {
 	ID string `json:"id"`
+	// Chain holds the IDs of the Stored Requests (or Stored Imps) which the stored data was resolved from,
+	// depth first in the order they're extended.
+	Chain []string `json:"chain,omitempty"`
 }
 
 // ExtStoredAuctionResponse defines the contract for bidrequest.imp[i].ext.prebid.storedauctionresponse
@@ -88,8 +88,8 @@ func TestExtRegsDSAClone(t *testing.T) {
 				if tt.extRegsDSA.DataToPub != nil {
 					assert.NotSame(t, tt.extRegsDSA.DataToPub, clone.DataToPub)
 				}
-				if tt.extRegsDSA.Transparency != nil {
-					assert.NotSame(t, tt.extRegsDSA.Transparency, clone.Transparency)
+				if len(tt.extRegsDSA.Transparency) > 0 {
+					assert.NotSame(t, &tt.extRegsDSA.Transparency[0], &clone.Transparency[0])
 				}
 			}
 		})
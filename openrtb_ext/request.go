@@ -63,6 +63,7 @@ type ExtRequestPrebid struct {
 	MultiBidMap          map[string]ExtMultiBid          `json:"-"`
 	Passthrough          json.RawMessage                 `json:"passthrough,omitempty"`
 	SChains              []*ExtRequestPrebidSChain       `json:"schains,omitempty"`
+	SecondaryBidders     []string                        `json:"secondarybidders,omitempty"`
 	Sdk                  *ExtRequestSdk                  `json:"sdk,omitempty"`
 	Server               *ExtRequestPrebidServer         `json:"server,omitempty"`
 	StoredRequest        *ExtStoredRequest               `json:"storedrequest,omitempty"`
@@ -514,6 +515,7 @@ func (erp *ExtRequestPrebid) Clone() *ExtRequestPrebid {
 	}
 
 	clone.NoSale = slices.Clone(erp.NoSale)
+	clone.SecondaryBidders = slices.Clone(erp.SecondaryBidders)
 
 	if erp.AlternateBidderCodes != nil {
 		newAlternateBidderCodes := ExtAlternateBidderCodes{Enabled: erp.AlternateBidderCodes.Enabled}
@@ -568,6 +568,18 @@ func TestCloneExtRequestPrebid(t *testing.T) {
 				prebid.NoSale = append(prebid.NoSale, "D")
 			},
 		},
+		{
+			name: "SecondaryBidders",
+			prebid: &ExtRequestPrebid{
+				SecondaryBidders: []string{"A", "B"},
+			},
+			prebidCopy: &ExtRequestPrebid{
+				SecondaryBidders: []string{"A", "B"},
+			},
+			mutator: func(t *testing.T, prebid *ExtRequestPrebid) {
+				prebid.SecondaryBidders[1] = "G"
+			},
+		},
 		{
 			name: "AlternateBidderCodes",
 			prebid: &ExtRequestPrebid{
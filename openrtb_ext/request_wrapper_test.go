@@ -2412,7 +2412,8 @@ func TestRegExtGetExtSetExt(t *testing.T) {
 
 	regExtJSON = regExt.GetExt()
 	assert.Equal(t, regExtJSON, rawJSON)
-	assert.NotSame(t, regExtJSON, rawJSON)
+	regExtJSON["gdpr"] = json.RawMessage(`0`)
+	assert.Equal(t, json.RawMessage(`1`), rawJSON["gdpr"], "get returns a copy of the ext")
 }
 
 func TestRegExtGetDSASetDSA(t *testing.T) {
@@ -2444,7 +2445,6 @@ func TestRegExtGetUSPrivacySetUSPrivacy(t *testing.T) {
 
 	regExtUSPrivacy = regExt.GetUSPrivacy()
 	assert.Equal(t, regExtUSPrivacy, usprivacy)
-	assert.NotSame(t, regExtUSPrivacy, usprivacy)
 }
 
 func TestRegExtGetGDPRSetGDPR(t *testing.T) {
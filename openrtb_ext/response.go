@@ -117,8 +117,9 @@ type NonBidObject struct {
 	MType   openrtb2.MarkupType     `json:"mtype,omitempty"`
 
 	// Custom Fields
-	OriginalBidCPM float64 `json:"origbidcpm,omitempty"`
-	OriginalBidCur string  `json:"origbidcur,omitempty"`
+	OriginalBidCPM float64             `json:"origbidcpm,omitempty"`
+	OriginalBidCur string              `json:"origbidcur,omitempty"`
+	Floors         *ExtBidPrebidFloors `json:"floors,omitempty"`
 }
 
 // ExtResponseNonBidPrebid represents bidresponse.ext.prebid.seatnonbid[].nonbid[].ext
@@ -13,7 +13,9 @@ import (
 	"github.com/julienschmidt/httprouter"
 	"github.com/prebid/prebid-server/v4/config"
 	"github.com/prebid/prebid-server/v4/logger"
+	"github.com/prebid/prebid-server/v4/metrics"
 	"github.com/prebid/prebid-server/v4/usersync"
+	"github.com/prebid/prebid-server/v4/util/uuidutil"
 )
 
 // Recaptcha code from https://github.com/haisum/recaptcha/blob/master/recaptcha.go
@@ -25,6 +27,8 @@ type UserSyncDeps struct {
 	HostCookieConfig *config.HostCookie
 	PriorityGroups   [][]string
 	CertPool         *x509.CertPool
+	UIDStore         usersync.UIDStore
+	MetricsEngine    metrics.MetricsEngine
 }
 
 // Struct for parsing json in google's response
@@ -69,8 +73,8 @@ func (deps *UserSyncDeps) VerifyRecaptcha(response string) error {
 func (deps *UserSyncDeps) OptOut(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
 	optout := r.FormValue("optout")
 	rr := r.FormValue("g-recaptcha-response")
-	encoder := usersync.Base64Encoder{}
-	decoder := usersync.Base64Decoder{}
+	encoder := usersync.NewEncoder(deps.HostCookieConfig)
+	decoder := usersync.NewDecoder(deps.HostCookieConfig, deps.MetricsEngine)
 
 	if rr == "" {
 		http.Redirect(w, r, fmt.Sprintf("%s/static/optout.html", deps.ExternalUrl), http.StatusMovedPermanently)
@@ -84,13 +88,30 @@ func (deps *UserSyncDeps) OptOut(w http.ResponseWriter, r *http.Request, _ httpr
 		return
 	}
 
-	// Read Cookie
+	// Read Cookie. The stored UIDs are only needed to opt back in, since they're deleted on opt out.
 	pc := usersync.ReadCookie(r, decoder, deps.HostCookieConfig)
+	if optout == "" {
+		if err := pc.LoadStoredUIDs(r.Context(), deps.UIDStore); err != nil {
+			logger.Warnf("Opt Out failed to load the uids of the user: %v", err)
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+	}
 	usersync.SyncHostCookie(r, pc, deps.HostCookieConfig)
 	pc.SetOptOut(optout != "")
 
 	// Write Cookie
-	encodedCookie, err := encoder.Encode(pc)
+	var encodedCookie string
+	if deps.UIDStore != nil {
+		encodedCookie, err = pc.PrepareCookieForStore(r.Context(), deps.UIDStore, encoder, uuidutil.UUIDRandomGenerator{})
+		if err != nil && optout != "" {
+			// the cookie doesn't carry the user key anymore, so the UIDs left in the store expire unused
+			logger.Warnf("Opt Out failed to delete the uids of the user: %v", err)
+			encodedCookie, err = encoder.Encode(pc)
+		}
+	} else {
+		encodedCookie, err = encoder.Encode(pc)
+	}
 	if err != nil {
 		w.WriteHeader(http.StatusBadRequest)
 		return
@@ -15,8 +15,10 @@ import (
 	"github.com/prebid/prebid-server/v4/config"
 	"github.com/prebid/prebid-server/v4/logger"
 	"github.com/prebid/prebid-server/v4/metrics"
+	"github.com/prebid/prebid-server/v4/tracing"
 
 	"github.com/buger/jsonparser"
+	"go.opentelemetry.io/otel/trace"
 	"golang.org/x/net/context/ctxhttp"
 )
 
@@ -90,6 +92,12 @@ func (c *clientImpl) PutJson(ctx context.Context, values []Cacheable) (uuids []s
 		return nil, errs
 	}
 
+	ctx, span := tracing.Start(ctx, "prebid_cache.put", trace.WithSpanKind(trace.SpanKindClient))
+	defer func() {
+		tracing.RecordErrors(span, errs...)
+		span.End()
+	}()
+
 	uuidsToReturn := make([]string, len(values))
 
 	postBody, err := encodeValues(values)
@@ -1,6 +1,9 @@
 package privacy
 
 import (
+	"strings"
+
+	gpplib "github.com/prebid/go-gpp"
 	"github.com/prebid/prebid-server/v4/config"
 	"github.com/prebid/prebid-server/v4/openrtb_ext"
 )
@@ -23,9 +26,17 @@ func NewRequestFromBidRequest(r openrtb_ext.RequestWrapper) ActivityRequest {
 	return ActivityRequest{bidRequest: &r}
 }
 
+// NewRequestFromBidRequestWithGPP returns the request of a bid request whose GPP string was already parsed, so
+// that the rules don't parse it again for every activity.
+func NewRequestFromBidRequestWithGPP(r openrtb_ext.RequestWrapper, gpp gpplib.GppContainer) ActivityRequest {
+	return ActivityRequest{bidRequest: &r, gpp: &gpp}
+}
+
 type ActivityRequest struct {
 	policies   *Policies
 	bidRequest *openrtb_ext.RequestWrapper
+	// gpp is the parsed GPP string of the bid request, if known
+	gpp *gpplib.GppContainer
 }
 
 func (r ActivityRequest) IsPolicies() bool {
@@ -50,14 +61,14 @@ func NewActivityControl(cfg *config.AccountPrivacy) ActivityControl {
 	}
 
 	plans := make(map[Activity]ActivityPlan, 8)
-	plans[ActivitySyncUser] = buildPlan(cfg.AllowActivities.SyncUser)
-	plans[ActivityFetchBids] = buildPlan(cfg.AllowActivities.FetchBids)
-	plans[ActivityEnrichUserFPD] = buildPlan(cfg.AllowActivities.EnrichUserFPD)
-	plans[ActivityReportAnalytics] = buildPlan(cfg.AllowActivities.ReportAnalytics)
-	plans[ActivityTransmitUserFPD] = buildPlan(cfg.AllowActivities.TransmitUserFPD)
-	plans[ActivityTransmitPreciseGeo] = buildPlan(cfg.AllowActivities.TransmitPreciseGeo)
-	plans[ActivityTransmitUniqueRequestIDs] = buildPlan(cfg.AllowActivities.TransmitUniqueRequestIds)
-	plans[ActivityTransmitTIDs] = buildPlan(cfg.AllowActivities.TransmitTids)
+	plans[ActivitySyncUser] = buildPlan(ActivitySyncUser, cfg.AllowActivities.SyncUser, cfg.Modules)
+	plans[ActivityFetchBids] = buildPlan(ActivityFetchBids, cfg.AllowActivities.FetchBids, cfg.Modules)
+	plans[ActivityEnrichUserFPD] = buildPlan(ActivityEnrichUserFPD, cfg.AllowActivities.EnrichUserFPD, cfg.Modules)
+	plans[ActivityReportAnalytics] = buildPlan(ActivityReportAnalytics, cfg.AllowActivities.ReportAnalytics, cfg.Modules)
+	plans[ActivityTransmitUserFPD] = buildPlan(ActivityTransmitUserFPD, cfg.AllowActivities.TransmitUserFPD, cfg.Modules)
+	plans[ActivityTransmitPreciseGeo] = buildPlan(ActivityTransmitPreciseGeo, cfg.AllowActivities.TransmitPreciseGeo, cfg.Modules)
+	plans[ActivityTransmitUniqueRequestIDs] = buildPlan(ActivityTransmitUniqueRequestIDs, cfg.AllowActivities.TransmitUniqueRequestIds, cfg.Modules)
+	plans[ActivityTransmitTIDs] = buildPlan(ActivityTransmitTIDs, cfg.AllowActivities.TransmitTids, cfg.Modules)
 	ac.plans = plans
 
 	ac.IPv4Config = cfg.IPv4Config
@@ -66,17 +77,22 @@ func NewActivityControl(cfg *config.AccountPrivacy) ActivityControl {
 	return ac
 }
 
-func buildPlan(activity config.Activity) ActivityPlan {
+func buildPlan(a Activity, activity config.Activity, modules []config.PrivacyModule) ActivityPlan {
 	return ActivityPlan{
-		rules:         cfgToRules(activity.Rules),
+		rules:         cfgToRules(a, activity.Rules, modules),
 		defaultResult: cfgToDefaultResult(activity.Default),
 	}
 }
 
-func cfgToRules(rules []config.ActivityRule) []Rule {
+func cfgToRules(a Activity, rules []config.ActivityRule, modules []config.PrivacyModule) []Rule {
 	var enfRules []Rule
 
 	for _, r := range rules {
+		if len(r.PrivacyReg) > 0 {
+			enfRules = append(enfRules, cfgToPrivacyRegRules(a, r, modules)...)
+			continue
+		}
+
 		result := ActivityDeny
 		if r.Allow {
 			result = ActivityAllow
@@ -92,6 +108,49 @@ func cfgToRules(rules []config.ActivityRule) []Rule {
 	return enfRules
 }
 
+// cfgToPrivacyRegRules builds a rule for each enabled privacy module referenced by the rule's
+// privacyreg field. Modules which are not configured for the account are enabled with defaults.
+func cfgToPrivacyRegRules(a Activity, rule config.ActivityRule, modules []config.PrivacyModule) []Rule {
+	var enfRules []Rule
+
+	for _, code := range privacyModuleCodes {
+		if !privacyRegMatches(rule.PrivacyReg, code) {
+			continue
+		}
+
+		module, found := findPrivacyModule(modules, code)
+		if found && module.Enabled != nil && !*module.Enabled {
+			continue
+		}
+
+		enfRules = append(enfRules, GPPUSRule{
+			activity:      a,
+			componentName: rule.Condition.ComponentName,
+			componentType: rule.Condition.ComponentType,
+			skipSIDs:      module.SkipSIDs,
+		})
+	}
+	return enfRules
+}
+
+func privacyRegMatches(privacyReg []string, code string) bool {
+	for _, r := range privacyReg {
+		if r == PrivacyModuleWildcard || strings.EqualFold(r, code) {
+			return true
+		}
+	}
+	return false
+}
+
+func findPrivacyModule(modules []config.PrivacyModule, code string) (config.PrivacyModule, bool) {
+	for _, m := range modules {
+		if strings.EqualFold(m.Code, code) {
+			return m, true
+		}
+	}
+	return config.PrivacyModule{}, false
+}
+
 func cfgToDefaultResult(activityDefault *bool) bool {
 	if activityDefault == nil {
 		return defaultActivityResult
@@ -61,6 +61,84 @@ func TestNewActivityControl(t *testing.T) {
 	}
 }
 
+func TestCfgToRulesPrivacyReg(t *testing.T) {
+	testCases := []struct {
+		name          string
+		rules         []config.ActivityRule
+		modules       []config.PrivacyModule
+		expectedRules []Rule
+	}{
+		{
+			name: "us_general",
+			rules: []config.ActivityRule{
+				{PrivacyReg: []string{"iab.usgeneral"}},
+			},
+			expectedRules: []Rule{
+				GPPUSRule{activity: ActivitySyncUser},
+			},
+		},
+		{
+			name: "wildcard_with_condition",
+			rules: []config.ActivityRule{
+				{
+					PrivacyReg: []string{"*"},
+					Condition:  config.ActivityCondition{ComponentName: []string{"bidderA"}, ComponentType: []string{"bidder"}},
+				},
+			},
+			expectedRules: []Rule{
+				GPPUSRule{activity: ActivitySyncUser, componentName: []string{"bidderA"}, componentType: []string{"bidder"}},
+			},
+		},
+		{
+			name: "module_config",
+			rules: []config.ActivityRule{
+				{PrivacyReg: []string{"iab.usgeneral"}},
+			},
+			modules: []config.PrivacyModule{
+				{Code: "iab.usgeneral", Enabled: ptrutil.ToPtr(true), SkipSIDs: []int8{8}},
+			},
+			expectedRules: []Rule{
+				GPPUSRule{activity: ActivitySyncUser, skipSIDs: []int8{8}},
+			},
+		},
+		{
+			name: "module_disabled",
+			rules: []config.ActivityRule{
+				{PrivacyReg: []string{"iab.usgeneral"}},
+			},
+			modules: []config.PrivacyModule{
+				{Code: "iab.usgeneral", Enabled: ptrutil.ToPtr(false)},
+			},
+			expectedRules: nil,
+		},
+		{
+			name: "unknown_module",
+			rules: []config.ActivityRule{
+				{PrivacyReg: []string{"iab.unknown"}},
+			},
+			expectedRules: nil,
+		},
+		{
+			name: "mixed_with_condition_rule",
+			rules: []config.ActivityRule{
+				{Allow: true, Condition: config.ActivityCondition{ComponentName: []string{"bidderA"}}},
+				{PrivacyReg: []string{"iab.usgeneral"}},
+			},
+			expectedRules: []Rule{
+				ConditionRule{result: ActivityAllow, componentName: []string{"bidderA"}},
+				GPPUSRule{activity: ActivitySyncUser},
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			actualRules := cfgToRules(ActivitySyncUser, test.rules, test.modules)
+			assert.Equal(t, test.expectedRules, actualRules)
+		})
+	}
+}
+
 func TestCfgToDefaultResult(t *testing.T) {
 	testCases := []struct {
 		name            string
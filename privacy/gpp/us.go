@@ -0,0 +1,242 @@
+package gpp
+
+import (
+	gpplib "github.com/prebid/go-gpp"
+	gppConstants "github.com/prebid/go-gpp/constants"
+	"github.com/prebid/go-gpp/sections"
+	"github.com/prebid/go-gpp/sections/uspca"
+	"github.com/prebid/go-gpp/sections/uspco"
+	"github.com/prebid/go-gpp/sections/uspct"
+	"github.com/prebid/go-gpp/sections/uspnat"
+	"github.com/prebid/go-gpp/sections/usput"
+	"github.com/prebid/go-gpp/sections/uspva"
+)
+
+// Values shared by the notice, opt-out and consent fields of the IAB US National and US state sections.
+const (
+	usNotApplicable byte = 0
+	usYes           byte = 1
+	usNo            byte = 2
+)
+
+// noPreciseGeoIndex marks a section which does not define a precise geolocation sensitive data category.
+const noPreciseGeoIndex = -1
+
+// USSectionIDs lists the IAB US National and US state sections in the order in which they are evaluated.
+var USSectionIDs = []gppConstants.SectionID{
+	gppConstants.SectionUSPNAT,
+	gppConstants.SectionUSPCA,
+	gppConstants.SectionUSPVA,
+	gppConstants.SectionUSPCO,
+	gppConstants.SectionUSPUT,
+	gppConstants.SectionUSPCT,
+}
+
+// USConsent is a normalized view of an IAB US National or US state GPP section. Fields which are not
+// defined by a particular section are left as not applicable.
+type USConsent struct {
+	SectionID                           gppConstants.SectionID
+	GPC                                 bool
+	SharingNotice                       byte
+	SaleOptOutNotice                    byte
+	SharingOptOutNotice                 byte
+	TargetedAdvertisingOptOutNotice     byte
+	SensitiveDataProcessingOptOutNotice byte
+	SensitiveDataLimitUseNotice         byte
+	SaleOptOut                          byte
+	SharingOptOut                       byte
+	TargetedAdvertisingOptOut           byte
+	SensitiveDataProcessing             []byte
+	KnownChildSensitiveDataConsents     []byte
+	PersonalDataConsents                byte
+	MspaServiceProviderMode             byte
+
+	preciseGeoIndex int
+}
+
+// NewUSConsent normalizes a parsed GPP section. Returns false if the section is not one of the
+// supported IAB US National or US state sections, or if it failed to parse.
+func NewUSConsent(section gpplib.Section) (USConsent, bool) {
+	if section == nil || section.GetID() == 0 {
+		return USConsent{}, false
+	}
+
+	switch s := section.(type) {
+	case uspnat.USPNAT:
+		c := s.CoreSegment
+		return USConsent{
+			SectionID:                           gppConstants.SectionUSPNAT,
+			GPC:                                 s.GPCSegment.Gpc,
+			SharingNotice:                       c.SharingNotice,
+			SaleOptOutNotice:                    c.SaleOptOutNotice,
+			SharingOptOutNotice:                 c.SharingOptOutNotice,
+			TargetedAdvertisingOptOutNotice:     c.TargetedAdvertisingOptOutNotice,
+			SensitiveDataProcessingOptOutNotice: c.SensitiveDataProcessingOptOutNotice,
+			SensitiveDataLimitUseNotice:         c.SensitiveDataLimitUseNotice,
+			SaleOptOut:                          c.SaleOptOut,
+			SharingOptOut:                       c.SharingOptOut,
+			TargetedAdvertisingOptOut:           c.TargetedAdvertisingOptOut,
+			SensitiveDataProcessing:             c.SensitiveDataProcessing,
+			KnownChildSensitiveDataConsents:     c.KnownChildSensitiveDataConsents,
+			PersonalDataConsents:                c.PersonalDataConsents,
+			MspaServiceProviderMode:             c.MspaServiceProviderMode,
+			preciseGeoIndex:                     7,
+		}, true
+	case uspca.USPCA:
+		c := s.CoreSegment
+		return USConsent{
+			SectionID:                       gppConstants.SectionUSPCA,
+			GPC:                             s.GPCSegment.Gpc,
+			SaleOptOutNotice:                c.SaleOptOutNotice,
+			SharingOptOutNotice:             c.SharingOptOutNotice,
+			SensitiveDataLimitUseNotice:     c.SensitiveDataLimitUseNotice,
+			SaleOptOut:                      c.SaleOptOut,
+			SharingOptOut:                   c.SharingOptOut,
+			SensitiveDataProcessing:         c.SensitiveDataProcessing,
+			KnownChildSensitiveDataConsents: c.KnownChildSensitiveDataConsents,
+			PersonalDataConsents:            c.PersonalDataConsents,
+			MspaServiceProviderMode:         c.MspaServiceProviderMode,
+			preciseGeoIndex:                 2,
+		}, true
+	case uspva.USPVA:
+		return newUSConsentFromCommon(gppConstants.SectionUSPVA, s.CoreSegment, sections.CommonUSGPCSegment{}, 7), true
+	case uspco.USPCO:
+		return newUSConsentFromCommon(gppConstants.SectionUSPCO, s.CoreSegment, s.GPCSegment, noPreciseGeoIndex), true
+	case uspct.USPCT:
+		return newUSConsentFromCommon(gppConstants.SectionUSPCT, s.CoreSegment, s.GPCSegment, 7), true
+	case usput.USPUT:
+		c := s.CoreSegment
+		return USConsent{
+			SectionID:                           gppConstants.SectionUSPUT,
+			SharingNotice:                       c.SharingNotice,
+			SaleOptOutNotice:                    c.SaleOptOutNotice,
+			TargetedAdvertisingOptOutNotice:     c.TargetedAdvertisingOptOutNotice,
+			SensitiveDataProcessingOptOutNotice: c.SensitiveDataProcessingOptOutNotice,
+			SaleOptOut:                          c.SaleOptOut,
+			TargetedAdvertisingOptOut:           c.TargetedAdvertisingOptOut,
+			SensitiveDataProcessing:             c.SensitiveDataProcessing,
+			KnownChildSensitiveDataConsents:     []byte{c.KnownChildSensitiveDataConsents},
+			MspaServiceProviderMode:             c.MspaServiceProviderMode,
+			preciseGeoIndex:                     7,
+		}, true
+	}
+	return USConsent{}, false
+}
+
+func newUSConsentFromCommon(sid gppConstants.SectionID, c sections.CommonUSCoreSegment, gpc sections.CommonUSGPCSegment, preciseGeoIndex int) USConsent {
+	return USConsent{
+		SectionID:                       sid,
+		GPC:                             gpc.Gpc,
+		SharingNotice:                   c.SharingNotice,
+		SaleOptOutNotice:                c.SaleOptOutNotice,
+		TargetedAdvertisingOptOutNotice: c.TargetedAdvertisingOptOutNotice,
+		SaleOptOut:                      c.SaleOptOut,
+		TargetedAdvertisingOptOut:       c.TargetedAdvertisingOptOut,
+		SensitiveDataProcessing:         c.SensitiveDataProcessing,
+		KnownChildSensitiveDataConsents: c.KnownChildSensitiveDataConsents,
+		MspaServiceProviderMode:         c.MspaServiceProviderMode,
+		preciseGeoIndex:                 preciseGeoIndex,
+	}
+}
+
+// ReadUSConsents returns the normalized IAB US National and US state sections present in the gpp
+// container which are listed as applicable in gppSIDs and are not listed in skipSIDs.
+func ReadUSConsents(gpp gpplib.GppContainer, gppSIDs []int8, skipSIDs []int8) []USConsent {
+	var consents []USConsent
+
+	for _, sid := range USSectionIDs {
+		if !IsSIDInList(gppSIDs, sid) || IsSIDInList(skipSIDs, sid) {
+			continue
+		}
+
+		i := IndexOfSID(gpp, sid)
+		if i < 0 || i >= len(gpp.Sections) {
+			continue
+		}
+
+		if consent, ok := NewUSConsent(gpp.Sections[i]); ok {
+			consents = append(consents, consent)
+		}
+	}
+
+	return consents
+}
+
+// SaleOrSharingOptedOut returns true if the user opted out of the sale or sharing of their personal
+// data, or if the publisher did not provide the corresponding notice.
+func (c USConsent) SaleOrSharingOptedOut() bool {
+	return isOptedOut(c.SaleOptOutNotice, c.SaleOptOut) ||
+		isOptedOut(c.SharingOptOutNotice, c.SharingOptOut) ||
+		c.SharingNotice == usNo
+}
+
+// TargetedAdvertisingOptedOut returns true if the user opted out of targeted advertising, or if the
+// publisher did not provide the corresponding notice.
+func (c USConsent) TargetedAdvertisingOptedOut() bool {
+	return isOptedOut(c.TargetedAdvertisingOptOutNotice, c.TargetedAdvertisingOptOut)
+}
+
+// SensitiveDataRestricted returns true if the processing of any sensitive data category other than
+// precise geolocation is not permitted.
+func (c USConsent) SensitiveDataRestricted() bool {
+	if c.sensitiveDataNoticeMissing() {
+		return true
+	}
+
+	for i, v := range c.SensitiveDataProcessing {
+		if i != c.preciseGeoIndex && v == usYes {
+			return true
+		}
+	}
+	return false
+}
+
+// PreciseGeoRestricted returns true if the processing of precise geolocation data is not permitted.
+func (c USConsent) PreciseGeoRestricted() bool {
+	if c.sensitiveDataNoticeMissing() {
+		return true
+	}
+
+	if c.preciseGeoIndex == noPreciseGeoIndex || c.preciseGeoIndex >= len(c.SensitiveDataProcessing) {
+		return false
+	}
+	return c.SensitiveDataProcessing[c.preciseGeoIndex] == usYes
+}
+
+// KnownChildWithoutConsent returns true if the user is a known child for whom consent to process
+// their data was not obtained.
+func (c USConsent) KnownChildWithoutConsent() bool {
+	for _, v := range c.KnownChildSensitiveDataConsents {
+		if v == usYes {
+			return true
+		}
+	}
+	return false
+}
+
+// PersonalDataConsentMissing returns true if consent was required but not obtained for the
+// processing of personal data for purposes which are incompatible with those disclosed.
+func (c USConsent) PersonalDataConsentMissing() bool {
+	return c.PersonalDataConsents == usYes
+}
+
+// ServiceProviderMode returns true if the publisher operates in MSPA Service Provider Mode, in which
+// case personal data may not be sold, shared or used for targeted advertising.
+func (c USConsent) ServiceProviderMode() bool {
+	return c.MspaServiceProviderMode == usYes
+}
+
+func (c USConsent) sensitiveDataNoticeMissing() bool {
+	return c.SensitiveDataProcessingOptOutNotice == usNo || c.SensitiveDataLimitUseNotice == usNo
+}
+
+// isOptedOut interprets a notice and opt-out field pair. An opt-out field of 1 means the user opted
+// out. A notice field of 2 means the notice was not provided, which prevents relying on the user not
+// having opted out. An opt-out field of 2 without any applicable notice is contradictory and is
+// treated as an opt-out.
+func isOptedOut(notice, optOut byte) bool {
+	if optOut == usYes || notice == usNo {
+		return true
+	}
+	return notice == usNotApplicable && optOut == usNo
+}
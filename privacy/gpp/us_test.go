@@ -0,0 +1,265 @@
+package gpp
+
+import (
+	"testing"
+
+	gpplib "github.com/prebid/go-gpp"
+	gppConstants "github.com/prebid/go-gpp/constants"
+	"github.com/prebid/go-gpp/sections"
+	"github.com/prebid/go-gpp/sections/uspca"
+	"github.com/prebid/go-gpp/sections/uspco"
+	"github.com/prebid/go-gpp/sections/uspnat"
+	"github.com/prebid/go-gpp/sections/usput"
+	"github.com/prebid/go-gpp/sections/uspva"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewUSConsent(t *testing.T) {
+	testCases := []struct {
+		desc            string
+		section         gpplib.Section
+		expectedOK      bool
+		expectedSID     gppConstants.SectionID
+		expectedGPC     bool
+		expectedGeoFlag bool
+	}{
+		{
+			desc:       "nil",
+			section:    nil,
+			expectedOK: false,
+		},
+		{
+			desc:       "unsupported_section",
+			section:    gpplib.GenericSection{},
+			expectedOK: false,
+		},
+		{
+			desc:       "unparsed_section",
+			section:    uspnat.USPNAT{},
+			expectedOK: false,
+		},
+		{
+			desc: "usnat",
+			section: uspnat.USPNAT{
+				SectionID:   gppConstants.SectionUSPNAT,
+				CoreSegment: uspnat.USPNATCoreSegment{SensitiveDataProcessing: []byte{0, 0, 0, 0, 0, 0, 0, 1, 0, 0, 0, 0}},
+				GPCSegment:  sections.CommonUSGPCSegment{Gpc: true},
+			},
+			expectedOK:      true,
+			expectedSID:     gppConstants.SectionUSPNAT,
+			expectedGPC:     true,
+			expectedGeoFlag: true,
+		},
+		{
+			desc: "usca",
+			section: uspca.USPCA{
+				SectionID:   gppConstants.SectionUSPCA,
+				CoreSegment: uspca.USPCACoreSegment{SensitiveDataProcessing: []byte{0, 0, 1, 0, 0, 0, 0, 0, 0}},
+			},
+			expectedOK:      true,
+			expectedSID:     gppConstants.SectionUSPCA,
+			expectedGeoFlag: true,
+		},
+		{
+			desc: "usva",
+			section: uspva.USPVA{
+				SectionID:   gppConstants.SectionUSPVA,
+				CoreSegment: sections.CommonUSCoreSegment{SensitiveDataProcessing: []byte{0, 0, 0, 0, 0, 0, 0, 1}},
+			},
+			expectedOK:      true,
+			expectedSID:     gppConstants.SectionUSPVA,
+			expectedGeoFlag: true,
+		},
+		{
+			desc: "usco_has_no_precise_geo_category",
+			section: uspco.USPCO{
+				SectionID:   gppConstants.SectionUSPCO,
+				CoreSegment: sections.CommonUSCoreSegment{SensitiveDataProcessing: []byte{1, 1, 1, 1, 1, 1, 1}},
+			},
+			expectedOK:      true,
+			expectedSID:     gppConstants.SectionUSPCO,
+			expectedGeoFlag: false,
+		},
+		{
+			desc: "usut",
+			section: usput.USPUT{
+				SectionID:   gppConstants.SectionUSPUT,
+				CoreSegment: usput.USPUTCoreSegment{SensitiveDataProcessing: []byte{0, 0, 0, 0, 0, 0, 0, 1}},
+			},
+			expectedOK:      true,
+			expectedSID:     gppConstants.SectionUSPUT,
+			expectedGeoFlag: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			consent, ok := NewUSConsent(tc.section)
+			assert.Equal(t, tc.expectedOK, ok)
+			assert.Equal(t, tc.expectedSID, consent.SectionID)
+			assert.Equal(t, tc.expectedGPC, consent.GPC)
+			assert.Equal(t, tc.expectedGeoFlag, consent.PreciseGeoRestricted())
+		})
+	}
+}
+
+func TestReadUSConsents(t *testing.T) {
+	gpp := gpplib.GppContainer{
+		SectionTypes: []gppConstants.SectionID{gppConstants.SectionTCFEU2, gppConstants.SectionUSPNAT, gppConstants.SectionUSPVA},
+		Sections: []gpplib.Section{
+			gpplib.GenericSection{},
+			uspnat.USPNAT{SectionID: gppConstants.SectionUSPNAT},
+			uspva.USPVA{SectionID: gppConstants.SectionUSPVA},
+		},
+	}
+
+	testCases := []struct {
+		desc         string
+		gppSIDs      []int8
+		skipSIDs     []int8
+		expectedSIDs []gppConstants.SectionID
+	}{
+		{
+			desc:         "no_applicable_sections",
+			gppSIDs:      nil,
+			expectedSIDs: nil,
+		},
+		{
+			desc:         "non_us_section_applicable",
+			gppSIDs:      []int8{2},
+			expectedSIDs: nil,
+		},
+		{
+			desc:         "all_applicable",
+			gppSIDs:      []int8{2, 7, 9},
+			expectedSIDs: []gppConstants.SectionID{gppConstants.SectionUSPNAT, gppConstants.SectionUSPVA},
+		},
+		{
+			desc:         "applicable_but_skipped",
+			gppSIDs:      []int8{7, 9},
+			skipSIDs:     []int8{7},
+			expectedSIDs: []gppConstants.SectionID{gppConstants.SectionUSPVA},
+		},
+		{
+			desc:         "applicable_but_missing",
+			gppSIDs:      []int8{8},
+			expectedSIDs: nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			var sids []gppConstants.SectionID
+			for _, c := range ReadUSConsents(gpp, tc.gppSIDs, tc.skipSIDs) {
+				sids = append(sids, c.SectionID)
+			}
+			assert.Equal(t, tc.expectedSIDs, sids)
+		})
+	}
+}
+
+func TestUSConsentRestrictions(t *testing.T) {
+	testCases := []struct {
+		desc                    string
+		consent                 USConsent
+		expectedSaleOrSharing   bool
+		expectedTargetedAds     bool
+		expectedSensitiveData   bool
+		expectedPreciseGeo      bool
+		expectedKnownChild      bool
+		expectedPersonalData    bool
+		expectedServiceProvider bool
+	}{
+		{
+			desc:    "not_applicable",
+			consent: USConsent{preciseGeoIndex: 7},
+		},
+		{
+			desc:                  "sale_opted_out",
+			consent:               USConsent{SaleOptOutNotice: usYes, SaleOptOut: usYes},
+			expectedSaleOrSharing: true,
+		},
+		{
+			desc:    "sale_not_opted_out",
+			consent: USConsent{SaleOptOutNotice: usYes, SaleOptOut: usNo},
+		},
+		{
+			desc:                  "sale_notice_not_provided",
+			consent:               USConsent{SaleOptOutNotice: usNo, SaleOptOut: usNo},
+			expectedSaleOrSharing: true,
+		},
+		{
+			desc:                  "sale_not_opted_out_without_notice",
+			consent:               USConsent{SaleOptOutNotice: usNotApplicable, SaleOptOut: usNo},
+			expectedSaleOrSharing: true,
+		},
+		{
+			desc:                  "sharing_opted_out",
+			consent:               USConsent{SharingOptOutNotice: usYes, SharingOptOut: usYes},
+			expectedSaleOrSharing: true,
+		},
+		{
+			desc:                  "sharing_notice_not_provided",
+			consent:               USConsent{SharingNotice: usNo},
+			expectedSaleOrSharing: true,
+		},
+		{
+			desc:                "targeted_advertising_opted_out",
+			consent:             USConsent{TargetedAdvertisingOptOutNotice: usYes, TargetedAdvertisingOptOut: usYes},
+			expectedTargetedAds: true,
+		},
+		{
+			desc:                  "sensitive_data_opted_out",
+			consent:               USConsent{SensitiveDataProcessing: []byte{1, 0, 0, 0, 0, 0, 0, 0}, preciseGeoIndex: 7},
+			expectedSensitiveData: true,
+		},
+		{
+			desc:               "precise_geo_opted_out",
+			consent:            USConsent{SensitiveDataProcessing: []byte{0, 0, 0, 0, 0, 0, 0, 1}, preciseGeoIndex: 7},
+			expectedPreciseGeo: true,
+		},
+		{
+			desc:                  "sensitive_data_notice_not_provided",
+			consent:               USConsent{SensitiveDataProcessingOptOutNotice: usNo, preciseGeoIndex: 7},
+			expectedSensitiveData: true,
+			expectedPreciseGeo:    true,
+		},
+		{
+			desc:                  "sensitive_data_limit_use_notice_not_provided",
+			consent:               USConsent{SensitiveDataLimitUseNotice: usNo, preciseGeoIndex: 7},
+			expectedSensitiveData: true,
+			expectedPreciseGeo:    true,
+		},
+		{
+			desc:               "known_child_without_consent",
+			consent:            USConsent{KnownChildSensitiveDataConsents: []byte{0, 1}},
+			expectedKnownChild: true,
+		},
+		{
+			desc:    "known_child_with_consent",
+			consent: USConsent{KnownChildSensitiveDataConsents: []byte{2, 2}},
+		},
+		{
+			desc:                 "personal_data_consent_missing",
+			consent:              USConsent{PersonalDataConsents: usYes},
+			expectedPersonalData: true,
+		},
+		{
+			desc:                    "service_provider_mode",
+			consent:                 USConsent{MspaServiceProviderMode: usYes},
+			expectedServiceProvider: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			assert.Equal(t, tc.expectedSaleOrSharing, tc.consent.SaleOrSharingOptedOut(), "sale or sharing")
+			assert.Equal(t, tc.expectedTargetedAds, tc.consent.TargetedAdvertisingOptedOut(), "targeted advertising")
+			assert.Equal(t, tc.expectedSensitiveData, tc.consent.SensitiveDataRestricted(), "sensitive data")
+			assert.Equal(t, tc.expectedPreciseGeo, tc.consent.PreciseGeoRestricted(), "precise geo")
+			assert.Equal(t, tc.expectedKnownChild, tc.consent.KnownChildWithoutConsent(), "known child")
+			assert.Equal(t, tc.expectedPersonalData, tc.consent.PersonalDataConsentMissing(), "personal data")
+			assert.Equal(t, tc.expectedServiceProvider, tc.consent.ServiceProviderMode(), "service provider mode")
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package privacy
+
+import (
+	gpplib "github.com/prebid/go-gpp"
+	"github.com/prebid/prebid-server/v4/privacy/gpp"
+)
+
+// Privacy regulation module codes which may be referenced by the privacyreg field of an activity rule.
+const (
+	PrivacyModuleUSGeneral = "iab.usgeneral"
+	PrivacyModuleWildcard  = "*"
+)
+
+// privacyModuleCodes lists the privacy regulation modules supported by activity rules.
+var privacyModuleCodes = []string{PrivacyModuleUSGeneral}
+
+// GPPUSRule enforces the IAB US National and US state GPP sections for a single activity. The rule
+// denies the activity if any applicable section restricts it, allows the activity if all applicable
+// sections permit it and abstains if the request carries no applicable section.
+type GPPUSRule struct {
+	activity      Activity
+	componentName []string
+	componentType []string
+	skipSIDs      []int8
+}
+
+func (r GPPUSRule) Evaluate(target Component, request ActivityRequest) ActivityResult {
+	if matched := evaluateComponentName(target, r.componentName); !matched {
+		return ActivityAbstain
+	}
+
+	if matched := evaluateComponentType(target, r.componentType); !matched {
+		return ActivityAbstain
+	}
+
+	consents := gpp.ReadUSConsents(getGPP(request), getGPPSID(request), r.skipSIDs)
+	if len(consents) == 0 {
+		return ActivityAbstain
+	}
+
+	for _, c := range consents {
+		if gppUSRestricts(r.activity, c) {
+			return ActivityDeny
+		}
+	}
+	return ActivityAllow
+}
+
+func gppUSRestricts(activity Activity, c gpp.USConsent) bool {
+	switch activity {
+	case ActivitySyncUser:
+		return gppUSRestrictsUserData(c)
+	case ActivityEnrichUserFPD, ActivityTransmitUserFPD:
+		return gppUSRestrictsUserData(c) || c.SensitiveDataRestricted()
+	case ActivityTransmitPreciseGeo:
+		return c.PreciseGeoRestricted() || c.KnownChildWithoutConsent()
+	case ActivityFetchBids:
+		// a known child's data may not be processed at all without consent, so the user's data
+		// cannot be protected by scrubbing alone
+		return c.KnownChildWithoutConsent()
+	}
+	return false
+}
+
+func gppUSRestrictsUserData(c gpp.USConsent) bool {
+	return c.GPC ||
+		c.SaleOrSharingOptedOut() ||
+		c.TargetedAdvertisingOptedOut() ||
+		c.KnownChildWithoutConsent() ||
+		c.PersonalDataConsentMissing() ||
+		c.ServiceProviderMode()
+}
+
+func getGPP(request ActivityRequest) gpplib.GppContainer {
+	if request.IsPolicies() {
+		return request.policies.GPP
+	}
+
+	if request.gpp != nil {
+		return *request.gpp
+	}
+
+	if request.IsBidRequest() && request.bidRequest.Regs != nil && len(request.bidRequest.Regs.GPP) > 0 {
+		// sections which fail to parse are ignored
+		container, _ := gpplib.Parse(request.bidRequest.Regs.GPP)
+		return container
+	}
+
+	return gpplib.GppContainer{}
+}
@@ -0,0 +1,196 @@
+package privacy
+
+import (
+	"testing"
+
+	gpplib "github.com/prebid/go-gpp"
+	gppConstants "github.com/prebid/go-gpp/constants"
+	"github.com/prebid/go-gpp/sections"
+	"github.com/prebid/go-gpp/sections/uspnat"
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v4/openrtb_ext"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGPPUSRuleEvaluate(t *testing.T) {
+	optedOut := uspnat.USPNATCoreSegment{
+		SaleOptOutNotice: 1,
+		SaleOptOut:       1,
+	}
+	preciseGeoOptedOut := uspnat.USPNATCoreSegment{
+		SensitiveDataProcessing: []byte{0, 0, 0, 0, 0, 0, 0, 1, 0, 0, 0, 0},
+	}
+	knownChild := uspnat.USPNATCoreSegment{
+		KnownChildSensitiveDataConsents: []byte{1, 0},
+	}
+
+	testCases := []struct {
+		name           string
+		rule           GPPUSRule
+		target         Component
+		request        ActivityRequest
+		activityResult ActivityResult
+	}{
+		{
+			name:           "no_gpp",
+			rule:           GPPUSRule{activity: ActivitySyncUser},
+			target:         Component{Type: "bidder", Name: "bidderA"},
+			request:        NewRequestFromPolicies(Policies{}),
+			activityResult: ActivityAbstain,
+		},
+		{
+			name:           "section_not_applicable",
+			rule:           GPPUSRule{activity: ActivitySyncUser},
+			target:         Component{Type: "bidder", Name: "bidderA"},
+			request:        NewRequestFromPolicies(Policies{GPPSID: []int8{8}, GPP: getTestUSNatGPP(optedOut, false)}),
+			activityResult: ActivityAbstain,
+		},
+		{
+			name:           "section_skipped",
+			rule:           GPPUSRule{activity: ActivitySyncUser, skipSIDs: []int8{7}},
+			target:         Component{Type: "bidder", Name: "bidderA"},
+			request:        NewRequestFromPolicies(Policies{GPPSID: []int8{7}, GPP: getTestUSNatGPP(optedOut, false)}),
+			activityResult: ActivityAbstain,
+		},
+		{
+			name:           "component_name_not_matched",
+			rule:           GPPUSRule{activity: ActivitySyncUser, componentName: []string{"bidderB"}},
+			target:         Component{Type: "bidder", Name: "bidderA"},
+			request:        NewRequestFromPolicies(Policies{GPPSID: []int8{7}, GPP: getTestUSNatGPP(optedOut, false)}),
+			activityResult: ActivityAbstain,
+		},
+		{
+			name:           "component_type_not_matched",
+			rule:           GPPUSRule{activity: ActivitySyncUser, componentType: []string{"analytics"}},
+			target:         Component{Type: "bidder", Name: "bidderA"},
+			request:        NewRequestFromPolicies(Policies{GPPSID: []int8{7}, GPP: getTestUSNatGPP(optedOut, false)}),
+			activityResult: ActivityAbstain,
+		},
+		{
+			name:           "sync_user_sale_opted_out",
+			rule:           GPPUSRule{activity: ActivitySyncUser, componentName: []string{"bidderA"}},
+			target:         Component{Type: "bidder", Name: "bidderA"},
+			request:        NewRequestFromPolicies(Policies{GPPSID: []int8{7}, GPP: getTestUSNatGPP(optedOut, false)}),
+			activityResult: ActivityDeny,
+		},
+		{
+			name:           "sync_user_gpc",
+			rule:           GPPUSRule{activity: ActivitySyncUser},
+			target:         Component{Type: "bidder", Name: "bidderA"},
+			request:        NewRequestFromPolicies(Policies{GPPSID: []int8{7}, GPP: getTestUSNatGPP(uspnat.USPNATCoreSegment{}, true)}),
+			activityResult: ActivityDeny,
+		},
+		{
+			name:           "sync_user_allowed",
+			rule:           GPPUSRule{activity: ActivitySyncUser},
+			target:         Component{Type: "bidder", Name: "bidderA"},
+			request:        NewRequestFromPolicies(Policies{GPPSID: []int8{7}, GPP: getTestUSNatGPP(preciseGeoOptedOut, false)}),
+			activityResult: ActivityAllow,
+		},
+		{
+			name:           "transmit_ufpd_sale_opted_out",
+			rule:           GPPUSRule{activity: ActivityTransmitUserFPD},
+			target:         Component{Type: "bidder", Name: "bidderA"},
+			request:        NewRequestFromPolicies(Policies{GPPSID: []int8{7}, GPP: getTestUSNatGPP(optedOut, false)}),
+			activityResult: ActivityDeny,
+		},
+		{
+			name:           "transmit_precise_geo_opted_out",
+			rule:           GPPUSRule{activity: ActivityTransmitPreciseGeo},
+			target:         Component{Type: "bidder", Name: "bidderA"},
+			request:        NewRequestFromPolicies(Policies{GPPSID: []int8{7}, GPP: getTestUSNatGPP(preciseGeoOptedOut, false)}),
+			activityResult: ActivityDeny,
+		},
+		{
+			name:           "transmit_precise_geo_sale_opted_out",
+			rule:           GPPUSRule{activity: ActivityTransmitPreciseGeo},
+			target:         Component{Type: "bidder", Name: "bidderA"},
+			request:        NewRequestFromPolicies(Policies{GPPSID: []int8{7}, GPP: getTestUSNatGPP(optedOut, false)}),
+			activityResult: ActivityAllow,
+		},
+		{
+			name:           "fetch_bids_known_child",
+			rule:           GPPUSRule{activity: ActivityFetchBids},
+			target:         Component{Type: "bidder", Name: "bidderA"},
+			request:        NewRequestFromPolicies(Policies{GPPSID: []int8{7}, GPP: getTestUSNatGPP(knownChild, false)}),
+			activityResult: ActivityDeny,
+		},
+		{
+			name:           "fetch_bids_sale_opted_out",
+			rule:           GPPUSRule{activity: ActivityFetchBids},
+			target:         Component{Type: "bidder", Name: "bidderA"},
+			request:        NewRequestFromPolicies(Policies{GPPSID: []int8{7}, GPP: getTestUSNatGPP(optedOut, false)}),
+			activityResult: ActivityAllow,
+		},
+		{
+			name:           "report_analytics_not_enforced",
+			rule:           GPPUSRule{activity: ActivityReportAnalytics},
+			target:         Component{Type: "analytics", Name: "analyticsA"},
+			request:        NewRequestFromPolicies(Policies{GPPSID: []int8{7}, GPP: getTestUSNatGPP(optedOut, true)}),
+			activityResult: ActivityAllow,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			actualResult := test.rule.Evaluate(test.target, test.request)
+			assert.Equal(t, test.activityResult, actualResult)
+		})
+	}
+}
+
+func TestGPPUSRuleEvaluateBidRequest(t *testing.T) {
+	gppString, err := gpplib.Encode([]gpplib.Section{
+		uspnat.USPNAT{
+			SectionID: gppConstants.SectionUSPNAT,
+			CoreSegment: uspnat.USPNATCoreSegment{
+				Version:                         1,
+				SaleOptOutNotice:                1,
+				SaleOptOut:                      1,
+				SensitiveDataProcessing:         make([]byte, 12),
+				KnownChildSensitiveDataConsents: make([]byte, 2),
+			},
+			GPCSegment: sections.CommonUSGPCSegment{SubsectionType: 1},
+		},
+	})
+	require.NoError(t, err)
+
+	rule := GPPUSRule{activity: ActivityTransmitUserFPD}
+	target := Component{Type: "bidder", Name: "bidderA"}
+
+	t.Run("applicable", func(t *testing.T) {
+		request := NewRequestFromBidRequest(openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{
+			Regs: &openrtb2.Regs{GPP: gppString, GPPSID: []int8{7}},
+		}})
+		assert.Equal(t, ActivityDeny, rule.Evaluate(target, request))
+	})
+
+	t.Run("malformed", func(t *testing.T) {
+		request := NewRequestFromBidRequest(openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{
+			Regs: &openrtb2.Regs{GPP: "malformed", GPPSID: []int8{7}},
+		}})
+		assert.Equal(t, ActivityAbstain, rule.Evaluate(target, request))
+	})
+
+	t.Run("already-parsed", func(t *testing.T) {
+		// the parsed GPP is used instead of parsing the GPP string again
+		request := NewRequestFromBidRequestWithGPP(openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{
+			Regs: &openrtb2.Regs{GPP: "malformed", GPPSID: []int8{7}},
+		}}, getTestUSNatGPP(uspnat.USPNATCoreSegment{}, true))
+		assert.Equal(t, ActivityDeny, rule.Evaluate(target, request))
+	})
+}
+
+func getTestUSNatGPP(core uspnat.USPNATCoreSegment, gpc bool) gpplib.GppContainer {
+	return gpplib.GppContainer{
+		SectionTypes: []gppConstants.SectionID{gppConstants.SectionUSPNAT},
+		Sections: []gpplib.Section{
+			uspnat.USPNAT{
+				SectionID:   gppConstants.SectionUSPNAT,
+				CoreSegment: core,
+				GPCSegment:  sections.CommonUSGPCSegment{SubsectionType: 1, Gpc: gpc},
+			},
+		},
+	}
+}
@@ -0,0 +1,158 @@
+package server
+
+import (
+	"bufio"
+	"errors"
+	"net"
+	"net/http"
+
+	"github.com/prebid/prebid-server/v4/logger"
+	"github.com/prebid/prebid-server/v4/util/httputil"
+)
+
+// compressionMinSize is the response size below which responses are sent uncompressed, since the
+// compression overhead outweighs the savings for responses fitting in a single packet.
+const compressionMinSize = 1400
+
+// compressionHandler compresses the responses with the content encoding negotiated from the
+// Accept-Encoding header of the request.
+type compressionHandler struct {
+	handler   http.Handler
+	encodings []httputil.ContentEncoding
+}
+
+func (h *compressionHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Add("Vary", "Accept-Encoding")
+
+	contentEncoding := httputil.NegotiateContentEncoding(r.Header.Get("Accept-Encoding"), h.encodings)
+	if contentEncoding == "" {
+		h.handler.ServeHTTP(w, r)
+		return
+	}
+
+	cw := &compressResponseWriter{ResponseWriter: w, contentEncoding: contentEncoding}
+	defer cw.close()
+	h.handler.ServeHTTP(cw, r)
+}
+
+// compressResponseWriter buffers the response until it reaches the minimum size for compression or
+// the handler returns. Responses already encoded by the handler are passed through unchanged.
+type compressResponseWriter struct {
+	http.ResponseWriter
+	contentEncoding httputil.ContentEncoding
+	encoder         httputil.Encoder
+	buffer          []byte
+	statusCode      int
+	passThrough     bool
+}
+
+func (cw *compressResponseWriter) WriteHeader(statusCode int) {
+	if cw.statusCode == 0 {
+		cw.statusCode = statusCode
+	}
+}
+
+func (cw *compressResponseWriter) Write(b []byte) (int, error) {
+	if cw.statusCode == 0 {
+		cw.statusCode = http.StatusOK
+	}
+	if cw.passThrough {
+		return cw.ResponseWriter.Write(b)
+	}
+	if cw.encoder != nil {
+		return cw.encoder.Write(b)
+	}
+
+	if !cw.compressible() {
+		cw.passThrough = true
+		cw.ResponseWriter.WriteHeader(cw.statusCode)
+		return cw.ResponseWriter.Write(b)
+	}
+
+	cw.buffer = append(cw.buffer, b...)
+	if len(cw.buffer) >= compressionMinSize {
+		if err := cw.startCompression(); err != nil {
+			return 0, err
+		}
+	}
+	return len(b), nil
+}
+
+// Flush sends the buffered response, compressing it regardless of its size since the handler
+// wants it delivered.
+func (cw *compressResponseWriter) Flush() {
+	if cw.statusCode != 0 && !cw.passThrough && cw.encoder == nil {
+		if !cw.compressible() {
+			cw.passThrough = true
+			cw.ResponseWriter.WriteHeader(cw.statusCode)
+		} else if err := cw.startCompression(); err != nil {
+			return
+		}
+	}
+	if cw.encoder != nil {
+		cw.encoder.Flush()
+	}
+	if flusher, ok := cw.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Hijack hands the connection over to the handler, which then writes the response itself uncompressed.
+func (cw *compressResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := cw.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("http.Hijacker interface is not supported")
+	}
+	conn, rw, err := hijacker.Hijack()
+	if err == nil {
+		cw.passThrough = true
+	}
+	return conn, rw, err
+}
+
+func (cw *compressResponseWriter) compressible() bool {
+	if cw.statusCode < http.StatusOK || cw.statusCode == http.StatusNoContent || cw.statusCode == http.StatusNotModified {
+		return false
+	}
+	return cw.Header().Get("Content-Encoding") == ""
+}
+
+func (cw *compressResponseWriter) startCompression() error {
+	header := cw.Header()
+	if header.Get("Content-Type") == "" {
+		// the content type can't be sniffed from the compressed response
+		header.Set("Content-Type", http.DetectContentType(cw.buffer))
+	}
+	header.Set("Content-Encoding", string(cw.contentEncoding))
+	header.Del("Content-Length")
+	cw.ResponseWriter.WriteHeader(cw.statusCode)
+
+	encoder, err := httputil.AcquireEncoder(cw.contentEncoding, cw.ResponseWriter)
+	if err != nil {
+		return err
+	}
+	cw.encoder = encoder
+
+	buffer := cw.buffer
+	cw.buffer = nil
+	_, err = cw.encoder.Write(buffer)
+	return err
+}
+
+// close sends the remaining response. Responses smaller than the minimum size are sent uncompressed.
+func (cw *compressResponseWriter) close() {
+	if cw.encoder != nil {
+		if err := cw.encoder.Close(); err != nil {
+			logger.Errorf("Failed to compress the response with %s: %v", cw.contentEncoding, err)
+		}
+		httputil.ReleaseEncoder(cw.contentEncoding, cw.encoder)
+		return
+	}
+	if cw.passThrough || cw.statusCode == 0 {
+		return
+	}
+	cw.ResponseWriter.WriteHeader(cw.statusCode)
+	if len(cw.buffer) > 0 {
+		cw.ResponseWriter.Write(cw.buffer)
+	}
+}
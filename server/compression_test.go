@@ -0,0 +1,204 @@
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/prebid/prebid-server/v4/config"
+	"github.com/prebid/prebid-server/v4/util/httputil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompressionHandler(t *testing.T) {
+	largeBody := strings.Repeat(`{"id":"some-response-id"}`, 100)
+	smallBody := `{"id":"some-response-id"}`
+
+	testCases := []struct {
+		description             string
+		compression             config.CompressionInfo
+		acceptEncoding          string
+		handler                 http.HandlerFunc
+		expectedStatus          int
+		expectedContentEncoding string
+		expectedContentType     string
+		expectedBody            string
+	}{
+		{
+			description:             "zstd-preferred",
+			compression:             config.CompressionInfo{GZIP: true, ZSTD: true, Brotli: true},
+			acceptEncoding:          "gzip, br, zstd",
+			handler:                 writeBody(http.StatusOK, largeBody),
+			expectedStatus:          http.StatusOK,
+			expectedContentEncoding: "zstd",
+			expectedContentType:     "text/plain; charset=utf-8",
+			expectedBody:            largeBody,
+		},
+		{
+			description:             "brotli",
+			compression:             config.CompressionInfo{GZIP: true, Brotli: true},
+			acceptEncoding:          "br;q=1, gzip;q=0.5",
+			handler:                 writeBody(http.StatusBadRequest, largeBody),
+			expectedStatus:          http.StatusBadRequest,
+			expectedContentEncoding: "br",
+			expectedContentType:     "text/plain; charset=utf-8",
+			expectedBody:            largeBody,
+		},
+		{
+			description:             "gzip",
+			compression:             config.CompressionInfo{GZIP: true, ZSTD: true},
+			acceptEncoding:          "gzip",
+			handler:                 writeBody(http.StatusOK, largeBody),
+			expectedStatus:          http.StatusOK,
+			expectedContentEncoding: "gzip",
+			expectedContentType:     "text/plain; charset=utf-8",
+			expectedBody:            largeBody,
+		},
+		{
+			description:    "not-accepted",
+			compression:    config.CompressionInfo{ZSTD: true},
+			acceptEncoding: "gzip",
+			handler:        writeBody(http.StatusOK, largeBody),
+			expectedStatus: http.StatusOK,
+			expectedBody:   largeBody,
+		},
+		{
+			description:    "below-min-size",
+			compression:    config.CompressionInfo{ZSTD: true},
+			acceptEncoding: "zstd",
+			handler:        writeBody(http.StatusOK, smallBody),
+			expectedStatus: http.StatusOK,
+			expectedBody:   smallBody,
+		},
+		{
+			description:    "no-content",
+			compression:    config.CompressionInfo{ZSTD: true},
+			acceptEncoding: "zstd",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusNoContent)
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			description:    "already-encoded",
+			compression:    config.CompressionInfo{ZSTD: true},
+			acceptEncoding: "zstd",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Encoding", "identity")
+				w.Write([]byte(largeBody))
+			},
+			expectedStatus:          http.StatusOK,
+			expectedContentEncoding: "identity",
+			expectedBody:            largeBody,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			handler := getCompressionEnabledHandler(test.handler, test.compression)
+			req := httptest.NewRequest(http.MethodPost, "/openrtb2/auction", nil)
+			req.Header.Set("Accept-Encoding", test.acceptEncoding)
+			recorder := httptest.NewRecorder()
+
+			handler.ServeHTTP(recorder, req)
+
+			assert.Equal(t, test.expectedStatus, recorder.Code)
+			assert.Equal(t, "Accept-Encoding", recorder.Header().Get("Vary"))
+			assert.Equal(t, test.expectedContentEncoding, recorder.Header().Get("Content-Encoding"))
+			if test.expectedContentType != "" {
+				assert.Equal(t, test.expectedContentType, recorder.Header().Get("Content-Type"))
+			}
+
+			body := io.Reader(recorder.Body)
+			if contentEncoding := test.expectedContentEncoding; contentEncoding != "" && contentEncoding != "identity" {
+				decoder, err := httputil.NewDecodingReader(recorder.Body, httputil.ContentEncoding(contentEncoding))
+				require.NoError(t, err)
+				body = decoder
+			}
+			decoded, err := io.ReadAll(body)
+			require.NoError(t, err)
+			assert.Equal(t, test.expectedBody, string(decoded))
+		})
+	}
+}
+
+func TestCompressionHandlerFlush(t *testing.T) {
+	handler := getCompressionEnabledHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("first"))
+		w.(http.Flusher).Flush()
+		w.Write([]byte("second"))
+	}), config.CompressionInfo{GZIP: true})
+	req := httptest.NewRequest(http.MethodGet, "/status", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	recorder := httptest.NewRecorder()
+
+	handler.ServeHTTP(recorder, req)
+
+	assert.True(t, recorder.Flushed)
+	assert.Equal(t, "gzip", recorder.Header().Get("Content-Encoding"))
+	decoder, err := httputil.NewDecodingReader(bytes.NewReader(recorder.Body.Bytes()), httputil.ContentEncodingGZIP)
+	require.NoError(t, err)
+	decoded, err := io.ReadAll(decoder)
+	require.NoError(t, err)
+	assert.Equal(t, "firstsecond", string(decoded))
+}
+
+func TestCompressionHandlerHijack(t *testing.T) {
+	var hijacked bool
+	handler := getCompressionEnabledHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hijacker, ok := w.(http.Hijacker)
+		require.True(t, ok)
+		_, _, err := hijacker.Hijack()
+		hijacked = err == nil
+	}), config.CompressionInfo{GZIP: true})
+	req := httptest.NewRequest(http.MethodGet, "/status", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	recorder := &hijackableRecorder{ResponseRecorder: httptest.NewRecorder()}
+
+	handler.ServeHTTP(recorder, req)
+
+	assert.True(t, hijacked)
+	assert.True(t, recorder.hijacked)
+	assert.False(t, recorder.Flushed)
+	assert.Empty(t, recorder.Body.Bytes())
+}
+
+func TestCompressionHandlerHijackNotSupported(t *testing.T) {
+	handler := getCompressionEnabledHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _, err := w.(http.Hijacker).Hijack()
+		assert.EqualError(t, err, "http.Hijacker interface is not supported")
+	}), config.CompressionInfo{GZIP: true})
+	req := httptest.NewRequest(http.MethodGet, "/status", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+}
+
+func TestCompressionHandlerDisabled(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+
+	assert.IsType(t, handler, getCompressionEnabledHandler(handler, config.CompressionInfo{}))
+}
+
+func writeBody(statusCode int, body string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(statusCode)
+		w.Write([]byte(body))
+	}
+}
+
+type hijackableRecorder struct {
+	*httptest.ResponseRecorder
+	hijacked bool
+}
+
+func (r *hijackableRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	r.hijacked = true
+	return nil, nil, nil
+}
@@ -11,7 +11,6 @@ import (
 	"syscall"
 	"time"
 
-	"github.com/NYTimes/gziphandler"
 	"github.com/prebid/prebid-server/v4/config"
 	"github.com/prebid/prebid-server/v4/logger"
 	"github.com/prebid/prebid-server/v4/metrics"
@@ -118,8 +117,8 @@ func newSocketServer(cfg *config.Configuration, handler http.Handler) *http.Serv
 }
 
 func getCompressionEnabledHandler(h http.Handler, compressionInfo config.CompressionInfo) http.Handler {
-	if compressionInfo.GZIP {
-		h = gziphandler.GzipHandler(h)
+	if encodings := compressionInfo.Encodings(); len(encodings) > 0 {
+		h = &compressionHandler{handler: h, encodings: encodings}
 	}
 	return h
 }
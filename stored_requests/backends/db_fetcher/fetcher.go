@@ -108,6 +108,20 @@ func (fetcher *dbFetcher) FetchRequests(ctx context.Context, requestIDs []string
 }
 
 func (fetcher *dbFetcher) FetchResponses(ctx context.Context, ids []string) (data map[string]json.RawMessage, errs []error) {
+	return fetcher.fetchByIDs(ctx, ids)
+}
+
+// FetchFloors fetches the floor rule sets by running the $ID_LIST query of the section
+func (fetcher *dbFetcher) FetchFloors(ctx context.Context, ids []string) (data map[string]json.RawMessage, errs []error) {
+	data, errs = fetcher.fetchByIDs(ctx, ids)
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	return data, appendErrors("Floors", ids, data, nil)
+}
+
+// fetchByIDs runs the responseQueryTemplate with the ids as $ID_LIST, and returns the data of each id found.
+func (fetcher *dbFetcher) fetchByIDs(ctx context.Context, ids []string) (data map[string]json.RawMessage, errs []error) {
 	if len(ids) < 1 {
 		return nil, nil
 	}
@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/prebid/prebid-server/v4/stored_requests"
 	"github.com/prebid/prebid-server/v4/stored_requests/backends/db_provider"
 	"github.com/stretchr/testify/assert"
 )
@@ -114,6 +115,21 @@ func TestFetchResponses(t *testing.T) {
 	}
 }
 
+func TestFetchFloors(t *testing.T) {
+	mockQuery := "SELECT id, data, 'floors' AS dataType FROM floors_table WHERE id IN (?, ?)"
+	mockReturn := sqlmock.NewRows([]string{"id", "data", "dataType"}).
+		AddRow("floors-1", `{"currency":"USD"}`, "floors")
+	mock, fetcher := newFetcher(t, mockReturn, mockQuery, "floors-1", "floors-2")
+	defer fetcher.provider.Close()
+
+	floors, errs := fetcher.FetchFloors(context.Background(), []string{"floors-1", "floors-2"})
+
+	assertMockExpectations(t, mock)
+	assert.Equal(t, []error{stored_requests.NotFoundError{ID: "floors-2", DataType: "Floors"}}, errs)
+	assertMapLength(t, 1, floors)
+	assertHasData(t, floors, "floors-1", `{"currency":"USD"}`)
+}
+
 // TestPartialResponse makes sure we unpack things properly when the DB finds some of the stored requests.
 func TestPartialResponse(t *testing.T) {
 	mockQuery := "SELECT id, data, 'request' AS dataType FROM req_table WHERE id IN (?, ?) UNION ALL SELECT id, data, 'imp' as dataType FROM imp_table WHERE id IN (NULL)"
@@ -36,6 +36,17 @@ func (fetcher EmptyFetcher) FetchAccount(ctx context.Context, accountDefaultJSON
 	return nil, []error{stored_requests.NotFoundError{ID: accountID, DataType: "Account"}}
 }
 
+func (fetcher EmptyFetcher) FetchFloors(ctx context.Context, ids []string) (data map[string]json.RawMessage, errs []error) {
+	errs = make([]error, 0, len(ids))
+	for _, id := range ids {
+		errs = append(errs, stored_requests.NotFoundError{
+			ID:       id,
+			DataType: "Floors",
+		})
+	}
+	return
+}
+
 func (fetcher EmptyFetcher) FetchCategories(ctx context.Context, primaryAdServer, publisherId, iabCategory string) (string, error) {
 	return "", nil
 }
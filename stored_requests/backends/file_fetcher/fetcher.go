@@ -1,12 +1,14 @@
 package file_fetcher
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
 	"strings"
+	"sync"
 
 	"github.com/prebid/prebid-server/v4/stored_requests"
 	"github.com/prebid/prebid-server/v4/util/jsonutil"
@@ -20,17 +22,28 @@ import (
 // For example, when asked to fetch the request with ID == "23", it will return the data from "directory/23.json".
 func NewFileFetcher(directory string) (stored_requests.AllFetcher, error) {
 	storedData, err := collectStoredData(directory, FileSystem{make(map[string]FileSystem), make(map[string]json.RawMessage)}, nil)
-	return &eagerFetcher{storedData, nil}, err
+	return &eagerFetcher{FileSystem: storedData}, err
 }
 
 type eagerFetcher struct {
 	FileSystem FileSystem
 	Categories map[string]map[string]stored_requests.Category
+	// lock guards the file system and the categories, which are replaced when the files are reloaded
+	lock sync.RWMutex
+}
+
+// fileSystem returns the current file system. It's replaced as a whole on reload, so the returned one may be
+// read without the lock.
+func (fetcher *eagerFetcher) fileSystem() FileSystem {
+	fetcher.lock.RLock()
+	defer fetcher.lock.RUnlock()
+	return fetcher.FileSystem
 }
 
 func (fetcher *eagerFetcher) FetchRequests(ctx context.Context, requestIDs []string, impIDs []string) (map[string]json.RawMessage, map[string]json.RawMessage, []error) {
-	storedRequests := fetcher.FileSystem.Directories["stored_requests"].Files
-	storedImpressions := fetcher.FileSystem.Directories["stored_imps"].Files
+	fileSystem := fetcher.fileSystem()
+	storedRequests := fileSystem.Directories["stored_requests"].Files
+	storedImpressions := fileSystem.Directories["stored_imps"].Files
 	errs := appendErrors("Request", requestIDs, storedRequests, nil)
 	errs = appendErrors("Imp", impIDs, storedImpressions, errs)
 	return storedRequests, storedImpressions, errs
@@ -38,7 +51,7 @@ func (fetcher *eagerFetcher) FetchRequests(ctx context.Context, requestIDs []str
 
 // Fetch Responses - Implements the interface to read the stored response information from the fetcher's FileSystem, the directory name is "stored_responses"
 func (fetcher *eagerFetcher) FetchResponses(ctx context.Context, ids []string) (data map[string]json.RawMessage, errs []error) {
-	storedRespFS, found := fetcher.FileSystem.Directories["stored_responses"]
+	storedRespFS, found := fetcher.fileSystem().Directories["stored_responses"]
 	if !found {
 		return nil, append(errs, errors.New(`no "stored_responses" directory found`))
 	}
@@ -47,12 +60,18 @@ func (fetcher *eagerFetcher) FetchResponses(ctx context.Context, ids []string) (
 	return data, appendErrors("Response", ids, data, nil)
 }
 
+// FetchFloors reads the floor rule sets from the fetcher's FileSystem, the directory name is "floors"
+func (fetcher *eagerFetcher) FetchFloors(ctx context.Context, ids []string) (data map[string]json.RawMessage, errs []error) {
+	data = fetcher.fileSystem().Directories["floors"].Files
+	return data, appendErrors("Floors", ids, data, nil)
+}
+
 // FetchAccount fetches the host account configuration for a publisher
 func (fetcher *eagerFetcher) FetchAccount(ctx context.Context, accountDefaultsJSON json.RawMessage, accountID string) (json.RawMessage, []error) {
 	if len(accountID) == 0 {
 		return nil, []error{fmt.Errorf("Cannot look up an empty accountID")}
 	}
-	accountJSON, ok := fetcher.FileSystem.Directories["accounts"].Files[accountID]
+	accountJSON, ok := fetcher.fileSystem().Directories["accounts"].Files[accountID]
 	if !ok {
 		return nil, []error{stored_requests.NotFoundError{
 			ID:       accountID,
@@ -77,14 +96,16 @@ func (fetcher *eagerFetcher) FetchCategories(ctx context.Context, primaryAdServe
 		fileName = primaryAdServer + "_" + publisherId
 	}
 
-	if fetcher.Categories == nil {
-		fetcher.Categories = make(map[string]map[string]stored_requests.Category)
-	}
-	if data, ok := fetcher.Categories[fileName]; ok {
+	fetcher.lock.RLock()
+	data, ok := fetcher.Categories[fileName]
+	fileSystem := fetcher.FileSystem
+	fetcher.lock.RUnlock()
+
+	if ok {
 		return data[iabCategory].Id, nil
 	}
 
-	if primaryAdServerDir, found := fetcher.FileSystem.Directories[primaryAdServer]; found {
+	if primaryAdServerDir, found := fileSystem.Directories[primaryAdServer]; found {
 
 		if file, ok := primaryAdServerDir.Files[fileName]; ok {
 
@@ -93,9 +114,8 @@ func (fetcher *eagerFetcher) FetchCategories(ctx context.Context, primaryAdServe
 			if err := jsonutil.UnmarshalValid(file, &tmp); err != nil {
 				return "", fmt.Errorf("Unable to unmarshal categories for adserver: '%s', publisherId: '%s'", primaryAdServer, publisherId)
 			}
-			fetcher.Categories[fileName] = tmp
+			fetcher.storeCategories(primaryAdServer, fileName, file, tmp)
 			resultCategory := tmp[iabCategory].Id
-			primaryAdServerDir.Files[fileName] = nil
 
 			if len(resultCategory) == 0 {
 				return "", fmt.Errorf("Unable to find category for adserver '%s', publisherId: '%s', iab category: '%s'", primaryAdServer, publisherId, iabCategory)
@@ -113,6 +133,21 @@ func (fetcher *eagerFetcher) FetchCategories(ctx context.Context, primaryAdServe
 
 }
 
+// storeCategories caches the categories read from the mapping file, unless the file was reloaded since it was
+// read, in which case the categories are read again from the new file by the next fetch.
+func (fetcher *eagerFetcher) storeCategories(primaryAdServer, fileName string, file json.RawMessage, categories map[string]stored_requests.Category) {
+	fetcher.lock.Lock()
+	defer fetcher.lock.Unlock()
+
+	if !bytes.Equal(fetcher.FileSystem.Directories[primaryAdServer].Files[fileName], file) {
+		return
+	}
+	if fetcher.Categories == nil {
+		fetcher.Categories = make(map[string]map[string]stored_requests.Category)
+	}
+	fetcher.Categories[fileName] = categories
+}
+
 type FileSystem struct {
 	Directories map[string]FileSystem
 	Files       map[string]json.RawMessage
@@ -99,6 +99,18 @@ func TestAccountFetcher(t *testing.T) {
 
 }
 
+func TestFloorsFetcher(t *testing.T) {
+	fetcher, err := NewFileFetcher("./test")
+	assert.NoError(t, err, "Failed to create test fetcher")
+	floorsFetcher, ok := fetcher.(stored_requests.FloorsFetcher)
+	assert.True(t, ok, "The file fetcher should fetch floors")
+
+	data, errs := floorsFetcher.FetchFloors(context.Background(), []string{"floors1", "nonexistent"})
+	assertErrorCount(t, 1, errs)
+	assert.Equal(t, stored_requests.NotFoundError{ID: "nonexistent", DataType: "Floors"}, errs[0])
+	assert.JSONEq(t, `{"currency":"USD","modelgroups":[{"schema":{"fields":["mediaType"]},"values":{"banner":1.5}}]}`, string(data["floors1"]))
+}
+
 func TestInvalidDirectory(t *testing.T) {
 	_, err := NewFileFetcher("./nonexistant-directory")
 	if err == nil {
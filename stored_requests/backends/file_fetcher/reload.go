@@ -0,0 +1,207 @@
+package file_fetcher
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prebid/prebid-server/v4/logger"
+	"github.com/prebid/prebid-server/v4/stored_requests"
+	"github.com/prebid/prebid-server/v4/stored_requests/events"
+)
+
+var errInvalidJSON = errors.New("invalid JSON")
+
+// NewReloadingFileFetcher loads stored data from local files like NewFileFetcher, then reloads them every
+// refreshRate so that the files can be edited without a restart. Files which can't be read or which aren't
+// valid JSON are logged and skipped, and the fetcher keeps their last good version.
+//
+// If produceEvents is true, the changes to the stored requests, imps, accounts, responses and floors are also sent
+// through the returned EventProducer, so that the caches in front of the fetcher are updated. The events
+// must then be listened to, since the reload waits until they're received or the reloads are stopped.
+//
+// The returned function stops the reloads, and must be called on shutdown.
+func NewReloadingFileFetcher(directory string, refreshRate time.Duration, produceEvents bool) (stored_requests.AllFetcher, events.EventProducer, func(), error) {
+	fetcher, err := NewFileFetcher(directory)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	reloader := &fileReloader{
+		fetcher:       fetcher.(*eagerFetcher),
+		directory:     directory,
+		produceEvents: produceEvents,
+		saves:         make(chan events.Save, 1),
+		invalidations: make(chan events.Invalidation, 1),
+		stop:          make(chan struct{}),
+	}
+	go reloader.refresh(time.NewTicker(refreshRate))
+
+	var stopOnce sync.Once
+	stop := func() {
+		stopOnce.Do(func() { close(reloader.stop) })
+	}
+
+	if !produceEvents {
+		return fetcher, nil, stop, nil
+	}
+	return fetcher, reloader, stop, nil
+}
+
+type fileReloader struct {
+	fetcher       *eagerFetcher
+	directory     string
+	produceEvents bool
+	saves         chan events.Save
+	invalidations chan events.Invalidation
+	// stop is closed to stop the reloads, including a reload waiting for its events to be received
+	stop chan struct{}
+}
+
+func (r *fileReloader) Saves() <-chan events.Save {
+	return r.saves
+}
+
+func (r *fileReloader) Invalidations() <-chan events.Invalidation {
+	return r.invalidations
+}
+
+func (r *fileReloader) refresh(ticker *time.Ticker) {
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			r.reload()
+		case <-r.stop:
+			return
+		}
+	}
+}
+
+// reload reads the directory again and applies the changes to the fetcher.
+func (r *fileReloader) reload() {
+	previous := r.fetcher.fileSystem()
+	current, err := reloadStoredData(r.directory, previous)
+	if err != nil {
+		logger.Errorf("Failed to reload the stored data from %s, the previous data is kept: %v", r.directory, err)
+		return
+	}
+	if equalFileSystems(previous, current) {
+		return
+	}
+
+	r.fetcher.lock.Lock()
+	r.fetcher.FileSystem = current
+	r.fetcher.Categories = nil
+	r.fetcher.lock.Unlock()
+
+	if !r.produceEvents {
+		return
+	}
+
+	var save events.Save
+	var invalidation events.Invalidation
+	save.Requests, invalidation.Requests = diffFiles(previous.Directories["stored_requests"], current.Directories["stored_requests"])
+	save.Imps, invalidation.Imps = diffFiles(previous.Directories["stored_imps"], current.Directories["stored_imps"])
+	save.Accounts, invalidation.Accounts = diffFiles(previous.Directories["accounts"], current.Directories["accounts"])
+	save.Responses, invalidation.Responses = diffFiles(previous.Directories["stored_responses"], current.Directories["stored_responses"])
+	save.Floors, invalidation.Floors = diffFiles(previous.Directories["floors"], current.Directories["floors"])
+
+	if len(save.Requests) > 0 || len(save.Imps) > 0 || len(save.Accounts) > 0 || len(save.Responses) > 0 || len(save.Floors) > 0 {
+		select {
+		case r.saves <- save:
+		case <-r.stop:
+			return
+		}
+	}
+	if len(invalidation.Requests) > 0 || len(invalidation.Imps) > 0 || len(invalidation.Accounts) > 0 || len(invalidation.Responses) > 0 || len(invalidation.Floors) > 0 {
+		select {
+		case r.invalidations <- invalidation:
+		case <-r.stop:
+		}
+	}
+}
+
+// reloadStoredData reads the directory like collectStoredData, except that the files which can't be read or
+// aren't valid JSON are replaced by their previous version, if any. An error is only returned if a directory
+// can't be read.
+func reloadStoredData(directory string, previous FileSystem) (FileSystem, error) {
+	entries, err := os.ReadDir(directory)
+	if err != nil {
+		return FileSystem{}, err
+	}
+
+	fileSystem := FileSystem{make(map[string]FileSystem), make(map[string]json.RawMessage)}
+	for _, entry := range entries {
+		path := filepath.Join(directory, entry.Name())
+
+		if entry.IsDir() {
+			subdirectory, err := reloadStoredData(path, previous.Directories[entry.Name()])
+			if err != nil {
+				return FileSystem{}, err
+			}
+			fileSystem.Directories[entry.Name()] = subdirectory
+			continue
+		}
+
+		if !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+		id := strings.TrimSuffix(entry.Name(), ".json")
+
+		data, err := os.ReadFile(path)
+		if err == nil && !json.Valid(data) {
+			err = errInvalidJSON
+		}
+		if err != nil {
+			logger.Errorf("Skipping stored data file %s, its last good version is kept if any: %v", path, err)
+			if previousData, ok := previous.Files[id]; ok {
+				fileSystem.Files[id] = previousData
+			}
+			continue
+		}
+		fileSystem.Files[id] = json.RawMessage(data)
+	}
+	return fileSystem, nil
+}
+
+// diffFiles returns the files which were added or changed, and the ids of the files which were removed.
+func diffFiles(previous, current FileSystem) (saved map[string]json.RawMessage, removed []string) {
+	for id, data := range current.Files {
+		if previousData, ok := previous.Files[id]; !ok || !bytes.Equal(previousData, data) {
+			if saved == nil {
+				saved = make(map[string]json.RawMessage)
+			}
+			saved[id] = data
+		}
+	}
+	for id := range previous.Files {
+		if _, ok := current.Files[id]; !ok {
+			removed = append(removed, id)
+		}
+	}
+	return saved, removed
+}
+
+func equalFileSystems(a, b FileSystem) bool {
+	if len(a.Files) != len(b.Files) || len(a.Directories) != len(b.Directories) {
+		return false
+	}
+	for id, data := range a.Files {
+		if otherData, ok := b.Files[id]; !ok || !bytes.Equal(data, otherData) {
+			return false
+		}
+	}
+	for name, directory := range a.Directories {
+		otherDirectory, ok := b.Directories[name]
+		if !ok || !equalFileSystems(directory, otherDirectory) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,160 @@
+package file_fetcher
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/prebid/prebid-server/v4/stored_requests"
+	"github.com/prebid/prebid-server/v4/stored_requests/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeStoredFile(t *testing.T, directory, subdirectory, id, data string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Join(directory, subdirectory), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(directory, subdirectory, id+".json"), []byte(data), 0644))
+}
+
+func newTestReloader(t *testing.T, directory string, produceEvents bool) (stored_requests.AllFetcher, *fileReloader) {
+	fetcher, err := NewFileFetcher(directory)
+	require.NoError(t, err)
+	reloader := &fileReloader{
+		fetcher:       fetcher.(*eagerFetcher),
+		directory:     directory,
+		produceEvents: produceEvents,
+		saves:         make(chan events.Save, 1),
+		invalidations: make(chan events.Invalidation, 1),
+		stop:          make(chan struct{}),
+	}
+	return fetcher, reloader
+}
+
+func TestReloadEvents(t *testing.T) {
+	directory := t.TempDir()
+	writeStoredFile(t, directory, "stored_requests", "changed", `{"v":1}`)
+	writeStoredFile(t, directory, "stored_requests", "removed", `{"v":1}`)
+	writeStoredFile(t, directory, "stored_imps", "same", `{"v":1}`)
+	writeStoredFile(t, directory, "accounts", "acc", `{"v":1}`)
+	fetcher, reloader := newTestReloader(t, directory, true)
+
+	writeStoredFile(t, directory, "stored_requests", "changed", `{"v":2}`)
+	require.NoError(t, os.Remove(filepath.Join(directory, "stored_requests", "removed.json")))
+	writeStoredFile(t, directory, "stored_responses", "added", `{"v":1}`)
+	writeStoredFile(t, directory, "accounts", "acc", `{"v":`)
+	reloader.reload()
+
+	assert.Equal(t, events.Save{
+		Requests:  map[string]json.RawMessage{"changed": json.RawMessage(`{"v":2}`)},
+		Responses: map[string]json.RawMessage{"added": json.RawMessage(`{"v":1}`)},
+	}, <-reloader.Saves())
+	assert.Equal(t, events.Invalidation{Requests: []string{"removed"}}, <-reloader.Invalidations())
+
+	requests, imps, errs := fetcher.FetchRequests(context.Background(), []string{"changed", "removed"}, []string{"same"})
+	assert.Equal(t, map[string]json.RawMessage{"changed": json.RawMessage(`{"v":2}`)}, requests)
+	assert.Equal(t, map[string]json.RawMessage{"same": json.RawMessage(`{"v":1}`)}, imps)
+	assert.Equal(t, []error{stored_requests.NotFoundError{ID: "removed", DataType: "Request"}}, errs)
+
+	account, errs := fetcher.FetchAccount(context.Background(), nil, "acc")
+	assert.Empty(t, errs)
+	assert.JSONEq(t, `{"v":1}`, string(account), "the last good version of a malformed file must be kept")
+}
+
+func TestReloadWithoutChanges(t *testing.T) {
+	directory := t.TempDir()
+	writeStoredFile(t, directory, "stored_requests", "1", `{"v":1}`)
+	_, reloader := newTestReloader(t, directory, true)
+
+	reloader.reload()
+
+	assert.Empty(t, reloader.saves)
+	assert.Empty(t, reloader.invalidations)
+}
+
+func TestReloadWithoutEvents(t *testing.T) {
+	directory := t.TempDir()
+	writeStoredFile(t, directory, "stored_requests", "1", `{"v":1}`)
+	fetcher, reloader := newTestReloader(t, directory, false)
+
+	writeStoredFile(t, directory, "stored_requests", "1", `{"v":2}`)
+	reloader.reload()
+
+	assert.Empty(t, reloader.saves)
+	requests, _, errs := fetcher.FetchRequests(context.Background(), []string{"1"}, nil)
+	assert.Empty(t, errs)
+	assert.Equal(t, json.RawMessage(`{"v":2}`), requests["1"])
+}
+
+func TestReloadCategories(t *testing.T) {
+	directory := t.TempDir()
+	writeStoredFile(t, directory, "adserver", "adserver", `{"IAB1-1":{"id":"first"}}`)
+	fetcher, reloader := newTestReloader(t, directory, false)
+
+	category, err := fetcher.FetchCategories(context.Background(), "adserver", "", "IAB1-1")
+	assert.NoError(t, err)
+	assert.Equal(t, "first", category)
+
+	writeStoredFile(t, directory, "adserver", "adserver", `{"IAB1-1":{"id":"second"}}`)
+	reloader.reload()
+
+	category, err = fetcher.FetchCategories(context.Background(), "adserver", "", "IAB1-1")
+	assert.NoError(t, err)
+	assert.Equal(t, "second", category)
+}
+
+func TestReloadDirectoryRemoved(t *testing.T) {
+	directory := t.TempDir()
+	writeStoredFile(t, directory, "stored_requests", "1", `{"v":1}`)
+	fetcher, reloader := newTestReloader(t, directory, true)
+
+	reloader.directory = filepath.Join(directory, "missing")
+	reloader.reload()
+
+	assert.Empty(t, reloader.invalidations)
+	requests, _, errs := fetcher.FetchRequests(context.Background(), []string{"1"}, nil)
+	assert.Empty(t, errs)
+	assert.Equal(t, json.RawMessage(`{"v":1}`), requests["1"])
+}
+
+func TestReloadStopped(t *testing.T) {
+	directory := t.TempDir()
+	writeStoredFile(t, directory, "stored_requests", "req", `{"v":1}`)
+	_, reloader := newTestReloader(t, directory, true)
+	// nobody receives the events, so the reload waits once the channel is full
+	reloader.saves <- events.Save{}
+
+	writeStoredFile(t, directory, "stored_requests", "req", `{"v":2}`)
+	done := make(chan struct{})
+	go func() {
+		reloader.reload()
+		close(done)
+	}()
+	close(reloader.stop)
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("the reload should return once stopped")
+	}
+}
+
+func TestReloadingFileFetcherStop(t *testing.T) {
+	directory := t.TempDir()
+	writeStoredFile(t, directory, "stored_requests", "req", `{"v":1}`)
+	fetcher, eventProducer, stop, err := NewReloadingFileFetcher(directory, time.Millisecond, true)
+	require.NoError(t, err)
+	require.NotNil(t, eventProducer)
+	stop()
+	stop()
+
+	// the files aren't reloaded anymore
+	time.Sleep(10 * time.Millisecond)
+	writeStoredFile(t, directory, "stored_requests", "req", `{"v":2}`)
+	time.Sleep(10 * time.Millisecond)
+	requests, _, _ := fetcher.FetchRequests(context.Background(), []string{"req"}, nil)
+	assert.JSONEq(t, `{"v":1}`, string(requests["req"]))
+}
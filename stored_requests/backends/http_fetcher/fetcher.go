@@ -32,6 +32,12 @@ import (
 // If UseRfcCompliantBuilder is true (symbols will be URLEncoded)
 // GET {endpoint}?account-id=acc1&account-id=acc2
 //
+// Floors
+// GET {endpoint}?floor-ids=["floors1","floors2"]
+//
+// If UseRfcCompliantBuilder is true (symbols will be URLEncoded)
+// GET {endpoint}?floor-id=floors1&floor-id=floors2
+//
 // The above endpoints should return a payload like:
 //
 //	{
@@ -54,6 +60,15 @@ import (
 //	    "acc2": { ... config data for acc2 ... },
 //	  },
 //	}
+//
+// or
+//
+//	{
+//	  "floors": {
+//	    "floors1": { ... floor rule set ... },
+//	    "floors2": null // If floors2 is not found
+//	  },
+//	}
 func NewFetcher(client *http.Client, endpoint string, useRfcCompliantBuilder bool) *HttpFetcher {
 	endpointURL, err := url.Parse(endpoint)
 
@@ -187,6 +202,57 @@ func (fetcher *HttpFetcher) FetchAccount(ctx context.Context, accountDefaultsJSO
 	return completeJSON, nil
 }
 
+// FetchFloors retrieves the floor rule sets with the given IDs
+func (fetcher *HttpFetcher) FetchFloors(ctx context.Context, ids []string) (map[string]json.RawMessage, []error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	u := *fetcher.EndpointURL
+	q := u.Query()
+	AddQueryParam(&q, "floor-id", ids, fetcher.UseRfcCompliantBuilder)
+	u.RawQuery = q.Encode()
+	httpReq, err := http.NewRequestWithContext(ctx, "GET", u.String(), nil)
+	if err != nil {
+		return nil, []error{
+			fmt.Errorf(`Error fetching floors %v via http: build request failed with %v`, ids, err),
+		}
+	}
+	httpResp, err := ctxhttp.Do(ctx, fetcher.client, httpReq)
+	if err != nil {
+		return nil, []error{
+			fmt.Errorf(`Error fetching floors %v via http: %v`, ids, err),
+		}
+	}
+	defer httpResp.Body.Close()
+
+	respBytes, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return nil, []error{
+			fmt.Errorf(`Error fetching floors %v via http: error reading response: %v`, ids, err),
+		}
+	}
+	if httpResp.StatusCode != http.StatusOK {
+		return nil, []error{
+			fmt.Errorf(`Error fetching floors %v via http: unexpected response status %d`, ids, httpResp.StatusCode),
+		}
+	}
+	var responseData floorsResponseContract
+	if err = jsonutil.UnmarshalValid(respBytes, &responseData); err != nil {
+		return nil, []error{
+			fmt.Errorf(`Error fetching floors %v via http: failed to parse response: %v`, ids, err),
+		}
+	}
+	var errs []error
+	for _, id := range ids {
+		// the ids which are null or missing weren't found
+		if data := responseData.Floors[id]; data == nil {
+			delete(responseData.Floors, id)
+			errs = append(errs, stored_requests.NotFoundError{ID: id, DataType: "Floors"})
+		}
+	}
+	return responseData.Floors, errs
+}
+
 func (fetcher *HttpFetcher) FetchCategories(ctx context.Context, primaryAdServer, publisherId, iabCategory string) (string, error) {
 	if fetcher.Categories == nil {
 		fetcher.Categories = make(map[string]map[string]stored_requests.Category)
@@ -314,3 +380,7 @@ type responseContract struct {
 type accountsResponseContract struct {
 	Accounts map[string]json.RawMessage `json:"accounts"`
 }
+
+type floorsResponseContract struct {
+	Floors map[string]json.RawMessage `json:"floors"`
+}
@@ -10,6 +10,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/prebid/prebid-server/v4/stored_requests"
 	"github.com/prebid/prebid-server/v4/util/jsonutil"
 	"github.com/stretchr/testify/assert"
 )
@@ -353,6 +354,63 @@ func TestFetchAccountNoIDProvided(t *testing.T) {
 	assert.Nil(t, account, "Fetching account with empty id should return nil")
 }
 
+func TestFetchFloorsRfcCompliant(t *testing.T) {
+	fetcher, close := newTestFloorsFetcher(t, []string{"floors-1", "floors-2"}, true)
+	defer close()
+
+	floorsData, errs := fetcher.FetchFloors(context.Background(), []string{"floors-1", "floors-2"})
+	assert.Empty(t, errs, "Unexpected error fetching known floors")
+	assertMapKeys(t, floorsData, "floors-1", "floors-2")
+}
+
+func TestFetchFloors(t *testing.T) {
+	fetcher, close := newTestFloorsFetcher(t, []string{"floors-1", "floors-2"}, false)
+	defer close()
+
+	floorsData, errs := fetcher.FetchFloors(context.Background(), []string{"floors-1", "floors-2"})
+	assert.Empty(t, errs, "Unexpected error fetching known floors")
+	assertMapKeys(t, floorsData, "floors-1", "floors-2")
+}
+
+func TestFetchFloorsMissingValues(t *testing.T) {
+	fetcher, close := newTestFloorsFetcher(t, []string{"floors-1", "floors-missing", "floors-null"}, true)
+	defer close()
+
+	floorsData, errs := fetcher.FetchFloors(context.Background(), []string{"floors-1", "floors-missing", "floors-null"})
+	assert.ElementsMatch(t, []error{
+		stored_requests.NotFoundError{ID: "floors-missing", DataType: "Floors"},
+		stored_requests.NotFoundError{ID: "floors-null", DataType: "Floors"},
+	}, errs)
+	assertMapKeys(t, floorsData, "floors-1")
+}
+
+func TestFetchFloorsNoData(t *testing.T) {
+	fetcher, close := newFetcherBrokenBackend()
+	defer close()
+
+	floorsData, errs := fetcher.FetchFloors(context.Background(), []string{"floors-1"})
+	assert.Len(t, errs, 1, "Fetching floors from a broken backend should have returned an error")
+	assert.Nil(t, floorsData, "Fetching floors from a broken backend should return nil floors map")
+}
+
+func TestFetchFloorsBadJSON(t *testing.T) {
+	fetcher, close := newFetcherBadJSON()
+	defer close()
+
+	floorsData, errs := fetcher.FetchFloors(context.Background(), []string{"floors-1"})
+	assert.Len(t, errs, 1, "Fetching floors with broken json should have returned an error")
+	assert.Nil(t, floorsData, "Fetching floors with broken json should return nil floors map")
+}
+
+func TestFetchFloorsNoIDsProvided(t *testing.T) {
+	fetcher, close := newTestFloorsFetcher(t, nil, true)
+	defer close()
+
+	floorsData, errs := fetcher.FetchFloors(context.Background(), nil)
+	assert.Empty(t, errs, "Unexpected error fetching empty floors list")
+	assert.Nil(t, floorsData, "Fetching empty floors list should return nil")
+}
+
 func TestErrResponse(t *testing.T) {
 	fetcher, close := newFetcherBrokenBackend()
 	defer close()
@@ -440,6 +498,43 @@ func newTestAccountFetcher(t *testing.T, expectAccIDs []string, useRfcCompliantB
 	return NewFetcher(server.Client(), server.URL, useRfcCompliantBuilder), server.Close
 }
 
+// newTestFloorsFetcher returns a fetcher of the expected floor IDs. The IDs ending with "-missing" are left out
+// of the response, and the ones ending with "-null" are null.
+func newTestFloorsFetcher(t *testing.T, expectFloorIDs []string, useRfcCompliantBuilder bool) (fetcher *HttpFetcher, closer func()) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		var gotFloorIDs []string
+		if !useRfcCompliantBuilder {
+			gotFloorIDs = richSplit(query.Get("floor-ids"))
+		} else {
+			gotFloorIDs = query["floor-id"]
+		}
+
+		assertMatches(t, gotFloorIDs, expectFloorIDs)
+
+		floorIDResponse := make(map[string]json.RawMessage, len(gotFloorIDs))
+		for _, floorID := range gotFloorIDs {
+			switch {
+			case strings.HasSuffix(floorID, "-missing"):
+			case strings.HasSuffix(floorID, "-null"):
+				floorIDResponse[floorID] = nil
+			default:
+				floorIDResponse[floorID] = jsonifyID(floorID)
+			}
+		}
+
+		respBytes, err := jsonutil.Marshal(floorsResponseContract{Floors: floorIDResponse})
+		if err != nil {
+			t.Errorf("failed to marshal responseContract in test:  %v", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Write(respBytes)
+	}
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	return NewFetcher(server.Client(), server.URL, useRfcCompliantBuilder), server.Close
+}
+
 func newAccountHandler(t *testing.T, expectAccIDs []string, useRfcCompliantBuilder bool) func(w http.ResponseWriter, r *http.Request) {
 	return func(w http.ResponseWriter, r *http.Request) {
 		query := r.URL.Query()
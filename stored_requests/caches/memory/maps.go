@@ -15,6 +15,8 @@ import (
 type mapLike interface {
 	Get(id string) (json.RawMessage, bool)
 	Set(id string, value json.RawMessage)
+	// SetWithTTL works like Set, except that the value expires after the given TTL if the map supports it.
+	SetWithTTL(id string, value json.RawMessage, ttlSeconds int)
 	Delete(id string)
 }
 
@@ -36,6 +38,10 @@ func (m *pbsSyncMap) Set(id string, value json.RawMessage) {
 	m.Map.Store(id, value)
 }
 
+func (m *pbsSyncMap) SetWithTTL(id string, value json.RawMessage, ttlSeconds int) {
+	m.Map.Store(id, value)
+}
+
 func (m *pbsSyncMap) Delete(id string) {
 	m.Map.Delete(id)
 }
@@ -58,7 +64,11 @@ func (m *pbsLRUCache) Get(id string) (json.RawMessage, bool) {
 }
 
 func (m *pbsLRUCache) Set(id string, value json.RawMessage) {
-	if err := m.Cache.Set([]byte(id), value, m.ttlSeconds); err != nil {
+	m.SetWithTTL(id, value, m.ttlSeconds)
+}
+
+func (m *pbsLRUCache) SetWithTTL(id string, value json.RawMessage, ttlSeconds int) {
+	if err := m.Cache.Set([]byte(id), value, ttlSeconds); err != nil {
 		logger.Errorf("error saving value in freecache: %v", err)
 	}
 }
@@ -0,0 +1,155 @@
+package memory
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/coocood/freecache"
+	"github.com/prebid/prebid-server/v4/logger"
+	"github.com/prebid/prebid-server/v4/stored_requests"
+	"github.com/prebid/prebid-server/v4/util/timeutil"
+)
+
+// The entries of a revalidating cache are prefixed by a header made of their kind and
+// the time, in unix nanoseconds, until which they are fresh. A zero time never expires.
+const (
+	entryData byte = iota
+	entryNotFound
+
+	entryHeaderSize = 9
+)
+
+// NewRevalidatingCache returns an in-memory Cache like NewCache, which also:
+//
+// 1. Keeps the values for staleTTLSeconds past their TTL, during which they're returned as stale by Lookup.
+// 2. Remembers the IDs saved with SaveNotFound for notFoundTTLSeconds. This requires a size limit.
+//
+// For no stale values, or no IDs remembered as not found, use a TTL <= 0.
+func NewRevalidatingCache(size int, ttlSeconds int, staleTTLSeconds int, notFoundTTLSeconds int, dataType string) stored_requests.RevalidatingCacheJSON {
+	if ttlSeconds > 0 && size <= 0 {
+		// a positive ttl indicates "LRU" cache type, while unlimited size indicates an "unbounded" cache type
+		logger.Fatalf("unbounded in-memory %s cache with TTL not allowed. Config validation should have caught this. Failing fast because something is buggy.", dataType)
+	}
+	if notFoundTTLSeconds > 0 && size <= 0 {
+		// the IDs not found would only be removed when they're looked up again
+		logger.Fatalf("unbounded in-memory %s cache with not found TTL not allowed. Config validation should have caught this. Failing fast because something is buggy.", dataType)
+	}
+
+	c := &revalidatingCache{
+		dataType:           dataType,
+		notFoundTTLSeconds: notFoundTTLSeconds,
+		time:               &timeutil.RealTime{},
+	}
+	// the stale values are kept until they're evicted by the lru cache
+	lruTTLSeconds := ttlSeconds
+	if ttlSeconds > 0 && staleTTLSeconds > 0 {
+		c.ttl = time.Duration(ttlSeconds) * time.Second
+		lruTTLSeconds += staleTTLSeconds
+	}
+
+	if size > 0 {
+		logger.Infof("Using a Stored %s in-memory cache. Max size: %d bytes. TTL: %d seconds. Stale TTL: %d seconds. Not found TTL: %d seconds.", dataType, size, ttlSeconds, staleTTLSeconds, notFoundTTLSeconds)
+		c.cache = &pbsLRUCache{
+			Cache:      freecache.NewCache(size),
+			ttlSeconds: lruTTLSeconds,
+		}
+	} else {
+		logger.Infof("Using an unbounded Stored %s in-memory cache.", dataType)
+		c.cache = &pbsSyncMap{&sync.Map{}}
+	}
+	return c
+}
+
+type revalidatingCache struct {
+	dataType string
+	cache    mapLike
+	// ttl is the duration for which the values are fresh, or zero if they don't go stale
+	ttl                time.Duration
+	notFoundTTLSeconds int
+	time               timeutil.Time
+}
+
+func (c *revalidatingCache) Get(ctx context.Context, ids []string) (data map[string]json.RawMessage) {
+	data, _, _ = c.Lookup(ctx, ids)
+	return
+}
+
+func (c *revalidatingCache) Lookup(ctx context.Context, ids []string) (fresh map[string]json.RawMessage, stale map[string]json.RawMessage, notFound []string) {
+	fresh = make(map[string]json.RawMessage, len(ids))
+	stale = make(map[string]json.RawMessage)
+	now := c.time.Now().UnixNano()
+
+	for _, id := range ids {
+		val, ok := c.cache.Get(id)
+		if !ok {
+			continue
+		}
+		kind, expiresAt, data, ok := decodeEntry(val)
+		if !ok {
+			logger.Errorf("invalid entry for %s %s in the in-memory cache", c.dataType, id)
+			c.cache.Delete(id)
+			continue
+		}
+
+		switch {
+		case kind == entryNotFound && now < expiresAt:
+			notFound = append(notFound, id)
+		case kind == entryNotFound:
+			// the lru cache expires the entries with a precision of a second
+			c.cache.Delete(id)
+		case expiresAt == 0 || now < expiresAt:
+			fresh[id] = data
+		default:
+			stale[id] = data
+		}
+	}
+	return
+}
+
+func (c *revalidatingCache) Save(ctx context.Context, data map[string]json.RawMessage) {
+	var expiresAt int64
+	if c.ttl > 0 {
+		expiresAt = c.time.Now().Add(c.ttl).UnixNano()
+	}
+	for id, data := range data {
+		c.cache.Set(id, encodeEntry(entryData, expiresAt, data))
+	}
+}
+
+func (c *revalidatingCache) SaveNotFound(ctx context.Context, ids []string) {
+	if c.notFoundTTLSeconds <= 0 {
+		return
+	}
+	expiresAt := c.time.Now().Add(time.Duration(c.notFoundTTLSeconds) * time.Second).UnixNano()
+	for _, id := range ids {
+		if val, ok := c.cache.Get(id); ok {
+			if kind, _, _, ok := decodeEntry(val); ok && kind == entryData {
+				continue
+			}
+		}
+		c.cache.SetWithTTL(id, encodeEntry(entryNotFound, expiresAt, nil), c.notFoundTTLSeconds)
+	}
+}
+
+func (c *revalidatingCache) Invalidate(ctx context.Context, ids []string) {
+	for _, id := range ids {
+		c.cache.Delete(id)
+	}
+}
+
+func encodeEntry(kind byte, expiresAt int64, data json.RawMessage) json.RawMessage {
+	entry := make([]byte, entryHeaderSize, entryHeaderSize+len(data))
+	entry[0] = kind
+	binary.BigEndian.PutUint64(entry[1:entryHeaderSize], uint64(expiresAt))
+	return append(entry, data...)
+}
+
+func decodeEntry(entry json.RawMessage) (kind byte, expiresAt int64, data json.RawMessage, ok bool) {
+	if len(entry) < entryHeaderSize {
+		return 0, 0, nil, false
+	}
+	return entry[0], int64(binary.BigEndian.Uint64(entry[1:entryHeaderSize])), entry[entryHeaderSize:], true
+}
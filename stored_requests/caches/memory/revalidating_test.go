@@ -0,0 +1,118 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/prebid/prebid-server/v4/stored_requests"
+	"github.com/prebid/prebid-server/v4/stored_requests/caches/cachestest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRevalidatingLRURobustness(t *testing.T) {
+	cachestest.AssertCacheRobustness(t, func() stored_requests.CacheJSON {
+		return NewRevalidatingCache(256*1024, 60, 60, 10, "TestData")
+	})
+}
+
+func TestRevalidatingUnboundedRobustness(t *testing.T) {
+	cachestest.AssertCacheRobustness(t, func() stored_requests.CacheJSON {
+		return NewRevalidatingCache(0, -1, 0, 0, "TestData")
+	})
+}
+
+func TestRaceRevalidatingConcurrency(t *testing.T) {
+	cache := NewRevalidatingCache(256*1024, 60, 60, 10, "TestData")
+	doRaceTest(t, cache)
+}
+
+func TestRevalidatingCacheStale(t *testing.T) {
+	now := &fakeTime{time: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	cache := NewRevalidatingCache(256*1024, 60, 60, 10, "TestData").(*revalidatingCache)
+	cache.time = now
+	ctx := context.Background()
+
+	cache.Save(ctx, map[string]json.RawMessage{"id": json.RawMessage(`{"id":1}`)})
+
+	fresh, stale, notFound := cache.Lookup(ctx, []string{"id"})
+	assert.Equal(t, map[string]json.RawMessage{"id": json.RawMessage(`{"id":1}`)}, fresh)
+	assert.Empty(t, stale)
+	assert.Empty(t, notFound)
+
+	now.time = now.time.Add(61 * time.Second)
+	fresh, stale, notFound = cache.Lookup(ctx, []string{"id"})
+	assert.Empty(t, fresh)
+	assert.Equal(t, map[string]json.RawMessage{"id": json.RawMessage(`{"id":1}`)}, stale)
+	assert.Empty(t, notFound)
+	assert.Empty(t, cache.Get(ctx, []string{"id"}), "Get must not return the stale values")
+
+	cache.Save(ctx, map[string]json.RawMessage{"id": json.RawMessage(`{"id":2}`)})
+	fresh, stale, _ = cache.Lookup(ctx, []string{"id"})
+	assert.Equal(t, map[string]json.RawMessage{"id": json.RawMessage(`{"id":2}`)}, fresh)
+	assert.Empty(t, stale)
+}
+
+func TestRevalidatingCacheWithoutStaleTTL(t *testing.T) {
+	now := &fakeTime{time: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	cache := NewRevalidatingCache(256*1024, 60, 0, 10, "TestData").(*revalidatingCache)
+	cache.time = now
+	ctx := context.Background()
+
+	cache.Save(ctx, map[string]json.RawMessage{"id": json.RawMessage(`{"id":1}`)})
+	now.time = now.time.Add(61 * time.Second)
+
+	// the lru cache evicts the value by itself, so it's never stale
+	_, stale, _ := cache.Lookup(ctx, []string{"id"})
+	assert.Empty(t, stale)
+}
+
+func TestRevalidatingCacheNotFound(t *testing.T) {
+	now := &fakeTime{time: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	cache := NewRevalidatingCache(256*1024, 0, 0, 10, "TestData").(*revalidatingCache)
+	cache.time = now
+	ctx := context.Background()
+
+	cache.Save(ctx, map[string]json.RawMessage{"saved": json.RawMessage(`{}`)})
+	cache.SaveNotFound(ctx, []string{"missing", "saved"})
+
+	fresh, stale, notFound := cache.Lookup(ctx, []string{"missing", "saved", "unknown"})
+	assert.Equal(t, map[string]json.RawMessage{"saved": json.RawMessage(`{}`)}, fresh, "SaveNotFound must not overwrite the saved values")
+	assert.Empty(t, stale)
+	assert.Equal(t, []string{"missing"}, notFound)
+	assert.Empty(t, cache.Get(ctx, []string{"missing"}))
+
+	now.time = now.time.Add(11 * time.Second)
+	_, _, notFound = cache.Lookup(ctx, []string{"missing"})
+	assert.Empty(t, notFound, "the IDs not found must expire")
+
+	cache.SaveNotFound(ctx, []string{"missing"})
+	cache.Save(ctx, map[string]json.RawMessage{"missing": json.RawMessage(`{}`)})
+	fresh, _, notFound = cache.Lookup(ctx, []string{"missing"})
+	assert.Len(t, fresh, 1, "Save must overwrite the IDs not found")
+	assert.Empty(t, notFound)
+
+	cache.SaveNotFound(ctx, []string{"other"})
+	cache.Invalidate(ctx, []string{"other"})
+	_, _, notFound = cache.Lookup(ctx, []string{"other"})
+	assert.Empty(t, notFound, "Invalidate must remove the IDs not found")
+}
+
+func TestRevalidatingCacheNotFoundDisabled(t *testing.T) {
+	cache := NewRevalidatingCache(256*1024, 60, 60, 0, "TestData")
+	ctx := context.Background()
+
+	cache.SaveNotFound(ctx, []string{"missing"})
+
+	_, _, notFound := cache.Lookup(ctx, []string{"missing"})
+	assert.Empty(t, notFound)
+}
+
+type fakeTime struct {
+	time time.Time
+}
+
+func (f *fakeTime) Now() time.Time {
+	return f.time
+}
@@ -54,18 +54,26 @@ func CreateStoredRequests(cfg *config.StoredRequests, metricsEngine metrics.Metr
 		}
 	}
 
-	eventProducers := newEventProducers(cfg, client, provider, metricsEngine, router)
-	fetcher = newFetcher(cfg, client, provider)
+	eventProducers, eventProducersShutdown := newEventProducers(cfg, client, provider, metricsEngine, router)
+	fetcher, fetcherEventProducers, fetcherShutdown := newFetcher(cfg, client, provider)
+	eventProducers = append(eventProducers, fetcherEventProducers...)
 
 	var shutdown1 func()
 
 	if cfg.InMemoryCache.Type != "" {
 		cache := newCache(cfg)
-		fetcher = stored_requests.WithCache(fetcher, cache, metricsEngine)
+		fetcher = stored_requests.WithCache(fetcher, cache, metricsEngine, cfg.FetchTimeout())
 		shutdown1 = addListeners(cache, eventProducers)
 	}
 
 	shutdown = func() {
+		// the fetcher is stopped first, so that it doesn't wait for the listeners to receive its events
+		if fetcherShutdown != nil {
+			fetcherShutdown()
+		}
+		if eventProducersShutdown != nil {
+			eventProducersShutdown()
+		}
 		if shutdown1 != nil {
 			shutdown1()
 		}
@@ -90,6 +98,8 @@ func CreateStoredRequests(cfg *config.StoredRequests, metricsEngine metrics.Metr
 // 4. A Fetcher which can be used to get Account data
 // 5. A Fetcher which can be used to get Category Mapping data
 // 6. A Fetcher which can be used to get Stored Requests for /openrtb2/video
+// 7. A Fetcher which can be used to get Stored Responses
+// 8. A Fetcher which can be used to get the price floor rule sets
 //
 // If any errors occur, the program will exit with an error message.
 // It probably means you have a bad config or networking issue.
@@ -102,7 +112,8 @@ func NewStoredRequests(cfg *config.Configuration, metricsEngine metrics.MetricsE
 	accountsFetcher stored_requests.AccountFetcher,
 	categoriesFetcher stored_requests.CategoryFetcher,
 	videoFetcher stored_requests.Fetcher,
-	storedRespFetcher stored_requests.Fetcher) {
+	storedRespFetcher stored_requests.Fetcher,
+	floorsFetcher stored_requests.FloorsFetcher) {
 
 	var provider db_provider.DbProvider
 
@@ -112,13 +123,15 @@ func NewStoredRequests(cfg *config.Configuration, metricsEngine metrics.MetricsE
 	fetcher4, shutdown4 := CreateStoredRequests(&cfg.StoredVideo, metricsEngine, client, router, provider)
 	fetcher5, shutdown5 := CreateStoredRequests(&cfg.Accounts, metricsEngine, client, router, provider)
 	fetcher6, shutdown6 := CreateStoredRequests(&cfg.StoredResponses, metricsEngine, client, router, provider)
+	fetcher7, shutdown7 := CreateStoredRequests(&cfg.StoredFloors, metricsEngine, client, router, provider)
 
-	fetcher = fetcher1.(stored_requests.Fetcher)
-	ampFetcher = fetcher2.(stored_requests.Fetcher)
+	fetcher = stored_requests.WithInheritance(fetcher1.(stored_requests.Fetcher))
+	ampFetcher = stored_requests.WithInheritance(fetcher2.(stored_requests.Fetcher))
 	categoriesFetcher = fetcher3.(stored_requests.CategoryFetcher)
-	videoFetcher = fetcher4.(stored_requests.Fetcher)
+	videoFetcher = stored_requests.WithInheritance(fetcher4.(stored_requests.Fetcher))
 	accountsFetcher = fetcher5.(stored_requests.AccountFetcher)
 	storedRespFetcher = fetcher6.(stored_requests.Fetcher)
+	floorsFetcher = fetcher7.(stored_requests.FloorsFetcher)
 
 	shutdown = func() {
 		shutdown1()
@@ -127,6 +140,7 @@ func NewStoredRequests(cfg *config.Configuration, metricsEngine metrics.MetricsE
 		shutdown4()
 		shutdown5()
 		shutdown6()
+		shutdown7()
 	}
 
 	return
@@ -148,12 +162,20 @@ func addListeners(cache stored_requests.Cache, eventProducers []events.EventProd
 	}
 }
 
-func newFetcher(cfg *config.StoredRequests, client *http.Client, provider db_provider.DbProvider) (fetcher stored_requests.AllFetcher) {
+// newFetcher returns the fetcher of the stored data, along with the event producers of the fetchers which
+// reload their data, so that the cache in front of them can be updated, and a function stopping the reloads
+// if there are any.
+func newFetcher(cfg *config.StoredRequests, client *http.Client, provider db_provider.DbProvider) (fetcher stored_requests.AllFetcher, eventProducers []events.EventProducer, shutdown func()) {
 	idList := make(stored_requests.MultiFetcher, 0, 3)
 
 	if cfg.Files.Enabled {
-		fFetcher := newFilesystem(cfg.DataType(), cfg.Files.Path)
+		var fFetcher stored_requests.AllFetcher
+		var fEventProducer events.EventProducer
+		fFetcher, fEventProducer, shutdown = newFilesystem(cfg.DataType(), cfg.Files, cfg.InMemoryCache.Type != "")
 		idList = append(idList, fFetcher)
+		if fEventProducer != nil {
+			eventProducers = append(eventProducers, fEventProducer)
+		}
 	}
 	if cfg.Database.FetcherQueries.QueryTemplate != "" {
 		logger.Infof("Loading Stored %s data via Database.\nQuery: %s", cfg.DataType(), cfg.Database.FetcherQueries.QueryTemplate)
@@ -178,21 +200,34 @@ func newCache(cfg *config.StoredRequests) stored_requests.Cache {
 		Imps:      &nil_cache.NilCache{},
 		Responses: &nil_cache.NilCache{},
 		Accounts:  &nil_cache.NilCache{},
+		Floors:    &nil_cache.NilCache{},
 	}
 	switch {
 	case cfg.InMemoryCache.Type == "none":
 		logger.Warnf("No %s cache configured. The %s Fetcher backend will be used for all data requests", cfg.DataType(), cfg.DataType())
 	case cfg.DataType() == config.AccountDataType:
-		cache.Accounts = memory.NewCache(cfg.InMemoryCache.Size, cfg.InMemoryCache.TTL, "Accounts")
+		cache.Accounts = newMemoryCache(cfg.InMemoryCache, cfg.InMemoryCache.Size, "Accounts")
+	case cfg.DataType() == config.FloorsDataType:
+		cache.Floors = newMemoryCache(cfg.InMemoryCache, cfg.InMemoryCache.Size, "Floors")
 	default:
-		cache.Requests = memory.NewCache(cfg.InMemoryCache.RequestCacheSize, cfg.InMemoryCache.TTL, "Requests")
-		cache.Imps = memory.NewCache(cfg.InMemoryCache.ImpCacheSize, cfg.InMemoryCache.TTL, "Imps")
-		cache.Responses = memory.NewCache(cfg.InMemoryCache.RespCacheSize, cfg.InMemoryCache.TTL, "Responses")
+		cache.Requests = newMemoryCache(cfg.InMemoryCache, cfg.InMemoryCache.RequestCacheSize, "Requests")
+		cache.Imps = newMemoryCache(cfg.InMemoryCache, cfg.InMemoryCache.ImpCacheSize, "Imps")
+		cache.Responses = newMemoryCache(cfg.InMemoryCache, cfg.InMemoryCache.RespCacheSize, "Responses")
 	}
 	return cache
 }
 
-func newEventProducers(cfg *config.StoredRequests, client *http.Client, provider db_provider.DbProvider, metricsEngine metrics.MetricsEngine, router *httprouter.Router) (eventProducers []events.EventProducer) {
+// newMemoryCache returns a revalidating cache if the stale values or the IDs not found are to be cached.
+func newMemoryCache(cfg config.InMemoryCache, size int, dataType string) stored_requests.CacheJSON {
+	if cfg.StaleTTL > 0 || cfg.NotFoundTTL > 0 {
+		return memory.NewRevalidatingCache(size, cfg.TTL, cfg.StaleTTL, cfg.NotFoundTTL, dataType)
+	}
+	return memory.NewCache(size, cfg.TTL, dataType)
+}
+
+// newEventProducers returns the event producers updating the cache, along with a function closing the
+// connection of the database listener if there is one.
+func newEventProducers(cfg *config.StoredRequests, client *http.Client, provider db_provider.DbProvider, metricsEngine metrics.MetricsEngine, router *httprouter.Router) (eventProducers []events.EventProducer, shutdown func()) {
 	if cfg.CacheEvents.Enabled {
 		eventProducers = append(eventProducers, newEventsAPI(router, cfg.CacheEvents.Endpoint))
 	}
@@ -215,6 +250,19 @@ func newEventProducers(cfg *config.StoredRequests, client *http.Client, provider
 		dbEventTickerTask.Start()
 		eventProducers = append(eventProducers, dbEventProducer)
 	}
+	if cfg.Database.ListenUpdates.Channel != "" {
+		notifyEventCfg := databaseEvents.NotifyEventProducerConfig{
+			Provider:      provider,
+			RequestType:   cfg.DataType(),
+			Channel:       cfg.Database.ListenUpdates.Channel,
+			Query:         cfg.Database.ListenUpdates.Query,
+			QueryTimeout:  time.Duration(cfg.Database.ListenUpdates.Timeout) * time.Millisecond,
+			MetricsEngine: metricsEngine,
+		}
+		notifyEventProducer := databaseEvents.NewNotifyEventProducer(notifyEventCfg)
+		eventProducers = append(eventProducers, notifyEventProducer)
+		shutdown = notifyEventProducer.Stop
+	}
 	return
 }
 
@@ -232,13 +280,23 @@ func newHttpEvents(client *http.Client, timeout time.Duration, refreshRate time.
 	return httpEvents.NewHTTPEvents(client, endpoint, ctxProducer, refreshRate)
 }
 
-func newFilesystem(dataType config.DataType, configPath string) stored_requests.AllFetcher {
-	logger.Infof("Loading Stored %s data from filesystem at path %s", dataType, configPath)
-	fetcher, err := file_fetcher.NewFileFetcher(configPath)
+// newFilesystem returns the file fetcher, along with its event producer if it reloads the files and there's
+// a cache listening to the events, and the function stopping the reloads if it reloads the files.
+func newFilesystem(dataType config.DataType, cfg config.FileFetcherConfig, hasCache bool) (stored_requests.AllFetcher, events.EventProducer, func()) {
+	logger.Infof("Loading Stored %s data from filesystem at path %s", dataType, cfg.Path)
+	if cfg.RefreshRate > 0 {
+		fetcher, eventProducer, stop, err := file_fetcher.NewReloadingFileFetcher(cfg.Path, cfg.RefreshRateDuration(), hasCache)
+		if err != nil {
+			logger.Fatalf("Failed to create a %s FileFetcher: %v", dataType, err)
+		}
+		return fetcher, eventProducer, stop
+	}
+
+	fetcher, err := file_fetcher.NewFileFetcher(cfg.Path)
 	if err != nil {
 		logger.Fatalf("Failed to create a %s FileFetcher: %v", dataType, err)
 	}
-	return fetcher
+	return fetcher, nil, nil
 }
 
 // consolidate returns a single Fetcher from an array of fetchers of any size.
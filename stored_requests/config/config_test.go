@@ -117,7 +117,7 @@ func TestNewEmptyFetcher(t *testing.T) {
 	}
 
 	for _, test := range testCases {
-		fetcher := newFetcher(test.config, nil, db_provider.DbProviderMock{})
+		fetcher, _, _ := newFetcher(test.config, nil, db_provider.DbProviderMock{})
 		assert.NotNil(t, fetcher, "The fetcher should be non-nil.")
 		if test.emptyFetcher {
 			assert.Equal(t, empty_fetcher.EmptyFetcher{}, fetcher, "Empty fetcher should be returned")
@@ -128,7 +128,7 @@ func TestNewEmptyFetcher(t *testing.T) {
 }
 
 func TestNewHTTPFetcher(t *testing.T) {
-	fetcher := newFetcher(&config.StoredRequests{
+	fetcher, _, _ := newFetcher(&config.StoredRequests{
 		HTTP: config.HTTPFetcherConfig{
 			Endpoint: "stored-requests.prebid.com",
 		},
@@ -142,6 +142,47 @@ func TestNewHTTPFetcher(t *testing.T) {
 	}
 }
 
+func TestNewFileFetcherEventProducers(t *testing.T) {
+	testCases := []struct {
+		description            string
+		givenFiles             config.FileFetcherConfig
+		givenCache             string
+		expectedEventProducers int
+	}{
+		{
+			description:            "Files not reloaded",
+			givenFiles:             config.FileFetcherConfig{Enabled: true, Path: "../backends/file_fetcher/test"},
+			givenCache:             "lru",
+			expectedEventProducers: 0,
+		},
+		{
+			description:            "Files reloaded without cache",
+			givenFiles:             config.FileFetcherConfig{Enabled: true, Path: "../backends/file_fetcher/test", RefreshRate: 100},
+			expectedEventProducers: 0,
+		},
+		{
+			description:            "Files reloaded with cache",
+			givenFiles:             config.FileFetcherConfig{Enabled: true, Path: "../backends/file_fetcher/test", RefreshRate: 100},
+			givenCache:             "lru",
+			expectedEventProducers: 1,
+		},
+	}
+
+	for _, test := range testCases {
+		cfg := &config.StoredRequests{
+			Files:         test.givenFiles,
+			InMemoryCache: config.InMemoryCache{Type: test.givenCache},
+		}
+		fetcher, eventProducers, shutdown := newFetcher(cfg, nil, nil)
+		assert.NotNil(t, fetcher, test.description)
+		assert.Len(t, eventProducers, test.expectedEventProducers, test.description)
+		assert.Equal(t, test.givenFiles.RefreshRate > 0, shutdown != nil, test.description+": the reloads must be stoppable")
+		if shutdown != nil {
+			shutdown()
+		}
+	}
+}
+
 func TestNewHTTPEvents(t *testing.T) {
 	handler := func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusInternalServerError)
@@ -158,7 +199,7 @@ func TestNewHTTPEvents(t *testing.T) {
 
 	metricsMock := &metrics.MetricsEngineMock{}
 
-	evProducers := newEventProducers(cfg, server1.Client(), nil, metricsMock, nil)
+	evProducers, _ := newEventProducers(cfg, server1.Client(), nil, metricsMock, nil)
 	assertSliceLength(t, evProducers, 1)
 	assertHttpWithURL(t, evProducers[0], server1.URL)
 }
@@ -224,7 +265,7 @@ func TestNewDatabaseEventProducers(t *testing.T) {
 	}
 	mock.ExpectQuery("^" + regexp.QuoteMeta(cfg.Database.CacheInitialization.Query) + "$").WillReturnError(errors.New("Query failed"))
 
-	evProducers := newEventProducers(cfg, client, provider, metricsMock, nil)
+	evProducers, _ := newEventProducers(cfg, client, provider, metricsMock, nil)
 	assertProducerLength(t, evProducers, 1)
 
 	assertExpectationsMet(t, mock)
@@ -20,6 +20,7 @@ func TestGoodRequests(t *testing.T) {
 		Imps:      memory.NewCache(256*1024, -1, "Imp"),
 		Responses: memory.NewCache(256*1024, -1, "Responses"),
 		Accounts:  memory.NewCache(256*1024, -1, "Account"),
+		Floors:    memory.NewCache(256*1024, -1, "Floors"),
 	}
 	id := "1"
 	config := fmt.Sprintf(`{"id": "%s"}`, id)
@@ -0,0 +1,252 @@
+package database
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/prebid/prebid-server/v4/config"
+	"github.com/prebid/prebid-server/v4/logger"
+	"github.com/prebid/prebid-server/v4/metrics"
+	"github.com/prebid/prebid-server/v4/stored_requests/backends/db_provider"
+	"github.com/prebid/prebid-server/v4/stored_requests/events"
+	"github.com/prebid/prebid-server/v4/util/jsonutil"
+)
+
+const (
+	notifyMinReconnectInterval = 10 * time.Second
+	notifyMaxReconnectInterval = time.Minute
+)
+
+type NotifyEventProducerConfig struct {
+	Provider      db_provider.DbProvider
+	RequestType   config.DataType
+	Channel       string
+	Query         string
+	QueryTimeout  time.Duration
+	MetricsEngine metrics.MetricsEngine
+}
+
+// notificationListener is implemented by pq.Listener.
+type notificationListener interface {
+	Listen(channel string) error
+	NotificationChannel() <-chan *pq.Notification
+	Close() error
+}
+
+// notification is the payload of the NOTIFY sent by the database when some stored data changes.
+type notification struct {
+	Type string   `json:"type"`
+	IDs  []string `json:"ids"`
+}
+
+// NotifyEventProducer produces events as soon as the Postgres database notifies that some stored data changed.
+// The notified ids are queried, and turned into saves if they're found or invalidations if they aren't.
+//
+// Notifications sent while the listener is reconnecting are lost, so this is meant to be used along with the
+// DatabaseEventProducer polling for updates.
+type NotifyEventProducer struct {
+	cfg           NotifyEventProducerConfig
+	listener      notificationListener
+	invalidations chan events.Invalidation
+	saves         chan events.Save
+}
+
+func NewNotifyEventProducer(cfg NotifyEventProducerConfig) *NotifyEventProducer {
+	if cfg.Provider == nil {
+		logger.Fatalf("The Database Stored %s Listener needs a database connection to work.", cfg.RequestType)
+	}
+
+	connStr, err := cfg.Provider.ConnString()
+	if err != nil {
+		logger.Fatalf("Failed to build the Database Stored %s Listener connection string: %v", cfg.RequestType, err)
+	}
+
+	listener := pq.NewListener(connStr, notifyMinReconnectInterval, notifyMaxReconnectInterval, func(event pq.ListenerEventType, err error) {
+		switch event {
+		case pq.ListenerEventDisconnected:
+			logger.Warnf("The Database Stored %s Listener disconnected: %v", cfg.RequestType, err)
+		case pq.ListenerEventReconnected:
+			logger.Infof("The Database Stored %s Listener reconnected", cfg.RequestType)
+		case pq.ListenerEventConnectionAttemptFailed:
+			logger.Warnf("The Database Stored %s Listener failed to connect: %v", cfg.RequestType, err)
+		}
+	})
+
+	producer, err := newNotifyEventProducer(cfg, listener)
+	if err != nil {
+		logger.Fatalf("The Database Stored %s Listener failed to listen to channel %s: %v", cfg.RequestType, cfg.Channel, err)
+	}
+	return producer
+}
+
+func newNotifyEventProducer(cfg NotifyEventProducerConfig, listener notificationListener) (*NotifyEventProducer, error) {
+	if err := listener.Listen(cfg.Channel); err != nil {
+		listener.Close()
+		return nil, err
+	}
+
+	producer := &NotifyEventProducer{
+		cfg:           cfg,
+		listener:      listener,
+		saves:         make(chan events.Save, 1),
+		invalidations: make(chan events.Invalidation, 1),
+	}
+	go producer.listen()
+	return producer, nil
+}
+
+func (e *NotifyEventProducer) Saves() <-chan events.Save {
+	return e.saves
+}
+
+func (e *NotifyEventProducer) Invalidations() <-chan events.Invalidation {
+	return e.invalidations
+}
+
+// Stop closes the connection listening to the notifications, which ends the production of events.
+func (e *NotifyEventProducer) Stop() {
+	if err := e.listener.Close(); err != nil {
+		logger.Errorf("Error closing the Database Stored %s Listener: %v", e.cfg.RequestType, err)
+	}
+}
+
+func (e *NotifyEventProducer) listen() {
+	for n := range e.listener.NotificationChannel() {
+		// a nil notification is sent once reconnected, the polling catches up with the missed notifications
+		if n == nil {
+			continue
+		}
+		if err := e.handle([]byte(n.Extra)); err != nil {
+			logger.Warnf("Failed to handle the Stored %s notification %q: %v", e.cfg.RequestType, n.Extra, err)
+		}
+	}
+}
+
+// handle queries the notified ids and sends the events. If it returns an error, then no events were sent.
+func (e *NotifyEventProducer) handle(payload []byte) (handleErr error) {
+	var n notification
+	if err := jsonutil.UnmarshalValid(payload, &n); err != nil {
+		return err
+	}
+	if n.Type != "request" && n.Type != "imp" && n.Type != "response" && n.Type != "account" && n.Type != "floors" {
+		return fmt.Errorf("invalid type: %s", n.Type)
+	}
+	if len(n.IDs) == 0 {
+		return nil
+	}
+
+	ids := make([]interface{}, len(n.IDs))
+	for i, id := range n.IDs {
+		ids[i] = id
+	}
+	params := []db_provider.QueryParam{
+		{Name: "ID_LIST", Value: ids},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), e.cfg.QueryTimeout)
+	defer cancel()
+
+	startTime := time.Now()
+	rows, err := e.cfg.Provider.QueryContext(ctx, e.cfg.Query, params...)
+	e.recordFetchTime(time.Since(startTime))
+	if err != nil {
+		var netErr net.Error
+		if errors.As(err, &netErr) {
+			e.recordError(metrics.StoredDataErrorNetwork)
+		} else {
+			e.recordError(metrics.StoredDataErrorUndefined)
+		}
+		return err
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			e.recordError(metrics.StoredDataErrorUndefined)
+			handleErr = err
+		}
+	}()
+
+	saved := make(map[string]json.RawMessage, len(n.IDs))
+	for rows.Next() {
+		var id string
+		var data []byte
+		var dataType string
+
+		// discard corrupted data so it is not saved in the cache
+		if err := rows.Scan(&id, &data, &dataType); err != nil {
+			e.recordError(metrics.StoredDataErrorUndefined)
+			return err
+		}
+		if dataType != n.Type || len(data) == 0 || bytes.Equal(data, bytesNull()) {
+			continue
+		}
+		saved[id] = data
+	}
+	if err := rows.Err(); err != nil {
+		e.recordError(metrics.StoredDataErrorUndefined)
+		return err
+	}
+
+	var invalidated []string
+	for _, id := range n.IDs {
+		if _, ok := saved[id]; !ok {
+			invalidated = append(invalidated, id)
+		}
+	}
+
+	if len(saved) > 0 {
+		var save events.Save
+		switch n.Type {
+		case "request":
+			save.Requests = saved
+		case "imp":
+			save.Imps = saved
+		case "response":
+			save.Responses = saved
+		case "account":
+			save.Accounts = saved
+		case "floors":
+			save.Floors = saved
+		}
+		e.saves <- save
+	}
+
+	if len(invalidated) > 0 {
+		var invalidation events.Invalidation
+		switch n.Type {
+		case "request":
+			invalidation.Requests = invalidated
+		case "imp":
+			invalidation.Imps = invalidated
+		case "response":
+			invalidation.Responses = invalidated
+		case "account":
+			invalidation.Accounts = invalidated
+		case "floors":
+			invalidation.Floors = invalidated
+		}
+		e.invalidations <- invalidation
+	}
+	return nil
+}
+
+func (e *NotifyEventProducer) recordFetchTime(elapsedTime time.Duration) {
+	e.cfg.MetricsEngine.RecordStoredDataFetchTime(
+		metrics.StoredDataLabels{
+			DataType:      storedDataTypeMetricMap[e.cfg.RequestType],
+			DataFetchType: metrics.FetchDelta,
+		}, elapsedTime)
+}
+
+func (e *NotifyEventProducer) recordError(errorType metrics.StoredDataError) {
+	e.cfg.MetricsEngine.RecordStoredDataError(
+		metrics.StoredDataLabels{
+			DataType: storedDataTypeMetricMap[e.cfg.RequestType],
+			Error:    errorType,
+		})
+}
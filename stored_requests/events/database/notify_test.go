@@ -0,0 +1,227 @@
+package database
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/prebid/prebid-server/v4/config"
+	"github.com/prebid/prebid-server/v4/metrics"
+	"github.com/prebid/prebid-server/v4/stored_requests/backends/db_provider"
+	"github.com/prebid/prebid-server/v4/stored_requests/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeListener struct {
+	listenErr     error
+	channel       string
+	notifications chan *pq.Notification
+	closed        bool
+}
+
+func (l *fakeListener) Listen(channel string) error {
+	l.channel = channel
+	return l.listenErr
+}
+
+func (l *fakeListener) NotificationChannel() <-chan *pq.Notification {
+	return l.notifications
+}
+
+func (l *fakeListener) Close() error {
+	l.closed = true
+	return nil
+}
+
+func newTestNotifyEventProducer(t *testing.T, requestType config.DataType) (*NotifyEventProducer, sqlmock.Sqlmock, *fakeListener, *metrics.MetricsEngineMock) {
+	provider, dbMock, err := db_provider.NewDbProviderMock()
+	require.NoError(t, err)
+
+	metricsMock := &metrics.MetricsEngineMock{}
+	metricsMock.Mock.On("RecordStoredDataFetchTime", mock.Anything, mock.Anything).Return()
+	metricsMock.Mock.On("RecordStoredDataError", mock.Anything).Return()
+
+	listener := &fakeListener{notifications: make(chan *pq.Notification)}
+	producer, err := newNotifyEventProducer(NotifyEventProducerConfig{
+		Provider:      provider,
+		RequestType:   requestType,
+		Channel:       "stored_data",
+		Query:         fakeQuery,
+		QueryTimeout:  100 * time.Millisecond,
+		MetricsEngine: metricsMock,
+	}, listener)
+	require.NoError(t, err)
+	t.Cleanup(func() { close(listener.notifications) })
+
+	return producer, dbMock, listener, metricsMock
+}
+
+func TestNotifyEventProducerEvents(t *testing.T) {
+	tests := []struct {
+		description      string
+		givePayload      string
+		giveRequestType  config.DataType
+		giveMockRows     *sqlmock.Rows
+		wantSave         *events.Save
+		wantInvalidation *events.Invalidation
+	}{
+		{
+			description:     "requests saved and invalidated",
+			givePayload:     `{"type":"request","ids":["req-1","req-2","req-3"]}`,
+			giveRequestType: config.RequestDataType,
+			giveMockRows: sqlmock.NewRows([]string{"id", "data", "dataType"}).
+				AddRow("req-1", "true", "request").
+				AddRow("req-2", "null", "request").
+				AddRow("req-3", "true", "imp"),
+			wantSave:         &events.Save{Requests: map[string]json.RawMessage{"req-1": json.RawMessage(`true`)}},
+			wantInvalidation: &events.Invalidation{Requests: []string{"req-2", "req-3"}},
+		},
+		{
+			description:     "amp requests saved",
+			givePayload:     `{"type":"request","ids":["amp-1"]}`,
+			giveRequestType: config.AMPRequestDataType,
+			giveMockRows:    sqlmock.NewRows([]string{"id", "data", "dataType"}).AddRow("amp-1", "true", "request"),
+			wantSave:        &events.Save{Requests: map[string]json.RawMessage{"amp-1": json.RawMessage(`true`)}},
+		},
+		{
+			description:     "imps saved",
+			givePayload:     `{"type":"imp","ids":["imp-1"]}`,
+			giveRequestType: config.RequestDataType,
+			giveMockRows:    sqlmock.NewRows([]string{"id", "data", "dataType"}).AddRow("imp-1", "true", "imp"),
+			wantSave:        &events.Save{Imps: map[string]json.RawMessage{"imp-1": json.RawMessage(`true`)}},
+		},
+		{
+			description:      "responses invalidated",
+			givePayload:      `{"type":"response","ids":["resp-1"]}`,
+			giveRequestType:  config.ResponseDataType,
+			giveMockRows:     sqlmock.NewRows([]string{"id", "data", "dataType"}),
+			wantInvalidation: &events.Invalidation{Responses: []string{"resp-1"}},
+		},
+		{
+			description:     "accounts saved",
+			givePayload:     `{"type":"account","ids":["acc-1"]}`,
+			giveRequestType: config.AccountDataType,
+			giveMockRows:    sqlmock.NewRows([]string{"id", "data", "dataType"}).AddRow("acc-1", `{"id":"acc-1"}`, "account"),
+			wantSave:        &events.Save{Accounts: map[string]json.RawMessage{"acc-1": json.RawMessage(`{"id":"acc-1"}`)}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			producer, dbMock, listener, _ := newTestNotifyEventProducer(t, tt.giveRequestType)
+			dbMock.ExpectQuery(fakeQueryRegex()).WillReturnRows(tt.giveMockRows)
+
+			listener.notifications <- nil
+			listener.notifications <- &pq.Notification{Channel: "stored_data", Extra: tt.givePayload}
+
+			if tt.wantSave != nil {
+				select {
+				case save := <-producer.Saves():
+					assert.Equal(t, *tt.wantSave, save)
+				case <-time.After(time.Second):
+					t.Fatal("save expected")
+				}
+			}
+			if tt.wantInvalidation != nil {
+				select {
+				case invalidation := <-producer.Invalidations():
+					assert.Equal(t, *tt.wantInvalidation, invalidation)
+				case <-time.After(time.Second):
+					t.Fatal("invalidation expected")
+				}
+			}
+			assert.Equal(t, "stored_data", listener.channel)
+		})
+	}
+}
+
+func TestNotifyEventProducerErrors(t *testing.T) {
+	tests := []struct {
+		description string
+		givePayload string
+		giveQuery   bool
+		giveRows    *sqlmock.Rows
+		giveErr     error
+		wantErr     bool
+		wantMetric  bool
+	}{
+		{
+			description: "malformed payload",
+			givePayload: `{"type":`,
+			wantErr:     true,
+		},
+		{
+			description: "invalid type",
+			givePayload: `{"type":"video","ids":["1"]}`,
+			wantErr:     true,
+		},
+		{
+			description: "no ids",
+			givePayload: `{"type":"request","ids":[]}`,
+		},
+		{
+			description: "query error",
+			givePayload: `{"type":"request","ids":["1"]}`,
+			giveQuery:   true,
+			giveErr:     errors.New("query failed"),
+			wantErr:     true,
+			wantMetric:  true,
+		},
+		{
+			description: "row error",
+			givePayload: `{"type":"request","ids":["1"]}`,
+			giveQuery:   true,
+			giveRows:    sqlmock.NewRows([]string{"id", "data", "dataType"}).AddRow("1", "true", "request").RowError(0, errors.New("row failed")),
+			wantErr:     true,
+			wantMetric:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			producer, dbMock, _, metricsMock := newTestNotifyEventProducer(t, config.RequestDataType)
+			if tt.giveQuery {
+				expectedQuery := dbMock.ExpectQuery(fakeQueryRegex())
+				if tt.giveErr != nil {
+					expectedQuery.WillReturnError(tt.giveErr)
+				} else {
+					expectedQuery.WillReturnRows(tt.giveRows)
+				}
+			}
+
+			err := producer.handle([]byte(tt.givePayload))
+
+			assert.Equal(t, tt.wantErr, err != nil)
+			assert.Empty(t, producer.saves)
+			assert.Empty(t, producer.invalidations)
+			if tt.wantMetric {
+				metricsMock.AssertCalled(t, "RecordStoredDataError", metrics.StoredDataLabels{DataType: metrics.RequestDataType, Error: metrics.StoredDataErrorUndefined})
+			} else {
+				metricsMock.AssertNotCalled(t, "RecordStoredDataError", mock.Anything)
+			}
+			assert.NoError(t, dbMock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestNewNotifyEventProducerListenError(t *testing.T) {
+	listener := &fakeListener{listenErr: errors.New("listen failed")}
+
+	_, err := newNotifyEventProducer(NotifyEventProducerConfig{Channel: "stored_data"}, listener)
+
+	assert.Error(t, err)
+	assert.True(t, listener.closed)
+}
+
+func TestNotifyEventProducerStop(t *testing.T) {
+	producer, _, listener, _ := newTestNotifyEventProducer(t, config.RequestDataType)
+
+	producer.Stop()
+
+	assert.True(t, listener.closed)
+}
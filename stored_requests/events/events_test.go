@@ -21,6 +21,7 @@ func TestListen(t *testing.T) {
 		Imps:      memory.NewCache(256*1024, -1, "Imps"),
 		Responses: memory.NewCache(256*1024, -1, "Responses"),
 		Accounts:  memory.NewCache(256*1024, -1, "Account"),
+		Floors:    memory.NewCache(256*1024, -1, "Floors"),
 	}
 
 	// create channels to synchronize
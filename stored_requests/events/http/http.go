@@ -58,6 +58,14 @@ import (
 //	  },
 //	}
 //
+// or
+//
+//	{
+//	  "floors": {
+//	    "floors1": { ... floor rule set ... },
+//	  },
+//	}
+//
 // To signal deletions, the endpoint may return { "deleted": true }
 // in place of the Stored Data if the "last-modified" param existed.
 func NewHTTPEvents(client *httpCore.Client, endpoint string, ctxProducer func() (ctx context.Context, canceller func()), refreshRate time.Duration) *HTTPEvents {
@@ -97,12 +105,13 @@ func (e *HTTPEvents) fetchAll() {
 
 	resp, err := ctxhttp.Get(ctx, e.client, e.Endpoint)
 	if respObj, ok := e.parse(e.Endpoint, resp, err); ok &&
-		(len(respObj.StoredRequests) > 0 || len(respObj.StoredImps) > 0 || len(respObj.StoredResponses) > 0 || len(respObj.Accounts) > 0) {
+		(len(respObj.StoredRequests) > 0 || len(respObj.StoredImps) > 0 || len(respObj.StoredResponses) > 0 || len(respObj.Accounts) > 0 || len(respObj.Floors) > 0) {
 		e.saves <- events.Save{
 			Requests:  respObj.StoredRequests,
 			Imps:      respObj.StoredImps,
 			Responses: respObj.StoredResponses,
 			Accounts:  respObj.Accounts,
+			Floors:    respObj.Floors,
 		}
 	}
 }
@@ -142,16 +151,18 @@ func (e *HTTPEvents) refresh(ticker <-chan time.Time) {
 				Imps:      extractInvalidations(respObj.StoredImps),
 				Responses: extractInvalidations(respObj.StoredResponses),
 				Accounts:  extractInvalidations(respObj.Accounts),
+				Floors:    extractInvalidations(respObj.Floors),
 			}
-			if len(respObj.StoredRequests) > 0 || len(respObj.StoredImps) > 0 || len(respObj.StoredResponses) > 0 || len(respObj.Accounts) > 0 {
+			if len(respObj.StoredRequests) > 0 || len(respObj.StoredImps) > 0 || len(respObj.StoredResponses) > 0 || len(respObj.Accounts) > 0 || len(respObj.Floors) > 0 {
 				e.saves <- events.Save{
 					Requests:  respObj.StoredRequests,
 					Imps:      respObj.StoredImps,
 					Responses: respObj.StoredResponses,
 					Accounts:  respObj.Accounts,
+					Floors:    respObj.Floors,
 				}
 			}
-			if len(invalidations.Requests) > 0 || len(invalidations.Imps) > 0 || len(invalidations.Responses) > 0 || len(invalidations.Accounts) > 0 {
+			if len(invalidations.Requests) > 0 || len(invalidations.Imps) > 0 || len(invalidations.Responses) > 0 || len(invalidations.Accounts) > 0 || len(invalidations.Floors) > 0 {
 				e.invalidations <- invalidations
 			}
 			e.lastUpdate = thisTimeInUTC
@@ -213,4 +224,5 @@ type responseContract struct {
 	StoredImps      map[string]json.RawMessage `json:"imps"`
 	StoredResponses map[string]json.RawMessage `json:"responses"`
 	Accounts        map[string]json.RawMessage `json:"accounts"`
+	Floors          map[string]json.RawMessage `json:"floors"`
 }
@@ -3,9 +3,17 @@ package stored_requests
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/prebid/prebid-server/v4/logger"
 	"github.com/prebid/prebid-server/v4/metrics"
+	"github.com/prebid/prebid-server/v4/tracing"
+	"golang.org/x/sync/singleflight"
 )
 
 // Fetcher knows how to fetch Stored Request data by id.
@@ -31,6 +39,14 @@ type AccountFetcher interface {
 	FetchAccount(ctx context.Context, accountDefaultJSON json.RawMessage, accountID string) (json.RawMessage, []error)
 }
 
+// FloorsFetcher knows how to fetch the price floor rule sets by id.
+type FloorsFetcher interface {
+	// FetchFloors fetches the floor rule sets for the given IDs.
+	//
+	// The returned map will have a key for every ID which was found. The returned objects can only be read from.
+	FetchFloors(ctx context.Context, ids []string) (data map[string]json.RawMessage, errs []error)
+}
+
 type CategoryFetcher interface {
 	// FetchCategories fetches the ad-server/publisher specific category for the given IAB category
 	FetchCategories(ctx context.Context, primaryAdServer, publisherId, iabCategory string) (string, error)
@@ -68,6 +84,7 @@ type Cache struct {
 	Imps      CacheJSON
 	Responses CacheJSON
 	Accounts  CacheJSON
+	Floors    CacheJSON
 }
 type CacheJSON interface {
 	// Get works much like Fetcher.FetchRequests, with a few exceptions:
@@ -90,6 +107,21 @@ type CacheJSON interface {
 	Save(ctx context.Context, data map[string]json.RawMessage)
 }
 
+// RevalidatingCacheJSON is a CacheJSON which keeps the values for a while past their TTL, and remembers the IDs
+// which don't exist. If a Cache implements it, the Fetcher returned by WithCache serves the stale values while
+// refreshing them in the background, and doesn't fetch the IDs known not to exist.
+type RevalidatingCacheJSON interface {
+	CacheJSON
+
+	// Lookup works like Get, except that the values past their TTL are returned in stale rather than
+	// in fresh, and the IDs saved with SaveNotFound are returned in notFound.
+	Lookup(ctx context.Context, ids []string) (fresh map[string]json.RawMessage, stale map[string]json.RawMessage, notFound []string)
+
+	// SaveNotFound will remember that the given IDs don't exist, until their data is saved via Save,
+	// or they're invalidated, or a short TTL expires. The IDs which have data in the cache are left as they are.
+	SaveNotFound(ctx context.Context, ids []string)
+}
+
 // ComposedCache creates an interface to treat a slice of caches as a single cache
 type ComposedCache []CacheJSON
 
@@ -149,87 +181,337 @@ type fetcherWithCache struct {
 	fetcher       AllFetcher
 	cache         Cache
 	metricsEngine metrics.MetricsEngine
+	// fetches coalesces the concurrent fetches and revalidations of the same IDs
+	fetches singleflight.Group
+	// fetchTimeout bounds the fetches shared by concurrent requests and the background refreshes of the stale
+	// values, which aren't tied to the deadline of the request which triggered them.
+	fetchTimeout time.Duration
 }
 
 // WithCache returns a Fetcher which uses the given Caches before delegating to the original.
 // This can be called multiple times to compose Cache layers onto the backing Fetcher, though
 // it is usually more desirable to first compose caches with Compose, ensuring propagation of updates
 // and invalidations through all cache layers.
-func WithCache(fetcher AllFetcher, cache Cache, metricsEngine metrics.MetricsEngine) AllFetcher {
+//
+// Concurrent misses on the same IDs are fetched once. If a Cache is a RevalidatingCacheJSON, its stale
+// values are served while they're refreshed in the background, and the IDs it knows not to exist are
+// returned as NotFoundErrors without being fetched. Those fetches are bounded by the fetchTimeout, or the
+// deadline of the request which started them if it's later.
+func WithCache(fetcher AllFetcher, cache Cache, metricsEngine metrics.MetricsEngine, fetchTimeout time.Duration) AllFetcher {
 	return &fetcherWithCache{
 		cache:         cache,
 		fetcher:       fetcher,
 		metricsEngine: metricsEngine,
+		fetchTimeout:  fetchTimeout,
 	}
 }
 
 func (f *fetcherWithCache) FetchRequests(ctx context.Context, requestIDs []string, impIDs []string) (requestData map[string]json.RawMessage, impData map[string]json.RawMessage, errs []error) {
+	_, span := tracing.Start(ctx, "stored_requests.fetch")
+	defer func() {
+		tracing.RecordErrors(span, errs...)
+		span.End()
+	}()
 
-	requestData = f.cache.Requests.Get(ctx, requestIDs)
-	impData = f.cache.Imps.Get(ctx, impIDs)
+	requests := lookupCache(ctx, f.cache.Requests, requestIDs)
+	imps := lookupCache(ctx, f.cache.Imps, impIDs)
+	requestData = requests.data
+	impData = imps.data
 
 	// Fixes #311
-	leftoverImps := findLeftovers(impIDs, impData)
-	leftoverReqs := findLeftovers(requestIDs, requestData)
+	leftoverImps := imps.misses
+	leftoverReqs := requests.misses
 
 	// Record cache hits for stored requests and stored imps
-	f.metricsEngine.RecordStoredReqCacheResult(metrics.CacheHit, len(requestIDs)-len(leftoverReqs))
-	f.metricsEngine.RecordStoredImpCacheResult(metrics.CacheHit, len(impIDs)-len(leftoverImps))
+	f.metricsEngine.RecordStoredReqCacheResult(metrics.CacheHit, len(requestIDs)-len(leftoverReqs)-len(requests.stale)-len(requests.notFound))
+	f.metricsEngine.RecordStoredImpCacheResult(metrics.CacheHit, len(impIDs)-len(leftoverImps)-len(imps.stale)-len(imps.notFound))
 	// Record cache misses for stored requests and stored imps
 	f.metricsEngine.RecordStoredReqCacheResult(metrics.CacheMiss, len(leftoverReqs))
 	f.metricsEngine.RecordStoredImpCacheResult(metrics.CacheMiss, len(leftoverImps))
+	recordStaleAndNegativeHits(f.metricsEngine.RecordStoredReqCacheResult, requests)
+	recordStaleAndNegativeHits(f.metricsEngine.RecordStoredImpCacheResult, imps)
+
+	errs = appendNotFoundErrors("Request", requests.notFound, nil, errs)
+	errs = appendNotFoundErrors("Imp", imps.notFound, nil, errs)
+
+	if len(requests.stale) > 0 || len(imps.stale) > 0 {
+		f.revalidate(ctx, "requests", [][]string{requests.stale, imps.stale}, func(ctx context.Context) {
+			fetcherReqData, fetcherImpData, fetcherErrs := f.fetcher.FetchRequests(ctx, requests.stale, imps.stale)
+			f.saveRevalidated(ctx, f.cache.Requests, requests.stale, fetcherReqData, notFoundIDs(fetcherErrs, "Request"))
+			f.saveRevalidated(ctx, f.cache.Imps, imps.stale, fetcherImpData, notFoundIDs(fetcherErrs, "Imp"))
+		})
+	}
 
 	if len(leftoverReqs) > 0 || len(leftoverImps) > 0 {
-		fetcherReqData, fetcherImpData, fetcherErrs := f.fetcher.FetchRequests(ctx, leftoverReqs, leftoverImps)
-		errs = fetcherErrs
+		fetched := f.fetch(ctx, "requests", [][]string{leftoverReqs, leftoverImps}, func(ctx context.Context) fetchResult {
+			fetcherReqData, fetcherImpData, fetcherErrs := f.fetcher.FetchRequests(ctx, leftoverReqs, leftoverImps)
+
+			f.cache.Requests.Save(ctx, fetcherReqData)
+			f.cache.Imps.Save(ctx, fetcherImpData)
+			saveNotFound(ctx, f.cache.Requests, notFoundIDs(fetcherErrs, "Request"))
+			saveNotFound(ctx, f.cache.Imps, notFoundIDs(fetcherErrs, "Imp"))
 
-		f.cache.Requests.Save(ctx, fetcherReqData)
-		f.cache.Imps.Save(ctx, fetcherImpData)
+			return fetchResult{data: fetcherReqData, impData: fetcherImpData, errs: fetcherErrs}
+		})
+		errs = append(errs, fetched.errs...)
 
-		requestData = mergeData(requestData, fetcherReqData)
-		impData = mergeData(impData, fetcherImpData)
+		requestData = mergeData(requestData, fetched.data)
+		impData = mergeData(impData, fetched.impData)
 	}
 
 	return
 }
 
 func (f *fetcherWithCache) FetchResponses(ctx context.Context, ids []string) (data map[string]json.RawMessage, errs []error) {
-	data = f.cache.Responses.Get(ctx, ids)
+	responses := lookupCache(ctx, f.cache.Responses, ids)
+	data = responses.data
 
-	leftoverResp := findLeftovers(ids, data)
+	leftoverResp := responses.misses
+
+	errs = appendNotFoundErrors("Response", responses.notFound, nil, errs)
+
+	if len(responses.stale) > 0 {
+		f.revalidate(ctx, "responses", [][]string{responses.stale}, func(ctx context.Context) {
+			fetcherRespData, fetcherErrs := f.fetcher.FetchResponses(ctx, responses.stale)
+			f.saveRevalidated(ctx, f.cache.Responses, responses.stale, fetcherRespData, notFoundIDs(fetcherErrs, "Response"))
+		})
+	}
 
 	if len(leftoverResp) > 0 {
-		fetcherRespData, fetcherErrs := f.fetcher.FetchResponses(ctx, leftoverResp)
-		errs = fetcherErrs
+		fetched := f.fetch(ctx, "responses", [][]string{leftoverResp}, func(ctx context.Context) fetchResult {
+			fetcherRespData, fetcherErrs := f.fetcher.FetchResponses(ctx, leftoverResp)
 
-		f.cache.Responses.Save(ctx, fetcherRespData)
+			f.cache.Responses.Save(ctx, fetcherRespData)
+			saveNotFound(ctx, f.cache.Responses, notFoundIDs(fetcherErrs, "Response"))
 
-		data = mergeData(data, fetcherRespData)
+			return fetchResult{data: fetcherRespData, errs: fetcherErrs}
+		})
+		errs = append(errs, fetched.errs...)
+
+		data = mergeData(data, fetched.data)
 	}
 
 	return
 }
 
 func (f *fetcherWithCache) FetchAccount(ctx context.Context, acccountDefaultJSON json.RawMessage, accountID string) (account json.RawMessage, errs []error) {
-	accountData := f.cache.Accounts.Get(ctx, []string{accountID})
+	accounts := lookupCache(ctx, f.cache.Accounts, []string{accountID})
 	// TODO: add metrics
-	if account, ok := accountData[accountID]; ok {
-		f.metricsEngine.RecordAccountCacheResult(metrics.CacheHit, 1)
+	if account, ok := accounts.data[accountID]; ok {
+		if len(accounts.stale) > 0 {
+			f.metricsEngine.RecordAccountCacheResult(metrics.CacheStaleHit, 1)
+			f.revalidate(ctx, "account", [][]string{accounts.stale}, func(ctx context.Context) {
+				account, errs := f.fetcher.FetchAccount(ctx, acccountDefaultJSON, accountID)
+				var data map[string]json.RawMessage
+				if len(errs) == 0 {
+					data = map[string]json.RawMessage{accountID: account}
+				}
+				f.saveRevalidated(ctx, f.cache.Accounts, accounts.stale, data, notFoundIDs(errs, "Account"))
+			})
+		} else {
+			f.metricsEngine.RecordAccountCacheResult(metrics.CacheHit, 1)
+		}
 		return account, errs
+	} else if len(accounts.notFound) > 0 {
+		f.metricsEngine.RecordAccountCacheResult(metrics.CacheNegativeHit, 1)
+		return nil, appendNotFoundErrors("Account", accounts.notFound, nil, errs)
 	} else {
 		f.metricsEngine.RecordAccountCacheResult(metrics.CacheMiss, 1)
 	}
-	account, errs = f.fetcher.FetchAccount(ctx, acccountDefaultJSON, accountID)
-	if len(errs) == 0 {
-		f.cache.Accounts.Save(ctx, map[string]json.RawMessage{accountID: account})
+
+	fetched := f.fetch(ctx, "account", [][]string{{accountID}}, func(ctx context.Context) fetchResult {
+		account, errs := f.fetcher.FetchAccount(ctx, acccountDefaultJSON, accountID)
+		if len(errs) == 0 {
+			f.cache.Accounts.Save(ctx, map[string]json.RawMessage{accountID: account})
+		}
+		saveNotFound(ctx, f.cache.Accounts, notFoundIDs(errs, "Account"))
+
+		return fetchResult{data: map[string]json.RawMessage{accountID: account}, errs: errs}
+	})
+	return fetched.data[accountID], fetched.errs
+}
+
+// FetchFloors fetches the floor rule sets through the cache. The floors are reported as not found if the
+// underlying fetcher can't fetch floors.
+func (f *fetcherWithCache) FetchFloors(ctx context.Context, ids []string) (data map[string]json.RawMessage, errs []error) {
+	floorsFetcher, ok := f.fetcher.(FloorsFetcher)
+	if !ok {
+		return nil, appendNotFoundErrors("Floors", ids, nil, nil)
+	}
+
+	floors := lookupCache(ctx, f.cache.Floors, ids)
+	data = floors.data
+
+	errs = appendNotFoundErrors("Floors", floors.notFound, nil, errs)
+
+	if len(floors.stale) > 0 {
+		f.revalidate(ctx, "floors", [][]string{floors.stale}, func(ctx context.Context) {
+			fetcherFloorsData, fetcherErrs := floorsFetcher.FetchFloors(ctx, floors.stale)
+			f.saveRevalidated(ctx, f.cache.Floors, floors.stale, fetcherFloorsData, notFoundIDs(fetcherErrs, "Floors"))
+		})
+	}
+
+	if len(floors.misses) > 0 {
+		fetched := f.fetch(ctx, "floors", [][]string{floors.misses}, func(ctx context.Context) fetchResult {
+			fetcherFloorsData, fetcherErrs := floorsFetcher.FetchFloors(ctx, floors.misses)
+
+			f.cache.Floors.Save(ctx, fetcherFloorsData)
+			saveNotFound(ctx, f.cache.Floors, notFoundIDs(fetcherErrs, "Floors"))
+
+			return fetchResult{data: fetcherFloorsData, errs: fetcherErrs}
+		})
+		errs = append(errs, fetched.errs...)
+
+		data = mergeData(data, fetched.data)
 	}
-	return account, errs
+
+	return
 }
 
 func (f *fetcherWithCache) FetchCategories(ctx context.Context, primaryAdServer, publisherId, iabCategory string) (string, error) {
 	return "", nil
 }
 
+// fetchResult is the result of a fetch, shared by the callers which asked for the same IDs concurrently.
+// Its maps can only be read from.
+type fetchResult struct {
+	data    map[string]json.RawMessage
+	impData map[string]json.RawMessage
+	errs    []error
+}
+
+// fetch calls fetchFunc, unless a fetch of the same kind and IDs is in flight. In that case, it waits for
+// that fetch and returns its result. The fetch is shared by the callers, so it isn't cancelled with the
+// caller which started it; each caller stops waiting when its own context is done.
+func (f *fetcherWithCache) fetch(ctx context.Context, kind string, ids [][]string, fetchFunc func(ctx context.Context) fetchResult) fetchResult {
+	fetchCtx := context.WithoutCancel(ctx)
+	fetchTimeout := f.fetchTimeout
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) > fetchTimeout {
+		fetchTimeout = time.Until(deadline)
+	}
+
+	results := f.fetches.DoChan(fetchKey("fetch", kind, ids), func() (interface{}, error) {
+		ctx, cancel := context.WithTimeout(fetchCtx, fetchTimeout)
+		defer cancel()
+
+		return fetchFunc(ctx), nil
+	})
+
+	select {
+	case result := <-results:
+		return result.Val.(fetchResult)
+	case <-ctx.Done():
+		return fetchResult{errs: []error{ctx.Err()}}
+	}
+}
+
+// revalidate calls revalidateFunc in the background, unless a revalidation of the same kind and IDs is
+// in flight already.
+func (f *fetcherWithCache) revalidate(ctx context.Context, kind string, ids [][]string, revalidateFunc func(ctx context.Context)) {
+	// the values are refreshed after the request is done, so its cancellation and deadline don't apply
+	revalidateCtx := context.WithoutCancel(ctx)
+
+	f.fetches.DoChan(fetchKey("revalidate", kind, ids), func() (interface{}, error) {
+		ctx, cancel := context.WithTimeout(revalidateCtx, f.fetchTimeout)
+		defer cancel()
+
+		revalidateFunc(ctx)
+		return nil, nil
+	})
+}
+
+// saveRevalidated saves the refreshed values of the stale IDs. The IDs which were found not to exist are
+// invalidated, and the others keep their stale value until the next revalidation or their eviction.
+func (f *fetcherWithCache) saveRevalidated(ctx context.Context, cache CacheJSON, staleIDs []string, data map[string]json.RawMessage, notFound []string) {
+	cache.Save(ctx, data)
+	if len(notFound) > 0 {
+		cache.Invalidate(ctx, notFound)
+		saveNotFound(ctx, cache, notFound)
+	}
+	if refreshed := len(data) + len(notFound); refreshed < len(staleIDs) {
+		logger.Warnf("Failed to refresh %d stale IDs out of %d, their stale value is kept", len(staleIDs)-refreshed, len(staleIDs))
+	}
+}
+
+// fetchKey identifies the fetches of the same IDs. The lists of IDs are encoded so that their IDs can't be
+// confused with the IDs of another list.
+func fetchKey(operation string, kind string, ids [][]string) string {
+	var key strings.Builder
+	key.WriteString(operation)
+	key.WriteString(":")
+	key.WriteString(kind)
+	for _, list := range ids {
+		key.WriteString(":")
+		key.WriteString(strconv.Itoa(len(list)))
+		for _, id := range list {
+			key.WriteString(":")
+			key.WriteString(strconv.Itoa(len(id)))
+			key.WriteString(":")
+			key.WriteString(id)
+		}
+	}
+	return key.String()
+}
+
+// cacheLookup is the result of looking up IDs in a Cache.
+type cacheLookup struct {
+	// data holds both the fresh and the stale values
+	data     map[string]json.RawMessage
+	stale    []string
+	notFound []string
+	// misses holds the IDs which need to be fetched
+	misses []string
+}
+
+func lookupCache(ctx context.Context, cache CacheJSON, ids []string) cacheLookup {
+	revalidatingCache, ok := cache.(RevalidatingCacheJSON)
+	if !ok {
+		data := cache.Get(ctx, ids)
+		return cacheLookup{data: data, misses: findLeftovers(ids, data)}
+	}
+
+	fresh, stale, notFound := revalidatingCache.Lookup(ctx, ids)
+	lookup := cacheLookup{
+		data:     mergeData(fresh, stale),
+		notFound: notFound,
+		misses:   make([]string, 0, len(ids)),
+	}
+	for _, id := range ids {
+		if _, ok := stale[id]; ok {
+			lookup.stale = append(lookup.stale, id)
+		} else if _, ok := lookup.data[id]; !ok && !slices.Contains(notFound, id) {
+			lookup.misses = append(lookup.misses, id)
+		}
+	}
+	return lookup
+}
+
+func recordStaleAndNegativeHits(record func(metrics.CacheResult, int), lookup cacheLookup) {
+	if len(lookup.stale) > 0 {
+		record(metrics.CacheStaleHit, len(lookup.stale))
+	}
+	if len(lookup.notFound) > 0 {
+		record(metrics.CacheNegativeHit, len(lookup.notFound))
+	}
+}
+
+func saveNotFound(ctx context.Context, cache CacheJSON, ids []string) {
+	if revalidatingCache, ok := cache.(RevalidatingCacheJSON); ok && len(ids) > 0 {
+		revalidatingCache.SaveNotFound(ctx, ids)
+	}
+}
+
+// notFoundIDs returns the IDs of the NotFoundErrors of the given data type.
+func notFoundIDs(errs []error, dataType string) (ids []string) {
+	for _, err := range errs {
+		var notFoundErr NotFoundError
+		if errors.As(err, &notFoundErr) && notFoundErr.DataType == dataType {
+			ids = append(ids, notFoundErr.ID)
+		}
+	}
+	return
+}
+
 func findLeftovers(ids []string, data map[string]json.RawMessage) (leftovers []string) {
 	leftovers = make([]string, 0, len(ids)-len(data))
 	for _, id := range ids {
@@ -4,7 +4,10 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"slices"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/prebid/prebid-server/v4/metrics"
 	"github.com/prebid/prebid-server/v4/stored_requests/caches/nil_cache"
@@ -13,13 +16,15 @@ import (
 	"github.com/stretchr/testify/mock"
 )
 
+const testFetchTimeout = time.Second
+
 func setupFetcherWithCacheDeps() (*mockCache, *mockCache, *mockCache, *mockFetcher, AllFetcher, *metrics.MetricsEngineMock) {
 	reqCache := &mockCache{}
 	impCache := &mockCache{}
 	respCache := &mockCache{}
 	metricsEngine := &metrics.MetricsEngineMock{}
 	fetcher := &mockFetcher{}
-	afetcherWithCache := WithCache(fetcher, Cache{reqCache, impCache, respCache, &nil_cache.NilCache{}}, metricsEngine)
+	afetcherWithCache := WithCache(fetcher, Cache{reqCache, impCache, respCache, &nil_cache.NilCache{}, &nil_cache.NilCache{}}, metricsEngine, testFetchTimeout)
 
 	return reqCache, impCache, respCache, fetcher, afetcherWithCache, metricsEngine
 }
@@ -80,30 +85,30 @@ func TestImperfectCache(t *testing.T) {
 			"cached": json.RawMessage(`true`),
 		})
 
-	fetcher.On("FetchRequests", ctx, []string{}, []string{"uncached"}).Return(
+	fetcher.On("FetchRequests", mock.Anything, []string{}, []string{"uncached"}).Return(
 		map[string]json.RawMessage{},
 		map[string]json.RawMessage{
 			"uncached": json.RawMessage(`false`),
 		},
 		[]error{},
 	)
-	impCache.On("Save", ctx,
+	impCache.On("Save", mock.Anything,
 		map[string]json.RawMessage{
 			"uncached": json.RawMessage(`false`),
 		})
 
-	fetcher.On("FetchResponses", ctx, []string{"uncached"}).Return(
+	fetcher.On("FetchResponses", mock.Anything, []string{"uncached"}).Return(
 		map[string]json.RawMessage{
 			"uncached": json.RawMessage(`false`),
 		},
 		[]error{},
 	)
-	respCache.On("Save", ctx,
+	respCache.On("Save", mock.Anything,
 		map[string]json.RawMessage{
 			"uncached": json.RawMessage(`false`),
 		})
 
-	reqCache.On("Save", ctx, map[string]json.RawMessage{})
+	reqCache.On("Save", mock.Anything, map[string]json.RawMessage{})
 
 	metricsEngine.On("RecordStoredReqCacheResult", metrics.CacheHit, 0)
 	metricsEngine.On("RecordStoredReqCacheResult", metrics.CacheMiss, 0)
@@ -141,7 +146,7 @@ func TestMissingData(t *testing.T) {
 	respCache.On("Get", ctx, []string(respIDs)).Return(
 		map[string]json.RawMessage{})
 
-	fetcher.On("FetchRequests", ctx, []string{}, impIDs).Return(
+	fetcher.On("FetchRequests", mock.Anything, []string{}, impIDs).Return(
 		map[string]json.RawMessage{},
 		map[string]json.RawMessage{},
 		[]error{
@@ -149,21 +154,21 @@ func TestMissingData(t *testing.T) {
 		},
 	)
 
-	fetcher.On("FetchResponses", ctx, respIDs).Return(
+	fetcher.On("FetchResponses", mock.Anything, respIDs).Return(
 		map[string]json.RawMessage{},
 		[]error{
 			errors.New("Data not found"),
 		},
 	)
 
-	impCache.On("Save", ctx,
+	impCache.On("Save", mock.Anything,
 		map[string]json.RawMessage{},
 	)
-	reqCache.On("Save", ctx,
+	reqCache.On("Save", mock.Anything,
 		map[string]json.RawMessage{},
 	)
 
-	respCache.On("Save", ctx,
+	respCache.On("Save", mock.Anything,
 		map[string]json.RawMessage{},
 	)
 	metricsEngine.On("RecordStoredReqCacheResult", metrics.CacheHit, 0)
@@ -226,7 +231,7 @@ func setupAccountFetcherWithCacheDeps() (*mockCache, *mockFetcher, AllFetcher, *
 	accCache := &mockCache{}
 	metricsEngine := &metrics.MetricsEngineMock{}
 	fetcher := &mockFetcher{}
-	afetcherWithCache := WithCache(fetcher, Cache{&nil_cache.NilCache{}, &nil_cache.NilCache{}, &nil_cache.NilCache{}, accCache}, metricsEngine)
+	afetcherWithCache := WithCache(fetcher, Cache{&nil_cache.NilCache{}, &nil_cache.NilCache{}, &nil_cache.NilCache{}, accCache, &nil_cache.NilCache{}}, metricsEngine, testFetchTimeout)
 
 	return accCache, fetcher, afetcherWithCache, metricsEngine
 }
@@ -262,8 +267,8 @@ func TestAccountCacheMiss(t *testing.T) {
 
 	// Test read from cache
 	accCache.On("Get", ctx, uncachedAccounts).Return(map[string]json.RawMessage{})
-	accCache.On("Save", ctx, uncachedAccountsData)
-	fetcher.On("FetchAccount", ctx, json.RawMessage("{}"), "uncached").Return(uncachedAccountsData["uncached"], []error{})
+	accCache.On("Save", mock.Anything, uncachedAccountsData)
+	fetcher.On("FetchAccount", mock.Anything, json.RawMessage("{}"), "uncached").Return(uncachedAccountsData["uncached"], []error{})
 	metricsEngine.On("RecordAccountCacheResult", metrics.CacheMiss, 1)
 
 	account, errs := aFetcherWithCache.FetchAccount(ctx, json.RawMessage("{}"), "uncached")
@@ -287,7 +292,7 @@ func TestComposedCache(t *testing.T) {
 	}
 	metricsEngine := &metrics.MetricsEngineMock{}
 	fetcher := &mockFetcher{}
-	aFetcherWithCache := WithCache(fetcher, cache, metricsEngine)
+	aFetcherWithCache := WithCache(fetcher, cache, metricsEngine, testFetchTimeout)
 	reqIDs := []string{"1", "2", "3"}
 	impIDs := []string{}
 	ctx := context.Background()
@@ -333,6 +338,287 @@ func TestComposedCache(t *testing.T) {
 	assert.JSONEq(t, `{"id": "3"}`, string(respData["3"]), "FetchResponses should fetch the right resp data")
 }
 
+func TestStaleCacheRevalidated(t *testing.T) {
+	reqCache := newFakeRevalidatingCache()
+	reqCache.stale["req"] = json.RawMessage(`{"stale":true}`)
+	impCache := newFakeRevalidatingCache()
+	metricsEngine := &metrics.MetricsEngineMock{}
+	fetcher := &mockFetcher{}
+	aFetcherWithCache := WithCache(fetcher, Cache{reqCache, impCache, &nil_cache.NilCache{}, &nil_cache.NilCache{}, &nil_cache.NilCache{}}, metricsEngine, testFetchTimeout)
+	ctx := context.Background()
+
+	fetcher.On("FetchRequests", mock.Anything, []string{"req"}, []string(nil)).Return(
+		map[string]json.RawMessage{"req": json.RawMessage(`{"stale":false}`)},
+		map[string]json.RawMessage{},
+		[]error{},
+	)
+	metricsEngine.On("RecordStoredReqCacheResult", metrics.CacheHit, 0)
+	metricsEngine.On("RecordStoredReqCacheResult", metrics.CacheMiss, 0)
+	metricsEngine.On("RecordStoredReqCacheResult", metrics.CacheStaleHit, 1)
+	metricsEngine.On("RecordStoredImpCacheResult", metrics.CacheHit, 0)
+	metricsEngine.On("RecordStoredImpCacheResult", metrics.CacheMiss, 0)
+
+	reqData, _, errs := aFetcherWithCache.FetchRequests(ctx, []string{"req"}, nil)
+
+	assert.Empty(t, errs, "FetchRequests shouldn't return an error for stale data")
+	assert.JSONEq(t, `{"stale":true}`, string(reqData["req"]), "FetchRequests should return the stale data")
+
+	select {
+	case saved := <-reqCache.saves:
+		assert.JSONEq(t, `{"stale":false}`, string(saved["req"]), "The stale data should be refreshed in the background")
+	case <-time.After(time.Second):
+		t.Fatal("The stale data wasn't refreshed")
+	}
+	fetcher.AssertExpectations(t)
+	metricsEngine.AssertExpectations(t)
+}
+
+func TestStaleCacheRevalidatedNotFound(t *testing.T) {
+	respCache := newFakeRevalidatingCache()
+	respCache.stale["resp"] = json.RawMessage(`{}`)
+	fetcher := &mockFetcher{}
+	aFetcherWithCache := WithCache(fetcher, Cache{&nil_cache.NilCache{}, &nil_cache.NilCache{}, respCache, &nil_cache.NilCache{}, &nil_cache.NilCache{}}, &metrics.MetricsEngineMock{}, testFetchTimeout)
+
+	fetcher.On("FetchResponses", mock.Anything, []string{"resp"}).Return(
+		map[string]json.RawMessage{},
+		[]error{NotFoundError{"resp", "Response"}},
+	)
+
+	respData, errs := aFetcherWithCache.FetchResponses(context.Background(), []string{"resp"})
+
+	assert.Empty(t, errs, "FetchResponses shouldn't return an error for stale data")
+	assert.Len(t, respData, 1, "FetchResponses should return the stale data")
+	select {
+	case notFound := <-respCache.notFoundSaves:
+		assert.Equal(t, []string{"resp"}, notFound, "The stale data which doesn't exist anymore should be remembered as not found")
+	case <-time.After(time.Second):
+		t.Fatal("The stale data wasn't refreshed")
+	}
+	assert.Equal(t, []string{"resp"}, respCache.invalidations, "The stale data which doesn't exist anymore should be invalidated")
+}
+
+func TestNegativeCacheHit(t *testing.T) {
+	reqCache := newFakeRevalidatingCache()
+	reqCache.notFound = []string{"missing"}
+	impCache := newFakeRevalidatingCache()
+	impCache.fresh["imp"] = json.RawMessage(`{}`)
+	metricsEngine := &metrics.MetricsEngineMock{}
+	fetcher := &mockFetcher{}
+	aFetcherWithCache := WithCache(fetcher, Cache{reqCache, impCache, &nil_cache.NilCache{}, &nil_cache.NilCache{}, &nil_cache.NilCache{}}, metricsEngine, testFetchTimeout)
+
+	metricsEngine.On("RecordStoredReqCacheResult", metrics.CacheHit, 0)
+	metricsEngine.On("RecordStoredReqCacheResult", metrics.CacheMiss, 0)
+	metricsEngine.On("RecordStoredReqCacheResult", metrics.CacheNegativeHit, 1)
+	metricsEngine.On("RecordStoredImpCacheResult", metrics.CacheHit, 1)
+	metricsEngine.On("RecordStoredImpCacheResult", metrics.CacheMiss, 0)
+
+	reqData, impData, errs := aFetcherWithCache.FetchRequests(context.Background(), []string{"missing"}, []string{"imp"})
+
+	fetcher.AssertExpectations(t)
+	metricsEngine.AssertExpectations(t)
+	assert.Equal(t, []error{NotFoundError{"missing", "Request"}}, errs, "FetchRequests should return the IDs known not to exist without fetching them")
+	assert.Empty(t, reqData)
+	assert.Len(t, impData, 1)
+}
+
+func TestNotFoundSaved(t *testing.T) {
+	reqCache := newFakeRevalidatingCache()
+	impCache := newFakeRevalidatingCache()
+	metricsEngine := &metrics.MetricsEngineMock{}
+	fetcher := &mockFetcher{}
+	aFetcherWithCache := WithCache(fetcher, Cache{reqCache, impCache, &nil_cache.NilCache{}, &nil_cache.NilCache{}, &nil_cache.NilCache{}}, metricsEngine, testFetchTimeout)
+	ctx := context.Background()
+
+	fetcher.On("FetchRequests", mock.Anything, []string{"req", "missing"}, []string{"imp"}).Return(
+		map[string]json.RawMessage{"req": json.RawMessage(`{}`)},
+		map[string]json.RawMessage{},
+		[]error{NotFoundError{"missing", "Request"}, NotFoundError{"imp", "Imp"}},
+	)
+	metricsEngine.On("RecordStoredReqCacheResult", metrics.CacheHit, 0)
+	metricsEngine.On("RecordStoredReqCacheResult", metrics.CacheMiss, 2)
+	metricsEngine.On("RecordStoredImpCacheResult", metrics.CacheHit, 0)
+	metricsEngine.On("RecordStoredImpCacheResult", metrics.CacheMiss, 1)
+
+	reqData, _, errs := aFetcherWithCache.FetchRequests(ctx, []string{"req", "missing"}, []string{"imp"})
+
+	fetcher.AssertExpectations(t)
+	metricsEngine.AssertExpectations(t)
+	assert.Len(t, errs, 2)
+	assert.Len(t, reqData, 1)
+	assert.Equal(t, []string{"missing"}, <-reqCache.notFoundSaves, "The requests not found should be remembered")
+	assert.Equal(t, []string{"imp"}, <-impCache.notFoundSaves, "The imps not found should be remembered")
+}
+
+func TestConcurrentMissesCoalesced(t *testing.T) {
+	fetcher := &blockingFetcher{release: make(chan struct{})}
+	metricsEngine := &metrics.MetricsEngineMock{}
+	metricsEngine.On("RecordStoredReqCacheResult", mock.Anything, mock.Anything)
+	metricsEngine.On("RecordStoredImpCacheResult", mock.Anything, mock.Anything)
+	aFetcherWithCache := WithCache(fetcher, Cache{&nil_cache.NilCache{}, &nil_cache.NilCache{}, &nil_cache.NilCache{}, &nil_cache.NilCache{}, &nil_cache.NilCache{}}, metricsEngine, testFetchTimeout)
+
+	results := make(chan map[string]json.RawMessage, 2)
+	for i := 0; i < 2; i++ {
+		go func() {
+			reqData, _, _ := aFetcherWithCache.FetchRequests(context.Background(), []string{"req"}, nil)
+			results <- reqData
+		}()
+	}
+	// give the second call the time to wait for the first fetch
+	time.Sleep(50 * time.Millisecond)
+	close(fetcher.release)
+
+	assert.JSONEq(t, `{}`, string((<-results)["req"]))
+	assert.JSONEq(t, `{}`, string((<-results)["req"]))
+	assert.Equal(t, int32(1), fetcher.calls.Load(), "Concurrent misses on the same IDs should be fetched once")
+}
+
+func TestCoalescedFetchOutlivesCancelledCaller(t *testing.T) {
+	fetcher := &blockingFetcher{release: make(chan struct{})}
+	metricsEngine := &metrics.MetricsEngineMock{}
+	metricsEngine.On("RecordStoredReqCacheResult", mock.Anything, mock.Anything)
+	metricsEngine.On("RecordStoredImpCacheResult", mock.Anything, mock.Anything)
+	aFetcherWithCache := WithCache(fetcher, Cache{&nil_cache.NilCache{}, &nil_cache.NilCache{}, &nil_cache.NilCache{}, &nil_cache.NilCache{}, &nil_cache.NilCache{}}, metricsEngine, testFetchTimeout)
+
+	firstCtx, cancelFirst := context.WithCancel(context.Background())
+	firstErrs := make(chan []error, 1)
+	go func() {
+		_, _, errs := aFetcherWithCache.FetchRequests(firstCtx, []string{"req"}, nil)
+		firstErrs <- errs
+	}()
+	// give the first call the time to start the fetch
+	time.Sleep(50 * time.Millisecond)
+
+	results := make(chan map[string]json.RawMessage, 1)
+	go func() {
+		reqData, _, _ := aFetcherWithCache.FetchRequests(context.Background(), []string{"req"}, nil)
+		results <- reqData
+	}()
+	shortCtx, cancelShort := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancelShort()
+	_, _, shortErrs := aFetcherWithCache.FetchRequests(shortCtx, []string{"req"}, nil)
+	assert.Equal(t, []error{context.DeadlineExceeded}, shortErrs, "A waiting call should stop at its own deadline")
+
+	cancelFirst()
+	assert.Equal(t, []error{context.Canceled}, <-firstErrs, "The cancelled call should return without waiting for the fetch")
+
+	close(fetcher.release)
+	assert.JSONEq(t, `{}`, string((<-results)["req"]), "The fetch shouldn't be cancelled with the call which started it")
+	assert.Equal(t, int32(1), fetcher.calls.Load())
+}
+
+func TestCoalescedFetchTimeout(t *testing.T) {
+	fetcher := &blockingFetcher{release: make(chan struct{})}
+	metricsEngine := &metrics.MetricsEngineMock{}
+	metricsEngine.On("RecordStoredReqCacheResult", mock.Anything, mock.Anything)
+	metricsEngine.On("RecordStoredImpCacheResult", mock.Anything, mock.Anything)
+	aFetcherWithCache := WithCache(fetcher, Cache{&nil_cache.NilCache{}, &nil_cache.NilCache{}, &nil_cache.NilCache{}, &nil_cache.NilCache{}, &nil_cache.NilCache{}}, metricsEngine, 20*time.Millisecond)
+
+	_, _, errs := aFetcherWithCache.FetchRequests(context.Background(), []string{"req"}, nil)
+	assert.Contains(t, errs, context.DeadlineExceeded, "The fetch should stop at the fetch timeout")
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	time.AfterFunc(50*time.Millisecond, func() { close(fetcher.release) })
+	reqData, _, errs := aFetcherWithCache.FetchRequests(ctx, []string{"req"}, nil)
+	assert.Empty(t, errs)
+	assert.JSONEq(t, `{}`, string(reqData["req"]), "The fetch should last until the later deadline of the call which started it")
+}
+
+func TestAccountCacheStaleHit(t *testing.T) {
+	accCache := newFakeRevalidatingCache()
+	accCache.stale["stale"] = json.RawMessage(`{"stale":true}`)
+	metricsEngine := &metrics.MetricsEngineMock{}
+	fetcher := &mockFetcher{}
+	aFetcherWithCache := WithCache(fetcher, Cache{&nil_cache.NilCache{}, &nil_cache.NilCache{}, &nil_cache.NilCache{}, accCache, &nil_cache.NilCache{}}, metricsEngine, testFetchTimeout)
+
+	fetcher.On("FetchAccount", mock.Anything, json.RawMessage("{}"), "stale").Return(json.RawMessage(`{"stale":false}`), []error{})
+	metricsEngine.On("RecordAccountCacheResult", metrics.CacheStaleHit, 1)
+
+	account, errs := aFetcherWithCache.FetchAccount(context.Background(), json.RawMessage("{}"), "stale")
+
+	assert.Empty(t, errs)
+	assert.JSONEq(t, `{"stale":true}`, string(account), "FetchAccount should return the stale account")
+	select {
+	case saved := <-accCache.saves:
+		assert.JSONEq(t, `{"stale":false}`, string(saved["stale"]), "The stale account should be refreshed in the background")
+	case <-time.After(time.Second):
+		t.Fatal("The stale account wasn't refreshed")
+	}
+	fetcher.AssertExpectations(t)
+	metricsEngine.AssertExpectations(t)
+}
+
+func TestAccountCacheNegativeHit(t *testing.T) {
+	accCache := newFakeRevalidatingCache()
+	accCache.notFound = []string{"missing"}
+	metricsEngine := &metrics.MetricsEngineMock{}
+	fetcher := &mockFetcher{}
+	aFetcherWithCache := WithCache(fetcher, Cache{&nil_cache.NilCache{}, &nil_cache.NilCache{}, &nil_cache.NilCache{}, accCache, &nil_cache.NilCache{}}, metricsEngine, testFetchTimeout)
+
+	metricsEngine.On("RecordAccountCacheResult", metrics.CacheNegativeHit, 1)
+
+	account, errs := aFetcherWithCache.FetchAccount(context.Background(), json.RawMessage("{}"), "missing")
+
+	fetcher.AssertExpectations(t)
+	metricsEngine.AssertExpectations(t)
+	assert.Nil(t, account)
+	assert.Equal(t, []error{NotFoundError{"missing", "Account"}}, errs, "FetchAccount should return the accounts known not to exist without fetching them")
+}
+
+func TestAccountCacheMissNotFoundSaved(t *testing.T) {
+	accCache := newFakeRevalidatingCache()
+	metricsEngine := &metrics.MetricsEngineMock{}
+	fetcher := &mockFetcher{}
+	aFetcherWithCache := WithCache(fetcher, Cache{&nil_cache.NilCache{}, &nil_cache.NilCache{}, &nil_cache.NilCache{}, accCache, &nil_cache.NilCache{}}, metricsEngine, testFetchTimeout)
+	ctx := context.Background()
+
+	fetcher.On("FetchAccount", mock.Anything, json.RawMessage("{}"), "missing").Return(json.RawMessage(nil), []error{NotFoundError{"missing", "Account"}})
+	metricsEngine.On("RecordAccountCacheResult", metrics.CacheMiss, 1)
+
+	_, errs := aFetcherWithCache.FetchAccount(ctx, json.RawMessage("{}"), "missing")
+
+	fetcher.AssertExpectations(t)
+	metricsEngine.AssertExpectations(t)
+	assert.Equal(t, []error{NotFoundError{"missing", "Account"}}, errs)
+	assert.Equal(t, []string{"missing"}, <-accCache.notFoundSaves, "The accounts not found should be remembered")
+}
+
+func TestFloorsCache(t *testing.T) {
+	floorsCache := newFakeRevalidatingCache()
+	floorsCache.fresh["cached"] = json.RawMessage(`{"cached":true}`)
+	floorsCache.notFound = []string{"missing"}
+	fetcher := &mockFetcher{}
+	aFetcherWithCache := WithCache(fetcher, Cache{&nil_cache.NilCache{}, &nil_cache.NilCache{}, &nil_cache.NilCache{}, &nil_cache.NilCache{}, floorsCache}, &metrics.MetricsEngineMock{}, testFetchTimeout)
+	ctx := context.Background()
+
+	fetcher.On("FetchFloors", mock.Anything, []string{"uncached", "unknown"}).Return(
+		map[string]json.RawMessage{"uncached": json.RawMessage(`{"cached":false}`)},
+		[]error{NotFoundError{"unknown", "Floors"}},
+	)
+
+	floorsData, errs := aFetcherWithCache.(FloorsFetcher).FetchFloors(ctx, []string{"cached", "uncached", "missing", "unknown"})
+
+	fetcher.AssertExpectations(t)
+	assert.ElementsMatch(t, []error{NotFoundError{"missing", "Floors"}, NotFoundError{"unknown", "Floors"}}, errs)
+	assert.Equal(t, map[string]json.RawMessage{
+		"cached":   json.RawMessage(`{"cached":true}`),
+		"uncached": json.RawMessage(`{"cached":false}`),
+	}, floorsData)
+	assert.Equal(t, map[string]json.RawMessage{"uncached": json.RawMessage(`{"cached":false}`)}, <-floorsCache.saves, "The fetched floors should be saved")
+	assert.Equal(t, []string{"unknown"}, <-floorsCache.notFoundSaves, "The floors not found should be remembered")
+}
+
+func TestFloorsCacheWithoutFloorsFetcher(t *testing.T) {
+	// the embedded interface hides the FetchFloors method of the mock
+	fetcher := struct{ AllFetcher }{&mockFetcher{}}
+	aFetcherWithCache := WithCache(fetcher, Cache{&nil_cache.NilCache{}, &nil_cache.NilCache{}, &nil_cache.NilCache{}, &nil_cache.NilCache{}, &nil_cache.NilCache{}}, &metrics.MetricsEngineMock{}, testFetchTimeout)
+
+	floorsData, errs := aFetcherWithCache.(FloorsFetcher).FetchFloors(context.Background(), []string{"floors"})
+
+	assert.Nil(t, floorsData)
+	assert.Equal(t, []error{NotFoundError{"floors", "Floors"}}, errs)
+}
+
 type mockFetcher struct {
 	mock.Mock
 }
@@ -352,6 +638,11 @@ func (a *mockFetcher) FetchAccount(ctx context.Context, defaultAccountsJSON json
 	return args.Get(0).(json.RawMessage), args.Get(1).([]error)
 }
 
+func (f *mockFetcher) FetchFloors(ctx context.Context, ids []string) (data map[string]json.RawMessage, errs []error) {
+	args := f.Called(ctx, ids)
+	return args.Get(0).(map[string]json.RawMessage), args.Get(1).([]error)
+}
+
 func (f *mockFetcher) FetchCategories(ctx context.Context, primaryAdServer, publisherId, iabCategory string) (string, error) {
 	return "", nil
 }
@@ -372,3 +663,76 @@ func (c *mockCache) Save(ctx context.Context, data map[string]json.RawMessage) {
 func (c *mockCache) Invalidate(ctx context.Context, ids []string) {
 	c.Called(ctx, ids)
 }
+
+// fakeRevalidatingCache returns its fresh, stale and not found data, and sends the data it's given to save
+// through its channels.
+type fakeRevalidatingCache struct {
+	fresh         map[string]json.RawMessage
+	stale         map[string]json.RawMessage
+	notFound      []string
+	invalidations []string
+	saves         chan map[string]json.RawMessage
+	notFoundSaves chan []string
+}
+
+func newFakeRevalidatingCache() *fakeRevalidatingCache {
+	return &fakeRevalidatingCache{
+		fresh:         make(map[string]json.RawMessage),
+		stale:         make(map[string]json.RawMessage),
+		saves:         make(chan map[string]json.RawMessage, 1),
+		notFoundSaves: make(chan []string, 1),
+	}
+}
+
+func (c *fakeRevalidatingCache) Get(ctx context.Context, ids []string) map[string]json.RawMessage {
+	fresh, _, _ := c.Lookup(ctx, ids)
+	return fresh
+}
+
+func (c *fakeRevalidatingCache) Lookup(ctx context.Context, ids []string) (map[string]json.RawMessage, map[string]json.RawMessage, []string) {
+	fresh := make(map[string]json.RawMessage)
+	stale := make(map[string]json.RawMessage)
+	var notFound []string
+	for _, id := range ids {
+		if data, ok := c.fresh[id]; ok {
+			fresh[id] = data
+		} else if data, ok := c.stale[id]; ok {
+			stale[id] = data
+		} else if slices.Contains(c.notFound, id) {
+			notFound = append(notFound, id)
+		}
+	}
+	return fresh, stale, notFound
+}
+
+func (c *fakeRevalidatingCache) Save(ctx context.Context, data map[string]json.RawMessage) {
+	if len(data) > 0 {
+		c.saves <- data
+	}
+}
+
+func (c *fakeRevalidatingCache) SaveNotFound(ctx context.Context, ids []string) {
+	c.notFoundSaves <- ids
+}
+
+func (c *fakeRevalidatingCache) Invalidate(ctx context.Context, ids []string) {
+	c.invalidations = append(c.invalidations, ids...)
+}
+
+// blockingFetcher counts its calls, and waits to be released before returning an empty stored request.
+// It fails if its context is done first.
+type blockingFetcher struct {
+	mockFetcher
+	calls   atomic.Int32
+	release chan struct{}
+}
+
+func (f *blockingFetcher) FetchRequests(ctx context.Context, requestIDs []string, impIDs []string) (map[string]json.RawMessage, map[string]json.RawMessage, []error) {
+	f.calls.Add(1)
+	select {
+	case <-f.release:
+		return map[string]json.RawMessage{"req": json.RawMessage(`{}`)}, nil, nil
+	case <-ctx.Done():
+		return nil, nil, []error{ctx.Err()}
+	}
+}
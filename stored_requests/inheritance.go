@@ -0,0 +1,251 @@
+package stored_requests
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/buger/jsonparser"
+	"github.com/prebid/prebid-server/v4/util/jsonutil"
+	jsonpatch "gopkg.in/evanphx/json-patch.v5"
+)
+
+// extendsPath is the path of the parent ids of a Stored Request or Stored Imp, and chainPath the path where
+// the resolved chain of its ancestors is written.
+var (
+	extendsPath = []string{"ext", "prebid", "storedrequest", "extends"}
+	chainPath   = []string{"ext", "prebid", "storedrequest", "chain"}
+)
+
+var errExtendsInvalid = errors.New("ext.prebid.storedrequest.extends must be a string or an array of strings")
+
+type fetcherWithInheritance struct {
+	Fetcher
+}
+
+// WithInheritance returns a Fetcher which resolves the parents of the Stored Requests and Stored Imps.
+//
+// A Stored Request (or Stored Imp) may extend one or more Stored Requests (or Stored Imps) through the
+// ext.prebid.storedrequest.extends id, or array of ids. The parents are fetched from the given Fetcher and
+// resolved recursively, then merge-patched in order, and the stored data is merge-patched on top of them.
+// The ids of the ancestors, depth first in the order they're extended, are written to
+// ext.prebid.storedrequest.chain so that the resolved chain shows in the debug output.
+func WithInheritance(fetcher Fetcher) Fetcher {
+	return &fetcherWithInheritance{Fetcher: fetcher}
+}
+
+func (f *fetcherWithInheritance) FetchRequests(ctx context.Context, requestIDs []string, impIDs []string) (map[string]json.RawMessage, map[string]json.RawMessage, []error) {
+	// the ids which weren't found are reported as they are, the others are still resolved
+	requestData, impData, errs := f.Fetcher.FetchRequests(ctx, requestIDs, impIDs)
+
+	requests := newInheritanceResolver("Request", requestData)
+	imps := newInheritanceResolver("Imp", impData)
+
+	// fetch the ancestors level by level, until all of them are loaded
+	for {
+		pendingRequests, err := requests.pendingParents()
+		if err != nil {
+			return nil, nil, append(errs, err)
+		}
+		pendingImps, err := imps.pendingParents()
+		if err != nil {
+			return nil, nil, append(errs, err)
+		}
+		if len(pendingRequests) == 0 && len(pendingImps) == 0 {
+			break
+		}
+
+		parentRequestData, parentImpData, parentErrs := f.Fetcher.FetchRequests(ctx, pendingRequests, pendingImps)
+		if len(parentErrs) > 0 {
+			return nil, nil, append(errs, parentErrs...)
+		}
+		if err := requests.load(pendingRequests, parentRequestData); err != nil {
+			return nil, nil, append(errs, err)
+		}
+		if err := imps.load(pendingImps, parentImpData); err != nil {
+			return nil, nil, append(errs, err)
+		}
+	}
+
+	resolvedRequests, err := requests.resolveAll(requestIDs)
+	if err != nil {
+		return nil, nil, append(errs, err)
+	}
+	resolvedImps, err := imps.resolveAll(impIDs)
+	if err != nil {
+		return nil, nil, append(errs, err)
+	}
+	return resolvedRequests, resolvedImps, errs
+}
+
+// inheritanceResolver resolves the parents of the stored data of a single type.
+type inheritanceResolver struct {
+	dataType string
+	// loaded holds the stored data of the fetched ids, whose parents may not have been fetched yet
+	loaded map[string]json.RawMessage
+	// parents holds the parent ids of the loaded stored data, once read
+	parents  map[string][]string
+	resolved map[string]json.RawMessage
+	chains   map[string][]string
+}
+
+func newInheritanceResolver(dataType string, data map[string]json.RawMessage) *inheritanceResolver {
+	loaded := make(map[string]json.RawMessage, len(data))
+	for id, value := range data {
+		loaded[id] = value
+	}
+	return &inheritanceResolver{
+		dataType: dataType,
+		loaded:   loaded,
+		parents:  make(map[string][]string, len(data)),
+		resolved: make(map[string]json.RawMessage, len(data)),
+		chains:   make(map[string][]string, len(data)),
+	}
+}
+
+// pendingParents returns the parent ids of the loaded stored data which haven't been loaded yet.
+func (r *inheritanceResolver) pendingParents() ([]string, error) {
+	var pending []string
+	seen := make(map[string]struct{})
+	for id, data := range r.loaded {
+		parents, ok := r.parents[id]
+		if !ok {
+			var err error
+			if parents, err = readExtends(data); err != nil {
+				return nil, fmt.Errorf("Stored %s with ID=\"%s\": %v", r.dataType, id, err)
+			}
+			r.parents[id] = parents
+		}
+
+		for _, parent := range parents {
+			if _, ok := r.loaded[parent]; ok {
+				continue
+			}
+			if _, ok := seen[parent]; ok {
+				continue
+			}
+			seen[parent] = struct{}{}
+			pending = append(pending, parent)
+		}
+	}
+	return pending, nil
+}
+
+func (r *inheritanceResolver) load(ids []string, data map[string]json.RawMessage) error {
+	for _, id := range ids {
+		value, ok := data[id]
+		if !ok {
+			return NotFoundError{ID: id, DataType: r.dataType}
+		}
+		r.loaded[id] = value
+	}
+	return nil
+}
+
+func (r *inheritanceResolver) resolveAll(ids []string) (map[string]json.RawMessage, error) {
+	resolved := make(map[string]json.RawMessage, len(ids))
+	for _, id := range ids {
+		if _, ok := r.loaded[id]; !ok {
+			continue
+		}
+		data, _, err := r.resolve(id, nil)
+		if err != nil {
+			return nil, err
+		}
+		resolved[id] = data
+	}
+	return resolved, nil
+}
+
+// resolve returns the stored data of the id merged on top of its resolved parents, along with the chain of
+// its ancestors. The path holds the ids being resolved, to detect cycles.
+func (r *inheritanceResolver) resolve(id string, path []string) (json.RawMessage, []string, error) {
+	if data, ok := r.resolved[id]; ok {
+		return data, r.chains[id], nil
+	}
+
+	data := r.loaded[id]
+	parents := r.parents[id]
+	if len(parents) == 0 {
+		r.resolved[id] = data
+		return data, nil, nil
+	}
+
+	path = append(path, id)
+	var base json.RawMessage
+	var chain []string
+	for _, parent := range parents {
+		for _, ancestor := range path {
+			if ancestor == parent {
+				return nil, nil, fmt.Errorf("Stored %s with ID=\"%s\" extends itself: %s -> %s", r.dataType, parent, strings.Join(path, " -> "), parent)
+			}
+		}
+
+		parentData, parentChain, err := r.resolve(parent, path)
+		if err != nil {
+			return nil, nil, err
+		}
+		chain = append(chain, parent)
+		chain = append(chain, parentChain...)
+
+		if base == nil {
+			base = parentData
+		} else if base, err = jsonpatch.MergePatch(base, parentData); err != nil {
+			return nil, nil, fmt.Errorf("Stored %s with ID=\"%s\" failed to merge its parent %s: %v", r.dataType, id, parent, err)
+		}
+	}
+
+	// jsonparser.Delete modifies its input, which is owned by the fetcher
+	child := jsonparser.Delete(append([]byte(nil), data...), extendsPath...)
+	merged, err := jsonpatch.MergePatch(base, child)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Stored %s with ID=\"%s\" failed to merge its parents: %v", r.dataType, id, err)
+	}
+
+	chainJSON, err := jsonutil.Marshal(chain)
+	if err != nil {
+		return nil, nil, err
+	}
+	if merged, err = jsonparser.Set(merged, chainJSON, chainPath...); err != nil {
+		return nil, nil, err
+	}
+
+	r.resolved[id] = merged
+	r.chains[id] = chain
+	return merged, chain, nil
+}
+
+// readExtends returns the parent ids of the stored data, which may be a single id or an array of ids.
+func readExtends(data json.RawMessage) ([]string, error) {
+	value, dataType, _, err := jsonparser.Get(data, extendsPath...)
+	if dataType == jsonparser.NotExist {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	switch dataType {
+	case jsonparser.String:
+		id, err := jsonparser.ParseString(value)
+		if err != nil || id == "" {
+			return nil, errExtendsInvalid
+		}
+		return []string{id}, nil
+	case jsonparser.Array:
+		var ids []string
+		if err := jsonutil.UnmarshalValid(value, &ids); err != nil {
+			return nil, errExtendsInvalid
+		}
+		for _, id := range ids {
+			if id == "" {
+				return nil, errExtendsInvalid
+			}
+		}
+		return ids, nil
+	default:
+		return nil, errExtendsInvalid
+	}
+}
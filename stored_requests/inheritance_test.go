@@ -0,0 +1,222 @@
+package stored_requests
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithInheritance(t *testing.T) {
+	testCases := []struct {
+		name             string
+		givenRequests    map[string]json.RawMessage
+		givenImps        map[string]json.RawMessage
+		givenRequestIDs  []string
+		givenImpIDs      []string
+		expectedRequests map[string]string
+		expectedImps     map[string]string
+		expectedErrors   []string
+	}{
+		{
+			name: "no-parent",
+			givenRequests: map[string]json.RawMessage{
+				"req": json.RawMessage(`{"id":"req"}`),
+			},
+			givenRequestIDs:  []string{"req"},
+			expectedRequests: map[string]string{"req": `{"id":"req"}`},
+			expectedImps:     map[string]string{},
+		},
+		{
+			name: "single-parent",
+			givenRequests: map[string]json.RawMessage{
+				"req":    json.RawMessage(`{"tmax":500,"site":{"page":"child.com"},"ext":{"prebid":{"storedrequest":{"extends":"parent"}}}}`),
+				"parent": json.RawMessage(`{"tmax":1000,"site":{"page":"parent.com","domain":"parent.com"},"ext":{"prebid":{"debug":true}}}`),
+			},
+			givenRequestIDs:  []string{"req"},
+			expectedRequests: map[string]string{"req": `{"tmax":500,"site":{"page":"child.com","domain":"parent.com"},"ext":{"prebid":{"debug":true,"storedrequest":{"chain":["parent"]}}}}`},
+			expectedImps:     map[string]string{},
+		},
+		{
+			name: "multiple-parents-applied-in-order",
+			givenRequests: map[string]json.RawMessage{
+				"req":     json.RawMessage(`{"id":"req","ext":{"prebid":{"storedrequest":{"extends":["first","second"]}}}}`),
+				"first":   json.RawMessage(`{"tmax":1000,"test":1,"ext":{"prebid":{"storedrequest":{"extends":"grandpa"}}}}`),
+				"second":  json.RawMessage(`{"tmax":2000}`),
+				"grandpa": json.RawMessage(`{"at":1,"test":0}`),
+			},
+			givenRequestIDs:  []string{"req"},
+			expectedRequests: map[string]string{"req": `{"id":"req","at":1,"test":1,"tmax":2000,"ext":{"prebid":{"storedrequest":{"chain":["first","grandpa","second"]}}}}`},
+			expectedImps:     map[string]string{},
+		},
+		{
+			name: "null-removes-parent-field",
+			givenRequests: map[string]json.RawMessage{
+				"req":    json.RawMessage(`{"site":{"domain":null},"ext":{"prebid":{"storedrequest":{"extends":"parent"}}}}`),
+				"parent": json.RawMessage(`{"site":{"page":"parent.com","domain":"parent.com"}}`),
+			},
+			givenRequestIDs:  []string{"req"},
+			expectedRequests: map[string]string{"req": `{"site":{"page":"parent.com"},"ext":{"prebid":{"storedrequest":{"chain":["parent"]}}}}`},
+			expectedImps:     map[string]string{},
+		},
+		{
+			name: "requests-and-imps",
+			givenRequests: map[string]json.RawMessage{
+				"req":    json.RawMessage(`{"id":"req","ext":{"prebid":{"storedrequest":{"extends":"parent"}}}}`),
+				"parent": json.RawMessage(`{"tmax":1000}`),
+			},
+			givenImps: map[string]json.RawMessage{
+				"imp":    json.RawMessage(`{"id":"imp","ext":{"prebid":{"storedrequest":{"extends":["parent"]}}}}`),
+				"parent": json.RawMessage(`{"banner":{"format":[{"w":300,"h":250}]}}`),
+			},
+			givenRequestIDs:  []string{"req"},
+			givenImpIDs:      []string{"imp"},
+			expectedRequests: map[string]string{"req": `{"id":"req","tmax":1000,"ext":{"prebid":{"storedrequest":{"chain":["parent"]}}}}`},
+			expectedImps:     map[string]string{"imp": `{"id":"imp","banner":{"format":[{"w":300,"h":250}]},"ext":{"prebid":{"storedrequest":{"chain":["parent"]}}}}`},
+		},
+		{
+			name: "shared-ancestor",
+			givenRequests: map[string]json.RawMessage{
+				"req1":   json.RawMessage(`{"id":"req1","ext":{"prebid":{"storedrequest":{"extends":"parent"}}}}`),
+				"req2":   json.RawMessage(`{"id":"req2","ext":{"prebid":{"storedrequest":{"extends":["parent","req1"]}}}}`),
+				"parent": json.RawMessage(`{"tmax":1000}`),
+			},
+			givenRequestIDs: []string{"req1", "req2"},
+			expectedRequests: map[string]string{
+				"req1": `{"id":"req1","tmax":1000,"ext":{"prebid":{"storedrequest":{"chain":["parent"]}}}}`,
+				"req2": `{"id":"req2","tmax":1000,"ext":{"prebid":{"storedrequest":{"chain":["parent","req1","parent"]}}}}`,
+			},
+			expectedImps: map[string]string{},
+		},
+		{
+			name: "not-found-id-reported",
+			givenRequests: map[string]json.RawMessage{
+				"req":    json.RawMessage(`{"id":"req","ext":{"prebid":{"storedrequest":{"extends":"parent"}}}}`),
+				"parent": json.RawMessage(`{"tmax":1000}`),
+			},
+			givenRequestIDs:  []string{"req", "other"},
+			expectedRequests: map[string]string{"req": `{"id":"req","tmax":1000,"ext":{"prebid":{"storedrequest":{"chain":["parent"]}}}}`},
+			expectedImps:     map[string]string{},
+			expectedErrors:   []string{`Stored Request with ID="other" not found.`},
+		},
+		{
+			name: "parent-not-found",
+			givenRequests: map[string]json.RawMessage{
+				"req": json.RawMessage(`{"ext":{"prebid":{"storedrequest":{"extends":"parent"}}}}`),
+			},
+			givenRequestIDs: []string{"req"},
+			expectedErrors:  []string{`Stored Request with ID="parent" not found.`},
+		},
+		{
+			name: "cycle",
+			givenRequests: map[string]json.RawMessage{
+				"req": json.RawMessage(`{"ext":{"prebid":{"storedrequest":{"extends":"a"}}}}`),
+				"a":   json.RawMessage(`{"ext":{"prebid":{"storedrequest":{"extends":"b"}}}}`),
+				"b":   json.RawMessage(`{"ext":{"prebid":{"storedrequest":{"extends":"a"}}}}`),
+			},
+			givenRequestIDs: []string{"req"},
+			expectedErrors:  []string{`Stored Request with ID="a" extends itself: req -> a -> b -> a`},
+		},
+		{
+			name: "self-extension",
+			givenImps: map[string]json.RawMessage{
+				"imp": json.RawMessage(`{"ext":{"prebid":{"storedrequest":{"extends":"imp"}}}}`),
+			},
+			givenImpIDs:    []string{"imp"},
+			expectedErrors: []string{`Stored Imp with ID="imp" extends itself: imp -> imp`},
+		},
+		{
+			name: "invalid-extends",
+			givenRequests: map[string]json.RawMessage{
+				"req": json.RawMessage(`{"ext":{"prebid":{"storedrequest":{"extends":1}}}}`),
+			},
+			givenRequestIDs: []string{"req"},
+			expectedErrors:  []string{`Stored Request with ID="req": ext.prebid.storedrequest.extends must be a string or an array of strings`},
+		},
+		{
+			name: "invalid-extends-array",
+			givenRequests: map[string]json.RawMessage{
+				"req": json.RawMessage(`{"ext":{"prebid":{"storedrequest":{"extends":["parent",""]}}}}`),
+			},
+			givenRequestIDs: []string{"req"},
+			expectedErrors:  []string{`Stored Request with ID="req": ext.prebid.storedrequest.extends must be a string or an array of strings`},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			fetcher := WithInheritance(&mapFetcher{requests: test.givenRequests, imps: test.givenImps})
+
+			requestData, impData, errs := fetcher.FetchRequests(context.Background(), test.givenRequestIDs, test.givenImpIDs)
+
+			var errMessages []string
+			for _, err := range errs {
+				errMessages = append(errMessages, err.Error())
+			}
+			assert.Equal(t, test.expectedErrors, errMessages)
+
+			if test.expectedRequests == nil {
+				assert.Nil(t, requestData)
+			} else {
+				assert.Len(t, requestData, len(test.expectedRequests))
+				for id, expected := range test.expectedRequests {
+					assert.JSONEq(t, expected, string(requestData[id]), id)
+				}
+			}
+			if test.expectedImps == nil {
+				assert.Nil(t, impData)
+			} else {
+				assert.Len(t, impData, len(test.expectedImps))
+				for id, expected := range test.expectedImps {
+					assert.JSONEq(t, expected, string(impData[id]), id)
+				}
+			}
+		})
+	}
+}
+
+func TestWithInheritanceKeepsFetchedData(t *testing.T) {
+	child := json.RawMessage(`{"id":"req","ext":{"prebid":{"storedrequest":{"extends":"parent"}}}}`)
+	original := string(child)
+	fetcher := WithInheritance(&mapFetcher{requests: map[string]json.RawMessage{
+		"req":    child,
+		"parent": json.RawMessage(`{"tmax":1000}`),
+	}})
+
+	_, _, errs := fetcher.FetchRequests(context.Background(), []string{"req"}, nil)
+
+	assert.Empty(t, errs)
+	assert.Equal(t, original, string(child), "the data of the underlying fetcher must not be modified")
+}
+
+// mapFetcher returns the stored data of its maps, and a NotFoundError for the ids which aren't there.
+type mapFetcher struct {
+	requests map[string]json.RawMessage
+	imps     map[string]json.RawMessage
+}
+
+func (f *mapFetcher) FetchRequests(ctx context.Context, requestIDs []string, impIDs []string) (map[string]json.RawMessage, map[string]json.RawMessage, []error) {
+	var errs []error
+	requestData := make(map[string]json.RawMessage, len(requestIDs))
+	for _, id := range requestIDs {
+		if data, ok := f.requests[id]; ok {
+			requestData[id] = data
+		} else {
+			errs = append(errs, NotFoundError{ID: id, DataType: "Request"})
+		}
+	}
+	impData := make(map[string]json.RawMessage, len(impIDs))
+	for _, id := range impIDs {
+		if data, ok := f.imps[id]; ok {
+			impData[id] = data
+		} else {
+			errs = append(errs, NotFoundError{ID: id, DataType: "Imp"})
+		}
+	}
+	return requestData, impData, errs
+}
+
+func (f *mapFetcher) FetchResponses(ctx context.Context, ids []string) (map[string]json.RawMessage, []error) {
+	return nil, nil
+}
@@ -55,6 +55,32 @@ func (mf MultiFetcher) FetchAccount(ctx context.Context, accountDefaultJSON json
 	return nil, errs
 }
 
+// FetchFloors polls the sub-Fetchers which can fetch floors, until all the IDs are found.
+func (mf MultiFetcher) FetchFloors(ctx context.Context, ids []string) (data map[string]json.RawMessage, errs []error) {
+	data = make(map[string]json.RawMessage, len(ids))
+
+	for _, f := range mf {
+		ff, ok := f.(FloorsFetcher)
+		if !ok {
+			continue
+		}
+		ids = filter(ids, data)
+		if len(ids) == 0 {
+			break
+		}
+
+		theseData, ferrs := ff.FetchFloors(ctx, ids)
+		// Drop NotFound errors, as other fetchers may have them. Also don't want multiple NotFound errors per ID.
+		ferrs = dropMissingIDs(ferrs)
+		if len(ferrs) > 0 {
+			errs = append(errs, ferrs...)
+		}
+		addAll(data, theseData)
+	}
+	errs = appendNotFoundErrors("Floors", ids, data, errs)
+	return
+}
+
 func (mf MultiFetcher) FetchCategories(ctx context.Context, primaryAdServer, publisherId, iabCategory string) (string, error) {
 	for _, f := range mf {
 		if cf, ok := f.(CategoryFetcher); ok {
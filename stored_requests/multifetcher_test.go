@@ -176,3 +176,28 @@ func TestMultiFetcherAccountNotFound(t *testing.T) {
 	assert.Nil(t, account)
 	assert.EqualError(t, errs[0], NotFoundError{"MISSING", "Account"}.Error())
 }
+
+func TestMultiFetcherFloors(t *testing.T) {
+	f1 := &mockFetcher{}
+	f2 := &mockFetcher{}
+	fetcher := &MultiFetcher{f1, struct{ AllFetcher }{&mockFetcher{}}, f2}
+	ctx := context.Background()
+
+	f1.On("FetchFloors", ctx, []string{"ONE", "TWO", "MISSING"}).Once().Return(
+		map[string]json.RawMessage{"ONE": json.RawMessage(`{"id": "ONE"}`)},
+		[]error{NotFoundError{"TWO", "Floors"}, NotFoundError{"MISSING", "Floors"}},
+	)
+	f2.On("FetchFloors", ctx, []string{"TWO", "MISSING"}).Once().Return(
+		map[string]json.RawMessage{"TWO": json.RawMessage(`{"id": "TWO"}`)},
+		[]error{NotFoundError{"MISSING", "Floors"}},
+	)
+
+	floorsData, errs := fetcher.FetchFloors(ctx, []string{"ONE", "TWO", "MISSING"})
+
+	f1.AssertExpectations(t)
+	f2.AssertExpectations(t)
+	assert.Equal(t, []error{NotFoundError{"MISSING", "Floors"}}, errs, "MultiFetcher should return a single NotFoundError for the floors missing in all the fetchers")
+	assert.Len(t, floorsData, 2)
+	assert.JSONEq(t, `{"id": "ONE"}`, string(floorsData["ONE"]))
+	assert.JSONEq(t, `{"id": "TWO"}`, string(floorsData["TWO"]))
+}
@@ -0,0 +1,71 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/prebid/prebid-server/v4/config"
+	"github.com/prebid/prebid-server/v4/logger"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+)
+
+const shutdownTimeout = 5 * time.Second
+
+// NewTracerProvider registers the global tracer provider and propagator described by the configuration.
+// The returned function flushes pending spans and stops the exporter. Nothing is registered if tracing
+// is disabled, leaving the no-op global tracer provider in place.
+func NewTracerProvider(cfg config.Tracing) (func(), error) {
+	if !cfg.Enabled {
+		return func() {}, nil
+	}
+
+	exporter, err := newExporter(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	sampler := newAccountSampler(sdktrace.NewBatchSpanProcessor(exporter), cfg.SamplingRate)
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithResource(resource.NewSchemaless(semconv.ServiceName(cfg.ServiceName))),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.MaxSamplingRate))),
+		sdktrace.WithSpanProcessor(sampler),
+	)
+
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	activeSampler.Store(sampler)
+
+	return func() {
+		ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		if err := provider.Shutdown(ctx); err != nil {
+			logger.Errorf("Failed to shut down the tracer provider: %v", err)
+		}
+	}, nil
+}
+
+func newExporter(cfg config.Tracing) (sdktrace.SpanExporter, error) {
+	switch cfg.Exporter {
+	case config.TracingExporterOTLPHTTP:
+		opts := []otlptracehttp.Option{
+			otlptracehttp.WithEndpoint(cfg.OTLP.Endpoint),
+			otlptracehttp.WithURLPath(cfg.OTLP.URLPath),
+			otlptracehttp.WithHeaders(cfg.OTLP.Headers),
+			otlptracehttp.WithTimeout(time.Duration(cfg.OTLP.TimeoutMS) * time.Millisecond),
+		}
+		if cfg.OTLP.Insecure {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		}
+		return otlptracehttp.New(context.Background(), opts...)
+	case config.TracingExporterStdout:
+		return stdouttrace.New()
+	}
+	return nil, fmt.Errorf("unknown tracing exporter %s", cfg.Exporter)
+}
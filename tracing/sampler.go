@@ -0,0 +1,141 @@
+package tracing
+
+import (
+	"context"
+	"encoding/binary"
+	"sync"
+	"sync/atomic"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// activeSampler is the account sampler of the registered tracer provider, or nil if tracing is disabled.
+var activeSampler atomic.Pointer[accountSampler]
+
+// accountSampler is a span processor which applies the sampling rate of the account of a request to its
+// trace. The account is only known part way through a request, after spans have already been started, so
+// spans which end before the sampling decision is made are held back until then. The decision falls back to
+// the host sampling rate if it's still pending when the local root span of the trace ends.
+//
+// Traces continuing a sampled remote parent are always exported.
+type accountSampler struct {
+	next        sdktrace.SpanProcessor
+	defaultRate float64
+
+	mutex  sync.Mutex
+	traces map[trace.TraceID]*traceSampling
+}
+
+type traceSampling struct {
+	rootSpanID trace.SpanID
+	decided    bool
+	sampled    bool
+	pending    []sdktrace.ReadOnlySpan
+}
+
+func newAccountSampler(next sdktrace.SpanProcessor, defaultRate float64) *accountSampler {
+	return &accountSampler{
+		next:        next,
+		defaultRate: defaultRate,
+		traces:      make(map[trace.TraceID]*traceSampling),
+	}
+}
+
+func (s *accountSampler) OnStart(parent context.Context, span sdktrace.ReadWriteSpan) {
+	parentSpanContext := span.Parent()
+	isLocalRoot := !parentSpanContext.IsValid() || parentSpanContext.IsRemote()
+	if isLocalRoot && !parentSpanContext.IsSampled() {
+		spanContext := span.SpanContext()
+		s.mutex.Lock()
+		s.traces[spanContext.TraceID()] = &traceSampling{rootSpanID: spanContext.SpanID()}
+		s.mutex.Unlock()
+	}
+	s.next.OnStart(parent, span)
+}
+
+func (s *accountSampler) OnEnd(span sdktrace.ReadOnlySpan) {
+	spanContext := span.SpanContext()
+
+	s.mutex.Lock()
+	t, ok := s.traces[spanContext.TraceID()]
+	if !ok {
+		s.mutex.Unlock()
+		s.next.OnEnd(span)
+		return
+	}
+
+	isRoot := spanContext.SpanID() == t.rootSpanID
+	if !t.decided && !isRoot {
+		t.pending = append(t.pending, span)
+		s.mutex.Unlock()
+		return
+	}
+	if !t.decided {
+		t.decided = true
+		t.sampled = isSampled(spanContext.TraceID(), s.defaultRate)
+	}
+
+	var export []sdktrace.ReadOnlySpan
+	if t.sampled {
+		export = append(t.pending, span)
+	}
+	t.pending = nil
+	if isRoot {
+		delete(s.traces, spanContext.TraceID())
+	}
+	s.mutex.Unlock()
+
+	for _, span := range export {
+		s.next.OnEnd(span)
+	}
+}
+
+func (s *accountSampler) Shutdown(ctx context.Context) error {
+	return s.next.Shutdown(ctx)
+}
+
+func (s *accountSampler) ForceFlush(ctx context.Context) error {
+	return s.next.ForceFlush(ctx)
+}
+
+// decide makes the sampling decision for the trace using the account sampling rate, or the host sampling
+// rate if the account doesn't define one. Only the first decision for a trace is applied.
+func (s *accountSampler) decide(traceID trace.TraceID, accountRate *float64) {
+	rate := s.defaultRate
+	if accountRate != nil {
+		rate = *accountRate
+	}
+
+	s.mutex.Lock()
+	t, ok := s.traces[traceID]
+	if !ok || t.decided {
+		s.mutex.Unlock()
+		return
+	}
+	sampled := isSampled(traceID, rate)
+	t.decided = true
+	t.sampled = sampled
+	pending := t.pending
+	t.pending = nil
+	s.mutex.Unlock()
+
+	if sampled {
+		for _, span := range pending {
+			s.next.OnEnd(span)
+		}
+	}
+}
+
+// isSampled makes the same decision as the OpenTelemetry trace ID ratio based sampler, so that traces are
+// consistently sampled when a higher rate is used by the head sampler.
+func isSampled(traceID trace.TraceID, rate float64) bool {
+	if rate >= 1 {
+		return true
+	}
+	if rate <= 0 {
+		return false
+	}
+	upperBound := uint64(rate * (1 << 63))
+	return binary.BigEndian.Uint64(traceID[8:16])>>1 < upperBound
+}
@@ -0,0 +1,121 @@
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prebid/prebid-server/v4/config"
+	"github.com/prebid/prebid-server/v4/util/ptrutil"
+	"github.com/stretchr/testify/assert"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestAccountSampler(t *testing.T) {
+	remoteParent := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{0x01},
+		SpanID:     trace.SpanID{0x01},
+		TraceFlags: trace.FlagsSampled,
+		Remote:     true,
+	})
+
+	testCases := []struct {
+		description       string
+		defaultRate       float64
+		parent            trace.SpanContext
+		account           *config.Account
+		expectedSpanNames []string
+	}{
+		{
+			description:       "account-sampled",
+			defaultRate:       0,
+			account:           &config.Account{ID: "a", Tracing: config.AccountTracing{SamplingRate: ptrutil.ToPtr(1.0)}},
+			expectedSpanNames: []string{"before", "after", "root"},
+		},
+		{
+			description:       "account-not-sampled",
+			defaultRate:       1,
+			account:           &config.Account{ID: "a", Tracing: config.AccountTracing{SamplingRate: ptrutil.ToPtr(0.0)}},
+			expectedSpanNames: []string{},
+		},
+		{
+			description:       "account-without-rate-uses-default",
+			defaultRate:       1,
+			account:           &config.Account{ID: "a"},
+			expectedSpanNames: []string{"before", "after", "root"},
+		},
+		{
+			description:       "no-account-uses-default-sampled",
+			defaultRate:       1,
+			account:           nil,
+			expectedSpanNames: []string{"before", "after", "root"},
+		},
+		{
+			description:       "no-account-uses-default-not-sampled",
+			defaultRate:       0,
+			account:           nil,
+			expectedSpanNames: []string{},
+		},
+		{
+			description:       "sampled-remote-parent",
+			defaultRate:       0,
+			parent:            remoteParent,
+			account:           &config.Account{ID: "a", Tracing: config.AccountTracing{SamplingRate: ptrutil.ToPtr(0.0)}},
+			expectedSpanNames: []string{"before", "after", "root"},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			exporter := tracetest.NewInMemoryExporter()
+			sampler := newAccountSampler(sdktrace.NewSimpleSpanProcessor(exporter), test.defaultRate)
+			provider := sdktrace.NewTracerProvider(sdktrace.WithSampler(sdktrace.AlwaysSample()), sdktrace.WithSpanProcessor(sampler))
+			tracer := provider.Tracer("test")
+
+			activeSampler.Store(sampler)
+			defer activeSampler.Store(nil)
+
+			ctx := context.Background()
+			if test.parent.IsValid() {
+				ctx = trace.ContextWithRemoteSpanContext(ctx, test.parent)
+			}
+
+			ctx, root := tracer.Start(ctx, "root")
+			_, before := tracer.Start(ctx, "before")
+			before.End()
+			SetAccount(ctx, test.account)
+			_, after := tracer.Start(ctx, "after")
+			after.End()
+			root.End()
+
+			spanNames := []string{}
+			for _, span := range exporter.GetSpans() {
+				spanNames = append(spanNames, span.Name)
+			}
+			assert.Equal(t, test.expectedSpanNames, spanNames)
+			assert.Empty(t, sampler.traces, "trace sampling state should be released when the root span ends")
+		})
+	}
+}
+
+func TestIsSampled(t *testing.T) {
+	traceID := trace.TraceID{0, 0, 0, 0, 0, 0, 0, 0, 0x40}
+
+	testCases := []struct {
+		description string
+		rate        float64
+		expected    bool
+	}{
+		{description: "never", rate: 0, expected: false},
+		{description: "always", rate: 1, expected: true},
+		{description: "below-bound", rate: 0.75, expected: true},
+		{description: "above-bound", rate: 0.25, expected: false},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			assert.Equal(t, test.expected, isSampled(traceID, test.rate))
+		})
+	}
+}
@@ -0,0 +1,69 @@
+// Package tracing instruments Prebid Server with OpenTelemetry distributed tracing.
+//
+// Spans are created through the global OpenTelemetry tracer provider, which is a no-op unless tracing is
+// enabled in the host configuration. Instrumented code therefore doesn't need to check whether tracing is
+// enabled before creating spans.
+package tracing
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/prebid/prebid-server/v4/config"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/prebid/prebid-server/v4"
+
+// Attribute keys specific to Prebid Server.
+const (
+	AttributeAccount = attribute.Key("pbs.account")
+	AttributeBidder  = attribute.Key("pbs.bidder")
+	AttributeStage   = attribute.Key("pbs.hook.stage")
+	AttributeModule  = attribute.Key("pbs.hook.module")
+	AttributeHook    = attribute.Key("pbs.hook.code")
+)
+
+// Start creates a span and a context containing it. The span is a child of the span in ctx, if any.
+func Start(ctx context.Context, spanName string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentationName).Start(ctx, spanName, opts...)
+}
+
+// Extract returns a copy of ctx carrying the remote span context of the W3C traceparent header, if any.
+func Extract(ctx context.Context, header http.Header) context.Context {
+	return otel.GetTextMapPropagator().Extract(ctx, propagation.HeaderCarrier(header))
+}
+
+// Inject writes the W3C traceparent header for the span in ctx to the header.
+func Inject(ctx context.Context, header http.Header) {
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(header))
+}
+
+// SetAccount tags the span in ctx with the account and samples its trace according to the sampling rate
+// of the account. It should be called as soon as the account of a request is known.
+func SetAccount(ctx context.Context, account *config.Account) {
+	span := trace.SpanFromContext(ctx)
+	if !span.IsRecording() || account == nil {
+		return
+	}
+	span.SetAttributes(AttributeAccount.String(account.ID))
+
+	if s := activeSampler.Load(); s != nil {
+		s.decide(span.SpanContext().TraceID(), account.Tracing.SamplingRate)
+	}
+}
+
+// RecordErrors marks the span as failed and records the errors as span events.
+func RecordErrors(span trace.Span, errs ...error) {
+	if len(errs) == 0 || !span.IsRecording() {
+		return
+	}
+	for _, err := range errs {
+		span.RecordError(err)
+	}
+	span.SetStatus(codes.Error, errs[0].Error())
+}
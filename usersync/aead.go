@@ -0,0 +1,178 @@
+package usersync
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/prebid/prebid-server/v4/config"
+	"github.com/prebid/prebid-server/v4/metrics"
+)
+
+// aeadCookieVersion starts the value of the cookies written by the AEADEncoder, which is formatted as
+// "<version>.<key id>.<base64 encoded nonce and ciphertext>". Base64 encoded cookies never contain a '.',
+// so both formats can be told apart.
+const aeadCookieVersion = "v1"
+
+// keyRing holds the AEAD of every key of the host key ring.
+type keyRing struct {
+	activeKeyID string
+	aeads       map[string]cipher.AEAD
+}
+
+func newKeyRing(cfg config.CookieKeyRing) (*keyRing, error) {
+	ring := &keyRing{
+		activeKeyID: cfg.ActiveKeyID,
+		aeads:       make(map[string]cipher.AEAD, len(cfg.Keys)),
+	}
+	for _, key := range cfg.Keys {
+		secret, err := base64.StdEncoding.DecodeString(key.Secret)
+		if err != nil {
+			return nil, fmt.Errorf("key %s: %v", key.ID, err)
+		}
+		block, err := aes.NewCipher(secret)
+		if err != nil {
+			return nil, fmt.Errorf("key %s: %v", key.ID, err)
+		}
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, fmt.Errorf("key %s: %v", key.ID, err)
+		}
+		ring.aeads[key.ID] = aead
+	}
+	return ring, nil
+}
+
+func mustNewKeyRing(cfg config.CookieKeyRing) *keyRing {
+	ring, err := newKeyRing(cfg)
+	if err != nil {
+		// the keys are checked when the configuration is validated
+		panic(fmt.Sprintf("invalid uids cookie key ring: %v", err))
+	}
+	return ring
+}
+
+// NewEncoder returns the encoder of the uids cookie configured for the host.
+func NewEncoder(cfg *config.HostCookie) Encoder {
+	compact := cfg.Format == config.HostCookieFormatCompact
+	if !cfg.KeyRing.Enabled() {
+		return Base64Encoder{Compact: compact}
+	}
+	return AEADEncoder{keyRing: mustNewKeyRing(cfg.KeyRing), compact: compact}
+}
+
+// NewDecoder returns the decoder of the uids cookie configured for the host. Cookies written with the key
+// ring can be read as soon as it holds keys, so that they can be distributed to every instance before one
+// of them is made active.
+func NewDecoder(cfg *config.HostCookie, metricsEngine metrics.MetricsEngine) Decoder {
+	if len(cfg.KeyRing.Keys) == 0 {
+		return Base64Decoder{}
+	}
+	return AEADDecoder{
+		keyRing:       mustNewKeyRing(cfg.KeyRing),
+		acceptLegacy:  cfg.KeyRing.AcceptLegacy || !cfg.KeyRing.Enabled(),
+		metricsEngine: metricsEngine,
+	}
+}
+
+// AEADEncoder encrypts and authenticates the uids cookie with the active key of the host key ring, so that
+// users can neither read nor forge the UIDs it carries.
+type AEADEncoder struct {
+	keyRing *keyRing
+	compact bool
+}
+
+func (e AEADEncoder) Encode(c *Cookie) (string, error) {
+	j, err := marshalCookie(c, e.compact)
+	if err != nil {
+		return "", err
+	}
+
+	aead := e.keyRing.aeads[e.keyRing.activeKeyID]
+	header := aeadCookieVersion + "." + e.keyRing.activeKeyID
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := aead.Seal(nonce, nonce, j, []byte(header))
+
+	return header + "." + base64.RawURLEncoding.EncodeToString(sealed), nil
+}
+
+// AEADDecoder reads the uids cookies written by the AEADEncoder with any key of the host key ring, along with
+// the base64 encoded cookies written before the key ring was configured if they're accepted. These are written
+// again by the AEADEncoder the next time the cookie is updated. Cookies which can't be read are replaced by a
+// new one.
+type AEADDecoder struct {
+	keyRing       *keyRing
+	acceptLegacy  bool
+	metricsEngine metrics.MetricsEngine
+}
+
+var (
+	errCookieTampered    = errors.New("the uids cookie failed authentication")
+	errCookieUndecodable = errors.New("the uids cookie is malformed")
+)
+
+func (d AEADDecoder) Decode(encodedValue string) *Cookie {
+	if !strings.Contains(encodedValue, ".") {
+		// base64 encoded cookies aren't authenticated, so they may have been forged
+		if !d.acceptLegacy {
+			d.metricsEngine.RecordUIDCookieDecode(metrics.UIDCookieDecodeLegacyRejected)
+			return NewCookie()
+		}
+		cookie, err := decodeBase64Cookie(encodedValue)
+		if err != nil {
+			d.metricsEngine.RecordUIDCookieDecode(metrics.UIDCookieDecodeUndecodable)
+			return NewCookie()
+		}
+		d.metricsEngine.RecordUIDCookieDecode(metrics.UIDCookieDecodeLegacy)
+		return cookie
+	}
+
+	cookie, err := d.open(encodedValue)
+	switch err {
+	case nil:
+		return cookie
+	case errCookieTampered:
+		d.metricsEngine.RecordUIDCookieDecode(metrics.UIDCookieDecodeTampered)
+	default:
+		d.metricsEngine.RecordUIDCookieDecode(metrics.UIDCookieDecodeUndecodable)
+	}
+	return NewCookie()
+}
+
+func (d AEADDecoder) open(encodedValue string) (*Cookie, error) {
+	parts := strings.SplitN(encodedValue, ".", 3)
+	if len(parts) != 3 || parts[0] != aeadCookieVersion {
+		return nil, errCookieUndecodable
+	}
+	keyID, payload := parts[1], parts[2]
+
+	sealed, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return nil, errCookieUndecodable
+	}
+
+	// cookies written with a key which isn't in the ring anymore have expired, unless they were forged
+	aead, ok := d.keyRing.aeads[keyID]
+	if !ok || len(sealed) < aead.NonceSize() {
+		return nil, errCookieTampered
+	}
+
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	j, err := aead.Open(nil, nonce, ciphertext, []byte(aeadCookieVersion+"."+keyID))
+	if err != nil {
+		return nil, errCookieTampered
+	}
+
+	cookie, err := unmarshalCookie(j)
+	if err != nil {
+		return nil, errCookieUndecodable
+	}
+	return cookie, nil
+}
@@ -0,0 +1,243 @@
+package usersync
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prebid/prebid-server/v4/config"
+	"github.com/prebid/prebid-server/v4/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const (
+	testKeySecret1 = "MDEyMzQ1Njc4OWFiY2RlZg=="
+	testKeySecret2 = "MDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODlhYmNkZWY="
+)
+
+func testKeyRingHostCookie(activeKeyID string, keys ...config.CookieKey) *config.HostCookie {
+	return &config.HostCookie{KeyRing: config.CookieKeyRing{ActiveKeyID: activeKeyID, Keys: keys, AcceptLegacy: true}}
+}
+
+func withoutLegacy(hostCookie *config.HostCookie) *config.HostCookie {
+	hostCookie.KeyRing.AcceptLegacy = false
+	return hostCookie
+}
+
+func TestNewEncoderDecoder(t *testing.T) {
+	key1 := config.CookieKey{ID: "1", Secret: testKeySecret1}
+
+	testCases := []struct {
+		name            string
+		givenHostCookie *config.HostCookie
+		expectedEncoder Encoder
+		expectedDecoder Decoder
+	}{
+		{
+			name:            "no-key-ring",
+			givenHostCookie: &config.HostCookie{},
+			expectedEncoder: Base64Encoder{},
+			expectedDecoder: Base64Decoder{},
+		},
+		{
+			name:            "keys-without-active-key",
+			givenHostCookie: testKeyRingHostCookie("", key1),
+			expectedEncoder: Base64Encoder{},
+			expectedDecoder: AEADDecoder{},
+		},
+		{
+			name:            "active-key",
+			givenHostCookie: testKeyRingHostCookie("1", key1),
+			expectedEncoder: AEADEncoder{},
+			expectedDecoder: AEADDecoder{},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			assert.IsType(t, test.expectedEncoder, NewEncoder(test.givenHostCookie))
+			assert.IsType(t, test.expectedDecoder, NewDecoder(test.givenHostCookie, &metrics.MetricsEngineMock{}))
+		})
+	}
+}
+
+func TestNewEncoderInvalidKeyRing(t *testing.T) {
+	hostCookie := testKeyRingHostCookie("1", config.CookieKey{ID: "1", Secret: "c2hvcnQ="})
+
+	assert.Panics(t, func() { NewEncoder(hostCookie) })
+	assert.Panics(t, func() { NewDecoder(hostCookie, &metrics.MetricsEngineMock{}) })
+}
+
+func TestAEADEncoderDecoder(t *testing.T) {
+	key1 := config.CookieKey{ID: "1", Secret: testKeySecret1}
+	key2 := config.CookieKey{ID: "2", Secret: testKeySecret2}
+
+	cookie := &Cookie{
+		uids: map[string]UIDEntry{
+			"adnxs": {UID: "UID", Expires: time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)},
+		},
+	}
+
+	testCases := []struct {
+		name            string
+		givenEncoder    Encoder
+		givenDecoderCfg *config.HostCookie
+		givenTamper     func(string) string
+		expectedStatus  metrics.UIDCookieDecodeStatus
+		expectedCookie  *Cookie
+	}{
+		{
+			name:            "same-key",
+			givenEncoder:    NewEncoder(testKeyRingHostCookie("1", key1)),
+			givenDecoderCfg: testKeyRingHostCookie("1", key1),
+			expectedCookie:  cookie,
+		},
+		{
+			name:            "rotated-key",
+			givenEncoder:    NewEncoder(testKeyRingHostCookie("1", key1)),
+			givenDecoderCfg: testKeyRingHostCookie("2", key1, key2),
+			expectedCookie:  cookie,
+		},
+		{
+			name:            "key-not-active-yet",
+			givenEncoder:    NewEncoder(testKeyRingHostCookie("2", key1, key2)),
+			givenDecoderCfg: testKeyRingHostCookie("1", key1, key2),
+			expectedCookie:  cookie,
+		},
+		{
+			name:            "legacy",
+			givenEncoder:    Base64Encoder{},
+			givenDecoderCfg: testKeyRingHostCookie("1", key1),
+			expectedStatus:  metrics.UIDCookieDecodeLegacy,
+			expectedCookie:  cookie,
+		},
+		{
+			name:            "legacy-rejected",
+			givenEncoder:    Base64Encoder{},
+			givenDecoderCfg: withoutLegacy(testKeyRingHostCookie("1", key1)),
+			expectedStatus:  metrics.UIDCookieDecodeLegacyRejected,
+			expectedCookie:  NewCookie(),
+		},
+		{
+			name:            "legacy-without-active-key",
+			givenEncoder:    Base64Encoder{},
+			givenDecoderCfg: withoutLegacy(testKeyRingHostCookie("", key1)),
+			expectedStatus:  metrics.UIDCookieDecodeLegacy,
+			expectedCookie:  cookie,
+		},
+		{
+			name:            "authenticated-with-legacy-rejected",
+			givenEncoder:    NewEncoder(testKeyRingHostCookie("1", key1)),
+			givenDecoderCfg: withoutLegacy(testKeyRingHostCookie("1", key1)),
+			expectedCookie:  cookie,
+		},
+		{
+			name:            "removed-key",
+			givenEncoder:    NewEncoder(testKeyRingHostCookie("1", key1)),
+			givenDecoderCfg: testKeyRingHostCookie("2", key2),
+			expectedStatus:  metrics.UIDCookieDecodeTampered,
+			expectedCookie:  NewCookie(),
+		},
+		{
+			name:            "tampered-ciphertext",
+			givenEncoder:    NewEncoder(testKeyRingHostCookie("1", key1)),
+			givenDecoderCfg: testKeyRingHostCookie("1", key1),
+			givenTamper: func(value string) string {
+				sealed, _ := base64.RawURLEncoding.DecodeString(value[strings.LastIndex(value, ".")+1:])
+				sealed[len(sealed)-1] ^= 1
+				return value[:strings.LastIndex(value, ".")+1] + base64.RawURLEncoding.EncodeToString(sealed)
+			},
+			expectedStatus: metrics.UIDCookieDecodeTampered,
+			expectedCookie: NewCookie(),
+		},
+		{
+			name:            "tampered-key-id",
+			givenEncoder:    NewEncoder(testKeyRingHostCookie("1", key1)),
+			givenDecoderCfg: testKeyRingHostCookie("1", key1, key2),
+			givenTamper: func(value string) string {
+				return strings.Replace(value, "v1.1.", "v1.2.", 1)
+			},
+			expectedStatus: metrics.UIDCookieDecodeTampered,
+			expectedCookie: NewCookie(),
+		},
+		{
+			name:            "truncated",
+			givenEncoder:    NewEncoder(testKeyRingHostCookie("1", key1)),
+			givenDecoderCfg: testKeyRingHostCookie("1", key1),
+			givenTamper: func(value string) string {
+				return "v1.1.AAAA"
+			},
+			expectedStatus: metrics.UIDCookieDecodeTampered,
+			expectedCookie: NewCookie(),
+		},
+		{
+			name:            "unknown-version",
+			givenEncoder:    NewEncoder(testKeyRingHostCookie("1", key1)),
+			givenDecoderCfg: testKeyRingHostCookie("1", key1),
+			givenTamper: func(value string) string {
+				return "v2" + value[2:]
+			},
+			expectedStatus: metrics.UIDCookieDecodeUndecodable,
+			expectedCookie: NewCookie(),
+		},
+		{
+			name:            "malformed-payload",
+			givenEncoder:    NewEncoder(testKeyRingHostCookie("1", key1)),
+			givenDecoderCfg: testKeyRingHostCookie("1", key1),
+			givenTamper: func(value string) string {
+				return "v1.1.!!!"
+			},
+			expectedStatus: metrics.UIDCookieDecodeUndecodable,
+			expectedCookie: NewCookie(),
+		},
+		{
+			name:            "malformed-legacy",
+			givenEncoder:    Base64Encoder{},
+			givenDecoderCfg: testKeyRingHostCookie("1", key1),
+			givenTamper: func(value string) string {
+				return "not base64"
+			},
+			expectedStatus: metrics.UIDCookieDecodeUndecodable,
+			expectedCookie: NewCookie(),
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			mockMetrics := &metrics.MetricsEngineMock{}
+			if test.expectedStatus != "" {
+				mockMetrics.On("RecordUIDCookieDecode", test.expectedStatus).Once()
+			}
+
+			value, err := test.givenEncoder.Encode(cookie)
+			assert.NoError(t, err)
+			if test.givenTamper != nil {
+				value = test.givenTamper(value)
+			}
+
+			decoded := NewDecoder(test.givenDecoderCfg, mockMetrics).Decode(value)
+
+			assert.Equal(t, test.expectedCookie, decoded)
+			mockMetrics.AssertExpectations(t)
+			if test.expectedStatus == "" {
+				mockMetrics.AssertNotCalled(t, "RecordUIDCookieDecode", mock.Anything)
+			}
+		})
+	}
+}
+
+func TestAEADEncoderUsesFreshNonce(t *testing.T) {
+	encoder := NewEncoder(testKeyRingHostCookie("1", config.CookieKey{ID: "1", Secret: testKeySecret1}))
+	cookie := &Cookie{uids: map[string]UIDEntry{"adnxs": {UID: "UID"}}}
+
+	first, err := encoder.Encode(cookie)
+	assert.NoError(t, err)
+	second, err := encoder.Encode(cookie)
+	assert.NoError(t, err)
+
+	assert.True(t, strings.HasPrefix(first, "v1.1."))
+	assert.NotEqual(t, first, second)
+	assert.NotContains(t, first, "UID")
+}
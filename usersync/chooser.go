@@ -2,9 +2,11 @@ package usersync
 
 import (
 	"strings"
+	"time"
 
 	"github.com/prebid/prebid-server/v4/config"
 	"github.com/prebid/prebid-server/v4/openrtb_ext"
+	"github.com/prebid/prebid-server/v4/util/timeutil"
 )
 
 // Chooser determines which syncers are eligible for a given request.
@@ -29,6 +31,7 @@ func NewChooser(bidderSyncerLookup map[string]Syncer, biddersKnown map[string]st
 		normalizeValidBidderName: openrtb_ext.NormalizeBidderName,
 		biddersKnown:             biddersKnown,
 		bidderInfo:               bidderInfo,
+		time:                     &timeutil.RealTime{},
 	}
 }
 
@@ -41,6 +44,7 @@ type Request struct {
 	SyncTypeFilter SyncTypeFilter
 	GPPSID         string
 	Debug          bool
+	Cooldown       Cooldown
 }
 
 // Cooperative specifies the settings for cooperative syncing for a given request, where bidders
@@ -51,6 +55,30 @@ type Cooperative struct {
 	PriorityGroupsOnly bool
 }
 
+// Cooldown limits how often a sync is offered to the user for each bidder, so that users whose syncs fail or
+// are declined aren't asked to sync again on every page view. The sync attempts are recorded in the cookie
+// with Cookie.RecordSyncAttempt.
+type Cooldown struct {
+	// Duration during which a sync isn't offered again after an attempt. There's no cooldown if 0.
+	Duration time.Duration
+	// MaxDailySyncs caps the number of syncs offered each day. There's no cap if 0.
+	MaxDailySyncs int
+	// BidderMaxDailySyncs overrides MaxDailySyncs for specific bidders.
+	BidderMaxDailySyncs map[string]int
+}
+
+// Enabled returns true if syncs are limited by a cooldown or a daily cap.
+func (c Cooldown) Enabled() bool {
+	return c.Duration > 0 || c.MaxDailySyncs > 0 || len(c.BidderMaxDailySyncs) > 0
+}
+
+func (c Cooldown) maxDailySyncs(bidder string) int {
+	if max, ok := c.BidderMaxDailySyncs[bidder]; ok {
+		return max
+	}
+	return c.MaxDailySyncs
+}
+
 // Result specifies which bidders were included in the evaluation and which syncers were chosen.
 type Result struct {
 	BiddersEvaluated []BidderEvaluation
@@ -104,6 +132,9 @@ const (
 
 	// StatusBlockedByDisabledUsersync refers to a bidder who won't be synced because it's been disabled in its config by the host
 	StatusBlockedByDisabledUsersync
+
+	// StatusCooldown specifies a sync was offered to the user too recently or too many times today for a specific bidder.
+	StatusCooldown
 )
 
 // Privacy determines which privacy policies will be enforced for a user sync request.
@@ -123,6 +154,7 @@ type standardChooser struct {
 	normalizeValidBidderName func(name string) (openrtb_ext.BidderName, bool)
 	biddersKnown             map[string]struct{}
 	bidderInfo               map[string]config.BidderInfo
+	time                     timeutil.Time
 }
 
 // Choose randomly selects user syncers which are permitted by the user's privacy settings and
@@ -148,7 +180,7 @@ func (c standardChooser) Choose(request Request, cookie *Cookie) Result {
 		if _, ok := biddersSeen[bidders[i]]; ok {
 			continue
 		}
-		syncer, evaluation := c.evaluate(bidders[i], syncersSeen, request.SyncTypeFilter, request.Privacy, cookie, request.GPPSID)
+		syncer, evaluation := c.evaluate(bidders[i], syncersSeen, request.SyncTypeFilter, request.Privacy, cookie, request.GPPSID, request.Cooldown)
 
 		biddersEvaluated = append(biddersEvaluated, evaluation)
 		if evaluation.Status == StatusOK {
@@ -160,7 +192,7 @@ func (c standardChooser) Choose(request Request, cookie *Cookie) Result {
 	return Result{Status: StatusOK, BiddersEvaluated: biddersEvaluated, SyncersChosen: syncersChosen}
 }
 
-func (c standardChooser) evaluate(bidder string, syncersSeen map[string]struct{}, syncTypeFilter SyncTypeFilter, privacy Privacy, cookie *Cookie, GPPSID string) (Syncer, BidderEvaluation) {
+func (c standardChooser) evaluate(bidder string, syncersSeen map[string]struct{}, syncTypeFilter SyncTypeFilter, privacy Privacy, cookie *Cookie, GPPSID string, cooldown Cooldown) (Syncer, BidderEvaluation) {
 	bidderNormalized, exists := c.normalizeValidBidderName(bidder)
 	if !exists {
 		return nil, BidderEvaluation{Status: StatusUnknownBidder, Bidder: bidder}
@@ -214,5 +246,9 @@ func (c standardChooser) evaluate(bidder string, syncersSeen map[string]struct{}
 		}
 	}
 
+	if cooldown.Enabled() && !cookie.SyncAttemptAllowed(syncer.Key(), cooldown.Duration, cooldown.maxDailySyncs(bidder), c.time.Now()) {
+		return nil, BidderEvaluation{Status: StatusCooldown, Bidder: bidder, SyncerKey: syncer.Key()}
+	}
+
 	return syncer, BidderEvaluation{Status: StatusOK, Bidder: bidder, SyncerKey: syncer.Key()}
 }
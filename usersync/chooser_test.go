@@ -468,6 +468,7 @@ func TestChooserEvaluate(t *testing.T) {
 	cookieNeedsSync := Cookie{}
 	cookieAlreadyHasSyncForA := Cookie{uids: map[string]UIDEntry{"keyA": {Expires: time.Now().Add(time.Duration(24) * time.Hour)}}}
 	cookieAlreadyHasSyncForB := Cookie{uids: map[string]UIDEntry{"keyB": {Expires: time.Now().Add(time.Duration(24) * time.Hour)}}}
+	cookieAttemptedSyncForA := Cookie{syncAttempts: map[string]SyncAttempt{"keyA": {Last: time.Now().Add(-time.Minute), Count: 2}}}
 
 	usersyncDisabled := ptrutil.ToPtr(false)
 
@@ -481,10 +482,63 @@ func TestChooserEvaluate(t *testing.T) {
 		givenGPPSID                 string
 		givenBidderInfo             map[string]config.BidderInfo
 		givenSyncTypeFilter         SyncTypeFilter
+		givenCooldown               Cooldown
 		normalizedBidderNamesLookup func(name string) (openrtb_ext.BidderName, bool)
 		expectedSyncer              Syncer
 		expectedEvaluation          BidderEvaluation
 	}{
+		{
+			description:                 "Blocked By Cooldown",
+			givenBidder:                 "a",
+			normalisedBidderName:        "a",
+			givenSyncersSeen:            map[string]struct{}{},
+			givenPrivacy:                fakePrivacy{gdprAllowsHostCookie: true, gdprAllowsBidderSync: true, ccpaAllowsBidderSync: true, activityAllowUserSync: true},
+			givenCookie:                 cookieAttemptedSyncForA,
+			givenSyncTypeFilter:         syncTypeFilter,
+			givenCooldown:               Cooldown{Duration: time.Hour},
+			normalizedBidderNamesLookup: normalizedBidderNamesLookup,
+			expectedSyncer:              nil,
+			expectedEvaluation:          BidderEvaluation{Bidder: "a", SyncerKey: "keyA", Status: StatusCooldown},
+		},
+		{
+			description:                 "Cooldown Over",
+			givenBidder:                 "a",
+			normalisedBidderName:        "a",
+			givenSyncersSeen:            map[string]struct{}{},
+			givenPrivacy:                fakePrivacy{gdprAllowsHostCookie: true, gdprAllowsBidderSync: true, ccpaAllowsBidderSync: true, activityAllowUserSync: true},
+			givenCookie:                 cookieAttemptedSyncForA,
+			givenSyncTypeFilter:         syncTypeFilter,
+			givenCooldown:               Cooldown{Duration: time.Second},
+			normalizedBidderNamesLookup: normalizedBidderNamesLookup,
+			expectedSyncer:              fakeSyncerA,
+			expectedEvaluation:          BidderEvaluation{Bidder: "a", SyncerKey: "keyA", Status: StatusOK},
+		},
+		{
+			description:                 "Blocked By Bidder Daily Cap",
+			givenBidder:                 "a",
+			normalisedBidderName:        "a",
+			givenSyncersSeen:            map[string]struct{}{},
+			givenPrivacy:                fakePrivacy{gdprAllowsHostCookie: true, gdprAllowsBidderSync: true, ccpaAllowsBidderSync: true, activityAllowUserSync: true},
+			givenCookie:                 cookieAttemptedSyncForA,
+			givenSyncTypeFilter:         syncTypeFilter,
+			givenCooldown:               Cooldown{MaxDailySyncs: 5, BidderMaxDailySyncs: map[string]int{"a": 2}},
+			normalizedBidderNamesLookup: normalizedBidderNamesLookup,
+			expectedSyncer:              nil,
+			expectedEvaluation:          BidderEvaluation{Bidder: "a", SyncerKey: "keyA", Status: StatusCooldown},
+		},
+		{
+			description:                 "Under Daily Cap",
+			givenBidder:                 "a",
+			normalisedBidderName:        "a",
+			givenSyncersSeen:            map[string]struct{}{},
+			givenPrivacy:                fakePrivacy{gdprAllowsHostCookie: true, gdprAllowsBidderSync: true, ccpaAllowsBidderSync: true, activityAllowUserSync: true},
+			givenCookie:                 cookieAttemptedSyncForA,
+			givenSyncTypeFilter:         syncTypeFilter,
+			givenCooldown:               Cooldown{MaxDailySyncs: 3},
+			normalizedBidderNamesLookup: normalizedBidderNamesLookup,
+			expectedSyncer:              fakeSyncerA,
+			expectedEvaluation:          BidderEvaluation{Bidder: "a", SyncerKey: "keyA", Status: StatusOK},
+		},
 		{
 			description:                 "Valid",
 			givenBidder:                 "a",
@@ -698,7 +752,7 @@ func TestChooserEvaluate(t *testing.T) {
 		t.Run(test.description, func(t *testing.T) {
 			chooser, _ := NewChooser(bidderSyncerLookup, biddersKnown, test.givenBidderInfo).(standardChooser)
 			chooser.normalizeValidBidderName = test.normalizedBidderNamesLookup
-			sync, evaluation := chooser.evaluate(test.givenBidder, test.givenSyncersSeen, test.givenSyncTypeFilter, &test.givenPrivacy, &test.givenCookie, test.givenGPPSID)
+			sync, evaluation := chooser.evaluate(test.givenBidder, test.givenSyncersSeen, test.givenSyncTypeFilter, &test.givenPrivacy, &test.givenCookie, test.givenGPPSID, test.givenCooldown)
 
 			assert.Equal(t, test.normalisedBidderName, test.givenPrivacy.inputBidderName)
 			assert.Equal(t, test.expectedSyncer, sync, test.description+":syncer")
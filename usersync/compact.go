@@ -0,0 +1,307 @@
+package usersync
+
+import (
+	"bytes"
+	"compress/flate"
+	"encoding/binary"
+	"errors"
+	"io"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/prebid/prebid-server/v4/util/jsonutil"
+)
+
+// The compact cookie format starts with a byte holding the format version, with the highest bit set if the
+// rest of the data is compressed with DEFLATE. JSON always starts with '{', so both formats can be told apart.
+//
+// Version 1 is made of:
+//
+//   - a byte of flags, telling whether the user opted out, and whether the cookie carries a user key and
+//     sync attempts
+//   - the user key, if any
+//   - the day the cookie was written, as a number of days since the Unix epoch
+//   - the number of UIDs
+//   - for each UID: the syncer key; the UID; the day it expires, relative to the day the cookie was written
+//   - the number of sync attempts, if any
+//   - for each attempt: the syncer key; the time of the latest attempt, as a number of seconds since the
+//     start of the day the cookie was written; the number of attempts on that day
+//
+// Syncer keys are written as their index in compactCookieKeys plus one, or as 0 followed by the key if it's
+// missing from the dictionary.
+//
+// Numbers are varints, and strings are prefixed with their length.
+const (
+	compactCookieVersion  byte = 1
+	compactCookieDeflated byte = 0x80
+)
+
+const (
+	compactCookieOptOut byte = 1 << iota
+	compactCookieUserKey
+	compactCookieSyncAttempts
+)
+
+const secondsPerDay = 24 * 60 * 60
+
+// maxCompactCookieSize caps the size of the inflated cookie data, which is way more than browsers allow
+// for a cookie.
+const maxCompactCookieSize = 64 * 1024
+
+var errCompactCookieMalformed = errors.New("the compact uids cookie is malformed")
+
+// flateWriters reuses the DEFLATE writers, which are expensive to allocate.
+var flateWriters = sync.Pool{
+	New: func() any {
+		w, _ := flate.NewWriter(nil, flate.BestCompression)
+		return w
+	},
+}
+
+// marshalCookie returns the cookie data in the compact format if compact is true, or as JSON otherwise.
+func marshalCookie(c *Cookie, compact bool) ([]byte, error) {
+	if !compact {
+		return jsonutil.Marshal(c)
+	}
+	return marshalCompactCookie(c, time.Now()), nil
+}
+
+// unmarshalCookie reads the cookie data written in either format.
+func unmarshalCookie(data []byte) (*Cookie, error) {
+	if len(data) > 0 && data[0]&^compactCookieDeflated == compactCookieVersion {
+		return unmarshalCompactCookie(data)
+	}
+
+	var cookie Cookie
+	if err := jsonutil.UnmarshalValid(data, &cookie); err != nil {
+		return nil, err
+	}
+	return &cookie, nil
+}
+
+func marshalCompactCookie(c *Cookie, now time.Time) []byte {
+	if c == nil {
+		c = NewCookie()
+	}
+
+	var flags byte
+	if c.optOut {
+		flags |= compactCookieOptOut
+	}
+	if c.userKey != "" {
+		flags |= compactCookieUserKey
+	}
+	if len(c.syncAttempts) > 0 {
+		flags |= compactCookieSyncAttempts
+	}
+
+	body := []byte{flags}
+	if c.userKey != "" {
+		body = appendCompactString(body, c.userKey)
+	}
+
+	today := compactDays(now)
+	body = binary.AppendVarint(body, today)
+	body = binary.AppendUvarint(body, uint64(len(c.uids)))
+
+	for _, key := range sortedKeys(c.uids) {
+		entry := c.uids[key]
+		body = appendCompactKey(body, key)
+		body = appendCompactString(body, entry.UID)
+		body = binary.AppendVarint(body, compactDays(entry.Expires)-today)
+	}
+
+	if len(c.syncAttempts) > 0 {
+		body = binary.AppendUvarint(body, uint64(len(c.syncAttempts)))
+		for _, key := range sortedKeys(c.syncAttempts) {
+			attempt := c.syncAttempts[key]
+			body = appendCompactKey(body, key)
+			body = binary.AppendVarint(body, attempt.Last.Unix()-today*secondsPerDay)
+			body = binary.AppendUvarint(body, uint64(attempt.Count))
+		}
+	}
+
+	var deflated bytes.Buffer
+	deflated.WriteByte(compactCookieVersion | compactCookieDeflated)
+	w := flateWriters.Get().(*flate.Writer)
+	w.Reset(&deflated)
+	w.Write(body)
+	w.Close()
+	flateWriters.Put(w)
+	if deflated.Len() < len(body)+1 {
+		return deflated.Bytes()
+	}
+
+	return append([]byte{compactCookieVersion}, body...)
+}
+
+func unmarshalCompactCookie(data []byte) (*Cookie, error) {
+	body := data[1:]
+	if data[0]&compactCookieDeflated != 0 {
+		inflated, err := io.ReadAll(io.LimitReader(flate.NewReader(bytes.NewReader(body)), maxCompactCookieSize))
+		if err != nil {
+			return nil, errCompactCookieMalformed
+		}
+		body = inflated
+	}
+
+	r := compactCookieReader{data: body}
+	cookie := NewCookie()
+
+	flags := r.byte()
+	if flags&compactCookieUserKey != 0 {
+		cookie.userKey = r.string()
+	}
+	today := r.varint()
+
+	count := r.uvarint()
+	for i := uint64(0); i < count && r.err == nil; i++ {
+		key := r.key()
+		uid := r.string()
+		expires := today + r.varint()
+
+		if key != "" {
+			cookie.uids[key] = UIDEntry{
+				UID:     uid,
+				Expires: time.Unix(expires*secondsPerDay, 0).UTC(),
+			}
+		}
+	}
+
+	if flags&compactCookieSyncAttempts != 0 {
+		cookie.syncAttempts = make(map[string]SyncAttempt)
+		count := r.uvarint()
+		for i := uint64(0); i < count && r.err == nil; i++ {
+			key := r.key()
+			last := today*secondsPerDay + r.varint()
+			attempts := r.uvarint()
+
+			if key != "" {
+				cookie.syncAttempts[key] = SyncAttempt{
+					Last:  time.Unix(last, 0).UTC(),
+					Count: int(attempts),
+				}
+			}
+		}
+	}
+
+	if r.err != nil || len(r.data) > 0 {
+		return nil, errCompactCookieMalformed
+	}
+
+	if flags&compactCookieOptOut != 0 {
+		cookie.optOut = true
+		cookie.uids = make(map[string]UIDEntry)
+		cookie.userKey = ""
+		cookie.syncAttempts = nil
+	}
+	cookie.deleteInvalidUIDs()
+
+	return cookie, nil
+}
+
+// compactDays returns the number of days since the Unix epoch, rounded up so that UIDs don't expire early.
+func compactDays(t time.Time) int64 {
+	seconds := t.Unix()
+	days := seconds / secondsPerDay
+	if seconds%secondsPerDay > 0 {
+		days++
+	}
+	return days
+}
+
+// sortedKeys returns the keys of the map in order, so that the same cookie is always written the same way.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func appendCompactKey(b []byte, key string) []byte {
+	if index, ok := compactCookieKeyIndexes[key]; ok {
+		return binary.AppendUvarint(b, index+1)
+	}
+	b = binary.AppendUvarint(b, 0)
+	return appendCompactString(b, key)
+}
+
+func appendCompactString(b []byte, s string) []byte {
+	b = binary.AppendUvarint(b, uint64(len(s)))
+	return append(b, s...)
+}
+
+// compactCookieReader reads the compact cookie data, and keeps the first error it runs into.
+type compactCookieReader struct {
+	data []byte
+	err  error
+}
+
+func (r *compactCookieReader) byte() byte {
+	if r.err != nil {
+		return 0
+	}
+	if len(r.data) == 0 {
+		r.err = errCompactCookieMalformed
+		return 0
+	}
+	b := r.data[0]
+	r.data = r.data[1:]
+	return b
+}
+
+func (r *compactCookieReader) uvarint() uint64 {
+	if r.err != nil {
+		return 0
+	}
+	v, n := binary.Uvarint(r.data)
+	if n <= 0 {
+		r.err = errCompactCookieMalformed
+		return 0
+	}
+	r.data = r.data[n:]
+	return v
+}
+
+func (r *compactCookieReader) varint() int64 {
+	if r.err != nil {
+		return 0
+	}
+	v, n := binary.Varint(r.data)
+	if n <= 0 {
+		r.err = errCompactCookieMalformed
+		return 0
+	}
+	r.data = r.data[n:]
+	return v
+}
+
+// key returns the syncer key, or an empty string if its index is missing from the dictionary, which happens
+// when the cookie was written by a newer version.
+func (r *compactCookieReader) key() string {
+	index := r.uvarint()
+	if index == 0 {
+		return r.string()
+	}
+	if index <= uint64(len(compactCookieKeys)) {
+		return compactCookieKeys[index-1]
+	}
+	return ""
+}
+
+func (r *compactCookieReader) string() string {
+	length := r.uvarint()
+	if r.err != nil {
+		return ""
+	}
+	if length > uint64(len(r.data)) {
+		r.err = errCompactCookieMalformed
+		return ""
+	}
+	s := string(r.data[:length])
+	r.data = r.data[length:]
+	return s
+}
@@ -0,0 +1,305 @@
+package usersync
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prebid/prebid-server/v4/config"
+	"github.com/prebid/prebid-server/v4/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompactCookie(t *testing.T) {
+	now := time.Date(2026, 10, 17, 15, 30, 0, 0, time.UTC)
+	expires := time.Date(2026, 10, 31, 0, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name           string
+		givenCookie    *Cookie
+		expectedCookie *Cookie
+	}{
+		{
+			name:           "empty",
+			givenCookie:    NewCookie(),
+			expectedCookie: NewCookie(),
+		},
+		{
+			name:           "nil",
+			givenCookie:    nil,
+			expectedCookie: NewCookie(),
+		},
+		{
+			name: "dictionary-keys",
+			givenCookie: &Cookie{uids: map[string]UIDEntry{
+				"adnxs":    {UID: "123", Expires: expires},
+				"pubmatic": {UID: "456", Expires: expires.Add(-48 * time.Hour)},
+			}},
+			expectedCookie: &Cookie{uids: map[string]UIDEntry{
+				"adnxs":    {UID: "123", Expires: expires},
+				"pubmatic": {UID: "456", Expires: expires.Add(-48 * time.Hour)},
+			}},
+		},
+		{
+			name: "key-missing-from-dictionary",
+			givenCookie: &Cookie{uids: map[string]UIDEntry{
+				"newbidder": {UID: "123", Expires: expires},
+			}},
+			expectedCookie: &Cookie{uids: map[string]UIDEntry{
+				"newbidder": {UID: "123", Expires: expires},
+			}},
+		},
+		{
+			name: "expiry-rounded-up-to-the-day",
+			givenCookie: &Cookie{uids: map[string]UIDEntry{
+				"adnxs": {UID: "123", Expires: expires.Add(time.Hour)},
+			}},
+			expectedCookie: &Cookie{uids: map[string]UIDEntry{
+				"adnxs": {UID: "123", Expires: expires.Add(24 * time.Hour)},
+			}},
+		},
+		{
+			name: "expired",
+			givenCookie: &Cookie{uids: map[string]UIDEntry{
+				"adnxs": {UID: "123", Expires: time.Time{}},
+			}},
+			expectedCookie: &Cookie{uids: map[string]UIDEntry{
+				"adnxs": {UID: "123", Expires: time.Time{}},
+			}},
+		},
+		{
+			name: "user-key",
+			givenCookie: &Cookie{
+				uids:    map[string]UIDEntry{},
+				userKey: "some-key",
+			},
+			expectedCookie: &Cookie{
+				uids:    map[string]UIDEntry{},
+				userKey: "some-key",
+			},
+		},
+		{
+			name: "sync-attempts",
+			givenCookie: &Cookie{
+				uids:         map[string]UIDEntry{},
+				syncAttempts: map[string]SyncAttempt{"adnxs": {Last: now.Add(-time.Hour), Count: 2}, "newbidder": {Last: now.Add(-48 * time.Hour), Count: 1}},
+			},
+			expectedCookie: &Cookie{
+				uids:         map[string]UIDEntry{},
+				syncAttempts: map[string]SyncAttempt{"adnxs": {Last: now.Add(-time.Hour), Count: 2}, "newbidder": {Last: now.Add(-48 * time.Hour), Count: 1}},
+			},
+		},
+		{
+			name: "opt-out",
+			givenCookie: &Cookie{
+				uids:   map[string]UIDEntry{"adnxs": {UID: "123", Expires: expires}},
+				optOut: true,
+			},
+			expectedCookie: &Cookie{
+				uids:   map[string]UIDEntry{},
+				optOut: true,
+			},
+		},
+		{
+			name: "audience-network",
+			givenCookie: &Cookie{uids: map[string]UIDEntry{
+				"adnxs":           {UID: "123", Expires: expires},
+				"audienceNetwork": {UID: "0", Expires: expires},
+			}},
+			expectedCookie: &Cookie{uids: map[string]UIDEntry{
+				"adnxs": {UID: "123", Expires: expires},
+			}},
+		},
+		{
+			name: "deflated",
+			givenCookie: &Cookie{uids: map[string]UIDEntry{
+				"adnxs":    {UID: "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa", Expires: expires},
+				"pubmatic": {UID: "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa", Expires: expires},
+			}},
+			expectedCookie: &Cookie{uids: map[string]UIDEntry{
+				"adnxs":    {UID: "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa", Expires: expires},
+				"pubmatic": {UID: "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa", Expires: expires},
+			}},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			data := marshalCompactCookie(test.givenCookie, now)
+
+			cookie, err := unmarshalCookie(data)
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.expectedCookie, cookie)
+		})
+	}
+}
+
+func TestCompactCookieDeflate(t *testing.T) {
+	now := time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC)
+
+	short := marshalCompactCookie(&Cookie{uids: map[string]UIDEntry{"adnxs": {UID: "1", Expires: now}}}, now)
+	assert.Equal(t, compactCookieVersion, short[0], "data which doesn't compress must not be deflated")
+
+	repeated := marshalCompactCookie(&Cookie{uids: map[string]UIDEntry{
+		"adnxs":    {UID: "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa", Expires: now},
+		"pubmatic": {UID: "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa", Expires: now},
+	}}, now)
+	assert.Equal(t, compactCookieVersion|compactCookieDeflated, repeated[0])
+}
+
+func TestUnmarshalCompactCookieErrors(t *testing.T) {
+	testCases := []struct {
+		name           string
+		givenData      []byte
+		expectedCookie *Cookie
+		expectedError  error
+	}{
+		{
+			name:          "truncated",
+			givenData:     []byte{compactCookieVersion, 0, 2, 1, 1, 3},
+			expectedError: errCompactCookieMalformed,
+		},
+		{
+			name:          "trailing-data",
+			givenData:     []byte{compactCookieVersion, 0, 2, 0, 0},
+			expectedError: errCompactCookieMalformed,
+		},
+		{
+			name:          "invalid-deflate",
+			givenData:     []byte{compactCookieVersion | compactCookieDeflated, 0xff, 0xff},
+			expectedError: errCompactCookieMalformed,
+		},
+		{
+			// index 1000 is written by a version with a bigger dictionary
+			name:           "index-missing-from-dictionary",
+			givenData:      []byte{compactCookieVersion, 0, 2, 1, 0xe9, 0x07, 1, 'a', 2},
+			expectedCookie: NewCookie(),
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			cookie, err := unmarshalCookie(test.givenData)
+
+			assert.Equal(t, test.expectedError, err)
+			assert.Equal(t, test.expectedCookie, cookie)
+		})
+	}
+}
+
+func TestCompactDays(t *testing.T) {
+	day := time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, compactDays(day), compactDays(day.Add(-time.Second)))
+	assert.Equal(t, compactDays(day)+1, compactDays(day.Add(time.Second)))
+	assert.Equal(t, time.Time{}, time.Unix(compactDays(time.Time{})*secondsPerDay, 0).UTC())
+}
+
+func TestCompactCookieKeysAreUnique(t *testing.T) {
+	assert.Len(t, compactCookieKeyIndexes, len(compactCookieKeys))
+}
+
+// TestCompactCookieKeysAreAppendOnly pins the entries of the dictionary written by the released versions. New
+// keys may be appended without changing the pin, but the cookies written with a reordered or removed entry can't
+// be read by the other instances.
+func TestCompactCookieKeysAreAppendOnly(t *testing.T) {
+	const pinnedCount = 243
+	const pinnedSHA256 = "31e93c3db619046f019484f247760dc1c7fa3d0d71a9be6c7c58a4f4bbecc137"
+
+	require.GreaterOrEqual(t, len(compactCookieKeys), pinnedCount, "Entries of the compact cookie keys were removed")
+	pinnedKeys := strings.Join(compactCookieKeys[:pinnedCount], "\n")
+	assert.Equal(t, pinnedSHA256, fmt.Sprintf("%x", sha256.Sum256([]byte(pinnedKeys))), "Entries of the compact cookie keys were reordered, removed or changed")
+}
+
+func TestBase64EncoderDecoderFormats(t *testing.T) {
+	expires := time.Now().Add(uidTTL).Truncate(24 * time.Hour).UTC()
+	cookie := &Cookie{uids: map[string]UIDEntry{"adnxs": {UID: "123", Expires: expires}}}
+
+	for _, compact := range []bool{false, true} {
+		t.Run(fmt.Sprintf("compact-%t", compact), func(t *testing.T) {
+			encoded, err := Base64Encoder{Compact: compact}.Encode(cookie)
+			require.NoError(t, err)
+
+			mockMetrics := &metrics.MetricsEngineMock{}
+			mockMetrics.On("RecordUIDCookieDecode", metrics.UIDCookieDecodeLegacy).Once()
+			aeadDecoder := NewDecoder(testKeyRingHostCookie("", config.CookieKey{ID: "1", Secret: testKeySecret1}), mockMetrics)
+
+			assert.Equal(t, cookie, Base64Decoder{}.Decode(encoded))
+			assert.Equal(t, cookie, aeadDecoder.Decode(encoded))
+			mockMetrics.AssertExpectations(t)
+		})
+	}
+}
+
+// benchmarkCookie returns a cookie with the given number of syncs, whose UIDs are random UUIDs.
+func benchmarkCookie(syncs int) *Cookie {
+	random := rand.New(rand.NewSource(int64(syncs)))
+	expires := time.Now().Add(uidTTL)
+	cookie := NewCookie()
+	for i := 0; i < syncs; i++ {
+		key := compactCookieKeys[i%len(compactCookieKeys)]
+		if i >= len(compactCookieKeys) {
+			key = fmt.Sprintf("%s%d", key, i/len(compactCookieKeys))
+		}
+		cookie.uids[key] = UIDEntry{
+			UID:     fmt.Sprintf("%08x-%04x-%04x-%04x-%012x", random.Uint32(), random.Int31n(1<<16), random.Int31n(1<<16), random.Int31n(1<<16), random.Int63n(1<<48)),
+			Expires: expires,
+		}
+	}
+	return cookie
+}
+
+// syncsThatFit returns how many syncs fit in a cookie of maxCookieSizeBytes.
+func syncsThatFit(encoder Encoder, maxCookieSizeBytes int) int {
+	for syncs := 1; ; syncs++ {
+		cookie := benchmarkCookie(syncs)
+		encoded, _ := encoder.Encode(cookie)
+		expires := time.Now().Add(uidTTL)
+		httpCookie := &http.Cookie{Name: uidCookieName, Value: encoded, Expires: expires, Path: "/"}
+		if len(httpCookie.String()) > maxCookieSizeBytes {
+			return syncs - 1
+		}
+	}
+}
+
+func TestCompactCookieFitsMoreSyncs(t *testing.T) {
+	jsonSyncs := syncsThatFit(Base64Encoder{}, 4096)
+	compactSyncs := syncsThatFit(Base64Encoder{Compact: true}, 4096)
+
+	assert.Greater(t, compactSyncs, jsonSyncs)
+}
+
+func BenchmarkCookieEncoders(b *testing.B) {
+	keyRing := testKeyRingHostCookie("1", config.CookieKey{ID: "1", Secret: testKeySecret1})
+	compactKeyRing := testKeyRingHostCookie("1", config.CookieKey{ID: "1", Secret: testKeySecret1})
+	compactKeyRing.Format = config.HostCookieFormatCompact
+
+	encoders := []struct {
+		name    string
+		encoder Encoder
+	}{
+		{name: "json", encoder: Base64Encoder{}},
+		{name: "compact", encoder: Base64Encoder{Compact: true}},
+		{name: "json-aead", encoder: NewEncoder(keyRing)},
+		{name: "compact-aead", encoder: NewEncoder(compactKeyRing)},
+	}
+
+	for _, e := range encoders {
+		b.Run(e.name, func(b *testing.B) {
+			syncs := syncsThatFit(e.encoder, 4096)
+			cookie := benchmarkCookie(syncs)
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				e.encoder.Encode(cookie)
+			}
+			b.ReportMetric(float64(syncs), "syncs/4KB")
+		})
+	}
+}
@@ -0,0 +1,261 @@
+package usersync
+
+// compactCookieKeys is the dictionary of the syncer keys written as an index in the compact cookie format.
+// Keys missing from the dictionary are written as is.
+//
+// Cookies written by other instances have to be read with the same dictionary, so entries must never be
+// reordered or removed. New keys are appended at the end.
+var compactCookieKeys = [...]string{
+	"33across",
+	"360playvid",
+	"aax",
+	"acuityads",
+	"adagio",
+	"adastra",
+	"adf",
+	"adform",
+	"adipolo",
+	"adkernel",
+	"adkernelAdn",
+	"adklip",
+	"adman",
+	"admatic",
+	"admixer",
+	"adnimation",
+	"adnxs",
+	"adot",
+	"adpone",
+	"adport",
+	"adprime",
+	"adquery",
+	"ads_interactive",
+	"adsinteractive",
+	"adtarget",
+	"adtelligent",
+	"adtonos",
+	"aduptech",
+	"advangelists",
+	"adverxo",
+	"adxcg",
+	"adyoulike",
+	"aidem",
+	"aja",
+	"alchemyx",
+	"alkimi",
+	"alliance_gravity",
+	"amx",
+	"anzuDSP",
+	"apacdex",
+	"apester",
+	"appStockSSP",
+	"aso",
+	"audienceNetwork",
+	"avocet",
+	"axis",
+	"axonix",
+	"bcmint",
+	"beachfront",
+	"Beintoo",
+	"bematterfull",
+	"between",
+	"beyondmedia",
+	"bidfuse",
+	"bidgency",
+	"bidmatic",
+	"bidmyadz",
+	"bidsmind",
+	"bidtheatre",
+	"bliink",
+	"blis",
+	"blue",
+	"bmtm",
+	"boldwin",
+	"bwx",
+	"cadent_aperture_mx",
+	"ccx",
+	"colossus",
+	"compass",
+	"connatix",
+	"connectad",
+	"connektai",
+	"consumable",
+	"contxtful",
+	"conversant",
+	"copper6",
+	"copper6ssp",
+	"cortex",
+	"cpmstar",
+	"criteo",
+	"cwire",
+	"datablocks",
+	"deepintent",
+	"dianomi",
+	"dmx",
+	"dpai",
+	"driftpixel",
+	"e_volution",
+	"emtv",
+	"emx_digital",
+	"eplanning",
+	"epsilon",
+	"eskimi",
+	"evtech",
+	"exco",
+	"feedad",
+	"freewheel-ssp",
+	"freewheelssp",
+	"frvradn",
+	"fwssp",
+	"gamma",
+	"gamoshi",
+	"globalsun",
+	"grid",
+	"gumgum",
+	"harrenmedia",
+	"imds",
+	"impactify",
+	"improvedigital",
+	"indicue",
+	"inmobi",
+	"insticator",
+	"invibes",
+	"iqx",
+	"iqzone",
+	"ix",
+	"janet",
+	"jdpmedia",
+	"jixie",
+	"kargo",
+	"kiviads",
+	"krushmedia",
+	"kuantyx",
+	"kueezrtb",
+	"lemmadigital",
+	"lm_kiviads",
+	"lockerdome",
+	"logan",
+	"logicad",
+	"lunamedia",
+	"m152",
+	"markapp",
+	"marsmedia",
+	"matterfull",
+	"mediago",
+	"medianet",
+	"mediasquare",
+	"metax",
+	"mgid",
+	"mgidX",
+	"mgtechnology",
+	"minutemedia",
+	"missena",
+	"mobilefuse",
+	"mycodemedia",
+	"nativo",
+	"nextmillennium",
+	"nexx360",
+	"nobid",
+	"ntvagents",
+	"ogury",
+	"omnidex",
+	"onetag",
+	"openweb",
+	"openx",
+	"operaads",
+	"optidigital",
+	"oraki",
+	"orbidder",
+	"outbrain",
+	"ownadx",
+	"pgam",
+	"pgamssp",
+	"pixfuture",
+	"playdigo",
+	"programmaticX",
+	"progx",
+	"proxistore",
+	"pubmatic",
+	"pubrise",
+	"pulsepoint",
+	"pwbid",
+	"qt",
+	"quantumdex",
+	"rediads",
+	"reklamup",
+	"richaudience",
+	"rise",
+	"robustApps",
+	"rocketlab",
+	"rtbhouse",
+	"rubicon",
+	"sa_lunamedia",
+	"scalibur",
+	"screencore",
+	"seedingAlliance",
+	"seedtag",
+	"selectmedia",
+	"sharethrough",
+	"smaato",
+	"smartadserver",
+	"smarthub",
+	"smartrtb",
+	"smartyads",
+	"smilewanted",
+	"smoot",
+	"sonobi",
+	"sovrn",
+	"sparteo",
+	"sspBC",
+	"stackadapt",
+	"streamkey",
+	"stroeerCore",
+	"suntContent",
+	"synapseHX",
+	"taboola",
+	"tagoras",
+	"tappx",
+	"targetVideo",
+	"teal",
+	"telaria",
+	"theadx",
+	"thetradedesk",
+	"tpmn",
+	"tredio",
+	"triplelift",
+	"triplelift_native",
+	"trustedstack",
+	"trustx",
+	"ucfunnel",
+	"undertone",
+	"unruly",
+	"valueimpression",
+	"vidazoo",
+	"videobyte",
+	"vidoomy",
+	"viewdeos",
+	"visiblemeasures",
+	"visx",
+	"vox",
+	"vrtcal",
+	"waardex_ak",
+	"xapads",
+	"xeworks",
+	"yahooAds",
+	"yahooAdvertising",
+	"yahoossp",
+	"yandex",
+	"yieldlab",
+	"yieldmo",
+	"yieldone",
+	"zeroclickfraud",
+	"zeta_global_ssp",
+}
+
+// compactCookieKeyIndexes maps the syncer keys of the dictionary to their index.
+var compactCookieKeyIndexes = func() map[string]uint64 {
+	indexes := make(map[string]uint64, len(compactCookieKeys))
+	for i, key := range compactCookieKeys {
+		indexes[key] = uint64(i)
+	}
+	return indexes
+}()
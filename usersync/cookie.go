@@ -23,6 +23,12 @@ const uidTTL = 14 * 24 * time.Hour
 type Cookie struct {
 	uids   map[string]UIDEntry
 	optOut bool
+	// userKey identifies the user in the UIDStore, when the UIDs are kept on the server.
+	userKey string
+	// syncAttempts records the syncs offered to the user by syncer key, when a cooldown is configured.
+	syncAttempts map[string]SyncAttempt
+	// storedUIDs are the UIDs loaded from the UIDStore, so that only the UIDs changed since are saved.
+	storedUIDs map[string]UIDEntry
 }
 
 // UIDEntry bundles the UID with an Expiration date.
@@ -33,6 +39,14 @@ type UIDEntry struct {
 	Expires time.Time `json:"expires"`
 }
 
+// SyncAttempt records when a sync was last offered to the user for a syncer key.
+type SyncAttempt struct {
+	// Last is the time of the latest attempt.
+	Last time.Time `json:"last"`
+	// Count is the number of attempts on the day of the latest attempt.
+	Count int `json:"count"`
+}
+
 // NewCookie returns a new empty cookie.
 func NewCookie() *Cookie {
 	return &Cookie{
@@ -56,9 +70,10 @@ func ReadCookie(r *http.Request, decoder Decoder, host *config.HostCookie) *Cook
 	return decodedCookie
 }
 
-// PrepareCookieForWrite ejects UIDs as long as the cookie is too full
+// PrepareCookieForWrite ejects UIDs as long as the cookie is too full. The sync attempts are dropped
+// before any UID is ejected.
 func (cookie *Cookie) PrepareCookieForWrite(cfg *config.HostCookie, encoder Encoder, ejector Ejector) (string, error) {
-	for len(cookie.uids) > 0 {
+	for len(cookie.uids) > 0 || len(cookie.syncAttempts) > 0 {
 		encodedCookie, err := encoder.Encode(cookie)
 		if err != nil {
 			return encodedCookie, err
@@ -76,6 +91,9 @@ func (cookie *Cookie) PrepareCookieForWrite(cfg *config.HostCookie, encoder Enco
 		isCookieTooBig := cookieSize > cfg.MaxCookieSizeBytes && cfg.MaxCookieSizeBytes > 0
 		if !isCookieTooBig {
 			return encodedCookie, nil
+		} else if len(cookie.syncAttempts) > 0 {
+			cookie.syncAttempts = nil
+			continue
 		} else if len(cookie.uids) == 1 {
 			return "", errors.New("uid that's trying to be synced is bigger than MaxCookieSize")
 		}
@@ -127,10 +145,56 @@ func (cookie *Cookie) Sync(key string, uid string) error {
 		UID:     uid,
 		Expires: time.Now().Add(uidTTL),
 	}
+	delete(cookie.syncAttempts, key)
 
 	return nil
 }
 
+// RecordSyncAttempt records that a sync was offered to the user for the syncer key. Attempts older than
+// the UID TTL are forgotten.
+func (cookie *Cookie) RecordSyncAttempt(key string, now time.Time) {
+	if cookie.syncAttempts == nil {
+		cookie.syncAttempts = make(map[string]SyncAttempt)
+	}
+
+	for k, attempt := range cookie.syncAttempts {
+		if now.Sub(attempt.Last) > uidTTL {
+			delete(cookie.syncAttempts, k)
+		}
+	}
+
+	attempt := cookie.syncAttempts[key]
+	if sameDay(attempt.Last, now) {
+		attempt.Count++
+	} else {
+		attempt.Count = 1
+	}
+	attempt.Last = now.UTC().Truncate(time.Second)
+	cookie.syncAttempts[key] = attempt
+}
+
+// SyncAttemptAllowed returns false if a sync was offered to the user for the syncer key less than cooldown
+// ago, or if maxDailySyncs syncs were already offered on the same day. Either limit is disabled if 0.
+func (cookie *Cookie) SyncAttemptAllowed(key string, cooldown time.Duration, maxDailySyncs int, now time.Time) bool {
+	attempt, ok := cookie.syncAttempts[key]
+	if !ok {
+		return true
+	}
+
+	if cooldown > 0 && now.Sub(attempt.Last) < cooldown {
+		return false
+	}
+	if maxDailySyncs > 0 && sameDay(attempt.Last, now) && attempt.Count >= maxDailySyncs {
+		return false
+	}
+	return true
+}
+
+func sameDay(a, b time.Time) bool {
+	a, b = a.UTC(), b.UTC()
+	return a.YearDay() == b.YearDay() && a.Year() == b.Year()
+}
+
 // SyncHostCookie syncs the request cookie with the host cookie
 func SyncHostCookie(r *http.Request, requestCookie *Cookie, host *config.HostCookie) {
 	if uid, _, _ := requestCookie.GetUID(host.Family); uid == "" && host.CookieName != "" {
@@ -163,6 +227,7 @@ func (cookie *Cookie) SetOptOut(optOut bool) {
 
 	if optOut {
 		cookie.uids = make(map[string]UIDEntry)
+		cookie.syncAttempts = nil
 	}
 }
 
@@ -221,14 +286,18 @@ func checkAudienceNetwork(key string, uid string) bool {
 // This exists so that Cookie (which is public) can have private fields, and the rest of
 // the code doesn't have to worry about the cookie data storage format.
 type cookieJson struct {
-	UIDs   map[string]UIDEntry `json:"tempUIDs,omitempty"`
-	OptOut bool                `json:"optout,omitempty"`
+	UIDs         map[string]UIDEntry    `json:"tempUIDs,omitempty"`
+	OptOut       bool                   `json:"optout,omitempty"`
+	UserKey      string                 `json:"key,omitempty"`
+	SyncAttempts map[string]SyncAttempt `json:"attempts,omitempty"`
 }
 
 func (cookie *Cookie) MarshalJSON() ([]byte, error) { // nosemgrep: marshal-json-pointer-receiver
 	return jsonutil.Marshal(cookieJson{
-		UIDs:   cookie.uids,
-		OptOut: cookie.optOut,
+		UIDs:         cookie.uids,
+		OptOut:       cookie.optOut,
+		UserKey:      cookie.userKey,
+		SyncAttempts: cookie.syncAttempts,
 	})
 }
 
@@ -242,18 +311,25 @@ func (cookie *Cookie) UnmarshalJSON(b []byte) error {
 
 	if cookie.optOut {
 		cookie.uids = nil
+		cookie.userKey = ""
 	} else {
 		cookie.uids = cookieContract.UIDs
+		cookie.userKey = cookieContract.UserKey
+		cookie.syncAttempts = cookieContract.SyncAttempts
 	}
 
 	if cookie.uids == nil {
 		cookie.uids = make(map[string]UIDEntry)
 	}
 
+	cookie.deleteInvalidUIDs()
+
+	return nil
+}
+
+func (cookie *Cookie) deleteInvalidUIDs() {
 	// Audience Network Handling
 	if id, ok := cookie.uids[string(openrtb_ext.BidderAudienceNetwork)]; ok && id.UID == "0" {
 		delete(cookie.uids, string(openrtb_ext.BidderAudienceNetwork))
 	}
-
-	return nil
 }
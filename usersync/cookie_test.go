@@ -550,6 +550,133 @@ func TestPrepareCookieForWriteEncoderError(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestPrepareCookieForWriteDropsSyncAttempts(t *testing.T) {
+	cookie := &Cookie{
+		uids: map[string]UIDEntry{
+			"adnxs": newTempId("UID", 1),
+		},
+		syncAttempts: map[string]SyncAttempt{
+			"rubicon":  {Last: time.Now(), Count: 1},
+			"pubmatic": {Last: time.Now(), Count: 1},
+		},
+	}
+	encodedWithoutAttempts, err := Base64Encoder{}.Encode(&Cookie{uids: cookie.uids})
+	assert.NoError(t, err)
+
+	encoded, err := cookie.PrepareCookieForWrite(&config.HostCookie{MaxCookieSizeBytes: len(encodedWithoutAttempts) + 100}, Base64Encoder{}, &OldestEjector{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, encodedWithoutAttempts, encoded)
+	assert.Contains(t, cookie.uids, "adnxs", "the sync attempts must be dropped before the UIDs")
+	assert.Empty(t, cookie.syncAttempts)
+}
+
+func TestRecordSyncAttempt(t *testing.T) {
+	now := time.Date(2026, 10, 17, 15, 30, 0, 500, time.UTC)
+
+	testCases := []struct {
+		name             string
+		givenAttempts    map[string]SyncAttempt
+		expectedAttempts map[string]SyncAttempt
+	}{
+		{
+			name:             "first",
+			givenAttempts:    nil,
+			expectedAttempts: map[string]SyncAttempt{"adnxs": {Last: now.Truncate(time.Second), Count: 1}},
+		},
+		{
+			name:             "same-day",
+			givenAttempts:    map[string]SyncAttempt{"adnxs": {Last: now.Add(-time.Hour), Count: 2}},
+			expectedAttempts: map[string]SyncAttempt{"adnxs": {Last: now.Truncate(time.Second), Count: 3}},
+		},
+		{
+			name:             "previous-day",
+			givenAttempts:    map[string]SyncAttempt{"adnxs": {Last: now.Add(-24 * time.Hour), Count: 2}},
+			expectedAttempts: map[string]SyncAttempt{"adnxs": {Last: now.Truncate(time.Second), Count: 1}},
+		},
+		{
+			name: "old-attempts-forgotten",
+			givenAttempts: map[string]SyncAttempt{
+				"rubicon":  {Last: now.Add(-uidTTL - time.Hour), Count: 1},
+				"pubmatic": {Last: now.Add(-time.Hour), Count: 1},
+			},
+			expectedAttempts: map[string]SyncAttempt{
+				"adnxs":    {Last: now.Truncate(time.Second), Count: 1},
+				"pubmatic": {Last: now.Add(-time.Hour), Count: 1},
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			cookie := &Cookie{uids: map[string]UIDEntry{}, syncAttempts: test.givenAttempts}
+
+			cookie.RecordSyncAttempt("adnxs", now)
+
+			assert.Equal(t, test.expectedAttempts, cookie.syncAttempts)
+		})
+	}
+}
+
+func TestSyncAttemptAllowed(t *testing.T) {
+	now := time.Date(2026, 10, 17, 15, 30, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name               string
+		givenAttempt       *SyncAttempt
+		givenCooldown      time.Duration
+		givenMaxDailySyncs int
+		expected           bool
+	}{
+		{
+			name:               "never-attempted",
+			givenCooldown:      time.Hour,
+			givenMaxDailySyncs: 1,
+			expected:           true,
+		},
+		{
+			name:          "in-cooldown",
+			givenAttempt:  &SyncAttempt{Last: now.Add(-time.Minute), Count: 1},
+			givenCooldown: time.Hour,
+			expected:      false,
+		},
+		{
+			name:          "cooldown-over",
+			givenAttempt:  &SyncAttempt{Last: now.Add(-2 * time.Hour), Count: 1},
+			givenCooldown: time.Hour,
+			expected:      true,
+		},
+		{
+			name:               "daily-cap-reached",
+			givenAttempt:       &SyncAttempt{Last: now.Add(-2 * time.Hour), Count: 3},
+			givenMaxDailySyncs: 3,
+			expected:           false,
+		},
+		{
+			name:               "daily-cap-reached-on-previous-day",
+			givenAttempt:       &SyncAttempt{Last: now.Add(-24 * time.Hour), Count: 3},
+			givenMaxDailySyncs: 3,
+			expected:           true,
+		},
+		{
+			name:         "no-limits",
+			givenAttempt: &SyncAttempt{Last: now, Count: 100},
+			expected:     true,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			cookie := NewCookie()
+			if test.givenAttempt != nil {
+				cookie.syncAttempts = map[string]SyncAttempt{"adnxs": *test.givenAttempt}
+			}
+
+			assert.Equal(t, test.expected, cookie.SyncAttemptAllowed("adnxs", test.givenCooldown, test.givenMaxDailySyncs, now))
+		})
+	}
+}
+
 func TestSyncHostCookie(t *testing.T) {
 	testCases := []struct {
 		name            string
@@ -2,8 +2,7 @@ package usersync
 
 import (
 	"encoding/base64"
-
-	"github.com/prebid/prebid-server/v4/util/jsonutil"
+	"strings"
 )
 
 type Decoder interface {
@@ -13,15 +12,20 @@ type Decoder interface {
 type Base64Decoder struct{}
 
 func (d Base64Decoder) Decode(encodedValue string) *Cookie {
-	jsonValue, err := base64.URLEncoding.DecodeString(encodedValue)
+	cookie, err := decodeBase64Cookie(encodedValue)
 	if err != nil {
 		return NewCookie()
 	}
 
-	var cookie Cookie
-	if err = jsonutil.UnmarshalValid(jsonValue, &cookie); err != nil {
-		return NewCookie()
+	return cookie
+}
+
+func decodeBase64Cookie(encodedValue string) (*Cookie, error) {
+	// cookies written in the compact format aren't padded
+	data, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(encodedValue, "="))
+	if err != nil {
+		return nil, err
 	}
 
-	return &cookie
+	return unmarshalCookie(data)
 }
@@ -0,0 +1,49 @@
+package usersync
+
+import (
+	"sort"
+
+	"github.com/prebid/openrtb/v20/adcom1"
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v4/config"
+)
+
+// EIDSources returns the EID source of each syncer key, for the bidders which configure one. The syncer
+// key defaults to the bidder name, as it does for the syncers.
+func EIDSources(bidderInfos config.BidderInfos) map[string]string {
+	sources := make(map[string]string)
+	for bidder, info := range bidderInfos {
+		if info.Syncer == nil || info.Syncer.EIDSource == "" {
+			continue
+		}
+		key := info.Syncer.Key
+		if key == "" {
+			key = bidder
+		}
+		sources[key] = info.Syncer.EIDSource
+	}
+	return sources
+}
+
+// BuildEIDs returns the UIDs of the syncer keys which have an EID source as OpenRTB EIDs. The UIDs of syncer
+// keys sharing the same source are grouped in the same EID, and the EIDs are sorted by source.
+func BuildEIDs(uids map[string]string, sources map[string]string) []openrtb2.EID {
+	uidsBySource := make(map[string][]openrtb2.UID)
+	for _, key := range sortedKeys(uids) {
+		source, ok := sources[key]
+		if !ok {
+			continue
+		}
+		uidsBySource[source] = append(uidsBySource[source], openrtb2.UID{
+			ID:    uids[key],
+			AType: adcom1.AgentTypeWeb,
+		})
+	}
+
+	eids := make([]openrtb2.EID, 0, len(uidsBySource))
+	for source, uids := range uidsBySource {
+		eids = append(eids, openrtb2.EID{Source: source, UIDs: uids})
+	}
+	sort.Slice(eids, func(i, j int) bool { return eids[i].Source < eids[j].Source })
+	return eids
+}
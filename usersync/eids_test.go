@@ -0,0 +1,64 @@
+package usersync
+
+import (
+	"testing"
+
+	"github.com/prebid/openrtb/v20/adcom1"
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v4/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEIDSources(t *testing.T) {
+	bidderInfos := config.BidderInfos{
+		"appnexus":   {Syncer: &config.Syncer{Key: "adnxs", EIDSource: "adnxs.com"}},
+		"pubmatic":   {Syncer: &config.Syncer{EIDSource: "pubmatic.com"}},
+		"rubicon":    {Syncer: &config.Syncer{Key: "rubicon"}},
+		"nosyncer":   {},
+		"appnexus-2": {Syncer: &config.Syncer{Key: "adnxs"}},
+	}
+
+	assert.Equal(t, map[string]string{"adnxs": "adnxs.com", "pubmatic": "pubmatic.com"}, EIDSources(bidderInfos))
+}
+
+func TestBuildEIDs(t *testing.T) {
+	sources := map[string]string{"adnxs": "adnxs.com", "adnxs2": "adnxs.com", "pubmatic": "pubmatic.com"}
+
+	testCases := []struct {
+		name         string
+		givenUIDs    map[string]string
+		expectedEIDs []openrtb2.EID
+	}{
+		{
+			name:         "none",
+			givenUIDs:    map[string]string{},
+			expectedEIDs: []openrtb2.EID{},
+		},
+		{
+			name:      "one-per-source",
+			givenUIDs: map[string]string{"pubmatic": "2", "adnxs": "1"},
+			expectedEIDs: []openrtb2.EID{
+				{Source: "adnxs.com", UIDs: []openrtb2.UID{{ID: "1", AType: adcom1.AgentTypeWeb}}},
+				{Source: "pubmatic.com", UIDs: []openrtb2.UID{{ID: "2", AType: adcom1.AgentTypeWeb}}},
+			},
+		},
+		{
+			name:      "shared-source",
+			givenUIDs: map[string]string{"adnxs2": "2", "adnxs": "1"},
+			expectedEIDs: []openrtb2.EID{
+				{Source: "adnxs.com", UIDs: []openrtb2.UID{{ID: "1", AType: adcom1.AgentTypeWeb}, {ID: "2", AType: adcom1.AgentTypeWeb}}},
+			},
+		},
+		{
+			name:         "no-source",
+			givenUIDs:    map[string]string{"rubicon": "1"},
+			expectedEIDs: []openrtb2.EID{},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expectedEIDs, BuildEIDs(test.givenUIDs, sources))
+		})
+	}
+}
@@ -2,8 +2,6 @@ package usersync
 
 import (
 	"encoding/base64"
-
-	"github.com/prebid/prebid-server/v4/util/jsonutil"
 )
 
 type Encoder interface {
@@ -11,13 +9,20 @@ type Encoder interface {
 	Encode(c *Cookie) (string, error)
 }
 
-type Base64Encoder struct{}
+type Base64Encoder struct {
+	// Compact writes the cookie data in the compact format rather than as JSON.
+	Compact bool
+}
 
 func (e Base64Encoder) Encode(c *Cookie) (string, error) {
-	j, err := jsonutil.Marshal(c)
+	j, err := marshalCookie(c, e.Compact)
 	if err != nil {
 		return "", err
 	}
+
+	if e.Compact {
+		return base64.RawURLEncoding.EncodeToString(j), nil
+	}
 	b64 := base64.URLEncoding.EncodeToString(j)
 
 	return b64, nil
@@ -56,6 +56,52 @@ func TestEncoderDecoder(t *testing.T) {
 				optOut: false,
 			},
 		},
+		{
+			name: "user-key",
+			givenCookie: &Cookie{
+				uids:    map[string]UIDEntry{},
+				userKey: "user-1",
+			},
+			expectedCookie: &Cookie{
+				uids:    map[string]UIDEntry{},
+				userKey: "user-1",
+			},
+		},
+		{
+			name: "opted-out-user-key",
+			givenCookie: &Cookie{
+				uids:    map[string]UIDEntry{},
+				optOut:  true,
+				userKey: "user-1",
+			},
+			expectedCookie: &Cookie{
+				uids:   map[string]UIDEntry{},
+				optOut: true,
+			},
+		},
+		{
+			name: "sync-attempts",
+			givenCookie: &Cookie{
+				uids:         map[string]UIDEntry{},
+				syncAttempts: map[string]SyncAttempt{"adnxs": {Last: time.Date(2026, 10, 17, 15, 30, 0, 0, time.UTC), Count: 2}},
+			},
+			expectedCookie: &Cookie{
+				uids:         map[string]UIDEntry{},
+				syncAttempts: map[string]SyncAttempt{"adnxs": {Last: time.Date(2026, 10, 17, 15, 30, 0, 0, time.UTC), Count: 2}},
+			},
+		},
+		{
+			name: "opted-out-sync-attempts",
+			givenCookie: &Cookie{
+				uids:         map[string]UIDEntry{},
+				optOut:       true,
+				syncAttempts: map[string]SyncAttempt{"adnxs": {Last: time.Date(2026, 10, 17, 15, 30, 0, 0, time.UTC), Count: 2}},
+			},
+			expectedCookie: &Cookie{
+				uids:   map[string]UIDEntry{},
+				optOut: true,
+			},
+		},
 	}
 
 	for _, test := range testCases {
@@ -66,6 +112,8 @@ func TestEncoderDecoder(t *testing.T) {
 
 			assert.Equal(t, test.expectedCookie.uids, decodedCookie.uids)
 			assert.Equal(t, test.expectedCookie.optOut, decodedCookie.optOut)
+			assert.Equal(t, test.expectedCookie.userKey, decodedCookie.userKey)
+			assert.Equal(t, test.expectedCookie.syncAttempts, decodedCookie.syncAttempts)
 		})
 	}
 }
@@ -0,0 +1,106 @@
+package usersync
+
+import (
+	"context"
+	"sort"
+
+	"github.com/prebid/prebid-server/v4/util/uuidutil"
+)
+
+// UIDStore keeps the UIDs synced for users on the server. When one is configured, the uids cookie only
+// carries a key identifying the user in the store, so it's no longer limited by the size browsers
+// allow for cookies.
+type UIDStore interface {
+	// Get returns the UIDs of the user which haven't expired yet.
+	Get(ctx context.Context, userKey string) (map[string]UIDEntry, error)
+	// Update sets the given UIDs of the user and removes the UIDs of the removed syncer keys, leaving the
+	// other UIDs of the user untouched so that concurrent syncs of different bidders don't overwrite each
+	// other. Each UID is kept until it expires.
+	Update(ctx context.Context, userKey string, uids map[string]UIDEntry, removed []string) error
+	// Delete removes all the UIDs of the user.
+	Delete(ctx context.Context, userKey string) error
+}
+
+// LoadStoredUIDs adds the UIDs kept in the store for the user to the cookie. UIDs carried by the cookie
+// itself, which was written before the store was configured, are kept unless the store has a UID which
+// expires later. It does nothing if the store is nil.
+func (cookie *Cookie) LoadStoredUIDs(ctx context.Context, store UIDStore) error {
+	if store == nil || !cookie.AllowSyncs() || cookie.userKey == "" {
+		return nil
+	}
+
+	uids, err := store.Get(ctx, cookie.userKey)
+	if err != nil {
+		return err
+	}
+	cookie.storedUIDs = make(map[string]UIDEntry, len(uids))
+	for key, stored := range uids {
+		cookie.storedUIDs[key] = stored
+		if current, ok := cookie.uids[key]; !ok || stored.Expires.After(current.Expires) {
+			cookie.uids[key] = stored
+		}
+	}
+	return nil
+}
+
+// PrepareCookieForStore saves the UIDs of the cookie changed since they were loaded to the store, and returns
+// the encoded cookie, which only carries the user key and the sync attempts. A key is assigned to users who
+// don't have one yet. If the user opted out, their UIDs are deleted from the store instead, and the user key
+// is dropped from the cookie even if the deletion fails.
+func (cookie *Cookie) PrepareCookieForStore(ctx context.Context, store UIDStore, encoder Encoder, uuidGenerator uuidutil.UUIDGenerator) (string, error) {
+	if !cookie.AllowSyncs() {
+		if cookie.userKey != "" {
+			userKey := cookie.userKey
+			cookie.userKey = ""
+			if err := store.Delete(ctx, userKey); err != nil {
+				return "", err
+			}
+		}
+		return encoder.Encode(cookie)
+	}
+
+	if cookie.userKey == "" {
+		userKey, err := uuidGenerator.Generate()
+		if err != nil {
+			return "", err
+		}
+		cookie.userKey = userKey
+	}
+
+	updated, removed := cookie.storedUIDChanges()
+	if len(updated) > 0 || len(removed) > 0 {
+		if err := store.Update(ctx, cookie.userKey, updated, removed); err != nil {
+			return "", err
+		}
+		cookie.storedUIDs = make(map[string]UIDEntry, len(cookie.uids))
+		for key, entry := range cookie.uids {
+			cookie.storedUIDs[key] = entry
+		}
+	}
+
+	return encoder.Encode(&Cookie{
+		uids:         make(map[string]UIDEntry),
+		userKey:      cookie.userKey,
+		syncAttempts: cookie.syncAttempts,
+	})
+}
+
+// storedUIDChanges returns the UIDs of the cookie which were added or changed since they were loaded from the
+// store, along with the syncer keys whose UID was removed.
+func (cookie *Cookie) storedUIDChanges() (map[string]UIDEntry, []string) {
+	updated := make(map[string]UIDEntry)
+	for key, entry := range cookie.uids {
+		if stored, ok := cookie.storedUIDs[key]; !ok || stored.UID != entry.UID || !stored.Expires.Equal(entry.Expires) {
+			updated[key] = entry
+		}
+	}
+
+	var removed []string
+	for key := range cookie.storedUIDs {
+		if _, ok := cookie.uids[key]; !ok {
+			removed = append(removed, key)
+		}
+	}
+	sort.Strings(removed)
+	return updated, removed
+}
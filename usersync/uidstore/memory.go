@@ -0,0 +1,89 @@
+package uidstore
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/prebid/prebid-server/v4/usersync"
+)
+
+// purgeInterval is the minimum time between two removals of the users whose UIDs have all expired.
+const purgeInterval = time.Minute
+
+// memoryStore keeps the UIDs of users in memory. Users whose UIDs have all expired are removed from time
+// to time, when the store is written to. It's meant for tests and single instance setups.
+type memoryStore struct {
+	mutex     sync.Mutex
+	users     map[string]map[string]usersync.UIDEntry
+	lastPurge time.Time
+	now       func() time.Time
+}
+
+// NewMemoryStore returns an empty UID store which keeps the UIDs in memory.
+func NewMemoryStore() usersync.UIDStore {
+	return &memoryStore{
+		users: make(map[string]map[string]usersync.UIDEntry),
+		now:   time.Now,
+	}
+}
+
+func (s *memoryStore) Get(_ context.Context, userKey string) (map[string]usersync.UIDEntry, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return liveUIDs(s.users[userKey], s.now()), nil
+}
+
+func (s *memoryStore) Update(_ context.Context, userKey string, uids map[string]usersync.UIDEntry, removed []string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	now := s.now()
+	if now.Sub(s.lastPurge) >= purgeInterval {
+		for key, stored := range s.users {
+			if len(liveUIDs(stored, now)) == 0 {
+				delete(s.users, key)
+			}
+		}
+		s.lastPurge = now
+	}
+
+	updated := liveUIDs(s.users[userKey], now)
+	for _, key := range removed {
+		delete(updated, key)
+	}
+	for key, entry := range uids {
+		if now.Before(entry.Expires) {
+			updated[key] = entry
+		} else {
+			delete(updated, key)
+		}
+	}
+
+	if len(updated) > 0 {
+		s.users[userKey] = updated
+	} else {
+		delete(s.users, userKey)
+	}
+	return nil
+}
+
+func (s *memoryStore) Delete(_ context.Context, userKey string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	delete(s.users, userKey)
+	return nil
+}
+
+// liveUIDs returns a copy of the UIDs which haven't expired at the given time.
+func liveUIDs(uids map[string]usersync.UIDEntry, now time.Time) map[string]usersync.UIDEntry {
+	live := make(map[string]usersync.UIDEntry, len(uids))
+	for key, entry := range uids {
+		if now.Before(entry.Expires) {
+			live[key] = entry
+		}
+	}
+	return live
+}
@@ -0,0 +1,97 @@
+package uidstore
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prebid/prebid-server/v4/usersync"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryStore(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	store := &memoryStore{
+		users: make(map[string]map[string]usersync.UIDEntry),
+		now:   func() time.Time { return now },
+	}
+	ctx := context.Background()
+
+	uids, err := store.Get(ctx, "user-1")
+	require.NoError(t, err)
+	assert.Empty(t, uids)
+
+	err = store.Update(ctx, "user-1", map[string]usersync.UIDEntry{
+		"adnxs":   {UID: "123", Expires: now.Add(time.Hour)},
+		"rubicon": {UID: "456", Expires: now.Add(2 * time.Hour)},
+		"openx":   {UID: "789", Expires: now.Add(-time.Hour)},
+	}, nil)
+	require.NoError(t, err)
+
+	uids, err = store.Get(ctx, "user-1")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]usersync.UIDEntry{
+		"adnxs":   {UID: "123", Expires: now.Add(time.Hour)},
+		"rubicon": {UID: "456", Expires: now.Add(2 * time.Hour)},
+	}, uids)
+
+	now = now.Add(90 * time.Minute)
+	uids, err = store.Get(ctx, "user-1")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]usersync.UIDEntry{
+		"rubicon": {UID: "456", Expires: now.Add(30 * time.Minute)},
+	}, uids)
+
+	require.NoError(t, store.Delete(ctx, "user-1"))
+	uids, err = store.Get(ctx, "user-1")
+	require.NoError(t, err)
+	assert.Empty(t, uids)
+}
+
+func TestMemoryStoreUpdatesEachUID(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	store := &memoryStore{
+		users: make(map[string]map[string]usersync.UIDEntry),
+		now:   func() time.Time { return now },
+	}
+	ctx := context.Background()
+
+	require.NoError(t, store.Update(ctx, "user-1", map[string]usersync.UIDEntry{
+		"adnxs": {UID: "123", Expires: now.Add(time.Hour)},
+		"openx": {UID: "456", Expires: now.Add(time.Hour)},
+	}, nil))
+	require.NoError(t, store.Update(ctx, "user-1", map[string]usersync.UIDEntry{"rubicon": {UID: "789", Expires: now.Add(time.Hour)}}, []string{"openx"}))
+	require.NoError(t, store.Update(ctx, "user-1", map[string]usersync.UIDEntry{"adnxs": {UID: "123", Expires: now.Add(-time.Hour)}}, nil))
+
+	uids, err := store.Get(ctx, "user-1")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]usersync.UIDEntry{
+		"rubicon": {UID: "789", Expires: now.Add(time.Hour)},
+	}, uids)
+}
+
+func TestMemoryStorePurgesExpiredUsers(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	store := &memoryStore{
+		users: make(map[string]map[string]usersync.UIDEntry),
+		now:   func() time.Time { return now },
+	}
+	ctx := context.Background()
+
+	require.NoError(t, store.Update(ctx, "user-1", map[string]usersync.UIDEntry{"adnxs": {UID: "123", Expires: now.Add(time.Minute)}}, nil))
+	require.NoError(t, store.Update(ctx, "user-2", map[string]usersync.UIDEntry{"adnxs": {UID: "456", Expires: now.Add(time.Hour)}}, nil))
+	assert.Len(t, store.users, 2)
+
+	now = now.Add(purgeInterval + time.Second)
+	require.NoError(t, store.Update(ctx, "user-3", map[string]usersync.UIDEntry{}, nil))
+	assert.Equal(t, []string{"user-2"}, keys(store.users))
+}
+
+func keys(users map[string]map[string]usersync.UIDEntry) []string {
+	result := make([]string, 0, len(users))
+	for key := range users {
+		result = append(result, key)
+	}
+	return result
+}
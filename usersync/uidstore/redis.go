@@ -0,0 +1,115 @@
+package uidstore
+
+import (
+	"context"
+	"time"
+
+	"github.com/prebid/prebid-server/v4/config"
+	"github.com/prebid/prebid-server/v4/usersync"
+	"github.com/prebid/prebid-server/v4/util/jsonutil"
+	"github.com/redis/go-redis/v9"
+)
+
+// redisStore keeps the UIDs of each user in a Redis hash with a field per syncer key, so that the UIDs of
+// different bidders can be updated concurrently. The hash expires along with the UID expiring last.
+type redisStore struct {
+	client    *redis.Client
+	keyPrefix string
+	timeout   time.Duration
+	now       func() time.Time
+}
+
+func newRedisStore(cfg config.UIDStoreRedis) *redisStore {
+	timeout := cfg.Timeout()
+	client := redis.NewClient(&redis.Options{
+		Addr:                  cfg.Address,
+		Username:              cfg.Username,
+		Password:              cfg.Password,
+		DB:                    cfg.DB,
+		DialTimeout:           timeout,
+		ReadTimeout:           timeout,
+		WriteTimeout:          timeout,
+		ContextTimeoutEnabled: true,
+	})
+
+	return &redisStore{
+		client:    client,
+		keyPrefix: cfg.KeyPrefix,
+		timeout:   timeout,
+		now:       time.Now,
+	}
+}
+
+func (s *redisStore) Get(ctx context.Context, userKey string) (map[string]usersync.UIDEntry, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	fields, err := s.client.HGetAll(ctx, s.keyPrefix+userKey).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	uids := make(map[string]usersync.UIDEntry, len(fields))
+	for key, value := range fields {
+		var entry usersync.UIDEntry
+		if err := jsonutil.UnmarshalValid([]byte(value), &entry); err != nil {
+			return nil, err
+		}
+		uids[key] = entry
+	}
+	return liveUIDs(uids, s.now()), nil
+}
+
+func (s *redisStore) Update(ctx context.Context, userKey string, uids map[string]usersync.UIDEntry, removed []string) error {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	now := s.now()
+	deleted := append([]string(nil), removed...)
+	fields := make([]interface{}, 0, 2*len(uids))
+	var expires time.Time
+	for key, entry := range uids {
+		if !now.Before(entry.Expires) {
+			deleted = append(deleted, key)
+			continue
+		}
+		value, err := jsonutil.Marshal(entry)
+		if err != nil {
+			return err
+		}
+		fields = append(fields, key, value)
+		if entry.Expires.After(expires) {
+			expires = entry.Expires
+		}
+	}
+	if len(deleted) == 0 && len(fields) == 0 {
+		return nil
+	}
+
+	redisKey := s.keyPrefix + userKey
+	_, err := s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		// Redis deletes the hash along with its last field
+		if len(deleted) > 0 {
+			pipe.HDel(ctx, redisKey, deleted...)
+		}
+		if len(fields) > 0 {
+			pipe.HSet(ctx, redisKey, fields...)
+			// the expiration is only extended, since other UIDs of the user may expire later
+			pipe.ExpireNX(ctx, redisKey, expires.Sub(now))
+			pipe.ExpireGT(ctx, redisKey, expires.Sub(now))
+		}
+		return nil
+	})
+	return err
+}
+
+func (s *redisStore) Delete(ctx context.Context, userKey string) error {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	return s.client.Del(ctx, s.keyPrefix+userKey).Err()
+}
+
+func (s *redisStore) close() {
+	s.client.Close()
+}
@@ -0,0 +1,121 @@
+package uidstore
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/prebid/prebid-server/v4/config"
+	"github.com/prebid/prebid-server/v4/usersync"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedisStore(t *testing.T) {
+	server := miniredis.RunT(t)
+	store := newRedisStore(config.UIDStoreRedis{Address: server.Addr(), KeyPrefix: "uids:", TimeoutMS: 1000})
+	defer store.close()
+
+	now := time.Now().Truncate(time.Second)
+	store.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	uids, err := store.Get(ctx, "user-1")
+	require.NoError(t, err)
+	assert.Empty(t, uids)
+
+	err = store.Update(ctx, "user-1", map[string]usersync.UIDEntry{
+		"adnxs":   {UID: "123", Expires: now.Add(time.Hour)},
+		"rubicon": {UID: "456", Expires: now.Add(2 * time.Hour)},
+		"openx":   {UID: "789", Expires: now.Add(-time.Hour)},
+	}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 2*time.Hour, server.TTL("uids:user-1"), "the key must expire along with the last uid")
+
+	uids, err = store.Get(ctx, "user-1")
+	require.NoError(t, err)
+	assertUIDs(t, map[string]usersync.UIDEntry{
+		"adnxs":   {UID: "123", Expires: now.Add(time.Hour)},
+		"rubicon": {UID: "456", Expires: now.Add(2 * time.Hour)},
+	}, uids)
+
+	now = now.Add(90 * time.Minute)
+	uids, err = store.Get(ctx, "user-1")
+	require.NoError(t, err)
+	assertUIDs(t, map[string]usersync.UIDEntry{
+		"rubicon": {UID: "456", Expires: now.Add(30 * time.Minute)},
+	}, uids)
+
+	require.NoError(t, store.Delete(ctx, "user-1"))
+	assert.False(t, server.Exists("uids:user-1"))
+}
+
+func TestRedisStoreUpdateExpiredUIDs(t *testing.T) {
+	server := miniredis.RunT(t)
+	store := newRedisStore(config.UIDStoreRedis{Address: server.Addr(), TimeoutMS: 1000})
+	defer store.close()
+	ctx := context.Background()
+
+	require.NoError(t, store.Update(ctx, "user-1", map[string]usersync.UIDEntry{"adnxs": {UID: "123", Expires: time.Now().Add(time.Hour)}}, nil))
+	require.NoError(t, store.Update(ctx, "user-1", map[string]usersync.UIDEntry{"adnxs": {UID: "123", Expires: time.Now().Add(-time.Hour)}}, nil))
+
+	assert.False(t, server.Exists("user-1"))
+}
+
+func TestRedisStoreUpdatesEachUID(t *testing.T) {
+	server := miniredis.RunT(t)
+	store := newRedisStore(config.UIDStoreRedis{Address: server.Addr(), TimeoutMS: 1000})
+	defer store.close()
+
+	now := time.Now().Truncate(time.Second)
+	store.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	require.NoError(t, store.Update(ctx, "user-1", map[string]usersync.UIDEntry{
+		"adnxs": {UID: "123", Expires: now.Add(2 * time.Hour)},
+		"openx": {UID: "456", Expires: now.Add(time.Hour)},
+	}, nil))
+
+	// two syncs of different bidders based on the same read of the UIDs
+	require.NoError(t, store.Update(ctx, "user-1", map[string]usersync.UIDEntry{"rubicon": {UID: "789", Expires: now.Add(time.Hour)}}, nil))
+	require.NoError(t, store.Update(ctx, "user-1", map[string]usersync.UIDEntry{"pubmatic": {UID: "abc", Expires: now.Add(time.Hour)}}, []string{"openx"}))
+
+	uids, err := store.Get(ctx, "user-1")
+	require.NoError(t, err)
+	assertUIDs(t, map[string]usersync.UIDEntry{
+		"adnxs":    {UID: "123", Expires: now.Add(2 * time.Hour)},
+		"rubicon":  {UID: "789", Expires: now.Add(time.Hour)},
+		"pubmatic": {UID: "abc", Expires: now.Add(time.Hour)},
+	}, uids)
+	assert.Equal(t, 2*time.Hour, server.TTL("user-1"), "the expiration must not be shortened by UIDs expiring earlier")
+
+	require.NoError(t, store.Update(ctx, "user-1", nil, []string{"adnxs", "rubicon", "pubmatic"}))
+	assert.False(t, server.Exists("user-1"), "the key must be deleted along with the last uid")
+}
+
+func TestRedisStoreErrors(t *testing.T) {
+	server := miniredis.RunT(t)
+	store := newRedisStore(config.UIDStoreRedis{Address: server.Addr(), TimeoutMS: 1000})
+	defer store.close()
+	ctx := context.Background()
+
+	server.HSet("user-1", "adnxs", "not json")
+	_, err := store.Get(ctx, "user-1")
+	assert.Error(t, err, "malformed value")
+
+	server.Close()
+	_, err = store.Get(ctx, "user-1")
+	assert.Error(t, err, "unreachable server")
+	assert.Error(t, store.Update(ctx, "user-1", map[string]usersync.UIDEntry{"adnxs": {UID: "123", Expires: time.Now().Add(time.Hour)}}, nil))
+}
+
+// assertUIDs compares the UIDs ignoring the location of their expiration time, which is lost in JSON.
+func assertUIDs(t *testing.T, expected, actual map[string]usersync.UIDEntry) {
+	t.Helper()
+	require.Len(t, actual, len(expected))
+	for key, entry := range expected {
+		assert.Equal(t, entry.UID, actual[key].UID, key)
+		assert.True(t, entry.Expires.Equal(actual[key].Expires), key)
+	}
+}
@@ -0,0 +1,25 @@
+// Package uidstore provides the implementations of usersync.UIDStore.
+package uidstore
+
+import (
+	"fmt"
+
+	"github.com/prebid/prebid-server/v4/config"
+	"github.com/prebid/prebid-server/v4/usersync"
+)
+
+// NewUIDStore builds the UID store described by the configuration, along with a function releasing its
+// resources. The store is nil if the UIDs are kept in the uids cookie.
+func NewUIDStore(cfg config.UIDStore) (usersync.UIDStore, func(), error) {
+	switch cfg.Type {
+	case "", config.UIDStoreTypeCookie:
+		return nil, func() {}, nil
+	case config.UIDStoreTypeMemory:
+		return NewMemoryStore(), func() {}, nil
+	case config.UIDStoreTypeRedis:
+		store := newRedisStore(cfg.Redis)
+		return store, store.close, nil
+	default:
+		return nil, nil, fmt.Errorf("unknown uid store type %s", cfg.Type)
+	}
+}
@@ -0,0 +1,62 @@
+package uidstore
+
+import (
+	"testing"
+
+	"github.com/prebid/prebid-server/v4/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewUIDStore(t *testing.T) {
+	testCases := []struct {
+		description   string
+		cfg           config.UIDStore
+		expectedStore any
+		expectedErr   string
+	}{
+		{
+			description:   "not-set",
+			cfg:           config.UIDStore{},
+			expectedStore: nil,
+		},
+		{
+			description:   "cookie",
+			cfg:           config.UIDStore{Type: config.UIDStoreTypeCookie},
+			expectedStore: nil,
+		},
+		{
+			description:   "memory",
+			cfg:           config.UIDStore{Type: config.UIDStoreTypeMemory},
+			expectedStore: &memoryStore{},
+		},
+		{
+			description:   "redis",
+			cfg:           config.UIDStore{Type: config.UIDStoreTypeRedis, Redis: config.UIDStoreRedis{Address: "localhost:6379", TimeoutMS: 50}},
+			expectedStore: &redisStore{},
+		},
+		{
+			description: "unknown",
+			cfg:         config.UIDStore{Type: "file"},
+			expectedErr: "unknown uid store type file",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			store, shutdown, err := NewUIDStore(test.cfg)
+
+			if test.expectedErr != "" {
+				assert.EqualError(t, err, test.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			if test.expectedStore == nil {
+				assert.Nil(t, store)
+			} else {
+				assert.IsType(t, test.expectedStore, store)
+			}
+			assert.NotPanics(t, shutdown)
+		})
+	}
+}
@@ -0,0 +1,207 @@
+package usersync
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadStoredUIDs(t *testing.T) {
+	now := time.Now()
+
+	testCases := []struct {
+		name         string
+		givenCookie  *Cookie
+		givenStore   UIDStore
+		expectedUIDs map[string]UIDEntry
+		expectedErr  error
+	}{
+		{
+			name:         "no-store",
+			givenCookie:  &Cookie{uids: map[string]UIDEntry{"adnxs": {UID: "cookie"}}, userKey: "user-1"},
+			givenStore:   nil,
+			expectedUIDs: map[string]UIDEntry{"adnxs": {UID: "cookie"}},
+		},
+		{
+			name:         "no-user-key",
+			givenCookie:  &Cookie{uids: map[string]UIDEntry{"adnxs": {UID: "cookie"}}},
+			givenStore:   &fakeUIDStore{users: map[string]map[string]UIDEntry{"": {"rubicon": {UID: "stored"}}}},
+			expectedUIDs: map[string]UIDEntry{"adnxs": {UID: "cookie"}},
+		},
+		{
+			name:        "merged",
+			givenCookie: &Cookie{uids: map[string]UIDEntry{"adnxs": {UID: "cookie", Expires: now}, "openx": {UID: "cookie", Expires: now}}, userKey: "user-1"},
+			givenStore: &fakeUIDStore{users: map[string]map[string]UIDEntry{"user-1": {
+				"adnxs":   {UID: "stored", Expires: now.Add(time.Hour)},
+				"openx":   {UID: "stored", Expires: now.Add(-time.Hour)},
+				"rubicon": {UID: "stored", Expires: now},
+			}}},
+			expectedUIDs: map[string]UIDEntry{
+				"adnxs":   {UID: "stored", Expires: now.Add(time.Hour)},
+				"openx":   {UID: "cookie", Expires: now},
+				"rubicon": {UID: "stored", Expires: now},
+			},
+		},
+		{
+			name:         "store-error",
+			givenCookie:  &Cookie{uids: map[string]UIDEntry{"adnxs": {UID: "cookie"}}, userKey: "user-1"},
+			givenStore:   &fakeUIDStore{err: errors.New("store error")},
+			expectedUIDs: map[string]UIDEntry{"adnxs": {UID: "cookie"}},
+			expectedErr:  errors.New("store error"),
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.givenCookie.LoadStoredUIDs(context.Background(), test.givenStore)
+
+			assert.Equal(t, test.expectedErr, err)
+			assert.Equal(t, test.expectedUIDs, test.givenCookie.uids)
+		})
+	}
+}
+
+func TestPrepareCookieForStore(t *testing.T) {
+	uids := map[string]UIDEntry{"adnxs": {UID: "123", Expires: time.Now().Add(time.Hour)}}
+
+	testCases := []struct {
+		name           string
+		givenCookie    *Cookie
+		givenStore     *fakeUIDStore
+		expectedCookie *Cookie
+		expectedStored map[string]map[string]UIDEntry
+		expectedErr    error
+	}{
+		{
+			name:           "new-user",
+			givenCookie:    &Cookie{uids: uids},
+			givenStore:     &fakeUIDStore{users: map[string]map[string]UIDEntry{}},
+			expectedCookie: &Cookie{uids: map[string]UIDEntry{}, userKey: "generated"},
+			expectedStored: map[string]map[string]UIDEntry{"generated": uids},
+		},
+		{
+			name:           "known-user",
+			givenCookie:    &Cookie{uids: uids, userKey: "user-1"},
+			givenStore:     &fakeUIDStore{users: map[string]map[string]UIDEntry{"user-1": {}}},
+			expectedCookie: &Cookie{uids: map[string]UIDEntry{}, userKey: "user-1"},
+			expectedStored: map[string]map[string]UIDEntry{"user-1": uids},
+		},
+		{
+			name:           "opted-out-user",
+			givenCookie:    &Cookie{uids: map[string]UIDEntry{}, optOut: true, userKey: "user-1"},
+			givenStore:     &fakeUIDStore{users: map[string]map[string]UIDEntry{"user-1": uids}},
+			expectedCookie: &Cookie{uids: map[string]UIDEntry{}, optOut: true},
+			expectedStored: map[string]map[string]UIDEntry{},
+		},
+		{
+			name:        "store-error",
+			givenCookie: &Cookie{uids: uids, userKey: "user-1"},
+			givenStore:  &fakeUIDStore{err: errors.New("store error")},
+			expectedErr: errors.New("store error"),
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			encodedCookie, err := test.givenCookie.PrepareCookieForStore(context.Background(), test.givenStore, Base64Encoder{}, fakeUUIDGenerator{id: "generated"})
+
+			if test.expectedErr != nil {
+				assert.Equal(t, test.expectedErr, err)
+				return
+			}
+			require.NoError(t, err)
+			decodedCookie := Base64Decoder{}.Decode(encodedCookie)
+			assert.Equal(t, test.expectedCookie, decodedCookie)
+			assert.Equal(t, test.expectedStored, test.givenStore.users)
+		})
+	}
+}
+
+func TestPrepareCookieForStoreSavesChanges(t *testing.T) {
+	expires := time.Now().Add(time.Hour)
+	store := &fakeUIDStore{users: map[string]map[string]UIDEntry{"user-1": {
+		"adnxs":   {UID: "123", Expires: expires},
+		"openx":   {UID: "456", Expires: expires},
+		"rubicon": {UID: "789", Expires: expires},
+	}}}
+	cookie := &Cookie{uids: map[string]UIDEntry{}, userKey: "user-1"}
+	require.NoError(t, cookie.LoadStoredUIDs(context.Background(), store))
+
+	// the UIDs of another bidder are synced concurrently
+	store.users["user-1"]["pubmatic"] = UIDEntry{UID: "concurrent", Expires: expires}
+
+	_, err := cookie.PrepareCookieForStore(context.Background(), store, Base64Encoder{}, fakeUUIDGenerator{})
+	require.NoError(t, err)
+	assert.Zero(t, store.updates, "the store must not be updated if the UIDs didn't change")
+
+	cookie.Unsync("openx")
+	require.NoError(t, cookie.Sync("rubicon", "updated"))
+	_, err = cookie.PrepareCookieForStore(context.Background(), store, Base64Encoder{}, fakeUUIDGenerator{})
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, store.updates)
+	assert.Len(t, store.users["user-1"], 3)
+	assert.Equal(t, "123", store.users["user-1"]["adnxs"].UID)
+	assert.Equal(t, "updated", store.users["user-1"]["rubicon"].UID)
+	assert.Equal(t, "concurrent", store.users["user-1"]["pubmatic"].UID, "the UIDs synced concurrently must be kept")
+
+	_, err = cookie.PrepareCookieForStore(context.Background(), store, Base64Encoder{}, fakeUUIDGenerator{})
+	require.NoError(t, err)
+	assert.Equal(t, 1, store.updates, "the saved changes must not be saved again")
+}
+
+func TestPrepareCookieForStoreOptOutDeleteError(t *testing.T) {
+	cookie := &Cookie{uids: map[string]UIDEntry{}, optOut: true, userKey: "user-1"}
+
+	_, err := cookie.PrepareCookieForStore(context.Background(), &fakeUIDStore{err: errors.New("store error")}, Base64Encoder{}, fakeUUIDGenerator{})
+
+	assert.Equal(t, errors.New("store error"), err)
+	assert.Empty(t, cookie.userKey, "the user key must be dropped even if the deletion failed")
+}
+
+type fakeUIDStore struct {
+	users   map[string]map[string]UIDEntry
+	err     error
+	updates int
+}
+
+func (s *fakeUIDStore) Get(_ context.Context, userKey string) (map[string]UIDEntry, error) {
+	return s.users[userKey], s.err
+}
+
+func (s *fakeUIDStore) Update(_ context.Context, userKey string, uids map[string]UIDEntry, removed []string) error {
+	s.updates++
+	if s.err != nil {
+		return s.err
+	}
+	if s.users[userKey] == nil {
+		s.users[userKey] = make(map[string]UIDEntry)
+	}
+	for _, key := range removed {
+		delete(s.users[userKey], key)
+	}
+	for key, entry := range uids {
+		s.users[userKey][key] = entry
+	}
+	return nil
+}
+
+func (s *fakeUIDStore) Delete(_ context.Context, userKey string) error {
+	if s.err != nil {
+		return s.err
+	}
+	delete(s.users, userKey)
+	return nil
+}
+
+type fakeUUIDGenerator struct {
+	id string
+}
+
+func (g fakeUUIDGenerator) Generate() (string, error) {
+	return g.id, nil
+}
@@ -0,0 +1,131 @@
+package httputil
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/zstd"
+)
+
+// ErrUnsupportedContentEncoding is returned for a content encoding which can't be compressed or decompressed.
+var ErrUnsupportedContentEncoding = errors.New("unsupported compression type")
+
+// Encoder compresses the data written to it. Close must be called to write the remaining compressed
+// data. Encoders are pooled, so they must be returned with ReleaseEncoder once closed.
+type Encoder interface {
+	io.WriteCloser
+	Flush() error
+	Reset(w io.Writer)
+}
+
+var encoderPools = map[ContentEncoding]*sync.Pool{
+	ContentEncodingGZIP: {New: func() any {
+		return gzip.NewWriter(nil)
+	}},
+	ContentEncodingZSTD: {New: func() any {
+		// a single goroutine per encoder, since the payloads are small and concurrency comes from the requests
+		encoder, _ := zstd.NewWriter(nil, zstd.WithEncoderConcurrency(1), zstd.WithEncoderLevel(zstd.SpeedDefault))
+		return encoder
+	}},
+	ContentEncodingBrotli: {New: func() any {
+		return brotli.NewWriter(nil)
+	}},
+}
+
+// AcquireEncoder returns an encoder of the content encoding writing to w.
+func AcquireEncoder(contentEncoding ContentEncoding, w io.Writer) (Encoder, error) {
+	pool, ok := encoderPools[contentEncoding.Normalize()]
+	if !ok {
+		return nil, fmt.Errorf("%w '%s'", ErrUnsupportedContentEncoding, contentEncoding)
+	}
+	encoder := pool.Get().(Encoder)
+	encoder.Reset(w)
+	return encoder, nil
+}
+
+// ReleaseEncoder returns a closed encoder to its pool.
+func ReleaseEncoder(contentEncoding ContentEncoding, encoder Encoder) {
+	if pool, ok := encoderPools[contentEncoding.Normalize()]; ok {
+		encoder.Reset(nil)
+		pool.Put(encoder)
+	}
+}
+
+// NewDecodingReader returns a reader decompressing the body according to its content encoding.
+func NewDecodingReader(body io.Reader, contentEncoding ContentEncoding) (io.ReadCloser, error) {
+	switch contentEncoding.Normalize() {
+	case ContentEncodingIdentity:
+		return io.NopCloser(body), nil
+	case ContentEncodingGZIP:
+		return gzip.NewReader(body)
+	case ContentEncodingZSTD:
+		decoder, err := zstd.NewReader(body, zstd.WithDecoderConcurrency(1))
+		if err != nil {
+			return nil, err
+		}
+		return decoder.IOReadCloser(), nil
+	case ContentEncodingBrotli:
+		return io.NopCloser(brotli.NewReader(body)), nil
+	case ContentEncodingDeflate:
+		return zlib.NewReader(body)
+	default:
+		return nil, fmt.Errorf("%w '%s'", ErrUnsupportedContentEncoding, contentEncoding)
+	}
+}
+
+// NegotiateContentEncoding returns the content encoding to use for a response based on the
+// Accept-Encoding header of the request. Encodings with the same quality are chosen in the order of
+// the supported encodings. An empty content encoding is returned if none of the supported
+// encodings is acceptable.
+func NegotiateContentEncoding(acceptEncoding string, supported []ContentEncoding) ContentEncoding {
+	if acceptEncoding == "" || len(supported) == 0 {
+		return ""
+	}
+
+	qualities := make(map[ContentEncoding]float64)
+	wildcardQuality := -1.0
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		name, params, _ := strings.Cut(part, ";")
+		encoding := ContentEncoding(strings.TrimSpace(name)).Normalize()
+		if encoding == "" {
+			continue
+		}
+
+		quality := 1.0
+		for _, param := range strings.Split(params, ";") {
+			key, value, found := strings.Cut(strings.TrimSpace(param), "=")
+			if found && strings.EqualFold(strings.TrimSpace(key), "q") {
+				if q, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil {
+					quality = q
+				}
+			}
+		}
+
+		if encoding == "*" {
+			wildcardQuality = quality
+		} else {
+			qualities[encoding] = quality
+		}
+	}
+
+	var best ContentEncoding
+	bestQuality := 0.0
+	for _, encoding := range supported {
+		quality, ok := qualities[encoding]
+		if !ok {
+			quality = wildcardQuality
+		}
+		if quality > bestQuality {
+			best = encoding
+			bestQuality = quality
+		}
+	}
+	return best
+}
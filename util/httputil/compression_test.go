@@ -0,0 +1,152 @@
+package httputil
+
+import (
+	"bytes"
+	"compress/zlib"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncodeDecodeRoundTrip(t *testing.T) {
+	payload := bytes.Repeat([]byte(`{"id":"some-request-id","imp":[{"id":"1"}]}`), 50)
+
+	for _, contentEncoding := range []ContentEncoding{ContentEncodingGZIP, ContentEncodingZSTD, ContentEncodingBrotli, "ZSTD"} {
+		t.Run(string(contentEncoding), func(t *testing.T) {
+			var compressed bytes.Buffer
+			encoder, err := AcquireEncoder(contentEncoding, &compressed)
+			require.NoError(t, err)
+			_, err = encoder.Write(payload)
+			require.NoError(t, err)
+			require.NoError(t, encoder.Close())
+			ReleaseEncoder(contentEncoding, encoder)
+
+			assert.Less(t, compressed.Len(), len(payload))
+
+			decoder, err := NewDecodingReader(&compressed, contentEncoding)
+			require.NoError(t, err)
+			decompressed, err := io.ReadAll(decoder)
+			require.NoError(t, err)
+			assert.NoError(t, decoder.Close())
+			assert.Equal(t, payload, decompressed)
+		})
+	}
+}
+
+func TestUnsupportedContentEncoding(t *testing.T) {
+	_, err := AcquireEncoder("deflate", io.Discard)
+	assert.EqualError(t, err, "unsupported compression type 'deflate'")
+
+	_, err = NewDecodingReader(bytes.NewReader(nil), "compress")
+	assert.EqualError(t, err, "unsupported compression type 'compress'")
+}
+
+func TestDecodeDeflateAndIdentity(t *testing.T) {
+	payload := []byte(`{"id":"some-request-id"}`)
+
+	var compressed bytes.Buffer
+	writer := zlib.NewWriter(&compressed)
+	_, err := writer.Write(payload)
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+
+	testCases := []struct {
+		contentEncoding ContentEncoding
+		body            []byte
+	}{
+		{contentEncoding: "deflate", body: compressed.Bytes()},
+		{contentEncoding: "Deflate", body: compressed.Bytes()},
+		{contentEncoding: "identity", body: payload},
+		{contentEncoding: "IDENTITY", body: payload},
+	}
+	for _, test := range testCases {
+		t.Run(string(test.contentEncoding), func(t *testing.T) {
+			decoder, err := NewDecodingReader(bytes.NewReader(test.body), test.contentEncoding)
+			require.NoError(t, err)
+			decompressed, err := io.ReadAll(decoder)
+			require.NoError(t, err)
+			assert.NoError(t, decoder.Close())
+			assert.Equal(t, payload, decompressed)
+		})
+	}
+}
+
+func TestNegotiateContentEncoding(t *testing.T) {
+	allEncodings := []ContentEncoding{ContentEncodingZSTD, ContentEncodingBrotli, ContentEncodingGZIP}
+
+	testCases := []struct {
+		description    string
+		acceptEncoding string
+		supported      []ContentEncoding
+		expected       ContentEncoding
+	}{
+		{
+			description:    "no-accept-encoding",
+			acceptEncoding: "",
+			supported:      allEncodings,
+			expected:       "",
+		},
+		{
+			description:    "none-supported",
+			acceptEncoding: "gzip, br",
+			supported:      nil,
+			expected:       "",
+		},
+		{
+			description:    "server-preference-on-equal-quality",
+			acceptEncoding: "gzip, deflate, br, zstd",
+			supported:      allEncodings,
+			expected:       ContentEncodingZSTD,
+		},
+		{
+			description:    "only-gzip-accepted",
+			acceptEncoding: "gzip",
+			supported:      allEncodings,
+			expected:       ContentEncodingGZIP,
+		},
+		{
+			description:    "highest-quality",
+			acceptEncoding: "zstd;q=0.5, br;q=0.8, gzip;q=0.1",
+			supported:      allEncodings,
+			expected:       ContentEncodingBrotli,
+		},
+		{
+			description:    "case-and-whitespace-insensitive",
+			acceptEncoding: " GZIP ; Q=1 ",
+			supported:      allEncodings,
+			expected:       ContentEncodingGZIP,
+		},
+		{
+			description:    "excluded-with-zero-quality",
+			acceptEncoding: "zstd;q=0, gzip",
+			supported:      []ContentEncoding{ContentEncodingZSTD},
+			expected:       "",
+		},
+		{
+			description:    "wildcard",
+			acceptEncoding: "*",
+			supported:      allEncodings,
+			expected:       ContentEncodingZSTD,
+		},
+		{
+			description:    "wildcard-does-not-override-explicit",
+			acceptEncoding: "zstd;q=0, *;q=0.5",
+			supported:      allEncodings,
+			expected:       ContentEncodingBrotli,
+		},
+		{
+			description:    "not-supported-by-server",
+			acceptEncoding: "br",
+			supported:      []ContentEncoding{ContentEncodingGZIP},
+			expected:       "",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			assert.Equal(t, test.expected, NegotiateContentEncoding(test.acceptEncoding, test.supported))
+		})
+	}
+}
@@ -17,7 +17,13 @@ var (
 type ContentEncoding string
 
 const (
-	ContentEncodingGZIP ContentEncoding = "gzip"
+	ContentEncodingGZIP   ContentEncoding = "gzip"
+	ContentEncodingZSTD   ContentEncoding = "zstd"
+	ContentEncodingBrotli ContentEncoding = "br"
+	// ContentEncodingDeflate is the zlib format, which can only be decoded
+	ContentEncodingDeflate ContentEncoding = "deflate"
+	// ContentEncodingIdentity means no compression
+	ContentEncodingIdentity ContentEncoding = "identity"
 )
 
 func (k ContentEncoding) Normalize() ContentEncoding {